@@ -0,0 +1,87 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package backup
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/spf13/cobra"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/config"
+
+	"github.com/elastic/cloud-on-k8s/v2/pkg/about"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/backup"
+	controllerscheme "github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/scheme"
+)
+
+const (
+	namespacesFlag = "namespaces"
+	outputDirFlag  = "output-dir"
+
+	// bundleTimeFormat is the format of the time in the name of the backup bundle files.
+	bundleTimeFormat = "20060102T150405Z"
+)
+
+// Command returns the command exporting the resources managed by the operator to a backup bundle, from which they can
+// be recreated in another Kubernetes cluster.
+func Command() *cobra.Command {
+	var namespaces []string
+	var outputDir string
+
+	cmd := &cobra.Command{
+		Use:   "backup",
+		Short: "Export the resources managed by the operator to a disaster-recovery backup bundle",
+		Long: `Export the resources managed by the operator, along with their certificate authorities, to a JSON backup bundle.
+The bundle holds the private keys of the certificate authorities and must be stored securely.`,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			cfg, err := config.GetConfig()
+			if err != nil {
+				return fmt.Errorf("failed to get a Kubernetes config: %w", err)
+			}
+			controllerscheme.SetupScheme()
+			c, err := client.New(cfg, client.Options{Scheme: scheme.Scheme})
+			if err != nil {
+				return fmt.Errorf("failed to create a Kubernetes client: %w", err)
+			}
+
+			now := time.Now().UTC()
+			bundle, err := backup.Export(cmd.Context(), c, about.GetBuildInfo().VersionString(), namespaces, now)
+			if err != nil {
+				return err
+			}
+			serialized, err := json.MarshalIndent(bundle, "", "  ")
+			if err != nil {
+				return fmt.Errorf("failed to serialize the backup bundle: %w", err)
+			}
+			path := filepath.Join(outputDir, fmt.Sprintf("eck-backup-%s.json", now.Format(bundleTimeFormat)))
+			// the bundle holds private keys: restrict its access to the owner
+			if err := os.WriteFile(path, serialized, 0600); err != nil {
+				return fmt.Errorf("failed to write the backup bundle: %w", err)
+			}
+			fmt.Fprintf(cmd.OutOrStdout(), "Exported %d resources and %d certificate authorities to %s\n",
+				len(bundle.Resources), len(bundle.CertificateAuthorities), path)
+			return nil
+		},
+	}
+
+	cmd.Flags().StringSliceVar(
+		&namespaces,
+		namespacesFlag,
+		nil,
+		"Comma-separated list of namespaces from which to export the managed resources. Defaults to all namespaces.",
+	)
+	cmd.Flags().StringVar(
+		&outputDir,
+		outputDirFlag,
+		".",
+		"Directory in which the backup bundle is written",
+	)
+	return cmd
+}
@@ -8,6 +8,7 @@ import (
 	"github.com/spf13/cobra"
 	_ "k8s.io/client-go/plugin/pkg/client/auth/gcp"
 
+	"github.com/elastic/cloud-on-k8s/v2/cmd/backup"
 	"github.com/elastic/cloud-on-k8s/v2/cmd/manager"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/about"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/dev"
@@ -23,6 +24,7 @@ func main() {
 		SilenceUsage: true,
 	}
 	rootCmd.AddCommand(manager.Command())
+	rootCmd.AddCommand(backup.Command())
 
 	// development mode is only available as a command line flag to avoid accidentally enabling it
 	rootCmd.PersistentFlags().BoolVar(&dev.Enabled, "development", false, "turns on development mode")
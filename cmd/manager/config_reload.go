@@ -0,0 +1,91 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package manager
+
+import (
+	"reflect"
+
+	"github.com/spf13/viper"
+
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/container"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/operator"
+	logconf "github.com/elastic/cloud-on-k8s/v2/pkg/utils/log"
+)
+
+// reloadableSettings are the operator settings that can be changed at runtime by updating the configuration file,
+// along with the function applying their new value. Changing any other setting restarts the operator.
+var reloadableSettings = map[string]func(v *viper.Viper){
+	logconf.FlagName: func(v *viper.Viper) {
+		logconf.SetVerbosity(v.GetInt(logconf.FlagName))
+	},
+	operator.ContainerRegistryFlag: func(v *viper.Viper) {
+		container.SetContainerRegistry(v.GetString(operator.ContainerRegistryFlag))
+	},
+}
+
+// configReloader applies the changes made to the operator configuration file at runtime.
+type configReloader struct {
+	path string
+	// current holds the settings read from the configuration file when the operator started or last reloaded them.
+	current *viper.Viper
+	// overridden returns true if the given setting is also set with a flag or an environment variable, which take
+	// precedence over the configuration file.
+	overridden func(key string) bool
+}
+
+func newConfigReloader(path string, overridden func(key string) bool) (*configReloader, error) {
+	current, err := readConfigFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return &configReloader{path: path, current: current, overridden: overridden}, nil
+}
+
+func readConfigFile(path string) (*viper.Viper, error) {
+	v := viper.New()
+	v.SetConfigFile(path)
+	if err := v.ReadInConfig(); err != nil {
+		return nil, err
+	}
+	return v, nil
+}
+
+// Reload reads the configuration file and applies the changed settings if all of them can be changed at runtime.
+// It returns false if the operator must be restarted to apply the changes.
+func (r *configReloader) Reload() (bool, error) {
+	updated, err := readConfigFile(r.path)
+	if err != nil {
+		return false, err
+	}
+
+	var changed []string
+	seen := map[string]struct{}{}
+	for _, key := range append(r.current.AllKeys(), updated.AllKeys()...) {
+		if _, exists := seen[key]; exists {
+			continue
+		}
+		seen[key] = struct{}{}
+		if reflect.DeepEqual(r.current.Get(key), updated.Get(key)) {
+			continue
+		}
+		// settings removed from the file fall back to their default value, which is only known at startup
+		if _, reloadable := reloadableSettings[key]; !reloadable || !updated.IsSet(key) {
+			log.Info("Configuration change requires a restart", "setting", key)
+			return false, nil
+		}
+		changed = append(changed, key)
+	}
+
+	for _, key := range changed {
+		if r.overridden(key) {
+			log.Info("Ignoring configuration change overridden by a flag or an environment variable", "setting", key)
+			continue
+		}
+		log.Info("Applying configuration change", "setting", key, "value", updated.Get(key))
+		reloadableSettings[key](updated)
+	}
+	r.current = updated
+	return true, nil
+}
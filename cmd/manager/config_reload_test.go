@@ -0,0 +1,70 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package manager
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/container"
+)
+
+func Test_configReloader_Reload(t *testing.T) {
+	log = logf.Log.WithName("test")
+	defer container.SetContainerRegistry(container.DefaultContainerRegistry)
+
+	path := filepath.Join(t.TempDir(), "eck.yaml")
+	writeConfig := func(content string) {
+		require.NoError(t, os.WriteFile(path, []byte(content), 0600))
+	}
+	writeConfig("log-verbosity: 0\ncontainer-registry: docker.elastic.co\nmax-concurrent-reconciles: 3\n")
+
+	var overridden []string
+	reloader, err := newConfigReloader(path, func(key string) bool {
+		for _, k := range overridden {
+			if k == key {
+				return true
+			}
+		}
+		return false
+	})
+	require.NoError(t, err)
+
+	// reloadable settings are applied at runtime
+	writeConfig("log-verbosity: 1\ncontainer-registry: my.registry\nmax-concurrent-reconciles: 3\n")
+	reloaded, err := reloader.Reload()
+	require.NoError(t, err)
+	require.True(t, reloaded)
+	require.Equal(t, "my.registry/kibana/kibana:8.5.0", container.ImageRepository(container.KibanaImage, "8.5.0"))
+
+	// settings overridden by a flag or an environment variable are left untouched
+	overridden = []string{"container-registry"}
+	writeConfig("log-verbosity: 1\ncontainer-registry: other.registry\nmax-concurrent-reconciles: 3\n")
+	reloaded, err = reloader.Reload()
+	require.NoError(t, err)
+	require.True(t, reloaded)
+	require.Equal(t, "my.registry/kibana/kibana:8.5.0", container.ImageRepository(container.KibanaImage, "8.5.0"))
+
+	// other settings require a restart
+	writeConfig("log-verbosity: 1\ncontainer-registry: other.registry\nmax-concurrent-reconciles: 5\n")
+	reloaded, err = reloader.Reload()
+	require.NoError(t, err)
+	require.False(t, reloaded)
+
+	// removing a reloadable setting requires a restart to fall back to its default value
+	writeConfig("container-registry: other.registry\nmax-concurrent-reconciles: 3\n")
+	reloaded, err = reloader.Reload()
+	require.NoError(t, err)
+	require.False(t, reloaded)
+
+	// invalid configuration files are reported
+	writeConfig("log-verbosity: [")
+	_, err = reloader.Reload()
+	require.Error(t, err)
+}
@@ -0,0 +1,81 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package manager
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	stdnet "net"
+	"net/http"
+	"strconv"
+	"time"
+
+	"sigs.k8s.io/controller-runtime/pkg/cache"
+	"sigs.k8s.io/controller-runtime/pkg/healthz"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/watchdog"
+)
+
+const (
+	// healthCheckTimeout bounds the time spent by each check, well below the default timeout of the Kubernetes probes.
+	healthCheckTimeout = 500 * time.Millisecond
+	// stuckReconciliationTimeout is the duration after which a reconciliation still in progress is considered stuck, well
+	// above the time spent by a reconciliation waiting for the timeouts of the requests to Elasticsearch.
+	stuckReconciliationTimeout = 30 * time.Minute
+)
+
+// addHealthChecks registers the checks served by the manager on the /healthz and /readyz endpoints of the health
+// probe port. The operator is live as long as none of its reconciliations is stuck, according to the given watchdog.
+// Losing the leadership stops the manager and exits the process, which does not need a liveness check. The operator is
+// ready once its informer caches are synced and, if enabled, its webhook server accepts connections.
+func addHealthChecks(mgr manager.Manager, w *watchdog.Watchdog, webhookEnabled bool) error {
+	if err := mgr.AddHealthzCheck("ping", healthz.Ping); err != nil {
+		return err
+	}
+	if w != nil {
+		if err := mgr.AddHealthzCheck("reconciliations", w.Check); err != nil {
+			return err
+		}
+	}
+	if err := mgr.AddReadyzCheck("informers", cacheSyncCheck(mgr.GetCache())); err != nil {
+		return err
+	}
+	if webhookEnabled {
+		server := mgr.GetWebhookServer()
+		host := server.Host
+		if host == "" {
+			host = "localhost"
+		}
+		if err := mgr.AddReadyzCheck("webhook", listenerCheck(stdnet.JoinHostPort(host, strconv.Itoa(server.Port)))); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// cacheSyncCheck returns a check failing until the informer caches are started and synced.
+func cacheSyncCheck(c cache.Cache) healthz.Checker {
+	return func(req *http.Request) error {
+		ctx, cancel := context.WithTimeout(req.Context(), healthCheckTimeout)
+		defer cancel()
+		if !c.WaitForCacheSync(ctx) {
+			return errors.New("informer caches are not synced")
+		}
+		return nil
+	}
+}
+
+// listenerCheck returns a check failing if no server accepts TCP connections at the given address.
+func listenerCheck(address string) healthz.Checker {
+	return func(req *http.Request) error {
+		conn, err := (&stdnet.Dialer{Timeout: healthCheckTimeout}).DialContext(req.Context(), "tcp", address)
+		if err != nil {
+			return fmt.Errorf("server not listening on %s: %w", address, err)
+		}
+		return conn.Close()
+	}
+}
@@ -8,6 +8,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	stdnet "net"
 	"net/http"
 	"net/http/pprof"
 	"os"
@@ -51,6 +52,7 @@ import (
 	entv1beta1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/enterprisesearch/v1beta1"
 	kbv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/kibana/v1"
 	kbv1beta1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/kibana/v1beta1"
+	lsv1alpha1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/logstash/v1alpha1"
 	emsv1alpha1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/maps/v1alpha1"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/agent"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/apmserver"
@@ -59,8 +61,12 @@ import (
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/autoscaling"
 	esavalidation "github.com/elastic/cloud-on-k8s/v2/pkg/controller/autoscaling/elasticsearch/validation"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/beat"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/cadistribution"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/certificates"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/container"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/decisions"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/dryrun"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/impersonation"
 	commonlicense "github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/license"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/operator"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/reconciler"
@@ -68,6 +74,7 @@ import (
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/tracing"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/tracing/apmclientgo"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/version"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/watchdog"
 	commonwebhook "github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/webhook"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/elasticsearch"
 	esclient "github.com/elastic/cloud-on-k8s/v2/pkg/controller/elasticsearch/client"
@@ -77,8 +84,10 @@ import (
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/kibana"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/license"
 	licensetrial "github.com/elastic/cloud-on-k8s/v2/pkg/controller/license/trial"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/logstash"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/maps"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/remoteca"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/stackconfigpolicy"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/webhook"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/dev"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/dev/portforward"
@@ -93,9 +102,10 @@ import (
 )
 
 const (
-	DefaultMetricPort  = 0 // disabled
-	DefaultWebhookName = "elastic-webhook.k8s.elastic.co"
-	WebhookPort        = 9443
+	DefaultMetricPort      = 0 // disabled
+	DefaultHealthProbePort = 0 // disabled
+	DefaultWebhookName     = "elastic-webhook.k8s.elastic.co"
+	WebhookPort            = 9443
 
 	LeaderElectionConfigMapName = "elastic-operator-leader"
 
@@ -147,6 +157,11 @@ func Command() *cobra.Command {
 		"",
 		"Path to a directory containing a CA certificate (tls.crt) and its associated private key (tls.key) to be used for all managed resources. Effectively disables the CA rotation and validity options.",
 	)
+	cmd.Flags().Bool(
+		operator.CAPerNamespaceFlag,
+		false,
+		"Use a single CA per namespace, generated and rotated by the operator, for all the managed resources of that namespace instead of a self-signed CA per resource. Cannot be used together with the ca-dir option.",
+	)
 	cmd.Flags().Duration(
 		operator.CACertRotateBeforeFlag,
 		certificates.DefaultRotateBefore,
@@ -181,18 +196,63 @@ func Command() *cobra.Command {
 	cmd.Flags().String(
 		operator.DebugHTTPListenFlag,
 		"localhost:6060",
-		"Listen address for debug HTTP server (only available in development mode)",
+		"Listen address for debug HTTP server (only available in development mode or with decision traces enabled)",
 	)
 	cmd.Flags().Bool(
 		operator.DisableConfigWatch,
 		false,
 		"Disable watching the configuration file for changes",
 	)
+	cmd.Flags().String(
+		operator.DriftPolicyFlag,
+		string(reconciler.DriftPolicyRevert),
+		fmt.Sprintf("How manual modifications of the resources managed by the operator are handled. Possible values: %s (revert and report them with an event), %s (only report them with an event).", reconciler.DriftPolicyRevert, reconciler.DriftPolicyReport),
+	)
+	cmd.Flags().Bool(
+		operator.DryRunFlag,
+		false,
+		"Validate the changes to the managed resources with dry-run requests and report them with events instead of applying them. Requests to Elasticsearch other than reads are not sent.",
+	)
 	cmd.Flags().Duration(
 		operator.ElasticsearchClientTimeout,
 		3*time.Minute,
 		"Default timeout for requests made by the Elasticsearch client.",
 	)
+	cmd.Flags().String(
+		operator.ElasticsearchDefaultConfigFlag,
+		"",
+		"Elasticsearch configuration in YAML format merged beneath the configuration of every managed Elasticsearch node set. User-provided configuration takes precedence.",
+	)
+	cmd.Flags().Duration(
+		operator.LicenseExpiryWarningPeriodFlag,
+		30*24*time.Hour,
+		"How long before the expiry of the operator license and of the Elasticsearch cluster licenses warning events are emitted. 0 disables the warnings.",
+	)
+	cmd.Flags().Duration(
+		operator.PVCDeletionGracePeriodFlag,
+		0,
+		"Duration during which the PersistentVolumeClaims no longer used by an Elasticsearch cluster, for example after a nodeSet removal or a scale down, are kept before being deleted. 0 deletes them immediately.",
+	)
+	cmd.Flags().Duration(
+		operator.ReconcileBackoffBaseDelayFlag,
+		5*time.Millisecond,
+		"Delay before retrying a failed reconciliation, doubled after each consecutive failure of the same resource up to reconcile-backoff-max-delay.",
+	)
+	cmd.Flags().Duration(
+		operator.ReconcileBackoffMaxDelayFlag,
+		1000*time.Second,
+		"Maximum delay before retrying a failed reconciliation of a resource.",
+	)
+	cmd.Flags().Int(
+		operator.ReconcileRateLimitBurstFlag,
+		100,
+		"Number of reconciliations each controller can queue in a burst above reconcile-rate-limit-qps.",
+	)
+	cmd.Flags().Float64(
+		operator.ReconcileRateLimitQPSFlag,
+		10,
+		"Overall number of reconciliations per second each controller can queue, including retries and periodic requeues.",
+	)
 	cmd.Flags().Duration(
 		operator.ElasticsearchObservationIntervalFlag,
 		10*time.Second,
@@ -213,11 +273,46 @@ func Command() *cobra.Command {
 		false, // Set to false for backward compatibility
 		"Restrict cross-namespace resource association through RBAC (eg. referencing Elasticsearch from Kibana)",
 	)
+	cmd.Flags().Bool(
+		operator.EnableCADistributionFlag,
+		false,
+		fmt.Sprintf("Enable copying the HTTP CA certificate of Elasticsearch clusters into ConfigMaps of the namespaces selected by the %s annotation. Requires permissions to list and watch namespaces.", cadistribution.NamespaceSelectorAnnotation),
+	)
+	cmd.Flags().Bool(
+		operator.EnableDecisionTraceFlag,
+		false,
+		fmt.Sprintf("Enable recording the checks and decisions made while reconciling Elasticsearch clusters. The most recent traces are served on the %s path of the debug HTTP server.", decisions.HandlerPath),
+	)
 	cmd.Flags().Bool(
 		operator.EnableLeaderElection,
 		true,
 		"Enable leader election. Enabling this will ensure there is only one active operator.",
 	)
+	cmd.Flags().String(
+		operator.LeaderElectionIDFlag,
+		LeaderElectionConfigMapName,
+		"Name of the resource used for leader election.",
+	)
+	cmd.Flags().String(
+		operator.LeaderElectionNamespaceFlag,
+		"",
+		fmt.Sprintf("Namespace of the resource used for leader election. Defaults to the namespace set with %s.", operator.OperatorNamespaceFlag),
+	)
+	cmd.Flags().Duration(
+		operator.LeaderElectionLeaseDurationFlag,
+		15*time.Second,
+		"Duration non-leader operator instances wait before trying to acquire the leadership. This is the maximum time a failover can take.",
+	)
+	cmd.Flags().Duration(
+		operator.LeaderElectionRenewDeadlineFlag,
+		10*time.Second,
+		"Duration the leader keeps trying to refresh the leadership before giving it up. Must be lower than the lease duration.",
+	)
+	cmd.Flags().Duration(
+		operator.LeaderElectionRetryPeriodFlag,
+		2*time.Second,
+		"Duration operator instances wait between attempts to acquire or refresh the leadership. Must be lower than the renew deadline.",
+	)
 	cmd.Flags().Bool(
 		operator.EnableTracingFlag,
 		false,
@@ -232,6 +327,21 @@ func Command() *cobra.Command {
 		[]string{},
 		"Comma separated list of node labels which are allowed to be copied as annotations on Elasticsearch Pods, empty by default",
 	)
+	cmd.Flags().Duration(
+		operator.GarbageCollectionIntervalFlag,
+		1*time.Hour,
+		"Interval between the deletions of the Secrets left over by deleted resources, such as association users and certificates. Set 0 to only delete them when the operator starts.",
+	)
+	cmd.Flags().Int(
+		operator.HealthProbePortFlag,
+		DefaultHealthProbePort,
+		"Port serving the /healthz liveness and /readyz readiness endpoints of the operator (set 0 to disable)",
+	)
+	cmd.Flags().StringToString(
+		operator.ImpersonatedServiceAccountsFlag,
+		map[string]string{},
+		"Comma separated list of namespace=serviceaccount pairs. Resources of these namespaces are mutated by impersonating the given ServiceAccount of the namespace, so that Kubernetes audit logs attribute the changes to it. Requires permissions to impersonate these ServiceAccounts.",
+	)
 	cmd.Flags().String(
 		operator.IPFamilyFlag,
 		"",
@@ -247,11 +357,26 @@ func Command() *cobra.Command {
 		true,
 		"Enables automatic certificates management for the webhook. The Secret and the ValidatingWebhookConfiguration must be created before running the operator",
 	)
+	cmd.Flags().Int(
+		operator.MaxCertificateIssuancesFlag,
+		0,
+		"Number of certificates issued in a namespace during the last hour above which the validating webhook rejects the creation of Elasticsearch clusters and changes to their HTTP and transport settings in that namespace (set 0 to disable)",
+	)
 	cmd.Flags().Int(
 		operator.MaxConcurrentReconcilesFlag,
 		3,
 		"Sets maximum number of concurrent reconciles per controller (Elasticsearch, Kibana, Apm Server etc). Affects the ability of the operator to process changes concurrently.",
 	)
+	cmd.Flags().StringToString(
+		operator.MaxConcurrentReconcilesPerControllerFlag,
+		map[string]string{},
+		"Comma separated list of controller=count pairs overriding max-concurrent-reconciles for the given controllers, for example elasticsearch-controller=10",
+	)
+	cmd.Flags().String(
+		operator.MetricsHostFlag,
+		"",
+		"The host to which the operator should bind to serve metrics in the Prometheus format. Will be combined with metrics-port.",
+	)
 	cmd.Flags().Int(
 		operator.MetricsPortFlag,
 		DefaultMetricPort,
@@ -303,6 +428,11 @@ func Command() *cobra.Command {
 		"auto-detect",
 		"Enables setting the default security context with fsGroup=1000 for Elasticsearch 8.0+ Pods. Ignored pre-8.0. Possible values: true, false, auto-detect",
 	)
+	cmd.Flags().Bool(
+		operator.SetVMMaxMapCountFlag,
+		false,
+		"Enables adding a privileged init container setting the vm.max_map_count kernel setting to Elasticsearch Pods. Can be overridden in the Elasticsearch specification.",
+	)
 
 	// hide development mode flags from the usage message
 	_ = cmd.Flags().MarkHidden(operator.AutoPortForwardFlag)
@@ -322,16 +452,25 @@ func Command() *cobra.Command {
 	return cmd
 }
 
-func doRun(_ *cobra.Command, _ []string) error {
+func doRun(cmd *cobra.Command, _ []string) error {
 	ctx := signals.SetupSignalHandler()
 
 	// receive config/CA file update events over a channel
 	confUpdateChan := make(chan struct{}, 1)
 	var toWatch []string
 
-	// watch for config file changes
+	// watch for config file changes, and apply the changes to the settings that can be reloaded at runtime
+	var reloader *configReloader
 	if !viper.GetBool(operator.DisableConfigWatch) && configFile != "" {
 		toWatch = append(toWatch, configFile)
+		var err error
+		reloader, err = newConfigReloader(configFile, func(key string) bool {
+			_, isEnvSet := os.LookupEnv(strings.ToUpper(strings.ReplaceAll(key, "-", "_")))
+			return cmd.Flags().Changed(key) || isEnvSet
+		})
+		if err != nil {
+			return fmt.Errorf("failed to read config file %s: %w", configFile, err)
+		}
 	}
 
 	// watch for CA files if configured
@@ -345,7 +484,16 @@ func doRun(_ *cobra.Command, _ []string) error {
 		)
 	}
 
-	onConfChange := func(_ []string) {
+	onConfChange := func(updated []string) {
+		if reloader != nil && len(updated) == 1 && updated[0] == configFile {
+			reloaded, err := reloader.Reload()
+			if err != nil {
+				log.Error(err, "Failed to reload the configuration file", "path", configFile)
+			}
+			if reloaded {
+				return
+			}
+		}
 		confUpdateChan <- struct{}{}
 	}
 	watcher := fs.NewFileWatcher(ctx, toWatch, onConfChange, 15*time.Second)
@@ -374,7 +522,7 @@ func doRun(_ *cobra.Command, _ []string) error {
 		case <-ctx.Done(): // signal received
 			log.Info("Shutting down due to signal")
 			return nil
-		case <-confUpdateChan: // config file updated
+		case <-confUpdateChan: // config or CA files updated with changes that cannot be applied at runtime
 			log.Info("Shutting down to apply updated configuration")
 			return nil
 		}
@@ -395,14 +543,24 @@ func startOperator(ctx context.Context) error {
 		return err
 	}
 
-	if dev.Enabled {
-		// expose pprof if development mode is enabled
+	var decisionTraces *decisions.Store
+	if viper.GetBool(operator.EnableDecisionTraceFlag) {
+		decisionTraces = decisions.NewStore()
+	}
+
+	if dev.Enabled || decisionTraces != nil {
 		mux := http.NewServeMux()
-		mux.HandleFunc("/debug/pprof/", pprof.Index)
-		mux.HandleFunc("/debug/pprof/cmdline", pprof.Cmdline)
-		mux.HandleFunc("/debug/pprof/profile", pprof.Profile)
-		mux.HandleFunc("/debug/pprof/symbol", pprof.Symbol)
-		mux.HandleFunc("/debug/pprof/trace", pprof.Trace)
+		if dev.Enabled {
+			// expose pprof if development mode is enabled
+			mux.HandleFunc("/debug/pprof/", pprof.Index)
+			mux.HandleFunc("/debug/pprof/cmdline", pprof.Cmdline)
+			mux.HandleFunc("/debug/pprof/profile", pprof.Profile)
+			mux.HandleFunc("/debug/pprof/symbol", pprof.Symbol)
+			mux.HandleFunc("/debug/pprof/trace", pprof.Trace)
+		}
+		if decisionTraces != nil {
+			mux.Handle(decisions.HandlerPath, decisionTraces)
+		}
 
 		pprofServer := http.Server{
 			Addr:              viper.GetString(operator.DebugHTTPListenFlag),
@@ -486,14 +644,32 @@ func startOperator(ctx context.Context) error {
 	// also set up the v1beta1 scheme, used by the v1beta1 webhook
 	controllerscheme.SetupV1beta1Scheme()
 
+	leaseDuration := viper.GetDuration(operator.LeaderElectionLeaseDurationFlag)
+	renewDeadline := viper.GetDuration(operator.LeaderElectionRenewDeadlineFlag)
+	retryPeriod := viper.GetDuration(operator.LeaderElectionRetryPeriodFlag)
+	if retryPeriod <= 0 || renewDeadline <= retryPeriod || leaseDuration <= renewDeadline {
+		err := fmt.Errorf("%s must be greater than %s, which must be greater than %s and 0",
+			operator.LeaderElectionLeaseDurationFlag, operator.LeaderElectionRenewDeadlineFlag, operator.LeaderElectionRetryPeriodFlag)
+		log.Error(err, "Invalid leader election durations",
+			"lease_duration", leaseDuration, "renew_deadline", renewDeadline, "retry_period", retryPeriod)
+		return err
+	}
+	leaderElectionNamespace := viper.GetString(operator.LeaderElectionNamespaceFlag)
+	if leaderElectionNamespace == "" {
+		leaderElectionNamespace = operatorNamespace
+	}
+
 	// Create a new Cmd to provide shared dependencies and start components
 	opts := ctrl.Options{
 		Scheme:                     clientgoscheme.Scheme,
 		CertDir:                    viper.GetString(operator.WebhookCertDirFlag),
 		LeaderElection:             viper.GetBool(operator.EnableLeaderElection),
 		LeaderElectionResourceLock: resourcelock.ConfigMapsLeasesResourceLock, // TODO: use 'lease' after operator is released with 'configmapsleases'
-		LeaderElectionID:           LeaderElectionConfigMapName,
-		LeaderElectionNamespace:    operatorNamespace,
+		LeaderElectionID:           viper.GetString(operator.LeaderElectionIDFlag),
+		LeaderElectionNamespace:    leaderElectionNamespace,
+		LeaseDuration:              &leaseDuration,
+		RenewDeadline:              &renewDeadline,
+		RetryPeriod:                &retryPeriod,
 		Logger:                     log.WithName("eck-operator"),
 	}
 
@@ -515,11 +691,58 @@ func startOperator(ctx context.Context) error {
 	}
 
 	// only expose prometheus metrics if provided a non-zero port
+	metricsHost := viper.GetString(operator.MetricsHostFlag)
 	metricsPort := viper.GetInt(operator.MetricsPortFlag)
+	opts.MetricsBindAddress = "0" // disabled
 	if metricsPort != 0 {
-		log.Info("Exposing Prometheus metrics on /metrics", "port", metricsPort)
+		log.Info("Exposing Prometheus metrics on /metrics", "host", metricsHost, "port", metricsPort)
+		opts.MetricsBindAddress = stdnet.JoinHostPort(metricsHost, strconv.Itoa(metricsPort))
+	}
+
+	// only serve the health probe endpoints if provided a non-zero port
+	healthProbePort := viper.GetInt(operator.HealthProbePortFlag)
+	opts.HealthProbeBindAddress = "0" // disabled
+	if healthProbePort != 0 {
+		log.Info("Serving health probes on /healthz and /readyz", "port", healthProbePort)
+		opts.HealthProbeBindAddress = stdnet.JoinHostPort("", strconv.Itoa(healthProbePort))
+	}
+
+	reconcileRateLimiter := operator.RateLimiterParams{
+		BaseDelay: viper.GetDuration(operator.ReconcileBackoffBaseDelayFlag),
+		MaxDelay:  viper.GetDuration(operator.ReconcileBackoffMaxDelayFlag),
+		QPS:       viper.GetFloat64(operator.ReconcileRateLimitQPSFlag),
+		Burst:     viper.GetInt(operator.ReconcileRateLimitBurstFlag),
+	}
+	if reconcileRateLimiter.BaseDelay <= 0 || reconcileRateLimiter.MaxDelay < reconcileRateLimiter.BaseDelay ||
+		reconcileRateLimiter.QPS <= 0 || reconcileRateLimiter.Burst <= 0 {
+		err := fmt.Errorf("%s must be greater than 0 and lower than or equal to %s, %s and %s must be greater than 0",
+			operator.ReconcileBackoffBaseDelayFlag, operator.ReconcileBackoffMaxDelayFlag, operator.ReconcileRateLimitQPSFlag, operator.ReconcileRateLimitBurstFlag)
+		log.Error(err, "Invalid reconciliation rate limiter settings",
+			"base_delay", reconcileRateLimiter.BaseDelay, "max_delay", reconcileRateLimiter.MaxDelay,
+			"qps", reconcileRateLimiter.QPS, "burst", reconcileRateLimiter.Burst)
+		return err
+	}
+
+	maxConcurrentReconcilesPerController, err := operator.ParseMaxConcurrentReconcilesPerController(
+		viper.GetStringMapString(operator.MaxConcurrentReconcilesPerControllerFlag),
+	)
+	if err != nil {
+		log.Error(err, "Invalid maximum number of concurrent reconciles per controller")
+		return err
+	}
+
+	// impersonate service accounts to mutate the resources of some namespaces if requested
+	impersonatedServiceAccounts, err := impersonation.ParseServiceAccounts(viper.GetStringMapString(operator.ImpersonatedServiceAccountsFlag))
+	if err != nil {
+		log.Error(err, "Invalid impersonated service accounts")
+		return err
+	}
+	opts.NewClient = impersonation.NewClientFunc(impersonatedServiceAccounts)
+
+	// only validate and report the changes to the managed resources in dry-run mode
+	if viper.GetBool(operator.DryRunFlag) {
+		opts.NewClient = dryrun.NewClientFunc(opts.NewClient)
 	}
-	opts.MetricsBindAddress = fmt.Sprintf(":%d", metricsPort) // 0 to disable
 
 	opts.Port = WebhookPort
 	mgr, err := ctrl.NewManager(cfg, opts)
@@ -528,12 +751,31 @@ func startOperator(ctx context.Context) error {
 		return err
 	}
 
+	// detect manual modifications of the managed resources
+	driftPolicy, err := reconciler.ParseDriftPolicy(viper.GetString(operator.DriftPolicyFlag))
+	if err != nil {
+		log.Error(err, "Invalid drift policy")
+		return err
+	}
+
+	// report the changes made through the APIs of the Elastic Stack applications in dry-run mode
+	var dryRun *dryrun.Mode
+	if viper.GetBool(operator.DryRunFlag) {
+		dryRun = dryrun.NewMode(mgr.GetEventRecorderFor(dryrun.EventSourceComponent))
+	}
+
 	// Retrieve globally shared CA if any
 	ca, err := readOptionalCA(viper.GetString(operator.CADirFlag))
 	if err != nil {
 		log.Error(err, "Cannot read global CA")
 		return err
 	}
+	namespaceCA := viper.GetBool(operator.CAPerNamespaceFlag)
+	if namespaceCA && ca != nil {
+		err := fmt.Errorf("%s and %s are mutually exclusive", operator.CAPerNamespaceFlag, operator.CADirFlag)
+		log.Error(err, "Invalid CA configuration")
+		return err
+	}
 
 	// Verify cert validity options
 	caCertValidity, caCertRotateBefore, err := validateCertExpirationFlags(operator.CACertValidityFlag, operator.CACertRotateBeforeFlag)
@@ -580,6 +822,12 @@ func startOperator(ctx context.Context) error {
 		return err
 	}
 
+	esDefaultConfig, err := settings.ParseDefaultConfig(viper.GetString(operator.ElasticsearchDefaultConfigFlag))
+	if err != nil {
+		log.Error(err, "Failed to parse default Elasticsearch configuration")
+		return err
+	}
+
 	setDefaultSecurityContext, err := determineSetDefaultSecurityContext(viper.GetString(operator.SetDefaultSecurityContextFlag), clientset)
 	if err != nil {
 		log.Error(err, "failed to determine how to set default security context")
@@ -588,12 +836,14 @@ func startOperator(ctx context.Context) error {
 
 	params := operator.Parameters{
 		Dialer:                           dialer,
+		ElasticsearchDefaultConfig:       esDefaultConfig,
 		ElasticsearchObservationInterval: viper.GetDuration(operator.ElasticsearchObservationIntervalFlag),
 		ExposedNodeLabels:                exposedNodeLabels,
 		IPFamily:                         ipFamily,
 		OperatorNamespace:                operatorNamespace,
 		OperatorInfo:                     operatorInfo,
 		GlobalCA:                         ca,
+		NamespaceCA:                      namespaceCA,
 		CACertRotation: certificates.RotationParams{
 			Validity:     caCertValidity,
 			RotateBefore: caCertRotateBefore,
@@ -602,16 +852,38 @@ func startOperator(ctx context.Context) error {
 			Validity:     certValidity,
 			RotateBefore: certRotateBefore,
 		},
-		MaxConcurrentReconciles:   viper.GetInt(operator.MaxConcurrentReconcilesFlag),
-		SetDefaultSecurityContext: setDefaultSecurityContext,
-		ValidateStorageClass:      viper.GetBool(operator.ValidateStorageClassFlag),
-		Tracer:                    tracer,
+		LicenseExpiryWarningPeriod:           viper.GetDuration(operator.LicenseExpiryWarningPeriodFlag),
+		ManagedNamespaces:                    managedNamespaces,
+		MaxCertificateIssuances:              viper.GetInt(operator.MaxCertificateIssuancesFlag),
+		MaxConcurrentReconciles:              viper.GetInt(operator.MaxConcurrentReconcilesFlag),
+		MaxConcurrentReconcilesPerController: maxConcurrentReconcilesPerController,
+		Controllers:                          operator.NewControllerRegistry(),
+		PVCDeletionGracePeriod:               viper.GetDuration(operator.PVCDeletionGracePeriodFlag),
+		ReconcileRateLimiter:                 reconcileRateLimiter,
+		SetDefaultSecurityContext:            setDefaultSecurityContext,
+		SetVMMaxMapCount:                     viper.GetBool(operator.SetVMMaxMapCountFlag),
+		ValidateStorageClass:                 viper.GetBool(operator.ValidateStorageClassFlag),
+		DecisionTraces:                       decisionTraces,
+		DriftDetection:                       reconciler.NewDriftDetection(driftPolicy, mgr.GetEventRecorderFor("elastic-operator")),
+		DryRun:                               dryRun,
+		Tracer:                               tracer,
+	}
+
+	if healthProbePort != 0 {
+		params.Watchdog = watchdog.New(stuckReconciliationTimeout)
 	}
 
 	if viper.GetBool(operator.EnableWebhookFlag) {
 		setupWebhook(ctx, mgr, params, clientset, exposedNodeLabels, managedNamespaces, tracer)
 	}
 
+	if healthProbePort != 0 {
+		if err := addHealthChecks(mgr, params.Watchdog, viper.GetBool(operator.EnableWebhookFlag)); err != nil {
+			log.Error(err, "Failed to add health checks")
+			return err
+		}
+	}
+
 	enforceRbacOnRefs := viper.GetBool(operator.EnforceRBACOnRefsFlag)
 
 	var accessReviewer rbac.AccessReviewer
@@ -624,6 +896,11 @@ func startOperator(ctx context.Context) error {
 	if err := registerControllers(mgr, params, accessReviewer); err != nil {
 		return err
 	}
+	// the controller names are only known once all the controllers are registered
+	if err := params.ValidateMaxConcurrentReconcilesPerController(); err != nil {
+		log.Error(err, "Invalid maximum number of concurrent reconciles per controller")
+		return err
+	}
 
 	disableTelemetry := viper.GetBool(operator.DisableTelemetryFlag)
 	telemetryInterval := viper.GetDuration(operator.TelemetryIntervalFlag)
@@ -697,7 +974,10 @@ func asyncTasks(
 
 	// Start the resource reporter
 	go func() {
-		r := licensing.NewResourceReporter(mgr.GetClient(), operatorNamespace, tracer)
+		r := licensing.NewResourceReporter(
+			mgr.GetClient(), operatorNamespace, tracer,
+			mgr.GetEventRecorderFor("resource-reporter"), viper.GetDuration(operator.LicenseExpiryWarningPeriodFlag),
+		)
 		r.Start(ctx, licensing.ResourceReporterFrequency)
 	}()
 
@@ -710,16 +990,48 @@ func asyncTasks(
 	}
 
 	// Garbage collect orphaned secrets leftover from deleted resources while the operator was not running
-	// - association user secrets
-	gcCtx := tracing.NewContextTransaction(ctx, tracer, tracing.RunOnceTxType, "garbage-collection", nil)
-	err := garbageCollectUsers(gcCtx, cfg, managedNamespaces)
-	if err != nil {
+	if err := garbageCollectOrphanSecrets(ctx, mgr, cfg, managedNamespaces, tracer, tracing.RunOnceTxType); err != nil {
 		log.Error(err, "exiting due to unrecoverable error")
 		os.Exit(1)
 	}
+
+	// then periodically, to delete the secrets whose deletion was missed while the operator was running
+	gcInterval := viper.GetDuration(operator.GarbageCollectionIntervalFlag)
+	if gcInterval <= 0 {
+		return
+	}
+	ticker := time.NewTicker(gcInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := garbageCollectOrphanSecrets(ctx, mgr, cfg, managedNamespaces, tracer, tracing.PeriodicTxType); err != nil {
+				log.Error(err, "Orphan secrets garbage collection failed, will be attempted again", "interval", gcInterval)
+			}
+		}
+	}
+}
+
+// garbageCollectOrphanSecrets deletes the secrets of deleted resources.
+func garbageCollectOrphanSecrets(
+	ctx context.Context,
+	mgr manager.Manager,
+	cfg *rest.Config,
+	managedNamespaces []string,
+	tracer *apm.Tracer,
+	txType tracing.TxType,
+) error {
+	gcCtx := tracing.NewContextTransaction(ctx, tracer, txType, "garbage-collection", nil)
+	defer tracing.EndContextTransaction(gcCtx)
+	// - association user secrets
+	if err := garbageCollectUsers(gcCtx, cfg, managedNamespaces); err != nil {
+		return err
+	}
 	// - soft-owned secrets
 	garbageCollectSoftOwnedSecrets(gcCtx, mgr.GetClient())
-	tracing.EndContextTransaction(gcCtx)
+	return nil
 }
 
 func chooseAndValidateIPFamily(ipFamilyStr string, ipFamilyDefault corev1.IPFamily) (corev1.IPFamily, error) {
@@ -798,8 +1110,9 @@ func registerControllers(mgr manager.Manager, params operator.Parameters, access
 		{name: "LicenseTrial", registerFunc: licensetrial.Add},
 		{name: "Agent", registerFunc: agent.Add},
 		{name: "Maps", registerFunc: maps.Add},
+		{name: "Logstash", registerFunc: logstash.Add},
+		{name: "StackConfigPolicy", registerFunc: stackconfigpolicy.Add},
 	}
-
 	for _, c := range controllers {
 		if err := c.registerFunc(mgr, params); err != nil {
 			log.Error(err, "Failed to register controller", "controller", c.name)
@@ -816,6 +1129,7 @@ func registerControllers(mgr manager.Manager, params operator.Parameters, access
 		{name: "APM-KB", registerFunc: associationctl.AddApmKibana},
 		{name: "KB-ES", registerFunc: associationctl.AddKibanaES},
 		{name: "KB-ENT", registerFunc: associationctl.AddKibanaEnt},
+		{name: "KB-EMS", registerFunc: associationctl.AddKibanaEMS},
 		{name: "ENT-ES", registerFunc: associationctl.AddEntES},
 		{name: "BEAT-ES", registerFunc: associationctl.AddBeatES},
 		{name: "BEAT-KB", registerFunc: associationctl.AddBeatKibana},
@@ -823,10 +1137,17 @@ func registerControllers(mgr manager.Manager, params operator.Parameters, access
 		{name: "AGENT-KB", registerFunc: associationctl.AddAgentKibana},
 		{name: "AGENT-FS", registerFunc: associationctl.AddAgentFleetServer},
 		{name: "EMS-ES", registerFunc: associationctl.AddMapsES},
+		{name: "LOGSTASH-ES", registerFunc: associationctl.AddLogstashES},
 		{name: "ES-MONITORING", registerFunc: associationctl.AddEsMonitoring},
 		{name: "KB-MONITORING", registerFunc: associationctl.AddKbMonitoring},
 		{name: "BEAT-MONITORING", registerFunc: associationctl.AddBeatMonitoring},
 	}
+	if viper.GetBool(operator.EnableCADistributionFlag) {
+		assocControllers = append(assocControllers, struct {
+			name         string
+			registerFunc func(manager.Manager, rbac.AccessReviewer, operator.Parameters) error
+		}{name: "CADistribution", registerFunc: cadistribution.Add})
+	}
 
 	for _, c := range assocControllers {
 		if err := c.registerFunc(mgr, accessReviewer, params); err != nil {
@@ -853,17 +1174,22 @@ func garbageCollectUsers(ctx context.Context, cfg *rest.Config, managedNamespace
 	span, ctx := apm.StartSpan(ctx, "gc_users", tracing.SpanTypeApp)
 	defer span.End()
 
-	ugc, err := association.NewUsersGarbageCollector(cfg, managedNamespaces)
+	// Use a sync client here in order not to depend on any cache initialization
+	cl, err := client.New(cfg, client.Options{})
 	if err != nil {
 		return fmt.Errorf("user garbage collector creation failed: %w", err)
 	}
-	err = ugc.
+	if viper.GetBool(operator.DryRunFlag) {
+		cl = dryrun.NewClient(cl, nil)
+	}
+	err = association.NewUsersGarbageCollector(cl, managedNamespaces).
 		For(&apmv1.ApmServerList{}, associationctl.ApmAssociationLabelNamespace, associationctl.ApmAssociationLabelName).
 		For(&kbv1.KibanaList{}, associationctl.KibanaAssociationLabelNamespace, associationctl.KibanaAssociationLabelName).
 		For(&entv1.EnterpriseSearchList{}, associationctl.EntESAssociationLabelNamespace, associationctl.EntESAssociationLabelName).
 		For(&beatv1beta1.BeatList{}, associationctl.BeatAssociationLabelNamespace, associationctl.BeatAssociationLabelName).
 		For(&agentv1alpha1.AgentList{}, associationctl.AgentAssociationLabelNamespace, associationctl.AgentAssociationLabelName).
 		For(&emsv1alpha1.ElasticMapsServerList{}, associationctl.MapsESAssociationLabelNamespace, associationctl.MapsESAssociationLabelName).
+		For(&lsv1alpha1.LogstashList{}, associationctl.LogstashESAssociationLabelNamespace, associationctl.LogstashESAssociationLabelName).
 		DoGarbageCollection(ctx)
 	if err != nil {
 		return fmt.Errorf("user garbage collector failed: %w", err)
@@ -883,8 +1209,9 @@ func garbageCollectSoftOwnedSecrets(ctx context.Context, k8sClient k8s.Client) {
 		beatv1beta1.Kind:   &beatv1beta1.Beat{},
 		agentv1alpha1.Kind: &agentv1alpha1.Agent{},
 		emsv1alpha1.Kind:   &emsv1alpha1.ElasticMapsServer{},
+		lsv1alpha1.Kind:    &lsv1alpha1.Logstash{},
 	}); err != nil {
-		log.Error(err, "Orphan secrets garbage collection failed, will be attempted again at next operator restart.")
+		log.Error(err, "Orphan secrets garbage collection failed, will be attempted again at the next garbage collection.")
 		return
 	}
 	log.Info("Orphan secrets garbage collection complete")
@@ -899,7 +1226,11 @@ func setupWebhook(
 	managedNamespaces []string,
 	tracer *apm.Tracer) {
 	manageWebhookCerts := viper.GetBool(operator.ManageWebhookCertsFlag)
-	if manageWebhookCerts {
+	switch {
+	case manageWebhookCerts && params.DryRun.Enabled():
+		// the webhook certificates and configuration are written with a clientset which does not support dry-run
+		log.Info("Skipping the management of the webhook certificates in dry-run mode")
+	case manageWebhookCerts:
 		if err := reconcileWebhookCertsAndAddController(ctx, mgr, params.CertRotation, clientset, tracer); err != nil {
 			log.Error(err, "unable to setup the webhook certificates")
 			os.Exit(1)
@@ -923,10 +1254,12 @@ func setupWebhook(
 		&kbv1.Kibana{},
 		&kbv1beta1.Kibana{},
 		&emsv1alpha1.ElasticMapsServer{},
+		&lsv1alpha1.Logstash{},
 	}
 	for _, obj := range webhookObjects {
 		if err := commonwebhook.SetupValidatingWebhookWithConfig(&commonwebhook.Config{
 			Manager:          mgr,
+			Client:           mgr.GetClient(),
 			WebhookPath:      obj.WebhookPath(),
 			ManagedNamespace: managedNamespaces,
 			Validator:        obj,
@@ -938,7 +1271,7 @@ func setupWebhook(
 	}
 
 	// Elasticsearch and ElasticsearchAutoscaling validating webhooks are wired up differently, in order to access the k8s client
-	esvalidation.RegisterWebhook(mgr, params.ValidateStorageClass, exposedNodeLabels, checker, managedNamespaces)
+	esvalidation.RegisterWebhook(mgr, params.ValidateStorageClass, exposedNodeLabels, checker, managedNamespaces, params.MaxCertificateIssuances)
 	esavalidation.RegisterWebhook(mgr, params.ValidateStorageClass, checker, managedNamespaces)
 
 	// wait for the secret to be populated in the local filesystem before returning
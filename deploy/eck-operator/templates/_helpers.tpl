@@ -193,6 +193,31 @@ updating docs/operating-eck/eck-permissions.asciidoc file.
   - update
   - patch
   - delete
+- apiGroups:
+  - networking.k8s.io
+  resources:
+  - ingresses
+  verbs:
+  - get
+  - list
+  - watch
+  - create
+  - update
+  - patch
+  - delete
+- apiGroups:
+  - route.openshift.io
+  resources:
+  - routes
+  - routes/custom-host
+  verbs:
+  - get
+  - list
+  - watch
+  - create
+  - update
+  - patch
+  - delete
 - apiGroups:
   - elasticsearch.k8s.elastic.co
   resources:
@@ -297,6 +322,32 @@ updating docs/operating-eck/eck-permissions.asciidoc file.
   - create
   - update
   - patch
+- apiGroups:
+  - logstash.k8s.elastic.co
+  resources:
+  - logstashes
+  - logstashes/status
+  - logstashes/finalizers # needed for ownerReferences with blockOwnerDeletion on OCP
+  verbs:
+  - get
+  - list
+  - watch
+  - create
+  - update
+  - patch
+- apiGroups:
+  - stackconfigpolicy.k8s.elastic.co
+  resources:
+  - stackconfigpolicies
+  - stackconfigpolicies/status
+  - stackconfigpolicies/finalizers # needed for ownerReferences with blockOwnerDeletion on OCP
+  verbs:
+  - get
+  - list
+  - watch
+  - create
+  - update
+  - patch
 {{- end -}}
 
 {{/*
@@ -338,3 +389,33 @@ RBAC permissions to read node labels
   - list
   - watch
 {{- end -}}
+
+{{/*
+RBAC permissions to impersonate the ServiceAccounts used to mutate the resources of some namespaces
+*/}}
+{{- define "eck-operator.impersonateServiceAccountsRbacRule" -}}
+- apiGroups:
+  - ""
+  resources:
+  - serviceaccounts
+  resourceNames:
+  {{- range $namespace, $name := .Values.config.impersonatedServiceAccounts }}
+  - {{ $name }}
+  {{- end }}
+  verbs:
+  - impersonate
+{{- end -}}
+
+{{/*
+RBAC permissions to read namespaces
+*/}}
+{{- define "eck-operator.readNamespacesRbacRule" -}}
+- apiGroups:
+  - ""
+  resources:
+  - namespaces
+  verbs:
+  - get
+  - list
+  - watch
+{{- end -}}
@@ -103,6 +103,9 @@ const (
 	EntConfigAnnotationNameBase = "association.k8s.elastic.co/ent-conf"
 	EntAssociationType          = "ent"
 
+	EMSConfigAnnotationNameBase = "association.k8s.elastic.co/ems-conf"
+	EMSAssociationType          = "ems"
+
 	FleetServerConfigAnnotationNameBase = "association.k8s.elastic.co/fs-conf"
 	FleetServerAssociationType          = "fleetserver"
 
@@ -200,9 +203,11 @@ type AssociationConf struct {
 	AuthSecretName   string `json:"authSecretName"`
 	AuthSecretKey    string `json:"authSecretKey"`
 	IsServiceAccount bool   `json:"isServiceAccount"`
-	CACertProvided   bool   `json:"caCertProvided"`
-	CASecretName     string `json:"caSecretName"`
-	URL              string `json:"url"`
+	// IsAPIKey is true if the auth secret holds an API key, encoded as id:api_key, instead of a password.
+	IsAPIKey       bool   `json:"isApiKey,omitempty"`
+	CACertProvided bool   `json:"caCertProvided"`
+	CASecretName   string `json:"caSecretName"`
+	URL            string `json:"url"`
 	// Version of the referenced resource. If a version upgrade is in progress,
 	// matches the lowest running version. May be empty if unknown.
 	Version string `json:"version"`
@@ -187,6 +187,10 @@ type HTTPConfig struct {
 	Service ServiceTemplate `json:"service,omitempty"`
 	// TLS defines options for configuring TLS for HTTP.
 	TLS TLSOptions `json:"tls,omitempty"`
+	// Expose defines options for exposing the HTTP endpoint outside of the Kubernetes cluster through an Ingress
+	// or an OpenShift Route managed by the operator.
+	// +kubebuilder:validation:Optional
+	Expose *ExposeConfig `json:"expose,omitempty"`
 }
 
 // Protocol returns the inferrred protocol (http or https) for this configuration.
@@ -197,6 +201,67 @@ func (http HTTPConfig) Protocol() string {
 	return "http"
 }
 
+// ExposeType is the type of the resource exposing an HTTP endpoint outside of the Kubernetes cluster.
+type ExposeType string
+
+const (
+	// IngressExposeType exposes the HTTP endpoint through a networking.k8s.io/v1 Ingress.
+	IngressExposeType ExposeType = "Ingress"
+	// RouteExposeType exposes the HTTP endpoint through an OpenShift route.openshift.io/v1 Route.
+	RouteExposeType ExposeType = "Route"
+)
+
+// TLSTermination defines how TLS connections to an exposed HTTP endpoint are handled.
+type TLSTermination string
+
+const (
+	// PassthroughTLSTermination forwards the TLS connections to the Pods, which present their own certificate.
+	PassthroughTLSTermination TLSTermination = "Passthrough"
+	// ReencryptTLSTermination terminates the TLS connections at the Ingress controller or router, which opens
+	// new TLS connections to the Pods.
+	ReencryptTLSTermination TLSTermination = "Reencrypt"
+)
+
+// ExposeConfig holds the configuration of the Ingress or Route exposing an HTTP endpoint.
+type ExposeConfig struct {
+	// Type is the type of the resource exposing the HTTP endpoint, either Ingress or Route. Routes are only
+	// available on OpenShift. Defaults to Ingress.
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Enum=Ingress;Route
+	Type ExposeType `json:"type,omitempty"`
+
+	// Host is the fully qualified domain name the HTTP endpoint is exposed at.
+	// It is added to the subject alternative names of the self-signed HTTP certificate.
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:MinLength=1
+	Host string `json:"host"`
+
+	// IngressClassName is the name of the IngressClass of the Ingress. Ignored for Routes.
+	// +kubebuilder:validation:Optional
+	IngressClassName *string `json:"ingressClassName,omitempty"`
+
+	// TLSTermination defines how TLS connections are handled when TLS is enabled, either Passthrough or Reencrypt.
+	// Defaults to Passthrough.
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Enum=Passthrough;Reencrypt
+	TLSTermination TLSTermination `json:"tlsTermination,omitempty"`
+
+	// ObjectMeta is the metadata of the Ingress or Route, for example to set annotations specific to an Ingress controller.
+	// The name and namespace provided here are managed by ECK and will be ignored.
+	// +kubebuilder:validation:Optional
+	ObjectMeta metav1.ObjectMeta `json:"metadata,omitempty"`
+}
+
+// IsRoute returns true if the HTTP endpoint is exposed through an OpenShift Route.
+func (e ExposeConfig) IsRoute() bool {
+	return e.Type == RouteExposeType
+}
+
+// IsReencrypt returns true if TLS connections are terminated and re-encrypted before reaching the Pods.
+func (e ExposeConfig) IsReencrypt() bool {
+	return e.TLSTermination == ReencryptTLSTermination
+}
+
 // TLSOptions holds TLS configuration options.
 type TLSOptions struct {
 	// SelfSignedCertificate allows configuring the self-signed certificate generated by the operator.
@@ -101,7 +101,7 @@ func GetMLNodesSettings(as v1alpha1.AutoscalingPolicySpecs) (nodes int32, maxMem
 // getNodeSetRoles attempts to parse the roles specified in the configuration of a given nodeSet.
 func getNodeSetRoles(v version.Version, nodeSet NodeSet) ([]string, error) {
 	cfg := ElasticsearchSettings{}
-	if err := UnpackConfig(nodeSet.Config, v, &cfg); err != nil {
+	if err := UnpackNodeSetConfig(nodeSet, v, &cfg); err != nil {
 		return nil, err
 	}
 	if cfg.Node == nil {
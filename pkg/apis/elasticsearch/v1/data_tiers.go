@@ -0,0 +1,78 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package v1
+
+import (
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/version"
+)
+
+// DataTier is the name of an Elasticsearch data tier.
+type DataTier string
+
+const (
+	DataTierHot    DataTier = "hot"
+	DataTierWarm   DataTier = "warm"
+	DataTierCold   DataTier = "cold"
+	DataTierFrozen DataTier = "frozen"
+
+	// DataTierAttr is the name of the node attribute holding the data tier of a node, it can be used in allocation
+	// filtering rules.
+	DataTierAttr     = "data"
+	NodeAttrDataTier = NodeAttr + "." + DataTierAttr
+)
+
+// DataTiers lists the supported data tiers, from the hottest to the coldest one.
+var DataTiers = []DataTier{DataTierHot, DataTierWarm, DataTierCold, DataTierFrozen}
+
+// IsDataTier returns true if the given name is the name of a supported data tier.
+func IsDataTier(name string) bool {
+	for _, tier := range DataTiers {
+		if string(tier) == name {
+			return true
+		}
+	}
+	return false
+}
+
+// Role returns the node role of the data tier.
+func (t DataTier) Role() NodeRole {
+	return NodeRole("data_" + string(t))
+}
+
+// Roles returns the node roles a node belonging to the data tier is configured with when node.roles is not set.
+// Nodes of the hot tier also hold the content tier, which is where indices that are not part of a data stream are
+// allocated by default.
+func (t DataTier) Roles() []string {
+	if t == DataTierHot {
+		return []string{string(DataHotRole), string(DataContentRole)}
+	}
+	return []string{string(t.Role())}
+}
+
+// MinVersion returns the first Elasticsearch version supporting the data tier.
+func (t DataTier) MinVersion() version.Version {
+	if t == DataTierFrozen {
+		return version.From(7, 12, 0)
+	}
+	return version.From(7, 10, 0)
+}
+
+// UnpackNodeSetConfig unpacks the configuration of a NodeSet into a typed subset. If the NodeSet belongs to a data tier
+// and node.roles is not set in its configuration, the node roles of the data tier are used.
+func UnpackNodeSetConfig(nodeSet NodeSet, ver version.Version, out *ElasticsearchSettings) error {
+	if err := UnpackConfig(nodeSet.Config, ver, out); err != nil {
+		return err
+	}
+	if nodeSet.DataTier == "" {
+		return nil
+	}
+	if out.Node == nil {
+		out.Node = &Node{}
+	}
+	if out.Node.Roles == nil {
+		out.Node.Roles = nodeSet.DataTier.Roles()
+	}
+	return nil
+}
@@ -10,6 +10,7 @@ import (
 
 	"github.com/blang/semver/v4"
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
 	commonv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/common/v1"
@@ -35,6 +36,29 @@ const (
 	DisableUpgradePredicatesAnnotation = "eck.k8s.elastic.co/disable-upgrade-predicates"
 	// DownwardNodeLabelsAnnotation holds an optional list of expected node labels to be set as annotations on the Elasticsearch Pods.
 	DownwardNodeLabelsAnnotation = "eck.k8s.elastic.co/downward-node-labels"
+	// LocalVolumesAnnotation indicates, when set to "true", that the data of the Elasticsearch nodes is stored on
+	// PersistentVolumes bound to a single Kubernetes node, such as local or hostPath PersistentVolumes. The operator then
+	// avoids restarting Pods which could not be scheduled again on the Kubernetes node holding their data.
+	LocalVolumesAnnotation = "eck.k8s.elastic.co/local-volumes"
+	// ReloadSecureSettingsAnnotation enables, when set to "true", the reload of the secure settings without restarting the
+	// Elasticsearch Pods when only reloadable secure settings change. A sidecar container then keeps the keystore of each
+	// node up to date with the secure settings.
+	ReloadSecureSettingsAnnotation = "eck.k8s.elastic.co/reload-secure-settings"
+	// SecureSettingsReloadAnnotation is set by the operator to record the state of the reload of the secure settings, to
+	// reload them once per version of the secure settings secrets.
+	SecureSettingsReloadAnnotation = "elasticsearch.k8s.elastic.co/secure-settings-reload"
+	// SafeToEvictAnnotation holds the value of the cluster-autoscaler.kubernetes.io/safe-to-evict annotation to set on all
+	// the Elasticsearch Pods, either "true" or "false". By default, only master and data Pods are annotated to prevent the
+	// cluster autoscaler from evicting them.
+	SafeToEvictAnnotation = "eck.k8s.elastic.co/safe-to-evict"
+	// StorageMigrationAnnotation allows, when set to "true", changes to the volume claim templates of a NodeSet which
+	// cannot be applied in place, such as changing the storage class or decreasing the storage size. The operator then
+	// replaces the StatefulSet of the NodeSet with a new one, and migrates the data away from the existing nodes before
+	// removing them.
+	StorageMigrationAnnotation = "eck.k8s.elastic.co/storage-migration"
+	// NodeSetStatefulSetsAnnotation is set by the operator to record, as a JSON object, the names of the StatefulSets
+	// replacing the original StatefulSets of the NodeSets whose storage was migrated, by NodeSet name.
+	NodeSetStatefulSetsAnnotation = "eck.k8s.elastic.co/nodeset-statefulsets"
 	// SuspendAnnotation allows users to annotate the Elasticsearch resource with the names of Pods they want to suspend
 	// for debugging purposes.
 	SuspendAnnotation = "eck.k8s.elastic.co/suspend"
@@ -107,10 +131,76 @@ type ElasticsearchSpec struct {
 	// +kubebuilder:validation:Optional
 	Auth Auth `json:"auth,omitempty"`
 
+	// AuditLogging enables the security audit log of Elasticsearch, written next to the other logs of the nodes.
+	// The audit events are shipped to the monitoring clusters along with the other logs if spec.monitoring.logs is set.
+	// +kubebuilder:validation:Optional
+	AuditLogging *AuditLogging `json:"auditLogging,omitempty"`
+
 	// SecureSettings is a list of references to Kubernetes secrets containing sensitive configuration options for Elasticsearch.
 	// +kubebuilder:validation:Optional
 	SecureSettings []commonv1.SecretSource `json:"secureSettings,omitempty"`
 
+	// Plugins is a list of Elasticsearch plugins installed on the nodes before Elasticsearch starts.
+	// +kubebuilder:validation:Optional
+	Plugins []Plugin `json:"plugins,omitempty"`
+
+	// SetVMMaxMapCount adds a privileged init container to the Elasticsearch Pods, which raises the vm.max_map_count
+	// kernel setting of the Kubernetes nodes to the value required by Elasticsearch. Set it to false if the Kubernetes
+	// nodes are already configured or if privileged containers are not allowed.
+	// Defaults to the set-vm-max-map-count setting of the operator.
+	// +kubebuilder:validation:Optional
+	SetVMMaxMapCount *bool `json:"setVmMaxMapCount,omitempty"`
+
+	// SnapshotVolumes are shared filesystem volumes mounted on all the Elasticsearch nodes, and registered in the path.repo
+	// setting to be used as locations of shared file system snapshot repositories.
+	// +kubebuilder:validation:Optional
+	SnapshotVolumes []SnapshotVolume `json:"snapshotVolumes,omitempty"`
+
+	// SnapshotRepositories are the snapshot repositories registered in Elasticsearch by the operator.
+	// +kubebuilder:validation:Optional
+	SnapshotRepositories []SnapshotRepository `json:"snapshotRepositories,omitempty"`
+
+	// UpgradeSnapshot requires a successful snapshot of all the indices before the operator starts upgrading the version
+	// of the Elasticsearch nodes. The upgrade is not started if the snapshot fails.
+	// +kubebuilder:validation:Optional
+	UpgradeSnapshot *UpgradeSnapshot `json:"upgradeSnapshot,omitempty"`
+
+	// InitialRestore restores a snapshot in the cluster once it is formed, for example to clone an existing cluster.
+	// The cluster is not reported as ready until the restore is complete. It can only be set at creation.
+	// +kubebuilder:validation:Optional
+	InitialRestore *InitialRestore `json:"initialRestore,omitempty"`
+
+	// RestoreVerification periodically restores the latest snapshot of a repository in a temporary single-node cluster,
+	// to verify that the snapshots can be restored. The temporary cluster is deleted once the verification completes.
+	// +kubebuilder:validation:Optional
+	RestoreVerification *RestoreVerification `json:"restoreVerification,omitempty"`
+
+	// IngestPipelines are the ingest pipelines created in Elasticsearch by the operator. Pipelines which already exist in
+	// Elasticsearch and were not created by the operator are reported as conflicts and left untouched.
+	// +kubebuilder:validation:Optional
+	IngestPipelines []IngestPipeline `json:"ingestPipelines,omitempty"`
+
+	// ClusterSettings are persistent cluster settings applied by the operator through the Elasticsearch API. Settings
+	// changed through the Elasticsearch API are reverted or reported depending on the conflict policy.
+	// +kubebuilder:validation:Optional
+	ClusterSettings *ManagedClusterSettings `json:"clusterSettings,omitempty"`
+
+	// IndexTemplates are the index templates created in Elasticsearch by the operator. Templates which already exist in
+	// Elasticsearch and were not created by the operator are reported as conflicts and left untouched.
+	// +kubebuilder:validation:Optional
+	// ComponentTemplates are the component templates created in Elasticsearch by the operator, to be composed by index
+	// templates. Templates which already exist in Elasticsearch and were not created by the operator are reported as
+	// conflicts and left untouched.
+	// +kubebuilder:validation:Optional
+	ComponentTemplates []ComponentTemplate `json:"componentTemplates,omitempty"`
+
+	IndexTemplates []IndexTemplate `json:"indexTemplates,omitempty"`
+
+	// ILMPolicies are the index lifecycle management policies created in Elasticsearch by the operator. Policies which
+	// already exist in Elasticsearch and were not created by the operator are reported as conflicts and left untouched.
+	// +kubebuilder:validation:Optional
+	ILMPolicies []ILMPolicy `json:"ilmPolicies,omitempty"`
+
 	// ServiceAccountName is used to check access from the current resource to a resource (for ex. a remote Elasticsearch cluster) in a different namespace.
 	// Can only be used if ECK is enforcing RBAC on references.
 	// +optional
@@ -178,6 +268,387 @@ func (tto TransportTLSOptions) UserDefinedCA() bool {
 	return tto.Certificate.SecretName != ""
 }
 
+// Plugin declares an Elasticsearch plugin to install on the nodes.
+type Plugin struct {
+	// Name is either the name of an official Elasticsearch plugin, or the URL of a plugin archive.
+	// Archives made available in a volume mounted in the Pods can be installed with a file:// URL, for example in
+	// air-gapped environments.
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:MinLength=1
+	Name string `json:"name"`
+
+	// SHA512 is the SHA-512 checksum of the plugin archive, verified before installing the plugin.
+	// Only supported for plugins installed from a URL.
+	// +kubebuilder:validation:Optional
+	SHA512 string `json:"sha512,omitempty"`
+}
+
+// IsURL returns true if the plugin is installed from a URL rather than by its official name.
+func (p Plugin) IsURL() bool {
+	return strings.Contains(p.Name, "://")
+}
+
+// SnapshotVolume declares a shared filesystem volume mounted on all the Elasticsearch nodes to store snapshots.
+// Exactly one of ClaimName and NFS must be set.
+type SnapshotVolume struct {
+	// Name of the volume, unique within the cluster. The volume is mounted in /usr/share/elasticsearch/snapshots/<name>,
+	// which is the location to use in the settings of a shared file system snapshot repository.
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:MinLength=1
+	// +kubebuilder:validation:MaxLength=53
+	// +kubebuilder:validation:Pattern=`^[a-z0-9]([-a-z0-9]*[a-z0-9])?$`
+	Name string `json:"name"`
+
+	// ClaimName is the name of a PersistentVolumeClaim in the namespace of the Elasticsearch cluster. The claim must
+	// support the ReadWriteMany access mode since it is mounted on all the Elasticsearch nodes.
+	// +kubebuilder:validation:Optional
+	ClaimName string `json:"claimName,omitempty"`
+
+	// NFS is an NFS export mounted on all the Elasticsearch nodes.
+	// +kubebuilder:validation:Optional
+	NFS *corev1.NFSVolumeSource `json:"nfs,omitempty"`
+}
+
+// SnapshotRepositoryType is the type of a snapshot repository.
+type SnapshotRepositoryType string
+
+const (
+	S3SnapshotRepository    SnapshotRepositoryType = "s3"
+	GCSSnapshotRepository   SnapshotRepositoryType = "gcs"
+	AzureSnapshotRepository SnapshotRepositoryType = "azure"
+	FSSnapshotRepository    SnapshotRepositoryType = "fs"
+)
+
+// SnapshotRepository declares a snapshot repository registered in Elasticsearch.
+type SnapshotRepository struct {
+	// Name of the repository in Elasticsearch.
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:MinLength=1
+	Name string `json:"name"`
+
+	// Type of the repository: s3, gcs, azure or fs. Before Elasticsearch 8.0, the s3, gcs and azure repository types
+	// require the installation of the corresponding plugin.
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:Enum=s3;gcs;azure;fs
+	Type SnapshotRepositoryType `json:"type"`
+
+	// Settings of the repository, such as the bucket or the location of the snapshots, as described in the
+	// Elasticsearch documentation of each repository type.
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:pruning:PreserveUnknownFields
+	Settings *commonv1.Config `json:"settings,omitempty"`
+
+	// SecureSettings is a list of references to Kubernetes secrets containing the credentials of the repository, which
+	// are added to the Elasticsearch keystore. Keys must be named after the client settings of the repository type,
+	// for example s3.client.default.access_key.
+	// +kubebuilder:validation:Optional
+	SecureSettings []commonv1.SecretSource `json:"secureSettings,omitempty"`
+
+	// Schedule of the snapshots taken in the repository. If Elasticsearch supports snapshot lifecycle management
+	// with retention, the schedule is configured as an SLM policy. Otherwise the operator takes the snapshots itself.
+	// +kubebuilder:validation:Optional
+	Schedule *SnapshotSchedule `json:"schedule,omitempty"`
+}
+
+// SnapshotSchedule declares when snapshots are taken in a snapshot repository, and how long they are kept.
+type SnapshotSchedule struct {
+	// Cron is the schedule of the snapshots, in the cron syntax of Elasticsearch which starts with a seconds field,
+	// for example "0 30 1 * * ?" for every day at 1:30 AM UTC.
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:MinLength=1
+	Cron string `json:"cron"`
+
+	// Indices to include in the snapshots. Defaults to all the indices and data streams.
+	// +kubebuilder:validation:Optional
+	Indices []string `json:"indices,omitempty"`
+
+	// Retention of the snapshots taken by the schedule.
+	// +kubebuilder:validation:Optional
+	Retention *SnapshotRetention `json:"retention,omitempty"`
+}
+
+// SnapshotRetention declares which snapshots taken by a schedule are deleted.
+type SnapshotRetention struct {
+	// ExpireAfter is the time after which a snapshot is deleted, in the time units of Elasticsearch, for example 30d.
+	// +kubebuilder:validation:Optional
+	ExpireAfter string `json:"expireAfter,omitempty"`
+
+	// MinCount is the minimum number of snapshots to keep, even if they are expired.
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Minimum=1
+	MinCount *int32 `json:"minCount,omitempty"`
+
+	// MaxCount is the maximum number of snapshots to keep, even if they are not expired.
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Minimum=1
+	MaxCount *int32 `json:"maxCount,omitempty"`
+}
+
+// UpgradeSnapshot declares the snapshot taken before a version upgrade.
+type UpgradeSnapshot struct {
+	// Repository in which the snapshot is taken. The repository must be registered in Elasticsearch, for example by
+	// declaring it in snapshotRepositories.
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:MinLength=1
+	Repository string `json:"repository"`
+}
+
+// InitialRestore declares the snapshot restored in a new cluster.
+type InitialRestore struct {
+	// Repository containing the snapshot. The repository must be registered in Elasticsearch, for example by declaring
+	// it in snapshotRepositories.
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:MinLength=1
+	Repository string `json:"repository"`
+
+	// Snapshot to restore.
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:MinLength=1
+	Snapshot string `json:"snapshot"`
+
+	// Indices to restore. Defaults to all the indices and data streams of the snapshot.
+	// +kubebuilder:validation:Optional
+	Indices []string `json:"indices,omitempty"`
+}
+
+// RestoreVerification declares the periodic verification of the snapshots of a repository.
+type RestoreVerification struct {
+	// Repository whose latest successful snapshot is restored. The repository must be declared in snapshotRepositories.
+	// It is registered as read-only in the verification cluster.
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:MinLength=1
+	Repository string `json:"repository"`
+
+	// Cron is the schedule of the verifications, in the cron syntax of Elasticsearch which starts with a seconds field,
+	// for example "0 0 3 ? * SUN" for every Sunday at 3 AM UTC.
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:MinLength=1
+	Cron string `json:"cron"`
+
+	// Indices to restore. Defaults to all the indices and data streams of the snapshot.
+	// +kubebuilder:validation:Optional
+	Indices []string `json:"indices,omitempty"`
+
+	// MinDocumentCount is the minimum number of documents the restored indices must hold for the verification to
+	// succeed. Defaults to 1.
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Minimum=0
+	MinDocumentCount *int64 `json:"minDocumentCount,omitempty"`
+
+	// PodTemplate provides customisation options (labels, annotations, affinity rules, resource requests, and so on)
+	// for the Pod of the verification cluster. Its data is stored in an emptyDir volume.
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:pruning:PreserveUnknownFields
+	PodTemplate corev1.PodTemplateSpec `json:"podTemplate,omitempty"`
+}
+
+// IngestPipelineConfigMapKey is the entry of the ConfigMaps holding the definition of an ingest pipeline.
+const IngestPipelineConfigMapKey = "pipeline.json"
+
+// IngestPipeline declares an ingest pipeline created in Elasticsearch.
+// Exactly one of Definition and ConfigMapName must be set.
+type IngestPipeline struct {
+	// Name of the pipeline in Elasticsearch.
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:MinLength=1
+	Name string `json:"name"`
+
+	// Definition of the pipeline, with its description and processors, as expected by the Elasticsearch ingest API.
+	// See: https://www.elastic.co/guide/en/elasticsearch/reference/current/put-pipeline-api.html
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:pruning:PreserveUnknownFields
+	Definition *commonv1.Config `json:"definition,omitempty"`
+
+	// ConfigMapName references a ConfigMap in the same namespace as the Elasticsearch resource, holding the JSON
+	// definition of the pipeline under a "pipeline.json" entry.
+	// +kubebuilder:validation:Optional
+	ConfigMapName string `json:"configMapName,omitempty"`
+}
+
+// ComponentTemplateConfigMapKey is the key of the ConfigMap entry holding the definition of a component template.
+const ComponentTemplateConfigMapKey = "component_template.json"
+
+// ComponentTemplate declares a component template created in Elasticsearch, available from Elasticsearch 7.8.0.
+// Exactly one of Definition and ConfigMapName must be set.
+type ComponentTemplate struct {
+	// Name of the component template in Elasticsearch.
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:MinLength=1
+	Name string `json:"name"`
+
+	// Definition of the component template, with the settings, mappings and aliases it provides to the index templates
+	// composed of it, as expected by the Elasticsearch component templates API.
+	// See: https://www.elastic.co/guide/en/elasticsearch/reference/current/indices-component-template.html
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:pruning:PreserveUnknownFields
+	Definition *commonv1.Config `json:"definition,omitempty"`
+
+	// ConfigMapName references a ConfigMap in the same namespace as the Elasticsearch resource, holding the JSON
+	// definition of the component template under a "component_template.json" entry.
+	// +kubebuilder:validation:Optional
+	ConfigMapName string `json:"configMapName,omitempty"`
+}
+
+// IndexTemplateConfigMapKey is the key of the ConfigMap entry holding the definition of an index template.
+const IndexTemplateConfigMapKey = "template.json"
+
+// IndexTemplateType is the type of an index template.
+type IndexTemplateType string
+
+const (
+	// ComposableIndexTemplateType is the type of the composable index templates, introduced in Elasticsearch 7.8.0.
+	ComposableIndexTemplateType IndexTemplateType = "Composable"
+	// LegacyIndexTemplateType is the type of the legacy index templates, deprecated in Elasticsearch 7.8.0.
+	LegacyIndexTemplateType IndexTemplateType = "Legacy"
+)
+
+// IndexTemplate declares an index template created in Elasticsearch.
+// Exactly one of Definition and ConfigMapName must be set.
+type IndexTemplate struct {
+	// Name of the template in Elasticsearch.
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:MinLength=1
+	Name string `json:"name"`
+
+	// Type of the template. Possible values are Composable and Legacy. Defaults to Composable.
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Enum=Composable;Legacy
+	Type IndexTemplateType `json:"type,omitempty"`
+
+	// Definition of the template, with its index patterns, settings and mappings, as expected by the Elasticsearch
+	// index templates API.
+	// See: https://www.elastic.co/guide/en/elasticsearch/reference/current/index-templates.html
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:pruning:PreserveUnknownFields
+	Definition *commonv1.Config `json:"definition,omitempty"`
+
+	// ConfigMapName references a ConfigMap in the same namespace as the Elasticsearch resource, holding the JSON
+	// definition of the template under a "template.json" entry.
+	// +kubebuilder:validation:Optional
+	ConfigMapName string `json:"configMapName,omitempty"`
+}
+
+// TypeOrDefault returns the type of the template, Composable by default.
+func (t IndexTemplate) TypeOrDefault() IndexTemplateType {
+	if t.Type == "" {
+		return ComposableIndexTemplateType
+	}
+	return t.Type
+}
+
+// ILMPolicyConfigMapKey is the key of the ConfigMap entry holding the definition of an ILM policy.
+const ILMPolicyConfigMapKey = "policy.json"
+
+// ILMPolicy declares an index lifecycle management policy created in Elasticsearch.
+// Exactly one of Definition and ConfigMapName must be set.
+type ILMPolicy struct {
+	// Name of the policy in Elasticsearch.
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:MinLength=1
+	Name string `json:"name"`
+
+	// Definition of the policy, with its phases, as expected under "policy" by the Elasticsearch ILM API.
+	// See: https://www.elastic.co/guide/en/elasticsearch/reference/current/ilm-put-lifecycle.html
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:pruning:PreserveUnknownFields
+	Definition *commonv1.Config `json:"definition,omitempty"`
+
+	// ConfigMapName references a ConfigMap in the same namespace as the Elasticsearch resource, holding the JSON
+	// definition of the policy under a "policy.json" entry.
+	// +kubebuilder:validation:Optional
+	ConfigMapName string `json:"configMapName,omitempty"`
+}
+
+// ClusterSettingsConflictPolicy describes how the operator handles the declared cluster settings changed through the
+// Elasticsearch API.
+type ClusterSettingsConflictPolicy string
+
+const (
+	// RevertClusterSettingsConflictPolicy reverts the changed settings to their declared value.
+	RevertClusterSettingsConflictPolicy ClusterSettingsConflictPolicy = "Revert"
+	// ReportClusterSettingsConflictPolicy reports the changed settings through events, and leaves them untouched.
+	ReportClusterSettingsConflictPolicy ClusterSettingsConflictPolicy = "Report"
+)
+
+// ManagedClusterSettings declares persistent cluster settings applied through the Elasticsearch API.
+type ManagedClusterSettings struct {
+	// Persistent are the persistent cluster settings, either flattened or nested.
+	// See: https://www.elastic.co/guide/en/elasticsearch/reference/current/cluster-update-settings.html
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:pruning:PreserveUnknownFields
+	Persistent *commonv1.Config `json:"persistent,omitempty"`
+
+	// ConflictPolicy defines how the settings changed through the Elasticsearch API are handled.
+	// Possible values are Revert and Report. Defaults to Revert.
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Enum=Revert;Report
+	ConflictPolicy ClusterSettingsConflictPolicy `json:"conflictPolicy,omitempty"`
+}
+
+// ConflictPolicyOrDefault returns the conflict policy, Revert by default.
+func (cs ManagedClusterSettings) ConflictPolicyOrDefault() ClusterSettingsConflictPolicy {
+	if cs.ConflictPolicy == "" {
+		return RevertClusterSettingsConflictPolicy
+	}
+	return cs.ConflictPolicy
+}
+
+// AuditLogging declares the security audit log of Elasticsearch.
+type AuditLogging struct {
+	// IncludeEvents are the types of the events written to the audit log, for example access_denied or
+	// authentication_failed. Defaults to the default event types of Elasticsearch.
+	// +kubebuilder:validation:Optional
+	IncludeEvents []string `json:"includeEvents,omitempty"`
+
+	// ExcludeEvents are the types of the events excluded from the audit log.
+	// +kubebuilder:validation:Optional
+	ExcludeEvents []string `json:"excludeEvents,omitempty"`
+
+	// EmitRequestBody includes the body of the REST requests in the audit events. The body may contain sensitive data.
+	// +kubebuilder:validation:Optional
+	EmitRequestBody bool `json:"emitRequestBody,omitempty"`
+
+	// IgnoreFilters are policies excluding events from the audit log.
+	// +kubebuilder:validation:Optional
+	IgnoreFilters []AuditIgnoreFilter `json:"ignoreFilters,omitempty"`
+}
+
+// AuditIgnoreFilter is a policy excluding from the audit log the events which match all its users, realms, roles and
+// indices. An unset list matches all the events.
+type AuditIgnoreFilter struct {
+	// Name of the policy.
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:MinLength=1
+	Name string `json:"name"`
+
+	// Users are the names or wildcards of the users whose events are ignored.
+	// +kubebuilder:validation:Optional
+	Users []string `json:"users,omitempty"`
+
+	// Realms are the names or wildcards of the authentication realms whose events are ignored.
+	// +kubebuilder:validation:Optional
+	Realms []string `json:"realms,omitempty"`
+
+	// Roles are the names or wildcards of the roles whose events are ignored.
+	// +kubebuilder:validation:Optional
+	Roles []string `json:"roles,omitempty"`
+
+	// Indices are the names or wildcards of the indices whose events are ignored.
+	// +kubebuilder:validation:Optional
+	Indices []string `json:"indices,omitempty"`
+}
+
+// DefaultRestoreVerificationMinDocumentCount is the default minimum number of documents of a restore verification.
+const DefaultRestoreVerificationMinDocumentCount int64 = 1
+
+// MinDocumentCountOrDefault returns the minimum number of documents the restored indices must hold.
+func (rv RestoreVerification) MinDocumentCountOrDefault() int64 {
+	if rv.MinDocumentCount == nil {
+		return DefaultRestoreVerificationMinDocumentCount
+	}
+	return *rv.MinDocumentCount
+}
+
 // RemoteCluster declares a remote Elasticsearch cluster connection.
 type RemoteCluster struct {
 	// Name is the name of the remote cluster as it is set in the Elasticsearch settings.
@@ -213,12 +684,312 @@ func (es ElasticsearchSpec) VolumeClaimDeletePolicyOrDefault() VolumeClaimDelete
 	return es.VolumeClaimDeletePolicy
 }
 
+// SetVMMaxMapCountOrDefault returns true if the vm.max_map_count init container should be added to the Pods, falling
+// back to the given operator default if not specified.
+func (es ElasticsearchSpec) SetVMMaxMapCountOrDefault(operatorDefault bool) bool {
+	if es.SetVMMaxMapCount == nil {
+		return operatorDefault
+	}
+	return *es.SetVMMaxMapCount
+}
+
 // Auth contains user authentication and authorization security settings for Elasticsearch.
 type Auth struct {
 	// Roles to propagate to the Elasticsearch cluster.
 	Roles []RoleSource `json:"roles,omitempty"`
 	// FileRealm to propagate to the Elasticsearch cluster.
 	FileRealm []FileRealmSource `json:"fileRealm,omitempty"`
+	// Users of the native realm to create in the Elasticsearch cluster through the security API. Users removed from
+	// this list are deleted from the cluster.
+	// +kubebuilder:validation:Optional
+	Users []NativeUser `json:"users,omitempty"`
+	// SAML realms to configure in the Elasticsearch cluster. The Kibana instances associated with the cluster are
+	// configured with a matching SAML authentication provider.
+	// +kubebuilder:validation:Optional
+	SAML []SAMLRealm `json:"saml,omitempty"`
+	// OIDC realms to configure in the Elasticsearch cluster. The Kibana instances associated with the cluster are
+	// configured with a matching OpenID Connect authentication provider.
+	// +kubebuilder:validation:Optional
+	OIDC []OIDCRealm `json:"oidc,omitempty"`
+	// LDAP realms to configure in the Elasticsearch cluster, connecting to LDAP or Active Directory servers.
+	// +kubebuilder:validation:Optional
+	LDAP []LDAPRealm `json:"ldap,omitempty"`
+}
+
+// SAMLRealm is a SAML realm configured by the operator in the Elasticsearch configuration.
+type SAMLRealm struct {
+	// Name of the realm, unique within the cluster.
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:MinLength=1
+	// +kubebuilder:validation:MaxLength=50
+	// +kubebuilder:validation:Pattern=`^[a-z0-9]([-a-z0-9]*[a-z0-9])?$`
+	Name string `json:"name"`
+	// Order of the realm in the realm chain, unique across the realms of the cluster. The file and native realms managed by
+	// the operator come first.
+	// +kubebuilder:validation:Optional
+	Order int32 `json:"order,omitempty"`
+	// IdP is the SAML identity provider.
+	IdP SAMLIdentityProvider `json:"idp"`
+	// SP is the SAML service provider, usually Kibana.
+	SP SAMLServiceProvider `json:"sp"`
+	// Attributes maps the SAML attributes of the identity provider to the user properties in Elasticsearch.
+	Attributes SAMLAttributes `json:"attributes"`
+	// SigningSecretName references a Kubernetes secret in the same namespace as the Elasticsearch resource, holding the
+	// certificate and the private key used to sign the SAML messages under the "tls.crt" and "tls.key" entries. The
+	// passphrase of the key can be set in the secure settings with the
+	// xpack.security.authc.realms.saml.<name>.signing.secure_key_passphrase setting.
+	// +kubebuilder:validation:Optional
+	SigningSecretName string `json:"signingSecretName,omitempty"`
+}
+
+// SAMLIdentityProvider describes the SAML identity provider of a SAML realm.
+// Exactly one of MetadataConfigMapName and MetadataURL must be set.
+type SAMLIdentityProvider struct {
+	// EntityID is the SAML entity ID of the identity provider.
+	// +kubebuilder:validation:MinLength=1
+	EntityID string `json:"entityID"`
+	// MetadataConfigMapName references a ConfigMap in the same namespace as the Elasticsearch resource, holding the SAML
+	// metadata of the identity provider under a "metadata.xml" entry.
+	// +kubebuilder:validation:Optional
+	MetadataConfigMapName string `json:"metadataConfigMapName,omitempty"`
+	// MetadataURL is the HTTPS URL from which Elasticsearch downloads the SAML metadata of the identity provider.
+	// +kubebuilder:validation:Optional
+	MetadataURL string `json:"metadataURL,omitempty"`
+}
+
+// SAMLServiceProvider describes the SAML service provider of a SAML realm.
+type SAMLServiceProvider struct {
+	// EntityID is the SAML entity ID of the service provider, usually the public URL of Kibana.
+	// +kubebuilder:validation:MinLength=1
+	EntityID string `json:"entityID"`
+	// ACS is the URL of the assertion consumer service, usually <Kibana public URL>/api/security/saml/callback.
+	// +kubebuilder:validation:MinLength=1
+	ACS string `json:"acs"`
+	// Logout is the URL of the single logout service, usually <Kibana public URL>/logout.
+	// +kubebuilder:validation:Optional
+	Logout string `json:"logout,omitempty"`
+}
+
+// SAMLAttributes maps the SAML attributes of the identity provider to the user properties in Elasticsearch.
+type SAMLAttributes struct {
+	// Principal is the name of the SAML attribute holding the username.
+	// +kubebuilder:validation:MinLength=1
+	Principal string `json:"principal"`
+	// Groups is the name of the SAML attribute holding the groups of the user, used in role mappings.
+	// +kubebuilder:validation:Optional
+	Groups string `json:"groups,omitempty"`
+	// Name is the name of the SAML attribute holding the full name of the user.
+	// +kubebuilder:validation:Optional
+	Name string `json:"name,omitempty"`
+	// Mail is the name of the SAML attribute holding the email address of the user.
+	// +kubebuilder:validation:Optional
+	Mail string `json:"mail,omitempty"`
+}
+
+// ReservedUserNames are the names of the users which cannot be declared as native users: the built-in users of
+// Elasticsearch, and the users created by the operator.
+var ReservedUserNames = set.Make(
+	"elastic",
+	"elastic-internal",
+	"elastic-internal-probe",
+	"elastic-internal-monitoring",
+	"kibana",
+	"kibana_system",
+	"logstash_system",
+	"beats_system",
+	"apm_system",
+	"remote_monitoring_user",
+)
+
+// NativeUser is a user of the native realm managed by the operator.
+type NativeUser struct {
+	// Name of the user.
+	// +kubebuilder:validation:MinLength=1
+	Name string `json:"name"`
+	// PasswordSecretRef references a Kubernetes secret in the same namespace as the Elasticsearch resource, holding the
+	// password of the user under a "password" entry. The password of the user is updated when the secret changes.
+	PasswordSecretRef commonv1.SecretRef `json:"passwordSecretRef"`
+	// Roles of the user.
+	// +kubebuilder:validation:Optional
+	Roles []string `json:"roles,omitempty"`
+	// FullName of the user.
+	// +kubebuilder:validation:Optional
+	FullName string `json:"fullName,omitempty"`
+	// Email of the user.
+	// +kubebuilder:validation:Optional
+	Email string `json:"email,omitempty"`
+}
+
+// OIDCRealm is an OpenID Connect realm configured by the operator in the Elasticsearch configuration.
+type OIDCRealm struct {
+	// Name of the realm, unique within the cluster.
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:MinLength=1
+	// +kubebuilder:validation:MaxLength=50
+	// +kubebuilder:validation:Pattern=`^[a-z0-9]([-a-z0-9]*[a-z0-9])?$`
+	Name string `json:"name"`
+	// Order of the realm in the realm chain, unique across the realms of the cluster. The file and native realms managed by
+	// the operator come first.
+	// +kubebuilder:validation:Optional
+	Order int32 `json:"order,omitempty"`
+	// RP is the OpenID Connect relying party, usually Kibana.
+	RP OIDCRelyingParty `json:"rp"`
+	// OP is the OpenID Connect provider.
+	OP OIDCProvider `json:"op"`
+	// Claims maps the claims of the OpenID Connect provider to the user properties in Elasticsearch.
+	Claims OIDCClaims `json:"claims"`
+}
+
+// OIDCRelyingParty describes the OpenID Connect relying party of an OIDC realm.
+type OIDCRelyingParty struct {
+	// ClientID is the OAuth 2.0 client identifier of the relying party, registered in the OpenID Connect provider.
+	// +kubebuilder:validation:MinLength=1
+	ClientID string `json:"clientID"`
+	// ClientSecretRef references a Kubernetes secret in the same namespace as the Elasticsearch resource, holding the
+	// client secret of the relying party under a "client_secret" entry. The client secret is added to the keystore.
+	ClientSecretRef commonv1.SecretRef `json:"clientSecretRef"`
+	// RedirectURI is the URL to which the OpenID Connect provider redirects the browser after authentication, usually
+	// <Kibana public URL>/api/security/oidc/callback.
+	// +kubebuilder:validation:MinLength=1
+	RedirectURI string `json:"redirectURI"`
+	// PostLogoutRedirectURI is the URL to which the OpenID Connect provider redirects the browser after logout,
+	// usually <Kibana public URL>/security/logged_out.
+	// +kubebuilder:validation:Optional
+	PostLogoutRedirectURI string `json:"postLogoutRedirectURI,omitempty"`
+	// ResponseType is the OAuth 2.0 response type, "code" for the authorization code flow or "id_token" for the
+	// implicit flow. Defaults to "code".
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Enum=code;id_token
+	ResponseType string `json:"responseType,omitempty"`
+	// RequestedScopes are the scopes requested in addition to the openid scope.
+	// +kubebuilder:validation:Optional
+	RequestedScopes []string `json:"requestedScopes,omitempty"`
+}
+
+// OIDCProvider describes the OpenID Connect provider of an OIDC realm.
+type OIDCProvider struct {
+	// Issuer is the issuer identifier of the OpenID Connect provider.
+	// +kubebuilder:validation:MinLength=1
+	Issuer string `json:"issuer"`
+	// AuthorizationEndpoint is the URL of the authorization endpoint of the OpenID Connect provider.
+	// +kubebuilder:validation:MinLength=1
+	AuthorizationEndpoint string `json:"authorizationEndpoint"`
+	// TokenEndpoint is the URL of the token endpoint of the OpenID Connect provider, required by the authorization
+	// code flow.
+	// +kubebuilder:validation:Optional
+	TokenEndpoint string `json:"tokenEndpoint,omitempty"`
+	// UserinfoEndpoint is the URL of the user info endpoint of the OpenID Connect provider.
+	// +kubebuilder:validation:Optional
+	UserinfoEndpoint string `json:"userinfoEndpoint,omitempty"`
+	// EndSessionEndpoint is the URL of the end session endpoint of the OpenID Connect provider.
+	// +kubebuilder:validation:Optional
+	EndSessionEndpoint string `json:"endSessionEndpoint,omitempty"`
+	// JWKSetURL is the HTTPS URL of the JSON Web Key Set holding the keys used by the OpenID Connect provider to sign
+	// the tokens.
+	// +kubebuilder:validation:MinLength=1
+	JWKSetURL string `json:"jwkSetURL"`
+}
+
+// OIDCClaims maps the claims of the OpenID Connect provider to the user properties in Elasticsearch.
+type OIDCClaims struct {
+	// Principal is the name of the claim holding the username.
+	// +kubebuilder:validation:MinLength=1
+	Principal string `json:"principal"`
+	// Groups is the name of the claim holding the groups of the user, used in role mappings.
+	// +kubebuilder:validation:Optional
+	Groups string `json:"groups,omitempty"`
+	// Name is the name of the claim holding the full name of the user.
+	// +kubebuilder:validation:Optional
+	Name string `json:"name,omitempty"`
+	// Mail is the name of the claim holding the email address of the user.
+	// +kubebuilder:validation:Optional
+	Mail string `json:"mail,omitempty"`
+}
+
+// OIDCClientSecretKey is the entry of the client secret in the secret referenced by an OIDC realm.
+const OIDCClientSecretKey = "client_secret"
+
+// LDAPRealmType is the type of an LDAP realm.
+type LDAPRealmType string
+
+const (
+	// LDAPRealmTypeLDAP is the type of the realms connecting to LDAP servers.
+	LDAPRealmTypeLDAP LDAPRealmType = "ldap"
+	// LDAPRealmTypeActiveDirectory is the type of the realms connecting to Active Directory servers.
+	LDAPRealmTypeActiveDirectory LDAPRealmType = "active_directory"
+
+	// LDAPBindPasswordKey is the entry of the bind password in the secret referenced by an LDAP realm.
+	LDAPBindPasswordKey = "bind_password"
+	// LDAPCertificateAuthorityKey is the entry of the CA certificate in the secret referenced by an LDAP realm.
+	LDAPCertificateAuthorityKey = "ca.crt"
+)
+
+// LDAPRealm is an LDAP or Active Directory realm configured by the operator in the Elasticsearch configuration.
+type LDAPRealm struct {
+	// Name of the realm, unique within the cluster.
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:MinLength=1
+	// +kubebuilder:validation:MaxLength=50
+	// +kubebuilder:validation:Pattern=`^[a-z0-9]([-a-z0-9]*[a-z0-9])?$`
+	Name string `json:"name"`
+	// Type of the realm, "ldap" or "active_directory". Defaults to "ldap".
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Enum=ldap;active_directory
+	Type LDAPRealmType `json:"type,omitempty"`
+	// Order of the realm in the realm chain, unique across the realms of the cluster. The file and native realms managed by
+	// the operator come first.
+	// +kubebuilder:validation:Optional
+	Order int32 `json:"order,omitempty"`
+	// URLs of the LDAP servers, using the ldap:// or ldaps:// scheme. Required for LDAP realms. Active Directory realms
+	// default to the domain controllers of the domain.
+	// +kubebuilder:validation:Optional
+	URLs []string `json:"urls,omitempty"`
+	// Domain is the name of the Active Directory domain. Required for Active Directory realms.
+	// +kubebuilder:validation:Optional
+	Domain string `json:"domain,omitempty"`
+	// BindDN is the distinguished name of the user used to bind to the servers to search for users and groups.
+	// Users bind with their own credentials if not set.
+	// +kubebuilder:validation:Optional
+	BindDN string `json:"bindDN,omitempty"`
+	// BindPasswordSecretRef references a Kubernetes secret in the same namespace as the Elasticsearch resource, holding
+	// the password of the bind user under a "bind_password" entry. The password is added to the keystore.
+	// +kubebuilder:validation:Optional
+	BindPasswordSecretRef *commonv1.SecretRef `json:"bindPasswordSecretRef,omitempty"`
+	// UserSearchBaseDN is the distinguished name of the container under which users are searched.
+	// +kubebuilder:validation:Optional
+	UserSearchBaseDN string `json:"userSearchBaseDN,omitempty"`
+	// UserSearchFilter is the filter used to search for users, for example "(cn={0})".
+	// +kubebuilder:validation:Optional
+	UserSearchFilter string `json:"userSearchFilter,omitempty"`
+	// UserDNTemplates are the templates of the distinguished names of the users, used instead of a user search, for
+	// example "cn={0}, ou=users, o=example". Only used by LDAP realms.
+	// +kubebuilder:validation:Optional
+	UserDNTemplates []string `json:"userDNTemplates,omitempty"`
+	// GroupSearchBaseDN is the distinguished name of the container under which the groups of the users are searched.
+	// +kubebuilder:validation:Optional
+	GroupSearchBaseDN string `json:"groupSearchBaseDN,omitempty"`
+	// UnmappedGroupsAsRoles maps the groups of the users which are not referenced in role mappings to roles of the
+	// same name.
+	// +kubebuilder:validation:Optional
+	UnmappedGroupsAsRoles bool `json:"unmappedGroupsAsRoles,omitempty"`
+	// CASecretName references a Kubernetes secret in the same namespace as the Elasticsearch resource, holding the
+	// certificate of the certificate authority of the LDAP servers under a "ca.crt" entry. The certificate authority
+	// is trusted for the ldaps:// connections.
+	// +kubebuilder:validation:Optional
+	CASecretName string `json:"caSecretName,omitempty"`
+}
+
+// RealmType returns the type of the realm, defaulting to LDAP.
+func (r LDAPRealm) RealmType() LDAPRealmType {
+	if r.Type == "" {
+		return LDAPRealmTypeLDAP
+	}
+	return r.Type
+}
+
+// SettingsPrefix returns the prefix of the settings of the realm in the Elasticsearch configuration.
+func (r LDAPRealm) SettingsPrefix() string {
+	return XPackSecurityAuthcRealms + "." + string(r.RealmType()) + "." + r.Name
 }
 
 // RoleSource references roles to create in the Elasticsearch cluster.
@@ -297,11 +1068,40 @@ type NodeSet struct {
 	// +kubebuilder:validation:Optional
 	Count int32 `json:"count"`
 
+	// DataTier is the data tier the nodes of this NodeSet belong to. The operator sets the node.attr.data attribute
+	// to the name of the tier and, unless node.roles is explicitly set in the configuration, configures the
+	// corresponding data tier node roles. Nodes of the hot tier also hold the content tier.
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Enum=hot;warm;cold;frozen
+	DataTier DataTier `json:"dataTier,omitempty"`
+
+	// Attributes are custom node attributes, rendered into node.attr.* settings of the nodes of this NodeSet. They can
+	// be used for shard allocation filtering and shard allocation awareness.
+	// See https://www.elastic.co/guide/en/elasticsearch/reference/current/modules-cluster.html#shard-allocation-awareness.
+	// +kubebuilder:validation:Optional
+	Attributes map[string]string `json:"attributes,omitempty"`
+
+	// JVMOptions are additional JVM options, such as garbage collection settings or a heap dump path, rendered into a
+	// file of the jvm.options.d directory of the nodes of this NodeSet. Changing them restarts the nodes of this NodeSet only.
+	// Requires Elasticsearch 7.7.0 or later.
+	// +kubebuilder:validation:Optional
+	JVMOptions []string `json:"jvmOptions,omitempty"`
+
+	// MemoryDataVolume replaces the PersistentVolumeClaim of the data volume with an emptyDir volume backed by memory,
+	// for short-lived clusters or coordinating-only nodes. The data of a node is lost when its Pod is deleted.
+	// +kubebuilder:validation:Optional
+	MemoryDataVolume *MemoryDataVolume `json:"memoryDataVolume,omitempty"`
+
 	// PodTemplate provides customisation options (labels, annotations, affinity rules, resource requests, and so on) for the Pods belonging to this NodeSet.
 	// +kubebuilder:validation:Optional
 	// +kubebuilder:pruning:PreserveUnknownFields
 	PodTemplate corev1.PodTemplateSpec `json:"podTemplate,omitempty"`
 
+	// ReadinessProbe holds the timeouts and thresholds of the readiness probe of the Elasticsearch nodes of this NodeSet.
+	// A readiness probe set in the PodTemplate takes precedence.
+	// +kubebuilder:validation:Optional
+	ReadinessProbe *ReadinessProbe `json:"readinessProbe,omitempty"`
+
 	// VolumeClaimTemplates is a list of persistent volume claims to be used by each Pod in this NodeSet.
 	// Every claim in this list must have a matching volumeMount in one of the containers defined in the PodTemplate.
 	// Items defined here take precedence over any default claims added by the operator with the same name.
@@ -309,6 +1109,31 @@ type NodeSet struct {
 	VolumeClaimTemplates []corev1.PersistentVolumeClaim `json:"volumeClaimTemplates,omitempty"`
 }
 
+// MemoryDataVolume is an emptyDir data volume backed by memory.
+type MemoryDataVolume struct {
+	// SizeLimit is the maximum size of the volume. The content of the volume counts against the memory limit of the
+	// Elasticsearch container, which must be increased accordingly.
+	SizeLimit resource.Quantity `json:"sizeLimit"`
+}
+
+// ReadinessProbe holds the timeouts and thresholds of the readiness probe of the Elasticsearch nodes.
+// Unset values default to the values set by the operator.
+type ReadinessProbe struct {
+	// InitialDelaySeconds is the number of seconds after the container has started before the probe is initiated.
+	// +kubebuilder:validation:Minimum=0
+	InitialDelaySeconds int32 `json:"initialDelaySeconds,omitempty"`
+	// PeriodSeconds is how often, in seconds, to perform the probe.
+	// +kubebuilder:validation:Minimum=1
+	PeriodSeconds int32 `json:"periodSeconds,omitempty"`
+	// TimeoutSeconds is the number of seconds after which the probe times out. The requests sent to Elasticsearch by the
+	// probe time out 2 seconds earlier.
+	// +kubebuilder:validation:Minimum=3
+	TimeoutSeconds int32 `json:"timeoutSeconds,omitempty"`
+	// FailureThreshold is the number of consecutive failures for the node to be considered not ready.
+	// +kubebuilder:validation:Minimum=1
+	FailureThreshold int32 `json:"failureThreshold,omitempty"`
+}
+
 // +kubebuilder:object:generate=false
 type NodeSetList []NodeSet
 
@@ -423,6 +1248,40 @@ func (es Elasticsearch) HasDownwardNodeLabels() bool {
 	return len(es.DownwardNodeLabels()) > 0
 }
 
+// IsStorageMigrationEnabled returns true if the StatefulSets of the NodeSets can be replaced to apply changes to their
+// volume claim templates.
+func (es Elasticsearch) IsStorageMigrationEnabled() bool {
+	return es.Annotations[StorageMigrationAnnotation] == "true"
+}
+
+// IsSecureSettingsReloadEnabled returns true if updates of reloadable secure settings are applied without restarting
+// the Elasticsearch Pods.
+func (es Elasticsearch) IsSecureSettingsReloadEnabled() bool {
+	return es.Annotations[ReloadSecureSettingsAnnotation] == "true"
+}
+
+// MigratedStatefulSets returns the names of the StatefulSets replacing the original StatefulSets of the NodeSets whose
+// storage was migrated, by NodeSet name.
+func (es Elasticsearch) MigratedStatefulSets() map[string]string {
+	serialized, exists := es.Annotations[NodeSetStatefulSetsAnnotation]
+	if !exists {
+		return nil
+	}
+	var names map[string]string
+	if err := json.Unmarshal([]byte(serialized), &names); err != nil {
+		return nil
+	}
+	return names
+}
+
+// StatefulSetName returns the name of the StatefulSet of the given NodeSet, accounting for storage migrations.
+func (es Elasticsearch) StatefulSetName(nodeSetName string) string {
+	if name, migrated := es.MigratedStatefulSets()[nodeSetName]; migrated {
+		return name
+	}
+	return StatefulSet(es.Name, nodeSetName)
+}
+
 // IsMarkedForDeletion returns true if the Elasticsearch is going to be deleted
 func (es Elasticsearch) IsMarkedForDeletion() bool {
 	return !es.DeletionTimestamp.IsZero()
@@ -505,8 +1364,42 @@ func (es Elasticsearch) GetAutoscalingSpecificationFromAnnotation() (Autoscaling
 	return autoscalingSpec, err
 }
 
+// SecureSettings returns the secure settings of the cluster, including the credentials of the snapshot repositories,
+// the client secrets of the OIDC realms and the bind passwords of the LDAP realms.
 func (es Elasticsearch) SecureSettings() []commonv1.SecretSource {
-	return es.Spec.SecureSettings
+	if len(es.Spec.SnapshotRepositories) == 0 && len(es.Spec.Auth.OIDC) == 0 && len(es.Spec.Auth.LDAP) == 0 {
+		return es.Spec.SecureSettings
+	}
+	// copy the secure settings to not mutate the spec
+	secureSettings := append([]commonv1.SecretSource{}, es.Spec.SecureSettings...)
+	for _, repository := range es.Spec.SnapshotRepositories {
+		secureSettings = append(secureSettings, repository.SecureSettings...)
+	}
+	for _, realm := range es.Spec.Auth.OIDC {
+		if realm.RP.ClientSecretRef.SecretName == "" {
+			continue
+		}
+		secureSettings = append(secureSettings, commonv1.SecretSource{
+			SecretName: realm.RP.ClientSecretRef.SecretName,
+			Entries: []commonv1.KeyToPath{{
+				Key:  OIDCClientSecretKey,
+				Path: XPackSecurityAuthcRealmsOIDC + "." + realm.Name + ".rp.client_secret",
+			}},
+		})
+	}
+	for _, realm := range es.Spec.Auth.LDAP {
+		if realm.BindPasswordSecretRef == nil || realm.BindPasswordSecretRef.SecretName == "" {
+			continue
+		}
+		secureSettings = append(secureSettings, commonv1.SecretSource{
+			SecretName: realm.BindPasswordSecretRef.SecretName,
+			Entries: []commonv1.KeyToPath{{
+				Key:  LDAPBindPasswordKey,
+				Path: realm.SettingsPrefix() + ".secure_bind_password",
+			}},
+		})
+	}
+	return secureSettings
 }
 
 func (es Elasticsearch) SuspendedPodNames() set.StringSet {
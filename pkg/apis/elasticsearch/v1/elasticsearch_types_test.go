@@ -396,3 +396,44 @@ func Test_AssociationConfs(t *testing.T) {
 	}
 	assert.Equal(t, 2, len(esMon.AssocConfs))
 }
+
+func TestElasticsearch_SecureSettings(t *testing.T) {
+	es := Elasticsearch{Spec: ElasticsearchSpec{
+		SecureSettings: []commonv1.SecretSource{{SecretName: "settings"}},
+		SnapshotRepositories: []SnapshotRepository{
+			{Name: "s3", Type: S3SnapshotRepository, SecureSettings: []commonv1.SecretSource{{SecretName: "s3-credentials"}}},
+			{Name: "fs", Type: FSSnapshotRepository},
+		},
+	}}
+	require.Equal(t,
+		[]commonv1.SecretSource{{SecretName: "settings"}, {SecretName: "s3-credentials"}},
+		es.SecureSettings(),
+	)
+	// the spec is not mutated
+	require.Equal(t, []commonv1.SecretSource{{SecretName: "settings"}}, es.Spec.SecureSettings)
+
+	// the client secrets of the OIDC realms are added to the keystore
+	es.Spec.SnapshotRepositories = nil
+	es.Spec.Auth.OIDC = []OIDCRealm{{Name: "okta", RP: OIDCRelyingParty{ClientSecretRef: commonv1.SecretRef{SecretName: "okta-client"}}}}
+	require.Equal(t,
+		[]commonv1.SecretSource{
+			{SecretName: "settings"},
+			{SecretName: "okta-client", Entries: []commonv1.KeyToPath{{Key: "client_secret", Path: "xpack.security.authc.realms.oidc.okta.rp.client_secret"}}},
+		},
+		es.SecureSettings(),
+	)
+
+	// the bind passwords of the LDAP realms are added to the keystore
+	es.Spec.Auth.OIDC = nil
+	es.Spec.Auth.LDAP = []LDAPRealm{
+		{Name: "corp", Type: LDAPRealmTypeActiveDirectory, BindPasswordSecretRef: &commonv1.SecretRef{SecretName: "corp-bind"}},
+		{Name: "anonymous"},
+	}
+	require.Equal(t,
+		[]commonv1.SecretSource{
+			{SecretName: "settings"},
+			{SecretName: "corp-bind", Entries: []commonv1.KeyToPath{{Key: "bind_password", Path: "xpack.security.authc.realms.active_directory.corp.secure_bind_password"}}},
+		},
+		es.SecureSettings(),
+	)
+}
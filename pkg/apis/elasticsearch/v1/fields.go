@@ -22,9 +22,12 @@ const (
 
 	PathData = "path.data"
 	PathLogs = "path.logs"
+	PathRepo = "path.repo"
 
 	ShardAwarenessAttributes = "cluster.routing.allocation.awareness.attributes"
 	NodeAttr                 = "node.attr"
+	// K8sNodeNameAttr is the name of the node attribute holding the name of the Kubernetes node of an Elasticsearch node.
+	K8sNodeNameAttr = "k8s_node_name"
 
 	XPackSecurityAuthcRealmsFileFile1Order     = "xpack.security.authc.realms.file.file1.order"     // 7.x realm syntax
 	XPackSecurityAuthcRealmsFile1Order         = "xpack.security.authc.realms.file1.order"          // 6.x realm syntax
@@ -33,6 +36,18 @@ const (
 	XPackSecurityAuthcRealmsNative1Order       = "xpack.security.authc.realms.native1.order"        // 6.x realm syntax
 	XPackSecurityAuthcRealmsNative1Type        = "xpack.security.authc.realms.native1.type"         // 6.x realm syntax
 
+	XPackSecurityAuthcRealms       = "xpack.security.authc.realms"
+	XPackSecurityAuthcRealmsSAML   = "xpack.security.authc.realms.saml" // 7.x realm syntax
+	XPackSecurityAuthcRealmsOIDC   = "xpack.security.authc.realms.oidc" // 7.x realm syntax
+	XPackSecurityAuthcTokenEnabled = "xpack.security.authc.token.enabled"
+
+	XPackSecurityAuditEnabled                    = "xpack.security.audit.enabled"
+	XPackSecurityAuditOutputs                    = "xpack.security.audit.outputs" // 6.x only
+	XPackSecurityAuditLogfileEventsInclude       = "xpack.security.audit.logfile.events.include"
+	XPackSecurityAuditLogfileEventsExclude       = "xpack.security.audit.logfile.events.exclude"
+	XPackSecurityAuditLogfileEventsEmitBody      = "xpack.security.audit.logfile.events.emit_request_body"
+	XPackSecurityAuditLogfileEventsIgnoreFilters = "xpack.security.audit.logfile.events.ignore_filters"
+
 	XPackSecurityAuthcReservedRealmEnabled          = "xpack.security.authc.reserved_realm.enabled"
 	XPackSecurityEnabled                            = "xpack.security.enabled"
 	XPackSecurityHttpSslCertificate                 = "xpack.security.http.ssl.certificate"             //nolint:revive
@@ -23,6 +23,9 @@ const (
 	transportServiceSuffix                       = "transport"
 	elasticUserSecretSuffix                      = "elastic-user"
 	internalUsersSecretSuffix                    = "internal-users"
+	nativeUsersSecretSuffix                      = "native-users"
+	managedResourcesSecretSuffix                 = "managed-resources"
+	stackConfigPolicySecretSuffix                = "stack-config-policy"
 	unicastHostsConfigMapSuffix                  = "unicast-hosts"
 	licenseSecretSuffix                          = "license"
 	defaultPodDisruptionBudget                   = "default"
@@ -51,6 +54,9 @@ var (
 		elasticUserSecretSuffix,
 		rolesAndFileRealmSecretSuffix,
 		internalUsersSecretSuffix,
+		nativeUsersSecretSuffix,
+		managedResourcesSecretSuffix,
+		stackConfigPolicySecretSuffix,
 		unicastHostsConfigMapSuffix,
 		licenseSecretSuffix,
 		defaultPodDisruptionBudget,
@@ -81,14 +87,8 @@ func ValidateNames(es Elasticsearch) error {
 		if err != nil {
 			return errors.Wrapf(err, "error generating StatefulSet name for nodeSet: '%s'", nodeSet.Name)
 		}
-
-		// length of the ordinal suffix that will be added to the pods of this sset (dash + ordinal)
-		podOrdinalSuffixLen := len(strconv.FormatInt(int64(nodeSet.Count), 10)) + 1
-		// there should be enough space for the ordinal suffix and the controller revision hash
-		if utilvalidation.LabelValueMaxLength-len(ssetName) < podOrdinalSuffixLen+controllerRevisionHashLen {
-			return errors.Errorf("generated StatefulSet name '%s' exceeds allowed length of %d",
-				ssetName,
-				utilvalidation.LabelValueMaxLength-podOrdinalSuffixLen-controllerRevisionHashLen)
+		if err := ValidateStatefulSetNameLength(ssetName, nodeSet.Count); err != nil {
+			return err
 		}
 	}
 
@@ -102,6 +102,20 @@ func ValidateNames(es Elasticsearch) error {
 	return nil
 }
 
+// ValidateStatefulSetNameLength checks that the names of the Pods of a StatefulSet with the given name and number of
+// replicas, and their controller revision hash label, do not exceed the allowed length.
+func ValidateStatefulSetNameLength(ssetName string, replicas int32) error {
+	// length of the ordinal suffix that will be added to the pods of this sset (dash + ordinal)
+	podOrdinalSuffixLen := len(strconv.FormatInt(int64(replicas), 10)) + 1
+	// there should be enough space for the ordinal suffix and the controller revision hash
+	if utilvalidation.LabelValueMaxLength-len(ssetName) < podOrdinalSuffixLen+controllerRevisionHashLen {
+		return errors.Errorf("generated StatefulSet name '%s' exceeds allowed length of %d",
+			ssetName,
+			utilvalidation.LabelValueMaxLength-podOrdinalSuffixLen-controllerRevisionHashLen)
+	}
+	return nil
+}
+
 // StatefulSet returns the name of the StatefulSet corresponding to the given NodeSet.
 func StatefulSet(esName string, nodeSetName string) string {
 	return ESNamer.Suffix(esName, nodeSetName)
@@ -149,6 +163,23 @@ func InternalUsersSecret(esName string) string {
 	return ESNamer.Suffix(esName, internalUsersSecretSuffix)
 }
 
+// NativeUsersSecret returns the name of the Secret which tracks the users of the native realm managed by the operator.
+func NativeUsersSecret(esName string) string {
+	return ESNamer.Suffix(esName, nativeUsersSecretSuffix)
+}
+
+// ManagedResourcesSecret returns the name of the Secret which tracks the resources declared in the spec of the given
+// cluster and applied through the Elasticsearch API.
+func ManagedResourcesSecret(esName string) string {
+	return ESNamer.Suffix(esName, managedResourcesSecretSuffix)
+}
+
+// StackConfigPolicySecret returns the name of the Secret which tracks the resources applied by the StackConfigPolicy
+// selecting the given cluster.
+func StackConfigPolicySecret(esName string) string {
+	return ESNamer.Suffix(esName, stackConfigPolicySecretSuffix)
+}
+
 // UnicastHostsConfigMap returns the name of the ConfigMap that holds the list of seed nodes for a given cluster.
 func UnicastHostsConfigMap(esName string) string {
 	return ESNamer.Suffix(esName, unicastHostsConfigMapSuffix)
@@ -76,6 +76,33 @@ type ElasticsearchStatus struct {
 	// **This API is in technical preview and may be changed or removed in a future release.**
 	InProgressOperations `json:"inProgressOperations"`
 
+	// +optional
+	// SnapshotRepositories reports the scheduled snapshots of the snapshot repositories declared with a schedule.
+	SnapshotRepositories []SnapshotRepositoryStatus `json:"snapshotRepositories,omitempty"`
+
+	// +optional
+	// SnapshotRepositoriesVerificationTime is the time the declared snapshot repositories were last verified. The result
+	// of the verification is reported in the SnapshotRepositoriesVerified condition.
+	SnapshotRepositoriesVerificationTime *metav1.Time `json:"snapshotRepositoriesVerificationTime,omitempty"`
+
+	// +optional
+	// InitialRestore reports the progress of the restore of the snapshot declared in the initialRestore specification.
+	InitialRestore *InitialRestoreStatus `json:"initialRestore,omitempty"`
+
+	// +optional
+	// RestoreVerification reports the last verification of the snapshots declared in the restoreVerification specification.
+	RestoreVerification *RestoreVerificationStatus `json:"restoreVerification,omitempty"`
+
+	// +optional
+	// License reports the license currently applied to the Elasticsearch cluster.
+	License *LicenseStatus `json:"license,omitempty"`
+
+	// +optional
+	// ConfigOverrides lists the settings of the Elasticsearch configuration defined with different values by several
+	// sources, such as operator-managed settings overridden by the user configuration, prefixed with the name of their
+	// NodeSet.
+	ConfigOverrides []string `json:"configOverrides,omitempty"`
+
 	// ObservedGeneration is the most recent generation observed for this Elasticsearch cluster.
 	// It corresponds to the metadata generation, which is updated on mutation by the API Server.
 	// If the generation observed in status diverges from the generation in metadata, the Elasticsearch
@@ -83,6 +110,21 @@ type ElasticsearchStatus struct {
 	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
 }
 
+// LicenseStatus is the license applied to an Elasticsearch cluster, as reported by the license API.
+type LicenseStatus struct {
+	// Type of the license, for example basic, trial or enterprise.
+	Type string `json:"type"`
+	// UID is the unique identifier of the license.
+	// +optional
+	UID string `json:"uid,omitempty"`
+	// Status of the license, for example active or expired.
+	// +optional
+	Status string `json:"status,omitempty"`
+	// ExpiryTime is the time as of which the license is no longer valid. Not set for licenses which do not expire.
+	// +optional
+	ExpiryTime *metav1.Time `json:"expiryTime,omitempty"`
+}
+
 // IsDegraded returns true if the current status is worse than the previous.
 func (es ElasticsearchStatus) IsDegraded(prev ElasticsearchStatus) bool {
 	return es.Health.Less(prev.Health)
@@ -106,10 +148,14 @@ func (es *Elasticsearch) SetAssociationStatusMap(typ commonv1.AssociationType, s
 }
 
 const (
-	ElasticsearchIsReachable v1alpha1.ConditionType = "ElasticsearchIsReachable"
-	ReconciliationComplete   v1alpha1.ConditionType = "ReconciliationComplete"
-	ResourcesAwareManagement v1alpha1.ConditionType = "ResourcesAwareManagement"
-	RunningDesiredVersion    v1alpha1.ConditionType = "RunningDesiredVersion"
+	ElasticsearchIsReachable     v1alpha1.ConditionType = "ElasticsearchIsReachable"
+	ILMDataTiersAvailable        v1alpha1.ConditionType = "ILMDataTiersAvailable"
+	ReconciliationComplete       v1alpha1.ConditionType = "ReconciliationComplete"
+	ReconciliationPaused         v1alpha1.ConditionType = "ReconciliationPaused"
+	ResourcesAwareManagement     v1alpha1.ConditionType = "ResourcesAwareManagement"
+	RunningDesiredVersion        v1alpha1.ConditionType = "RunningDesiredVersion"
+	SnapshotRepositoriesVerified v1alpha1.ConditionType = "SnapshotRepositoriesVerified"
+	UpgradeSnapshotTaken         v1alpha1.ConditionType = "UpgradeSnapshotTaken"
 )
 
 // NewNodeStatus provides details about the status of nodes which are expected to be created and added to the Elasticsearch cluster.
@@ -210,3 +256,122 @@ type InProgressOperations struct {
 	UpgradeOperation   UpgradeOperation   `json:"upgrade"`
 	UpscaleOperation   UpscaleOperation   `json:"upscale"`
 }
+
+// SnapshotState is the state of a snapshot as returned by the Elasticsearch snapshot API.
+type SnapshotState string
+
+const (
+	SnapshotInProgress SnapshotState = "IN_PROGRESS"
+	SnapshotSuccess    SnapshotState = "SUCCESS"
+	SnapshotFailed     SnapshotState = "FAILED"
+	SnapshotPartial    SnapshotState = "PARTIAL"
+)
+
+// SnapshotRepositoryStatus reports the scheduled snapshots of a snapshot repository.
+type SnapshotRepositoryStatus struct {
+	// Name of the snapshot repository.
+	Name string `json:"name"`
+
+	// +optional
+	// LastSnapshot is the name of the last snapshot taken by the schedule.
+	LastSnapshot string `json:"lastSnapshot,omitempty"`
+
+	// +optional
+	// LastSnapshotTime is the time the last snapshot taken by the schedule started.
+	LastSnapshotTime *metav1.Time `json:"lastSnapshotTime,omitempty"`
+
+	// +optional
+	// LastSnapshotState is the state of the last snapshot taken by the schedule.
+	LastSnapshotState SnapshotState `json:"lastSnapshotState,omitempty"`
+
+	// +optional
+	// LastSuccessTime is the time the last successful snapshot taken by the schedule started.
+	LastSuccessTime *metav1.Time `json:"lastSuccessTime,omitempty"`
+
+	// +optional
+	// LastFailureTime is the time the last failed snapshot taken by the schedule started.
+	LastFailureTime *metav1.Time `json:"lastFailureTime,omitempty"`
+
+	// +optional
+	// LastFailureReason is the reason of the failure of the last failed snapshot taken by the schedule.
+	LastFailureReason string `json:"lastFailureReason,omitempty"`
+
+	// +optional
+	// SnapshotInProgress is true while a snapshot taken by the schedule is in progress.
+	SnapshotInProgress bool `json:"snapshotInProgress,omitempty"`
+
+	// +optional
+	// NextSnapshotTime is the time the next snapshot is scheduled at.
+	NextSnapshotTime *metav1.Time `json:"nextSnapshotTime,omitempty"`
+}
+
+// InitialRestorePhase is the phase of the restore of the initial snapshot.
+type InitialRestorePhase string
+
+const (
+	InitialRestoreInProgress InitialRestorePhase = "InProgress"
+	InitialRestoreCompleted  InitialRestorePhase = "Completed"
+	InitialRestoreFailed     InitialRestorePhase = "Failed"
+)
+
+// InitialRestoreStatus reports the progress of the restore of the initial snapshot.
+type InitialRestoreStatus struct {
+	// Repository containing the restored snapshot.
+	Repository string `json:"repository"`
+
+	// Snapshot being restored.
+	Snapshot string `json:"snapshot"`
+
+	// Phase of the restore.
+	Phase InitialRestorePhase `json:"phase,omitempty"`
+
+	// +optional
+	// Message provides details about the phase of the restore, such as the reason of a failure.
+	Message string `json:"message,omitempty"`
+
+	// +optional
+	// StartTime is the time the restore started.
+	StartTime *metav1.Time `json:"startTime,omitempty"`
+
+	// +optional
+	// CompletionTime is the time the restore completed.
+	CompletionTime *metav1.Time `json:"completionTime,omitempty"`
+
+	// +optional
+	// DocumentCount is the number of documents in the restored indices, once the restore is completed.
+	DocumentCount *int64 `json:"documentCount,omitempty"`
+}
+
+// RestoreVerificationPhase is the phase of the verification of a snapshot.
+type RestoreVerificationPhase string
+
+const (
+	RestoreVerificationInProgress RestoreVerificationPhase = "InProgress"
+	RestoreVerificationSucceeded  RestoreVerificationPhase = "Succeeded"
+	RestoreVerificationFailed     RestoreVerificationPhase = "Failed"
+)
+
+// RestoreVerificationStatus reports the verification of a snapshot restored in a temporary cluster.
+type RestoreVerificationStatus struct {
+	// Snapshot being verified.
+	Snapshot string `json:"snapshot,omitempty"`
+
+	// Phase of the verification.
+	Phase RestoreVerificationPhase `json:"phase,omitempty"`
+
+	// +optional
+	// Message provides details about the phase of the verification, such as the reason of a failure.
+	Message string `json:"message,omitempty"`
+
+	// +optional
+	// DocumentCount is the number of documents in the indices restored in the verification cluster.
+	DocumentCount *int64 `json:"documentCount,omitempty"`
+
+	// +optional
+	// StartTime is the time the verification started.
+	StartTime *metav1.Time `json:"startTime,omitempty"`
+
+	// +optional
+	// CompletionTime is the time the verification completed.
+	CompletionTime *metav1.Time `json:"completionTime,omitempty"`
+}
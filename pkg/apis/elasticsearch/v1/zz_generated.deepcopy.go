@@ -16,6 +16,73 @@ import (
 	runtime "k8s.io/apimachinery/pkg/runtime"
 )
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AuditIgnoreFilter) DeepCopyInto(out *AuditIgnoreFilter) {
+	*out = *in
+	if in.Users != nil {
+		in, out := &in.Users, &out.Users
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Realms != nil {
+		in, out := &in.Realms, &out.Realms
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Roles != nil {
+		in, out := &in.Roles, &out.Roles
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Indices != nil {
+		in, out := &in.Indices, &out.Indices
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AuditIgnoreFilter.
+func (in *AuditIgnoreFilter) DeepCopy() *AuditIgnoreFilter {
+	if in == nil {
+		return nil
+	}
+	out := new(AuditIgnoreFilter)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AuditLogging) DeepCopyInto(out *AuditLogging) {
+	*out = *in
+	if in.IncludeEvents != nil {
+		in, out := &in.IncludeEvents, &out.IncludeEvents
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.ExcludeEvents != nil {
+		in, out := &in.ExcludeEvents, &out.ExcludeEvents
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.IgnoreFilters != nil {
+		in, out := &in.IgnoreFilters, &out.IgnoreFilters
+		*out = make([]AuditIgnoreFilter, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AuditLogging.
+func (in *AuditLogging) DeepCopy() *AuditLogging {
+	if in == nil {
+		return nil
+	}
+	out := new(AuditLogging)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Auth) DeepCopyInto(out *Auth) {
 	*out = *in
@@ -29,6 +96,32 @@ func (in *Auth) DeepCopyInto(out *Auth) {
 		*out = make([]FileRealmSource, len(*in))
 		copy(*out, *in)
 	}
+	if in.Users != nil {
+		in, out := &in.Users, &out.Users
+		*out = make([]NativeUser, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.SAML != nil {
+		in, out := &in.SAML, &out.SAML
+		*out = make([]SAMLRealm, len(*in))
+		copy(*out, *in)
+	}
+	if in.OIDC != nil {
+		in, out := &in.OIDC, &out.OIDC
+		*out = make([]OIDCRealm, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.LDAP != nil {
+		in, out := &in.LDAP, &out.LDAP
+		*out = make([]LDAPRealm, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Auth.
@@ -117,6 +210,25 @@ func (in *ClusterSettings) DeepCopy() *ClusterSettings {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ComponentTemplate) DeepCopyInto(out *ComponentTemplate) {
+	*out = *in
+	if in.Definition != nil {
+		in, out := &in.Definition, &out.Definition
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ComponentTemplate.
+func (in *ComponentTemplate) DeepCopy() *ComponentTemplate {
+	if in == nil {
+		return nil
+	}
+	out := new(ComponentTemplate)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *DownscaleOperation) DeepCopyInto(out *DownscaleOperation) {
 	*out = *in
@@ -271,6 +383,11 @@ func (in *ElasticsearchSpec) DeepCopyInto(out *ElasticsearchSpec) {
 		(*in).DeepCopyInto(*out)
 	}
 	in.Auth.DeepCopyInto(&out.Auth)
+	if in.AuditLogging != nil {
+		in, out := &in.AuditLogging, &out.AuditLogging
+		*out = new(AuditLogging)
+		(*in).DeepCopyInto(*out)
+	}
 	if in.SecureSettings != nil {
 		in, out := &in.SecureSettings, &out.SecureSettings
 		*out = make([]commonv1.SecretSource, len(*in))
@@ -278,6 +395,78 @@ func (in *ElasticsearchSpec) DeepCopyInto(out *ElasticsearchSpec) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.Plugins != nil {
+		in, out := &in.Plugins, &out.Plugins
+		*out = make([]Plugin, len(*in))
+		copy(*out, *in)
+	}
+	if in.SetVMMaxMapCount != nil {
+		in, out := &in.SetVMMaxMapCount, &out.SetVMMaxMapCount
+		*out = new(bool)
+		**out = **in
+	}
+	if in.SnapshotVolumes != nil {
+		in, out := &in.SnapshotVolumes, &out.SnapshotVolumes
+		*out = make([]SnapshotVolume, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.SnapshotRepositories != nil {
+		in, out := &in.SnapshotRepositories, &out.SnapshotRepositories
+		*out = make([]SnapshotRepository, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.UpgradeSnapshot != nil {
+		in, out := &in.UpgradeSnapshot, &out.UpgradeSnapshot
+		*out = new(UpgradeSnapshot)
+		**out = **in
+	}
+	if in.InitialRestore != nil {
+		in, out := &in.InitialRestore, &out.InitialRestore
+		*out = new(InitialRestore)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.RestoreVerification != nil {
+		in, out := &in.RestoreVerification, &out.RestoreVerification
+		*out = new(RestoreVerification)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.IngestPipelines != nil {
+		in, out := &in.IngestPipelines, &out.IngestPipelines
+		*out = make([]IngestPipeline, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.ClusterSettings != nil {
+		in, out := &in.ClusterSettings, &out.ClusterSettings
+		*out = new(ManagedClusterSettings)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ComponentTemplates != nil {
+		in, out := &in.ComponentTemplates, &out.ComponentTemplates
+		*out = make([]ComponentTemplate, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.IndexTemplates != nil {
+		in, out := &in.IndexTemplates, &out.IndexTemplates
+		*out = make([]IndexTemplate, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.ILMPolicies != nil {
+		in, out := &in.ILMPolicies, &out.ILMPolicies
+		*out = make([]ILMPolicy, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 	if in.RemoteClusters != nil {
 		in, out := &in.RemoteClusters, &out.RemoteClusters
 		*out = make([]RemoteCluster, len(*in))
@@ -319,6 +508,37 @@ func (in *ElasticsearchStatus) DeepCopyInto(out *ElasticsearchStatus) {
 		}
 	}
 	in.InProgressOperations.DeepCopyInto(&out.InProgressOperations)
+	if in.SnapshotRepositories != nil {
+		in, out := &in.SnapshotRepositories, &out.SnapshotRepositories
+		*out = make([]SnapshotRepositoryStatus, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.SnapshotRepositoriesVerificationTime != nil {
+		in, out := &in.SnapshotRepositoriesVerificationTime, &out.SnapshotRepositoriesVerificationTime
+		*out = (*in).DeepCopy()
+	}
+	if in.InitialRestore != nil {
+		in, out := &in.InitialRestore, &out.InitialRestore
+		*out = new(InitialRestoreStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.RestoreVerification != nil {
+		in, out := &in.RestoreVerification, &out.RestoreVerification
+		*out = new(RestoreVerificationStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.License != nil {
+		in, out := &in.License, &out.License
+		*out = new(LicenseStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ConfigOverrides != nil {
+		in, out := &in.ConfigOverrides, &out.ConfigOverrides
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ElasticsearchStatus.
@@ -368,6 +588,25 @@ func (in *FileRealmSource) DeepCopy() *FileRealmSource {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ILMPolicy) DeepCopyInto(out *ILMPolicy) {
+	*out = *in
+	if in.Definition != nil {
+		in, out := &in.Definition, &out.Definition
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ILMPolicy.
+func (in *ILMPolicy) DeepCopy() *ILMPolicy {
+	if in == nil {
+		return nil
+	}
+	out := new(ILMPolicy)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *InProgressOperations) DeepCopyInto(out *InProgressOperations) {
 	*out = *in
@@ -387,185 +626,746 @@ func (in *InProgressOperations) DeepCopy() *InProgressOperations {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *NewNode) DeepCopyInto(out *NewNode) {
+func (in *IndexTemplate) DeepCopyInto(out *IndexTemplate) {
 	*out = *in
-	if in.Message != nil {
-		in, out := &in.Message, &out.Message
-		*out = new(string)
-		**out = **in
+	if in.Definition != nil {
+		in, out := &in.Definition, &out.Definition
+		*out = (*in).DeepCopy()
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NewNode.
-func (in *NewNode) DeepCopy() *NewNode {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new IndexTemplate.
+func (in *IndexTemplate) DeepCopy() *IndexTemplate {
 	if in == nil {
 		return nil
 	}
-	out := new(NewNode)
+	out := new(IndexTemplate)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *Node) DeepCopyInto(out *Node) {
+func (in *IngestPipeline) DeepCopyInto(out *IngestPipeline) {
 	*out = *in
-	if in.Master != nil {
-		in, out := &in.Master, &out.Master
-		*out = new(bool)
-		**out = **in
-	}
-	if in.Data != nil {
-		in, out := &in.Data, &out.Data
-		*out = new(bool)
-		**out = **in
-	}
-	if in.Ingest != nil {
-		in, out := &in.Ingest, &out.Ingest
-		*out = new(bool)
-		**out = **in
-	}
-	if in.ML != nil {
-		in, out := &in.ML, &out.ML
-		*out = new(bool)
-		**out = **in
-	}
-	if in.Transform != nil {
-		in, out := &in.Transform, &out.Transform
-		*out = new(bool)
-		**out = **in
-	}
-	if in.RemoteClusterClient != nil {
-		in, out := &in.RemoteClusterClient, &out.RemoteClusterClient
-		*out = new(bool)
-		**out = **in
-	}
-	if in.Roles != nil {
-		in, out := &in.Roles, &out.Roles
-		*out = make([]string, len(*in))
-		copy(*out, *in)
-	}
-	if in.VotingOnly != nil {
-		in, out := &in.VotingOnly, &out.VotingOnly
-		*out = new(bool)
-		**out = **in
+	if in.Definition != nil {
+		in, out := &in.Definition, &out.Definition
+		*out = (*in).DeepCopy()
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Node.
-func (in *Node) DeepCopy() *Node {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new IngestPipeline.
+func (in *IngestPipeline) DeepCopy() *IngestPipeline {
 	if in == nil {
 		return nil
 	}
-	out := new(Node)
+	out := new(IngestPipeline)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *NodeSet) DeepCopyInto(out *NodeSet) {
+func (in *InitialRestore) DeepCopyInto(out *InitialRestore) {
 	*out = *in
-	if in.Config != nil {
-		in, out := &in.Config, &out.Config
-		*out = (*in).DeepCopy()
-	}
-	in.PodTemplate.DeepCopyInto(&out.PodTemplate)
-	if in.VolumeClaimTemplates != nil {
-		in, out := &in.VolumeClaimTemplates, &out.VolumeClaimTemplates
-		*out = make([]corev1.PersistentVolumeClaim, len(*in))
-		for i := range *in {
-			(*in)[i].DeepCopyInto(&(*out)[i])
-		}
+	if in.Indices != nil {
+		in, out := &in.Indices, &out.Indices
+		*out = make([]string, len(*in))
+		copy(*out, *in)
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NodeSet.
-func (in *NodeSet) DeepCopy() *NodeSet {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new InitialRestore.
+func (in *InitialRestore) DeepCopy() *InitialRestore {
 	if in == nil {
 		return nil
 	}
-	out := new(NodeSet)
+	out := new(InitialRestore)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *RemoteCluster) DeepCopyInto(out *RemoteCluster) {
+func (in *InitialRestoreStatus) DeepCopyInto(out *InitialRestoreStatus) {
 	*out = *in
-	out.ElasticsearchRef = in.ElasticsearchRef
+	if in.StartTime != nil {
+		in, out := &in.StartTime, &out.StartTime
+		*out = (*in).DeepCopy()
+	}
+	if in.CompletionTime != nil {
+		in, out := &in.CompletionTime, &out.CompletionTime
+		*out = (*in).DeepCopy()
+	}
+	if in.DocumentCount != nil {
+		in, out := &in.DocumentCount, &out.DocumentCount
+		*out = new(int64)
+		**out = **in
+	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RemoteCluster.
-func (in *RemoteCluster) DeepCopy() *RemoteCluster {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new InitialRestoreStatus.
+func (in *InitialRestoreStatus) DeepCopy() *InitialRestoreStatus {
 	if in == nil {
 		return nil
 	}
-	out := new(RemoteCluster)
+	out := new(InitialRestoreStatus)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *RoleSource) DeepCopyInto(out *RoleSource) {
+func (in *LDAPRealm) DeepCopyInto(out *LDAPRealm) {
 	*out = *in
-	out.SecretRef = in.SecretRef
+	if in.URLs != nil {
+		in, out := &in.URLs, &out.URLs
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.BindPasswordSecretRef != nil {
+		in, out := &in.BindPasswordSecretRef, &out.BindPasswordSecretRef
+		*out = new(commonv1.SecretRef)
+		**out = **in
+	}
+	if in.UserDNTemplates != nil {
+		in, out := &in.UserDNTemplates, &out.UserDNTemplates
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RoleSource.
-func (in *RoleSource) DeepCopy() *RoleSource {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LDAPRealm.
+func (in *LDAPRealm) DeepCopy() *LDAPRealm {
 	if in == nil {
 		return nil
 	}
-	out := new(RoleSource)
+	out := new(LDAPRealm)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *TransportConfig) DeepCopyInto(out *TransportConfig) {
+func (in *LicenseStatus) DeepCopyInto(out *LicenseStatus) {
 	*out = *in
-	in.Service.DeepCopyInto(&out.Service)
-	in.TLS.DeepCopyInto(&out.TLS)
+	if in.ExpiryTime != nil {
+		in, out := &in.ExpiryTime, &out.ExpiryTime
+		*out = (*in).DeepCopy()
+	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TransportConfig.
-func (in *TransportConfig) DeepCopy() *TransportConfig {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LicenseStatus.
+func (in *LicenseStatus) DeepCopy() *LicenseStatus {
 	if in == nil {
 		return nil
 	}
-	out := new(TransportConfig)
+	out := new(LicenseStatus)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *TransportTLSOptions) DeepCopyInto(out *TransportTLSOptions) {
+func (in *ManagedClusterSettings) DeepCopyInto(out *ManagedClusterSettings) {
 	*out = *in
-	if in.SubjectAlternativeNames != nil {
-		in, out := &in.SubjectAlternativeNames, &out.SubjectAlternativeNames
-		*out = make([]commonv1.SubjectAlternativeName, len(*in))
-		copy(*out, *in)
+	if in.Persistent != nil {
+		in, out := &in.Persistent, &out.Persistent
+		*out = (*in).DeepCopy()
 	}
-	out.Certificate = in.Certificate
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TransportTLSOptions.
-func (in *TransportTLSOptions) DeepCopy() *TransportTLSOptions {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ManagedClusterSettings.
+func (in *ManagedClusterSettings) DeepCopy() *ManagedClusterSettings {
 	if in == nil {
 		return nil
 	}
-	out := new(TransportTLSOptions)
+	out := new(ManagedClusterSettings)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *UpdateStrategy) DeepCopyInto(out *UpdateStrategy) {
+func (in *MemoryDataVolume) DeepCopyInto(out *MemoryDataVolume) {
 	*out = *in
-	in.ChangeBudget.DeepCopyInto(&out.ChangeBudget)
+	out.SizeLimit = in.SizeLimit.DeepCopy()
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new UpdateStrategy.
-func (in *UpdateStrategy) DeepCopy() *UpdateStrategy {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MemoryDataVolume.
+func (in *MemoryDataVolume) DeepCopy() *MemoryDataVolume {
+	if in == nil {
+		return nil
+	}
+	out := new(MemoryDataVolume)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NativeUser) DeepCopyInto(out *NativeUser) {
+	*out = *in
+	out.PasswordSecretRef = in.PasswordSecretRef
+	if in.Roles != nil {
+		in, out := &in.Roles, &out.Roles
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NativeUser.
+func (in *NativeUser) DeepCopy() *NativeUser {
+	if in == nil {
+		return nil
+	}
+	out := new(NativeUser)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NewNode) DeepCopyInto(out *NewNode) {
+	*out = *in
+	if in.Message != nil {
+		in, out := &in.Message, &out.Message
+		*out = new(string)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NewNode.
+func (in *NewNode) DeepCopy() *NewNode {
+	if in == nil {
+		return nil
+	}
+	out := new(NewNode)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Node) DeepCopyInto(out *Node) {
+	*out = *in
+	if in.Master != nil {
+		in, out := &in.Master, &out.Master
+		*out = new(bool)
+		**out = **in
+	}
+	if in.Data != nil {
+		in, out := &in.Data, &out.Data
+		*out = new(bool)
+		**out = **in
+	}
+	if in.Ingest != nil {
+		in, out := &in.Ingest, &out.Ingest
+		*out = new(bool)
+		**out = **in
+	}
+	if in.ML != nil {
+		in, out := &in.ML, &out.ML
+		*out = new(bool)
+		**out = **in
+	}
+	if in.Transform != nil {
+		in, out := &in.Transform, &out.Transform
+		*out = new(bool)
+		**out = **in
+	}
+	if in.RemoteClusterClient != nil {
+		in, out := &in.RemoteClusterClient, &out.RemoteClusterClient
+		*out = new(bool)
+		**out = **in
+	}
+	if in.Roles != nil {
+		in, out := &in.Roles, &out.Roles
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.VotingOnly != nil {
+		in, out := &in.VotingOnly, &out.VotingOnly
+		*out = new(bool)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Node.
+func (in *Node) DeepCopy() *Node {
+	if in == nil {
+		return nil
+	}
+	out := new(Node)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NodeSet) DeepCopyInto(out *NodeSet) {
+	*out = *in
+	if in.Config != nil {
+		in, out := &in.Config, &out.Config
+		*out = (*in).DeepCopy()
+	}
+	if in.Attributes != nil {
+		in, out := &in.Attributes, &out.Attributes
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.JVMOptions != nil {
+		in, out := &in.JVMOptions, &out.JVMOptions
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.MemoryDataVolume != nil {
+		in, out := &in.MemoryDataVolume, &out.MemoryDataVolume
+		*out = new(MemoryDataVolume)
+		(*in).DeepCopyInto(*out)
+	}
+	in.PodTemplate.DeepCopyInto(&out.PodTemplate)
+	if in.ReadinessProbe != nil {
+		in, out := &in.ReadinessProbe, &out.ReadinessProbe
+		*out = new(ReadinessProbe)
+		**out = **in
+	}
+	if in.VolumeClaimTemplates != nil {
+		in, out := &in.VolumeClaimTemplates, &out.VolumeClaimTemplates
+		*out = make([]corev1.PersistentVolumeClaim, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NodeSet.
+func (in *NodeSet) DeepCopy() *NodeSet {
+	if in == nil {
+		return nil
+	}
+	out := new(NodeSet)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OIDCClaims) DeepCopyInto(out *OIDCClaims) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OIDCClaims.
+func (in *OIDCClaims) DeepCopy() *OIDCClaims {
+	if in == nil {
+		return nil
+	}
+	out := new(OIDCClaims)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OIDCProvider) DeepCopyInto(out *OIDCProvider) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OIDCProvider.
+func (in *OIDCProvider) DeepCopy() *OIDCProvider {
+	if in == nil {
+		return nil
+	}
+	out := new(OIDCProvider)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OIDCRealm) DeepCopyInto(out *OIDCRealm) {
+	*out = *in
+	in.RP.DeepCopyInto(&out.RP)
+	out.OP = in.OP
+	out.Claims = in.Claims
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OIDCRealm.
+func (in *OIDCRealm) DeepCopy() *OIDCRealm {
+	if in == nil {
+		return nil
+	}
+	out := new(OIDCRealm)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OIDCRelyingParty) DeepCopyInto(out *OIDCRelyingParty) {
+	*out = *in
+	out.ClientSecretRef = in.ClientSecretRef
+	if in.RequestedScopes != nil {
+		in, out := &in.RequestedScopes, &out.RequestedScopes
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OIDCRelyingParty.
+func (in *OIDCRelyingParty) DeepCopy() *OIDCRelyingParty {
+	if in == nil {
+		return nil
+	}
+	out := new(OIDCRelyingParty)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Plugin) DeepCopyInto(out *Plugin) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Plugin.
+func (in *Plugin) DeepCopy() *Plugin {
+	if in == nil {
+		return nil
+	}
+	out := new(Plugin)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ReadinessProbe) DeepCopyInto(out *ReadinessProbe) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ReadinessProbe.
+func (in *ReadinessProbe) DeepCopy() *ReadinessProbe {
+	if in == nil {
+		return nil
+	}
+	out := new(ReadinessProbe)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RemoteCluster) DeepCopyInto(out *RemoteCluster) {
+	*out = *in
+	out.ElasticsearchRef = in.ElasticsearchRef
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RemoteCluster.
+func (in *RemoteCluster) DeepCopy() *RemoteCluster {
+	if in == nil {
+		return nil
+	}
+	out := new(RemoteCluster)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RestoreVerification) DeepCopyInto(out *RestoreVerification) {
+	*out = *in
+	if in.Indices != nil {
+		in, out := &in.Indices, &out.Indices
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.MinDocumentCount != nil {
+		in, out := &in.MinDocumentCount, &out.MinDocumentCount
+		*out = new(int64)
+		**out = **in
+	}
+	in.PodTemplate.DeepCopyInto(&out.PodTemplate)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RestoreVerification.
+func (in *RestoreVerification) DeepCopy() *RestoreVerification {
+	if in == nil {
+		return nil
+	}
+	out := new(RestoreVerification)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RestoreVerificationStatus) DeepCopyInto(out *RestoreVerificationStatus) {
+	*out = *in
+	if in.DocumentCount != nil {
+		in, out := &in.DocumentCount, &out.DocumentCount
+		*out = new(int64)
+		**out = **in
+	}
+	if in.StartTime != nil {
+		in, out := &in.StartTime, &out.StartTime
+		*out = (*in).DeepCopy()
+	}
+	if in.CompletionTime != nil {
+		in, out := &in.CompletionTime, &out.CompletionTime
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RestoreVerificationStatus.
+func (in *RestoreVerificationStatus) DeepCopy() *RestoreVerificationStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(RestoreVerificationStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RoleSource) DeepCopyInto(out *RoleSource) {
+	*out = *in
+	out.SecretRef = in.SecretRef
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RoleSource.
+func (in *RoleSource) DeepCopy() *RoleSource {
+	if in == nil {
+		return nil
+	}
+	out := new(RoleSource)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SAMLAttributes) DeepCopyInto(out *SAMLAttributes) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SAMLAttributes.
+func (in *SAMLAttributes) DeepCopy() *SAMLAttributes {
+	if in == nil {
+		return nil
+	}
+	out := new(SAMLAttributes)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SAMLIdentityProvider) DeepCopyInto(out *SAMLIdentityProvider) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SAMLIdentityProvider.
+func (in *SAMLIdentityProvider) DeepCopy() *SAMLIdentityProvider {
+	if in == nil {
+		return nil
+	}
+	out := new(SAMLIdentityProvider)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SAMLRealm) DeepCopyInto(out *SAMLRealm) {
+	*out = *in
+	out.IdP = in.IdP
+	out.SP = in.SP
+	out.Attributes = in.Attributes
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SAMLRealm.
+func (in *SAMLRealm) DeepCopy() *SAMLRealm {
+	if in == nil {
+		return nil
+	}
+	out := new(SAMLRealm)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SAMLServiceProvider) DeepCopyInto(out *SAMLServiceProvider) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SAMLServiceProvider.
+func (in *SAMLServiceProvider) DeepCopy() *SAMLServiceProvider {
+	if in == nil {
+		return nil
+	}
+	out := new(SAMLServiceProvider)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SnapshotRepository) DeepCopyInto(out *SnapshotRepository) {
+	*out = *in
+	if in.Settings != nil {
+		in, out := &in.Settings, &out.Settings
+		*out = (*in).DeepCopy()
+	}
+	if in.SecureSettings != nil {
+		in, out := &in.SecureSettings, &out.SecureSettings
+		*out = make([]commonv1.SecretSource, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Schedule != nil {
+		in, out := &in.Schedule, &out.Schedule
+		*out = new(SnapshotSchedule)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SnapshotRepository.
+func (in *SnapshotRepository) DeepCopy() *SnapshotRepository {
+	if in == nil {
+		return nil
+	}
+	out := new(SnapshotRepository)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SnapshotRepositoryStatus) DeepCopyInto(out *SnapshotRepositoryStatus) {
+	*out = *in
+	if in.LastSnapshotTime != nil {
+		in, out := &in.LastSnapshotTime, &out.LastSnapshotTime
+		*out = (*in).DeepCopy()
+	}
+	if in.LastSuccessTime != nil {
+		in, out := &in.LastSuccessTime, &out.LastSuccessTime
+		*out = (*in).DeepCopy()
+	}
+	if in.LastFailureTime != nil {
+		in, out := &in.LastFailureTime, &out.LastFailureTime
+		*out = (*in).DeepCopy()
+	}
+	if in.NextSnapshotTime != nil {
+		in, out := &in.NextSnapshotTime, &out.NextSnapshotTime
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SnapshotRepositoryStatus.
+func (in *SnapshotRepositoryStatus) DeepCopy() *SnapshotRepositoryStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(SnapshotRepositoryStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SnapshotRetention) DeepCopyInto(out *SnapshotRetention) {
+	*out = *in
+	if in.MinCount != nil {
+		in, out := &in.MinCount, &out.MinCount
+		*out = new(int32)
+		**out = **in
+	}
+	if in.MaxCount != nil {
+		in, out := &in.MaxCount, &out.MaxCount
+		*out = new(int32)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SnapshotRetention.
+func (in *SnapshotRetention) DeepCopy() *SnapshotRetention {
+	if in == nil {
+		return nil
+	}
+	out := new(SnapshotRetention)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SnapshotSchedule) DeepCopyInto(out *SnapshotSchedule) {
+	*out = *in
+	if in.Indices != nil {
+		in, out := &in.Indices, &out.Indices
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Retention != nil {
+		in, out := &in.Retention, &out.Retention
+		*out = new(SnapshotRetention)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SnapshotSchedule.
+func (in *SnapshotSchedule) DeepCopy() *SnapshotSchedule {
+	if in == nil {
+		return nil
+	}
+	out := new(SnapshotSchedule)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SnapshotVolume) DeepCopyInto(out *SnapshotVolume) {
+	*out = *in
+	if in.NFS != nil {
+		in, out := &in.NFS, &out.NFS
+		*out = new(corev1.NFSVolumeSource)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SnapshotVolume.
+func (in *SnapshotVolume) DeepCopy() *SnapshotVolume {
+	if in == nil {
+		return nil
+	}
+	out := new(SnapshotVolume)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TransportConfig) DeepCopyInto(out *TransportConfig) {
+	*out = *in
+	in.Service.DeepCopyInto(&out.Service)
+	in.TLS.DeepCopyInto(&out.TLS)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TransportConfig.
+func (in *TransportConfig) DeepCopy() *TransportConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(TransportConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TransportTLSOptions) DeepCopyInto(out *TransportTLSOptions) {
+	*out = *in
+	if in.SubjectAlternativeNames != nil {
+		in, out := &in.SubjectAlternativeNames, &out.SubjectAlternativeNames
+		*out = make([]commonv1.SubjectAlternativeName, len(*in))
+		copy(*out, *in)
+	}
+	out.Certificate = in.Certificate
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TransportTLSOptions.
+func (in *TransportTLSOptions) DeepCopy() *TransportTLSOptions {
+	if in == nil {
+		return nil
+	}
+	out := new(TransportTLSOptions)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *UpdateStrategy) DeepCopyInto(out *UpdateStrategy) {
+	*out = *in
+	in.ChangeBudget.DeepCopyInto(&out.ChangeBudget)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new UpdateStrategy.
+func (in *UpdateStrategy) DeepCopy() *UpdateStrategy {
 	if in == nil {
 		return nil
 	}
@@ -597,6 +1397,21 @@ func (in *UpgradeOperation) DeepCopy() *UpgradeOperation {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *UpgradeSnapshot) DeepCopyInto(out *UpgradeSnapshot) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new UpgradeSnapshot.
+func (in *UpgradeSnapshot) DeepCopy() *UpgradeSnapshot {
+	if in == nil {
+		return nil
+	}
+	out := new(UpgradeSnapshot)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *UpgradedNode) DeepCopyInto(out *UpgradedNode) {
 	*out = *in
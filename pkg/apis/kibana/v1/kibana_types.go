@@ -45,6 +45,8 @@ type Kibana struct {
 	assocConf *commonv1.AssociationConf `json:"-"`
 	// entAssocConf holds the configuration for the Enterprise Search association
 	entAssocConf *commonv1.AssociationConf `json:"-"`
+	// emsAssocConf holds the configuration for the Elastic Maps Server association
+	emsAssocConf *commonv1.AssociationConf `json:"-"`
 	// monitoringAssocConf holds the configuration for the monitoring Elasticsearch clusters association
 	monitoringAssocConfs map[commonv1.ObjectSelector]commonv1.AssociationConf `json:"-"`
 }
@@ -80,6 +82,12 @@ type KibanaSpec struct {
 	// Kibana provides the default Enterprise Search UI starting version 7.14.
 	EnterpriseSearchRef commonv1.ObjectSelector `json:"enterpriseSearchRef,omitempty"`
 
+	// ElasticMapsServerRef is a reference to an Elastic Maps Server running in the same Kubernetes cluster.
+	// Kibana trusts its CA certificate. Map tiles are fetched by the browsers of the Kibana users: set map.emsUrl in the
+	// Kibana configuration to the URL where they reach the Elastic Maps Server.
+	// +kubebuilder:validation:Optional
+	ElasticMapsServerRef commonv1.ObjectSelector `json:"elasticMapsServerRef,omitempty"`
+
 	// Config holds the Kibana configuration. See: https://www.elastic.co/guide/en/kibana/current/settings.html
 	// +kubebuilder:pruning:PreserveUnknownFields
 	Config *commonv1.Config `json:"config,omitempty"`
@@ -98,6 +106,10 @@ type KibanaSpec struct {
 	// SecureSettings is a list of references to Kubernetes secrets containing sensitive configuration options for Kibana.
 	SecureSettings []commonv1.SecretSource `json:"secureSettings,omitempty"`
 
+	// Plugins is a list of Kibana plugins installed before Kibana starts.
+	// +kubebuilder:validation:Optional
+	Plugins []Plugin `json:"plugins,omitempty"`
+
 	// ServiceAccountName is used to check access from the current resource to a resource (for ex. Elasticsearch) in a different namespace.
 	// Can only be used if ECK is enforcing RBAC on references.
 	// +optional
@@ -109,6 +121,35 @@ type KibanaSpec struct {
 	// Elasticsearch monitoring clusters running in the same Kubernetes cluster.
 	// +kubebuilder:validation:Optional
 	Monitoring commonv1.Monitoring `json:"monitoring,omitempty"`
+
+	// SavedObjects lists ConfigMaps holding saved objects, such as dashboards or data views, to import into Kibana
+	// through the saved objects API. Saved objects are imported again whenever the content of the ConfigMaps changes.
+	// Requires an elasticsearchRef to an Elasticsearch cluster managed by ECK.
+	// +kubebuilder:validation:Optional
+	SavedObjects []SavedObjectsSource `json:"savedObjects,omitempty"`
+}
+
+// SavedObjectsSource references a ConfigMap holding saved objects to import into Kibana.
+type SavedObjectsSource struct {
+	// ConfigMapName is the name of a ConfigMap in the namespace of the Kibana resource. Each entry of the ConfigMap holds
+	// saved objects in the NDJSON format produced by the Kibana saved objects export API.
+	ConfigMapName string `json:"configMapName"`
+	// Space is the identifier of the Kibana space to import the saved objects into. Defaults to the default space.
+	// +kubebuilder:validation:Optional
+	Space string `json:"space,omitempty"`
+}
+
+// Plugin declares a Kibana plugin to install.
+type Plugin struct {
+	// URL of the plugin archive. Archives made available in a volume mounted in the Pods can be installed with a
+	// file:// URL, for example in air-gapped environments.
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:MinLength=1
+	URL string `json:"url"`
+
+	// SHA512 is the SHA-512 checksum of the plugin archive, verified before installing the plugin.
+	// +kubebuilder:validation:Optional
+	SHA512 string `json:"sha512,omitempty"`
 }
 
 // KibanaStatus defines the observed state of Kibana
@@ -125,9 +166,16 @@ type KibanaStatus struct {
 	// EnterpriseSearchAssociationStatus is the status of any auto-linking to Enterprise Search.
 	EnterpriseSearchAssociationStatus commonv1.AssociationStatus `json:"enterpriseSearchAssociationStatus,omitempty"`
 
+	// ElasticMapsServerAssociationStatus is the status of any auto-linking to Elastic Maps Server.
+	ElasticMapsServerAssociationStatus commonv1.AssociationStatus `json:"elasticMapsServerAssociationStatus,omitempty"`
+
 	// MonitoringAssociationStatus is the status of any auto-linking to monitoring Elasticsearch clusters.
 	MonitoringAssociationStatus commonv1.AssociationStatusMap `json:"monitoringAssociationStatus,omitempty"`
 
+	// ConfigOverrides lists the settings of the Kibana configuration defined with different values by several sources,
+	// such as operator-managed settings overridden by the user configuration.
+	ConfigOverrides []string `json:"configOverrides,omitempty"`
+
 	// ObservedGeneration is the most recent generation observed for this Kibana instance.
 	// It corresponds to the metadata generation, which is updated on mutation by the API Server.
 	// If the generation observed in status diverges from the generation in metadata, the Kibana
@@ -171,6 +219,11 @@ func (k *Kibana) GetAssociations() []commonv1.Association {
 			Kibana: k,
 		})
 	}
+	if k.Spec.ElasticMapsServerRef.IsDefined() {
+		associations = append(associations, &KibanaEMSAssociation{
+			Kibana: k,
+		})
+	}
 	for _, ref := range k.Spec.Monitoring.Metrics.ElasticsearchRefs {
 		if ref.IsDefined() {
 			associations = append(associations, &KbMonitoringAssociation{
@@ -201,6 +254,10 @@ func (k *Kibana) AssociationStatusMap(typ commonv1.AssociationType) commonv1.Ass
 		if k.Spec.EnterpriseSearchRef.IsDefined() {
 			return commonv1.NewSingleAssociationStatusMap(k.Status.EnterpriseSearchAssociationStatus)
 		}
+	case commonv1.EMSAssociationType:
+		if k.Spec.ElasticMapsServerRef.IsDefined() {
+			return commonv1.NewSingleAssociationStatusMap(k.Status.ElasticMapsServerAssociationStatus)
+		}
 	case commonv1.KbMonitoringAssociationType:
 		for _, esRef := range k.Spec.Monitoring.Metrics.ElasticsearchRefs {
 			if esRef.IsDefined() {
@@ -236,6 +293,13 @@ func (k *Kibana) SetAssociationStatusMap(typ commonv1.AssociationType, status co
 		}
 		k.Status.EnterpriseSearchAssociationStatus = single
 		return nil
+	case commonv1.EMSAssociationType:
+		single, err := status.Single()
+		if err != nil {
+			return err
+		}
+		k.Status.ElasticMapsServerAssociationStatus = single
+		return nil
 	case commonv1.KbMonitoringAssociationType:
 		k.Status.MonitoringAssociationStatus = status
 		return nil
@@ -358,6 +422,57 @@ func (kbent *KibanaEntAssociation) AssociationID() string {
 	return commonv1.SingletonAssociationID
 }
 
+// -- association with Elastic Maps Server
+
+func (k *Kibana) EMSAssociation() *KibanaEMSAssociation {
+	return &KibanaEMSAssociation{Kibana: k}
+}
+
+// KibanaEMSAssociation helps to manage the Kibana / Elastic Maps Server association.
+type KibanaEMSAssociation struct {
+	*Kibana
+}
+
+var _ commonv1.Association = &KibanaEMSAssociation{}
+
+func (kbems *KibanaEMSAssociation) ElasticServiceAccount() (commonv1.ServiceAccountName, error) {
+	return "", nil
+}
+
+func (kbems *KibanaEMSAssociation) Associated() commonv1.Associated {
+	if kbems == nil {
+		return nil
+	}
+	if kbems.Kibana == nil {
+		kbems.Kibana = &Kibana{}
+	}
+	return kbems.Kibana
+}
+
+func (kbems *KibanaEMSAssociation) AssociationConfAnnotationName() string {
+	return commonv1.EMSConfigAnnotationNameBase
+}
+
+func (kbems *KibanaEMSAssociation) AssociationType() commonv1.AssociationType {
+	return commonv1.EMSAssociationType
+}
+
+func (kbems *KibanaEMSAssociation) AssociationRef() commonv1.ObjectSelector {
+	return kbems.Spec.ElasticMapsServerRef.WithDefaultNamespace(kbems.Namespace)
+}
+
+func (kbems *KibanaEMSAssociation) AssociationConf() (*commonv1.AssociationConf, error) {
+	return commonv1.GetAndSetAssociationConf(kbems, kbems.emsAssocConf)
+}
+
+func (kbems *KibanaEMSAssociation) SetAssociationConf(assocConf *commonv1.AssociationConf) {
+	kbems.emsAssocConf = assocConf
+}
+
+func (kbems *KibanaEMSAssociation) AssociationID() string {
+	return commonv1.SingletonAssociationID
+}
+
 // -- association with monitoring Elasticsearch clusters
 
 // KbMonitoringAssociation helps to manage the Kibana / monitoring Elasticsearch clusters association.
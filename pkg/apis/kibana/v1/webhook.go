@@ -6,6 +6,7 @@ package v1
 
 import (
 	"errors"
+	"strings"
 
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	runtime "k8s.io/apimachinery/pkg/runtime"
@@ -23,6 +24,9 @@ import (
 const (
 	// webhookPath is the HTTP path for the Kibana validating webhook.
 	webhookPath = "/validate-kibana-k8s-elastic-co-v1-kibana"
+
+	savedObjectsElasticsearchRefMsg = "Saved objects can only be imported into a Kibana associated with an Elasticsearch cluster managed by ECK"
+	pluginURLMsg                    = "Plugins must be installed from the URL of a plugin archive"
 )
 
 var (
@@ -35,6 +39,8 @@ var (
 		checkSupportedVersion,
 		checkMonitoring,
 		checkAssociations,
+		checkSavedObjects,
+		checkPlugins,
 	}
 
 	updateChecks = []func(old, curr *Kibana) field.ErrorList{
@@ -138,5 +144,48 @@ func checkAssociations(k *Kibana) field.ErrorList {
 	err2 := commonv1.CheckAssociationRefs(monitoringPath.Child("logs"), k.GetMonitoringLogsRefs()...)
 	err3 := commonv1.CheckAssociationRefs(field.NewPath("spec").Child("elasticsearchRef"), k.Spec.ElasticsearchRef)
 	err4 := commonv1.CheckAssociationRefs(field.NewPath("spec").Child("enterpriseSearchRef"), k.Spec.EnterpriseSearchRef)
-	return append(err1, append(err2, append(err3, err4...)...)...)
+	err5 := commonv1.CheckAssociationRefs(field.NewPath("spec").Child("elasticMapsServerRef"), k.Spec.ElasticMapsServerRef)
+	return append(err1, append(err2, append(err3, append(err4, err5...)...)...)...)
+}
+
+func checkSavedObjects(k *Kibana) field.ErrorList {
+	if len(k.Spec.SavedObjects) == 0 {
+		return nil
+	}
+	var errs field.ErrorList
+	path := field.NewPath("spec").Child("savedObjects")
+	// saved objects are imported with the credentials of the elastic user of the associated Elasticsearch cluster
+	if !k.Spec.ElasticsearchRef.IsDefined() || k.Spec.ElasticsearchRef.IsExternal() {
+		errs = append(errs, field.Invalid(field.NewPath("spec").Child("elasticsearchRef"), k.Spec.ElasticsearchRef, savedObjectsElasticsearchRefMsg))
+	}
+	seen := make(map[SavedObjectsSource]struct{}, len(k.Spec.SavedObjects))
+	for i, source := range k.Spec.SavedObjects {
+		if source.ConfigMapName == "" {
+			errs = append(errs, field.Required(path.Index(i).Child("configMapName"), "ConfigMap name is required"))
+			continue
+		}
+		if _, exists := seen[source]; exists {
+			errs = append(errs, field.Duplicate(path.Index(i), source.ConfigMapName))
+		}
+		seen[source] = struct{}{}
+	}
+	return errs
+}
+
+// checkPlugins checks that plugins are declared only once, and that they are installed from a URL since Kibana does
+// not have official plugins that can be installed by name.
+func checkPlugins(k *Kibana) field.ErrorList {
+	var errs field.ErrorList
+	urls := make(map[string]struct{}, len(k.Spec.Plugins))
+	for i, plugin := range k.Spec.Plugins {
+		path := field.NewPath("spec").Child("plugins").Index(i).Child("url")
+		if _, exists := urls[plugin.URL]; exists {
+			errs = append(errs, field.Duplicate(path, plugin.URL))
+		}
+		urls[plugin.URL] = struct{}{}
+		if !strings.Contains(plugin.URL, "://") {
+			errs = append(errs, field.Invalid(path, plugin.URL, pluginURLMsg))
+		}
+	}
+	return errs
 }
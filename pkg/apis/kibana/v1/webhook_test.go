@@ -318,6 +318,97 @@ func TestWebhook(t *testing.T) {
 				`spec.monitoring.logs: Forbidden: Invalid association reference: serviceName or namespace can only be used in combination with name, not with secretName`,
 			),
 		},
+		{
+			Name:      "saved-objects",
+			Operation: admissionv1beta1.Create,
+			Object: func(t *testing.T, uid string) []byte {
+				t.Helper()
+				kb := mkKibana(uid)
+				kb.Spec.ElasticsearchRef = commonv1.ObjectSelector{Name: "esname"}
+				kb.Spec.SavedObjects = []kbv1.SavedObjectsSource{
+					{ConfigMapName: "dashboards"},
+					{ConfigMapName: "dashboards", Space: "marketing"},
+				}
+				return serialize(t, kb)
+			},
+			Check: test.ValidationWebhookSucceeded,
+		},
+		{
+			Name:      "saved-objects-without-es-ref",
+			Operation: admissionv1beta1.Create,
+			Object: func(t *testing.T, uid string) []byte {
+				t.Helper()
+				kb := mkKibana(uid)
+				kb.Spec.SavedObjects = []kbv1.SavedObjectsSource{{ConfigMapName: "dashboards"}}
+				return serialize(t, kb)
+			},
+			Check: test.ValidationWebhookFailed(
+				`spec.elasticsearchRef: Invalid value`,
+			),
+		},
+		{
+			Name:      "saved-objects-with-external-es-ref",
+			Operation: admissionv1beta1.Create,
+			Object: func(t *testing.T, uid string) []byte {
+				t.Helper()
+				kb := mkKibana(uid)
+				kb.Spec.ElasticsearchRef = commonv1.ObjectSelector{SecretName: "esname"}
+				kb.Spec.SavedObjects = []kbv1.SavedObjectsSource{{ConfigMapName: "dashboards"}}
+				return serialize(t, kb)
+			},
+			Check: test.ValidationWebhookFailed(
+				`spec.elasticsearchRef: Invalid value`,
+			),
+		},
+		{
+			Name:      "duplicate-saved-objects",
+			Operation: admissionv1beta1.Create,
+			Object: func(t *testing.T, uid string) []byte {
+				t.Helper()
+				kb := mkKibana(uid)
+				kb.Spec.ElasticsearchRef = commonv1.ObjectSelector{Name: "esname"}
+				kb.Spec.SavedObjects = []kbv1.SavedObjectsSource{
+					{ConfigMapName: "dashboards"},
+					{ConfigMapName: "dashboards"},
+				}
+				return serialize(t, kb)
+			},
+			Check: test.ValidationWebhookFailed(
+				`spec.savedObjects\[1\]: Duplicate value: "dashboards"`,
+			),
+		},
+		{
+			Name:      "plugins",
+			Operation: admissionv1beta1.Create,
+			Object: func(t *testing.T, uid string) []byte {
+				t.Helper()
+				kb := mkKibana(uid)
+				kb.Spec.Plugins = []kbv1.Plugin{
+					{URL: "https://example.com/plugins/my-plugin-7.6.1.zip", SHA512: "abcd"},
+					{URL: "file:///mnt/plugins/other-plugin-7.6.1.zip"},
+				}
+				return serialize(t, kb)
+			},
+			Check: test.ValidationWebhookSucceeded,
+		},
+		{
+			Name:      "invalid-plugins",
+			Operation: admissionv1beta1.Create,
+			Object: func(t *testing.T, uid string) []byte {
+				t.Helper()
+				kb := mkKibana(uid)
+				kb.Spec.Plugins = []kbv1.Plugin{
+					{URL: "my-plugin"},
+					{URL: "file:///mnt/plugins/other-plugin-7.6.1.zip"},
+					{URL: "file:///mnt/plugins/other-plugin-7.6.1.zip"},
+				}
+				return serialize(t, kb)
+			},
+			Check: test.ValidationWebhookFailed(
+				`spec.plugins\[0\].url: Invalid value: "my-plugin"`,
+				`spec.plugins\[2\].url: Duplicate value: "file:///mnt/plugins/other-plugin-7.6.1.zip"`,
+			),
+		},
 	}
 
 	validator := &kbv1.Kibana{}
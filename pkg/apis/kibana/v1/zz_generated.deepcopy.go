@@ -52,6 +52,11 @@ func (in *Kibana) DeepCopyInto(out *Kibana) {
 		*out = new(commonv1.AssociationConf)
 		**out = **in
 	}
+	if in.emsAssocConf != nil {
+		in, out := &in.emsAssocConf, &out.emsAssocConf
+		*out = new(commonv1.AssociationConf)
+		**out = **in
+	}
 	if in.monitoringAssocConfs != nil {
 		in, out := &in.monitoringAssocConfs, &out.monitoringAssocConfs
 		*out = make(map[commonv1.ObjectSelector]commonv1.AssociationConf, len(*in))
@@ -79,6 +84,26 @@ func (in *Kibana) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KibanaEMSAssociation) DeepCopyInto(out *KibanaEMSAssociation) {
+	*out = *in
+	if in.Kibana != nil {
+		in, out := &in.Kibana, &out.Kibana
+		*out = new(Kibana)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KibanaEMSAssociation.
+func (in *KibanaEMSAssociation) DeepCopy() *KibanaEMSAssociation {
+	if in == nil {
+		return nil
+	}
+	out := new(KibanaEMSAssociation)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *KibanaEntAssociation) DeepCopyInto(out *KibanaEntAssociation) {
 	*out = *in
@@ -156,6 +181,7 @@ func (in *KibanaSpec) DeepCopyInto(out *KibanaSpec) {
 	*out = *in
 	out.ElasticsearchRef = in.ElasticsearchRef
 	out.EnterpriseSearchRef = in.EnterpriseSearchRef
+	out.ElasticMapsServerRef = in.ElasticMapsServerRef
 	if in.Config != nil {
 		in, out := &in.Config, &out.Config
 		*out = (*in).DeepCopy()
@@ -174,7 +200,17 @@ func (in *KibanaSpec) DeepCopyInto(out *KibanaSpec) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.Plugins != nil {
+		in, out := &in.Plugins, &out.Plugins
+		*out = make([]Plugin, len(*in))
+		copy(*out, *in)
+	}
 	in.Monitoring.DeepCopyInto(&out.Monitoring)
+	if in.SavedObjects != nil {
+		in, out := &in.SavedObjects, &out.SavedObjects
+		*out = make([]SavedObjectsSource, len(*in))
+		copy(*out, *in)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KibanaSpec.
@@ -198,6 +234,11 @@ func (in *KibanaStatus) DeepCopyInto(out *KibanaStatus) {
 			(*out)[key] = val
 		}
 	}
+	if in.ConfigOverrides != nil {
+		in, out := &in.ConfigOverrides, &out.ConfigOverrides
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KibanaStatus.
@@ -209,3 +250,33 @@ func (in *KibanaStatus) DeepCopy() *KibanaStatus {
 	in.DeepCopyInto(out)
 	return out
 }
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Plugin) DeepCopyInto(out *Plugin) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Plugin.
+func (in *Plugin) DeepCopy() *Plugin {
+	if in == nil {
+		return nil
+	}
+	out := new(Plugin)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SavedObjectsSource) DeepCopyInto(out *SavedObjectsSource) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SavedObjectsSource.
+func (in *SavedObjectsSource) DeepCopy() *SavedObjectsSource {
+	if in == nil {
+		return nil
+	}
+	out := new(SavedObjectsSource)
+	in.DeepCopyInto(out)
+	return out
+}
@@ -0,0 +1,241 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package v1alpha1
+
+import (
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	commonv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/common/v1"
+)
+
+const (
+	LogstashContainerName = "logstash"
+	// Kind is inferred from the struct name using reflection in SchemeBuilder.Register()
+	// we duplicate it as a constant here for practical purposes.
+	Kind = "Logstash"
+)
+
+// LogstashSpec defines the desired state of Logstash
+type LogstashSpec struct {
+	// Version of Logstash.
+	Version string `json:"version"`
+
+	// Image is the Logstash Docker image to deploy. Version and Type have to match the Logstash in the image.
+	// +kubebuilder:validation:Optional
+	Image string `json:"image,omitempty"`
+
+	// Count of Logstash instances to deploy.
+	Count int32 `json:"count,omitempty"`
+
+	// ElasticsearchRef is a reference to the Elasticsearch cluster Logstash pipelines send data to. The connection
+	// settings are made available to the Logstash pipelines through the ELASTICSEARCH_HOSTS, ELASTICSEARCH_USERNAME,
+	// ELASTICSEARCH_PASSWORD and ELASTICSEARCH_SSL_CERTIFICATE_AUTHORITY environment variables.
+	// +kubebuilder:validation:Optional
+	ElasticsearchRef commonv1.ObjectSelector `json:"elasticsearchRef,omitempty"`
+
+	// Config holds the Logstash configuration. At most one of [`Config`, `ConfigRef`] can be specified.
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:pruning:PreserveUnknownFields
+	Config *commonv1.Config `json:"config,omitempty"`
+
+	// ConfigRef contains a reference to an existing Kubernetes Secret holding the Logstash configuration.
+	// Logstash settings must be specified as yaml, under a single "logstash.yml" entry. At most one of [`Config`, `ConfigRef`]
+	// can be specified.
+	// +kubebuilder:validation:Optional
+	ConfigRef *commonv1.ConfigSource `json:"configRef,omitempty"`
+
+	// Pipelines holds the Logstash Pipelines. At most one of [`Pipelines`, `PipelinesRef`] can be specified.
+	// Changes to the pipelines are reloaded by Logstash without restarting the Pods.
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:pruning:PreserveUnknownFields
+	Pipelines []commonv1.Config `json:"pipelines,omitempty"`
+
+	// PipelinesRef contains a reference to an existing Kubernetes Secret holding the Logstash Pipelines.
+	// Logstash pipelines must be specified as yaml, under a single "pipelines.yml" entry. At most one of [`Pipelines`, `PipelinesRef`]
+	// can be specified.
+	// +kubebuilder:validation:Optional
+	PipelinesRef *commonv1.ConfigSource `json:"pipelinesRef,omitempty"`
+
+	// Services contains details of services that Logstash should expose - similar to the HTTP layer configuration for the
+	// rest of the stack, but also applicable for more use cases than the metrics API, as logstash may need to
+	// be opened up for other services: Beats, TCP, UDP, etc, inputs.
+	// +kubebuilder:validation:Optional
+	Services []LogstashService `json:"services,omitempty"`
+
+	// PodTemplate provides customisation options for the Logstash pods.
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:pruning:PreserveUnknownFields
+	PodTemplate corev1.PodTemplateSpec `json:"podTemplate,omitempty"`
+
+	// RevisionHistoryLimit is the number of revisions to retain to allow rollback in the underlying StatefulSet.
+	RevisionHistoryLimit *int32 `json:"revisionHistoryLimit,omitempty"`
+
+	// ServiceAccountName is used to check access from the current resource to an Elasticsearch resource in a different namespace.
+	// Can only be used if ECK is enforcing RBAC on references.
+	// +optional
+	ServiceAccountName string `json:"serviceAccountName,omitempty"`
+}
+
+// LogstashService is a Service exposing the Pods of a Logstash resource, for example to receive events from Beats.
+type LogstashService struct {
+	// Name of the Service, which is used as a suffix of the name of the created Kubernetes Service.
+	Name string `json:"name,omitempty"`
+	// Service defines the template for the associated Kubernetes Service object. The ports of the Service must
+	// match the ports of the Logstash inputs.
+	Service commonv1.ServiceTemplate `json:"service,omitempty"`
+}
+
+// LogstashStatus defines the observed state of Logstash
+type LogstashStatus struct {
+	commonv1.DeploymentStatus `json:",inline"`
+
+	ElasticsearchAssociationStatus commonv1.AssociationStatus `json:"elasticsearchAssociationStatus,omitempty"`
+
+	// Pipelines is the status of the Logstash pipelines, as reported by the Logstash API of the ready Pods.
+	Pipelines []PipelineStatus `json:"pipelines,omitempty"`
+
+	// ObservedGeneration is the most recent generation observed for this Logstash instance.
+	// It corresponds to the metadata generation, which is updated on mutation by the API Server.
+	// If the generation observed in status diverges from the generation in metadata, the Logstash
+	// controller has not yet processed the changes contained in the Logstash specification.
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+}
+
+// PipelineStatus is the status of a Logstash pipeline, aggregated over the ready Logstash Pods.
+type PipelineStatus struct {
+	// ID of the pipeline.
+	ID string `json:"id"`
+
+	// RunningPods is the number of Pods running the pipeline.
+	RunningPods int32 `json:"runningPods"`
+
+	// ReloadFailedPods is the number of Pods where the last reload of the pipeline failed. These Pods keep running the
+	// previous version of the pipeline.
+	ReloadFailedPods int32 `json:"reloadFailedPods,omitempty"`
+
+	// LastReloadError is the error message of the last failed reload of the pipeline, if any.
+	LastReloadError string `json:"lastReloadError,omitempty"`
+}
+
+// IsMarkedForDeletion returns true if the Logstash instance is going to be deleted
+func (l *Logstash) IsMarkedForDeletion() bool {
+	return !l.DeletionTimestamp.IsZero()
+}
+
+func (l *Logstash) Associated() commonv1.Associated {
+	return l
+}
+
+func (l *Logstash) AssociationConfAnnotationName() string {
+	return commonv1.ElasticsearchConfigAnnotationNameBase
+}
+
+func (l *Logstash) AssociationType() commonv1.AssociationType {
+	return commonv1.ElasticsearchAssociationType
+}
+
+func (l *Logstash) AssociationRef() commonv1.ObjectSelector {
+	return l.Spec.ElasticsearchRef.WithDefaultNamespace(l.Namespace)
+}
+
+func (l *Logstash) ServiceAccountName() string {
+	return l.Spec.ServiceAccountName
+}
+
+func (l *Logstash) AssociationConf() (*commonv1.AssociationConf, error) {
+	return commonv1.GetAndSetAssociationConf(l, l.assocConf)
+}
+
+func (l *Logstash) SetAssociationConf(assocConf *commonv1.AssociationConf) {
+	l.assocConf = assocConf
+}
+
+// RequiresAssociation returns true if the spec specifies an Elasticsearch reference.
+func (l *Logstash) RequiresAssociation() bool {
+	return l.Spec.ElasticsearchRef.IsDefined()
+}
+
+func (l *Logstash) AssociationStatusMap(typ commonv1.AssociationType) commonv1.AssociationStatusMap {
+	if typ == commonv1.ElasticsearchAssociationType && l.Spec.ElasticsearchRef.IsDefined() {
+		return commonv1.NewSingleAssociationStatusMap(l.Status.ElasticsearchAssociationStatus)
+	}
+
+	return commonv1.AssociationStatusMap{}
+}
+
+func (l *Logstash) SetAssociationStatusMap(typ commonv1.AssociationType, status commonv1.AssociationStatusMap) error {
+	single, err := status.Single()
+	if err != nil {
+		return err
+	}
+
+	if typ != commonv1.ElasticsearchAssociationType {
+		return fmt.Errorf("association type %s not known", typ)
+	}
+
+	l.Status.ElasticsearchAssociationStatus = single
+	return nil
+}
+
+func (l *Logstash) ElasticServiceAccount() (commonv1.ServiceAccountName, error) {
+	return "", nil
+}
+
+func (l *Logstash) GetAssociations() []commonv1.Association {
+	associations := make([]commonv1.Association, 0)
+	if l.Spec.ElasticsearchRef.IsDefined() {
+		associations = append(associations, l)
+	}
+	return associations
+}
+
+func (l *Logstash) AssociationID() string {
+	return commonv1.SingletonAssociationID
+}
+
+var _ commonv1.Associated = &Logstash{}
+var _ commonv1.Association = &Logstash{}
+
+// GetObservedGeneration will return the observed generation from the Logstash status.
+func (l *Logstash) GetObservedGeneration() int64 {
+	return l.Status.ObservedGeneration
+}
+
+// +kubebuilder:object:root=true
+
+// Logstash is the Schema for the logstashes API
+// +kubebuilder:resource:categories=elastic,shortName=ls
+// +kubebuilder:subresource:status
+// +kubebuilder:printcolumn:name="health",type="string",JSONPath=".status.health"
+// +kubebuilder:printcolumn:name="available",type="integer",JSONPath=".status.availableNodes",description="Available nodes"
+// +kubebuilder:printcolumn:name="expected",type="integer",JSONPath=".spec.count",description="Expected nodes"
+// +kubebuilder:printcolumn:name="age",type="date",JSONPath=".metadata.creationTimestamp"
+// +kubebuilder:printcolumn:name="version",type="string",JSONPath=".status.version",description="Logstash version"
+// +kubebuilder:subresource:scale:specpath=.spec.count,statuspath=.status.count,selectorpath=.status.selector
+// +kubebuilder:storageversion
+type Logstash struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec      LogstashSpec              `json:"spec,omitempty"`
+	Status    LogstashStatus            `json:"status,omitempty"`
+	assocConf *commonv1.AssociationConf `json:"-"`
+}
+
+// +kubebuilder:object:root=true
+
+// LogstashList contains a list of Logstash
+type LogstashList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []Logstash `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&Logstash{}, &LogstashList{})
+}
@@ -0,0 +1,111 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package v1alpha1
+
+import (
+	"fmt"
+
+	"k8s.io/apimachinery/pkg/util/validation"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+
+	commonv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/common/v1"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/version"
+)
+
+const (
+	// APIServiceName is the name of the Service always created by the operator to expose the Logstash API.
+	APIServiceName = "api"
+)
+
+var (
+	defaultChecks = []func(*Logstash) field.ErrorList{
+		checkNoUnknownFields,
+		checkNameLength,
+		checkSupportedVersion,
+		checkSingleConfigSource,
+		checkSinglePipelinesSource,
+		checkServices,
+		checkAssociations,
+	}
+
+	updateChecks = []func(old, curr *Logstash) field.ErrorList{
+		checkNoDowngrade,
+	}
+)
+
+func checkNoUnknownFields(l *Logstash) field.ErrorList {
+	return commonv1.NoUnknownFields(l, l.ObjectMeta)
+}
+
+func checkNameLength(l *Logstash) field.ErrorList {
+	return commonv1.CheckNameLength(l)
+}
+
+func checkSupportedVersion(l *Logstash) field.ErrorList {
+	return commonv1.CheckSupportedStackVersion(l.Spec.Version, version.SupportedLogstashVersions)
+}
+
+func checkNoDowngrade(prev, curr *Logstash) field.ErrorList {
+	if commonv1.IsConfiguredToAllowDowngrades(curr) {
+		return nil
+	}
+	return commonv1.CheckNoDowngrade(prev.Spec.Version, curr.Spec.Version)
+}
+
+func checkSingleConfigSource(l *Logstash) field.ErrorList {
+	if l.Spec.Config != nil && l.Spec.ConfigRef != nil {
+		msg := "Specify at most one of [`config`, `configRef`], not both"
+		return field.ErrorList{
+			field.Forbidden(field.NewPath("spec").Child("config"), msg),
+			field.Forbidden(field.NewPath("spec").Child("configRef"), msg),
+		}
+	}
+
+	return nil
+}
+
+func checkSinglePipelinesSource(l *Logstash) field.ErrorList {
+	if l.Spec.Pipelines != nil && l.Spec.PipelinesRef != nil {
+		msg := "Specify at most one of [`pipelines`, `pipelinesRef`], not both"
+		return field.ErrorList{
+			field.Forbidden(field.NewPath("spec").Child("pipelines"), msg),
+			field.Forbidden(field.NewPath("spec").Child("pipelinesRef"), msg),
+		}
+	}
+
+	return nil
+}
+
+// checkServices checks that the Services are named, that their names are unique and do not conflict with the API
+// Service, and that they expose at least one port.
+func checkServices(l *Logstash) field.ErrorList {
+	var errs field.ErrorList
+	names := make(map[string]struct{}, len(l.Spec.Services))
+	for i, svc := range l.Spec.Services {
+		path := field.NewPath("spec").Child("services").Index(i)
+		switch {
+		case svc.Name == "":
+			errs = append(errs, field.Required(path.Child("name"), "Service name is required"))
+		case svc.Name == APIServiceName:
+			errs = append(errs, field.Invalid(path.Child("name"), svc.Name, fmt.Sprintf("%s is reserved for the Logstash API Service", APIServiceName)))
+		default:
+			for _, msg := range validation.IsDNS1035Label(svc.Name) {
+				errs = append(errs, field.Invalid(path.Child("name"), svc.Name, msg))
+			}
+		}
+		if _, exists := names[svc.Name]; exists {
+			errs = append(errs, field.Duplicate(path.Child("name"), svc.Name))
+		}
+		names[svc.Name] = struct{}{}
+		if len(svc.Service.Spec.Ports) == 0 {
+			errs = append(errs, field.Required(path.Child("service", "spec", "ports"), "At least one port must be exposed"))
+		}
+	}
+	return errs
+}
+
+func checkAssociations(l *Logstash) field.ErrorList {
+	return commonv1.CheckAssociationRefs(field.NewPath("spec").Child("elasticsearchRef"), l.Spec.ElasticsearchRef)
+}
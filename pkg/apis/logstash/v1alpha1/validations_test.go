@@ -0,0 +1,143 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package v1alpha1
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	commonv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/common/v1"
+)
+
+func beatsService(name string) LogstashService {
+	return LogstashService{
+		Name: name,
+		Service: commonv1.ServiceTemplate{
+			Spec: corev1.ServiceSpec{Ports: []corev1.ServicePort{{Name: "beats", Port: 5044}}},
+		},
+	}
+}
+
+func Test_checkServices(t *testing.T) {
+	tests := []struct {
+		name     string
+		services []LogstashService
+		wantErr  bool
+	}{
+		{
+			name: "no services",
+		},
+		{
+			name:     "valid services",
+			services: []LogstashService{beatsService("beats"), beatsService("tcp")},
+		},
+		{
+			name:     "missing name",
+			services: []LogstashService{beatsService("")},
+			wantErr:  true,
+		},
+		{
+			name:     "reserved name",
+			services: []LogstashService{beatsService(APIServiceName)},
+			wantErr:  true,
+		},
+		{
+			name:     "invalid name",
+			services: []LogstashService{beatsService("Beats_Input")},
+			wantErr:  true,
+		},
+		{
+			name:     "duplicate name",
+			services: []LogstashService{beatsService("beats"), beatsService("beats")},
+			wantErr:  true,
+		},
+		{
+			name:     "no port",
+			services: []LogstashService{{Name: "beats"}},
+			wantErr:  true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := checkServices(&Logstash{Spec: LogstashSpec{Services: tt.services}})
+			require.Equal(t, tt.wantErr, len(got) > 0)
+		})
+	}
+}
+
+func TestLogstash_validate(t *testing.T) {
+	tests := []struct {
+		name    string
+		ls      Logstash
+		old     *Logstash
+		wantErr bool
+	}{
+		{
+			name: "valid",
+			ls: Logstash{
+				ObjectMeta: metav1.ObjectMeta{Name: "ls"},
+				Spec: LogstashSpec{
+					Version:          "8.6.0",
+					ElasticsearchRef: commonv1.ObjectSelector{Name: "es"},
+					Pipelines:        []commonv1.Config{{Data: map[string]interface{}{"pipeline.id": "main"}}},
+					Services:         []LogstashService{beatsService("beats")},
+				},
+			},
+		},
+		{
+			name: "unsupported version",
+			ls: Logstash{
+				ObjectMeta: metav1.ObjectMeta{Name: "ls"},
+				Spec:       LogstashSpec{Version: "7.10.0"},
+			},
+			wantErr: true,
+		},
+		{
+			name: "config and configRef",
+			ls: Logstash{
+				ObjectMeta: metav1.ObjectMeta{Name: "ls"},
+				Spec: LogstashSpec{
+					Version:   "8.6.0",
+					Config:    &commonv1.Config{Data: map[string]interface{}{"log.level": "debug"}},
+					ConfigRef: &commonv1.ConfigSource{SecretRef: commonv1.SecretRef{SecretName: "config"}},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "pipelines and pipelinesRef",
+			ls: Logstash{
+				ObjectMeta: metav1.ObjectMeta{Name: "ls"},
+				Spec: LogstashSpec{
+					Version:      "8.6.0",
+					Pipelines:    []commonv1.Config{{Data: map[string]interface{}{"pipeline.id": "main"}}},
+					PipelinesRef: &commonv1.ConfigSource{SecretRef: commonv1.SecretRef{SecretName: "pipelines"}},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "downgrade",
+			ls: Logstash{
+				ObjectMeta: metav1.ObjectMeta{Name: "ls"},
+				Spec:       LogstashSpec{Version: "8.6.0"},
+			},
+			old: &Logstash{
+				ObjectMeta: metav1.ObjectMeta{Name: "ls"},
+				Spec:       LogstashSpec{Version: "8.7.0"},
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.ls.validate(tt.old)
+			require.Equal(t, tt.wantErr, err != nil, "unexpected validation result: %v", err)
+		})
+	}
+}
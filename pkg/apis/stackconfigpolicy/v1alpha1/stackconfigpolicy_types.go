@@ -0,0 +1,191 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package v1alpha1
+
+import (
+	"fmt"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	commonv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/common/v1"
+)
+
+const (
+	// Kind is inferred from the struct name using reflection in SchemeBuilder.Register()
+	// we duplicate it as a constant here for practical purposes.
+	Kind = "StackConfigPolicy"
+)
+
+func init() {
+	SchemeBuilder.Register(&StackConfigPolicy{}, &StackConfigPolicyList{})
+}
+
+// +kubebuilder:object:root=true
+
+// StackConfigPolicy represents a StackConfigPolicy resource in a Kubernetes cluster.
+// +kubebuilder:resource:categories=elastic,shortName=scp
+// +kubebuilder:subresource:status
+// +kubebuilder:printcolumn:name="Ready",type="string",JSONPath=".status.readyCount",description="Resources configured"
+// +kubebuilder:printcolumn:name="Phase",type="string",JSONPath=".status.phase"
+// +kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp"
+// +kubebuilder:storageversion
+type StackConfigPolicy struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   StackConfigPolicySpec   `json:"spec,omitempty"`
+	Status StackConfigPolicyStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// StackConfigPolicyList contains a list of StackConfigPolicy resources.
+type StackConfigPolicyList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []StackConfigPolicy `json:"items"`
+}
+
+// StackConfigPolicySpec holds the specification of a StackConfigPolicy.
+type StackConfigPolicySpec struct {
+	// ResourceSelector is a label selector for the resources to which the policy applies. An empty selector selects
+	// all the resources of the namespace of the policy, or of all the managed namespaces if the policy is created in
+	// the namespace of the operator.
+	ResourceSelector metav1.LabelSelector `json:"resourceSelector,omitempty"`
+	// Elasticsearch holds the configuration applied to the selected Elasticsearch clusters.
+	Elasticsearch ElasticsearchConfigPolicySpec `json:"elasticsearch,omitempty"`
+}
+
+// ElasticsearchConfigPolicySpec holds the configuration applied through the Elasticsearch API to the Elasticsearch
+// clusters selected by a StackConfigPolicy. Except for the cluster settings, each field maps the names of resources to
+// their definition, in the format expected by the corresponding Elasticsearch API.
+type ElasticsearchConfigPolicySpec struct {
+	// ClusterSettings holds the persistent cluster settings.
+	// See: https://www.elastic.co/guide/en/elasticsearch/reference/current/cluster-update-settings.html
+	// +kubebuilder:pruning:PreserveUnknownFields
+	ClusterSettings *commonv1.Config `json:"clusterSettings,omitempty"`
+	// SnapshotRepositories holds the snapshot repositories, with their type and settings.
+	// See: https://www.elastic.co/guide/en/elasticsearch/reference/current/put-snapshot-repo-api.html
+	// +kubebuilder:pruning:PreserveUnknownFields
+	SnapshotRepositories *commonv1.Config `json:"snapshotRepositories,omitempty"`
+	// SecurityRoleMappings holds the role mappings.
+	// See: https://www.elastic.co/guide/en/elasticsearch/reference/current/security-api-put-role-mapping.html
+	// +kubebuilder:pruning:PreserveUnknownFields
+	SecurityRoleMappings *commonv1.Config `json:"securityRoleMappings,omitempty"`
+	// IndexLifecyclePolicies holds the index lifecycle management policies.
+	// See: https://www.elastic.co/guide/en/elasticsearch/reference/current/ilm-put-lifecycle.html
+	// +kubebuilder:pruning:PreserveUnknownFields
+	IndexLifecyclePolicies *commonv1.Config `json:"indexLifecyclePolicies,omitempty"`
+	// IngestPipelines holds the ingest pipelines.
+	// See: https://www.elastic.co/guide/en/elasticsearch/reference/current/put-pipeline-api.html
+	// +kubebuilder:pruning:PreserveUnknownFields
+	IngestPipelines *commonv1.Config `json:"ingestPipelines,omitempty"`
+	// IndexTemplates holds the component and composable index templates.
+	IndexTemplates IndexTemplates `json:"indexTemplates,omitempty"`
+}
+
+// IndexTemplates holds the index templates applied by a StackConfigPolicy.
+type IndexTemplates struct {
+	// ComponentTemplates holds the component templates.
+	// See: https://www.elastic.co/guide/en/elasticsearch/reference/current/put-component-template.html
+	// +kubebuilder:pruning:PreserveUnknownFields
+	ComponentTemplates *commonv1.Config `json:"componentTemplates,omitempty"`
+	// ComposableIndexTemplates holds the composable index templates.
+	// See: https://www.elastic.co/guide/en/elasticsearch/reference/current/index-templates.html
+	// +kubebuilder:pruning:PreserveUnknownFields
+	ComposableIndexTemplates *commonv1.Config `json:"composableIndexTemplates,omitempty"`
+}
+
+// PolicyPhase is the phase of a StackConfigPolicy, or of its application to a resource.
+type PolicyPhase string
+
+const (
+	// ReadyPhase is the phase of a policy applied to all the selected resources.
+	ReadyPhase PolicyPhase = "Ready"
+	// ApplyingChangesPhase is the phase of a policy which is not applied yet, for example because the resource is not
+	// reachable.
+	ApplyingChangesPhase PolicyPhase = "ApplyingChanges"
+	// ErrorPhase is the phase of a policy which could not be applied.
+	ErrorPhase PolicyPhase = "Error"
+	// ConflictPhase is the phase of a policy selecting resources which are also selected by another policy. The
+	// policies are not applied to those resources.
+	ConflictPhase PolicyPhase = "Conflict"
+	// InvalidPhase is the phase of a policy whose specification is invalid.
+	InvalidPhase PolicyPhase = "Invalid"
+)
+
+// StackConfigPolicyStatus defines the observed state of a StackConfigPolicy.
+type StackConfigPolicyStatus struct {
+	// ResourcesStatuses holds the status of the application of the policy to each selected resource, indexed by
+	// namespace/name.
+	ResourcesStatuses map[string]ResourcePolicyStatus `json:"resourcesStatuses,omitempty"`
+	// Resources is the number of resources selected by the policy.
+	Resources int `json:"resources,omitempty"`
+	// Ready is the number of resources to which the policy is applied.
+	Ready int `json:"ready,omitempty"`
+	// Errors is the number of resources to which the policy could not be applied.
+	Errors int `json:"errors,omitempty"`
+	// ReadyCount is a human representation of the number of resources to which the policy is applied.
+	ReadyCount string `json:"readyCount,omitempty"`
+	// Phase is the phase of the policy, derived from the phase of its application to each resource.
+	Phase PolicyPhase `json:"phase,omitempty"`
+	// ObservedGeneration is the most recent generation observed for this StackConfigPolicy.
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+}
+
+// ResourcePolicyStatus is the status of the application of a policy to a resource.
+type ResourcePolicyStatus struct {
+	Phase PolicyPhase `json:"phase,omitempty"`
+	// Error is the reason why the policy could not be applied to the resource.
+	Error string `json:"error,omitempty"`
+}
+
+// NewStatus returns an empty status for the given policy.
+func NewStatus(policy StackConfigPolicy) StackConfigPolicyStatus {
+	return StackConfigPolicyStatus{
+		ResourcesStatuses:  map[string]ResourcePolicyStatus{},
+		ReadyCount:         "0/0",
+		Phase:              ReadyPhase,
+		ObservedGeneration: policy.Generation,
+	}
+}
+
+// AddResourceStatus records the status of the application of the policy to the given resource, and updates the
+// counters and the phase of the policy accordingly.
+func (s *StackConfigPolicyStatus) AddResourceStatus(resource types.NamespacedName, status ResourcePolicyStatus) {
+	if s.ResourcesStatuses == nil {
+		s.ResourcesStatuses = map[string]ResourcePolicyStatus{}
+	}
+	s.ResourcesStatuses[resource.String()] = status
+	s.Resources = len(s.ResourcesStatuses)
+	s.Ready, s.Errors = 0, 0
+	for _, status := range s.ResourcesStatuses {
+		switch status.Phase {
+		case ReadyPhase:
+			s.Ready++
+		case ErrorPhase, ConflictPhase:
+			s.Errors++
+		}
+	}
+	s.ReadyCount = fmt.Sprintf("%d/%d", s.Ready, s.Resources)
+	if phasePriority[status.Phase] > phasePriority[s.Phase] {
+		s.Phase = status.Phase
+	}
+}
+
+// phasePriority orders the phases of the resources, the phase of the policy is the highest phase of its resources.
+var phasePriority = map[PolicyPhase]int{
+	ReadyPhase:           0,
+	ApplyingChangesPhase: 1,
+	ErrorPhase:           2,
+	ConflictPhase:        3,
+	InvalidPhase:         4,
+}
+
+// IsMarkedForDeletion returns true if the StackConfigPolicy is going to be deleted.
+func (p *StackConfigPolicy) IsMarkedForDeletion() bool {
+	return !p.DeletionTimestamp.IsZero()
+}
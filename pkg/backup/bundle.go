@@ -0,0 +1,269 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package backup
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	agentv1alpha1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/agent/v1alpha1"
+	apmv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/apm/v1"
+	easv1alpha1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/autoscaling/v1alpha1"
+	beatv1beta1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/beat/v1beta1"
+	commonv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/common/v1"
+	esv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/elasticsearch/v1"
+	entv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/enterprisesearch/v1"
+	kbv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/kibana/v1"
+	lsv1alpha1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/logstash/v1alpha1"
+	emsv1alpha1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/maps/v1alpha1"
+	policyv1alpha1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/stackconfigpolicy/v1alpha1"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/annotation"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/certificates"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/labels"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/elasticsearch/bootstrap"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/elasticsearch/clustersettings"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/elasticsearch/hints"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/elasticsearch/remotecluster"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/elasticsearch/snapshot"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/elasticsearch/version/zen2"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/utils/k8s"
+)
+
+// BundleVersion is the version of the format of the backup bundles. It must be increased on any breaking change of the
+// format, so that bundles can be read back by later versions of the operator.
+const BundleVersion = "v1"
+
+// ManagedKinds are the kinds of the resources managed by the operator which are included in the backup bundles, in the
+// order in which they should be recreated.
+var ManagedKinds = []schema.GroupVersionKind{
+	esv1.GroupVersion.WithKind(esv1.Kind),
+	easv1alpha1.GroupVersion.WithKind(easv1alpha1.Kind),
+	kbv1.GroupVersion.WithKind(kbv1.Kind),
+	apmv1.GroupVersion.WithKind(apmv1.Kind),
+	entv1.GroupVersion.WithKind(entv1.Kind),
+	beatv1beta1.GroupVersion.WithKind(beatv1beta1.Kind),
+	agentv1alpha1.GroupVersion.WithKind(agentv1alpha1.Kind),
+	emsv1alpha1.GroupVersion.WithKind(emsv1alpha1.Kind),
+	lsv1alpha1.GroupVersion.WithKind(lsv1alpha1.Kind),
+	policyv1alpha1.GroupVersion.WithKind(policyv1alpha1.Kind),
+}
+
+// operatorStateAnnotations are the annotations holding the state of the operator for a given cluster. They are removed
+// from the exported resources: the recreated resources are new clusters from the operator point of view, for example
+// the cluster UUID annotation would prevent a new Elasticsearch cluster from bootstrapping.
+var operatorStateAnnotations = []string{
+	bootstrap.ClusterUUIDAnnotationName,
+	zen2.InitialMasterNodesAnnotation,
+	remotecluster.ManagedRemoteClustersAnnotationName,
+	clustersettings.ManagedClusterSettingsAnnotationName,
+	snapshot.ManagedRepositoriesAnnotation,
+	esv1.SecureSettingsReloadAnnotation,
+	hints.OrchestrationsHintsAnnotation,
+	common.OperatorVersionAnnotation,
+	annotation.CurrAssocStatusAnnotation,
+	annotation.PrevAssocStatusAnnotation,
+	corev1.LastAppliedConfigAnnotation,
+}
+
+// operatorStateAnnotationPrefixes are the prefixes of the annotations holding the configuration of the associations,
+// whose names are suffixed with the ID of the association when a resource has several associations of the same type.
+// The other annotations of the association domain, such as the ones set by users to request an API key or the rotation
+// of a service account token, are kept.
+var operatorStateAnnotationPrefixes = []string{
+	commonv1.ElasticsearchConfigAnnotationNameBase,
+	commonv1.KibanaConfigAnnotationNameBase,
+	commonv1.EntConfigAnnotationNameBase,
+	commonv1.EMSConfigAnnotationNameBase,
+	commonv1.FleetServerConfigAnnotationNameBase,
+	commonv1.BeatConfigAnnotationNameBase,
+}
+
+// Bundle is a backup of the resources managed by the operator, from which they can be recreated in another Kubernetes
+// cluster along with their certificate authorities.
+type Bundle struct {
+	// Version of the format of the bundle.
+	Version string `json:"version"`
+	// OperatorVersion is the version of the operator which created the bundle.
+	OperatorVersion string `json:"operatorVersion"`
+	// CreatedAt is the time the bundle was created.
+	CreatedAt time.Time `json:"createdAt"`
+	// Resources are the resources managed by the operator, without their status and server-side metadata.
+	Resources []unstructured.Unstructured `json:"resources"`
+	// CertificateAuthorities are the Secrets holding the internal certificate authorities of the managed resources.
+	// Recreating them before the resources lets the operator reuse them, so that clients trusting the certificates of the
+	// original resources also trust the certificates of the recreated ones.
+	CertificateAuthorities []corev1.Secret `json:"certificateAuthorities"`
+	// Secrets describes the other Secrets of the namespaces of the managed resources, without their data.
+	Secrets []SecretMetadata `json:"secrets"`
+}
+
+// SecretMetadata describes a Secret without its data.
+type SecretMetadata struct {
+	Namespace string            `json:"namespace"`
+	Name      string            `json:"name"`
+	Type      corev1.SecretType `json:"type,omitempty"`
+	Labels    map[string]string `json:"labels,omitempty"`
+	// Keys of the data of the Secret.
+	Keys []string `json:"keys,omitempty"`
+	// ManagedByOperator is true if the Secret is created by the operator, which recreates it along with the resources.
+	ManagedByOperator bool `json:"managedByOperator"`
+}
+
+// Export returns a backup bundle of the resources managed by the operator in the given namespaces, or in all the
+// namespaces if none is given.
+func Export(ctx context.Context, c k8s.Client, operatorVersion string, namespaces []string, now time.Time) (Bundle, error) {
+	if len(namespaces) == 0 {
+		namespaces = []string{corev1.NamespaceAll}
+	}
+	bundle := Bundle{
+		Version:                BundleVersion,
+		OperatorVersion:        operatorVersion,
+		CreatedAt:              now.UTC(),
+		Resources:              []unstructured.Unstructured{},
+		CertificateAuthorities: []corev1.Secret{},
+		Secrets:                []SecretMetadata{},
+	}
+
+	resourceNamespaces := make(map[string]struct{})
+	for _, gvk := range ManagedKinds {
+		for _, ns := range namespaces {
+			list := unstructured.UnstructuredList{}
+			list.SetGroupVersionKind(gvk.GroupVersion().WithKind(gvk.Kind + "List"))
+			if err := c.List(ctx, &list, client.InNamespace(ns)); err != nil {
+				return Bundle{}, fmt.Errorf("while listing %s resources: %w", gvk.Kind, err)
+			}
+			for _, item := range list.Items {
+				// temporary clusters created by the operator to verify the snapshots are not worth recreating
+				if _, isVerification := item.GetLabels()[snapshot.RestoreVerificationOfLabelName]; isVerification {
+					continue
+				}
+				bundle.Resources = append(bundle.Resources, exportedResource(item))
+				resourceNamespaces[item.GetNamespace()] = struct{}{}
+			}
+		}
+	}
+
+	for ns := range resourceNamespaces {
+		var secrets corev1.SecretList
+		if err := c.List(ctx, &secrets, client.InNamespace(ns)); err != nil {
+			return Bundle{}, fmt.Errorf("while listing Secrets in namespace %s: %w", ns, err)
+		}
+		for _, secret := range secrets.Items {
+			if isCertificateAuthority(secret) {
+				bundle.CertificateAuthorities = append(bundle.CertificateAuthorities, exportedSecret(secret))
+				continue
+			}
+			bundle.Secrets = append(bundle.Secrets, secretMetadata(secret))
+		}
+	}
+	sort.SliceStable(bundle.CertificateAuthorities, func(i, j int) bool {
+		return k8s.ExtractNamespacedName(&bundle.CertificateAuthorities[i]).String() < k8s.ExtractNamespacedName(&bundle.CertificateAuthorities[j]).String()
+	})
+	sort.SliceStable(bundle.Secrets, func(i, j int) bool {
+		if bundle.Secrets[i].Namespace != bundle.Secrets[j].Namespace {
+			return bundle.Secrets[i].Namespace < bundle.Secrets[j].Namespace
+		}
+		return bundle.Secrets[i].Name < bundle.Secrets[j].Name
+	})
+	return bundle, nil
+}
+
+// exportedResource returns the given resource with only its type, identity, labels, user annotations, and spec.
+func exportedResource(resource unstructured.Unstructured) unstructured.Unstructured {
+	exported := unstructured.Unstructured{Object: map[string]interface{}{}}
+	exported.SetAPIVersion(resource.GetAPIVersion())
+	exported.SetKind(resource.GetKind())
+	exported.SetNamespace(resource.GetNamespace())
+	exported.SetName(resource.GetName())
+	exported.SetLabels(resource.GetLabels())
+	exported.SetAnnotations(userAnnotations(resource.GetAnnotations()))
+	if spec, exists := resource.Object["spec"]; exists {
+		exported.Object["spec"] = spec
+	}
+	return exported
+}
+
+// userAnnotations returns the given annotations without the ones holding the state of the operator.
+func userAnnotations(annotations map[string]string) map[string]string {
+	filtered := make(map[string]string, len(annotations))
+	for k, v := range annotations {
+		if isOperatorStateAnnotation(k) {
+			continue
+		}
+		filtered[k] = v
+	}
+	if len(filtered) == 0 {
+		return nil
+	}
+	return filtered
+}
+
+func isOperatorStateAnnotation(key string) bool {
+	for _, a := range operatorStateAnnotations {
+		if key == a {
+			return true
+		}
+	}
+	for _, prefix := range operatorStateAnnotationPrefixes {
+		if strings.HasPrefix(key, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// isCertificateAuthority returns true if the given Secret holds an internal certificate authority of the operator.
+func isCertificateAuthority(secret corev1.Secret) bool {
+	if secret.Name != certificates.NamespaceCASecretName && !strings.HasSuffix(secret.Name, "-ca-internal") {
+		return false
+	}
+	_, hasCert := secret.Data[certificates.CertFileName]
+	_, hasKey := secret.Data[certificates.KeyFileName]
+	return hasCert && hasKey
+}
+
+// exportedSecret returns the given Secret without its server-side metadata and owner references, which do not apply to
+// the recreated resources.
+func exportedSecret(secret corev1.Secret) corev1.Secret {
+	return corev1.Secret{
+		TypeMeta: metav1.TypeMeta{APIVersion: "v1", Kind: "Secret"},
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace:   secret.Namespace,
+			Name:        secret.Name,
+			Labels:      secret.Labels,
+			Annotations: secret.Annotations,
+		},
+		Type: secret.Type,
+		Data: secret.Data,
+	}
+}
+
+// secretMetadata describes the given Secret without its data.
+func secretMetadata(secret corev1.Secret) SecretMetadata {
+	keys := make([]string, 0, len(secret.Data))
+	for k := range secret.Data {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	_, managed := secret.Labels[labels.TypeLabelName]
+	return SecretMetadata{
+		Namespace:         secret.Namespace,
+		Name:              secret.Name,
+		Type:              secret.Type,
+		Labels:            secret.Labels,
+		Keys:              keys,
+		ManagedByOperator: managed,
+	}
+}
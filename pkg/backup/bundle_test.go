@@ -0,0 +1,124 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package backup
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	esv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/elasticsearch/v1"
+	kbv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/kibana/v1"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/certificates"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/labels"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/scheme"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/elasticsearch/bootstrap"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/utils/k8s"
+)
+
+func TestExport(t *testing.T) {
+	scheme.SetupScheme()
+	now := time.Date(2022, 10, 20, 8, 0, 0, 0, time.UTC)
+
+	es := esv1.Elasticsearch{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "ns",
+			Name:      "es",
+			Annotations: map[string]string{
+				bootstrap.ClusterUUIDAnnotationName:                     "uuid",
+				"association.k8s.elastic.co/es-conf":                    "{}",
+				"elasticsearch.k8s.elastic.co/disable-downscale-safety": "true",
+			},
+		},
+		Spec: esv1.ElasticsearchSpec{Version: "8.4.0", NodeSets: []esv1.NodeSet{{Name: "default", Count: 3}}},
+		Status: esv1.ElasticsearchStatus{
+			Phase: esv1.ElasticsearchReadyPhase,
+		},
+	}
+	kb := kbv1.Kibana{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "other-ns",
+			Name:      "kb",
+			Annotations: map[string]string{
+				"association.k8s.elastic.co/es-conf":                      "{}",
+				"association.k8s.elastic.co/es-conf-3476932817":           "{}",
+				"association.k8s.elastic.co/current-status":               "{}",
+				"association.k8s.elastic.co/rotate-service-account-token": "2022-10-01",
+				"association.k8s.elastic.co/es-api-key":                   "true",
+			},
+		},
+		Spec: kbv1.KibanaSpec{Version: "8.4.0", Count: 1},
+	}
+	caSecret := corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace:       "ns",
+			Name:            "es-es-http-ca-internal",
+			Labels:          map[string]string{labels.TypeLabelName: "elasticsearch"},
+			ResourceVersion: "42",
+			OwnerReferences: []metav1.OwnerReference{{Kind: "Elasticsearch", Name: "es"}},
+		},
+		Data: map[string][]byte{certificates.CertFileName: []byte("cert"), certificates.KeyFileName: []byte("key")},
+	}
+	userSecret := corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "s3-credentials"},
+		Data:       map[string][]byte{"s3.client.default.secret_key": []byte("secret"), "s3.client.default.access_key": []byte("access")},
+	}
+	ignoredSecret := corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "unrelated-ns", Name: "ignored"},
+	}
+
+	c := k8s.NewFakeClient(&es, &kb, &caSecret, &userSecret, &ignoredSecret)
+
+	t.Run("all namespaces", func(t *testing.T) {
+		bundle, err := Export(context.Background(), c, "2.6.0", nil, now)
+		require.NoError(t, err)
+		require.Equal(t, BundleVersion, bundle.Version)
+		require.Equal(t, "2.6.0", bundle.OperatorVersion)
+		require.Equal(t, now, bundle.CreatedAt)
+
+		require.Len(t, bundle.Resources, 2)
+		exportedES := bundle.Resources[0]
+		require.Equal(t, esv1.Kind, exportedES.GetKind())
+		require.Equal(t, map[string]string{"elasticsearch.k8s.elastic.co/disable-downscale-safety": "true"}, exportedES.GetAnnotations())
+		require.Empty(t, exportedES.GetResourceVersion())
+		_, hasStatus := exportedES.Object["status"]
+		require.False(t, hasStatus)
+		require.Contains(t, exportedES.Object, "spec")
+		require.Equal(t, kbv1.Kind, bundle.Resources[1].GetKind())
+		// only the association annotations set by the operator are removed
+		require.Equal(t, map[string]string{
+			"association.k8s.elastic.co/rotate-service-account-token": "2022-10-01",
+			"association.k8s.elastic.co/es-api-key":                   "true",
+		}, bundle.Resources[1].GetAnnotations())
+
+		require.Len(t, bundle.CertificateAuthorities, 1)
+		ca := bundle.CertificateAuthorities[0]
+		require.Equal(t, caSecret.Name, ca.Name)
+		require.Equal(t, caSecret.Data, ca.Data)
+		require.Empty(t, ca.OwnerReferences)
+		require.Empty(t, ca.ResourceVersion)
+
+		require.Equal(t, []SecretMetadata{
+			{
+				Namespace: "ns",
+				Name:      "s3-credentials",
+				Keys:      []string{"s3.client.default.access_key", "s3.client.default.secret_key"},
+			},
+		}, bundle.Secrets)
+	})
+
+	t.Run("restricted to some namespaces", func(t *testing.T) {
+		bundle, err := Export(context.Background(), c, "2.6.0", []string{"other-ns"}, now)
+		require.NoError(t, err)
+		require.Len(t, bundle.Resources, 1)
+		require.Equal(t, "kb", bundle.Resources[0].GetName())
+		require.Empty(t, bundle.CertificateAuthorities)
+		require.Empty(t, bundle.Secrets)
+	})
+}
@@ -140,6 +140,11 @@ func (r *ReconcileAgent) Reconcile(ctx context.Context, request reconcile.Reques
 		return reconcile.Result{}, nil
 	}
 
+	// do not reconcile resources last managed by a newer operator
+	if managed, err := common.ReconcileOperatorVersion(ctx, r.Client, agent); err != nil || !managed {
+		return reconcile.Result{}, tracing.CaptureError(ctx, err)
+	}
+
 	if agent.IsMarkedForDeletion() {
 		return reconcile.Result{}, nil
 	}
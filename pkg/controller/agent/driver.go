@@ -22,6 +22,7 @@ import (
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/certificates"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/defaults"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/events"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/expose"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/operator"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/reconciler"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/tracing"
@@ -113,21 +114,38 @@ func internalReconcile(params Params) (*reconciler.Results, agentv1alpha1.AgentS
 		fleetCerts, caResults = certificates.Reconciler{
 			K8sClient:             params.Client,
 			DynamicWatches:        params.Watches,
+			Recorder:              params.EventRecorder,
 			Owner:                 &params.Agent,
 			TLSOptions:            params.Agent.Spec.HTTP.TLS,
 			Namer:                 Namer,
 			Labels:                NewLabels(params.Agent),
 			Services:              []corev1.Service{*svc},
 			GlobalCA:              params.OperatorParams.GlobalCA,
+			NamespaceCA:           params.OperatorParams.NamespaceCA,
 			CACertRotation:        params.OperatorParams.CACertRotation,
 			CertRotation:          params.OperatorParams.CertRotation,
 			GarbageCollectSecrets: true,
-			ExtraHTTPSANs:         []commonv1.SubjectAlternativeName{{DNS: fmt.Sprintf("*.%s.%s.svc", HTTPServiceName(params.Agent.Name), params.Agent.Namespace)}},
+			ExtraHTTPSANs: append(
+				[]commonv1.SubjectAlternativeName{{DNS: fmt.Sprintf("*.%s.%s.svc", HTTPServiceName(params.Agent.Name), params.Agent.Namespace)}},
+				expose.SubjectAltNames(params.Agent.Spec.HTTP)...,
+			),
 		}.ReconcileCAAndHTTPCerts(params.Context)
 		if caResults.HasError() {
 			return results.WithResults(caResults), params.Status
 		}
 		_, _ = configHash.Write(fleetCerts.Data[certificates.CertFileName])
+
+		err = expose.Reconciler{
+			K8sClient: params.Client,
+			Owner:     &params.Agent,
+			HTTP:      params.Agent.Spec.HTTP,
+			Service:   *svc,
+			Namer:     Namer,
+			Labels:    NewLabels(params.Agent),
+		}.Reconcile(params.Context)
+		if err != nil {
+			return results.WithError(err), params.Status
+		}
 	}
 
 	fleetToken := maybeReconcileFleetEnrollment(params, results)
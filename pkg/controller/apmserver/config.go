@@ -114,16 +114,20 @@ func newElasticsearchConfigFromSpec(ctx context.Context, c k8s.Client, esAssocia
 		return settings.NewCanonicalConfig(), nil
 	}
 
-	// Get username and password
+	// Get username and password, or API key
 	credentials, err := association.ElasticsearchAuthSettings(ctx, c, &esAssociation)
 	if err != nil {
 		return nil, err
 	}
 
 	tmpOutputCfg := map[string]interface{}{
-		"output.elasticsearch.hosts":    []string{esAssocConf.GetURL()},
-		"output.elasticsearch.username": credentials.Username,
-		"output.elasticsearch.password": credentials.Password,
+		"output.elasticsearch.hosts": []string{esAssocConf.GetURL()},
+	}
+	if credentials.HasAPIKey() {
+		tmpOutputCfg["output.elasticsearch.api_key"] = credentials.APIKey
+	} else {
+		tmpOutputCfg["output.elasticsearch.username"] = credentials.Username
+		tmpOutputCfg["output.elasticsearch.password"] = credentials.Password
 	}
 	if esAssocConf.GetCACertProvided() {
 		tmpOutputCfg["output.elasticsearch.ssl.certificate_authorities"] = []string{filepath.Join(certificatesDir(esAssociation.AssociationType()), certificates.CAFileName)}
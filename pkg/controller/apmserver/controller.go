@@ -6,6 +6,7 @@ package apmserver
 
 import (
 	"context"
+	"fmt"
 	"path/filepath"
 	"reflect"
 	"sync/atomic"
@@ -32,6 +33,7 @@ import (
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/defaults"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/driver"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/events"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/expose"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/finalizer"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/keystore"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/labels"
@@ -48,6 +50,13 @@ const (
 	controllerName           = "apmserver-controller"
 	configHashAnnotationName = "apm.k8s.elastic.co/config-hash"
 
+	// RotateSecretTokenAnnotation can be set on the ApmServer resource to request the rotation of the secret token.
+	// A new token is generated every time the value of the annotation changes.
+	RotateSecretTokenAnnotation = "apm.k8s.elastic.co/rotate-secret-token"
+	// secretTokenRotationAnnotation records on the token secret the value of the rotation annotation for which the
+	// current token was generated.
+	secretTokenRotationAnnotation = "apm.k8s.elastic.co/secret-token-rotation"
+
 	// ApmBaseDir is the base directory of the APM server
 	ApmBaseDir = "/usr/share/apm-server"
 )
@@ -194,6 +203,11 @@ func (r *ReconcileApmServer) Reconcile(ctx context.Context, request reconcile.Re
 		return reconcile.Result{}, nil
 	}
 
+	// do not reconcile resources last managed by a newer operator
+	if managed, err := common.ReconcileOperatorVersion(ctx, r.Client, &as); err != nil || !managed {
+		return reconcile.Result{}, tracing.CaptureError(ctx, err)
+	}
+
 	// Remove any previous finalizer used in ECK v1.0.0-beta1 that we don't need anymore
 	if err := finalizer.RemoveAll(ctx, r.Client, &as); err != nil {
 		return reconcile.Result{}, err
@@ -234,15 +248,18 @@ func (r *ReconcileApmServer) doReconcile(ctx context.Context, as *apmv1.ApmServe
 	_, results = certificates.Reconciler{
 		K8sClient:             r.K8sClient(),
 		DynamicWatches:        r.DynamicWatches(),
+		Recorder:              r.Recorder(),
 		Owner:                 as,
 		TLSOptions:            as.Spec.HTTP.TLS,
 		Namer:                 Namer,
 		Labels:                NewLabels(as.Name),
 		Services:              []corev1.Service{*svc},
 		GlobalCA:              r.GlobalCA,
+		NamespaceCA:           r.NamespaceCA,
 		CACertRotation:        r.CACertRotation,
 		CertRotation:          r.CertRotation,
 		GarbageCollectSecrets: true,
+		ExtraHTTPSANs:         expose.SubjectAltNames(as.Spec.HTTP),
 	}.ReconcileCAAndHTTPCerts(ctx)
 	if results.HasError() {
 		_, err := results.Aggregate()
@@ -250,6 +267,18 @@ func (r *ReconcileApmServer) doReconcile(ctx context.Context, as *apmv1.ApmServe
 		return results, state
 	}
 
+	err = expose.Reconciler{
+		K8sClient: r.Client,
+		Owner:     as,
+		HTTP:      as.Spec.HTTP,
+		Service:   *svc,
+		Namer:     Namer,
+		Labels:    NewLabels(as.Name),
+	}.Reconcile(ctx)
+	if err != nil {
+		return results.WithError(err), state
+	}
+
 	asVersion, err := version.Parse(as.Spec.Version)
 	if err != nil {
 		return results.WithError(err), state
@@ -316,8 +345,9 @@ func (r *ReconcileApmServer) onDelete(ctx context.Context, obj types.NamespacedN
 }
 
 // reconcileApmServerToken reconciles a Secret containing the APM Server token.
-// It reuses the existing token if possible.
-func reconcileApmServerToken(ctx context.Context, c k8s.Client, as *apmv1.ApmServer) (corev1.Secret, error) {
+// It reuses the existing token if possible. A new token is generated if a rotation is requested through the
+// RotateSecretTokenAnnotation. APM Server accepts a single secret token, the replaced token is not kept.
+func reconcileApmServerToken(ctx context.Context, c k8s.Client, as *apmv1.ApmServer, recorder record.EventRecorder) (corev1.Secret, error) {
 	expectedApmServerSecret := corev1.Secret{
 		ObjectMeta: metav1.ObjectMeta{
 			Namespace: as.Namespace,
@@ -326,21 +356,36 @@ func reconcileApmServerToken(ctx context.Context, c k8s.Client, as *apmv1.ApmSer
 		},
 		Data: make(map[string][]byte),
 	}
+	rotation := as.Annotations[RotateSecretTokenAnnotation]
+	if rotation != "" {
+		expectedApmServerSecret.Annotations = map[string]string{secretTokenRotationAnnotation: rotation}
+	}
 	// reuse the secret token if it already exists
 	var existingSecret corev1.Secret
 	err := c.Get(ctx, k8s.ExtractNamespacedName(&expectedApmServerSecret), &existingSecret)
 	if err != nil && !apierrors.IsNotFound(err) {
 		return corev1.Secret{}, err
 	}
-	if token, exists := existingSecret.Data[SecretTokenKey]; exists {
-		expectedApmServerSecret.Data[SecretTokenKey] = token
-	} else {
-		expectedApmServerSecret.Data[SecretTokenKey] = common.RandomBytes(24)
+	token, exists := existingSecret.Data[SecretTokenKey]
+	// the first token is not a rotation
+	rotate := exists && rotation != "" && existingSecret.Annotations[secretTokenRotationAnnotation] != rotation
+	if !exists || rotate {
+		token = common.RandomBytes(24)
 	}
+	expectedApmServerSecret.Data[SecretTokenKey] = token
 
 	// Don't set an ownerRef for the APM token secret, likely to be copied into different namespaces.
 	// See https://github.com/elastic/cloud-on-k8s/issues/3986.
-	return reconciler.ReconcileSecretNoOwnerRef(ctx, c, expectedApmServerSecret, as)
+	reconciled, err := reconciler.ReconcileSecretNoOwnerRef(ctx, c, expectedApmServerSecret, as)
+	if err != nil {
+		return corev1.Secret{}, err
+	}
+	if rotate {
+		ulog.FromContext(ctx).Info("Rotated the APM Server secret token", "namespace", as.Namespace, "as_name", as.Name)
+		recorder.Event(as, corev1.EventTypeNormal, events.EventReasonPasswordRotation,
+			fmt.Sprintf("Rotated the secret token stored in secret %s", reconciled.Name))
+	}
+	return reconciled, nil
 }
 
 func (r *ReconcileApmServer) updateStatus(ctx context.Context, state State) error {
@@ -114,42 +114,87 @@ func TestReconcileApmServer_doReconcile(t *testing.T) {
 }
 
 func Test_reconcileApmServerToken(t *testing.T) {
-	apm := &apmv1.ApmServer{
-		ObjectMeta: metav1.ObjectMeta{
-			Namespace: "ns",
-			Name:      "apm",
-		},
+	tokenSecret := func(token string, annotations map[string]string) *corev1.Secret {
+		return &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace:   "ns",
+				Name:        SecretToken("apm"),
+				Annotations: annotations,
+			},
+			Data: map[string][]byte{
+				SecretTokenKey: []byte(token),
+			},
+		}
 	}
 	tests := []struct {
-		name       string
-		c          k8s.Client
-		reuseToken []byte
+		name          string
+		c             k8s.Client
+		rotation      string
+		reuseToken    []byte
+		replacedToken []byte
+		wantEvents    int
 	}{
 		{
 			name: "no secret exists: create one",
 			c:    k8s.NewFakeClient(),
 		},
 		{
-			name: "reuse token if it already exists",
-			c: k8s.NewFakeClient(&corev1.Secret{
-				ObjectMeta: metav1.ObjectMeta{
-					Namespace: "ns",
-					Name:      SecretToken(apm.Name),
-				},
-				Data: map[string][]byte{
-					SecretTokenKey: []byte("existing"),
-				},
-			}),
+			name:       "reuse token if it already exists",
+			c:          k8s.NewFakeClient(tokenSecret("existing", nil)),
+			reuseToken: []byte("existing"),
+		},
+		{
+			name:     "no secret exists: the first token is not a rotation",
+			c:        k8s.NewFakeClient(),
+			rotation: "1",
+		},
+		{
+			name:          "rotation requested: generate a new token",
+			c:             k8s.NewFakeClient(tokenSecret("existing", nil)),
+			rotation:      "1",
+			replacedToken: []byte("existing"),
+			wantEvents:    1,
+		},
+		{
+			name:       "rotation already done: reuse token",
+			c:          k8s.NewFakeClient(tokenSecret("existing", map[string]string{secretTokenRotationAnnotation: "1"})),
+			rotation:   "1",
+			reuseToken: []byte("existing"),
+		},
+		{
+			name:          "new rotation requested: generate a new token",
+			c:             k8s.NewFakeClient(tokenSecret("existing", map[string]string{secretTokenRotationAnnotation: "1"})),
+			rotation:      "2",
+			replacedToken: []byte("existing"),
+			wantEvents:    1,
 		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			got, err := reconcileApmServerToken(context.Background(), tt.c, apm)
+			apm := &apmv1.ApmServer{
+				ObjectMeta: metav1.ObjectMeta{
+					Namespace: "ns",
+					Name:      "apm",
+				},
+			}
+			if tt.rotation != "" {
+				apm.Annotations = map[string]string{RotateSecretTokenAnnotation: tt.rotation}
+			}
+			recorder := record.NewFakeRecorder(10)
+			got, err := reconcileApmServerToken(context.Background(), tt.c, apm, recorder)
 			require.NoError(t, err)
 			require.NotEmpty(t, got.Data[SecretTokenKey])
 			if tt.reuseToken != nil {
 				require.Equal(t, tt.reuseToken, got.Data[SecretTokenKey])
 			}
+			if tt.replacedToken != nil {
+				require.NotEqual(t, tt.replacedToken, got.Data[SecretTokenKey])
+			}
+			require.Len(t, got.Data, 1)
+			if tt.rotation != "" {
+				require.Equal(t, tt.rotation, got.Annotations[secretTokenRotationAnnotation])
+			}
+			require.Len(t, recorder.Events, tt.wantEvents)
 		})
 	}
 }
@@ -30,7 +30,7 @@ func (r *ReconcileApmServer) reconcileApmServerDeployment(
 	span, ctx := apm.StartSpan(ctx, "reconcile_deployment", tracing.SpanTypeApp)
 	defer span.End()
 
-	tokenSecret, err := reconcileApmServerToken(ctx, r.Client, as)
+	tokenSecret, err := reconcileApmServerToken(ctx, r.Client, as, r.recorder)
 	if err != nil {
 		return state, err
 	}
@@ -111,6 +111,11 @@ func buildConfigHash(c k8s.Client, as *apmv1.ApmServer, params PodSpecParams) (s
 	// - in the APMServer configuration file content
 	_, _ = configHash.Write(params.ConfigSecret.Data[ApmCfgSecretKey])
 
+	// - in the rotation of the secret token, as the token is only read at startup
+	if rotation, exists := params.TokenSecret.Annotations[secretTokenRotationAnnotation]; exists {
+		_, _ = configHash.Write([]byte(rotation))
+	}
+
 	// - in the APMServer keystore
 	if params.keystoreResources != nil {
 		_, _ = configHash.Write([]byte(params.keystoreResources.Version))
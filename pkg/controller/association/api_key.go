@@ -0,0 +1,299 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package association
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"go.elastic.co/apm/v2"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	commonv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/common/v1"
+	esv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/elasticsearch/v1"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/dryrun"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/hash"
+	commonlabels "github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/labels"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/reconciler"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/tracing"
+	esclient "github.com/elastic/cloud-on-k8s/v2/pkg/controller/elasticsearch/client"
+	eslabel "github.com/elastic/cloud-on-k8s/v2/pkg/controller/elasticsearch/label"
+	esuser "github.com/elastic/cloud-on-k8s/v2/pkg/controller/elasticsearch/user"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/utils/k8s"
+	ulog "github.com/elastic/cloud-on-k8s/v2/pkg/utils/log"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/utils/maps"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/utils/net"
+)
+
+const (
+	// ElasticsearchAPIKeyAnnotation can be set to "true" on an associated resource to authenticate to Elasticsearch with
+	// an API key created by the operator instead of a dedicated user, if the associated resource supports it.
+	ElasticsearchAPIKeyAnnotation = "association.k8s.elastic.co/es-api-key"
+	// ElasticsearchAPIKeyRotationIntervalAnnotation can be set on the associated resource to override the interval,
+	// as a duration such as "72h", after which a new API key is created.
+	ElasticsearchAPIKeyRotationIntervalAnnotation = "association.k8s.elastic.co/es-api-key-rotation-interval"
+	// DefaultAPIKeyRotationInterval is the default interval after which a new API key is created.
+	DefaultAPIKeyRotationInterval = 7 * 24 * time.Hour
+
+	// APIKeySecretKey is the key of the application secret holding the API key, encoded as id:api_key.
+	APIKeySecretKey = "api_key"
+	// apiKeyCreationAnnotation records on the application secret the time at which the API key was created.
+	apiKeyCreationAnnotation = "association.k8s.elastic.co/es-api-key-creation"
+	// apiKeyRolesHashAnnotation records on the application secret the hash of the role descriptors of the API key.
+	apiKeyRolesHashAnnotation = "association.k8s.elastic.co/es-api-key-roles-hash"
+	// apiKeyNameField is the field of the tracking secret holding the name of the API keys in Elasticsearch.
+	apiKeyNameField = "name"
+)
+
+// EsClientProvider returns a client to the given Elasticsearch cluster, authenticated as the operator.
+type EsClientProvider func(ctx context.Context, c k8s.Client, dialer net.Dialer, dryRun *dryrun.Mode, es esv1.Elasticsearch) (esclient.Client, error)
+
+// isAPIKeyRequested returns true if the associated resource requests to authenticate to Elasticsearch with an API key.
+func isAPIKeyRequested(associated commonv1.Associated) bool {
+	return associated.GetAnnotations()[ElasticsearchAPIKeyAnnotation] == "true"
+}
+
+// apiKeyRotationInterval returns the interval after which a new API key is created for the associated resource.
+func apiKeyRotationInterval(associated commonv1.Associated) (time.Duration, error) {
+	value, exists := associated.GetAnnotations()[ElasticsearchAPIKeyRotationIntervalAnnotation]
+	if !exists {
+		return DefaultAPIKeyRotationInterval, nil
+	}
+	interval, err := time.ParseDuration(value)
+	if err != nil {
+		return 0, fmt.Errorf("invalid annotation %s: %w", ElasticsearchAPIKeyRotationIntervalAnnotation, err)
+	}
+	if interval <= 0 {
+		return 0, fmt.Errorf("invalid annotation %s: %s is not a positive duration", ElasticsearchAPIKeyRotationIntervalAnnotation, value)
+	}
+	return interval, nil
+}
+
+// apiKeyExpiration returns the expiration of the API keys rotated at the given interval. A rotated API key remains
+// valid during one more interval, so that the Pods still using it keep working until they are updated.
+func apiKeyExpiration(interval time.Duration) string {
+	return fmt.Sprintf("%ds", int64((2 * interval).Seconds()))
+}
+
+// apiKeyName is the name of the API keys of the association in Elasticsearch. Like the name of the associated user,
+// it must be namespace-aware.
+func apiKeyName(association commonv1.Association, esNamespace, userSuffix string) string {
+	return UserKey(association, esNamespace, userSuffix).Name
+}
+
+// apiKeyTrackingSecretKey is the namespaced name of the secret tracking the API keys of the association, in the
+// Elasticsearch namespace.
+func apiKeyTrackingSecretKey(association commonv1.Association, esNamespace, userSuffix string) types.NamespacedName {
+	key := UserKey(association, esNamespace, userSuffix)
+	key.Name += "-api-key"
+	return key
+}
+
+// apiKeyLabelSelector returns labels selecting the API key tracking secret, including association labels and API key
+// type label.
+func (a AssociationInfo) apiKeyLabelSelector(
+	associated types.NamespacedName,
+	association types.NamespacedName,
+) client.MatchingLabels {
+	return maps.Merge(
+		map[string]string{commonlabels.TypeLabelName: esuser.AssociatedAPIKeyType},
+		a.AssociationResourceLabels(associated, association),
+	)
+}
+
+// apiKeyRoleDescriptors returns the role descriptors narrowing the privileges of an API key to the given comma
+// separated roles. Roles predefined by the operator are resolved locally, other roles are retrieved from Elasticsearch.
+func apiKeyRoleDescriptors(ctx context.Context, esClient esclient.SecurityClient, roles string) (map[string]esclient.Role, error) {
+	descriptors := make(map[string]esclient.Role)
+	var missing []string
+	for _, name := range strings.Split(roles, ",") {
+		if predefined, ok := esuser.PredefinedRoles[name].(esclient.Role); ok {
+			descriptors[name] = predefined
+			continue
+		}
+		missing = append(missing, name)
+	}
+	if len(missing) == 0 {
+		return descriptors, nil
+	}
+	retrieved, err := esClient.GetRoles(ctx, missing...)
+	if err != nil {
+		return nil, err
+	}
+	for _, name := range missing {
+		role, exists := retrieved[name]
+		if !exists {
+			return nil, fmt.Errorf("role %s not found in Elasticsearch", name)
+		}
+		descriptors[name] = role
+	}
+	return descriptors, nil
+}
+
+// reconcileAPIKey ensures the application secret holds an API key narrowed to the given comma separated roles, and that
+// the API key is tracked in the Elasticsearch namespace to be invalidated once the association is removed.
+// A new API key is created if the roles changed, or once the current API key is older than the rotation interval.
+// It returns the duration after which the API key must be rotated.
+func reconcileAPIKey(
+	ctx context.Context,
+	c k8s.Client,
+	esClient esclient.SecurityClient,
+	association commonv1.Association,
+	labels map[string]string,
+	roles string,
+	userSuffix string,
+	es esv1.Elasticsearch,
+	rotationInterval time.Duration,
+	now time.Time,
+) (time.Duration, error) {
+	span, ctx := apm.StartSpan(ctx, "reconcile_api_key", tracing.SpanTypeApp)
+	defer span.End()
+
+	// Add the Elasticsearch name to a copy of the labels, this is also used to invalidate the API keys of a removed association
+	labels = maps.Merge(maps.Merge(map[string]string{}, labels), map[string]string{eslabel.ClusterNameLabelName: es.Name})
+
+	name := apiKeyName(association, es.Namespace, userSuffix)
+	trackingKey := apiKeyTrackingSecretKey(association, es.Namespace, userSuffix)
+	tracking := corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      trackingKey.Name,
+			Namespace: trackingKey.Namespace,
+			Labels:    maps.Merge(map[string]string{commonlabels.TypeLabelName: esuser.AssociatedAPIKeyType}, labels),
+		},
+		Data: map[string][]byte{apiKeyNameField: []byte(name)},
+	}
+	// track the API key before creating it
+	owner := es // tracking secret is owned by the es resource in es namespace
+	if _, err := reconciler.ReconcileSecret(ctx, c, tracking, &owner); err != nil {
+		return 0, err
+	}
+
+	descriptors, err := apiKeyRoleDescriptors(ctx, esClient, roles)
+	if err != nil {
+		return 0, err
+	}
+	rolesHash := hash.HashObject(descriptors)
+
+	secKey := secretKey(association, userSuffix)
+	var existing corev1.Secret
+	if err := c.Get(ctx, secKey, &existing); err != nil && !apierrors.IsNotFound(err) {
+		return 0, err
+	}
+	created, err := time.Parse(time.RFC3339, existing.Annotations[apiKeyCreationAnnotation])
+	if err == nil && len(existing.Data[APIKeySecretKey]) > 0 && existing.Annotations[apiKeyRolesHashAnnotation] == rolesHash {
+		if rotateAfter := created.Add(rotationInterval).Sub(now); rotateAfter > 0 {
+			return rotateAfter, nil
+		}
+	}
+
+	apiKey, err := esClient.CreateAPIKey(ctx, esclient.APIKeyRequest{
+		Name:            name,
+		Expiration:      apiKeyExpiration(rotationInterval),
+		RoleDescriptors: descriptors,
+	})
+	if err != nil {
+		return 0, fmt.Errorf("while creating API key %s: %w", name, err)
+	}
+	ulog.FromContext(ctx).Info("Created API key", "namespace", es.Namespace, "es_name", es.Name, "api_key_name", name, "api_key_id", apiKey.ID)
+
+	expected := corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      secKey.Name,
+			Namespace: secKey.Namespace,
+			Labels:    commonlabels.AddCredentialsLabel(labels),
+			Annotations: map[string]string{
+				apiKeyCreationAnnotation:  now.UTC().Format(time.RFC3339),
+				apiKeyRolesHashAnnotation: rolesHash,
+			},
+		},
+		Data: map[string][]byte{APIKeySecretKey: []byte(apiKey.ID + ":" + apiKey.APIKey)},
+	}
+	if _, err := reconciler.ReconcileSecret(ctx, c, expected, association.Associated()); err != nil {
+		return 0, err
+	}
+	return rotationInterval, nil
+}
+
+// invalidateOrphanedAPIKeys invalidates the API keys of the associations of the associated resource which do not exist
+// anymore, before their tracking secrets are garbage collected.
+func (r *Reconciler) invalidateOrphanedAPIKeys(
+	ctx context.Context,
+	associated types.NamespacedName,
+	associations []commonv1.Association,
+) error {
+	var secrets corev1.SecretList
+	if err := r.List(ctx, &secrets, client.MatchingLabels(maps.Merge(
+		map[string]string{commonlabels.TypeLabelName: esuser.AssociatedAPIKeyType},
+		r.Labels(associated),
+	))); err != nil {
+		return err
+	}
+	orphaned := make([]corev1.Secret, 0, len(secrets.Items))
+	for _, secret := range secrets.Items {
+		isOrphaned := true
+		for _, association := range associations {
+			if isSecretForAssociation(r.AssociationInfo, secret, association) && isAPIKeyRequested(association.Associated()) {
+				isOrphaned = false
+				break
+			}
+		}
+		if isOrphaned {
+			orphaned = append(orphaned, secret)
+		}
+	}
+	return r.invalidateTrackedAPIKeys(ctx, orphaned)
+}
+
+// invalidateAPIKeys invalidates the API keys tracked by the secrets matching the given options.
+func (r *Reconciler) invalidateAPIKeys(ctx context.Context, opts ...client.ListOption) error {
+	var secrets corev1.SecretList
+	if err := r.List(ctx, &secrets, opts...); err != nil {
+		return err
+	}
+	return r.invalidateTrackedAPIKeys(ctx, secrets.Items)
+}
+
+// invalidateTrackedAPIKeys invalidates the API keys tracked by the given secrets, then deletes the secrets.
+// API keys of Elasticsearch clusters which do not exist anymore are deleted along with their cluster.
+func (r *Reconciler) invalidateTrackedAPIKeys(ctx context.Context, secrets []corev1.Secret) error {
+	for _, secret := range secrets {
+		secret := secret
+		var es esv1.Elasticsearch
+		esKey := types.NamespacedName{Namespace: secret.Namespace, Name: secret.Labels[eslabel.ClusterNameLabelName]}
+		err := r.Get(ctx, esKey, &es)
+		if err != nil && !apierrors.IsNotFound(err) {
+			return err
+		}
+		if err == nil {
+			if err := r.invalidateAPIKey(ctx, es, string(secret.Data[apiKeyNameField])); err != nil {
+				return err
+			}
+		}
+		if err := r.Delete(ctx, &secret); err != nil && !apierrors.IsNotFound(err) {
+			return err
+		}
+	}
+	return nil
+}
+
+// invalidateAPIKey invalidates all the API keys with the given name in the given Elasticsearch cluster.
+func (r *Reconciler) invalidateAPIKey(ctx context.Context, es esv1.Elasticsearch, name string) error {
+	esClient, err := r.esClientProvider(ctx, r.Client, r.Dialer, r.DryRun, es)
+	if err != nil {
+		return err
+	}
+	defer esClient.Close()
+	ulog.FromContext(ctx).Info("Invalidating API keys", "namespace", es.Namespace, "es_name", es.Name, "api_key_name", name)
+	if err := esClient.InvalidateAPIKeys(ctx, name); err != nil {
+		return fmt.Errorf("while invalidating API keys %s: %w", name, err)
+	}
+	return nil
+}
@@ -0,0 +1,223 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package association
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	beatv1beta1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/beat/v1beta1"
+	commonv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/common/v1"
+	esv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/elasticsearch/v1"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/dryrun"
+	commonlabels "github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/labels"
+	controllerscheme "github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/scheme"
+	esclient "github.com/elastic/cloud-on-k8s/v2/pkg/controller/elasticsearch/client"
+	eslabel "github.com/elastic/cloud-on-k8s/v2/pkg/controller/elasticsearch/label"
+	esuser "github.com/elastic/cloud-on-k8s/v2/pkg/controller/elasticsearch/user"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/utils/k8s"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/utils/net"
+)
+
+type fakeAPIKeyClient struct {
+	esclient.Client
+	roles       map[string]esclient.Role
+	created     []esclient.APIKeyRequest
+	invalidated []string
+}
+
+func (f *fakeAPIKeyClient) GetRoles(_ context.Context, names ...string) (map[string]esclient.Role, error) {
+	roles := make(map[string]esclient.Role)
+	for _, name := range names {
+		if role, exists := f.roles[name]; exists {
+			roles[name] = role
+		}
+	}
+	return roles, nil
+}
+
+func (f *fakeAPIKeyClient) CreateAPIKey(_ context.Context, request esclient.APIKeyRequest) (esclient.APIKey, error) {
+	f.created = append(f.created, request)
+	n := len(f.created)
+	return esclient.APIKey{ID: fmt.Sprintf("id-%d", n), Name: request.Name, APIKey: fmt.Sprintf("key-%d", n)}, nil
+}
+
+func (f *fakeAPIKeyClient) InvalidateAPIKeys(_ context.Context, name string) error {
+	f.invalidated = append(f.invalidated, name)
+	return nil
+}
+
+func (f *fakeAPIKeyClient) Close() {}
+
+var apiKeyTestAssociationInfo = AssociationInfo{
+	Labels: func(associated types.NamespacedName) map[string]string {
+		return map[string]string{
+			"beatassociation.k8s.elastic.co/name":      associated.Name,
+			"beatassociation.k8s.elastic.co/namespace": associated.Namespace,
+		}
+	},
+	AssociationResourceNameLabelName:      eslabel.ClusterNameLabelName,
+	AssociationResourceNamespaceLabelName: eslabel.ClusterNamespaceLabelName,
+}
+
+func apiKeyTestBeat() *beatv1beta1.Beat {
+	return &beatv1beta1.Beat{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace:   "ns",
+			Name:        "filebeat",
+			Annotations: map[string]string{ElasticsearchAPIKeyAnnotation: "true"},
+		},
+		Spec: beatv1beta1.BeatSpec{
+			Type:             "filebeat",
+			ElasticsearchRef: commonv1.ObjectSelector{Name: "es"},
+		},
+	}
+}
+
+func Test_reconcileAPIKey(t *testing.T) {
+	controllerscheme.SetupScheme()
+	es := esv1.Elasticsearch{ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "es"}}
+	beat := apiKeyTestBeat()
+	association := &beatv1beta1.BeatESAssociation{Beat: beat}
+	c := k8s.NewFakeClient(&es, beat)
+	esClient := &fakeAPIKeyClient{roles: map[string]esclient.Role{"beats_admin": {Cluster: []string{"monitor"}}}}
+	beatRole := esuser.BeatEsRoleName(esuser.V77, "filebeat")
+	created := time.Date(2022, 10, 18, 10, 0, 0, 0, time.UTC)
+	interval := 24 * time.Hour
+
+	reconcile := func(roles string, now time.Time) time.Duration {
+		t.Helper()
+		labels := apiKeyTestAssociationInfo.AssociationResourceLabels(
+			k8s.ExtractNamespacedName(beat), types.NamespacedName{Namespace: "ns", Name: "es"},
+		)
+		rotateAfter, err := reconcileAPIKey(context.Background(), c, esClient, association, labels, roles, "beat-user", es, interval, now)
+		require.NoError(t, err)
+		// the labels of the caller are left untouched
+		require.NotContains(t, labels, eslabel.ClusterNameLabelName)
+		return rotateAfter
+	}
+	apiKey := func() string {
+		t.Helper()
+		var secret corev1.Secret
+		require.NoError(t, c.Get(context.Background(), types.NamespacedName{Namespace: "ns", Name: "filebeat-beat-user"}, &secret))
+		return string(secret.Data[APIKeySecretKey])
+	}
+
+	// the API key is created, narrowed to the roles of the association, and tracked in the Elasticsearch namespace
+	require.Equal(t, interval, reconcile(beatRole+",beats_admin", created))
+	require.Len(t, esClient.created, 1)
+	require.Equal(t, "ns-filebeat-beat-user", esClient.created[0].Name)
+	require.Equal(t, "172800s", esClient.created[0].Expiration)
+	require.Equal(t, map[string]esclient.Role{
+		beatRole:      esuser.PredefinedRoles[beatRole].(esclient.Role),
+		"beats_admin": {Cluster: []string{"monitor"}},
+	}, esClient.created[0].RoleDescriptors)
+	require.Equal(t, "id-1:key-1", apiKey())
+	var tracking corev1.Secret
+	require.NoError(t, c.Get(context.Background(), types.NamespacedName{Namespace: "ns", Name: "ns-filebeat-beat-user-api-key"}, &tracking))
+	require.Equal(t, "ns-filebeat-beat-user", string(tracking.Data[apiKeyNameField]))
+	require.Equal(t, esuser.AssociatedAPIKeyType, tracking.Labels[commonlabels.TypeLabelName])
+
+	// the API key is reused until it must be rotated
+	require.Equal(t, 23*time.Hour, reconcile(beatRole+",beats_admin", created.Add(time.Hour)))
+	require.Len(t, esClient.created, 1)
+	require.Equal(t, "id-1:key-1", apiKey())
+
+	// a new API key is created once the rotation interval elapsed
+	require.Equal(t, interval, reconcile(beatRole+",beats_admin", created.Add(interval)))
+	require.Len(t, esClient.created, 2)
+	require.Equal(t, "id-2:key-2", apiKey())
+
+	// a new API key is created if the roles of the association change
+	reconcile(beatRole, created.Add(interval+time.Hour))
+	require.Len(t, esClient.created, 3)
+	require.Equal(t, map[string]esclient.Role{beatRole: esuser.PredefinedRoles[beatRole].(esclient.Role)}, esClient.created[2].RoleDescriptors)
+	require.Equal(t, "id-3:key-3", apiKey())
+
+	// roles which do not exist are reported
+	labels := apiKeyTestAssociationInfo.AssociationResourceLabels(k8s.ExtractNamespacedName(beat), types.NamespacedName{Namespace: "ns", Name: "es"})
+	_, err := reconcileAPIKey(context.Background(), c, esClient, association, labels, "unknown", "beat-user", es, interval, created)
+	require.Error(t, err)
+}
+
+func TestReconciler_invalidateOrphanedAPIKeys(t *testing.T) {
+	controllerscheme.SetupScheme()
+	es := esv1.Elasticsearch{ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "es"}}
+	tracking := corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "ns",
+			Name:      "ns-filebeat-beat-user-api-key",
+			Labels: map[string]string{
+				"beatassociation.k8s.elastic.co/name":      "filebeat",
+				"beatassociation.k8s.elastic.co/namespace": "ns",
+				eslabel.ClusterNameLabelName:               "es",
+				eslabel.ClusterNamespaceLabelName:          "ns",
+				commonlabels.TypeLabelName:                 esuser.AssociatedAPIKeyType,
+			},
+		},
+		Data: map[string][]byte{apiKeyNameField: []byte("ns-filebeat-beat-user")},
+	}
+	withoutAPIKey := apiKeyTestBeat()
+	withoutAPIKey.Annotations = nil
+
+	tests := []struct {
+		name            string
+		associations    []commonv1.Association
+		withoutES       bool
+		wantInvalidated []string
+	}{
+		{
+			name:         "association using an API key",
+			associations: []commonv1.Association{&beatv1beta1.BeatESAssociation{Beat: apiKeyTestBeat()}},
+		},
+		{
+			name:            "association using a user",
+			associations:    []commonv1.Association{&beatv1beta1.BeatESAssociation{Beat: withoutAPIKey}},
+			wantInvalidated: []string{"ns-filebeat-beat-user"},
+		},
+		{
+			name:            "association removed",
+			wantInvalidated: []string{"ns-filebeat-beat-user"},
+		},
+		{
+			name:      "Elasticsearch cluster removed",
+			withoutES: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tracking := tracking.DeepCopy()
+			c := k8s.NewFakeClient(tracking)
+			if !tt.withoutES {
+				c = k8s.NewFakeClient(tracking, es.DeepCopy())
+			}
+			esClient := &fakeAPIKeyClient{}
+			r := &Reconciler{
+				AssociationInfo: apiKeyTestAssociationInfo,
+				Client:          c,
+				esClientProvider: func(_ context.Context, _ k8s.Client, _ net.Dialer, _ *dryrun.Mode, _ esv1.Elasticsearch) (esclient.Client, error) {
+					return esClient, nil
+				},
+			}
+			err := r.invalidateOrphanedAPIKeys(context.Background(), types.NamespacedName{Namespace: "ns", Name: "filebeat"}, tt.associations)
+			require.NoError(t, err)
+			require.Equal(t, tt.wantInvalidated, esClient.invalidated)
+			err = c.Get(context.Background(), k8s.ExtractNamespacedName(tracking), &corev1.Secret{})
+			if len(tt.associations) > 0 && tt.wantInvalidated == nil {
+				require.NoError(t, err)
+				return
+			}
+			require.True(t, apierrors.IsNotFound(err))
+		})
+	}
+}
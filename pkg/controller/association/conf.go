@@ -66,12 +66,18 @@ func IsConfiguredIfSet(ctx context.Context, association commonv1.Association, r
 
 type Credentials struct {
 	Username, Password, ServiceAccountToken string
+	// APIKey is an Elasticsearch API key encoded as id:api_key.
+	APIKey string
 }
 
 func (c Credentials) HasServiceAccountToken() bool {
 	return len(c.ServiceAccountToken) > 0
 }
 
+func (c Credentials) HasAPIKey() bool {
+	return len(c.APIKey) > 0
+}
+
 // ElasticsearchAuthSettings returns the credentials to be used by an associated object to authenticate
 // against an Elasticsearch cluster.
 // This is also used for transitive authentication that relies on Elasticsearch native realm (eg. APMServer -> Kibana).
@@ -101,6 +107,10 @@ func ElasticsearchAuthSettings(ctx context.Context, c k8s.Client, assoc commonv1
 		return Credentials{ServiceAccountToken: string(passwordBytes)}, nil
 	}
 
+	if assocConf.IsAPIKey {
+		return Credentials{APIKey: string(passwordBytes)}, nil
+	}
+
 	password := string(passwordBytes)
 	// if direct or transitive managed ES, the username is the name of the password key in the auth managed Secret
 	username := assocConf.AuthSecretKey
@@ -14,6 +14,7 @@ import (
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/operator"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/watches"
+	esuser "github.com/elastic/cloud-on-k8s/v2/pkg/controller/elasticsearch/user"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/utils/rbac"
 )
 
@@ -26,12 +27,13 @@ func AddAssociationController(
 ) error {
 	controllerName := associationInfo.AssociationName + "-association-controller"
 	r := &Reconciler{
-		AssociationInfo: associationInfo,
-		Client:          mgr.GetClient(),
-		accessReviewer:  accessReviewer,
-		watches:         watches.NewDynamicWatches(),
-		recorder:        mgr.GetEventRecorderFor(controllerName),
-		Parameters:      params,
+		AssociationInfo:  associationInfo,
+		Client:           mgr.GetClient(),
+		accessReviewer:   accessReviewer,
+		watches:          watches.NewDynamicWatches(),
+		recorder:         mgr.GetEventRecorderFor(controllerName),
+		Parameters:       params,
+		esClientProvider: esuser.NewControllerClient,
 	}
 	c, err := common.NewController(mgr, controllerName, r, params)
 	if err != nil {
@@ -63,6 +63,7 @@ func AddApmES(mgr manager.Manager, accessReviewer rbac.AccessReviewer, params op
 			},
 			UserSecretSuffix: "apm-user",
 			ESUserRole:       getAPMElasticsearchRoles,
+			APIKeySupported:  true,
 		},
 	})
 }
@@ -64,6 +64,7 @@ func AddBeatES(mgr manager.Manager, accessReviewer rbac.AccessReviewer, params o
 			},
 			UserSecretSuffix: "beat-user",
 			ESUserRole:       getBeatRoles,
+			APIKeySupported:  true,
 		},
 	})
 }
@@ -0,0 +1,79 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package controller
+
+import (
+	"context"
+
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+
+	commonv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/common/v1"
+	kbv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/kibana/v1"
+	emsv1alpha1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/maps/v1alpha1"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/association"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/operator"
+	mapsctl "github.com/elastic/cloud-on-k8s/v2/pkg/controller/maps"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/utils/k8s"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/utils/rbac"
+)
+
+func AddKibanaEMS(mgr manager.Manager, accessReviewer rbac.AccessReviewer, params operator.Parameters) error {
+	return association.AddAssociationController(mgr, accessReviewer, params, association.AssociationInfo{
+		AssociatedObjTemplate:     func() commonv1.Associated { return &kbv1.Kibana{} },
+		ReferencedObjTemplate:     func() client.Object { return &emsv1alpha1.ElasticMapsServer{} },
+		ExternalServiceURL:        getEMSExternalURL,
+		ReferencedResourceVersion: referencedEMSStatusVersion,
+		ReferencedResourceNamer:   mapsctl.EMSNamer,
+		AssociationName:           "kb-ems",
+		AssociatedShortName:       "kb",
+		AssociationType:           commonv1.EMSAssociationType,
+		Labels: func(associated types.NamespacedName) map[string]string {
+			return map[string]string{
+				KibanaAssociationLabelName:      associated.Name,
+				KibanaAssociationLabelNamespace: associated.Namespace,
+				KibanaAssociationLabelType:      commonv1.EMSAssociationType,
+			}
+		},
+		AssociationConfAnnotationNameBase:     commonv1.EMSConfigAnnotationNameBase,
+		AssociationResourceNameLabelName:      mapsctl.NameLabelName,
+		AssociationResourceNamespaceLabelName: mapsctl.NamespaceLabelName,
+		ElasticsearchUserCreation:             nil, // no dedicated ES user required for Kibana->EMS connection
+	})
+}
+
+func getEMSExternalURL(c k8s.Client, assoc commonv1.Association) (string, error) {
+	emsRef := assoc.AssociationRef()
+	if !emsRef.IsDefined() {
+		return "", nil
+	}
+	ems := emsv1alpha1.ElasticMapsServer{}
+	if err := c.Get(context.Background(), emsRef.NamespacedName(), &ems); err != nil {
+		return "", err
+	}
+	serviceName := emsRef.ServiceName
+	if serviceName == "" {
+		serviceName = mapsctl.HTTPService(ems.Name)
+	}
+	nsn := types.NamespacedName{Namespace: ems.Namespace, Name: serviceName}
+	return association.ServiceURL(c, nsn, ems.Spec.HTTP.Protocol())
+}
+
+// referencedEMSStatusVersion returns the currently running version of Elastic Maps Server
+// reported in its status.
+func referencedEMSStatusVersion(c k8s.Client, emsRef commonv1.ObjectSelector) (string, error) {
+	if emsRef.IsExternal() {
+		// the version of an Elastic Maps Server not managed by ECK is not checked
+		return association.UnknownVersion, nil
+	}
+
+	var ems emsv1alpha1.ElasticMapsServer
+	err := c.Get(context.Background(), emsRef.NamespacedName(), &ems)
+	if err != nil {
+		return "", err
+	}
+	return ems.Status.Version, nil
+}
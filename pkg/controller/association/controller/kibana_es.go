@@ -17,6 +17,7 @@ import (
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/association"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/operator"
 	eslabel "github.com/elastic/cloud-on-k8s/v2/pkg/controller/elasticsearch/label"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/kibana"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/utils/k8s"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/utils/rbac"
 )
@@ -65,6 +66,16 @@ func AddKibanaES(mgr manager.Manager, accessReviewer rbac.AccessReviewer, params
 			ESUserRole: func(associated commonv1.Associated) (string, error) {
 				return KibanaSystemUserBuiltinRole, nil
 			},
+			AdditionalUsers: []association.AdditionalUser{{
+				UserSecretSuffix: kibana.SavedObjectsUserSecretSuffix,
+				ESUserRole: func(associated commonv1.Associated) string {
+					kb, ok := associated.(*kbv1.Kibana)
+					if !ok {
+						return ""
+					}
+					return kibana.SavedObjectsUserRole(*kb)
+				},
+			}},
 		},
 	})
 }
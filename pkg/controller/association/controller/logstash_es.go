@@ -0,0 +1,66 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package controller
+
+import (
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+
+	commonv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/common/v1"
+	esv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/elasticsearch/v1"
+	lsv1alpha1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/logstash/v1alpha1"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/association"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/operator"
+	eslabel "github.com/elastic/cloud-on-k8s/v2/pkg/controller/elasticsearch/label"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/elasticsearch/user"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/utils/k8s"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/utils/rbac"
+)
+
+const (
+	// LogstashESAssociationLabelName marks resources created for an association originating from Logstash with the
+	// Logstash name.
+	LogstashESAssociationLabelName = "logstashassociation.k8s.elastic.co/name"
+	// LogstashESAssociationLabelNamespace marks resources created for an association originating from Logstash with the
+	// Logstash namespace.
+	LogstashESAssociationLabelNamespace = "logstashassociation.k8s.elastic.co/namespace"
+	// LogstashESAssociationLabelType marks resources created for an association originating from Logstash
+	// with the target resource type (e.g. "elasticsearch").
+	LogstashESAssociationLabelType = "logstashassociation.k8s.elastic.co/type"
+)
+
+func AddLogstashES(mgr manager.Manager, accessReviewer rbac.AccessReviewer, params operator.Parameters) error {
+	return association.AddAssociationController(mgr, accessReviewer, params, association.AssociationInfo{
+		AssociatedObjTemplate:     func() commonv1.Associated { return &lsv1alpha1.Logstash{} },
+		ReferencedObjTemplate:     func() client.Object { return &esv1.Elasticsearch{} },
+		ReferencedResourceVersion: referencedElasticsearchStatusVersion,
+		ExternalServiceURL:        getElasticsearchExternalURL,
+		AssociationType:           commonv1.ElasticsearchAssociationType,
+		ReferencedResourceNamer:   esv1.ESNamer,
+		AssociationName:           "ls-es",
+		AssociatedShortName:       "ls",
+		Labels: func(associated types.NamespacedName) map[string]string {
+			return map[string]string{
+				LogstashESAssociationLabelName:      associated.Name,
+				LogstashESAssociationLabelNamespace: associated.Namespace,
+				LogstashESAssociationLabelType:      commonv1.ElasticsearchAssociationType,
+			}
+		},
+		AssociationConfAnnotationNameBase:     commonv1.ElasticsearchConfigAnnotationNameBase,
+		AssociationResourceNameLabelName:      eslabel.ClusterNameLabelName,
+		AssociationResourceNamespaceLabelName: eslabel.ClusterNamespaceLabelName,
+
+		ElasticsearchUserCreation: &association.ElasticsearchUserCreation{
+			ElasticsearchRef: func(c k8s.Client, association commonv1.Association) (bool, commonv1.ObjectSelector, error) {
+				return true, association.AssociationRef(), nil
+			},
+			UserSecretSuffix: "logstash-user",
+			ESUserRole: func(associated commonv1.Associated) (string, error) {
+				return user.LogstashUserRole, nil
+			},
+		},
+	})
+}
@@ -12,7 +12,6 @@ import (
 	"k8s.io/apimachinery/pkg/api/meta"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
-	"k8s.io/client-go/rest"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/labels"
@@ -29,7 +28,7 @@ const (
 // reconciliation loop. But without a Finalizer nothing prevents the associated resource to be removed while the
 // operator is not running.
 // This code is intended to be run during startup, before the controllers are started, to detect and delete such
-// orphaned resources.
+// orphaned resources, and periodically afterwards.
 type UsersGarbageCollector struct {
 	client            k8s.Client
 	managedNamespaces []string
@@ -44,20 +43,16 @@ type registeredResource struct {
 	associationNameLabel, associationNamespaceLabel string
 }
 
-// NewUsersGarbageCollector creates a new UsersGarbageCollector instance.
-func NewUsersGarbageCollector(cfg *rest.Config, managedNamespaces []string) (*UsersGarbageCollector, error) {
-	// Use a sync client here in order not to depend on any cache initialization
-	cl, err := client.New(cfg, client.Options{})
-	if err != nil {
-		return nil, err
-	}
+// NewUsersGarbageCollector creates a new UsersGarbageCollector instance. The given client should not depend on any
+// cache initialization.
+func NewUsersGarbageCollector(cl k8s.Client, managedNamespaces []string) *UsersGarbageCollector {
 	if len(managedNamespaces) == 0 {
 		managedNamespaces = []string{AllNamespaces}
 	}
 	return &UsersGarbageCollector{
 		client:            cl,
 		managedNamespaces: managedNamespaces,
-	}, nil
+	}
 }
 
 // For is used to register the associated resources and the annotation names needed to resolve the name
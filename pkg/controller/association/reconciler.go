@@ -96,6 +96,21 @@ type ElasticsearchUserCreation struct {
 	UserSecretSuffix string
 	// ESUserRole is the role to use for the Elasticsearch user created by the association.
 	ESUserRole func(commonv1.Associated) (string, error)
+	// APIKeySupported is true if the associated resource can authenticate to Elasticsearch with an API key narrowed to
+	// ESUserRole instead of a user, when requested through the ElasticsearchAPIKeyAnnotation.
+	APIKeySupported bool
+	// AdditionalUsers are other Elasticsearch users created for the associated resource, used by the operator to call
+	// its API for example. They do not replace the user or service account of the association.
+	AdditionalUsers []AdditionalUser
+}
+
+// AdditionalUser is an Elasticsearch user created for the associated resource in addition to the one of the association.
+type AdditionalUser struct {
+	// UserSecretSuffix is used as a suffix in the name of the secret holding user data in the associated namespace.
+	UserSecretSuffix string
+	// ESUserRole returns the role of the user, or an empty string if the associated resource does not need the user,
+	// which is then deleted.
+	ESUserRole func(commonv1.Associated) string
 }
 
 // AssociationResourceLabels returns all labels required by a resource to allow identifying both its Associated resource
@@ -133,6 +148,8 @@ type Reconciler struct {
 	recorder       record.EventRecorder
 	watches        watches.DynamicWatches
 	operator.Parameters
+	// esClientProvider is used to manage the API keys of the associations
+	esClientProvider EsClientProvider
 	// iteration is the number of times this controller has run its Reconcile method
 	iteration uint64
 }
@@ -182,6 +199,11 @@ func (r *Reconciler) Reconcile(ctx context.Context, request reconcile.Request) (
 		}
 	}
 
+	// invalidate the API keys which are not required anymore, before their tracking secrets are garbage collected
+	if err := r.invalidateOrphanedAPIKeys(ctx, associatedKey, associations); err != nil {
+		return reconcile.Result{}, tracing.CaptureError(ctx, err)
+	}
+
 	// garbage collect leftover resources that are not required anymore
 	if err := deleteOrphanedResources(ctx, r.Client, r.AssociationInfo, associatedKey, associations); err != nil {
 		log.Error(err, "Error while trying to delete orphaned resources. Continuing.")
@@ -195,7 +217,7 @@ func (r *Reconciler) Reconcile(ctx context.Context, request reconcile.Request) (
 	results := reconciler.NewResult(ctx)
 	newStatusMap := commonv1.AssociationStatusMap{}
 	for _, association := range associations {
-		newStatus, err := r.reconcileAssociation(ctx, association)
+		newStatus, err := r.reconcileAssociation(ctx, association, results)
 		if err != nil {
 			results.WithError(err)
 		}
@@ -219,7 +241,11 @@ func (r *Reconciler) Reconcile(ctx context.Context, request reconcile.Request) (
 		Aggregate()
 }
 
-func (r *Reconciler) reconcileAssociation(ctx context.Context, association commonv1.Association) (commonv1.AssociationStatus, error) {
+func (r *Reconciler) reconcileAssociation(
+	ctx context.Context,
+	association commonv1.Association,
+	results *reconciler.Results,
+) (commonv1.AssociationStatus, error) {
 	assocRef := association.AssociationRef()
 	log := ulog.FromContext(ctx)
 
@@ -341,6 +367,9 @@ func (r *Reconciler) reconcileAssociation(ctx context.Context, association commo
 
 	// If it is the case create the related Secrets and update the association configuration on the associated resource.
 	assocLabels := r.AssociationResourceLabels(k8s.ExtractNamespacedName(association.Associated()), assocRef.NamespacedName())
+	if err := r.reconcileAdditionalUsers(ctx, association, es, assocLabels); err != nil {
+		return commonv1.AssociationPending, err
+	}
 	if len(serviceAccount) > 0 && esHints.ServiceAccounts.IsTrue() {
 		applicationSecretName := secretKey(association, r.ElasticsearchUserCreation.UserSecretSuffix)
 		log.V(1).Info("Ensure service account exists", "sa", serviceAccount)
@@ -354,6 +383,7 @@ func (r *Reconciler) reconcileAssociation(ctx context.Context, association commo
 			serviceAccount,
 			association.GetName(),
 			association.GetUID(),
+			association.Associated().GetAnnotations()[RotateServiceAccountTokenAnnotation],
 		)
 		if err != nil {
 			return commonv1.AssociationFailed, err
@@ -370,6 +400,10 @@ func (r *Reconciler) reconcileAssociation(ctx context.Context, association commo
 		return commonv1.AssociationFailed, err
 	}
 
+	if r.ElasticsearchUserCreation.APIKeySupported && isAPIKeyRequested(association.Associated()) {
+		return r.reconcileAPIKeyAssociation(ctx, association, es, assocLabels, userRole, expectedAssocConf, results)
+	}
+
 	if err := reconcileEsUserSecret(
 		ctx,
 		r.Client,
@@ -390,6 +424,85 @@ func (r *Reconciler) reconcileAssociation(ctx context.Context, association commo
 	return r.updateAssocConf(ctx, expectedAssocConf, association)
 }
 
+// reconcileAdditionalUsers creates the additional Elasticsearch users needed by the associated resource, and deletes
+// the ones it does not need anymore.
+func (r *Reconciler) reconcileAdditionalUsers(
+	ctx context.Context,
+	association commonv1.Association,
+	es esv1.Elasticsearch,
+	assocLabels map[string]string,
+) error {
+	for _, additionalUser := range r.ElasticsearchUserCreation.AdditionalUsers {
+		role := additionalUser.ESUserRole(association.Associated())
+		if role == "" {
+			if err := k8s.DeleteSecretIfExists(ctx, r.Client, secretKey(association, additionalUser.UserSecretSuffix)); err != nil {
+				return err
+			}
+			if err := k8s.DeleteSecretIfExists(ctx, r.Client, UserKey(association, es.Namespace, additionalUser.UserSecretSuffix)); err != nil {
+				return err
+			}
+			continue
+		}
+		if err := reconcileEsUserSecret(ctx, r.Client, association, assocLabels, role, additionalUser.UserSecretSuffix, es); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// reconcileAPIKeyAssociation creates the API key used by the associated resource to authenticate to Elasticsearch
+// instead of a user, rotates it at the requested interval, and updates the association conf accordingly.
+func (r *Reconciler) reconcileAPIKeyAssociation(
+	ctx context.Context,
+	association commonv1.Association,
+	es esv1.Elasticsearch,
+	assocLabels map[string]string,
+	roles string,
+	expectedAssocConf *commonv1.AssociationConf,
+	results *reconciler.Results,
+) (commonv1.AssociationStatus, error) {
+	rotationInterval, err := apiKeyRotationInterval(association.Associated())
+	if err != nil {
+		return commonv1.AssociationFailed, err
+	}
+
+	// the API key replaces the user the association may have used before
+	if err := k8s.DeleteSecretMatching(ctx, r.Client, r.userLabelSelector(
+		k8s.ExtractNamespacedName(association.Associated()),
+		association.AssociationRef().NamespacedName(),
+	)); err != nil {
+		return commonv1.AssociationPending, err
+	}
+
+	esClient, err := r.esClientProvider(ctx, r.Client, r.Dialer, r.DryRun, es)
+	if err != nil {
+		return commonv1.AssociationPending, err
+	}
+	defer esClient.Close()
+	rotateAfter, err := reconcileAPIKey(
+		ctx,
+		r.Client,
+		esClient,
+		association,
+		assocLabels,
+		roles,
+		r.ElasticsearchUserCreation.UserSecretSuffix,
+		es,
+		rotationInterval,
+		time.Now(),
+	)
+	if err != nil {
+		return commonv1.AssociationPending, err
+	}
+	results.WithResult(reconcile.Result{RequeueAfter: rotateAfter})
+
+	expectedAssocConf.AuthSecretName = secretKey(association, r.ElasticsearchUserCreation.UserSecretSuffix).Name
+	expectedAssocConf.AuthSecretKey = APIKeySecretKey
+	expectedAssocConf.IsAPIKey = true
+	// update the association configuration if necessary
+	return r.updateAssocConf(ctx, expectedAssocConf, association)
+}
+
 // getElasticsearch attempts to retrieve the referenced Elasticsearch resource. If not found, it removes
 // any existing association configuration on associated, and returns AssociationPending.
 func (r *Reconciler) getElasticsearch(
@@ -420,6 +533,15 @@ func (r *Reconciler) getElasticsearch(
 
 // Unbind removes the association resources.
 func (r *Reconciler) Unbind(ctx context.Context, association commonv1.Association) error {
+	// Ensure that the API keys of the association are invalidated to prevent illegitimate access
+	if err := r.invalidateAPIKeys(
+		ctx,
+		r.apiKeyLabelSelector(
+			k8s.ExtractNamespacedName(association),
+			association.AssociationRef().NamespacedName(),
+		)); err != nil {
+		return err
+	}
 	// Ensure that user in Elasticsearch is deleted to prevent illegitimate access
 	if err := k8s.DeleteSecretMatching(
 		ctx,
@@ -513,6 +635,11 @@ func (r *Reconciler) onDelete(ctx context.Context, associated types.NamespacedNa
 	// remove watches
 	r.removeWatches(associated)
 
+	// invalidate the API keys of the associations before their tracking secrets are garbage collected
+	if err := r.invalidateOrphanedAPIKeys(ctx, associated, nil); err != nil {
+		ulog.FromContext(ctx).Error(err, "Error while trying to invalidate orphaned API keys. Continuing.")
+	}
+
 	// delete user Secret in the Elasticsearch namespace
 	if err := deleteOrphanedResources(ctx, r.Client, r.AssociationInfo, associated, nil); err != nil {
 		ulog.FromContext(ctx).Error(err, "Error while trying to delete orphaned resources. Continuing.")
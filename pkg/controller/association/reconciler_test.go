@@ -1238,3 +1238,34 @@ func TestReconciler_ReconcileSecretRef(t *testing.T) {
 	require.NoError(t, err)
 	require.Equal(t, commonv1.AssociationEstablished, updatedKibana.Status.AssociationStatus)
 }
+
+func TestReconciler_reconcileAdditionalUsers(t *testing.T) {
+	kb := sampleKibanaWithESRef()
+	r := testReconciler(&kb, &sampleES)
+	role := "kibana_admin"
+	userCreation := *kbAssociationInfo.ElasticsearchUserCreation
+	userCreation.AdditionalUsers = []AdditionalUser{{
+		UserSecretSuffix: "kibana-extra-user",
+		ESUserRole: func(associated commonv1.Associated) string {
+			return role
+		},
+	}}
+	r.ElasticsearchUserCreation = &userCreation
+	association := kb.EsAssociation()
+	assocLabels := r.AssociationResourceLabels(k8s.ExtractNamespacedName(&kb), association.AssociationRef().NamespacedName())
+
+	// the users are created with their own role
+	require.NoError(t, r.reconcileAdditionalUsers(context.Background(), association, sampleES, assocLabels))
+	var esUser corev1.Secret
+	require.NoError(t, r.Get(context.Background(), UserKey(association, esNamespace, "kibana-extra-user"), &esUser))
+	require.Equal(t, "kibana_admin", string(esUser.Data[user.UserRolesField]))
+	var kbUser corev1.Secret
+	require.NoError(t, r.Get(context.Background(), secretKey(association, "kibana-extra-user"), &kbUser))
+	require.NotEmpty(t, kbUser.Data)
+
+	// the users are deleted when not needed anymore
+	role = ""
+	require.NoError(t, r.reconcileAdditionalUsers(context.Background(), association, sampleES, assocLabels))
+	require.True(t, apierrors.IsNotFound(r.Get(context.Background(), UserKey(association, esNamespace, "kibana-extra-user"), &corev1.Secret{})))
+	require.True(t, apierrors.IsNotFound(r.Get(context.Background(), secretKey(association, "kibana-extra-user"), &corev1.Secret{})))
+}
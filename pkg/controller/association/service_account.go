@@ -39,6 +39,14 @@ const (
 
 	ServiceAccountNameField       = "serviceAccount"
 	ServiceAccountTokenValueField = "token"
+
+	// RotateServiceAccountTokenAnnotation can be set on a resource authenticating to Elasticsearch with a service
+	// account token, such as Kibana, to request the rotation of the token. A new token is generated every time the value
+	// of the annotation changes.
+	RotateServiceAccountTokenAnnotation = "association.k8s.elastic.co/rotate-service-account-token"
+	// serviceAccountTokenRotationAnnotation records on the application secret the value of the rotation annotation for
+	// which the current token was generated.
+	serviceAccountTokenRotationAnnotation = "association.k8s.elastic.co/service-account-token-rotation"
 )
 
 func applicationSecretLabels(es esv1.Elasticsearch) map[string]string {
@@ -57,6 +65,7 @@ func esSecretsLabels(es esv1.Elasticsearch) map[string]string {
 }
 
 // reconcileApplicationSecret reconciles the Secret which contains the application token.
+// A new token is generated if a rotation is requested through the RotateServiceAccountTokenAnnotation.
 func reconcileApplicationSecret(
 	ctx context.Context,
 	client k8s.Client,
@@ -65,6 +74,7 @@ func reconcileApplicationSecret(
 	commonLabels map[string]string,
 	tokenName string,
 	serviceAccount commonv1.ServiceAccountName,
+	rotation string,
 ) (*Token, error) {
 	span, ctx := apm.StartSpan(ctx, "reconcile_sa_token_application", tracing.SpanTypeApp)
 	defer span.End()
@@ -76,19 +86,32 @@ func reconcileApplicationSecret(
 	}
 
 	var token *Token
-	if k8serrors.IsNotFound(err) || len(applicationStore.Data) == 0 {
+	switch {
+	case k8serrors.IsNotFound(err) || len(applicationStore.Data) == 0:
 		// Secret does not exist or is empty, create a new token
 		token, err = newApplicationToken(serviceAccount, tokenName)
 		if err != nil {
 			return nil, err
 		}
-	} else {
+	case rotation != "" && applicationStore.Annotations[serviceAccountTokenRotationAnnotation] != rotation:
+		// the first token generated for a resource is not a rotation
+		ulog.FromContext(ctx).Info("Rotating service account token", "namespace", applicationSecretName.Namespace,
+			"secret_name", applicationSecretName.Name, "service_account", serviceAccount)
+		token, err = newApplicationToken(serviceAccount, tokenName)
+		if err != nil {
+			return nil, err
+		}
+	default:
 		// Attempt to read current token, create a new one in case of an error.
 		token, err = getOrCreateToken(ctx, &es, applicationSecretName.Name, applicationStore.Data, serviceAccount, tokenName)
 		if err != nil {
 			return nil, err
 		}
 	}
+	var annotations map[string]string
+	if rotation != "" {
+		annotations = map[string]string{serviceAccountTokenRotationAnnotation: rotation}
+	}
 
 	labels := applicationSecretLabels(es)
 	for labelName, labelValue := range commonLabels {
@@ -96,9 +119,10 @@ func reconcileApplicationSecret(
 	}
 	applicationStore = corev1.Secret{
 		ObjectMeta: metav1.ObjectMeta{
-			Name:      applicationSecretName.Name,
-			Namespace: applicationSecretName.Namespace,
-			Labels:    labels,
+			Name:        applicationSecretName.Name,
+			Namespace:   applicationSecretName.Namespace,
+			Labels:      labels,
+			Annotations: annotations,
 		},
 		Data: map[string][]byte{
 			esuser.ServiceAccountTokenNameField: []byte(token.TokenName),
@@ -142,6 +166,13 @@ func reconcileElasticsearchSecret(
 ) error {
 	span, ctx := apm.StartSpan(ctx, "reconcile_sa_token_elasticsearch", tracing.SpanTypeApp)
 	defer span.End()
+	var existing corev1.Secret
+	if err := client.Get(ctx, elasticsearchSecretName, &existing); err != nil && !k8serrors.IsNotFound(err) {
+		return err
+	}
+	if err := checkSameAssociation(existing, commonLabels); err != nil {
+		return err
+	}
 	fullyQualifiedName := token.ServiceAccountName + "/" + token.TokenName
 	labels := esSecretsLabels(es)
 	for labelName, labelValue := range commonLabels {
@@ -162,6 +193,8 @@ func reconcileElasticsearchSecret(
 	return err
 }
 
+// ReconcileServiceAccounts ensures that the application Secret holds a token of the given service account, and that the
+// Elasticsearch Secret holds the hash of the token. The token is regenerated when the given rotation changes.
 func ReconcileServiceAccounts(
 	ctx context.Context,
 	client k8s.Client,
@@ -172,9 +205,10 @@ func ReconcileServiceAccounts(
 	serviceAccount commonv1.ServiceAccountName,
 	applicationName string,
 	applicationUID types.UID,
+	rotation string,
 ) error {
 	tokenName := tokenName(applicationSecretName.Namespace, applicationName, applicationUID)
-	token, err := reconcileApplicationSecret(ctx, client, es, applicationSecretName, commonLabels, tokenName, serviceAccount)
+	token, err := reconcileApplicationSecret(ctx, client, es, applicationSecretName, commonLabels, tokenName, serviceAccount, rotation)
 	if err != nil {
 		return err
 	}
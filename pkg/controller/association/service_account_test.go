@@ -104,7 +104,10 @@ func Test_ReconcileServiceAccounts(t *testing.T) {
 		client         k8s.Client
 		applicationUID types.UID
 		serviceAccount commonv1.ServiceAccountName
+		rotation       string
 	}
+	rotatedKibanaUserSecret := expectedKibanaUserSecret.DeepCopy()
+	rotatedKibanaUserSecret.Annotations = map[string]string{"association.k8s.elastic.co/service-account-token-rotation": "1"}
 	tests := []struct {
 		name                                 string
 		args                                 args
@@ -171,6 +174,39 @@ func Test_ReconcileServiceAccounts(t *testing.T) {
 			wantKibanaUserResourceVersion:        "1",       // new Secret
 			wantElasticsearchUserResourceVersion: "3443558", // updated with new token
 		},
+		{
+			name: "token rotation requested",
+			args: args{
+				client: k8s.NewFakeClient(
+					existingElasticsearch.DeepCopy(),
+					existingKibana.DeepCopy(),
+					expectedKibanaUserSecret.DeepCopy(),
+					expectedElasticsearchUserSecret.DeepCopy(),
+				),
+				applicationUID: existingKibana.UID,
+				serviceAccount: "kibana",
+				rotation:       "1",
+			},
+			wantNewToken:                         true,
+			wantKibanaUserResourceVersion:        "3442952", // updated with new token
+			wantElasticsearchUserResourceVersion: "3443558", // updated with new token
+		},
+		{
+			name: "token already rotated",
+			args: args{
+				client: k8s.NewFakeClient(
+					existingElasticsearch.DeepCopy(),
+					existingKibana.DeepCopy(),
+					rotatedKibanaUserSecret,
+					expectedElasticsearchUserSecret.DeepCopy(),
+				),
+				applicationUID: existingKibana.UID,
+				serviceAccount: "kibana",
+				rotation:       "1",
+			},
+			wantKibanaUserResourceVersion:        "3442951", // not updated
+			wantElasticsearchUserResourceVersion: "3443557", // not updated
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -193,6 +229,7 @@ func Test_ReconcileServiceAccounts(t *testing.T) {
 				tt.args.serviceAccount,
 				existingKibana.Name,
 				existingKibana.UID,
+				tt.args.rotation,
 			)
 			if (err != nil) != tt.wantErr {
 				t.Errorf("store.EnsureTokenExists() error = %v, wantErr %v", err, tt.wantErr)
@@ -204,6 +241,9 @@ func Test_ReconcileServiceAccounts(t *testing.T) {
 			assert.NoError(t, tt.args.client.Get(context.Background(), applicationSecretName, &reconciledKibanaSecret))
 			assert.Equal(t, expectedKibanaUserSecret.Labels, reconciledKibanaSecret.Labels, "Labels on application SA secret are not equal")
 			assert.Equal(t, tt.wantKibanaUserResourceVersion, reconciledKibanaSecret.ResourceVersion, "Unexpected Kibana Secret resource version")
+			if tt.args.rotation != "" {
+				assert.Equal(t, tt.args.rotation, reconciledKibanaSecret.Annotations["association.k8s.elastic.co/service-account-token-rotation"])
+			}
 
 			reconciledElasticsearchSecret := corev1.Secret{}
 			assert.NoError(t, tt.args.client.Get(context.Background(), elasticsearchSecretName, &reconciledElasticsearchSecret))
@@ -6,6 +6,7 @@ package association
 
 import (
 	"context"
+	"fmt"
 
 	"go.elastic.co/apm/v2"
 	"golang.org/x/crypto/bcrypt"
@@ -74,6 +75,21 @@ func UserSecretKeySelector(association commonv1.Association, userSuffix string)
 	}
 }
 
+// checkSameAssociation returns an error if the given existing Secret, living in the Elasticsearch namespace, holds the
+// credentials of the association of another resource. The names of these Secrets are built from the namespace and the
+// name of the associated resources, which may collide: for example both the Kibana kb-b in the namespace a and the
+// Kibana b in the namespace a-kb use the a-kb-b-kibana-user user. Overwriting the Secret would lock the other resource
+// out of Elasticsearch.
+func checkSameAssociation(existing corev1.Secret, associationLabels map[string]string) error {
+	for key, value := range associationLabels {
+		if actual, exists := existing.Labels[key]; exists && actual != value {
+			return fmt.Errorf("secret %s/%s already holds the credentials of another association (%s: %s), rename one of the associated resources",
+				existing.Namespace, existing.Name, key, actual)
+		}
+	}
+	return nil
+}
+
 // reconcileEsUserSecret creates or updates the Elasticsearch user secrets in the Elasticsearch namespace
 // and the associated resource namespace.
 func reconcileEsUserSecret(
@@ -146,6 +162,9 @@ func reconcileEsUserSecret(
 	if err := c.Get(ctx, k8s.ExtractNamespacedName(&expectedEsUser), &existingUserSecret); err != nil && !apierrors.IsNotFound(err) {
 		return err
 	}
+	if err := checkSameAssociation(existingUserSecret, labels); err != nil {
+		return err
+	}
 
 	// reuse the existing hash if valid
 	var bcryptHash []byte
@@ -212,6 +212,34 @@ func Test_reconcileEsUser(t *testing.T) {
 				require.Equal(t, "$2a$10$mE3yo/AkZgR4eVW9kbA1TeIQ40Jv6WaWU494rx4C6EhLvuY0BSg4e", string(userSecret.Data[esuser.PasswordHashField]))
 			},
 		},
+		{
+			name: "User of the association of another resource with the same user name: error",
+			args: args{
+				initialObjects: []runtime.Object{&corev1.Secret{
+					ObjectMeta: metav1.ObjectMeta{
+						// also the user name of the Kibana foo in the namespace default-kibana
+						Name:      userName,
+						Namespace: "default",
+						Labels: map[string]string{
+							associationLabelName:      "foo",
+							associationLabelNamespace: "default-kibana",
+						},
+					},
+					Data: map[string][]byte{
+						esuser.PasswordHashField: []byte("$2a$10$mE3yo/AkZgR4eVW9kbA1TeIQ40Jv6WaWU494rx4C6EhLvuY0BSg4e"),
+					},
+				}},
+				kibana: kibanaFixture,
+				es:     esFixture,
+			},
+			wantErr: true,
+			postCondition: func(c k8s.Client) {
+				var esUser corev1.Secret
+				assert.NoError(t, c.Get(context.Background(), types.NamespacedName{Name: userName, Namespace: "default"}, &esUser))
+				assert.Equal(t, "foo", esUser.Labels[associationLabelName])
+				assert.Equal(t, "$2a$10$mE3yo/AkZgR4eVW9kbA1TeIQ40Jv6WaWU494rx4C6EhLvuY0BSg4e", string(esUser.Data[esuser.PasswordHashField]))
+			},
+		},
 		{
 			name: "Reconcile is namespace aware",
 			args: args{
@@ -267,6 +295,38 @@ func Test_reconcileEsUser(t *testing.T) {
 	}
 }
 
+func Test_reconcileEsUser_severalKibanas(t *testing.T) {
+	es := esv1.Elasticsearch{ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "es"}}
+	newKibana := func(name string) kbv1.Kibana {
+		return kbv1.Kibana{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: name},
+			Spec:       kbv1.KibanaSpec{ElasticsearchRef: commonv1.ObjectSelector{Name: es.Name}},
+		}
+	}
+	c := k8s.NewFakeClient()
+	kibanas := []kbv1.Kibana{newKibana("internal"), newKibana("public")}
+	for _, kb := range kibanas {
+		require.NoError(t, reconcileEsUserSecret(
+			context.Background(),
+			c,
+			kb.EsAssociation(),
+			map[string]string{associationLabelName: kb.Name, associationLabelNamespace: kb.Namespace},
+			"kibana_system",
+			"kibana-user",
+			es,
+		))
+	}
+	// each Kibana has its own user, credentials and CA Secret
+	for _, kb := range kibanas {
+		var userSecret corev1.Secret
+		require.NoError(t, c.Get(context.Background(), UserKey(kb.EsAssociation(), es.Namespace, "kibana-user"), &userSecret))
+		require.Equal(t, kb.Name, userSecret.Labels[associationLabelName])
+		require.NoError(t, c.Get(context.Background(), secretKey(kb.EsAssociation(), "kibana-user"), &corev1.Secret{}))
+	}
+	require.NotEqual(t, UserKey(kibanas[0].EsAssociation(), es.Namespace, "kibana-user"), UserKey(kibanas[1].EsAssociation(), es.Namespace, "kibana-user"))
+	require.NotEqual(t, CACertSecretName(kibanas[0].EsAssociation(), "kb-es"), CACertSecretName(kibanas[1].EsAssociation(), "kb-es"))
+}
+
 // ChecksUser checks that a secret contains the required fields expected by the user reconciler.
 func ChecksUser(t *testing.T, secret *corev1.Secret, expectedUsername string, expectedRoles []string) {
 	t.Helper()
@@ -38,12 +38,17 @@ func buildOutputConfig(ctx context.Context, client k8s.Client, associated beatv1
 		return settings.NewCanonicalConfig(), err
 	}
 
+	output := map[string]interface{}{
+		"hosts": []string{esAssocConf.GetURL()},
+	}
+	if credentials.HasAPIKey() {
+		output["api_key"] = credentials.APIKey
+	} else {
+		output["username"] = credentials.Username
+		output["password"] = credentials.Password
+	}
 	esOutput := map[string]interface{}{
-		"output.elasticsearch": map[string]interface{}{
-			"hosts":    []string{esAssocConf.GetURL()},
-			"username": credentials.Username,
-			"password": credentials.Password,
-		},
+		"output.elasticsearch": output,
 	}
 
 	if esAssocConf.GetCACertProvided() {
@@ -45,6 +45,13 @@ func Test_buildBeatConfig(t *testing.T) {
 			},
 			Data: map[string][]byte{"elastic": []byte("123")},
 		},
+		&corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "api-key-secret",
+				Namespace: "ns",
+			},
+			Data: map[string][]byte{"api_key": []byte("id:key")},
+		},
 	)
 
 	managedCfg := settings.MustParseConfig([]byte("setup.kibana: true"))
@@ -72,6 +79,22 @@ func Test_buildBeatConfig(t *testing.T) {
 		CASecretName:   "secret2",
 		URL:            "url",
 	})
+	withAPIKeyAssoc := *withAssoc.DeepCopy()
+	apiKeyAssoc := beatv1beta1.BeatESAssociation{Beat: &withAPIKeyAssoc}
+	apiKeyAssoc.SetAssociationConf(&commonv1.AssociationConf{
+		AuthSecretName: "api-key-secret",
+		AuthSecretKey:  "api_key",
+		IsAPIKey:       true,
+		CASecretName:   "secret2",
+		URL:            "url",
+	})
+	apiKeyOutputYaml := settings.MustParseConfig([]byte(`output:
+  elasticsearch:
+    hosts:
+    - url
+    api_key: id:key
+`))
+
 	withAssocWithCA := *withAssoc.DeepCopy()
 
 	esAssocWithCA := beatv1beta1.BeatESAssociation{Beat: &withAssocWithCA}
@@ -154,6 +177,12 @@ func Test_buildBeatConfig(t *testing.T) {
 			managedConfig: managedCfg,
 			want:          merge(userCanonicalCfg, managedCfg, outputYaml),
 		},
+		{
+			name:   "association with an API key, no configs",
+			client: clientWithSecret,
+			beat:   withAPIKeyAssoc,
+			want:   apiKeyOutputYaml,
+		},
 		{
 			name:   "association with ca, no configs",
 			client: clientWithSecret,
@@ -146,6 +146,11 @@ func (r *ReconcileBeat) Reconcile(ctx context.Context, request reconcile.Request
 		return reconcile.Result{}, nil
 	}
 
+	// do not reconcile resources last managed by a newer operator
+	if managed, err := common.ReconcileOperatorVersion(ctx, r.Client, &beat); err != nil || !managed {
+		return reconcile.Result{}, tracing.CaptureError(ctx, err)
+	}
+
 	if beat.IsMarkedForDeletion() {
 		return reconcile.Result{}, nil
 	}
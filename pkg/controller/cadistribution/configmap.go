@@ -0,0 +1,107 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package cadistribution
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+
+	"go.elastic.co/apm/v2"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	esv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/elasticsearch/v1"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/certificates"
+	commonlabels "github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/labels"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/reconciler"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/tracing"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/elasticsearch/label"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/utils/k8s"
+	ulog "github.com/elastic/cloud-on-k8s/v2/pkg/utils/log"
+)
+
+const (
+	// TypeLabelValue is a type used to identify a ConfigMap which contains the distributed CA of a cluster.
+	TypeLabelValue = "ca-distribution"
+	// caConfigMapSuffix is the suffix of the name of the ConfigMaps containing a distributed CA.
+	caConfigMapSuffix = "ca"
+)
+
+// Labels returns the labels set on the ConfigMaps containing the distributed CA of the given Elasticsearch cluster.
+func Labels(es types.NamespacedName) client.MatchingLabels {
+	return map[string]string{
+		commonlabels.TypeLabelName:      TypeLabelValue,
+		label.ClusterNameLabelName:      es.Name,
+		label.ClusterNamespaceLabelName: es.Namespace,
+	}
+}
+
+// CAConfigMapName returns the name of the ConfigMap containing the distributed CA of the given Elasticsearch cluster.
+// The namespace of the cluster is part of the name since clusters from several namespaces can be distributed to the
+// same namespace. It is separated from the name of the cluster with a dot, which namespace names and cluster names
+// cannot contain, so that the ConfigMaps of different clusters cannot have the same name.
+func CAConfigMapName(es types.NamespacedName) string {
+	return fmt.Sprintf("%s.%s", es.Namespace, esv1.ESNamer.Suffix(es.Name, caConfigMapSuffix))
+}
+
+// reconcileCAConfigMap creates or updates the ConfigMap containing the CA of the given cluster in the given namespace.
+// The ConfigMap has no owner reference since it may live in a different namespace than the cluster.
+func reconcileCAConfigMap(ctx context.Context, c k8s.Client, es types.NamespacedName, namespace string, ca []byte) error {
+	expected := corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      CAConfigMapName(es),
+			Namespace: namespace,
+			Labels:    Labels(es),
+		},
+		Data: map[string]string{
+			certificates.CAFileName: string(ca),
+		},
+	}
+	reconciled := &corev1.ConfigMap{}
+	return reconciler.ReconcileResource(
+		reconciler.Params{
+			Context:    ctx,
+			Client:     c,
+			Expected:   &expected,
+			Reconciled: reconciled,
+			NeedsUpdate: func() bool {
+				return !reflect.DeepEqual(expected.Data, reconciled.Data) ||
+					!reflect.DeepEqual(expected.Labels, reconciled.Labels)
+			},
+			UpdateReconciled: func() {
+				reconciled.Labels = expected.Labels
+				reconciled.Data = expected.Data
+			},
+		},
+	)
+}
+
+// deleteCAConfigMaps deletes the ConfigMaps containing the CA of the given cluster, except the ones living in the
+// namespaces to keep. ConfigMaps not named after the cluster, such as the ones created by previous operator versions,
+// are deleted in all namespaces.
+func deleteCAConfigMaps(ctx context.Context, c k8s.Client, es types.NamespacedName, toKeep map[string]struct{}) error {
+	span, ctx := apm.StartSpan(ctx, "delete_ca_configmaps", tracing.SpanTypeApp)
+	defer span.End()
+
+	var configMaps corev1.ConfigMapList
+	if err := c.List(ctx, &configMaps, Labels(es)); err != nil {
+		return err
+	}
+	for i := range configMaps.Items {
+		configMap := configMaps.Items[i]
+		if _, keep := toKeep[configMap.Namespace]; keep && configMap.Name == CAConfigMapName(es) {
+			continue
+		}
+		ulog.FromContext(ctx).Info("Deleting distributed CA", "namespace", configMap.Namespace, "configmap_name", configMap.Name)
+		if err := c.Delete(ctx, &configMap); err != nil && !errors.IsNotFound(err) {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,209 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package cadistribution
+
+import (
+	"context"
+	"fmt"
+
+	"go.elastic.co/apm/v2"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	esv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/elasticsearch/v1"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/certificates"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/events"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/operator"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/reconciler"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/tracing"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/watches"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/utils/k8s"
+	ulog "github.com/elastic/cloud-on-k8s/v2/pkg/utils/log"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/utils/rbac"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/utils/stringsutil"
+)
+
+const (
+	name = "ca-distribution-controller"
+
+	// NamespaceSelectorAnnotation is the annotation holding the label selector of the namespaces the HTTP CA
+	// certificate of an Elasticsearch cluster is distributed to.
+	NamespaceSelectorAnnotation = "eck.k8s.elastic.co/ca-distribution-namespace-selector"
+)
+
+// Add creates a new CA distribution controller and adds it to the manager.
+func Add(mgr manager.Manager, accessReviewer rbac.AccessReviewer, params operator.Parameters) error {
+	r := NewReconciler(mgr, accessReviewer, params)
+	c, err := common.NewController(mgr, name, r, params)
+	if err != nil {
+		return err
+	}
+	return AddWatches(c, r)
+}
+
+// NewReconciler returns a new reconcile.Reconciler
+func NewReconciler(mgr manager.Manager, accessReviewer rbac.AccessReviewer, params operator.Parameters) *ReconcileCADistribution {
+	return &ReconcileCADistribution{
+		Client:         mgr.GetClient(),
+		accessReviewer: accessReviewer,
+		watches:        watches.NewDynamicWatches(),
+		recorder:       mgr.GetEventRecorderFor(name),
+		Parameters:     params,
+	}
+}
+
+var _ reconcile.Reconciler = &ReconcileCADistribution{}
+
+// ReconcileCADistribution copies the HTTP CA certificate of Elasticsearch clusters into ConfigMaps of the managed
+// namespaces selected by the NamespaceSelectorAnnotation, so that workloads running in these namespaces can validate
+// TLS connections to the clusters. The default service account of each selected namespace must be allowed to access
+// the cluster, like the resources associated to it.
+type ReconcileCADistribution struct {
+	k8s.Client
+	operator.Parameters
+	accessReviewer rbac.AccessReviewer
+	recorder       record.EventRecorder
+	watches        watches.DynamicWatches
+
+	// iteration is the number of times this controller has run its Reconcile method
+	iteration uint64
+}
+
+// Reconcile distributes the HTTP CA certificate of the Elasticsearch cluster in the selected namespaces and removes
+// it from the namespaces which are not selected anymore.
+func (r *ReconcileCADistribution) Reconcile(ctx context.Context, request reconcile.Request) (reconcile.Result, error) {
+	ctx = common.NewReconciliationContext(ctx, &r.iteration, r.Tracer, name, "es_name", request)
+	defer common.LogReconciliationRun(ulog.FromContext(ctx))()
+	defer tracing.EndContextTransaction(ctx)
+
+	es := esv1.Elasticsearch{}
+	if err := r.Get(ctx, request.NamespacedName, &es); err != nil {
+		if errors.IsNotFound(err) {
+			r.watches.Secrets.RemoveHandlerForKey(watchName(request.NamespacedName))
+			return reconcile.Result{}, deleteCAConfigMaps(ctx, r.Client, request.NamespacedName, nil)
+		}
+		return reconcile.Result{}, err
+	}
+
+	if common.IsUnmanaged(ctx, &es) {
+		ulog.FromContext(ctx).Info("Object is currently not managed by this controller. Skipping reconciliation", "namespace", es.Namespace, "es_name", es.Name)
+		return reconcile.Result{}, nil
+	}
+
+	return reconcile.Result{}, r.doReconcile(ctx, es)
+}
+
+func (r *ReconcileCADistribution) doReconcile(ctx context.Context, es esv1.Elasticsearch) error {
+	esKey := k8s.ExtractNamespacedName(&es)
+
+	selector, err := namespaceSelector(es)
+	if err != nil {
+		r.recorder.Event(&es, corev1.EventTypeWarning, events.EventReasonValidation, err.Error())
+		// do not remove the ConfigMaps already distributed because of a typo in the selector
+		return nil
+	}
+	if selector == nil {
+		r.watches.Secrets.RemoveHandlerForKey(watchName(esKey))
+		return deleteCAConfigMaps(ctx, r.Client, esKey, nil)
+	}
+
+	// watch the public HTTP certificates Secret to follow CA rotations
+	if err := r.watches.Secrets.AddHandler(watches.NamedWatch{
+		Name:    watchName(esKey),
+		Watched: []types.NamespacedName{certificates.PublicCertsSecretRef(esv1.ESNamer, esKey)},
+		Watcher: esKey,
+	}); err != nil {
+		return err
+	}
+
+	ca, err := httpCA(ctx, r.Client, esKey)
+	if err != nil {
+		return err
+	}
+	if len(ca) == 0 {
+		// TLS is disabled or the CA is not yet available
+		return deleteCAConfigMaps(ctx, r.Client, esKey, nil)
+	}
+
+	var namespaces corev1.NamespaceList
+	if err := r.List(ctx, &namespaces, client.MatchingLabelsSelector{Selector: selector}); err != nil {
+		return err
+	}
+	expected := make(map[string]struct{}, len(namespaces.Items))
+	results := &reconciler.Results{}
+	for _, ns := range namespaces.Items {
+		if ns.DeletionTimestamp != nil || !r.isManaged(ns.Name) {
+			continue
+		}
+		allowed, err := r.accessReviewer.AccessAllowed(ctx, "", ns.Name, &es)
+		if err != nil {
+			results.WithError(err)
+			// keep the ConfigMap already distributed until the access can be checked
+			expected[ns.Name] = struct{}{}
+			continue
+		}
+		if !allowed {
+			ulog.FromContext(ctx).V(1).Info("Namespace not allowed to access the cluster, skipping CA distribution",
+				"namespace", es.Namespace, "es_name", es.Name, "target_namespace", ns.Name)
+			continue
+		}
+		expected[ns.Name] = struct{}{}
+		results.WithError(reconcileCAConfigMap(ctx, r.Client, esKey, ns.Name, ca))
+	}
+	results.WithError(deleteCAConfigMaps(ctx, r.Client, esKey, expected))
+	_, err = results.Aggregate()
+	return err
+}
+
+// isManaged returns true if the given namespace is managed by the operator.
+func (r *ReconcileCADistribution) isManaged(namespace string) bool {
+	if len(r.ManagedNamespaces) == 0 {
+		return true
+	}
+	return stringsutil.StringInSlice(namespace, r.ManagedNamespaces)
+}
+
+// namespaceSelector returns the namespace selector set on the given Elasticsearch resource, or nil if the CA should
+// not be distributed. An empty selector, which would select all the namespaces, is rejected.
+func namespaceSelector(es esv1.Elasticsearch) (labels.Selector, error) {
+	value, exists := es.Annotations[NamespaceSelectorAnnotation]
+	if !exists {
+		return nil, nil
+	}
+	selector, err := labels.Parse(value)
+	if err != nil {
+		return nil, fmt.Errorf("invalid %s annotation: %w", NamespaceSelectorAnnotation, err)
+	}
+	if selector.Empty() {
+		return nil, fmt.Errorf("invalid %s annotation: the namespace selector must not be empty", NamespaceSelectorAnnotation)
+	}
+	return selector, nil
+}
+
+// httpCA returns the HTTP CA certificate of the given Elasticsearch cluster, or nil if it is not available.
+func httpCA(ctx context.Context, c k8s.Client, es types.NamespacedName) ([]byte, error) {
+	span, ctx := apm.StartSpan(ctx, "get_http_ca", tracing.SpanTypeApp)
+	defer span.End()
+
+	var secret corev1.Secret
+	if err := c.Get(ctx, certificates.PublicCertsSecretRef(esv1.ESNamer, es), &secret); err != nil {
+		if errors.IsNotFound(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return secret.Data[certificates.CAFileName], nil
+}
+
+func watchName(es types.NamespacedName) string {
+	return fmt.Sprintf("%s-%s-ca-distribution", es.Namespace, es.Name)
+}
@@ -0,0 +1,204 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package cadistribution
+
+import (
+	"context"
+	"sort"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	esv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/elasticsearch/v1"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/certificates"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/operator"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/watches"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/utils/k8s"
+)
+
+var esKey = types.NamespacedName{Namespace: "elastic", Name: "es"}
+
+func newES(selector *string) *esv1.Elasticsearch {
+	es := &esv1.Elasticsearch{ObjectMeta: metav1.ObjectMeta{Namespace: esKey.Namespace, Name: esKey.Name}}
+	if selector != nil {
+		es.Annotations = map[string]string{NamespaceSelectorAnnotation: *selector}
+	}
+	return es
+}
+
+func newNamespace(name string, labels map[string]string) *corev1.Namespace {
+	return &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: name, Labels: labels}}
+}
+
+func newPublicCertsSecret(ca string) *corev1.Secret {
+	ref := certificates.PublicCertsSecretRef(esv1.ESNamer, esKey)
+	return &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Namespace: ref.Namespace, Name: ref.Name},
+		Data:       map[string][]byte{certificates.CAFileName: []byte(ca)},
+	}
+}
+
+func newCAConfigMap(namespace string, ca string) *corev1.ConfigMap {
+	return &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Namespace: namespace, Name: CAConfigMapName(esKey), Labels: Labels(esKey)},
+		Data:       map[string]string{certificates.CAFileName: ca},
+	}
+}
+
+func ptr(s string) *string {
+	return &s
+}
+
+// denyAccessReviewer denies the access from the given namespaces.
+type denyAccessReviewer struct {
+	denied []string
+}
+
+func (d denyAccessReviewer) AccessAllowed(_ context.Context, _ string, sourceNamespace string, _ runtime.Object) (bool, error) {
+	for _, ns := range d.denied {
+		if ns == sourceNamespace {
+			return false, nil
+		}
+	}
+	return true, nil
+}
+
+func TestReconcileCADistribution_Reconcile(t *testing.T) {
+	teamA := map[string]string{"team": "a"}
+	tests := []struct {
+		name              string
+		objects           []runtime.Object
+		managedNamespaces []string
+		deniedNamespaces  []string
+		wantNamespaces    []string
+		wantCA            string
+	}{
+		{
+			name: "no annotation: nothing is distributed",
+			objects: []runtime.Object{
+				newES(nil), newPublicCertsSecret("ca"), newNamespace("ns1", teamA),
+			},
+		},
+		{
+			name: "CA is distributed to the selected namespaces",
+			objects: []runtime.Object{
+				newES(ptr("team=a")), newPublicCertsSecret("ca"),
+				newNamespace("ns1", teamA), newNamespace("ns2", teamA), newNamespace("ns3", nil),
+			},
+			wantNamespaces: []string{"ns1", "ns2"},
+			wantCA:         "ca",
+		},
+		{
+			name: "CA is updated after a rotation",
+			objects: []runtime.Object{
+				newES(ptr("team=a")), newPublicCertsSecret("new-ca"),
+				newNamespace("ns1", teamA), newCAConfigMap("ns1", "old-ca"),
+			},
+			wantNamespaces: []string{"ns1"},
+			wantCA:         "new-ca",
+		},
+		{
+			name: "CA is removed from the namespaces which are not selected anymore",
+			objects: []runtime.Object{
+				newES(ptr("team=a")), newPublicCertsSecret("ca"),
+				newNamespace("ns1", teamA), newNamespace("ns2", nil), newCAConfigMap("ns2", "ca"),
+			},
+			wantNamespaces: []string{"ns1"},
+			wantCA:         "ca",
+		},
+		{
+			name: "CA is only distributed to the managed namespaces",
+			objects: []runtime.Object{
+				newES(ptr("team=a")), newPublicCertsSecret("ca"),
+				newNamespace("ns1", teamA), newNamespace("ns2", teamA), newCAConfigMap("ns2", "ca"),
+			},
+			managedNamespaces: []string{"elastic", "ns1"},
+			wantNamespaces:    []string{"ns1"},
+			wantCA:            "ca",
+		},
+		{
+			name: "CA is only distributed to the namespaces allowed to access the cluster",
+			objects: []runtime.Object{
+				newES(ptr("team=a")), newPublicCertsSecret("ca"),
+				newNamespace("ns1", teamA), newNamespace("ns2", teamA), newCAConfigMap("ns2", "ca"),
+			},
+			deniedNamespaces: []string{"ns2"},
+			wantNamespaces:   []string{"ns1"},
+			wantCA:           "ca",
+		},
+		{
+			name: "CA distributed under a previous name is renamed",
+			objects: []runtime.Object{
+				newES(ptr("team=a")), newPublicCertsSecret("ca"), newNamespace("ns1", teamA),
+				&corev1.ConfigMap{
+					ObjectMeta: metav1.ObjectMeta{Namespace: "ns1", Name: "elastic-es-es-ca", Labels: Labels(esKey)},
+					Data:       map[string]string{certificates.CAFileName: "ca"},
+				},
+			},
+			wantNamespaces: []string{"ns1"},
+			wantCA:         "ca",
+		},
+		{
+			name: "CA is removed when the annotation is removed",
+			objects: []runtime.Object{
+				newES(nil), newPublicCertsSecret("ca"), newNamespace("ns1", teamA), newCAConfigMap("ns1", "ca"),
+			},
+		},
+		{
+			name: "CA is removed when the cluster is deleted",
+			objects: []runtime.Object{
+				newNamespace("ns1", teamA), newCAConfigMap("ns1", "ca"),
+			},
+		},
+		{
+			name: "empty selector: distributed CA is left untouched",
+			objects: []runtime.Object{
+				newES(ptr("")), newPublicCertsSecret("ca"), newNamespace("ns1", teamA), newNamespace("ns2", nil),
+				newCAConfigMap("ns1", "ca"),
+			},
+			wantNamespaces: []string{"ns1"},
+			wantCA:         "ca",
+		},
+		{
+			name: "invalid selector: distributed CA is left untouched",
+			objects: []runtime.Object{
+				newES(ptr("team in a")), newPublicCertsSecret("ca"), newNamespace("ns1", teamA), newCAConfigMap("ns1", "ca"),
+			},
+			wantNamespaces: []string{"ns1"},
+			wantCA:         "ca",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := k8s.NewFakeClient(tt.objects...)
+			r := &ReconcileCADistribution{
+				Client:         c,
+				Parameters:     operator.Parameters{ManagedNamespaces: tt.managedNamespaces},
+				accessReviewer: denyAccessReviewer{denied: tt.deniedNamespaces},
+				watches:        watches.NewDynamicWatches(),
+				recorder:       record.NewFakeRecorder(10),
+			}
+			_, err := r.Reconcile(context.Background(), reconcile.Request{NamespacedName: esKey})
+			require.NoError(t, err)
+
+			var configMaps corev1.ConfigMapList
+			require.NoError(t, c.List(context.Background(), &configMaps, Labels(esKey)))
+			var namespaces []string
+			for _, cm := range configMaps.Items {
+				require.Equal(t, "elastic.es-es-ca", cm.Name)
+				namespaces = append(namespaces, cm.Namespace)
+				require.Equal(t, tt.wantCA, cm.Data[certificates.CAFileName])
+			}
+			sort.Strings(namespaces)
+			require.Equal(t, tt.wantNamespaces, namespaces)
+		})
+	}
+}
@@ -0,0 +1,82 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package cadistribution
+
+import (
+	"context"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+
+	esv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/elasticsearch/v1"
+	commonlabels "github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/labels"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/elasticsearch/label"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/utils/k8s"
+	ulog "github.com/elastic/cloud-on-k8s/v2/pkg/utils/log"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/utils/maps"
+)
+
+// AddWatches sets watches on objects needed to distribute the CA of Elasticsearch clusters.
+func AddWatches(c controller.Controller, r *ReconcileCADistribution) error {
+	// Watch for changes to Elasticsearch clusters
+	if err := c.Watch(&source.Kind{Type: &esv1.Elasticsearch{}}, &handler.EnqueueRequestForObject{}); err != nil {
+		return err
+	}
+
+	// Watch the distributed CA ConfigMaps to restore them if they are modified or deleted
+	if err := c.Watch(&source.Kind{Type: &corev1.ConfigMap{}}, handler.EnqueueRequestsFromMapFunc(requestsFromCAConfigMap)); err != nil {
+		return err
+	}
+
+	// Watch namespaces to distribute the CA when they start or stop matching a selector
+	if err := c.Watch(&source.Kind{Type: &corev1.Namespace{}}, handler.EnqueueRequestsFromMapFunc(requestsFromNamespace(r.Client))); err != nil {
+		return err
+	}
+
+	// Dynamically watch the public HTTP certificates of the clusters whose CA is distributed
+	return c.Watch(&source.Kind{Type: &corev1.Secret{}}, r.watches.Secrets)
+}
+
+// requestsFromCAConfigMap creates a reconcile request for the cluster whose CA is in the given ConfigMap.
+func requestsFromCAConfigMap(obj client.Object) []reconcile.Request {
+	labels := obj.GetLabels()
+	if !maps.ContainsKeys(labels, label.ClusterNameLabelName, label.ClusterNamespaceLabelName, commonlabels.TypeLabelName) {
+		return nil
+	}
+	if labels[commonlabels.TypeLabelName] != TypeLabelValue {
+		return nil
+	}
+	return []reconcile.Request{
+		{NamespacedName: types.NamespacedName{
+			Namespace: labels[label.ClusterNamespaceLabelName],
+			Name:      labels[label.ClusterNameLabelName]},
+		},
+	}
+}
+
+// requestsFromNamespace creates reconcile requests for all the clusters whose CA is distributed based on a namespace
+// selector, since any namespace change may affect the namespaces it selects.
+func requestsFromNamespace(c k8s.Client) handler.MapFunc {
+	return func(_ client.Object) []reconcile.Request {
+		var list esv1.ElasticsearchList
+		if err := c.List(context.Background(), &list); err != nil {
+			ulog.Log.Error(err, "Failed to list Elasticsearch clusters")
+			return nil
+		}
+		var requests []reconcile.Request
+		for _, es := range list.Items {
+			if _, exists := es.Annotations[NamespaceSelectorAnnotation]; !exists {
+				continue
+			}
+			requests = append(requests, reconcile.Request{NamespacedName: types.NamespacedName{Namespace: es.Namespace, Name: es.Name}})
+		}
+		return requests
+	}
+}
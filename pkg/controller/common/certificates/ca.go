@@ -17,6 +17,7 @@ import (
 	"github.com/pkg/errors"
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/utils/clock"
 
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/name"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/utils/k8s"
@@ -55,6 +56,8 @@ type CABuilderOptions struct {
 	PrivateKey *rsa.PrivateKey
 	// ExpireIn defines in how much time will the CA expire (defaults to DefaultCertValidity if not provided).
 	ExpireIn *time.Duration
+	// Clock provides the current time the validity of the CA starts from (defaults to the real clock if not provided).
+	Clock clock.PassiveClock
 }
 
 // NewSelfSignedCA creates a self-signed CA according to the given options
@@ -73,15 +76,16 @@ func NewSelfSignedCA(options CABuilderOptions) (*CA, error) {
 		}
 	}
 
-	notAfter := time.Now().Add(DefaultCertValidity)
+	now := currentTime(options.Clock)
+	notAfter := now.Add(DefaultCertValidity)
 	if options.ExpireIn != nil {
-		notAfter = time.Now().Add(*options.ExpireIn)
+		notAfter = now.Add(*options.ExpireIn)
 	}
 
 	certificateTemplate := x509.Certificate{
 		SerialNumber:          serial,
 		Subject:               options.Subject,
-		NotBefore:             time.Now().Add(-10 * time.Minute),
+		NotBefore:             now.Add(-10 * time.Minute),
 		NotAfter:              notAfter,
 		SignatureAlgorithm:    x509.SHA256WithRSA,
 		IsCA:                  true,
@@ -21,6 +21,7 @@ import (
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/name"
@@ -58,6 +59,7 @@ func CAInternalSecretName(namer name.Namer, ownerName string, caType CAType) str
 func ReconcileCAForOwner(
 	ctx context.Context,
 	cl k8s.Client,
+	recorder record.EventRecorder,
 	namer name.Namer,
 	owner client.Object,
 	labels map[string]string,
@@ -77,24 +79,25 @@ func ReconcileCAForOwner(
 	}
 	if apierrors.IsNotFound(err) {
 		log.Info("No internal CA certificate Secret found, creating a new one", "owner_namespace", owner.GetNamespace(), "owner_name", owner.GetName(), "ca_type", caType)
-		return renewCA(ctx, cl, namer, owner, labels, rotationParams.Validity, caType)
+		return renewCA(ctx, cl, recorder, namer, owner, labels, rotationParams, caType)
 	}
 
 	// build CA
 	ca := BuildCAFromSecret(ctx, caInternalSecret)
 	if ca == nil {
 		log.Info("Cannot build CA from secret, creating a new one", "owner_namespace", owner.GetNamespace(), "owner_name", owner.GetName(), "ca_type", caType)
-		return renewCA(ctx, cl, namer, owner, labels, rotationParams.Validity, caType)
+		return renewCA(ctx, cl, recorder, namer, owner, labels, rotationParams, caType)
 	}
 
 	// renew or recreate from private key if cannot reuse
-	if !CanReuseCA(ctx, ca, rotationParams.RotateBefore) {
-		if ca.PrivateKey != nil && certExpiring(time.Now(), *ca.Cert, rotationParams.RotateBefore) {
+	now := rotationParams.Now()
+	if !canReuseCAAt(ctx, ca, now, rotationParams.RotateBefore) {
+		if ca.PrivateKey != nil && certExpiring(now, *ca.Cert, rotationParams.RotateBefore) {
 			log.Info("Existing CA is expiring, creating a new one from existing private key", "owner_namespace", owner.GetNamespace(), "owner_name", owner.GetName(), "ca_type", caType)
-			return renewCAFromExisting(ctx, cl, namer, owner, labels, rotationParams.Validity, caType, ca.PrivateKey)
+			return renewCAFromExisting(ctx, cl, recorder, namer, owner, labels, rotationParams, caType, ca.PrivateKey)
 		}
 		log.Info("Cannot reuse existing CA, creating a new one", "owner_namespace", owner.GetNamespace(), "owner_name", owner.GetName(), "ca_type", caType)
-		return renewCA(ctx, cl, namer, owner, labels, rotationParams.Validity, caType)
+		return renewCA(ctx, cl, recorder, namer, owner, labels, rotationParams, caType)
 	}
 
 	// reuse existing CA
@@ -110,10 +113,11 @@ func ReconcileCAForOwner(
 func renewCAFromExisting(
 	ctx context.Context,
 	client k8s.Client,
+	recorder record.EventRecorder,
 	namer name.Namer,
 	owner client.Object,
 	labels map[string]string,
-	expireIn time.Duration,
+	rotationParams RotationParams,
 	caType CAType,
 	signer crypto.Signer,
 ) (*CA, error) {
@@ -127,7 +131,7 @@ func renewCAFromExisting(
 			"name", owner.GetName(),
 			"type", fmt.Sprintf("%T", signer),
 		)
-		return renewCA(ctx, client, namer, owner, labels, expireIn, caType)
+		return renewCA(ctx, client, recorder, namer, owner, labels, rotationParams, caType)
 	}
 
 	log.Info(
@@ -135,13 +139,14 @@ func renewCAFromExisting(
 		"namespace", owner.GetNamespace(),
 		"name", owner.GetName(),
 	)
-	return renewCAWithOptions(ctx, client, namer, owner, labels, caType, CABuilderOptions{
+	return renewCAWithOptions(ctx, client, recorder, namer, owner, labels, caType, CABuilderOptions{
 		Subject: pkix.Name{
 			CommonName:         owner.GetName() + "-" + string(caType),
 			OrganizationalUnit: []string{owner.GetName()},
 		},
-		ExpireIn:   &expireIn,
+		ExpireIn:   &rotationParams.Validity,
 		PrivateKey: privateKey,
+		Clock:      rotationParams.Clock,
 	})
 }
 
@@ -149,18 +154,20 @@ func renewCAFromExisting(
 func renewCA(
 	ctx context.Context,
 	client k8s.Client,
+	recorder record.EventRecorder,
 	namer name.Namer,
 	owner client.Object,
 	labels map[string]string,
-	expireIn time.Duration,
+	rotationParams RotationParams,
 	caType CAType,
 ) (*CA, error) {
-	return renewCAWithOptions(ctx, client, namer, owner, labels, caType, CABuilderOptions{
+	return renewCAWithOptions(ctx, client, recorder, namer, owner, labels, caType, CABuilderOptions{
 		Subject: pkix.Name{
 			CommonName:         owner.GetName() + "-" + string(caType),
 			OrganizationalUnit: []string{owner.GetName()},
 		},
-		ExpireIn: &expireIn,
+		ExpireIn: &rotationParams.Validity,
+		Clock:    rotationParams.Clock,
 	})
 }
 
@@ -169,6 +176,7 @@ func renewCA(
 func renewCAWithOptions(
 	ctx context.Context,
 	client k8s.Client,
+	recorder record.EventRecorder,
 	namer name.Namer,
 	owner client.Object,
 	labels map[string]string,
@@ -184,24 +192,38 @@ func renewCAWithOptions(
 		return nil, err
 	}
 
+	if err := recordReplacingIssuance(ctx, client, &caInternalSecret, CAIssuance); err != nil {
+		return nil, err
+	}
+
 	// create or update internal secret
 	if _, err := reconciler.ReconcileSecret(ctx, client, caInternalSecret, owner); err != nil {
 		return nil, err
 	}
+	RecordIssuanceEvent(recorder, owner, fmt.Sprintf("Issued %s CA certificate", caType))
 
 	return ca, nil
 }
 
 // CanReuseCA returns true if the given CA is valid for reuse
 func CanReuseCA(ctx context.Context, ca *CA, expirationSafetyMargin time.Duration) bool {
-	return PrivateMatchesPublicKey(ctx, ca.Cert.PublicKey, ca.PrivateKey) && CertIsValid(ctx, *ca.Cert, expirationSafetyMargin)
+	return canReuseCAAt(ctx, ca, time.Now(), expirationSafetyMargin)
+}
+
+// canReuseCAAt returns true if the given CA is valid for reuse at the given time.
+func canReuseCAAt(ctx context.Context, ca *CA, now time.Time, expirationSafetyMargin time.Duration) bool {
+	return PrivateMatchesPublicKey(ctx, ca.Cert.PublicKey, ca.PrivateKey) && certIsValidAt(ctx, *ca.Cert, now, expirationSafetyMargin)
 }
 
 // CertIsValid returns true if the given cert is valid,
 // according to a safety time margin.
 func CertIsValid(ctx context.Context, cert x509.Certificate, expirationSafetyMargin time.Duration) bool {
+	return certIsValidAt(ctx, cert, time.Now(), expirationSafetyMargin)
+}
+
+// certIsValidAt returns true if the given cert is valid at the given time, according to a safety time margin.
+func certIsValidAt(ctx context.Context, cert x509.Certificate, now time.Time, expirationSafetyMargin time.Duration) bool {
 	log := ulog.FromContext(ctx)
-	now := time.Now()
 	if now.Before(cert.NotBefore) {
 		log.Info("CA cert is not valid yet", "subject", cert.Subject)
 		return false
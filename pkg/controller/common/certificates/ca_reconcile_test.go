@@ -21,6 +21,7 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/apimachinery/pkg/util/validation"
+	testingclock "k8s.io/utils/clock/testing"
 
 	esv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/elasticsearch/v1"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/name"
@@ -237,7 +238,7 @@ func Test_renewCA(t *testing.T) {
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			ca, err := renewCA(context.Background(), tt.client, testNamer, &testCluster, nil, tt.expireIn, TransportCAType)
+			ca, err := renewCA(context.Background(), tt.client, nil, testNamer, &testCluster, nil, RotationParams{Validity: tt.expireIn}, TransportCAType)
 			require.NoError(t, err)
 			require.NotNil(t, ca)
 			assert.Equal(t, ca.Cert.Issuer.CommonName, testName+"-"+string(TransportCAType))
@@ -315,7 +316,7 @@ func TestReconcileCAForCluster(t *testing.T) {
 		t.Run(tt.name, func(t *testing.T) {
 			ca, err := ReconcileCAForOwner(
 				context.Background(),
-				tt.cl, testNamer, &testCluster, nil, TransportCAType, RotationParams{
+				tt.cl, nil, testNamer, &testCluster, nil, TransportCAType, RotationParams{
 					Validity:     tt.caCertValidity,
 					RotateBefore: DefaultRotateBefore,
 				},
@@ -329,6 +330,34 @@ func TestReconcileCAForCluster(t *testing.T) {
 	}
 }
 
+func TestReconcileCAForOwner_FakeClock(t *testing.T) {
+	fakeClock := testingclock.NewFakePassiveClock(time.Date(2022, 1, 1, 0, 0, 0, 0, time.UTC))
+	rotation := RotationParams{Validity: 10 * 24 * time.Hour, RotateBefore: 24 * time.Hour, Clock: fakeClock}
+	cl := k8s.NewFakeClient()
+	reconcileCA := func() *CA {
+		ca, err := ReconcileCAForOwner(context.Background(), cl, nil, testNamer, &testCluster, nil, TransportCAType, rotation)
+		require.NoError(t, err)
+		return ca
+	}
+
+	// the CA validity is computed from the fake clock
+	ca := reconcileCA()
+	require.True(t, fakeClock.Now().Add(rotation.Validity).Equal(ca.Cert.NotAfter))
+	require.Equal(t, 9*24*time.Hour+time.Second, ShouldRotateIn(rotation.Now(), ca.Cert.NotAfter, rotation.RotateBefore))
+
+	// the CA is reused until the rotation threshold is reached
+	fakeClock.SetTime(fakeClock.Now().Add(9 * 24 * time.Hour))
+	require.True(t, reconcileCA().Cert.Equal(ca.Cert))
+	require.Equal(t, time.Second, ShouldRotateIn(rotation.Now(), ca.Cert.NotAfter, rotation.RotateBefore))
+
+	// past the threshold, a new CA is issued from the same private key
+	fakeClock.SetTime(fakeClock.Now().Add(time.Second))
+	renewed := reconcileCA()
+	require.False(t, renewed.Cert.Equal(ca.Cert))
+	require.True(t, fakeClock.Now().Add(rotation.Validity).Equal(renewed.Cert.NotAfter))
+	require.True(t, PrivateMatchesPublicKey(context.Background(), ca.Cert.PublicKey, renewed.PrivateKey))
+}
+
 func Test_internalSecretForCA(t *testing.T) {
 	testCa, err := NewSelfSignedCA(CABuilderOptions{})
 	require.NoError(t, err)
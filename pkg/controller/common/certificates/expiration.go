@@ -4,7 +4,11 @@
 
 package certificates
 
-import "time"
+import (
+	"time"
+
+	"k8s.io/utils/clock"
+)
 
 const (
 	// DefaultCertValidity makes new certificates default to a 1 year expiration
@@ -20,6 +24,22 @@ type RotationParams struct {
 	Validity time.Duration
 	// RotateBefore defines how long before expiration certificates should be rotated.
 	RotateBefore time.Duration
+	// Clock provides the current time to compute certificates validity and expiration. Defaults to the real clock if
+	// nil, tests can use a fake clock to fast-forward time.
+	Clock clock.PassiveClock
+}
+
+// Now returns the current time according to the clock of the rotation params.
+func (p RotationParams) Now() time.Time {
+	return currentTime(p.Clock)
+}
+
+// currentTime returns the current time according to the given clock, or the real current time if the clock is nil.
+func currentTime(c clock.PassiveClock) time.Time {
+	if c == nil {
+		return time.Now()
+	}
+	return c.Now()
 }
 
 // ShouldRotateIn computes the duration after which a certificate rotation should be scheduled
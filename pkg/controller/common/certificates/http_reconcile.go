@@ -19,6 +19,8 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
 
 	commonv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/common/v1"
@@ -126,7 +128,7 @@ func (r Reconciler) ReconcileInternalHTTPCerts(ctx context.Context, ca *CA, cust
 		}
 	} else {
 		selfSignedNeedsUpdate, err := ensureInternalSelfSignedCertificateSecretContents(
-			ctx, &secret, ownerNSN, r.Namer, r.TLSOptions, r.ExtraHTTPSANs, r.Services, ca, r.CertRotation,
+			ctx, r.Recorder, &secret, r.Owner, r.Namer, r.TLSOptions, r.ExtraHTTPSANs, r.Services, ca, r.CertRotation,
 		)
 		if err != nil {
 			return nil, err
@@ -164,8 +166,9 @@ func (r Reconciler) ReconcileInternalHTTPCerts(ctx context.Context, ca *CA, cust
 // Returns true if the secret was changed.
 func ensureInternalSelfSignedCertificateSecretContents(
 	ctx context.Context,
+	recorder record.EventRecorder,
 	secret *corev1.Secret,
-	owner types.NamespacedName,
+	ownerObj client.Object,
 	namer name.Namer,
 	tls commonv1.TLSOptions,
 	controllerSANs []commonv1.SubjectAlternativeName,
@@ -174,6 +177,7 @@ func ensureInternalSelfSignedCertificateSecretContents(
 	rotationParam RotationParams,
 ) (bool, error) {
 	log := ulog.FromContext(ctx)
+	owner := k8s.ExtractNamespacedName(ownerObj)
 	secretWasChanged := false
 
 	// verify that the secret contains a parsable and compatible private key
@@ -195,7 +199,7 @@ func ensureInternalSelfSignedCertificateSecretContents(
 	}
 
 	// check if the existing cert should be re-issued
-	certificate := getHTTPCertificate(ctx, owner, namer, tls, controllerSANs, secret, svcs, ca, rotationParam.RotateBefore)
+	certificate := getHTTPCertificate(ctx, owner, namer, tls, controllerSANs, secret, svcs, ca, rotationParam)
 	if certificate == nil {
 		log.Info(
 			"Issuing new HTTP certificate",
@@ -218,13 +222,15 @@ func ensureInternalSelfSignedCertificateSecretContents(
 
 		// validate the csr
 		validatedCertificateTemplate := createValidatedHTTPCertificateTemplate(
-			owner, namer, tls, controllerSANs, svcs, parsedCSR, rotationParam.Validity,
+			owner, namer, tls, controllerSANs, svcs, parsedCSR, rotationParam.Now(), rotationParam.Validity,
 		)
 		// sign the certificate
 		certificate, err = ca.CreateCertificate(*validatedCertificateTemplate)
 		if err != nil {
 			return secretWasChanged, err
 		}
+		RecordIssuance(secret, HTTPIssuance)
+		RecordIssuanceEvent(recorder, ownerObj, "Issued HTTP certificate")
 
 		secretWasChanged = true
 		// store certificate and signed certificate in a secret mounted into the pod
@@ -268,12 +274,13 @@ func getHTTPCertificate(
 	secret *corev1.Secret,
 	svcs []corev1.Service,
 	ca *CA,
-	certReconcileBefore time.Duration,
+	rotationParams RotationParams,
 ) []byte {
 	log := ulog.FromContext(ctx)
+	now := rotationParams.Now()
 
 	validatedTemplate := createValidatedHTTPCertificateTemplate(
-		owner, namer, tls, controllerSANs, svcs, &x509.CertificateRequest{}, certReconcileBefore,
+		owner, namer, tls, controllerSANs, svcs, &x509.CertificateRequest{}, now, rotationParams.RotateBefore,
 	)
 
 	var certificate *x509.Certificate
@@ -306,6 +313,7 @@ func getHTTPCertificate(
 		DNSName:       validatedTemplate.Subject.CommonName,
 		Roots:         pool,
 		Intermediates: pool,
+		CurrentTime:   now,
 	}
 	if _, err := certificate.Verify(verifyOpts); err != nil {
 		log.Info(
@@ -321,7 +329,7 @@ func getHTTPCertificate(
 		return nil
 	}
 
-	if time.Now().After(certificate.NotAfter.Add(-certReconcileBefore)) {
+	if now.After(certificate.NotAfter.Add(-rotationParams.RotateBefore)) {
 		log.Info("Certificate soon to expire, should issue new", "namespace", secret.Namespace, "secret_name", secret.Name)
 		return nil
 	}
@@ -330,7 +338,13 @@ func getHTTPCertificate(
 		return nil
 	}
 
-	if !reflect.DeepEqual(certificate.IPAddresses, validatedTemplate.IPAddresses) {
+	// IP SANs become stale when the IP addresses of a Service change, for example when it is recreated
+	if !sameIPAddresses(certificate.IPAddresses, validatedTemplate.IPAddresses) {
+		log.Info(
+			"Certificate IP addresses do not match the expected ones, should issue new",
+			"namespace", secret.Namespace, "secret_name", secret.Name,
+			"actual_ip_addresses", certificate.IPAddresses, "expected_ip_addresses", validatedTemplate.IPAddresses,
+		)
 		return nil
 	}
 
@@ -341,6 +355,24 @@ func getHTTPCertificate(
 	return certificate.Raw
 }
 
+// sameIPAddresses returns true if both lists contain the same IP addresses, regardless of their order.
+func sameIPAddresses(actual, expected []net.IP) bool {
+	if len(actual) != len(expected) {
+		return false
+	}
+	counts := make(map[string]int, len(actual))
+	for _, ip := range actual {
+		counts[ip.String()]++
+	}
+	for _, ip := range expected {
+		if counts[ip.String()] == 0 {
+			return false
+		}
+		counts[ip.String()]--
+	}
+	return true
+}
+
 // createValidatedHTTPCertificateTemplate validates a CSR and creates a certificate template.
 func createValidatedHTTPCertificateTemplate(
 	owner types.NamespacedName,
@@ -349,6 +381,7 @@ func createValidatedHTTPCertificateTemplate(
 	controllerSANs []commonv1.SubjectAlternativeName,
 	svcs []corev1.Service,
 	csr *x509.CertificateRequest,
+	now time.Time,
 	certValidity time.Duration,
 ) *ValidatedCertificateTemplate {
 	defaultSuffixes := strings.Join(namer.DefaultSuffixes, "-")
@@ -403,8 +436,8 @@ func createValidatedHTTPCertificateTemplate(
 		DNSNames:    dnsNames,
 		IPAddresses: ipAddresses,
 
-		NotBefore: time.Now().Add(-10 * time.Minute),
-		NotAfter:  time.Now().Add(certValidity),
+		NotBefore: now.Add(-10 * time.Minute),
+		NotAfter:  now.Add(certValidity),
 
 		PublicKeyAlgorithm: csr.PublicKeyAlgorithm,
 		PublicKey:          csr.PublicKey,
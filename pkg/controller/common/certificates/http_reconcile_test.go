@@ -94,6 +94,7 @@ func init() {
 		[]commonv1.SubjectAlternativeName{},
 		[]corev1.Service{testSvc},
 		testCSR,
+		time.Now(),
 		DefaultCertValidity,
 	)
 
@@ -507,6 +508,7 @@ func Test_createValidatedHTTPCertificateTemplate(t *testing.T) {
 				assert.Contains(t, cert.DNSNames, "controller-san-2")
 				assert.Contains(t, cert.IPAddresses, net.ParseIP(sanIP1).To4())
 				assert.Contains(t, cert.IPAddresses, net.ParseIP(sanIPv6))
+				assert.Contains(t, cert.IPAddresses, net.ParseIP("10.11.12.13").To4())
 			},
 		},
 	}
@@ -519,6 +521,7 @@ func Test_createValidatedHTTPCertificateTemplate(t *testing.T) {
 				tt.args.extraHTTPSANs,
 				tt.args.svcs,
 				&x509.CertificateRequest{},
+				time.Now(),
 				tt.args.certValidity,
 			)
 			if tt.want != nil {
@@ -541,10 +544,13 @@ func Test_getHTTPCertificate(t *testing.T) {
 			},
 		},
 	}
+	recreatedSvc := testSvc.DeepCopy()
+	recreatedSvc.Spec.ClusterIP = "2.2.3.4"
 	type args struct {
 		es             esv1.Elasticsearch
 		controllerSANs []commonv1.SubjectAlternativeName
 		secret         corev1.Secret
+		svcs           []corev1.Service
 		rotateBefore   time.Duration
 	}
 	tests := []struct {
@@ -613,9 +619,27 @@ func Test_getHTTPCertificate(t *testing.T) {
 			},
 			want: nil,
 		},
+		{
+			name: "with a Service recreated with a different IP address",
+			args: args{
+				secret: corev1.Secret{
+					Data: map[string][]byte{
+						CertFileName: pemTLS,
+					},
+				},
+				es:           testES,
+				svcs:         []corev1.Service{*recreatedSvc},
+				rotateBefore: DefaultRotateBefore,
+			},
+			want: nil,
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
+			svcs := tt.args.svcs
+			if svcs == nil {
+				svcs = []corev1.Service{testSvc}
+			}
 			if got := getHTTPCertificate(
 				context.Background(),
 				k8s.ExtractNamespacedName(&tt.args.es),
@@ -623,12 +647,22 @@ func Test_getHTTPCertificate(t *testing.T) {
 				tt.args.es.Spec.HTTP.TLS,
 				tt.args.controllerSANs,
 				&tt.args.secret,
-				[]corev1.Service{testSvc},
+				svcs,
 				testCA,
-				tt.args.rotateBefore,
+				RotationParams{RotateBefore: tt.args.rotateBefore},
 			); !reflect.DeepEqual(got, tt.want) {
 				t.Errorf("shouldIssueNewCertificate() = %v, want %v", got, tt.want)
 			}
 		})
 	}
 }
+
+func Test_sameIPAddresses(t *testing.T) {
+	ipv4 := net.ParseIP("1.2.3.4")
+	ipv6 := net.ParseIP("2001:db8::1")
+	assert.True(t, sameIPAddresses(nil, nil))
+	assert.True(t, sameIPAddresses([]net.IP{ipv4, ipv6}, []net.IP{ipv6, ipv4}))
+	assert.True(t, sameIPAddresses([]net.IP{ipv4}, []net.IP{ipv4.To4()}))
+	assert.False(t, sameIPAddresses([]net.IP{ipv4}, []net.IP{ipv4, ipv6}))
+	assert.False(t, sameIPAddresses([]net.IP{ipv4, ipv4}, []net.IP{ipv4, ipv6}))
+}
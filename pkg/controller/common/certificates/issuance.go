@@ -0,0 +1,121 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package certificates
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/events"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/utils/k8s"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/utils/metrics"
+)
+
+const (
+	// IssuanceWindow is the period over which the certificates issued in each namespace are counted.
+	IssuanceWindow = time.Hour
+
+	// IssuanceAnnotation holds the comma separated issuance times of the certificates stored in a Secret during the
+	// last IssuanceWindow. Persisting them in the Secret allows counting the certificates issued in a namespace across
+	// operator restarts, and from any operator replica serving the validating webhook.
+	IssuanceAnnotation = "certificates.k8s.elastic.co/issued-at"
+
+	// CAIssuance is the type of issuance of a CA certificate.
+	CAIssuance = "ca"
+	// HTTPIssuance is the type of issuance of an HTTP certificate.
+	HTTPIssuance = "http"
+	// TransportIssuance is the type of issuance of an Elasticsearch transport certificate.
+	TransportIssuance = "transport"
+)
+
+// RecordIssuance records the issuance of a certificate of the given type, stored in the given Secret, in the operator
+// metrics and in the issuance annotation of the Secret. The Secret must be written afterwards for the issuance to be
+// counted in its namespace.
+func RecordIssuance(secret *corev1.Secret, issuanceType string) {
+	metrics.CertificatesIssuedCounter.WithLabelValues(issuanceType).Inc()
+	recordIssuanceAt(secret, time.Now())
+}
+
+// recordReplacingIssuance records the issuance of a certificate stored in the given expected Secret, which replaces
+// the current version of the Secret, keeping the issuance times recorded in the latter.
+func recordReplacingIssuance(ctx context.Context, c k8s.Client, expected *corev1.Secret, issuanceType string) error {
+	var current corev1.Secret
+	err := c.Get(ctx, k8s.ExtractNamespacedName(expected), &current)
+	if err != nil && !apierrors.IsNotFound(err) {
+		return err
+	}
+	if issuedAt, exists := current.Annotations[IssuanceAnnotation]; exists {
+		if expected.Annotations == nil {
+			expected.Annotations = make(map[string]string)
+		}
+		expected.Annotations[IssuanceAnnotation] = issuedAt
+	}
+	RecordIssuance(expected, issuanceType)
+	return nil
+}
+
+// recordIssuanceAt adds the given time to the issuance times recorded in the given Secret, dropping the ones out of the
+// issuance window.
+func recordIssuanceAt(secret *corev1.Secret, now time.Time) {
+	issuedAt := make([]string, 0, 1)
+	for _, t := range issuedSince(*secret, now.Add(-IssuanceWindow)) {
+		issuedAt = append(issuedAt, t.Format(time.RFC3339))
+	}
+	issuedAt = append(issuedAt, now.UTC().Format(time.RFC3339))
+	if secret.Annotations == nil {
+		secret.Annotations = make(map[string]string)
+	}
+	secret.Annotations[IssuanceAnnotation] = strings.Join(issuedAt, ",")
+}
+
+// issuedSince returns the issuance times recorded in the given Secret after the given time. Unparseable times are ignored.
+func issuedSince(secret corev1.Secret, threshold time.Time) []time.Time {
+	value, exists := secret.Annotations[IssuanceAnnotation]
+	if !exists || value == "" {
+		return nil
+	}
+	var times []time.Time
+	for _, s := range strings.Split(value, ",") {
+		t, err := time.Parse(time.RFC3339, s)
+		if err != nil || !t.After(threshold) {
+			continue
+		}
+		times = append(times, t)
+	}
+	return times
+}
+
+// RecordIssuanceEvent records the issuance of a certificate as an event on its owner, if a recorder is given.
+func RecordIssuanceEvent(recorder record.EventRecorder, owner runtime.Object, message string) {
+	if recorder == nil {
+		return
+	}
+	recorder.Event(owner, corev1.EventTypeNormal, events.EventReasonCertificateIssued, message)
+}
+
+// IssuedInNamespace returns the number of certificates issued in the given namespace during the last IssuanceWindow,
+// as recorded in the Secrets of the namespace.
+func IssuedInNamespace(ctx context.Context, c k8s.Client, namespace string) (int, error) {
+	return issuedInNamespaceAt(ctx, c, namespace, time.Now())
+}
+
+func issuedInNamespaceAt(ctx context.Context, c k8s.Client, namespace string, now time.Time) (int, error) {
+	var secrets corev1.SecretList
+	if err := c.List(ctx, &secrets, client.InNamespace(namespace)); err != nil {
+		return 0, err
+	}
+	count := 0
+	for _, secret := range secrets.Items {
+		count += len(issuedSince(secret, now.Add(-IssuanceWindow)))
+	}
+	return count, nil
+}
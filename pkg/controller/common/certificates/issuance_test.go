@@ -0,0 +1,63 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package certificates
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/elastic/cloud-on-k8s/v2/pkg/utils/k8s"
+)
+
+func Test_recordIssuanceAt(t *testing.T) {
+	now := time.Date(2022, 10, 1, 12, 0, 0, 0, time.UTC)
+	secret := corev1.Secret{ObjectMeta: metav1.ObjectMeta{Namespace: "ns1", Name: "certs"}}
+
+	require.Empty(t, issuedSince(secret, now.Add(-IssuanceWindow)))
+	recordIssuanceAt(&secret, now)
+	now = now.Add(30 * time.Minute)
+	recordIssuanceAt(&secret, now)
+	require.Equal(t, "2022-10-01T12:00:00Z,2022-10-01T12:30:00Z", secret.Annotations[IssuanceAnnotation])
+	require.Len(t, issuedSince(secret, now.Add(-IssuanceWindow)), 2)
+
+	// the first issuance is out of the window and dropped
+	now = now.Add(31 * time.Minute)
+	require.Len(t, issuedSince(secret, now.Add(-IssuanceWindow)), 1)
+	recordIssuanceAt(&secret, now)
+	require.Equal(t, "2022-10-01T12:30:00Z,2022-10-01T13:01:00Z", secret.Annotations[IssuanceAnnotation])
+
+	// unparseable times are ignored
+	secret.Annotations[IssuanceAnnotation] = "yesterday,2022-10-01T13:01:00Z"
+	require.Len(t, issuedSince(secret, now.Add(-IssuanceWindow)), 1)
+}
+
+func Test_issuedInNamespaceAt(t *testing.T) {
+	now := time.Date(2022, 10, 1, 12, 0, 0, 0, time.UTC)
+	newSecret := func(namespace, name, issuedAt string) *corev1.Secret {
+		return &corev1.Secret{ObjectMeta: metav1.ObjectMeta{
+			Namespace:   namespace,
+			Name:        name,
+			Annotations: map[string]string{IssuanceAnnotation: issuedAt},
+		}}
+	}
+	c := k8s.NewFakeClient(
+		newSecret("ns1", "transport-certs", "2022-10-01T10:00:00Z,2022-10-01T11:30:00Z,2022-10-01T11:45:00Z"),
+		newSecret("ns1", "http-certs", "2022-10-01T11:50:00Z"),
+		newSecret("ns1", "ca", "2022-10-01T10:50:00Z"),
+		newSecret("ns2", "http-certs", "2022-10-01T11:50:00Z"),
+		&corev1.Secret{ObjectMeta: metav1.ObjectMeta{Namespace: "ns1", Name: "other"}},
+	)
+	count, err := issuedInNamespaceAt(context.Background(), c, "ns1", now)
+	require.NoError(t, err)
+	require.Equal(t, 3, count)
+	count, err = issuedInNamespaceAt(context.Background(), c, "ns3", now)
+	require.NoError(t, err)
+	require.Equal(t, 0, count)
+}
@@ -0,0 +1,112 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package certificates
+
+import (
+	"context"
+	"crypto/rsa"
+	"crypto/x509/pkix"
+	"sync"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/labels"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/reconciler"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/utils/k8s"
+	ulog "github.com/elastic/cloud-on-k8s/v2/pkg/utils/log"
+)
+
+const (
+	// NamespaceCASecretName is the name of the Secret holding the CA shared by all the resources of a namespace, when
+	// the operator is configured to use one CA per namespace.
+	NamespaceCASecretName = "eck-namespace-ca-internal"
+	// NamespaceCATypeLabelValue is the value of the type label set on the namespace CA Secrets.
+	NamespaceCATypeLabelValue = "namespace-ca"
+)
+
+// namespaceCAMutex serializes the reconciliation of the namespace CAs, which are shared by resources reconciled
+// concurrently by different controllers.
+var namespaceCAMutex sync.Mutex
+
+// ReconcileNamespaceCA ensures that the CA shared by all the resources of the given namespace exists, and returns it.
+//
+// The CA is persisted in the namespace it is used for, in the `eck-namespace-ca-internal` Secret. It is never used to
+// sign certificates for resources of other namespaces, so that the CA of a namespace cannot be used to issue
+// certificates trusted by the resources of other namespaces. The Secret has no owner: it lives as long as the
+// namespace. Like the CAs of individual resources, it is rotated if it becomes invalid or soon to expire.
+func ReconcileNamespaceCA(ctx context.Context, c k8s.Client, namespace string, rotationParams RotationParams) (*CA, error) {
+	namespaceCAMutex.Lock()
+	defer namespaceCAMutex.Unlock()
+
+	log := ulog.FromContext(ctx)
+	var secret corev1.Secret
+	err := c.Get(ctx, types.NamespacedName{Namespace: namespace, Name: NamespaceCASecretName}, &secret)
+	if err != nil && !apierrors.IsNotFound(err) {
+		return nil, err
+	}
+	if apierrors.IsNotFound(err) {
+		log.Info("No namespace CA certificate Secret found, creating a new one", "namespace", namespace)
+		return renewNamespaceCA(ctx, c, namespace, rotationParams, nil)
+	}
+
+	ca := BuildCAFromSecret(ctx, secret)
+	if ca == nil {
+		log.Info("Cannot build namespace CA from secret, creating a new one", "namespace", namespace)
+		return renewNamespaceCA(ctx, c, namespace, rotationParams, nil)
+	}
+
+	now := rotationParams.Now()
+	if !canReuseCAAt(ctx, ca, now, rotationParams.RotateBefore) {
+		if privateKey, ok := ca.PrivateKey.(*rsa.PrivateKey); ok && certExpiring(now, *ca.Cert, rotationParams.RotateBefore) {
+			log.Info("Existing namespace CA is expiring, creating a new one from existing private key", "namespace", namespace)
+			return renewNamespaceCA(ctx, c, namespace, rotationParams, privateKey)
+		}
+		log.Info("Cannot reuse existing namespace CA, creating a new one", "namespace", namespace)
+		return renewNamespaceCA(ctx, c, namespace, rotationParams, nil)
+	}
+	return ca, nil
+}
+
+// renewNamespaceCA creates and stores a new CA for the given namespace, reusing the given private key if not nil.
+func renewNamespaceCA(ctx context.Context, c k8s.Client, namespace string, rotationParams RotationParams, privateKey *rsa.PrivateKey) (*CA, error) {
+	ca, err := NewSelfSignedCA(CABuilderOptions{
+		Subject: pkix.Name{
+			CommonName:         namespace + "-" + NamespaceCATypeLabelValue,
+			OrganizationalUnit: []string{namespace},
+		},
+		ExpireIn:   &rotationParams.Validity,
+		PrivateKey: privateKey,
+		Clock:      rotationParams.Clock,
+	})
+	if err != nil {
+		return nil, err
+	}
+	privateKeyData, err := EncodePEMPrivateKey(ca.PrivateKey)
+	if err != nil {
+		return nil, err
+	}
+	expected := corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: namespace,
+			Name:      NamespaceCASecretName,
+			Labels:    map[string]string{labels.TypeLabelName: NamespaceCATypeLabelValue},
+		},
+		Data: map[string][]byte{
+			CertFileName: EncodePEMCert(ca.Cert.Raw),
+			KeyFileName:  privateKeyData,
+		},
+	}
+	if err := recordReplacingIssuance(ctx, c, &expected, CAIssuance); err != nil {
+		return nil, err
+	}
+	// the Secret is shared by all the resources of the namespace and has no owner
+	if _, err := reconciler.ReconcileSecret(ctx, c, expected, nil); err != nil {
+		return nil, err
+	}
+	return ca, nil
+}
@@ -0,0 +1,98 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package certificates
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	"github.com/elastic/cloud-on-k8s/v2/pkg/utils/k8s"
+)
+
+func namespaceCASecret(t *testing.T, namespace string, ca *CA) *corev1.Secret {
+	t.Helper()
+	privateKeyData, err := EncodePEMPrivateKey(ca.PrivateKey)
+	require.NoError(t, err)
+	return &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Namespace: namespace, Name: NamespaceCASecretName},
+		Data: map[string][]byte{
+			CertFileName: EncodePEMCert(ca.Cert.Raw),
+			KeyFileName:  privateKeyData,
+		},
+	}
+}
+
+func TestReconcileNamespaceCA(t *testing.T) {
+	rotation := RotationParams{Validity: DefaultCertValidity, RotateBefore: DefaultRotateBefore}
+
+	validCA, err := NewSelfSignedCA(CABuilderOptions{})
+	require.NoError(t, err)
+	soonToExpire := 1 * time.Minute
+	soonToExpireCA, err := NewSelfSignedCA(CABuilderOptions{ExpireIn: &soonToExpire})
+	require.NoError(t, err)
+
+	tests := []struct {
+		name               string
+		cl                 k8s.Client
+		shouldReuseCA      *CA
+		shouldReuseKeyFrom *CA
+	}{
+		{
+			name: "no existing CA",
+			cl:   k8s.NewFakeClient(),
+		},
+		{
+			name:          "existing valid CA",
+			cl:            k8s.NewFakeClient(namespaceCASecret(t, testNamespace, validCA)),
+			shouldReuseCA: validCA,
+		},
+		{
+			name:               "existing CA soon to expire",
+			cl:                 k8s.NewFakeClient(namespaceCASecret(t, testNamespace, soonToExpireCA)),
+			shouldReuseKeyFrom: soonToExpireCA,
+		},
+		{
+			name: "existing CA in another namespace",
+			cl:   k8s.NewFakeClient(namespaceCASecret(t, "other-namespace", validCA)),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ca, err := ReconcileNamespaceCA(context.Background(), tt.cl, testNamespace, rotation)
+			require.NoError(t, err)
+			require.NotNil(t, ca)
+
+			if tt.shouldReuseCA != nil {
+				require.True(t, ca.Cert.Equal(tt.shouldReuseCA.Cert))
+			} else {
+				require.False(t, ca.Cert.Equal(validCA.Cert))
+				require.Equal(t, testNamespace+"-"+NamespaceCATypeLabelValue, ca.Cert.Subject.CommonName)
+			}
+			if tt.shouldReuseKeyFrom != nil {
+				require.False(t, ca.Cert.Equal(tt.shouldReuseKeyFrom.Cert))
+				require.True(t, PrivateMatchesPublicKey(context.Background(), tt.shouldReuseKeyFrom.Cert.PublicKey, ca.PrivateKey))
+			}
+
+			// the CA is stored in the namespace, without owner
+			var secret corev1.Secret
+			require.NoError(t, tt.cl.Get(context.Background(), types.NamespacedName{Namespace: testNamespace, Name: NamespaceCASecretName}, &secret))
+			require.Empty(t, secret.OwnerReferences)
+			stored := BuildCAFromSecret(context.Background(), secret)
+			require.NotNil(t, stored)
+			require.True(t, stored.Cert.Equal(ca.Cert))
+
+			// the CA is reused by the next reconciliations
+			again, err := ReconcileNamespaceCA(context.Background(), tt.cl, testNamespace, rotation)
+			require.NoError(t, err)
+			require.True(t, again.Cert.Equal(ca.Cert))
+		})
+	}
+}
@@ -6,11 +6,11 @@ package certificates
 
 import (
 	"context"
-	"time"
 
 	"go.elastic.co/apm/v2"
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	commonv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/common/v1"
@@ -24,6 +24,7 @@ import (
 type Reconciler struct {
 	K8sClient      k8s.Client
 	DynamicWatches watches.DynamicWatches
+	Recorder       record.EventRecorder // to record the issuance of certificates as events on the owner, optional
 
 	Owner client.Object // owner for the TLS certificates (for ex. Elasticsearch, Kibana)
 
@@ -34,7 +35,8 @@ type Reconciler struct {
 	Labels   map[string]string // to set on the reconciled cert secrets
 	Services []corev1.Service  // to be used for TLS SANs
 
-	GlobalCA *CA // if configured on the operator level supersedes self-signed CAs but not per-resource custom CAs
+	GlobalCA    *CA  // if configured on the operator level supersedes self-signed CAs but not per-resource custom CAs
+	NamespaceCA bool // if true, use the CA shared by all resources of the owner namespace instead of a self-signed CA
 
 	CACertRotation RotationParams // to requeue a reconciliation before CA cert expiration
 	CertRotation   RotationParams // to requeue a reconciliation before cert expiration
@@ -71,23 +73,29 @@ func (r Reconciler) ReconcileCAAndHTTPCerts(ctx context.Context) (*CertificatesS
 	case r.GlobalCA != nil:
 		httpCa = r.GlobalCA
 	default:
-		// if not then reconcile self-signed CA
-		httpCa, err = ReconcileCAForOwner(
-			ctx,
-			r.K8sClient,
-			r.Namer,
-			r.Owner,
-			r.Labels,
-			HTTPCAType,
-			r.CACertRotation,
-		)
+		if r.NamespaceCA {
+			// reconcile the CA shared by all resources of the namespace
+			httpCa, err = ReconcileNamespaceCA(ctx, r.K8sClient, r.Owner.GetNamespace(), r.CACertRotation)
+		} else {
+			// if not then reconcile self-signed CA
+			httpCa, err = ReconcileCAForOwner(
+				ctx,
+				r.K8sClient,
+				r.Recorder,
+				r.Namer,
+				r.Owner,
+				r.Labels,
+				HTTPCAType,
+				r.CACertRotation,
+			)
+		}
 		if err != nil {
 			return nil, results.WithError(err)
 		}
 		// handle CA expiry via requeue
 		results.WithReconciliationState(
 			reconciler.
-				RequeueAfter(ShouldRotateIn(time.Now(), httpCa.Cert.NotAfter, r.CACertRotation.RotateBefore)).
+				RequeueAfter(ShouldRotateIn(r.CACertRotation.Now(), httpCa.Cert.NotAfter, r.CACertRotation.RotateBefore)).
 				ReconciliationComplete(), // This reconciliation result should not prevent the reconciliation loop to be considered as completed in the status
 		)
 	}
@@ -103,7 +111,7 @@ func (r Reconciler) ReconcileCAAndHTTPCerts(ctx context.Context) (*CertificatesS
 	}
 	results.WithReconciliationState(
 		reconciler.
-			RequeueAfter(ShouldRotateIn(time.Now(), primaryCert.NotAfter, r.CertRotation.RotateBefore)).
+			RequeueAfter(ShouldRotateIn(r.CertRotation.Now(), primaryCert.NotAfter, r.CertRotation.RotateBefore)).
 			ReconciliationComplete(), // This reconciliation result should not prevent the reconciliation loop to be considered as completed in the status
 	)
 
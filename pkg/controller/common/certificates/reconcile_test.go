@@ -13,6 +13,7 @@ import (
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
 
 	commonv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/common/v1"
 	esv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/elasticsearch/v1"
@@ -45,10 +46,12 @@ var (
 // inner functions are individually tested elsewhere
 func TestReconcileCAAndHTTPCerts(t *testing.T) {
 	c := k8s.NewFakeClient()
+	recorder := record.NewFakeRecorder(10)
 
 	r := Reconciler{
 		K8sClient:             c,
 		DynamicWatches:        watches.NewDynamicWatches(),
+		Recorder:              recorder,
 		Owner:                 &obj,
 		TLSOptions:            commonv1.TLSOptions{},
 		Namer:                 esv1.ESNamer,
@@ -106,11 +109,15 @@ func TestReconcileCAAndHTTPCerts(t *testing.T) {
 		require.Equal(t, labelsWithSoftOwner, publicCerts.Labels)
 	}
 	checkCertsSecrets()
+	// the issued certificates should have been recorded as events on the owner
+	require.Equal(t, "Normal CertificateIssued Issued http CA certificate", <-recorder.Events)
+	require.Equal(t, "Normal CertificateIssued Issued HTTP certificate", <-recorder.Events)
 
 	// running again should lead to the same results
 	httpCerts, results = r.ReconcileCAAndHTTPCerts(context.Background())
 	checkResults()
 	checkCertsSecrets()
+	require.Empty(t, recorder.Events)
 
 	// disable TLS and run again: should keep existing certs secrets (Elasticsearch use case)
 	r.TLSOptions = commonv1.TLSOptions{SelfSignedCertificate: &commonv1.SelfSignedCertificate{Disabled: true}}
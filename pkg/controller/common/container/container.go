@@ -7,17 +7,22 @@ package container
 import (
 	"fmt"
 	"strings"
+	"sync"
 )
 
 const DefaultContainerRegistry = "docker.elastic.co"
 
 var (
+	// registryLock protects containerRegistry, which can be changed at runtime when the operator configuration is reloaded.
+	registryLock      sync.RWMutex
 	containerRegistry = DefaultContainerRegistry
 	containerSuffix   = ""
 )
 
 // SetContainerRegistry sets the global container registry used to download Elastic stack images.
 func SetContainerRegistry(registry string) {
+	registryLock.Lock()
+	defer registryLock.Unlock()
 	containerRegistry = registry
 }
 
@@ -40,10 +45,13 @@ const (
 	PacketbeatImage       Image = "beats/packetbeat"
 	AgentImage            Image = "beats/elastic-agent"
 	MapsImage             Image = "elastic-maps-service/elastic-maps-server-ubi8"
+	LogstashImage         Image = "logstash/logstash"
 )
 
 // ImageRepository returns the full container image name by concatenating the current container registry and the image path with the given version.
 func ImageRepository(img Image, version string) string {
+	registryLock.RLock()
+	defer registryLock.RUnlock()
 	// don't double append suffix if already contained as e.g. the case for maps
 	if strings.HasSuffix(string(img), containerSuffix) {
 		return fmt.Sprintf("%s/%s:%s", containerRegistry, img, version)
@@ -11,8 +11,11 @@ import (
 
 	"go.elastic.co/apm/module/apmzap/v2"
 	"go.elastic.co/apm/v2"
+	"golang.org/x/time/rate"
+	"k8s.io/client-go/util/workqueue"
 	"sigs.k8s.io/controller-runtime/pkg/controller"
 	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/ratelimiter"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/operator"
@@ -21,8 +24,27 @@ import (
 )
 
 // NewController creates a new controller with the given name, reconciler and parameters and registers it with the manager.
+// Its reconciliations are tracked by the watchdog of the parameters, if any, and detect the manual modifications of the
+// reconciled resources according to the drift detection of the parameters.
 func NewController(mgr manager.Manager, name string, r reconcile.Reconciler, p operator.Parameters) (controller.Controller, error) {
-	return controller.New(name, mgr, controller.Options{Reconciler: r, MaxConcurrentReconciles: p.MaxConcurrentReconciles})
+	p.Controllers.Register(name)
+	return controller.New(name, mgr, controller.Options{
+		Reconciler:              p.Watchdog.Wrap(name, p.DriftDetection.Wrap(r)),
+		MaxConcurrentReconciles: p.MaxConcurrentReconcilesFor(name),
+		RateLimiter:             newRateLimiter(p.ReconcileRateLimiter),
+	})
+}
+
+// newRateLimiter returns a rate limiter combining a per-resource exponential backoff on failures with an overall
+// token bucket, like the controller-runtime default rate limiter, with the given settings.
+func newRateLimiter(p operator.RateLimiterParams) ratelimiter.RateLimiter {
+	if p == (operator.RateLimiterParams{}) {
+		return workqueue.DefaultControllerRateLimiter()
+	}
+	return workqueue.NewMaxOfRateLimiter(
+		workqueue.NewItemExponentialFailureRateLimiter(p.BaseDelay, p.MaxDelay),
+		&workqueue.BucketRateLimiter{Limiter: rate.NewLimiter(rate.Limit(p.QPS), p.Burst)},
+	)
 }
 
 // NewReconciliationContext increments iteration, creates an apm transaction and initiates the logger. Returns context
@@ -0,0 +1,132 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+// Package decisions records the checks and decisions made during a reconciliation, to explain why the operator did, or
+// did not, apply a change. Recording is a no-op unless a Trace has been attached to the context of the reconciliation.
+package decisions
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"k8s.io/apimachinery/pkg/types"
+)
+
+// Outcome is the result of a check or a decision.
+type Outcome string
+
+const (
+	// Passed means a check passed.
+	Passed Outcome = "passed"
+	// Failed means a check failed, usually preventing the operator from moving forward.
+	Failed Outcome = "failed"
+	// Decided means the operator decided to apply a change.
+	Decided Outcome = "decided"
+)
+
+// Decision is a check or a decision made during a reconciliation.
+type Decision struct {
+	// Time is when the decision was made.
+	Time time.Time `json:"time"`
+	// Name identifies the check or the decision, for example "expectations_satisfied".
+	Name string `json:"name"`
+	// Outcome is the result of the check or the decision.
+	Outcome Outcome `json:"outcome"`
+	// Reason explains the outcome.
+	Reason string `json:"reason,omitempty"`
+}
+
+// Trace holds the decisions made during a single reconciliation of a resource.
+type Trace struct {
+	Kind      string     `json:"kind"`
+	Namespace string     `json:"namespace"`
+	Name      string     `json:"name"`
+	Start     time.Time  `json:"start"`
+	End       time.Time  `json:"end"`
+	Decisions []Decision `json:"decisions"`
+
+	mutex sync.Mutex
+}
+
+// NewTrace returns a new Trace for the reconciliation of the given resource.
+func NewTrace(kind string, resource types.NamespacedName) *Trace {
+	return &Trace{Kind: kind, Namespace: resource.Namespace, Name: resource.Name, Start: time.Now()}
+}
+
+func (t *Trace) record(name string, outcome Outcome, reason string) {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	t.Decisions = append(t.Decisions, Decision{Time: time.Now(), Name: name, Outcome: outcome, Reason: reason})
+}
+
+func (t *Trace) end() {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	t.End = time.Now()
+}
+
+// snapshot returns a copy of the trace which can be read while decisions are still being recorded.
+func (t *Trace) snapshot() *Trace {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	return &Trace{
+		Kind:      t.Kind,
+		Namespace: t.Namespace,
+		Name:      t.Name,
+		Start:     t.Start,
+		End:       t.End,
+		Decisions: append([]Decision(nil), t.Decisions...),
+	}
+}
+
+type traceKey struct{}
+
+// NewContext returns a context holding the given trace.
+func NewContext(ctx context.Context, trace *Trace) context.Context {
+	return context.WithValue(ctx, traceKey{}, trace)
+}
+
+// FromContext returns the trace held by the given context, or nil.
+func FromContext(ctx context.Context) *Trace {
+	if ctx == nil {
+		return nil
+	}
+	trace, _ := ctx.Value(traceKey{}).(*Trace)
+	return trace
+}
+
+// Start attaches a new trace for the reconciliation of the given resource to the given context. The returned function
+// must be called at the end of the reconciliation to store the trace. It is a no-op if the store is nil.
+func Start(ctx context.Context, store *Store, kind string, resource types.NamespacedName) (context.Context, func()) {
+	if store == nil {
+		return ctx, func() {}
+	}
+	trace := NewTrace(kind, resource)
+	return NewContext(ctx, trace), func() {
+		trace.end()
+		store.Save(trace)
+	}
+}
+
+// Pass records a check that passed.
+func Pass(ctx context.Context, name string, reason string) {
+	record(ctx, name, Passed, reason)
+}
+
+// Fail records a check that failed.
+func Fail(ctx context.Context, name string, reason string) {
+	record(ctx, name, Failed, reason)
+}
+
+// Decide records a change the operator decided to apply.
+func Decide(ctx context.Context, name string, reason string) {
+	record(ctx, name, Decided, reason)
+}
+
+func record(ctx context.Context, name string, outcome Outcome, reason string) {
+	if trace := FromContext(ctx); trace != nil {
+		trace.record(name, outcome, reason)
+	}
+}
@@ -0,0 +1,119 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package decisions
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+func TestRecord_NoTrace(t *testing.T) {
+	// recording without a trace in the context must be a no-op
+	ctx := context.Background()
+	Pass(ctx, "check", "")
+	Fail(ctx, "check", "reason")
+	Decide(ctx, "decision", "reason")
+	require.Nil(t, FromContext(ctx))
+
+	// same without a store
+	ctx, save := Start(ctx, nil, "Elasticsearch", types.NamespacedName{Namespace: "ns", Name: "es"})
+	Fail(ctx, "check", "reason")
+	save()
+	require.Nil(t, FromContext(ctx))
+}
+
+func TestStart(t *testing.T) {
+	store := NewStore()
+	nsn := types.NamespacedName{Namespace: "ns", Name: "es"}
+	ctx, save := Start(context.Background(), store, "Elasticsearch", nsn)
+	Pass(ctx, "expectations_satisfied", "")
+	Fail(ctx, "upgrade_predicates", "Pod es-default-0 cannot be restarted: predicate require_started_replica")
+	Decide(ctx, "delete_pod", "Deleting pod for rolling upgrade: es-default-1")
+
+	// nothing is stored until the reconciliation is over
+	require.Empty(t, store.Get("", "", ""))
+	save()
+
+	traces := store.Get("Elasticsearch", "ns", "es")
+	require.Len(t, traces, 1)
+	require.Equal(t, "Elasticsearch", traces[0].Kind)
+	require.Equal(t, "ns", traces[0].Namespace)
+	require.Equal(t, "es", traces[0].Name)
+	require.False(t, traces[0].End.Before(traces[0].Start))
+	require.Len(t, traces[0].Decisions, 3)
+	require.Equal(t, Passed, traces[0].Decisions[0].Outcome)
+	require.Equal(t, "upgrade_predicates", traces[0].Decisions[1].Name)
+	require.Equal(t, Failed, traces[0].Decisions[1].Outcome)
+	require.Equal(t, Decided, traces[0].Decisions[2].Outcome)
+	require.Equal(t, "Deleting pod for rolling upgrade: es-default-1", traces[0].Decisions[2].Reason)
+}
+
+func TestStore(t *testing.T) {
+	store := NewStore()
+	es1 := types.NamespacedName{Namespace: "ns1", Name: "es"}
+	es2 := types.NamespacedName{Namespace: "ns2", Name: "es"}
+	for i := 0; i < tracesPerResource+2; i++ {
+		trace := NewTrace("Elasticsearch", es1)
+		trace.record(fmt.Sprintf("decision-%d", i), Decided, "")
+		store.Save(trace)
+	}
+	store.Save(NewTrace("Elasticsearch", es2))
+	store.Save(NewTrace("Kibana", es2))
+
+	// only the most recent traces are kept
+	traces := store.Get("Elasticsearch", "ns1", "es")
+	require.Len(t, traces, tracesPerResource)
+	require.Equal(t, "decision-2", traces[0].Decisions[0].Name)
+	require.Equal(t, fmt.Sprintf("decision-%d", tracesPerResource+1), traces[tracesPerResource-1].Decisions[0].Name)
+
+	// filters
+	require.Len(t, store.Get("", "", ""), tracesPerResource+2)
+	require.Len(t, store.Get("", "", "es"), tracesPerResource+2)
+	require.Len(t, store.Get("", "ns2", ""), 2)
+	require.Len(t, store.Get("Kibana", "", ""), 1)
+	require.Empty(t, store.Get("Elasticsearch", "ns3", ""))
+
+	// deleted resources are forgotten
+	store.Forget("Elasticsearch", es1)
+	require.Empty(t, store.Get("Elasticsearch", "ns1", ""))
+	require.Len(t, store.Get("", "ns2", ""), 2)
+}
+
+func TestStore_ServeHTTP(t *testing.T) {
+	store := NewStore()
+	trace := NewTrace("Elasticsearch", types.NamespacedName{Namespace: "ns", Name: "es"})
+	trace.record("elasticsearch_reachable", Failed, "Elasticsearch cannot be reached yet")
+	store.Save(trace)
+	store.Save(NewTrace("Elasticsearch", types.NamespacedName{Namespace: "ns", Name: "other"}))
+
+	tests := []struct {
+		name       string
+		query      string
+		wantTraces int
+	}{
+		{name: "all traces", query: "", wantTraces: 2},
+		{name: "filter by name", query: "?namespace=ns&name=es", wantTraces: 1},
+		{name: "no match", query: "?namespace=unknown", wantTraces: 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			recorder := httptest.NewRecorder()
+			store.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, HandlerPath+tt.query, nil))
+			require.Equal(t, http.StatusOK, recorder.Code)
+			require.Equal(t, "application/json", recorder.Header().Get("Content-Type"))
+			var traces []Trace
+			require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &traces))
+			require.NotNil(t, traces)
+			require.Len(t, traces, tt.wantTraces)
+		})
+	}
+}
@@ -0,0 +1,108 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package decisions
+
+import (
+	"encoding/json"
+	"net/http"
+	"sort"
+	"sync"
+
+	"k8s.io/apimachinery/pkg/types"
+)
+
+const (
+	// HandlerPath is the path of the debug HTTP endpoint serving the decision traces.
+	HandlerPath = "/debug/decisions"
+	// tracesPerResource is the number of traces kept for each resource.
+	tracesPerResource = 5
+)
+
+type resourceKey struct {
+	kind     string
+	resource types.NamespacedName
+}
+
+// Store keeps the most recent traces of each reconciled resource in memory.
+type Store struct {
+	mutex  sync.RWMutex
+	traces map[resourceKey][]*Trace
+}
+
+// NewStore returns an empty Store.
+func NewStore() *Store {
+	return &Store{traces: make(map[resourceKey][]*Trace)}
+}
+
+// Save stores the given trace, evicting the oldest trace of the same resource if needed.
+func (s *Store) Save(trace *Trace) {
+	if s == nil || trace == nil {
+		return
+	}
+	key := resourceKey{kind: trace.Kind, resource: types.NamespacedName{Namespace: trace.Namespace, Name: trace.Name}}
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	traces := append(s.traces[key], trace)
+	if len(traces) > tracesPerResource {
+		traces = traces[len(traces)-tracesPerResource:]
+	}
+	s.traces[key] = traces
+}
+
+// Forget removes the traces of the given resource, for example once it has been deleted.
+func (s *Store) Forget(kind string, resource types.NamespacedName) {
+	if s == nil {
+		return
+	}
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	delete(s.traces, resourceKey{kind: kind, resource: resource})
+}
+
+// Get returns the stored traces matching the given kind, namespace and name, from the oldest to the most recent for each
+// resource. Empty values match any resource.
+func (s *Store) Get(kind, namespace, name string) []*Trace {
+	if s == nil {
+		return nil
+	}
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+	keys := make([]resourceKey, 0, len(s.traces))
+	for key := range s.traces {
+		if (kind == "" || key.kind == kind) &&
+			(namespace == "" || key.resource.Namespace == namespace) &&
+			(name == "" || key.resource.Name == name) {
+			keys = append(keys, key)
+		}
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if keys[i].kind != keys[j].kind {
+			return keys[i].kind < keys[j].kind
+		}
+		return keys[i].resource.String() < keys[j].resource.String()
+	})
+	var result []*Trace
+	for _, key := range keys {
+		for _, trace := range s.traces[key] {
+			result = append(result, trace.snapshot())
+		}
+	}
+	return result
+}
+
+// ServeHTTP serves the stored traces as JSON. The kind, namespace and name query parameters filter the traces.
+func (s *Store) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	traces := s.Get(query.Get("kind"), query.Get("namespace"), query.Get("name"))
+	if traces == nil {
+		traces = []*Trace{}
+	}
+	w.Header().Set("Content-Type", "application/json")
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(traces); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
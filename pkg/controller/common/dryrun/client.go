@@ -0,0 +1,142 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+// Package dryrun provides a Kubernetes client which validates the changes the operator intends to make with dry-run
+// requests and reports them, without persisting them.
+package dryrun
+
+import (
+	"context"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	typedcorev1 "k8s.io/client-go/kubernetes/typed/core/v1"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/cache"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/apiutil"
+	"sigs.k8s.io/controller-runtime/pkg/cluster"
+
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/events"
+	ulog "github.com/elastic/cloud-on-k8s/v2/pkg/utils/log"
+)
+
+// EventSourceComponent is the source of the events reporting the changes the operator would make in dry-run mode.
+const EventSourceComponent = "elastic-operator-dry-run"
+
+// NewClientFunc wraps the given function creating the client of the controller manager, so that all the writes
+// are sent as dry-run requests: they are validated by the API server, including admission webhooks, but not persisted.
+// Each write is logged and reported with an event on the controller owner of the object, or on the object itself.
+func NewClientFunc(newClient cluster.NewClientFunc) cluster.NewClientFunc {
+	return func(cache cache.Cache, config *rest.Config, options client.Options, uncachedObjects ...client.Object) (client.Client, error) {
+		c, err := newClient(cache, config, options, uncachedObjects...)
+		if err != nil {
+			return nil, err
+		}
+		clientset, err := kubernetes.NewForConfig(config)
+		if err != nil {
+			return nil, err
+		}
+		// events are not written through the manager client, which would skip them
+		broadcaster := record.NewBroadcaster()
+		broadcaster.StartRecordingToSink(&typedcorev1.EventSinkImpl{Interface: clientset.CoreV1().Events("")})
+		recorder := broadcaster.NewRecorder(clientgoscheme.Scheme, corev1.EventSource{Component: EventSourceComponent})
+		ulog.Log.Info("Running in dry-run mode, changes to Kubernetes resources and Elasticsearch clusters are reported but not applied")
+		return NewClient(c, recorder), nil
+	}
+}
+
+// NewClient returns a client sending all the writes as dry-run requests and reporting them with the given recorder.
+func NewClient(c client.Client, recorder record.EventRecorder) client.Client {
+	return &dryRunClient{Client: c, recorder: recorder}
+}
+
+type dryRunClient struct {
+	client.Client
+	recorder record.EventRecorder
+}
+
+var _ client.Client = &dryRunClient{}
+
+// report logs the intended write and records an event about it.
+func (c *dryRunClient) report(verb string, obj client.Object) {
+	kind := obj.GetObjectKind().GroupVersionKind().Kind
+	if gvk, err := apiutil.GVKForObject(obj, c.Scheme()); err == nil {
+		kind = gvk.Kind
+	}
+	msg := fmt.Sprintf("Dry run: would %s %s %s/%s", verb, kind, obj.GetNamespace(), obj.GetName())
+	ulog.Log.Info(msg, "namespace", obj.GetNamespace(), "name", obj.GetName(), "kind", kind, "verb", verb)
+	if c.recorder == nil {
+		return
+	}
+	c.recorder.Event(c.eventTarget(obj), corev1.EventTypeNormal, events.EventReasonDryRun, msg)
+}
+
+// eventTarget returns a reference to the controller owner of the given object if any, since the object itself may not
+// exist, or the object otherwise.
+func (c *dryRunClient) eventTarget(obj client.Object) *corev1.ObjectReference {
+	if owner := metav1.GetControllerOf(obj); owner != nil {
+		return &corev1.ObjectReference{
+			APIVersion: owner.APIVersion,
+			Kind:       owner.Kind,
+			Namespace:  obj.GetNamespace(),
+			Name:       owner.Name,
+			UID:        owner.UID,
+		}
+	}
+	ref := &corev1.ObjectReference{Namespace: obj.GetNamespace(), Name: obj.GetName(), UID: obj.GetUID()}
+	if gvk, err := apiutil.GVKForObject(obj, c.Scheme()); err == nil {
+		ref.APIVersion, ref.Kind = gvk.GroupVersion().String(), gvk.Kind
+	}
+	return ref
+}
+
+func (c *dryRunClient) Create(ctx context.Context, obj client.Object, opts ...client.CreateOption) error {
+	c.report("create", obj)
+	return c.Client.Create(ctx, obj, append(opts, client.DryRunAll)...)
+}
+
+func (c *dryRunClient) Update(ctx context.Context, obj client.Object, opts ...client.UpdateOption) error {
+	c.report("update", obj)
+	return c.Client.Update(ctx, obj, append(opts, client.DryRunAll)...)
+}
+
+func (c *dryRunClient) Patch(ctx context.Context, obj client.Object, patch client.Patch, opts ...client.PatchOption) error {
+	c.report("patch", obj)
+	return c.Client.Patch(ctx, obj, patch, append(opts, client.DryRunAll)...)
+}
+
+func (c *dryRunClient) Delete(ctx context.Context, obj client.Object, opts ...client.DeleteOption) error {
+	c.report("delete", obj)
+	return c.Client.Delete(ctx, obj, append(opts, client.DryRunAll)...)
+}
+
+func (c *dryRunClient) DeleteAllOf(ctx context.Context, obj client.Object, opts ...client.DeleteAllOfOption) error {
+	deleteAllOfOptions := client.DeleteAllOfOptions{}
+	deleteAllOfOptions.ApplyOptions(opts)
+	ulog.Log.Info("Dry run: would delete all matching objects", "namespace", deleteAllOfOptions.Namespace, "kind", obj.GetObjectKind().GroupVersionKind().Kind)
+	return c.Client.DeleteAllOf(ctx, obj, append(opts, client.DryRunAll)...)
+}
+
+func (c *dryRunClient) Status() client.StatusWriter {
+	return &dryRunStatusWriter{c: c}
+}
+
+type dryRunStatusWriter struct {
+	c *dryRunClient
+}
+
+func (w *dryRunStatusWriter) Update(ctx context.Context, obj client.Object, opts ...client.UpdateOption) error {
+	w.c.report("update the status of", obj)
+	return w.c.Client.Status().Update(ctx, obj, append(opts, client.DryRunAll)...)
+}
+
+func (w *dryRunStatusWriter) Patch(ctx context.Context, obj client.Object, patch client.Patch, opts ...client.PatchOption) error {
+	w.c.report("patch the status of", obj)
+	return w.c.Client.Status().Patch(ctx, obj, patch, append(opts, client.DryRunAll)...)
+}
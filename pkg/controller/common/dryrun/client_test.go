@@ -0,0 +1,89 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package dryrun
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/utils/pointer"
+
+	"github.com/elastic/cloud-on-k8s/v2/pkg/utils/k8s"
+)
+
+func TestDryRunClient(t *testing.T) {
+	ctx := context.Background()
+	existing := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "existing"},
+		Data:       map[string][]byte{"key": []byte("value")},
+	}
+	k8sClient := k8s.NewFakeClient(existing)
+	recorder := record.NewFakeRecorder(10)
+	c := NewClient(k8sClient, recorder)
+
+	// objects are not created
+	created := &corev1.Secret{ObjectMeta: metav1.ObjectMeta{
+		Namespace: "ns",
+		Name:      "created",
+		OwnerReferences: []metav1.OwnerReference{{
+			APIVersion: "elasticsearch.k8s.elastic.co/v1",
+			Kind:       "Elasticsearch",
+			Name:       "es",
+			Controller: pointer.Bool(true),
+		}},
+	}}
+	require.NoError(t, c.Create(ctx, created))
+	require.True(t, errors.IsNotFound(k8sClient.Get(ctx, k8s.ExtractNamespacedName(created), &corev1.Secret{})))
+	require.Equal(t, "Normal DryRun Dry run: would create Secret ns/created", <-recorder.Events)
+
+	// objects are not updated
+	updated := existing.DeepCopy()
+	updated.Data = map[string][]byte{"key": []byte("other")}
+	require.NoError(t, c.Update(ctx, updated))
+	var actual corev1.Secret
+	require.NoError(t, k8sClient.Get(ctx, k8s.ExtractNamespacedName(existing), &actual))
+	require.Equal(t, existing.Data, actual.Data)
+	require.Equal(t, "Normal DryRun Dry run: would update Secret ns/existing", <-recorder.Events)
+
+	// objects are not deleted
+	require.NoError(t, c.Delete(ctx, existing.DeepCopy()))
+	require.NoError(t, k8sClient.Get(ctx, k8s.ExtractNamespacedName(existing), &corev1.Secret{}))
+	require.Equal(t, "Normal DryRun Dry run: would delete Secret ns/existing", <-recorder.Events)
+}
+
+func TestDryRunClient_eventTarget(t *testing.T) {
+	c := &dryRunClient{Client: k8s.NewFakeClient()}
+	owned := &corev1.Secret{ObjectMeta: metav1.ObjectMeta{
+		Namespace: "ns",
+		Name:      "es-es-http-certs-internal",
+		OwnerReferences: []metav1.OwnerReference{{
+			APIVersion: "elasticsearch.k8s.elastic.co/v1",
+			Kind:       "Elasticsearch",
+			Name:       "es",
+			UID:        "uid",
+			Controller: pointer.Bool(true),
+		}},
+	}}
+	require.Equal(t, &corev1.ObjectReference{
+		APIVersion: "elasticsearch.k8s.elastic.co/v1",
+		Kind:       "Elasticsearch",
+		Namespace:  "ns",
+		Name:       "es",
+		UID:        "uid",
+	}, c.eventTarget(owned))
+
+	notOwned := &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "secret"}}
+	require.Equal(t, &corev1.ObjectReference{
+		APIVersion: "v1",
+		Kind:       "Secret",
+		Namespace:  "ns",
+		Name:       "secret",
+	}, c.eventTarget(notOwned))
+}
@@ -0,0 +1,56 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package dryrun
+
+import (
+	"context"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
+
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/events"
+	ulog "github.com/elastic/cloud-on-k8s/v2/pkg/utils/log"
+)
+
+// Mode is the dry-run mode of the operator, in which the changes made through the APIs of the Elastic Stack
+// applications are reported with events instead of being applied. A nil Mode disables the dry-run mode.
+type Mode struct {
+	recorder record.EventRecorder
+}
+
+// NewMode returns a dry-run mode emitting events with the given recorder.
+func NewMode(recorder record.EventRecorder) *Mode {
+	return &Mode{recorder: recorder}
+}
+
+// Enabled returns true if the operator runs in dry-run mode.
+func (m *Mode) Enabled() bool {
+	return m != nil
+}
+
+// ReporterFor returns a reporter of the changes made to the application managed through the given resource, or nil
+// if the dry-run mode is disabled.
+func (m *Mode) ReporterFor(obj runtime.Object) *Reporter {
+	if m == nil {
+		return nil
+	}
+	return &Reporter{recorder: m.recorder, target: obj}
+}
+
+// Reporter reports the changes the operator would make to an application with events on the resource managing it.
+type Reporter struct {
+	recorder record.EventRecorder
+	target   runtime.Object
+}
+
+// Report logs the given change, which is not applied, and records an event about it.
+func (r *Reporter) Report(ctx context.Context, msg string) {
+	ulog.FromContext(ctx).Info(msg)
+	if r.recorder == nil {
+		return
+	}
+	r.recorder.Event(r.target, corev1.EventTypeNormal, events.EventReasonDryRun, msg)
+}
@@ -0,0 +1,29 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package dryrun
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+)
+
+func TestMode_ReporterFor(t *testing.T) {
+	obj := &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "secret"}}
+
+	var disabled *Mode
+	require.False(t, disabled.Enabled())
+	require.Nil(t, disabled.ReporterFor(obj))
+
+	recorder := record.NewFakeRecorder(10)
+	mode := NewMode(recorder)
+	require.True(t, mode.Enabled())
+	mode.ReporterFor(obj).Report(context.Background(), "Dry run: would send a request")
+	require.Equal(t, "Normal DryRun Dry run: would send a request", <-recorder.Events)
+}
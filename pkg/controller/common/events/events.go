@@ -6,12 +6,28 @@ package events
 
 // Event reasons for the Elastic stack controller
 const (
+	// EventReasonCertificateIssued describes events where a TLS certificate was issued by the operator.
+	EventReasonCertificateIssued = "CertificateIssued"
+	// EventReasonConflict describes events where a resource declared by the user conflicts with another resource.
+	EventReasonConflict = "Conflict"
 	// EventReasonDeprecated describes events that were due to a deprecated resource being submitted by the user.
 	EventReasonDeprecated = "Deprecated"
+	// EventReasonDryRun describes events about a change that the operator would have made if not running in dry-run mode.
+	EventReasonDryRun = "DryRun"
+	// EventReasonDrift describes events where a resource managed by the operator was modified outside the operator.
+	EventReasonDrift = "Drift"
 	// EventReasonDelayed describes events where a requested change was delayed e.g. to prevent data loss.
 	EventReasonDelayed = "Delayed"
 	// EventReasonInvalidLicense describes events where a user configured an invalid license for the operator.
 	EventReasonInvalidLicense = "InvalidLicense"
+	// EventReasonLicenseExpiring describes events about a license which expires soon.
+	EventReasonLicenseExpiring = "LicenseExpiring"
+	// EventReasonPasswordRotation describes events about the rotation of the password of a user managed by the operator.
+	EventReasonPasswordRotation = "PasswordRotation"
+	// EventReasonRestore describes events about the restore of a snapshot in a new cluster.
+	EventReasonRestore = "Restore"
+	// EventReasonSnapshot describes events about the snapshots scheduled in the snapshot repositories.
+	EventReasonSnapshot = "Snapshot"
 	// EventReasonStalled describes events where a requested change is stalled and may not make progress without user
 	// intervention. There are transient states e.g. during a nodeSet rename where shards still do not have a place to
 	// move to until the new nodes come up and Elasticsearch will report a stalled shutdown. There are however also
@@ -0,0 +1,363 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package expose
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"go.elastic.co/apm/v2"
+	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/utils/pointer"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	commonv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/common/v1"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/certificates"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/hash"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/name"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/reconciler"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/tracing"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/utils/k8s"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/utils/maps"
+)
+
+const (
+	// SSLPassthroughAnnotation enables TLS passthrough in the ingress-nginx controller.
+	SSLPassthroughAnnotation = "nginx.ingress.kubernetes.io/ssl-passthrough"
+	// BackendProtocolAnnotation sets the protocol used by the ingress-nginx controller to reach the backend Service.
+	BackendProtocolAnnotation = "nginx.ingress.kubernetes.io/backend-protocol"
+	// ExposedAsAnnotation records on the exposed resource the type of the Ingress or Route created by the operator, so
+	// that Ingresses and Routes are only read and deleted for the resources which were exposed.
+	ExposedAsAnnotation = "eck.k8s.elastic.co/exposed-as"
+)
+
+// RouteGVK is the GroupVersionKind of the OpenShift Routes.
+var RouteGVK = schema.GroupVersionKind{Group: "route.openshift.io", Version: "v1", Kind: "Route"}
+
+var (
+	routeAvailableMutex sync.Mutex
+	routeAvailable      *bool
+)
+
+// SubjectAltNames returns the SANs to add to the self-signed HTTP certificate for the HTTP endpoint to be reachable
+// through the exposed host.
+func SubjectAltNames(http commonv1.HTTPConfig) []commonv1.SubjectAlternativeName {
+	if http.Expose == nil {
+		return nil
+	}
+	return []commonv1.SubjectAlternativeName{{DNS: http.Expose.Host}}
+}
+
+// Reconciler reconciles the Ingress or the OpenShift Route exposing the HTTP Service of a resource
+// outside of the Kubernetes cluster.
+type Reconciler struct {
+	K8sClient k8s.Client
+	Owner     client.Object
+	HTTP      commonv1.HTTPConfig
+	// Service is the HTTP Service of the resource, the Ingress or Route has the same name.
+	Service corev1.Service
+	Namer   name.Namer
+	Labels  map[string]string
+}
+
+// Reconcile creates or updates the Ingress or the Route described in the HTTP configuration, and deletes
+// the one that is not expected anymore.
+func (r Reconciler) Reconcile(ctx context.Context) error {
+	span, ctx := apm.StartSpan(ctx, "reconcile_expose", tracing.SpanTypeApp)
+	defer span.End()
+
+	var expected commonv1.ExposeType
+	switch {
+	case r.HTTP.Expose == nil:
+	case r.HTTP.Expose.IsRoute():
+		expected = commonv1.RouteExposeType
+	default:
+		expected = commonv1.IngressExposeType
+	}
+
+	if previous := commonv1.ExposeType(r.Owner.GetAnnotations()[ExposedAsAnnotation]); previous != expected {
+		switch previous {
+		case commonv1.IngressExposeType:
+			if err := r.deleteIngress(ctx); err != nil {
+				return err
+			}
+		case commonv1.RouteExposeType:
+			if err := r.deleteRoute(ctx); err != nil {
+				return err
+			}
+		}
+		// record the new type before creating the Ingress or the Route, to delete it later on
+		if err := r.recordExposedAs(ctx, expected); err != nil {
+			return err
+		}
+	}
+
+	switch expected {
+	case commonv1.RouteExposeType:
+		return r.reconcileRoute(ctx)
+	case commonv1.IngressExposeType:
+		return r.reconcileIngress(ctx)
+	default:
+		return nil
+	}
+}
+
+// recordExposedAs records the given expose type in the annotations of the owner, or removes the annotation if empty.
+func (r Reconciler) recordExposedAs(ctx context.Context, exposedAs commonv1.ExposeType) error {
+	patch := client.MergeFrom(r.Owner.DeepCopyObject().(client.Object)) //nolint:forcetypeassert
+	annotations := r.Owner.GetAnnotations()
+	if exposedAs == "" {
+		delete(annotations, ExposedAsAnnotation)
+	} else {
+		if annotations == nil {
+			annotations = make(map[string]string)
+		}
+		annotations[ExposedAsAnnotation] = string(exposedAs)
+	}
+	r.Owner.SetAnnotations(annotations)
+	return r.K8sClient.Patch(ctx, r.Owner, patch)
+}
+
+func (r Reconciler) reconcileIngress(ctx context.Context) error {
+	expected := r.newIngress()
+	// label the Ingress with a hash of its content, for comparison purposes
+	expected.Labels = hash.SetTemplateHashLabel(expected.Labels, expected)
+
+	reconciled := &networkingv1.Ingress{}
+	return reconciler.ReconcileResource(reconciler.Params{
+		Context:    ctx,
+		Client:     r.K8sClient,
+		Owner:      r.Owner,
+		Expected:   expected,
+		Reconciled: reconciled,
+		NeedsUpdate: func() bool {
+			return hash.GetTemplateHashLabel(expected.Labels) != hash.GetTemplateHashLabel(reconciled.Labels)
+		},
+		UpdateReconciled: func() {
+			reconciled.Labels = expected.Labels
+			reconciled.Annotations = expected.Annotations
+			reconciled.Spec = expected.Spec
+		},
+	})
+}
+
+func (r Reconciler) newIngress() *networkingv1.Ingress {
+	expose := r.HTTP.Expose
+	pathType := networkingv1.PathTypePrefix
+	ingress := &networkingv1.Ingress{
+		ObjectMeta: r.objectMeta(),
+		Spec: networkingv1.IngressSpec{
+			IngressClassName: expose.IngressClassName,
+			Rules: []networkingv1.IngressRule{{
+				Host: expose.Host,
+				IngressRuleValue: networkingv1.IngressRuleValue{
+					HTTP: &networkingv1.HTTPIngressRuleValue{
+						Paths: []networkingv1.HTTPIngressPath{{
+							Path:     "/",
+							PathType: &pathType,
+							Backend: networkingv1.IngressBackend{
+								Service: &networkingv1.IngressServiceBackend{
+									Name: r.Service.Name,
+									Port: networkingv1.ServiceBackendPort{Number: r.servicePort()},
+								},
+							},
+						}},
+					},
+				},
+			}},
+		},
+	}
+
+	if !r.HTTP.TLS.Enabled() {
+		return ingress
+	}
+
+	// default to the ingress-nginx annotations, which can be overridden in the metadata of the expose configuration
+	defaultAnnotations := map[string]string{BackendProtocolAnnotation: "HTTPS"}
+	if expose.IsReencrypt() {
+		// serve the HTTP certificate, which includes the exposed host in its SANs
+		ingress.Spec.TLS = []networkingv1.IngressTLS{{
+			Hosts:      []string{expose.Host},
+			SecretName: certificates.InternalCertsSecretName(r.Namer, r.Owner.GetName()),
+		}}
+	} else {
+		defaultAnnotations[SSLPassthroughAnnotation] = "true"
+	}
+	ingress.Annotations = maps.Merge(defaultAnnotations, ingress.Annotations)
+	return ingress
+}
+
+func (r Reconciler) reconcileRoute(ctx context.Context) error {
+	available, err := isRouteAvailable(r.K8sClient)
+	if err != nil {
+		return err
+	}
+	if !available {
+		return fmt.Errorf("cannot expose %s/%s through a Route: %s is not available in this cluster",
+			r.Owner.GetNamespace(), r.Owner.GetName(), RouteGVK.GroupVersion().String())
+	}
+
+	expected, err := r.newRoute(ctx)
+	if err != nil {
+		return err
+	}
+	// label the Route with a hash of its content, for comparison purposes
+	expected.SetLabels(hash.SetTemplateHashLabel(expected.GetLabels(), expected.Object))
+
+	reconciled := &unstructured.Unstructured{}
+	reconciled.SetGroupVersionKind(RouteGVK)
+	return reconciler.ReconcileResource(reconciler.Params{
+		Context:    ctx,
+		Client:     r.K8sClient,
+		Owner:      r.Owner,
+		Expected:   expected,
+		Reconciled: reconciled,
+		NeedsUpdate: func() bool {
+			return hash.GetTemplateHashLabel(expected.GetLabels()) != hash.GetTemplateHashLabel(reconciled.GetLabels())
+		},
+		UpdateReconciled: func() {
+			reconciled.SetLabels(expected.GetLabels())
+			reconciled.SetAnnotations(expected.GetAnnotations())
+			reconciled.Object["spec"] = expected.Object["spec"]
+		},
+	})
+}
+
+func (r Reconciler) newRoute(ctx context.Context) (*unstructured.Unstructured, error) {
+	expose := r.HTTP.Expose
+	spec := map[string]interface{}{
+		"host": expose.Host,
+		"to": map[string]interface{}{
+			"kind":   "Service",
+			"name":   r.Service.Name,
+			"weight": int64(100),
+		},
+		"port": map[string]interface{}{
+			"targetPort": r.servicePortName(),
+		},
+		"wildcardPolicy": "None",
+	}
+
+	if r.HTTP.TLS.Enabled() {
+		tls := map[string]interface{}{
+			"termination":                   "passthrough",
+			"insecureEdgeTerminationPolicy": "Redirect",
+		}
+		if expose.IsReencrypt() {
+			ca, err := r.caCertificate(ctx)
+			if err != nil {
+				return nil, err
+			}
+			tls["termination"] = "reencrypt"
+			// the router validates the certificate presented by the Pods against the CA of the HTTP certificate
+			tls["destinationCACertificate"] = string(ca)
+		}
+		spec["tls"] = tls
+	}
+
+	route := &unstructured.Unstructured{Object: map[string]interface{}{"spec": spec}}
+	route.SetGroupVersionKind(RouteGVK)
+	objMeta := r.objectMeta()
+	route.SetName(objMeta.Name)
+	route.SetNamespace(objMeta.Namespace)
+	route.SetLabels(objMeta.Labels)
+	route.SetAnnotations(objMeta.Annotations)
+	return route, nil
+}
+
+// caCertificate returns the PEM encoded CA certificate of the HTTP certificate of the resource.
+func (r Reconciler) caCertificate(ctx context.Context) ([]byte, error) {
+	var secret corev1.Secret
+	key := types.NamespacedName{
+		Namespace: r.Owner.GetNamespace(),
+		Name:      certificates.InternalCertsSecretName(r.Namer, r.Owner.GetName()),
+	}
+	if err := r.K8sClient.Get(ctx, key, &secret); err != nil {
+		return nil, err
+	}
+	ca := k8s.GetSecretEntry(secret, certificates.CAFileName)
+	if len(ca) == 0 {
+		return nil, fmt.Errorf("no %s entry in secret %s/%s", certificates.CAFileName, key.Namespace, key.Name)
+	}
+	return ca, nil
+}
+
+func (r Reconciler) objectMeta() metav1.ObjectMeta {
+	userMeta := r.HTTP.Expose.ObjectMeta.DeepCopy()
+	return metav1.ObjectMeta{
+		Name:        r.Service.Name,
+		Namespace:   r.Service.Namespace,
+		Labels:      maps.Merge(userMeta.Labels, r.Labels),
+		Annotations: userMeta.Annotations,
+	}
+}
+
+func (r Reconciler) servicePort() int32 {
+	if len(r.Service.Spec.Ports) == 0 {
+		return 0
+	}
+	return r.Service.Spec.Ports[0].Port
+}
+
+func (r Reconciler) servicePortName() string {
+	if len(r.Service.Spec.Ports) == 0 {
+		return ""
+	}
+	return r.Service.Spec.Ports[0].Name
+}
+
+// deleteIngress deletes the Ingress created by the operator. It is deleted without being read first, to not start an
+// informer on the Ingresses for the resources which are not exposed anymore.
+func (r Reconciler) deleteIngress(ctx context.Context) error {
+	ingress := &networkingv1.Ingress{ObjectMeta: metav1.ObjectMeta{Namespace: r.Service.Namespace, Name: r.Service.Name}}
+	if err := r.K8sClient.Delete(ctx, ingress); err != nil && !apierrors.IsNotFound(err) {
+		return err
+	}
+	return nil
+}
+
+// deleteRoute deletes the Route created by the operator, without reading it first.
+func (r Reconciler) deleteRoute(ctx context.Context) error {
+	available, err := isRouteAvailable(r.K8sClient)
+	if err != nil || !available {
+		return err
+	}
+	route := &unstructured.Unstructured{}
+	route.SetGroupVersionKind(RouteGVK)
+	route.SetNamespace(r.Service.Namespace)
+	route.SetName(r.Service.Name)
+	if err := r.K8sClient.Delete(ctx, route); err != nil && !apierrors.IsNotFound(err) {
+		return err
+	}
+	return nil
+}
+
+// isRouteAvailable returns true if the OpenShift Route API is served by the API server.
+// The result is cached for the lifetime of the operator.
+func isRouteAvailable(c k8s.Client) (bool, error) {
+	routeAvailableMutex.Lock()
+	defer routeAvailableMutex.Unlock()
+	if routeAvailable != nil {
+		return *routeAvailable, nil
+	}
+	_, err := c.RESTMapper().RESTMapping(RouteGVK.GroupKind(), RouteGVK.Version)
+	switch {
+	case meta.IsNoMatchError(err):
+		routeAvailable = pointer.Bool(false)
+	case err != nil:
+		return false, err
+	default:
+		routeAvailable = pointer.Bool(true)
+	}
+	return *routeAvailable, nil
+}
@@ -0,0 +1,179 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package expose
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/types"
+
+	commonv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/common/v1"
+	kbv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/kibana/v1"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/certificates"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/utils/k8s"
+)
+
+func newReconciler(c k8s.Client, kb *kbv1.Kibana, http commonv1.HTTPConfig) Reconciler {
+	return Reconciler{
+		K8sClient: c,
+		Owner:     kb,
+		HTTP:      http,
+		Service: corev1.Service{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "kb-kb-http"},
+			Spec:       corev1.ServiceSpec{Ports: []corev1.ServicePort{{Name: "https", Port: 5601}}},
+		},
+		Namer:  kbv1.KBNamer,
+		Labels: map[string]string{"kibana.k8s.elastic.co/name": "kb"},
+	}
+}
+
+func TestReconciler_Reconcile_Ingress(t *testing.T) {
+	kb := &kbv1.Kibana{ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "kb"}}
+	c := k8s.NewFakeClient(kb)
+	key := types.NamespacedName{Namespace: "ns", Name: "kb-kb-http"}
+	className := "nginx"
+
+	// TLS passthrough by default
+	http := commonv1.HTTPConfig{Expose: &commonv1.ExposeConfig{
+		Host:             "kibana.example.com",
+		IngressClassName: &className,
+		ObjectMeta:       metav1.ObjectMeta{Annotations: map[string]string{"foo": "bar"}},
+	}}
+	require.NoError(t, newReconciler(c, kb, http).Reconcile(context.Background()))
+
+	var ingress networkingv1.Ingress
+	require.NoError(t, c.Get(context.Background(), key, &ingress))
+	require.True(t, metav1.IsControlledBy(&ingress, kb))
+	var actualKb kbv1.Kibana
+	require.NoError(t, c.Get(context.Background(), k8s.ExtractNamespacedName(kb), &actualKb))
+	require.Equal(t, "Ingress", actualKb.Annotations[ExposedAsAnnotation])
+	require.Equal(t, "kb", ingress.Labels["kibana.k8s.elastic.co/name"])
+	require.Equal(t, map[string]string{
+		"foo":                     "bar",
+		BackendProtocolAnnotation: "HTTPS",
+		SSLPassthroughAnnotation:  "true",
+	}, ingress.Annotations)
+	require.Equal(t, &className, ingress.Spec.IngressClassName)
+	require.Len(t, ingress.Spec.Rules, 1)
+	require.Equal(t, "kibana.example.com", ingress.Spec.Rules[0].Host)
+	backend := ingress.Spec.Rules[0].HTTP.Paths[0].Backend.Service
+	require.Equal(t, "kb-kb-http", backend.Name)
+	require.Equal(t, int32(5601), backend.Port.Number)
+	require.Empty(t, ingress.Spec.TLS)
+
+	// TLS reencrypt serves the HTTP certificate
+	http.Expose.TLSTermination = commonv1.ReencryptTLSTermination
+	require.NoError(t, newReconciler(c, kb, http).Reconcile(context.Background()))
+	require.NoError(t, c.Get(context.Background(), key, &ingress))
+	require.NotContains(t, ingress.Annotations, SSLPassthroughAnnotation)
+	require.Equal(t, []networkingv1.IngressTLS{{
+		Hosts:      []string{"kibana.example.com"},
+		SecretName: certificates.InternalCertsSecretName(kbv1.KBNamer, "kb"),
+	}}, ingress.Spec.TLS)
+
+	// TLS disabled
+	http.TLS = commonv1.TLSOptions{SelfSignedCertificate: &commonv1.SelfSignedCertificate{Disabled: true}}
+	require.NoError(t, newReconciler(c, kb, http).Reconcile(context.Background()))
+	require.NoError(t, c.Get(context.Background(), key, &ingress))
+	require.Equal(t, map[string]string{"foo": "bar"}, ingress.Annotations)
+	require.Empty(t, ingress.Spec.TLS)
+
+	// removing the expose configuration deletes the Ingress
+	http.Expose = nil
+	require.NoError(t, newReconciler(c, kb, http).Reconcile(context.Background()))
+	require.True(t, apierrors.IsNotFound(c.Get(context.Background(), key, &ingress)))
+	require.NoError(t, c.Get(context.Background(), k8s.ExtractNamespacedName(kb), &actualKb))
+	require.NotContains(t, actualKb.Annotations, ExposedAsAnnotation)
+}
+
+func TestReconciler_Reconcile_IngressNotOwned(t *testing.T) {
+	kb := &kbv1.Kibana{ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "kb"}}
+	userIngress := &networkingv1.Ingress{ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "kb-kb-http"}}
+	c := k8s.NewFakeClient(kb, userIngress)
+
+	// an Ingress created by the user for a resource never exposed by the operator is left untouched
+	require.NoError(t, newReconciler(c, kb, commonv1.HTTPConfig{}).Reconcile(context.Background()))
+	var ingress networkingv1.Ingress
+	require.NoError(t, c.Get(context.Background(), k8s.ExtractNamespacedName(userIngress), &ingress))
+}
+
+func TestReconciler_Reconcile_RouteNotAvailable(t *testing.T) {
+	kb := &kbv1.Kibana{ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "kb"}}
+	http := commonv1.HTTPConfig{Expose: &commonv1.ExposeConfig{Type: commonv1.RouteExposeType, Host: "kibana.example.com"}}
+	err := newReconciler(k8s.NewFakeClient(kb), kb, http).Reconcile(context.Background())
+	require.ErrorContains(t, err, "route.openshift.io/v1 is not available")
+}
+
+func TestReconciler_newRoute(t *testing.T) {
+	kb := &kbv1.Kibana{ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "kb"}}
+	caSecret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: certificates.InternalCertsSecretName(kbv1.KBNamer, "kb")},
+		Data:       map[string][]byte{certificates.CAFileName: []byte("ca-pem")},
+	}
+	c := k8s.NewFakeClient(kb, caSecret)
+
+	tests := []struct {
+		name    string
+		http    commonv1.HTTPConfig
+		wantTLS map[string]interface{}
+	}{
+		{
+			name:    "TLS passthrough",
+			http:    commonv1.HTTPConfig{Expose: &commonv1.ExposeConfig{Type: commonv1.RouteExposeType, Host: "kibana.example.com"}},
+			wantTLS: map[string]interface{}{"termination": "passthrough", "insecureEdgeTerminationPolicy": "Redirect"},
+		},
+		{
+			name: "TLS reencrypt",
+			http: commonv1.HTTPConfig{Expose: &commonv1.ExposeConfig{
+				Type:           commonv1.RouteExposeType,
+				Host:           "kibana.example.com",
+				TLSTermination: commonv1.ReencryptTLSTermination,
+			}},
+			wantTLS: map[string]interface{}{
+				"termination":                   "reencrypt",
+				"insecureEdgeTerminationPolicy": "Redirect",
+				"destinationCACertificate":      "ca-pem",
+			},
+		},
+		{
+			name: "TLS disabled",
+			http: commonv1.HTTPConfig{
+				Expose: &commonv1.ExposeConfig{Type: commonv1.RouteExposeType, Host: "kibana.example.com"},
+				TLS:    commonv1.TLSOptions{SelfSignedCertificate: &commonv1.SelfSignedCertificate{Disabled: true}},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			route, err := newReconciler(c, kb, tt.http).newRoute(context.Background())
+			require.NoError(t, err)
+			require.Equal(t, RouteGVK, route.GroupVersionKind())
+			require.Equal(t, "kb-kb-http", route.GetName())
+			host, _, _ := unstructured.NestedString(route.Object, "spec", "host")
+			require.Equal(t, "kibana.example.com", host)
+			service, _, _ := unstructured.NestedString(route.Object, "spec", "to", "name")
+			require.Equal(t, "kb-kb-http", service)
+			port, _, _ := unstructured.NestedString(route.Object, "spec", "port", "targetPort")
+			require.Equal(t, "https", port)
+			tls, _, _ := unstructured.NestedMap(route.Object, "spec", "tls")
+			require.Equal(t, tt.wantTLS, tls)
+		})
+	}
+}
+
+func TestSubjectAltNames(t *testing.T) {
+	require.Nil(t, SubjectAltNames(commonv1.HTTPConfig{}))
+	require.Equal(t,
+		[]commonv1.SubjectAlternativeName{{DNS: "kibana.example.com"}},
+		SubjectAltNames(commonv1.HTTPConfig{Expose: &commonv1.ExposeConfig{Host: "kibana.example.com"}}),
+	)
+}
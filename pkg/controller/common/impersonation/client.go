@@ -0,0 +1,143 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+// Package impersonation provides a Kubernetes client which mutates the resources of some namespaces on behalf of a
+// ServiceAccount of the namespace, so that Kubernetes audit logs attribute the changes made by the operator to the
+// team owning the namespace.
+package impersonation
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"k8s.io/client-go/rest"
+	"sigs.k8s.io/controller-runtime/pkg/cache"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/cluster"
+
+	ulog "github.com/elastic/cloud-on-k8s/v2/pkg/utils/log"
+)
+
+// ServiceAccounts maps namespaces to the name of the ServiceAccount of the namespace to impersonate.
+type ServiceAccounts map[string]string
+
+// ParseServiceAccounts validates the given mapping of namespaces to ServiceAccount names.
+func ParseServiceAccounts(serviceAccounts map[string]string) (ServiceAccounts, error) {
+	for namespace, name := range serviceAccounts {
+		if namespace == "" || name == "" {
+			return nil, fmt.Errorf("invalid impersonated service account %q for namespace %q", name, namespace)
+		}
+	}
+	return serviceAccounts, nil
+}
+
+// UserName returns the name of the user impersonated to mutate resources in the given namespace, or an empty string.
+func (s ServiceAccounts) UserName(namespace string) string {
+	name, exists := s[namespace]
+	if !exists {
+		return ""
+	}
+	return fmt.Sprintf("system:serviceaccount:%s:%s", namespace, name)
+}
+
+// String returns a stable representation of the mapping, suitable for logging.
+func (s ServiceAccounts) String() string {
+	pairs := make([]string, 0, len(s))
+	for namespace, name := range s {
+		pairs = append(pairs, namespace+"="+name)
+	}
+	sort.Strings(pairs)
+	return strings.Join(pairs, ",")
+}
+
+// NewClientFunc returns a function creating the client of the controller manager. Reads are served as usual, while
+// writes on objects living in one of the given namespaces are performed by impersonating the ServiceAccount
+// associated with the namespace.
+func NewClientFunc(serviceAccounts ServiceAccounts) cluster.NewClientFunc {
+	return func(cache cache.Cache, config *rest.Config, options client.Options, uncachedObjects ...client.Object) (client.Client, error) {
+		c, err := cluster.DefaultNewClient(cache, config, options, uncachedObjects...)
+		if err != nil {
+			return nil, err
+		}
+		if len(serviceAccounts) == 0 {
+			return c, nil
+		}
+		impersonating := make(map[string]client.Client, len(serviceAccounts))
+		for namespace := range serviceAccounts {
+			impersonatingConfig := rest.CopyConfig(config)
+			impersonatingConfig.Impersonate = rest.ImpersonationConfig{UserName: serviceAccounts.UserName(namespace)}
+			impersonatingClient, err := client.New(impersonatingConfig, options)
+			if err != nil {
+				return nil, err
+			}
+			impersonating[namespace] = impersonatingClient
+		}
+		ulog.Log.Info("Impersonating service accounts to mutate resources", "service_accounts", serviceAccounts.String())
+		return NewClient(c, impersonating), nil
+	}
+}
+
+// NewClient returns a client which delegates writes on objects living in the namespaces of the impersonating clients
+// to these clients.
+func NewClient(c client.Client, impersonating map[string]client.Client) client.Client {
+	return &impersonatingClient{Client: c, impersonating: impersonating}
+}
+
+type impersonatingClient struct {
+	client.Client
+	impersonating map[string]client.Client
+}
+
+var _ client.Client = &impersonatingClient{}
+
+// writer returns the client to use to mutate the given object.
+func (c *impersonatingClient) writer(obj client.Object) client.Client {
+	if impersonating, exists := c.impersonating[obj.GetNamespace()]; exists {
+		return impersonating
+	}
+	return c.Client
+}
+
+func (c *impersonatingClient) Create(ctx context.Context, obj client.Object, opts ...client.CreateOption) error {
+	return c.writer(obj).Create(ctx, obj, opts...)
+}
+
+func (c *impersonatingClient) Update(ctx context.Context, obj client.Object, opts ...client.UpdateOption) error {
+	return c.writer(obj).Update(ctx, obj, opts...)
+}
+
+func (c *impersonatingClient) Patch(ctx context.Context, obj client.Object, patch client.Patch, opts ...client.PatchOption) error {
+	return c.writer(obj).Patch(ctx, obj, patch, opts...)
+}
+
+func (c *impersonatingClient) Delete(ctx context.Context, obj client.Object, opts ...client.DeleteOption) error {
+	return c.writer(obj).Delete(ctx, obj, opts...)
+}
+
+func (c *impersonatingClient) DeleteAllOf(ctx context.Context, obj client.Object, opts ...client.DeleteAllOfOption) error {
+	deleteAllOfOptions := client.DeleteAllOfOptions{}
+	deleteAllOfOptions.ApplyOptions(opts)
+	if impersonating, exists := c.impersonating[deleteAllOfOptions.Namespace]; exists {
+		return impersonating.DeleteAllOf(ctx, obj, opts...)
+	}
+	return c.Client.DeleteAllOf(ctx, obj, opts...)
+}
+
+func (c *impersonatingClient) Status() client.StatusWriter {
+	return &impersonatingStatusWriter{c: c}
+}
+
+type impersonatingStatusWriter struct {
+	c *impersonatingClient
+}
+
+func (w *impersonatingStatusWriter) Update(ctx context.Context, obj client.Object, opts ...client.UpdateOption) error {
+	return w.c.writer(obj).Status().Update(ctx, obj, opts...)
+}
+
+func (w *impersonatingStatusWriter) Patch(ctx context.Context, obj client.Object, patch client.Patch, opts ...client.PatchOption) error {
+	return w.c.writer(obj).Status().Patch(ctx, obj, patch, opts...)
+}
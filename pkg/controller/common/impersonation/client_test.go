@@ -0,0 +1,80 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package impersonation
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/elastic/cloud-on-k8s/v2/pkg/utils/k8s"
+)
+
+func TestServiceAccounts_UserName(t *testing.T) {
+	serviceAccounts := ServiceAccounts{"team-a": "eck"}
+	require.Equal(t, "system:serviceaccount:team-a:eck", serviceAccounts.UserName("team-a"))
+	require.Equal(t, "", serviceAccounts.UserName("team-b"))
+}
+
+func TestParseServiceAccounts(t *testing.T) {
+	_, err := ParseServiceAccounts(map[string]string{"team-a": "eck"})
+	require.NoError(t, err)
+	_, err = ParseServiceAccounts(map[string]string{"team-a": ""})
+	require.Error(t, err)
+}
+
+func TestImpersonatingClient(t *testing.T) {
+	secret := func(namespace string) *corev1.Secret {
+		return &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Namespace: namespace, Name: "secret"}}
+	}
+	tests := []struct {
+		name              string
+		namespace         string
+		wantImpersonating bool
+	}{
+		{
+			name:              "writes in an impersonated namespace use the impersonating client",
+			namespace:         "team-a",
+			wantImpersonating: true,
+		},
+		{
+			name:              "writes in other namespaces use the operator client",
+			namespace:         "team-b",
+			wantImpersonating: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			operatorClient := k8s.NewFakeClient()
+			teamClient := k8s.NewFakeClient()
+			c := NewClient(operatorClient, map[string]client.Client{"team-a": teamClient})
+
+			expectedClient, otherClient := operatorClient, teamClient
+			if tt.wantImpersonating {
+				expectedClient, otherClient = teamClient, operatorClient
+			}
+
+			obj := secret(tt.namespace)
+			require.NoError(t, c.Create(ctx, obj))
+			require.NoError(t, expectedClient.Get(ctx, k8s.ExtractNamespacedName(obj), &corev1.Secret{}))
+			require.True(t, errors.IsNotFound(otherClient.Get(ctx, k8s.ExtractNamespacedName(obj), &corev1.Secret{})))
+
+			obj.Data = map[string][]byte{"key": []byte("value")}
+			require.NoError(t, c.Update(ctx, obj))
+			var updated corev1.Secret
+			require.NoError(t, expectedClient.Get(ctx, k8s.ExtractNamespacedName(obj), &updated))
+			require.Equal(t, obj.Data, updated.Data)
+
+			require.NoError(t, c.Delete(ctx, obj))
+			require.True(t, errors.IsNotFound(expectedClient.Get(ctx, k8s.ExtractNamespacedName(obj), &corev1.Secret{})))
+		})
+	}
+}
@@ -24,6 +24,8 @@ type Resources struct {
 	InitContainer corev1.Container
 	// version of the secret provided by the user
 	Version string
+	// hash of the value of each secure setting, by setting name
+	SettingsHashes map[string]string
 }
 
 // HasKeystore interface represents an Elastic Stack application that offers a keystore which in ECK
@@ -56,7 +58,7 @@ func ReconcileResources(
 	initContainerParams InitContainerParameters,
 ) (*Resources, error) {
 	// setup a volume from the user-provided secure settings secret
-	secretVolume, version, err := secureSettingsVolume(ctx, r, hasKeystore, labels, namer)
+	secretVolume, version, settingsHashes, err := secureSettingsVolume(ctx, r, hasKeystore, labels, namer)
 	if err != nil {
 		return nil, err
 	}
@@ -72,8 +74,9 @@ func ReconcileResources(
 	}
 
 	return &Resources{
-		Volume:        secretVolume.Volume(),
-		InitContainer: initContainer,
-		Version:       version,
+		Volume:         secretVolume.Volume(),
+		InitContainer:  initContainer,
+		Version:        version,
+		SettingsHashes: settingsHashes,
 	}, nil
 }
@@ -18,6 +18,7 @@ import (
 	commonv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/common/v1"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/driver"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/events"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/hash"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/name"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/reconciler"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/volume"
@@ -35,14 +36,15 @@ const secureSettingsSecretSuffix = "secure-settings"
 // This secret is mounted into the pods for secure settings to be injected into a keystore.
 // The user-provided secrets are watched to reconcile on any change.
 // The user secret resource version is returned along with the volume, so that
-// any change in the user secret leads to pod rotation.
+// any change in the user secret leads to pod rotation, as well as a hash of the value of each secure setting
+// to identify which settings changed.
 func secureSettingsVolume(
 	ctx context.Context,
 	r driver.Interface,
 	hasKeystore HasKeystore,
 	labels map[string]string,
 	namer name.Namer,
-) (*volume.SecretVolume, string, error) {
+) (*volume.SecretVolume, string, map[string]string, error) {
 	// setup (or remove) watches for the user-provided secret to reconcile on any change
 	watcher := k8s.ExtractNamespacedName(hasKeystore)
 	if err := watches.WatchUserProvidedSecrets(
@@ -51,19 +53,19 @@ func secureSettingsVolume(
 		SecureSettingsWatchName(watcher),
 		WatchedSecretNames(hasKeystore),
 	); err != nil {
-		return nil, "", err
+		return nil, "", nil, err
 	}
 
 	secrets, err := retrieveUserSecrets(ctx, r.K8sClient(), r.Recorder(), hasKeystore)
 	if err != nil {
-		return nil, "", err
+		return nil, "", nil, err
 	}
 	secret, err := reconcileSecureSettings(ctx, r.K8sClient(), hasKeystore, secrets, namer, labels)
 	if err != nil {
-		return nil, "", err
+		return nil, "", nil, err
 	}
 	if secret == nil {
-		return nil, "", nil
+		return nil, "", nil, nil
 	}
 
 	// build a volume from that secret
@@ -77,7 +79,12 @@ func secureSettingsVolume(
 	// to recreate pods on any secret change.
 	resourceVersion := secret.GetResourceVersion()
 
-	return &secureSettingsVolume, resourceVersion, nil
+	settingsHashes := make(map[string]string, len(secret.Data))
+	for k, v := range secret.Data {
+		settingsHashes[k] = hash.HashObject(v)
+	}
+
+	return &secureSettingsVolume, resourceVersion, settingsHashes, nil
 }
 
 func reconcileSecureSettings(
@@ -102,7 +102,7 @@ func Test_secureSettingsVolume(t *testing.T) {
 				Watches:      tt.w,
 				FakeRecorder: record.NewFakeRecorder(1000),
 			}
-			vol, version, err := secureSettingsVolume(context.Background(), testDriver, &tt.kb, nil, kbNamer)
+			vol, version, _, err := secureSettingsVolume(context.Background(), testDriver, &tt.kb, nil, kbNamer)
 			require.NoError(t, err)
 			assert.Equal(t, tt.wantVolume, vol)
 			assert.Equal(t, tt.wantVersion, version)
@@ -19,6 +19,12 @@ const (
 	EULAAnnotation           = "elastic.co/eula"
 	EULAAcceptedValue        = "accepted"
 	LicenseInvalidAnnotation = "license.k8s.elastic.co/invalid"
+	// LicenseSecretAnnotation is set on an Elasticsearch resource to reference the Secret, in the namespace of the
+	// resource, holding the Elasticsearch license to apply to this cluster instead of the enterprise license.
+	LicenseSecretAnnotation = "license.k8s.elastic.co/secret-name"
+	// StartTrialAnnotation is set to true on an Elasticsearch resource to start the self-generated trial license of
+	// Elasticsearch on this cluster, if it is not licensed otherwise.
+	StartTrialAnnotation = "license.k8s.elastic.co/start-trial"
 )
 
 type LicenseScope string //nolint:revive
@@ -4,7 +4,15 @@
 
 package license
 
+import "time"
+
 const (
 	// FileName is the name used in the license secret to point to the license data.
 	FileName = "license"
 )
+
+// ExpiresWithin returns true if a license expiring at the given time is still valid at now, but expires within the
+// given period. A non-positive period is never reached.
+func ExpiresWithin(expiry time.Time, period time.Duration, now time.Time) bool {
+	return period > 0 && expiry.After(now) && expiry.Before(now.Add(period))
+}
@@ -0,0 +1,53 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package license
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestExpiresWithin(t *testing.T) {
+	now := time.Date(2022, 10, 18, 10, 0, 0, 0, time.UTC)
+	period := 30 * 24 * time.Hour
+	tests := []struct {
+		name   string
+		expiry time.Time
+		period time.Duration
+		want   bool
+	}{
+		{
+			name:   "expires after the period",
+			expiry: now.Add(31 * 24 * time.Hour),
+			period: period,
+			want:   false,
+		},
+		{
+			name:   "expires within the period",
+			expiry: now.Add(24 * time.Hour),
+			period: period,
+			want:   true,
+		},
+		{
+			name:   "already expired",
+			expiry: now.Add(-time.Hour),
+			period: period,
+			want:   false,
+		},
+		{
+			name:   "warnings disabled",
+			expiry: now.Add(24 * time.Hour),
+			period: 0,
+			want:   false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.Equal(t, tt.want, ExpiresWithin(tt.expiry, tt.period, now))
+		})
+	}
+}
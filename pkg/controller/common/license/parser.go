@@ -9,6 +9,8 @@ import (
 	"fmt"
 
 	"github.com/pkg/errors"
+
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/elasticsearch/client"
 )
 
 func ParseEnterpriseLicense(raw map[string][]byte) (EnterpriseLicense, error) {
@@ -30,6 +32,32 @@ func ParseEnterpriseLicense(raw map[string][]byte) (EnterpriseLicense, error) {
 	return license, nil
 }
 
+// ParseElasticsearchLicense parses the Elasticsearch license provided by the user for a single cluster, either as the
+// license file issued by Elastic, or as the bare license object.
+func ParseElasticsearchLicense(raw map[string][]byte) (client.License, error) {
+	bytes, err := FetchLicenseData(raw)
+	if err != nil {
+		return client.License{}, err
+	}
+	var file struct {
+		License *client.License `json:"license"`
+	}
+	if err := json.Unmarshal(bytes, &file); err != nil {
+		return client.License{}, errors.Wrapf(err, "license cannot be unmarshalled")
+	}
+	license := file.License
+	if license == nil {
+		license = &client.License{}
+		if err := json.Unmarshal(bytes, license); err != nil {
+			return client.License{}, errors.Wrapf(err, "license cannot be unmarshalled")
+		}
+	}
+	if license.UID == "" || license.Signature == "" {
+		return client.License{}, errors.New("license must have a uid and a signature")
+	}
+	return *license, nil
+}
+
 func FetchLicenseData(raw map[string][]byte) ([]byte, error) {
 	if len(raw) != 1 {
 		return nil, errors.New("license secret needs to contain exactly one file with any name")
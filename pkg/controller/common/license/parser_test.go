@@ -5,12 +5,15 @@
 package license
 
 import (
+	"encoding/json"
 	"os"
 	"strings"
 	"testing"
 
 	"github.com/go-test/deep"
 	"github.com/stretchr/testify/require"
+
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/elasticsearch/client"
 )
 
 func TestParseEnterpriseLicenses(t *testing.T) {
@@ -92,3 +95,62 @@ func TestParseEnterpriseLicenses(t *testing.T) {
 		})
 	}
 }
+
+func TestParseElasticsearchLicense(t *testing.T) {
+	file, err := os.ReadFile("testdata/wrong-type.json")
+	require.NoError(t, err)
+	want := client.License{
+		UID:                "57E312E2-6EA0-49D0-8E65-AA5017742ACF",
+		Type:               "platinum",
+		IssueDateInMillis:  1548115200000,
+		ExpiryDateInMillis: 1561247999999,
+		MaxNodes:           100,
+		IssuedTo:           "test org",
+		Issuer:             "test issuer",
+		StartDateInMillis:  1548115200000,
+		Signature:          "test signature platinum",
+	}
+	bare, err := json.Marshal(want)
+	require.NoError(t, err)
+
+	tests := []struct {
+		name    string
+		raw     map[string][]byte
+		wantErr bool
+	}{
+		{
+			name: "license file issued by Elastic",
+			raw:  map[string][]byte{"license.json": file},
+		},
+		{
+			name: "bare license",
+			raw:  map[string][]byte{"license.json": bare},
+		},
+		{
+			name:    "malformed license",
+			raw:     map[string][]byte{"license.json": []byte("{")},
+			wantErr: true,
+		},
+		{
+			name:    "unsigned license",
+			raw:     map[string][]byte{"license.json": []byte(`{"license":{"uid":"57E312E2-6EA0-49D0-8E65-AA5017742ACF"}}`)},
+			wantErr: true,
+		},
+		{
+			name:    "several files",
+			raw:     map[string][]byte{"license.json": file, "other.json": file},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseElasticsearchLicense(tt.raw)
+			if tt.wantErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, want, got)
+		})
+	}
+}
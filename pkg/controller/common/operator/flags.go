@@ -5,37 +5,61 @@
 package operator
 
 const (
-	AutoPortForwardFlag                  = "auto-port-forward"
-	CADirFlag                            = "ca-dir"
-	CACertRotateBeforeFlag               = "ca-cert-rotate-before"
-	CACertValidityFlag                   = "ca-cert-validity"
-	CertRotateBeforeFlag                 = "cert-rotate-before"
-	CertValidityFlag                     = "cert-validity"
-	ConfigFlag                           = "config"
-	ContainerRegistryFlag                = "container-registry"
-	DebugHTTPListenFlag                  = "debug-http-listen"
-	DisableConfigWatch                   = "disable-config-watch"
-	DisableTelemetryFlag                 = "disable-telemetry"
-	DistributionChannelFlag              = "distribution-channel"
-	ElasticsearchClientTimeout           = "elasticsearch-client-timeout"
-	ElasticsearchObservationIntervalFlag = "elasticsearch-observation-interval"
-	EnableLeaderElection                 = "enable-leader-election"
-	EnableTracingFlag                    = "enable-tracing"
-	EnableWebhookFlag                    = "enable-webhook"
-	EnforceRBACOnRefsFlag                = "enforce-rbac-on-refs"
-	ExposedNodeLabels                    = "exposed-node-labels"
-	IPFamilyFlag                         = "ip-family"
-	KubeClientTimeout                    = "kube-client-timeout"
-	ManageWebhookCertsFlag               = "manage-webhook-certs"
-	MaxConcurrentReconcilesFlag          = "max-concurrent-reconciles"
-	MetricsPortFlag                      = "metrics-port"
-	NamespacesFlag                       = "namespaces"
-	OperatorNamespaceFlag                = "operator-namespace"
-	SetDefaultSecurityContextFlag        = "set-default-security-context"
-	TelemetryIntervalFlag                = "telemetry-interval"
-	UBIOnlyFlag                          = "ubi-only"
-	ValidateStorageClassFlag             = "validate-storage-class"
-	WebhookCertDirFlag                   = "webhook-cert-dir"
-	WebhookNameFlag                      = "webhook-name"
-	WebhookSecretFlag                    = "webhook-secret"
+	AutoPortForwardFlag                      = "auto-port-forward"
+	CADirFlag                                = "ca-dir"
+	CACertRotateBeforeFlag                   = "ca-cert-rotate-before"
+	CACertValidityFlag                       = "ca-cert-validity"
+	CAPerNamespaceFlag                       = "ca-per-namespace"
+	CertRotateBeforeFlag                     = "cert-rotate-before"
+	CertValidityFlag                         = "cert-validity"
+	ConfigFlag                               = "config"
+	ContainerRegistryFlag                    = "container-registry"
+	DebugHTTPListenFlag                      = "debug-http-listen"
+	DisableConfigWatch                       = "disable-config-watch"
+	DisableTelemetryFlag                     = "disable-telemetry"
+	DistributionChannelFlag                  = "distribution-channel"
+	DriftPolicyFlag                          = "drift-policy"
+	DryRunFlag                               = "dry-run"
+	ElasticsearchClientTimeout               = "elasticsearch-client-timeout"
+	ElasticsearchDefaultConfigFlag           = "elasticsearch-default-config"
+	ElasticsearchObservationIntervalFlag     = "elasticsearch-observation-interval"
+	EnableCADistributionFlag                 = "enable-ca-distribution"
+	EnableDecisionTraceFlag                  = "enable-decision-trace"
+	EnableLeaderElection                     = "enable-leader-election"
+	EnableTracingFlag                        = "enable-tracing"
+	EnableWebhookFlag                        = "enable-webhook"
+	EnforceRBACOnRefsFlag                    = "enforce-rbac-on-refs"
+	ExposedNodeLabels                        = "exposed-node-labels"
+	GarbageCollectionIntervalFlag            = "garbage-collection-interval"
+	HealthProbePortFlag                      = "health-probe-port"
+	ImpersonatedServiceAccountsFlag          = "impersonated-service-accounts"
+	IPFamilyFlag                             = "ip-family"
+	LicenseExpiryWarningPeriodFlag           = "license-expiry-warning-period"
+	KubeClientTimeout                        = "kube-client-timeout"
+	LeaderElectionIDFlag                     = "leader-election-id"
+	LeaderElectionLeaseDurationFlag          = "leader-election-lease-duration"
+	LeaderElectionNamespaceFlag              = "leader-election-namespace"
+	LeaderElectionRenewDeadlineFlag          = "leader-election-renew-deadline"
+	LeaderElectionRetryPeriodFlag            = "leader-election-retry-period"
+	ManageWebhookCertsFlag                   = "manage-webhook-certs"
+	MaxCertificateIssuancesFlag              = "max-certificate-issuances-per-namespace"
+	MaxConcurrentReconcilesFlag              = "max-concurrent-reconciles"
+	MaxConcurrentReconcilesPerControllerFlag = "max-concurrent-reconciles-per-controller"
+	MetricsHostFlag                          = "metrics-host"
+	MetricsPortFlag                          = "metrics-port"
+	NamespacesFlag                           = "namespaces"
+	OperatorNamespaceFlag                    = "operator-namespace"
+	PVCDeletionGracePeriodFlag               = "pvc-deletion-grace-period"
+	ReconcileBackoffBaseDelayFlag            = "reconcile-backoff-base-delay"
+	ReconcileBackoffMaxDelayFlag             = "reconcile-backoff-max-delay"
+	ReconcileRateLimitBurstFlag              = "reconcile-rate-limit-burst"
+	ReconcileRateLimitQPSFlag                = "reconcile-rate-limit-qps"
+	SetDefaultSecurityContextFlag            = "set-default-security-context"
+	SetVMMaxMapCountFlag                     = "set-vm-max-map-count"
+	TelemetryIntervalFlag                    = "telemetry-interval"
+	UBIOnlyFlag                              = "ubi-only"
+	ValidateStorageClassFlag                 = "validate-storage-class"
+	WebhookCertDirFlag                       = "webhook-cert-dir"
+	WebhookNameFlag                          = "webhook-name"
+	WebhookSecretFlag                        = "webhook-secret"
 )
@@ -5,19 +5,32 @@
 package operator
 
 import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
 	"time"
 
 	"go.elastic.co/apm/v2"
 	corev1 "k8s.io/api/core/v1"
 
 	"github.com/elastic/cloud-on-k8s/v2/pkg/about"
+	commonv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/common/v1"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/certificates"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/decisions"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/dryrun"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/reconciler"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/watchdog"
 	esvalidation "github.com/elastic/cloud-on-k8s/v2/pkg/controller/elasticsearch/validation"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/utils/net"
 )
 
 // Parameters contain parameters to create new operators.
 type Parameters struct {
+	// ElasticsearchDefaultConfig is an optional Elasticsearch configuration merged beneath the configuration of every
+	// managed NodeSet.
+	ElasticsearchDefaultConfig *commonv1.Config
 	// ElasticsearchObservationInterval is the interval between (asynchronous) observations of Elasticsearch health.
 	ElasticsearchObservationInterval time.Duration
 	// ExposedNodeLabels holds regular expressions of node labels which are allowed to be automatically set as annotations on Elasticsearch Pods.
@@ -32,18 +45,133 @@ type Parameters struct {
 	IPFamily corev1.IPFamily
 	// GlobalCA is an optionally configured, globally shared CA to be used for all managed resources.
 	GlobalCA *certificates.CA
+	// NamespaceCA is true if all the managed resources of a namespace share a CA generated for that namespace, instead
+	// of using a self-signed CA each.
+	NamespaceCA bool
 	// CACertRotation defines the rotation params for CA certificates.
 	CACertRotation certificates.RotationParams
 	// CertRotation defines the rotation params for non-CA certificates.
 	CertRotation certificates.RotationParams
+	// LicenseExpiryWarningPeriod is how long before the expiry of the operator license and of the Elasticsearch cluster
+	// licenses warning events are emitted.
+	LicenseExpiryWarningPeriod time.Duration
+	// ManagedNamespaces are the namespaces in which the operator manages resources, all the namespaces if empty.
+	ManagedNamespaces []string
+	// MaxCertificateIssuances is the number of certificates issued in a namespace during the last hour above which the
+	// validating webhook rejects changes requiring new certificates in that namespace. 0 disables the limit.
+	MaxCertificateIssuances int
 	// MaxConcurrentReconciles controls the number of goroutines per controller.
 	MaxConcurrentReconciles int
+	// MaxConcurrentReconcilesPerController overrides MaxConcurrentReconciles for the controllers with the given names.
+	MaxConcurrentReconcilesPerController map[string]int
+	// Controllers records the names of the controllers created with these parameters, or is nil if they are not recorded.
+	Controllers *ControllerRegistry
+	// ReconcileRateLimiter defines how the reconciliations of each controller are delayed and rate limited.
+	ReconcileRateLimiter RateLimiterParams
+	// PVCDeletionGracePeriod is how long the PersistentVolumeClaims no longer used by an Elasticsearch cluster are kept
+	// before being deleted. 0 deletes them immediately.
+	PVCDeletionGracePeriod time.Duration
 	// SetDefaultSecurityContext enables setting the default security context
 	// with fsGroup=1000 for Elasticsearch 8.0+ Pods. Ignored pre-8.0
 	SetDefaultSecurityContext bool
+	// SetVMMaxMapCount enables adding a privileged init container setting vm.max_map_count to Elasticsearch Pods,
+	// unless disabled in the Elasticsearch specification.
+	SetVMMaxMapCount bool
 	// ValidateStorageClass specifies whether the operator should retrieve storage classes to verify volume expansion support.
 	// Can be disabled if cluster-wide storage class RBAC access is not available.
 	ValidateStorageClass bool
+	// DecisionTraces stores the checks and decisions made during the reconciliations, or is nil if they are not recorded.
+	DecisionTraces *decisions.Store
+	// DriftDetection handles the manual modifications of the reconciled resources, or is nil if they are not detected.
+	DriftDetection *reconciler.DriftDetection
+	// DryRun reports the changes made through the APIs of the Elastic Stack applications instead of applying them, or
+	// is nil if they are applied.
+	DryRun *dryrun.Mode
 	// Tracer is a shared APM tracer instance or nil
 	Tracer *apm.Tracer
+	// Watchdog tracks the reconciliations in progress to detect stuck controllers, or is nil if they are not tracked.
+	Watchdog *watchdog.Watchdog
+}
+
+// RateLimiterParams defines the workqueue rate limiter of a controller. The zero value uses the controller-runtime
+// defaults.
+type RateLimiterParams struct {
+	// BaseDelay is the delay before retrying a failed reconciliation, doubled after each consecutive failure of the
+	// same resource up to MaxDelay.
+	BaseDelay time.Duration
+	MaxDelay  time.Duration
+	// QPS and Burst limit the overall rate at which the resources of a controller are queued.
+	QPS   float64
+	Burst int
+}
+
+// MaxConcurrentReconcilesFor returns the number of goroutines of the controller with the given name.
+func (p Parameters) MaxConcurrentReconcilesFor(controllerName string) int {
+	if n, exists := p.MaxConcurrentReconcilesPerController[controllerName]; exists {
+		return n
+	}
+	return p.MaxConcurrentReconciles
+}
+
+// ParseMaxConcurrentReconcilesPerController parses the controllerName=count pairs of the
+// max-concurrent-reconciles-per-controller flag.
+func ParseMaxConcurrentReconcilesPerController(values map[string]string) (map[string]int, error) {
+	result := make(map[string]int, len(values))
+	for controllerName, value := range values {
+		n, err := strconv.Atoi(value)
+		if err != nil || n < 1 {
+			return nil, fmt.Errorf("invalid number of concurrent reconciles %q for controller %q: must be a positive integer", value, controllerName)
+		}
+		result[controllerName] = n
+	}
+	return result, nil
+}
+
+// ValidateMaxConcurrentReconcilesPerController returns an error if MaxConcurrentReconcilesPerController refers to
+// controllers which are not registered. It must be called once all the controllers are registered.
+func (p Parameters) ValidateMaxConcurrentReconcilesPerController() error {
+	var unknown []string
+	for controllerName := range p.MaxConcurrentReconcilesPerController {
+		if !p.Controllers.Has(controllerName) {
+			unknown = append(unknown, controllerName)
+		}
+	}
+	if len(unknown) == 0 {
+		return nil
+	}
+	sort.Strings(unknown)
+	return fmt.Errorf("unknown controllers in %s: %s", MaxConcurrentReconcilesPerControllerFlag, strings.Join(unknown, ", "))
+}
+
+// ControllerRegistry records the names of the registered controllers, to validate the settings referring to
+// controllers by name.
+type ControllerRegistry struct {
+	mutex sync.Mutex
+	names map[string]struct{}
+}
+
+// NewControllerRegistry returns an empty ControllerRegistry.
+func NewControllerRegistry() *ControllerRegistry {
+	return &ControllerRegistry{names: make(map[string]struct{})}
+}
+
+// Register records the controller with the given name. It is a no-op if the registry is nil.
+func (r *ControllerRegistry) Register(controllerName string) {
+	if r == nil {
+		return
+	}
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	r.names[controllerName] = struct{}{}
+}
+
+// Has returns true if the controller with the given name is registered. A nil registry has no controllers.
+func (r *ControllerRegistry) Has(controllerName string) bool {
+	if r == nil {
+		return false
+	}
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	_, exists := r.names[controllerName]
+	return exists
 }
@@ -0,0 +1,80 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package operator
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseMaxConcurrentReconcilesPerController(t *testing.T) {
+	tests := []struct {
+		name    string
+		values  map[string]string
+		want    map[string]int
+		wantErr bool
+	}{
+		{
+			name:   "no override",
+			values: map[string]string{},
+			want:   map[string]int{},
+		},
+		{
+			name:   "overrides",
+			values: map[string]string{"elasticsearch-controller": "10", "kibana-controller": "1"},
+			want:   map[string]int{"elasticsearch-controller": 10, "kibana-controller": 1},
+		},
+		{
+			name:    "not a number",
+			values:  map[string]string{"elasticsearch-controller": "ten"},
+			wantErr: true,
+		},
+		{
+			name:    "zero",
+			values:  map[string]string{"elasticsearch-controller": "0"},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseMaxConcurrentReconcilesPerController(tt.values)
+			if tt.wantErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestParameters_MaxConcurrentReconcilesFor(t *testing.T) {
+	p := Parameters{
+		MaxConcurrentReconciles:              3,
+		MaxConcurrentReconcilesPerController: map[string]int{"elasticsearch-controller": 10},
+	}
+	require.Equal(t, 10, p.MaxConcurrentReconcilesFor("elasticsearch-controller"))
+	require.Equal(t, 3, p.MaxConcurrentReconcilesFor("kibana-controller"))
+	require.Equal(t, 3, Parameters{MaxConcurrentReconciles: 3}.MaxConcurrentReconcilesFor("elasticsearch-controller"))
+}
+
+func TestParameters_ValidateMaxConcurrentReconcilesPerController(t *testing.T) {
+	controllers := NewControllerRegistry()
+	controllers.Register("elasticsearch-controller")
+	controllers.Register("kibana-controller")
+
+	p := Parameters{
+		Controllers:                          controllers,
+		MaxConcurrentReconcilesPerController: map[string]int{"elasticsearch-controller": 10},
+	}
+	require.NoError(t, p.ValidateMaxConcurrentReconcilesPerController())
+
+	p.MaxConcurrentReconcilesPerController["elasticsearch"] = 5
+	require.EqualError(t, p.ValidateMaxConcurrentReconcilesPerController(),
+		"unknown controllers in max-concurrent-reconciles-per-controller: elasticsearch")
+
+	require.NoError(t, Parameters{Controllers: controllers}.ValidateMaxConcurrentReconcilesPerController())
+}
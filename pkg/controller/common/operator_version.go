@@ -0,0 +1,67 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package common
+
+import (
+	"context"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/elastic/cloud-on-k8s/v2/pkg/about"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/version"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/utils/k8s"
+	ulog "github.com/elastic/cloud-on-k8s/v2/pkg/utils/log"
+)
+
+// OperatorVersionAnnotation is the annotation holding the version of the operator which last reconciled a resource.
+const OperatorVersionAnnotation = "eck.k8s.elastic.co/operator-version"
+
+// operatorVersion returns the version of the running operator, or nil if it is not known as in development builds.
+func operatorVersion() *version.Version {
+	v, err := version.Parse(about.GetBuildInfo().Version)
+	if err != nil || v.EQ(version.From(0, 0, 0)) {
+		return nil
+	}
+	// pre-releases of a version are considered equal to the version
+	release := version.From(int(v.Major), int(v.Minor), int(v.Patch))
+	return &release
+}
+
+// ReconcileOperatorVersion records the version of the running operator in the OperatorVersionAnnotation of the given
+// resource. It returns false, leaving the resource untouched, if the resource was last reconciled by a newer version
+// of the operator, to prevent an accidental operator downgrade from mangling the state written by the newer version.
+// Removing the annotation allows an older operator to manage the resource again.
+func ReconcileOperatorVersion(ctx context.Context, c k8s.Client, obj client.Object) (bool, error) {
+	return reconcileOperatorVersion(ctx, c, obj, operatorVersion())
+}
+
+func reconcileOperatorVersion(ctx context.Context, c k8s.Client, obj client.Object, current *version.Version) (bool, error) {
+	if current == nil {
+		return true, nil
+	}
+	if recorded, exists := obj.GetAnnotations()[OperatorVersionAnnotation]; exists {
+		recordedVersion, err := version.Parse(recorded)
+		if err == nil && recordedVersion.GT(*current) {
+			ulog.FromContext(ctx).Info(
+				"Resource was last managed by a newer operator version. Skipping reconciliation",
+				"namespace", obj.GetNamespace(), "name", obj.GetName(),
+				"operator_version", current.String(), "resource_operator_version", recorded,
+			)
+			return false, nil
+		}
+		if err == nil && recordedVersion.EQ(*current) {
+			return true, nil
+		}
+	}
+
+	patch := client.MergeFrom(obj.DeepCopyObject().(client.Object)) //nolint:forcetypeassert
+	annotations := obj.GetAnnotations()
+	if annotations == nil {
+		annotations = make(map[string]string, 1)
+	}
+	annotations[OperatorVersionAnnotation] = current.String()
+	obj.SetAnnotations(annotations)
+	return true, c.Patch(ctx, obj, patch)
+}
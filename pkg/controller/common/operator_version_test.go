@@ -0,0 +1,79 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package common
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/version"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/utils/k8s"
+)
+
+func Test_reconcileOperatorVersion(t *testing.T) {
+	v260 := version.MustParse("2.6.0")
+	tests := []struct {
+		name           string
+		current        *version.Version
+		recorded       string
+		wantManaged    bool
+		wantAnnotation string
+	}{
+		{
+			name:           "unknown operator version: resource is managed and left untouched",
+			current:        nil,
+			wantManaged:    true,
+			wantAnnotation: "",
+		},
+		{
+			name:           "no recorded version: the operator version is recorded",
+			current:        &v260,
+			wantManaged:    true,
+			wantAnnotation: "2.6.0",
+		},
+		{
+			name:           "older recorded version: the operator version is recorded",
+			current:        &v260,
+			recorded:       "2.5.0",
+			wantManaged:    true,
+			wantAnnotation: "2.6.0",
+		},
+		{
+			name:           "same recorded version: resource is managed",
+			current:        &v260,
+			recorded:       "2.6.0",
+			wantManaged:    true,
+			wantAnnotation: "2.6.0",
+		},
+		{
+			name:           "newer recorded version: resource is not managed",
+			current:        &v260,
+			recorded:       "2.7.0",
+			wantManaged:    false,
+			wantAnnotation: "2.7.0",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			obj := &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "resource"}}
+			if tt.recorded != "" {
+				obj.Annotations = map[string]string{OperatorVersionAnnotation: tt.recorded}
+			}
+			c := k8s.NewFakeClient(obj)
+
+			managed, err := reconcileOperatorVersion(context.Background(), c, obj, tt.current)
+			require.NoError(t, err)
+			require.Equal(t, tt.wantManaged, managed)
+
+			var actual corev1.ConfigMap
+			require.NoError(t, c.Get(context.Background(), k8s.ExtractNamespacedName(obj), &actual))
+			require.Equal(t, tt.wantAnnotation, actual.Annotations[OperatorVersionAnnotation])
+		})
+	}
+}
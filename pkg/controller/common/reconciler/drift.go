@@ -0,0 +1,135 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package reconciler
+
+import (
+	"context"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/events"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/hash"
+)
+
+const (
+	// ExpectedHashAnnotation is set on the resources reconciled by the operator with the hash of their expected state.
+	// A resource which needs to be updated while its expected state did not change since the last reconciliation has
+	// been modified outside the operator.
+	ExpectedHashAnnotation = "eck.k8s.elastic.co/expected-hash"
+)
+
+// DriftPolicy defines how the operator handles the manual modifications of the resources it manages.
+type DriftPolicy string
+
+const (
+	// DriftPolicyNone disables the detection of manual modifications, which are reverted silently.
+	DriftPolicyNone DriftPolicy = ""
+	// DriftPolicyRevert reverts manual modifications and reports them with an event.
+	DriftPolicyRevert DriftPolicy = "revert"
+	// DriftPolicyReport only reports manual modifications with an event, and leaves the resources untouched until their
+	// expected state changes.
+	DriftPolicyReport DriftPolicy = "report"
+)
+
+// ParseDriftPolicy parses the given drift policy.
+func ParseDriftPolicy(policy string) (DriftPolicy, error) {
+	switch DriftPolicy(policy) {
+	case DriftPolicyNone, DriftPolicyRevert, DriftPolicyReport:
+		return DriftPolicy(policy), nil
+	default:
+		return DriftPolicyNone, fmt.Errorf("invalid drift policy %q, must be one of %s, %s", policy, DriftPolicyRevert, DriftPolicyReport)
+	}
+}
+
+// DriftDetection handles the manual modifications of the reconciled resources according to a drift policy, and reports
+// them with events. A nil DriftDetection disables the detection.
+type DriftDetection struct {
+	policy   DriftPolicy
+	recorder record.EventRecorder
+}
+
+// NewDriftDetection returns a DriftDetection applying the given policy and emitting events with the given recorder,
+// or nil if the policy disables the detection.
+func NewDriftDetection(policy DriftPolicy, recorder record.EventRecorder) *DriftDetection {
+	if policy == DriftPolicyNone {
+		return nil
+	}
+	return &DriftDetection{policy: policy, recorder: recorder}
+}
+
+// Wrap returns a reconciler passing the drift detection to the given reconciler through the context of each
+// reconciliation. It returns the given reconciler if the drift detection is nil.
+func (d *DriftDetection) Wrap(r reconcile.Reconciler) reconcile.Reconciler {
+	if d == nil {
+		return r
+	}
+	return reconcile.Func(func(ctx context.Context, request reconcile.Request) (reconcile.Result, error) {
+		return r.Reconcile(NewDriftDetectionContext(ctx, d), request)
+	})
+}
+
+type driftDetectionKey struct{}
+
+// NewDriftDetectionContext returns a context holding the given drift detection.
+func NewDriftDetectionContext(ctx context.Context, d *DriftDetection) context.Context {
+	return context.WithValue(ctx, driftDetectionKey{}, d)
+}
+
+// driftDetectionFromContext returns the drift detection held by the given context, or nil.
+func driftDetectionFromContext(ctx context.Context) *DriftDetection {
+	if ctx == nil {
+		return nil
+	}
+	d, _ := ctx.Value(driftDetectionKey{}).(*DriftDetection)
+	return d
+}
+
+// expectedHash returns the hash of the expected state of a resource, or an empty string if drift detection is disabled.
+func (d *DriftDetection) expectedHash(expected client.Object) string {
+	if d == nil {
+		return ""
+	}
+	return hash.HashObject(expected)
+}
+
+// setExpectedHash records the hash of the expected state on the given resource.
+func setExpectedHash(obj client.Object, expectedHash string) {
+	if expectedHash == "" {
+		return
+	}
+	annotations := obj.GetAnnotations()
+	if annotations == nil {
+		annotations = make(map[string]string, 1)
+	}
+	annotations[ExpectedHashAnnotation] = expectedHash
+	obj.SetAnnotations(annotations)
+}
+
+// hasDrifted returns true if the given resource, which needs to be updated, was reconciled with the same expected
+// state before, which means it has been modified outside the operator.
+func hasDrifted(reconciled client.Object, expectedHash string) bool {
+	return expectedHash != "" && reconciled.GetAnnotations()[ExpectedHashAnnotation] == expectedHash
+}
+
+// reportDrift emits an event on the owner of the given drifted resource, or on the resource itself if it has no owner.
+func (d *DriftDetection) reportDrift(owner client.Object, reconciled client.Object, kind string) {
+	if d == nil || d.recorder == nil {
+		return
+	}
+	action := "reverting"
+	if d.policy == DriftPolicyReport {
+		action = "not reverting"
+	}
+	target := owner
+	if target == nil {
+		target = reconciled
+	}
+	d.recorder.Eventf(target, corev1.EventTypeWarning, events.EventReasonDrift,
+		"%s %s/%s was modified outside the operator, %s the changes", kind, reconciled.GetNamespace(), reconciled.GetName(), action)
+}
@@ -0,0 +1,106 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package reconciler
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+
+	"github.com/elastic/cloud-on-k8s/v2/pkg/utils/k8s"
+)
+
+func TestReconcileResource_Drift(t *testing.T) {
+	key := types.NamespacedName{Namespace: "ns", Name: "cm"}
+	reconcileConfigMap := func(c k8s.Client, d *DriftDetection, data string) error {
+		expected := &corev1.ConfigMap{
+			ObjectMeta: k8s.ToObjectMeta(key),
+			Data:       map[string]string{"key": data},
+		}
+		reconciled := &corev1.ConfigMap{}
+		return ReconcileResource(Params{
+			Context:    NewDriftDetectionContext(context.Background(), d),
+			Client:     c,
+			Expected:   expected,
+			Reconciled: reconciled,
+			NeedsUpdate: func() bool {
+				return !reflect.DeepEqual(expected.Data, reconciled.Data)
+			},
+			UpdateReconciled: func() {
+				reconciled.Data = expected.Data
+			},
+		})
+	}
+	editConfigMap := func(t *testing.T, c k8s.Client, data string) {
+		t.Helper()
+		var cm corev1.ConfigMap
+		require.NoError(t, c.Get(context.Background(), key, &cm))
+		cm.Data["key"] = data
+		require.NoError(t, c.Update(context.Background(), &cm))
+	}
+
+	tests := []struct {
+		name       string
+		policy     DriftPolicy
+		edit       string
+		expected   string
+		wantData   string
+		wantEvents int
+	}{
+		{
+			name:       "no drift detection: manual changes are reverted silently",
+			policy:     DriftPolicyNone,
+			edit:       "edited",
+			expected:   "value",
+			wantData:   "value",
+			wantEvents: 0,
+		},
+		{
+			name:       "revert policy: manual changes are reverted and reported",
+			policy:     DriftPolicyRevert,
+			edit:       "edited",
+			expected:   "value",
+			wantData:   "value",
+			wantEvents: 1,
+		},
+		{
+			name:       "report policy: manual changes are only reported",
+			policy:     DriftPolicyReport,
+			edit:       "edited",
+			expected:   "value",
+			wantData:   "edited",
+			wantEvents: 1,
+		},
+		{
+			name:       "report policy: changes of the expected state are applied",
+			policy:     DriftPolicyReport,
+			edit:       "edited",
+			expected:   "new-value",
+			wantData:   "new-value",
+			wantEvents: 0,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			recorder := record.NewFakeRecorder(10)
+			d := NewDriftDetection(tt.policy, recorder)
+
+			c := k8s.NewFakeClient()
+			require.NoError(t, reconcileConfigMap(c, d, "value"))
+			editConfigMap(t, c, tt.edit)
+			require.NoError(t, reconcileConfigMap(c, d, tt.expected))
+
+			var cm corev1.ConfigMap
+			require.NoError(t, c.Get(context.Background(), key, &cm))
+			require.Equal(t, tt.wantData, cm.Data["key"])
+			require.Len(t, recorder.Events, tt.wantEvents)
+		})
+	}
+}
@@ -87,6 +87,9 @@ func ReconcileResource(params Params) error {
 		}
 	}
 
+	driftDetection := driftDetectionFromContext(params.Context)
+	expectedStateHash := driftDetection.expectedHash(params.Expected)
+
 	create := func() error {
 		log.Info("Creating resource", "kind", kind, "namespace", namespace, "name", name)
 		if params.PreCreate != nil {
@@ -101,6 +104,7 @@ func ReconcileResource(params Params) error {
 		// This will panic if params.Expected and params.Reconciled don't have the same underlying type.
 		expectedCopyValue := reflect.ValueOf(params.Expected.DeepCopyObject()).Elem()
 		reflect.ValueOf(params.Reconciled).Elem().Set(expectedCopyValue)
+		setExpectedHash(params.Reconciled, expectedStateHash)
 		// Create the object, which modifies params.Reconciled in-place
 		err = params.Client.Create(params.Context, params.Reconciled)
 		if err != nil {
@@ -144,6 +148,13 @@ func ReconcileResource(params Params) error {
 	//nolint:nestif
 	// Update if needed
 	if params.NeedsUpdate() {
+		if hasDrifted(params.Reconciled, expectedStateHash) {
+			log.Info("Resource was modified outside the operator", "kind", kind, "namespace", namespace, "name", name, "drift_policy", driftDetection.policy)
+			driftDetection.reportDrift(params.Owner, params.Reconciled, kind)
+			if driftDetection.policy == DriftPolicyReport {
+				return nil
+			}
+		}
 		log.Info("Updating resource", "kind", kind, "namespace", namespace, "name", name)
 		if params.PreUpdate != nil {
 			if err := params.PreUpdate(); err != nil {
@@ -158,6 +169,7 @@ func ReconcileResource(params Params) error {
 		// retain the resource version to avoid unconditional updates
 		resourceVersion := reconciledMeta.GetResourceVersion()
 		params.UpdateReconciled()
+		setExpectedHash(params.Reconciled, expectedStateHash)
 		// and set the resource version back into the resource to indicate the state we are basing the update off of
 		reconciledMeta.SetResourceVersion(resourceVersion)
 		// also keep the owner references up to date
@@ -7,6 +7,7 @@ package reconciler
 import (
 	"context"
 	"reflect"
+	"time"
 
 	corev1 "k8s.io/api/core/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
@@ -29,6 +30,10 @@ const (
 	SoftOwnerKindLabel      = "eck.k8s.elastic.co/owner-kind"
 )
 
+// orphanSecretsGracePeriod is the minimum age of the secrets deleted by GarbageCollectAllSoftOwnedOrphanSecrets, which
+// prevents the deletion of the secrets of a newly created owner not yet in the cache.
+const orphanSecretsGracePeriod = time.Minute
+
 // ReconcileSecret creates or updates the actual secret to match the expected one.
 // Existing annotations or labels that are not expected are preserved.
 func ReconcileSecret(ctx context.Context, c k8s.Client, expected corev1.Secret, owner client.Object) (corev1.Secret, error) {
@@ -178,8 +183,8 @@ func GarbageCollectSoftOwnedSecrets(ctx context.Context, c k8s.Client, deletedOw
 // GarbageCollectAllSoftOwnedOrphanSecrets iterates over all Secrets that reference a soft owner. If the owner
 // doesn't exist anymore, it deletes the secrets.
 // Should be called on operator startup, after cache warm-up, to cover cases where
-// the operator is down when the owner is deleted.
-// If the operator is up, garbage collection is already handled by GarbageCollectSoftOwnedSecrets on owner deletion.
+// the operator is down when the owner is deleted, and periodically to catch the deletions missed by
+// GarbageCollectSoftOwnedSecrets, which handles garbage collection on owner deletion while the operator is up.
 func GarbageCollectAllSoftOwnedOrphanSecrets(ctx context.Context, c k8s.Client, ownerKinds map[string]client.Object) error {
 	// retrieve all secrets that reference a soft owner
 	var secrets corev1.SecretList
@@ -206,6 +211,10 @@ func GarbageCollectAllSoftOwnedOrphanSecrets(ctx context.Context, c k8s.Client,
 		if !managed {
 			continue
 		}
+		if time.Since(secret.CreationTimestamp.Time) < orphanSecretsGracePeriod {
+			// owner may have just been created
+			continue
+		}
 		owner = k8s.DeepCopyObject(owner)
 		err := c.Get(ctx, types.NamespacedName{Namespace: softOwner.Namespace, Name: softOwner.Name}, owner)
 		if err != nil {
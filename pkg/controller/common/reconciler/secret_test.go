@@ -382,6 +382,20 @@ func TestGarbageCollectAllSoftOwnedOrphanSecrets(t *testing.T) {
 			},
 			wantObjs: []runtime.Object{},
 		},
+		{
+			name: "don't gc recently created secrets",
+			runtimeObjs: []runtime.Object{
+				// owner may not be in the cache yet
+				func() *corev1.Secret {
+					s := ownedSecret("ns", "secret-1", sampleOwner().Namespace, sampleOwner().Name, sampleOwner().Kind)
+					s.CreationTimestamp = metav1.Now()
+					return s
+				}(),
+			},
+			wantObjs: []runtime.Object{
+				ownedSecret("ns", "secret-1", sampleOwner().Namespace, sampleOwner().Name, sampleOwner().Kind),
+			},
+		},
 		{
 			name: "don't gc secret targeting an owner in a different namespace",
 			runtimeObjs: []runtime.Object{
@@ -0,0 +1,121 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package settings
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+)
+
+// ConfigSource is a named configuration to be merged with other configurations.
+type ConfigSource struct {
+	// Name describes where the configuration comes from, for example "user configuration".
+	Name   string
+	Config *CanonicalConfig
+}
+
+// ConfigOverride describes a setting defined with different values by several configuration sources.
+type ConfigOverride struct {
+	// Key is the flattened key of the setting.
+	Key string
+	// Source is the name of the source whose value was overridden.
+	Source string
+	// By is the name of the source whose value took precedence.
+	By string
+	// Appended is true if the setting is a list, in which case the values of both sources are appended.
+	Appended bool
+}
+
+func (o ConfigOverride) String() string {
+	if o.Appended {
+		return fmt.Sprintf("%s: %s values appended to %s values", o.Key, o.By, o.Source)
+	}
+	return fmt.Sprintf("%s: %s value overridden by %s", o.Key, o.Source, o.By)
+}
+
+// ConfigOverrides is a list of ConfigOverride.
+type ConfigOverrides []ConfigOverride
+
+// Strings returns the string representation of each override, or nil if there is none.
+func (o ConfigOverrides) Strings() []string {
+	if len(o) == 0 {
+		return nil
+	}
+	strs := make([]string, 0, len(o))
+	for _, override := range o {
+		strs = append(strs, override.String())
+	}
+	return strs
+}
+
+// MergeSources merges the given configuration sources in order, later sources taking precedence over earlier ones.
+// It returns the merged configuration along with the settings defined by several sources with different values,
+// which would otherwise be silently overridden.
+func MergeSources(sources ...ConfigSource) (*CanonicalConfig, ConfigOverrides, error) {
+	merged := NewCanonicalConfig()
+	// name of the source each flattened key was last set by
+	owners := map[string]string{}
+	var overrides ConfigOverrides
+	for _, source := range sources {
+		if source.Config == nil {
+			continue
+		}
+		current, err := merged.Flatten()
+		if err != nil {
+			return nil, nil, err
+		}
+		values, err := source.Config.Flatten()
+		if err != nil {
+			return nil, nil, err
+		}
+		for _, key := range sortedKeys(values) {
+			previous, exists := current[key]
+			if exists && !reflect.DeepEqual(previous, values[key]) {
+				_, isList := values[key].([]interface{})
+				overrides = append(overrides, ConfigOverride{Key: key, Source: owners[key], By: source.Name, Appended: isList})
+			}
+			owners[key] = source.Name
+		}
+		if err := merged.MergeWith(source.Config); err != nil {
+			return nil, nil, err
+		}
+	}
+	return merged, overrides, nil
+}
+
+// Flatten returns the leaf values of c indexed by their flattened key. Lists are considered as leaf values.
+func (c *CanonicalConfig) Flatten() (map[string]interface{}, error) {
+	var out untypedDict
+	if err := c.asUCfg().Unpack(&out, Options...); err != nil {
+		return nil, err
+	}
+	flat := map[string]interface{}{}
+	flattenMap(out, "", flat)
+	return flat, nil
+}
+
+func flattenMap(m untypedDict, prefix string, into map[string]interface{}) {
+	for k, v := range m {
+		key := k
+		if prefix != "" {
+			key = prefix + "." + k
+		}
+		if child, isMap := v.(untypedDict); isMap {
+			flattenMap(child, key, into)
+			continue
+		}
+		into[key] = v
+	}
+}
+
+func sortedKeys(m map[string]interface{}) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
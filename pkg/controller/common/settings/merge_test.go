@@ -0,0 +1,89 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package settings
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestMergeSources(t *testing.T) {
+	tests := []struct {
+		name          string
+		sources       []ConfigSource
+		wantConfig    *CanonicalConfig
+		wantOverrides ConfigOverrides
+	}{
+		{
+			name:          "no sources",
+			wantConfig:    NewCanonicalConfig(),
+			wantOverrides: nil,
+		},
+		{
+			name: "no conflicts",
+			sources: []ConfigSource{
+				{Name: "operator", Config: MustCanonicalConfig(map[string]interface{}{"a.b": "c"})},
+				{Name: "nil", Config: nil},
+				{Name: "user", Config: MustCanonicalConfig(map[string]interface{}{"a": map[string]interface{}{"d": "e"}})},
+			},
+			wantConfig:    MustCanonicalConfig(map[string]interface{}{"a.b": "c", "a.d": "e"}),
+			wantOverrides: nil,
+		},
+		{
+			name: "same value in several sources is not a conflict",
+			sources: []ConfigSource{
+				{Name: "operator", Config: MustCanonicalConfig(map[string]interface{}{"a.b": "c"})},
+				{Name: "user", Config: MustCanonicalConfig(map[string]interface{}{"a.b": "c"})},
+			},
+			wantConfig:    MustCanonicalConfig(map[string]interface{}{"a.b": "c"}),
+			wantOverrides: nil,
+		},
+		{
+			name: "conflicts are reported with the sources involved",
+			sources: []ConfigSource{
+				{Name: "operator", Config: MustCanonicalConfig(map[string]interface{}{"a.b": "c", "x": "y"})},
+				{Name: "defaults", Config: MustCanonicalConfig(map[string]interface{}{"x": "z"})},
+				{Name: "user", Config: MustCanonicalConfig(map[string]interface{}{"a.b": "d", "x": "user", "list": []string{"b"}})},
+			},
+			wantConfig: MustCanonicalConfig(map[string]interface{}{"a.b": "d", "x": "user", "list": []string{"b"}}),
+			wantOverrides: ConfigOverrides{
+				{Key: "a.b", Source: "operator", By: "user"},
+				{Key: "x", Source: "operator", By: "defaults"},
+				{Key: "x", Source: "defaults", By: "user"},
+			},
+		},
+		{
+			name: "lists are appended",
+			sources: []ConfigSource{
+				{Name: "operator", Config: MustCanonicalConfig(map[string]interface{}{"list": []string{"a"}})},
+				{Name: "user", Config: MustCanonicalConfig(map[string]interface{}{"list": []string{"b"}})},
+			},
+			wantConfig: MustCanonicalConfig(map[string]interface{}{"list": []string{"a", "b"}}),
+			wantOverrides: ConfigOverrides{
+				{Key: "list", Source: "operator", By: "user", Appended: true},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, overrides, err := MergeSources(tt.sources...)
+			require.NoError(t, err)
+			require.Empty(t, got.Diff(tt.wantConfig, nil))
+			require.Equal(t, tt.wantOverrides, overrides)
+		})
+	}
+}
+
+func TestConfigOverrides_Strings(t *testing.T) {
+	overrides := ConfigOverrides{
+		{Key: "a.b", Source: "operator", By: "user"},
+		{Key: "list", Source: "operator", By: "user", Appended: true},
+	}
+	require.Equal(t, []string{
+		"a.b: operator value overridden by user",
+		"list: user values appended to operator values",
+	}, overrides.Strings())
+}
@@ -0,0 +1,82 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+// Package watchdog detects the reconciliations which do not complete, so that an operator whose controllers are stuck
+// can be restarted by its liveness probe.
+package watchdog
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+// reconciliation is a reconciliation in progress.
+type reconciliation struct {
+	controller string
+	request    reconcile.Request
+	startedAt  time.Time
+}
+
+// Watchdog tracks the reconciliations in progress in all the controllers.
+type Watchdog struct {
+	mutex      sync.Mutex
+	now        func() time.Time
+	timeout    time.Duration
+	nextID     uint64
+	inProgress map[uint64]reconciliation
+}
+
+// New returns a Watchdog considering the reconciliations in progress for longer than the given timeout as stuck.
+func New(timeout time.Duration) *Watchdog {
+	return newWatchdog(timeout, time.Now)
+}
+
+func newWatchdog(timeout time.Duration, now func() time.Time) *Watchdog {
+	return &Watchdog{now: now, timeout: timeout, inProgress: make(map[uint64]reconciliation)}
+}
+
+// Wrap returns a reconciler tracking the reconciliations of the given reconciler of the given controller, or the given
+// reconciler if the watchdog is nil.
+func (w *Watchdog) Wrap(controller string, r reconcile.Reconciler) reconcile.Reconciler {
+	if w == nil {
+		return r
+	}
+	return reconcile.Func(func(ctx context.Context, request reconcile.Request) (reconcile.Result, error) {
+		defer w.track(controller, request)()
+		return r.Reconcile(ctx, request)
+	})
+}
+
+// track records the start of a reconciliation and returns a function recording its end.
+func (w *Watchdog) track(controller string, request reconcile.Request) func() {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+	id := w.nextID
+	w.nextID++
+	w.inProgress[id] = reconciliation{controller: controller, request: request, startedAt: w.now()}
+	return func() {
+		w.mutex.Lock()
+		defer w.mutex.Unlock()
+		delete(w.inProgress, id)
+	}
+}
+
+// Check returns an error if a reconciliation has been in progress for longer than the timeout. It satisfies the
+// healthz.Checker signature.
+func (w *Watchdog) Check(_ *http.Request) error {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+	threshold := w.now().Add(-w.timeout)
+	for _, r := range w.inProgress {
+		if r.startedAt.Before(threshold) {
+			return fmt.Errorf("reconciliation of %s by %s in progress since %s", r.request.NamespacedName, r.controller, r.startedAt.Format(time.RFC3339))
+		}
+	}
+	return nil
+}
@@ -0,0 +1,48 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package watchdog
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+func TestWatchdog(t *testing.T) {
+	now := time.Date(2022, 10, 1, 12, 0, 0, 0, time.UTC)
+	w := newWatchdog(10*time.Minute, func() time.Time { return now })
+	request := reconcile.Request{NamespacedName: types.NamespacedName{Namespace: "ns", Name: "es"}}
+
+	// completed reconciliations are not tracked anymore
+	_, err := w.Wrap("elasticsearch-controller", reconcile.Func(func(context.Context, reconcile.Request) (reconcile.Result, error) {
+		now = now.Add(time.Hour)
+		return reconcile.Result{}, nil
+	})).Reconcile(context.Background(), request)
+	require.NoError(t, err)
+	require.NoError(t, w.Check(nil))
+
+	// reconciliations in progress for longer than the timeout are reported
+	done := w.track("elasticsearch-controller", request)
+	now = now.Add(5 * time.Minute)
+	require.NoError(t, w.Check(nil))
+	now = now.Add(6 * time.Minute)
+	require.EqualError(t, w.Check(nil), "reconciliation of ns/es by elasticsearch-controller in progress since 2022-10-01T13:00:00Z")
+	done()
+	require.NoError(t, w.Check(nil))
+}
+
+func TestWatchdog_nil(t *testing.T) {
+	var w *Watchdog
+	r := reconcile.Func(func(context.Context, reconcile.Request) (reconcile.Result, error) {
+		return reconcile.Result{Requeue: true}, nil
+	})
+	res, err := w.Wrap("elasticsearch-controller", r).Reconcile(context.Background(), reconcile.Request{})
+	require.NoError(t, err)
+	require.True(t, res.Requeue)
+}
@@ -0,0 +1,37 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package watches
+
+import (
+	"k8s.io/apimachinery/pkg/types"
+)
+
+// WatchUserProvidedConfigMaps registers a watch for user-provided ConfigMaps.
+// Only one watch per watcher is registered:
+// - if it already exists with different ConfigMaps, it is replaced to watch the new ConfigMaps.
+// - if there is no ConfigMap provided by the user, remove the watch.
+func WatchUserProvidedConfigMaps(
+	watcher types.NamespacedName, // resource to which the watches are attached (eg. an Elasticsearch object)
+	watched DynamicWatches, // existing dynamic watches
+	watchName string, // dynamic watch to register
+	configMaps []string, // user-provided ConfigMaps to watch
+) error {
+	if len(configMaps) == 0 {
+		watched.ConfigMaps.RemoveHandlerForKey(watchName)
+		return nil
+	}
+	nsns := make([]types.NamespacedName, 0, len(configMaps))
+	for _, name := range configMaps {
+		nsns = append(nsns, types.NamespacedName{
+			Namespace: watcher.Namespace,
+			Name:      name,
+		})
+	}
+	return watched.ConfigMaps.AddHandler(NamedWatch{
+		Name:    watchName,
+		Watched: nsns,
+		Watcher: watcher,
+	})
+}
@@ -7,6 +7,7 @@ package webhook
 import (
 	"context"
 
+	admissionv1 "k8s.io/api/admission/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/api/meta"
 	"k8s.io/apimachinery/pkg/runtime"
@@ -18,8 +19,11 @@ import (
 	ulog "github.com/elastic/cloud-on-k8s/v2/pkg/utils/log"
 )
 
-func (v *validatingWebhook) commonValidations(ctx context.Context, req admission.Request, obj runtime.Object) error {
+func (v *validatingWebhook) commonValidations(ctx context.Context, req admission.Request, obj runtime.Object, old runtime.Object) error {
 	errorList := hasRequestedLicenseLevel(ctx, obj, v.licenseChecker)
+	if req.Operation == admissionv1.Create || req.Operation == admissionv1.Update {
+		errorList = append(errorList, checkElasticsearchVersionCompatibility(ctx, v.client, obj, old)...)
+	}
 	if len(errorList) > 0 {
 		return apierrors.NewInvalid(schema.GroupKind{
 			Group: req.Kind.Group,
@@ -0,0 +1,113 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package webhook
+
+import (
+	"context"
+	"fmt"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+
+	apmv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/apm/v1"
+	beatv1beta1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/beat/v1beta1"
+	commonv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/common/v1"
+	esv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/elasticsearch/v1"
+	kbv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/kibana/v1"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/version"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/utils/k8s"
+	ulog "github.com/elastic/cloud-on-k8s/v2/pkg/utils/log"
+)
+
+const (
+	sameMinorVersionMsg     = "version %s must have the same major and minor version as the referenced Elasticsearch cluster (%s)"
+	notGreaterVersionMsg    = "version %s must not be greater than the version of the referenced Elasticsearch cluster (%s)"
+	upgradeElasticsearchMsg = ", upgrade Elasticsearch first"
+)
+
+// esVersionConstraint describes how the version of a resource relates to the version of the Elasticsearch cluster
+// it references.
+type esVersionConstraint struct {
+	version   string
+	namespace string
+	esRef     commonv1.ObjectSelector
+	// sameMinor requires the resource to run the same major and minor version as Elasticsearch, otherwise the version
+	// of the resource must be lower than or equal to the version of Elasticsearch.
+	sameMinor bool
+}
+
+// esVersionConstraintFor returns the Elasticsearch version constraint of the given resource, or false if the resource
+// does not reference an Elasticsearch cluster managed by the operator.
+func esVersionConstraintFor(obj runtime.Object) (esVersionConstraint, bool) {
+	var constraint esVersionConstraint
+	switch o := obj.(type) {
+	case *kbv1.Kibana:
+		constraint = esVersionConstraint{version: o.Spec.Version, namespace: o.Namespace, esRef: o.Spec.ElasticsearchRef, sameMinor: true}
+	case *apmv1.ApmServer:
+		constraint = esVersionConstraint{version: o.Spec.Version, namespace: o.Namespace, esRef: o.Spec.ElasticsearchRef}
+	case *beatv1beta1.Beat:
+		constraint = esVersionConstraint{version: o.Spec.Version, namespace: o.Namespace, esRef: o.Spec.ElasticsearchRef}
+	default:
+		return esVersionConstraint{}, false
+	}
+	if !constraint.esRef.IsDefined() || constraint.esRef.IsExternal() {
+		return esVersionConstraint{}, false
+	}
+	return constraint, true
+}
+
+// checkElasticsearchVersionCompatibility checks that the version of a Kibana, APM Server or Beat is compatible with
+// the version of the Elasticsearch cluster it references: Kibana must run the same minor version, APM Server and Beats
+// must not run a greater version. On updates, the check only applies if the version or the reference changes, so that
+// the resource can still be updated while the referenced Elasticsearch cluster is upgraded first.
+// The check is skipped if the Elasticsearch cluster cannot be retrieved: the association controllers report it.
+func checkElasticsearchVersionCompatibility(ctx context.Context, c k8s.Client, obj runtime.Object, old runtime.Object) field.ErrorList {
+	constraint, ok := esVersionConstraintFor(obj)
+	if !ok {
+		return nil
+	}
+	if old != nil {
+		oldConstraint, ok := esVersionConstraintFor(old)
+		if ok && oldConstraint.version == constraint.version && oldConstraint.esRef == constraint.esRef {
+			return nil
+		}
+	}
+
+	resourceVersion, err := version.Parse(constraint.version)
+	if err != nil {
+		// reported by the resource validations
+		return nil
+	}
+
+	whlog := ulog.FromContext(ctx).WithName("common-webhook")
+	var es esv1.Elasticsearch
+	esNsn := constraint.esRef.WithDefaultNamespace(constraint.namespace).NamespacedName()
+	if err := c.Get(ctx, esNsn, &es); err != nil {
+		if !apierrors.IsNotFound(err) {
+			whlog.Error(err, "while retrieving the referenced Elasticsearch cluster during validation", "namespace", esNsn.Namespace, "es_name", esNsn.Name)
+		}
+		return nil
+	}
+	esVersion, err := version.Parse(es.Spec.Version)
+	if err != nil {
+		return nil
+	}
+
+	// pre-release versions, for example snapshots, are compared as their final release
+	resourceVersion.Pre, esVersion.Pre = nil, nil
+	path := field.NewPath("spec").Child("version")
+	switch {
+	case constraint.sameMinor && (resourceVersion.Major != esVersion.Major || resourceVersion.Minor != esVersion.Minor):
+		msg := fmt.Sprintf(sameMinorVersionMsg, resourceVersion, esVersion)
+		if resourceVersion.GT(esVersion) {
+			msg += upgradeElasticsearchMsg
+		}
+		return field.ErrorList{field.Forbidden(path, msg)}
+	case !constraint.sameMinor && resourceVersion.GT(esVersion):
+		return field.ErrorList{field.Forbidden(path, fmt.Sprintf(notGreaterVersionMsg, resourceVersion, esVersion)+upgradeElasticsearchMsg)}
+	}
+	return nil
+}
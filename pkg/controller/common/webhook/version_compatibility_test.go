@@ -0,0 +1,133 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package webhook
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	agentv1alpha1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/agent/v1alpha1"
+	apmv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/apm/v1"
+	beatv1beta1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/beat/v1beta1"
+	commonv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/common/v1"
+	esv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/elasticsearch/v1"
+	kbv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/kibana/v1"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/utils/k8s"
+)
+
+func Test_checkElasticsearchVersionCompatibility(t *testing.T) {
+	es := &esv1.Elasticsearch{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "es"},
+		Spec:       esv1.ElasticsearchSpec{Version: "8.5.2"},
+	}
+	kibana := func(version string, esRef commonv1.ObjectSelector) *kbv1.Kibana {
+		return &kbv1.Kibana{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "kb"},
+			Spec:       kbv1.KibanaSpec{Version: version, ElasticsearchRef: esRef},
+		}
+	}
+	esRef := commonv1.ObjectSelector{Name: "es"}
+
+	tests := []struct {
+		name    string
+		obj     runtime.Object
+		old     runtime.Object
+		wantErr string
+	}{
+		{
+			name: "Kibana with the same minor version",
+			obj:  kibana("8.5.0", esRef),
+		},
+		{
+			name: "Kibana snapshot with the same minor version",
+			obj:  kibana("8.5.3-SNAPSHOT", esRef),
+		},
+		{
+			name:    "Kibana with a greater minor version",
+			obj:     kibana("8.6.0", esRef),
+			wantErr: "spec.version: Forbidden: version 8.6.0 must have the same major and minor version as the referenced Elasticsearch cluster (8.5.2), upgrade Elasticsearch first",
+		},
+		{
+			name:    "Kibana with a lower minor version",
+			obj:     kibana("8.4.0", esRef),
+			wantErr: "spec.version: Forbidden: version 8.4.0 must have the same major and minor version as the referenced Elasticsearch cluster (8.5.2)",
+		},
+		{
+			name:    "Kibana upgraded before Elasticsearch",
+			obj:     kibana("8.6.0", esRef),
+			old:     kibana("8.5.0", esRef),
+			wantErr: "upgrade Elasticsearch first",
+		},
+		{
+			name: "Kibana updated without changing the version or the reference",
+			obj:  kibana("8.4.0", esRef),
+			old:  kibana("8.4.0", esRef),
+		},
+		{
+			name: "Kibana without Elasticsearch reference",
+			obj:  kibana("8.6.0", commonv1.ObjectSelector{}),
+		},
+		{
+			name: "Kibana referencing an external Elasticsearch cluster",
+			obj:  kibana("8.6.0", commonv1.ObjectSelector{SecretName: "es-ref"}),
+		},
+		{
+			name: "Kibana referencing a missing Elasticsearch cluster",
+			obj:  kibana("8.6.0", commonv1.ObjectSelector{Name: "missing"}),
+		},
+		{
+			name: "APM Server with a lower version",
+			obj: &apmv1.ApmServer{
+				ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "apm"},
+				Spec:       apmv1.ApmServerSpec{Version: "8.4.0", ElasticsearchRef: esRef},
+			},
+		},
+		{
+			name: "APM Server with a greater version",
+			obj: &apmv1.ApmServer{
+				ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "apm"},
+				Spec:       apmv1.ApmServerSpec{Version: "8.5.3", ElasticsearchRef: esRef},
+			},
+			wantErr: "spec.version: Forbidden: version 8.5.3 must not be greater than the version of the referenced Elasticsearch cluster (8.5.2), upgrade Elasticsearch first",
+		},
+		{
+			name: "Beat from a previous major version",
+			obj: &beatv1beta1.Beat{
+				ObjectMeta: metav1.ObjectMeta{Namespace: "other", Name: "beat"},
+				Spec:       beatv1beta1.BeatSpec{Version: "7.17.0", ElasticsearchRef: commonv1.ObjectSelector{Namespace: "ns", Name: "es"}},
+			},
+		},
+		{
+			name: "Beat with a greater version",
+			obj: &beatv1beta1.Beat{
+				ObjectMeta: metav1.ObjectMeta{Namespace: "other", Name: "beat"},
+				Spec:       beatv1beta1.BeatSpec{Version: "8.6.0", ElasticsearchRef: commonv1.ObjectSelector{Namespace: "ns", Name: "es"}},
+			},
+			wantErr: "version 8.6.0 must not be greater than the version of the referenced Elasticsearch cluster (8.5.2)",
+		},
+		{
+			name: "resources without version constraint are not checked",
+			obj: &agentv1alpha1.Agent{
+				ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "agent"},
+				Spec:       agentv1alpha1.AgentSpec{Version: "8.6.0"},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			errs := checkElasticsearchVersionCompatibility(context.Background(), k8s.NewFakeClient(es), tt.obj, tt.old)
+			if tt.wantErr == "" {
+				require.Empty(t, errs)
+				return
+			}
+			require.Len(t, errs, 1)
+			require.Contains(t, errs[0].Error(), tt.wantErr)
+		})
+	}
+}
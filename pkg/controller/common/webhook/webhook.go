@@ -11,11 +11,13 @@ import (
 
 	admissionv1 "k8s.io/api/admission/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/webhook"
 	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
 
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/license"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/utils/k8s"
 	ulog "github.com/elastic/cloud-on-k8s/v2/pkg/utils/log"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/utils/set"
 )
@@ -36,6 +38,7 @@ func SetupValidatingWebhookWithConfig(config *Config) error {
 		config.WebhookPath,
 		&webhook.Admission{
 			Handler: &validatingWebhook{
+				client:            config.Client,
 				validator:         config.Validator,
 				licenseChecker:    config.LicenseChecker,
 				managedNamespaces: set.Make(config.ManagedNamespace...)}})
@@ -45,6 +48,7 @@ func SetupValidatingWebhookWithConfig(config *Config) error {
 // Config is the configuration for setting up a webhook
 type Config struct {
 	Manager          ctrl.Manager
+	Client           k8s.Client
 	WebhookPath      string
 	ManagedNamespace []string
 	LicenseChecker   license.Checker
@@ -52,6 +56,7 @@ type Config struct {
 }
 
 type validatingWebhook struct {
+	client            k8s.Client
 	decoder           *admission.Decoder
 	managedNamespaces set.StringSet
 	licenseChecker    license.Checker
@@ -88,7 +93,17 @@ func (v *validatingWebhook) Handle(ctx context.Context, req admission.Request) a
 		return admission.Allowed("")
 	}
 
-	if err := v.commonValidations(ctx, req, obj); err != nil {
+	var oldObj runtime.Object
+	if req.Operation == admissionv1.Update {
+		oldObj = v.validator.DeepCopyObject()
+		err = v.decoder.DecodeRaw(req.OldObject, oldObj)
+		if err != nil {
+			whlog.Error(err, "decoding old object from webhook request into type (%T)", oldObj)
+			return admission.Errored(http.StatusBadRequest, err)
+		}
+	}
+
+	if err := v.commonValidations(ctx, req, obj, oldObj); err != nil {
 		return admission.Denied(err.Error())
 	}
 
@@ -100,12 +115,6 @@ func (v *validatingWebhook) Handle(ctx context.Context, req admission.Request) a
 	}
 
 	if req.Operation == admissionv1.Update {
-		oldObj := v.validator.DeepCopyObject()
-		err = v.decoder.DecodeRaw(req.OldObject, oldObj)
-		if err != nil {
-			whlog.Error(err, "decoding old object from webhook request into type (%T)", oldObj)
-			return admission.Errored(http.StatusBadRequest, err)
-		}
 		err = obj.ValidateUpdate(oldObj)
 		if err != nil {
 			return admission.Denied(err.Error())
@@ -7,7 +7,6 @@ package certificates
 import (
 	"context"
 	"crypto/x509"
-	"time"
 
 	"go.elastic.co/apm/v2"
 	corev1 "k8s.io/api/core/v1"
@@ -17,6 +16,7 @@ import (
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/certificates"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/driver"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/events"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/expose"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/reconciler"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/tracing"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/elasticsearch/certificates/remoteca"
@@ -33,6 +33,7 @@ func ReconcileHTTP(
 	es esv1.Elasticsearch,
 	services []corev1.Service,
 	globalCA *certificates.CA,
+	namespaceCA bool,
 	caRotation certificates.RotationParams,
 	certRotation certificates.RotationParams,
 ) ([]*x509.Certificate, *reconciler.Results) {
@@ -48,14 +49,17 @@ func ReconcileHTTP(
 	extraHTTPSANs := make([]commonv1.SubjectAlternativeName, len(es.Spec.NodeSets))
 	for i, nodeSet := range es.Spec.NodeSets {
 		extraHTTPSANs[i] =
-			commonv1.SubjectAlternativeName{DNS: "*." + nodespec.HeadlessServiceName(esv1.StatefulSet(es.Name, nodeSet.Name)) + "." + es.Namespace + ".svc"}
+			commonv1.SubjectAlternativeName{DNS: "*." + nodespec.HeadlessServiceName(es.StatefulSetName(nodeSet.Name)) + "." + es.Namespace + ".svc"}
 	}
+	// Add the host the cluster is exposed at through an Ingress or a Route.
+	extraHTTPSANs = append(extraHTTPSANs, expose.SubjectAltNames(es.Spec.HTTP)...)
 
 	// reconcile HTTP CA and cert
 	var httpCerts *certificates.CertificatesSecret
 	httpCerts, results = certificates.Reconciler{
 		K8sClient:      driver.K8sClient(),
 		DynamicWatches: driver.DynamicWatches(),
+		Recorder:       driver.Recorder(),
 		Owner:          &es,
 		TLSOptions:     es.Spec.HTTP.TLS,
 		ExtraHTTPSANs:  extraHTTPSANs,
@@ -63,6 +67,7 @@ func ReconcileHTTP(
 		Labels:         certsLabels,
 		Services:       services,
 		GlobalCA:       globalCA,
+		NamespaceCA:    namespaceCA,
 		CACertRotation: caRotation,
 		CertRotation:   certRotation,
 		// ES is able to hot-reload TLS certificates: let's keep secrets around even though TLS is disabled.
@@ -89,6 +94,7 @@ func ReconcileTransport(
 	driver driver.Interface,
 	es esv1.Elasticsearch,
 	globalCA *certificates.CA,
+	namespaceCA bool,
 	caRotation certificates.RotationParams,
 	certRotation certificates.RotationParams,
 ) *reconciler.Results {
@@ -107,6 +113,7 @@ func ReconcileTransport(
 		es,
 		certsLabels,
 		globalCA,
+		namespaceCA,
 		caRotation,
 	)
 	if err != nil {
@@ -115,7 +122,7 @@ func ReconcileTransport(
 	// make sure to requeue before the CA cert expires
 	results.WithReconciliationState(
 		reconciler.
-			RequeueAfter(certificates.ShouldRotateIn(time.Now(), transportCA.Cert.NotAfter, caRotation.RotateBefore)).
+			RequeueAfter(certificates.ShouldRotateIn(caRotation.Now(), transportCA.Cert.NotAfter, caRotation.RotateBefore)).
 			ReconciliationComplete(), // This reconciliation result should not prevent the reconciliation loop to be considered as completed in the status
 	)
 
@@ -128,6 +135,7 @@ func ReconcileTransport(
 	transportResults := transport.ReconcileTransportCertificatesSecrets(
 		ctx,
 		driver.K8sClient(),
+		driver.Recorder(),
 		transportCA,
 		es,
 		certRotation,
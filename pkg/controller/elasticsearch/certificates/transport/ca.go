@@ -32,6 +32,7 @@ func ReconcileOrRetrieveCA(
 	es esv1.Elasticsearch,
 	labels map[string]string,
 	globalCA *certificates.CA,
+	namespaceCA bool,
 	rotationParams certificates.RotationParams,
 ) (*certificates.CA, error) {
 	esNSN := k8s.ExtractNamespacedName(&es)
@@ -54,15 +55,19 @@ func ReconcileOrRetrieveCA(
 		return nil, err
 	}
 	// 1. No custom certs are specified, reconcile our internal self-signed CA instead (probably the common case)
-	// or return the shared global CA
+	// or return the shared global or namespace CA
 	if customCASecret == nil {
 		if globalCA != nil {
 			return globalCA, nil
 		}
+		if namespaceCA {
+			return certificates.ReconcileNamespaceCA(ctx, driver.K8sClient(), es.Namespace, rotationParams)
+		}
 
 		return certificates.ReconcileCAForOwner(
 			ctx,
 			driver.K8sClient(),
+			driver.Recorder(),
 			esv1.ESNamer,
 			&es,
 			labels,
@@ -26,6 +26,7 @@ func createValidatedCertificateTemplate(
 	pod corev1.Pod,
 	cluster esv1.Elasticsearch,
 	csr *x509.CertificateRequest,
+	now time.Time,
 	certValidity time.Duration,
 ) (*certificates.ValidatedCertificateTemplate, error) {
 	if err := csr.CheckSignature(); err != nil {
@@ -51,8 +52,8 @@ func createValidatedCertificateTemplate(
 		ExtraExtensions: []pkix.Extension{
 			{Id: certificates.SubjectAlternativeNamesObjectIdentifier, Value: generalNamesBytes},
 		},
-		NotBefore: time.Now().Add(-10 * time.Minute),
-		NotAfter:  time.Now().Add(certValidity),
+		NotBefore: now.Add(-10 * time.Minute),
+		NotAfter:  now.Add(certValidity),
 
 		PublicKeyAlgorithm: csr.PublicKeyAlgorithm,
 		PublicKey:          csr.PublicKey,
@@ -12,9 +12,9 @@ import (
 	"errors"
 	"fmt"
 	"reflect"
-	"time"
 
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/client-go/tools/record"
 
 	esv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/elasticsearch/v1"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/certificates"
@@ -35,6 +35,7 @@ func PodCertFileName(podName string) string {
 // content for a specific pod
 func ensureTransportCertificatesSecretContentsForPod(
 	ctx context.Context,
+	recorder record.EventRecorder,
 	es esv1.Elasticsearch,
 	secret *corev1.Secret,
 	pod corev1.Pod,
@@ -60,7 +61,7 @@ func ensureTransportCertificatesSecretContentsForPod(
 		secret.Data[PodKeyFileName(pod.Name)] = pemPrivateKey
 	}
 
-	if shouldIssueNewCertificate(ctx, es, *secret, pod, privateKey, ca, rotationParams.RotateBefore) {
+	if shouldIssueNewCertificate(ctx, es, *secret, pod, privateKey, ca, rotationParams) {
 		log.Info(
 			"Issuing new certificate",
 			"pod_name", pod.Name,
@@ -78,7 +79,7 @@ func ensureTransportCertificatesSecretContentsForPod(
 		}
 
 		validatedCertificateTemplate, err := createValidatedCertificateTemplate(
-			pod, es, parsedCSR, rotationParams.Validity,
+			pod, es, parsedCSR, rotationParams.Now(), rotationParams.Validity,
 		)
 		if err != nil {
 			return err
@@ -88,6 +89,8 @@ func ensureTransportCertificatesSecretContentsForPod(
 		if err != nil {
 			return err
 		}
+		certificates.RecordIssuance(secret, certificates.TransportIssuance)
+		certificates.RecordIssuanceEvent(recorder, &es, fmt.Sprintf("Issued transport certificate for Pod %s", pod.Name))
 
 		// store the issued certificate in a secret mounted into the pod
 		secret.Data[PodCertFileName(pod.Name)] = certificates.EncodePEMCert(certData, ca.Cert.Raw)
@@ -111,7 +114,7 @@ func shouldIssueNewCertificate(
 	pod corev1.Pod,
 	privateKey crypto.Signer,
 	ca *certificates.CA,
-	certReconcileBefore time.Duration,
+	rotationParams certificates.RotationParams,
 ) bool {
 	log := ulog.FromContext(ctx)
 	certCommonName := buildCertificateCommonName(pod, es)
@@ -140,12 +143,14 @@ func shouldIssueNewCertificate(
 		return true
 	}
 
+	now := rotationParams.Now()
 	pool := x509.NewCertPool()
 	pool.AddCert(ca.Cert)
 	verifyOpts := x509.VerifyOptions{
 		DNSName:       certCommonName,
 		Roots:         pool,
 		Intermediates: pool,
+		CurrentTime:   now,
 	}
 	if _, err := cert.Verify(verifyOpts); err != nil {
 		log.Info(
@@ -159,7 +164,7 @@ func shouldIssueNewCertificate(
 		return true
 	}
 
-	if time.Now().After(cert.NotAfter.Add(-certReconcileBefore)) {
+	if now.After(cert.NotAfter.Add(-rotationParams.RotateBefore)) {
 		log.Info("Certificate soon to expire, should issue new",
 			"namespace", pod.Namespace, "pod", pod.Name)
 		return true
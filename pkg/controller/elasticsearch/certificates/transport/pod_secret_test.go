@@ -99,7 +99,7 @@ func Test_shouldIssueNewCertificate(t *testing.T) {
 				*tt.args.pod,
 				testRSAPrivateKey,
 				testRSACA,
-				tt.args.rotateBefore,
+				certificates.RotationParams{RotateBefore: tt.args.rotateBefore},
 			); got != tt.want {
 				t.Errorf("shouldIssueNewCertificate() = %v, want %v", got, tt.want)
 			}
@@ -228,6 +228,7 @@ func Test_ensureTransportCertificatesSecretContentsForPod(t *testing.T) {
 
 			err := ensureTransportCertificatesSecretContentsForPod(
 				context.Background(),
+				nil,
 				testES,
 				tt.secret,
 				*tt.pod,
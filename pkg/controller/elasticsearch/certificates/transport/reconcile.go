@@ -9,12 +9,12 @@ import (
 	"context"
 	"reflect"
 	"strings"
-	"time"
 
 	"github.com/pkg/errors"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 
@@ -35,6 +35,7 @@ import (
 func ReconcileTransportCertificatesSecrets(
 	ctx context.Context,
 	c k8s.Client,
+	recorder record.EventRecorder,
 	ca *certificates.CA,
 	es esv1.Elasticsearch,
 	rotationParams certificates.RotationParams,
@@ -50,11 +51,11 @@ func ReconcileTransportCertificatesSecrets(
 	}
 	ssets := actualStatefulSets.Names()
 	for _, nodeSet := range es.Spec.NodeSets {
-		ssets.Add(esv1.StatefulSet(es.Name, nodeSet.Name))
+		ssets.Add(es.StatefulSetName(nodeSet.Name))
 	}
 
 	for ssetName := range ssets {
-		if err := reconcileNodeSetTransportCertificatesSecrets(ctx, c, ca, es, ssetName, rotationParams); err != nil {
+		if err := reconcileNodeSetTransportCertificatesSecrets(ctx, c, recorder, ca, es, ssetName, rotationParams); err != nil {
 			results.WithError(err)
 		}
 	}
@@ -83,6 +84,7 @@ func DeleteLegacyTransportCertificate(ctx context.Context, client k8s.Client, es
 func reconcileNodeSetTransportCertificatesSecrets(
 	ctx context.Context,
 	c k8s.Client,
+	recorder record.EventRecorder,
 	ca *certificates.CA,
 	es esv1.Elasticsearch,
 	ssetName string,
@@ -111,7 +113,7 @@ func reconcileNodeSetTransportCertificatesSecrets(
 		}
 
 		if err := ensureTransportCertificatesSecretContentsForPod(
-			ctx, es, secret, pod, ca, rotationParams,
+			ctx, recorder, es, secret, pod, ca, rotationParams,
 		); err != nil {
 			return err
 		}
@@ -122,7 +124,7 @@ func reconcileNodeSetTransportCertificatesSecrets(
 		}
 		// handle cert expiry via requeue
 		results.WithResult(reconcile.Result{
-			RequeueAfter: certificates.ShouldRotateIn(time.Now(), cert.NotAfter, rotationParams.RotateBefore),
+			RequeueAfter: certificates.ShouldRotateIn(rotationParams.Now(), cert.NotAfter, rotationParams.RotateBefore),
 		})
 	}
 
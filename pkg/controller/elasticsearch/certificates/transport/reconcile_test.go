@@ -190,7 +190,7 @@ func TestReconcileTransportCertificatesSecrets(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			k8sClient := k8s.NewFakeClient(tt.args.initialObjects...)
-			if got := ReconcileTransportCertificatesSecrets(context.Background(), k8sClient, tt.args.ca, *tt.args.es, tt.args.rotationParams); !reflect.DeepEqual(got, tt.want) {
+			if got := ReconcileTransportCertificatesSecrets(context.Background(), k8sClient, nil, tt.args.ca, *tt.args.es, tt.args.rotationParams); !reflect.DeepEqual(got, tt.want) {
 				t.Errorf("ReconcileTransportCertificatesSecrets() = %v, want %v", got, tt.want)
 			}
 			// Check Secrets
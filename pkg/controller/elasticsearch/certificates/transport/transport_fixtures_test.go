@@ -11,6 +11,7 @@ import (
 	"crypto/x509/pkix"
 	"encoding/pem"
 	"fmt"
+	"time"
 
 	v1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
@@ -114,7 +115,7 @@ func init() {
 		panic("Failed to parse CSR:" + err.Error())
 	}
 
-	validatedRSACertificateTemplate, err := createValidatedCertificateTemplate(testPod, testES, testRSACSR, certificates.DefaultCertValidity)
+	validatedRSACertificateTemplate, err := createValidatedCertificateTemplate(testPod, testES, testRSACSR, time.Now(), certificates.DefaultCertValidity)
 	if err != nil {
 		panic("Failed to create validated cert template:" + err.Error())
 	}
@@ -9,18 +9,28 @@ import (
 	"context"
 	"crypto/x509"
 	"encoding/json"
+	"fmt"
 	"io"
 	"net/http"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/hashicorp/go-multierror"
 	"k8s.io/apimachinery/pkg/types"
 
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/dryrun"
 	commonhttp "github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/http"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/version"
 	ulog "github.com/elastic/cloud-on-k8s/v2/pkg/utils/log"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/utils/metrics"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/utils/stringsutil"
 )
 
+// dryRunResponseBody is the body of the responses to the requests which are not sent in dry-run mode. It acknowledges
+// the request, and the license update, so that the reconciliation carries on as if the request succeeded.
+const dryRunResponseBody = `{"acknowledged":true,"license_status":"valid"}`
+
 type baseClient struct {
 	User     BasicAuth
 	HTTP     *http.Client
@@ -29,6 +39,7 @@ type baseClient struct {
 	caCerts  []*x509.Certificate
 	version  version.Version
 	debug    bool
+	dryRun   *dryrun.Reporter
 }
 
 // Close idle connections in the underlying http client.
@@ -77,7 +88,24 @@ func (c *baseClient) doRequest(context context.Context, request *http.Request) (
 		"namespace", c.es.Namespace,
 		"es_name", c.es.Name,
 	)
+	if c.dryRun != nil && request.Method != http.MethodGet && request.Method != http.MethodHead {
+		c.dryRun.Report(context, fmt.Sprintf("Dry run: would send the Elasticsearch request %s %s", request.Method, request.URL.Path))
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Body:       io.NopCloser(strings.NewReader(dryRunResponseBody)),
+			Request:    withContext,
+		}, nil
+	}
+
+	start := time.Now()
 	response, err := c.HTTP.Do(withContext)
+	code := "error"
+	if err == nil {
+		code = strconv.Itoa(response.StatusCode)
+	}
+	metrics.ElasticsearchRequestDuration.
+		WithLabelValues(request.Method, code).
+		Observe(time.Since(start).Seconds())
 	if err != nil {
 		return response, newDecoratedHTTPError(request, err)
 	}
@@ -17,6 +17,7 @@ import (
 
 	esv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/elasticsearch/v1"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/annotation"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/dryrun"
 	commonhttp "github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/http"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/version"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/utils/net"
@@ -58,10 +59,14 @@ type Role struct {
 type Client interface {
 	AllocationSetter
 	AutoscalingClient
+	ILMClient
+	IngestClient
 	DesiredNodesClient
 	ShardLister
 	LicenseClient
 	SecurityClient
+	SnapshotClient
+	TemplateClient
 	// Close idle connections in the underlying http client.
 	Close()
 	// Equal returns true if other can be considered as the same client.
@@ -101,6 +106,10 @@ type Client interface {
 	UpdateRemoteClusterSettings(ctx context.Context, settings RemoteClustersSettings) error
 	// GetRemoteClusterSettings retrieves the remote clusters of a cluster.
 	GetRemoteClusterSettings(ctx context.Context) (RemoteClustersSettings, error)
+	// UpdateClusterSettings updates the persistent settings of a cluster. Settings with a nil value are reset.
+	UpdateClusterSettings(ctx context.Context, settings ClusterSettings) error
+	// GetClusterSettings retrieves the persistent settings of a cluster, indexed by their flattened key.
+	GetClusterSettings(ctx context.Context) (ClusterSettings, error)
 	// AddVotingConfigExclusions sets the transient and persistent setting of the same name in cluster settings.
 	// Introduced in: Elasticsearch 7.0.0
 	AddVotingConfigExclusions(ctx context.Context, nodeNames []string) error
@@ -139,7 +148,8 @@ func formatAsSeconds(d time.Duration) string {
 
 // NewElasticsearchClient creates a new client for the target cluster.
 //
-// If dialer is not nil, it will be used to create new TCP connections
+// If dialer is not nil, it will be used to create new TCP connections.
+// If dryRun is not nil, requests other than GET and HEAD requests are reported instead of being sent, and succeed.
 func NewElasticsearchClient(
 	dialer net.Dialer,
 	es types.NamespacedName,
@@ -149,6 +159,7 @@ func NewElasticsearchClient(
 	caCerts []*x509.Certificate,
 	timeout time.Duration,
 	debug bool,
+	dryRun *dryrun.Reporter,
 ) Client {
 	client := commonhttp.Client(dialer, caCerts, timeout)
 	client.Transport = apmelasticsearch.WrapRoundTripper(client.Transport)
@@ -159,6 +170,7 @@ func NewElasticsearchClient(
 		HTTP:     client,
 		es:       es,
 		debug:    debug,
+		dryRun:   dryRun,
 	}
 	return versioned(base, v)
 }
@@ -0,0 +1,105 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package client
+
+import (
+	"context"
+	"net/url"
+	"strings"
+
+	"github.com/elastic/cloud-on-k8s/v2/pkg/utils/stringsutil"
+)
+
+type ILMClient interface {
+	// GetILMPolicies returns the index lifecycle management policies, indexed by name.
+	GetILMPolicies(ctx context.Context) (ILMPolicies, error)
+	// UpdateILMPolicy creates an index lifecycle management policy, or updates it if it already exists.
+	UpdateILMPolicy(ctx context.Context, name string, policy map[string]interface{}) error
+	// DeleteILMPolicy deletes an index lifecycle management policy.
+	DeleteILMPolicy(ctx context.Context, name string) error
+}
+
+// ILMPolicies maps the name of the index lifecycle management policies to their definition.
+type ILMPolicies map[string]ILMPolicyDefinition
+
+type ILMPolicyDefinition struct {
+	Policy ILMPolicy `json:"policy"`
+}
+
+type ILMPolicy struct {
+	Phases map[string]ILMPhase `json:"phases"`
+}
+
+type ILMPhase struct {
+	Actions ILMActions `json:"actions"`
+}
+
+type ILMActions struct {
+	Allocate *ILMAllocateAction `json:"allocate,omitempty"`
+	Migrate  *ILMMigrateAction  `json:"migrate,omitempty"`
+}
+
+type ILMAllocateAction struct {
+	Include map[string]string `json:"include,omitempty"`
+	Exclude map[string]string `json:"exclude,omitempty"`
+	Require map[string]string `json:"require,omitempty"`
+}
+
+type ILMMigrateAction struct {
+	Enabled *bool `json:"enabled,omitempty"`
+}
+
+// ilmTierPhases are the ILM phases which implicitly migrate indices to the data tier of the same name.
+var ilmTierPhases = []string{"warm", "cold", "frozen"}
+
+// DataTiers returns the names of the data tiers the policies move indices to. Tiers are either implicitly targeted by
+// the warm, cold and frozen phases, unless the migrate action is disabled, or explicitly by allocate actions requiring
+// or including nodes with the given data attribute.
+func (p ILMPolicies) DataTiers(dataAttribute string) []string {
+	seen := make(map[string]struct{})
+	var tiers []string
+	add := func(tier string) {
+		if _, exists := seen[tier]; exists {
+			return
+		}
+		seen[tier] = struct{}{}
+		tiers = append(tiers, tier)
+	}
+	for _, definition := range p {
+		for name, phase := range definition.Policy.Phases {
+			if allocate := phase.Actions.Allocate; allocate != nil {
+				for _, filter := range []map[string]string{allocate.Require, allocate.Include} {
+					for _, tier := range strings.Split(filter[dataAttribute], ",") {
+						if tier = strings.TrimSpace(tier); tier != "" {
+							add(tier)
+						}
+					}
+				}
+			}
+			if !stringsutil.StringInSlice(name, ilmTierPhases) {
+				continue
+			}
+			if migrate := phase.Actions.Migrate; migrate != nil && migrate.Enabled != nil && !*migrate.Enabled {
+				continue
+			}
+			add(name)
+		}
+	}
+	return tiers
+}
+
+func (c *baseClient) GetILMPolicies(ctx context.Context) (ILMPolicies, error) {
+	var policies ILMPolicies
+	err := c.get(ctx, "/_ilm/policy", &policies)
+	return policies, err
+}
+
+func (c *baseClient) UpdateILMPolicy(ctx context.Context, name string, policy map[string]interface{}) error {
+	return c.put(ctx, "/_ilm/policy/"+url.PathEscape(name), map[string]interface{}{"policy": policy}, nil)
+}
+
+func (c *baseClient) DeleteILMPolicy(ctx context.Context, name string) error {
+	return c.delete(ctx, "/_ilm/policy/"+url.PathEscape(name))
+}
@@ -0,0 +1,45 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package client
+
+import (
+	"encoding/json"
+	"sort"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+const ilmPoliciesSample = `{
+  "logs": {
+    "version": 1,
+    "policy": {
+      "phases": {
+        "hot": {"actions": {"rollover": {"max_age": "7d"}}},
+        "warm": {"actions": {"shrink": {"number_of_shards": 1}}},
+        "delete": {"min_age": "90d", "actions": {"delete": {}}}
+      }
+    }
+  },
+  "metrics": {
+    "version": 3,
+    "policy": {
+      "phases": {
+        "warm": {"actions": {"migrate": {"enabled": false}, "allocate": {"require": {"data": "cold, frozen"}}}},
+        "cold": {"actions": {"migrate": {"enabled": false}}}
+      }
+    }
+  }
+}`
+
+func TestILMPolicies_DataTiers(t *testing.T) {
+	var policies ILMPolicies
+	require.NoError(t, json.Unmarshal([]byte(ilmPoliciesSample), &policies))
+	tiers := policies.DataTiers("data")
+	sort.Strings(tiers)
+	require.Equal(t, []string{"cold", "frozen", "warm"}, tiers)
+
+	require.Empty(t, ILMPolicies{}.DataTiers("data"))
+}
@@ -0,0 +1,40 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package client
+
+import (
+	"context"
+	"net/url"
+)
+
+// IngestPipelines maps the name of the ingest pipelines to their definition.
+type IngestPipelines map[string]map[string]interface{}
+
+type IngestClient interface {
+	// GetIngestPipelines returns all the ingest pipelines.
+	GetIngestPipelines(ctx context.Context) (IngestPipelines, error)
+	// UpdateIngestPipeline creates an ingest pipeline, or updates it if it already exists.
+	UpdateIngestPipeline(ctx context.Context, name string, pipeline map[string]interface{}) error
+	// DeleteIngestPipeline deletes an ingest pipeline.
+	DeleteIngestPipeline(ctx context.Context, name string) error
+}
+
+func (c *baseClient) GetIngestPipelines(ctx context.Context) (IngestPipelines, error) {
+	pipelines := IngestPipelines{}
+	err := c.get(ctx, "/_ingest/pipeline", &pipelines)
+	if IsNotFound(err) {
+		// returned by some versions of Elasticsearch when there is no pipeline
+		return IngestPipelines{}, nil
+	}
+	return pipelines, err
+}
+
+func (c *baseClient) UpdateIngestPipeline(ctx context.Context, name string, pipeline map[string]interface{}) error {
+	return c.put(ctx, "/_ingest/pipeline/"+url.PathEscape(name), pipeline, nil)
+}
+
+func (c *baseClient) DeleteIngestPipeline(ctx context.Context, name string) error {
+	return c.delete(ctx, "/_ingest/pipeline/"+url.PathEscape(name))
+}
@@ -396,6 +396,11 @@ type RemoteCluster struct {
 	Seeds []string `json:"seeds"`
 }
 
+// ClusterSettings models the persistent settings of a cluster, indexed by their flattened key.
+type ClusterSettings struct {
+	PersistentSettings map[string]interface{} `json:"persistent"`
+}
+
 // Hit represents a single search hit.
 type Hit struct {
 	Index  string                 `json:"_index"`
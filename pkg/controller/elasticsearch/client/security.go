@@ -7,6 +7,9 @@ package client
 import (
 	"context"
 	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
 
 	"github.com/elastic/cloud-on-k8s/v2/pkg/utils/set"
 )
@@ -34,10 +37,69 @@ func (s *ServiceAccountCredential) Nodes() set.StringSet {
 	return result
 }
 
+// Users maps the name of the users of the native realm to their definition.
+type Users map[string]User
+
+// User is a user of the native realm, as returned by the security API.
+type User struct {
+	Roles    []string `json:"roles"`
+	FullName string   `json:"full_name,omitempty"`
+	Email    string   `json:"email,omitempty"`
+	Enabled  bool     `json:"enabled"`
+	// Password of the user, only set when creating or updating the user.
+	Password string `json:"password,omitempty"`
+}
+
+// Equal returns true if both users have the same roles, in any order, full name, email and enablement. Passwords are
+// not compared since they are not returned by the security API.
+func (u User) Equal(other User) bool {
+	if u.FullName != other.FullName || u.Email != other.Email || u.Enabled != other.Enabled {
+		return false
+	}
+	roles, otherRoles := set.Make(u.Roles...), set.Make(other.Roles...)
+	return roles.Count() == otherRoles.Count() && roles.Diff(otherRoles).Count() == 0
+}
+
+// APIKeyRequest is a request to create an API key through the security API.
+type APIKeyRequest struct {
+	Name string `json:"name"`
+	// Expiration of the API key, as a time value such as "14d". The API key does not expire if empty.
+	Expiration string `json:"expiration,omitempty"`
+	// RoleDescriptors narrow the privileges of the API key to the intersection of the given roles and of the privileges
+	// of the user creating the API key.
+	RoleDescriptors map[string]Role `json:"role_descriptors,omitempty"`
+}
+
+// APIKey is an API key, as returned by the security API when it is created.
+type APIKey struct {
+	ID     string `json:"id"`
+	Name   string `json:"name"`
+	APIKey string `json:"api_key"`
+	// Expiration of the API key in milliseconds since the epoch, zero if the API key does not expire.
+	Expiration int64 `json:"expiration,omitempty"`
+}
+
 type SecurityClient interface {
 
 	// GetServiceAccountCredentials returns the service account credentials from the /_security/service API
 	GetServiceAccountCredentials(ctx context.Context, namespacedService string) (ServiceAccountCredential, error)
+	// GetUsers returns the users of the native realm, including the reserved users, indexed by name.
+	GetUsers(ctx context.Context) (Users, error)
+	// UpdateUser creates a user of the native realm, or updates it if it already exists. The password of an existing
+	// user is left unchanged if the password of the given user is empty.
+	UpdateUser(ctx context.Context, name string, user User) error
+	// DeleteUser deletes a user of the native realm.
+	DeleteUser(ctx context.Context, name string) error
+	// GetRoles returns the definition of the given native or reserved roles, indexed by name.
+	GetRoles(ctx context.Context, names ...string) (map[string]Role, error)
+	// CreateAPIKey creates an API key for the user of the client.
+	CreateAPIKey(ctx context.Context, request APIKeyRequest) (APIKey, error)
+	// InvalidateAPIKeys invalidates all the API keys with the given name.
+	InvalidateAPIKeys(ctx context.Context, name string) error
+	// UpdateRoleMapping creates a role mapping, or updates it if it already exists.
+	UpdateRoleMapping(ctx context.Context, name string, mapping map[string]interface{}) error
+	// DeleteRoleMapping deletes a role mapping.
+	DeleteRoleMapping(ctx context.Context, name string) error
 }
 
 func (c *clientV6) GetServiceAccountCredentials(_ context.Context, _ string) (ServiceAccountCredential, error) {
@@ -52,3 +114,45 @@ func (c *clientV7) GetServiceAccountCredentials(ctx context.Context, namespacedS
 	}
 	return serviceAccountCredential, nil
 }
+
+func (c *baseClient) GetUsers(ctx context.Context) (Users, error) {
+	var users Users
+	err := c.get(ctx, "/_security/user", &users)
+	return users, err
+}
+
+func (c *baseClient) UpdateUser(ctx context.Context, name string, user User) error {
+	return c.put(ctx, "/_security/user/"+url.PathEscape(name), user, nil)
+}
+
+func (c *baseClient) DeleteUser(ctx context.Context, name string) error {
+	return c.delete(ctx, "/_security/user/"+url.PathEscape(name))
+}
+
+func (c *baseClient) GetRoles(ctx context.Context, names ...string) (map[string]Role, error) {
+	escaped := make([]string, len(names))
+	for i, name := range names {
+		escaped[i] = url.PathEscape(name)
+	}
+	var roles map[string]Role
+	err := c.get(ctx, "/_security/role/"+strings.Join(escaped, ","), &roles)
+	return roles, err
+}
+
+func (c *baseClient) CreateAPIKey(ctx context.Context, request APIKeyRequest) (APIKey, error) {
+	var apiKey APIKey
+	err := c.post(ctx, "/_security/api_key", request, &apiKey)
+	return apiKey, err
+}
+
+func (c *baseClient) InvalidateAPIKeys(ctx context.Context, name string) error {
+	return c.request(ctx, http.MethodDelete, "/_security/api_key", map[string]string{"name": name}, nil, nil)
+}
+
+func (c *baseClient) UpdateRoleMapping(ctx context.Context, name string, mapping map[string]interface{}) error {
+	return c.put(ctx, "/_security/role_mapping/"+url.PathEscape(name), mapping, nil)
+}
+
+func (c *baseClient) DeleteRoleMapping(ctx context.Context, name string) error {
+	return c.delete(ctx, "/_security/role_mapping/"+url.PathEscape(name))
+}
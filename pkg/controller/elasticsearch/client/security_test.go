@@ -76,3 +76,71 @@ func Test_GetServiceAccountCredentials(t *testing.T) {
 		})
 	}
 }
+
+func TestClient_GetUsers(t *testing.T) {
+	client := NewMockClient(version.MustParse("8.5.0"), func(req *http.Request) *http.Response {
+		require.Equal(t, http.MethodGet, req.Method)
+		require.Equal(t, "/_security/user", req.URL.Path)
+		return NewMockResponse(200, req, `{
+	"elastic": {"username": "elastic", "roles": ["superuser"], "full_name": null, "email": null, "metadata": {"_reserved": true}, "enabled": true},
+	"jacknich": {"username": "jacknich", "roles": ["admin", "other_role1"], "full_name": "Jack Nicholson", "email": "jacknich@example.com", "metadata": {}, "enabled": true}
+}`)
+	})
+	users, err := client.GetUsers(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, Users{
+		"elastic":  {Roles: []string{"superuser"}, Enabled: true},
+		"jacknich": {Roles: []string{"admin", "other_role1"}, FullName: "Jack Nicholson", Email: "jacknich@example.com", Enabled: true},
+	}, users)
+}
+
+func TestClient_UpdateUser(t *testing.T) {
+	client := NewMockClient(version.MustParse("8.5.0"), func(req *http.Request) *http.Response {
+		require.Equal(t, http.MethodPut, req.Method)
+		require.Equal(t, "/_security/user/jacknich", req.URL.Path)
+		body, err := io.ReadAll(req.Body)
+		require.NoError(t, err)
+		require.JSONEq(t, `{"roles":["admin"],"full_name":"Jack Nicholson","enabled":true,"password":"l0ng-r4nd0m-p@ssw0rd"}`, string(body))
+		return NewMockResponse(200, req, `{"created":true}`)
+	})
+	err := client.UpdateUser(context.Background(), "jacknich", User{
+		Roles: []string{"admin"}, FullName: "Jack Nicholson", Enabled: true, Password: "l0ng-r4nd0m-p@ssw0rd",
+	})
+	require.NoError(t, err)
+}
+
+func TestClient_CreateAPIKey(t *testing.T) {
+	client := NewMockClient(version.MustParse("8.5.0"), func(req *http.Request) *http.Response {
+		require.Equal(t, http.MethodPost, req.Method)
+		require.Equal(t, "/_security/api_key", req.URL.Path)
+		body, err := io.ReadAll(req.Body)
+		require.NoError(t, err)
+		require.JSONEq(t, `{"name":"ns-filebeat-beat-user","expiration":"14d","role_descriptors":{"writer":{"cluster":["monitor"]}}}`, string(body))
+		return NewMockResponse(200, req, `{"id":"VuaCfGcBCdbkQm-e5aOx","name":"ns-filebeat-beat-user","expiration":1544068612110,"api_key":"ui2lp2axTNmsyakw9tvNnw","encoded":"VnVhQ2ZHY0JDZGJrUW0tZTVhT3g6dWkybHAyYXhUTm1zeWFrdzl0dk5udw=="}`)
+	})
+	apiKey, err := client.CreateAPIKey(context.Background(), APIKeyRequest{
+		Name: "ns-filebeat-beat-user", Expiration: "14d", RoleDescriptors: map[string]Role{"writer": {Cluster: []string{"monitor"}}},
+	})
+	require.NoError(t, err)
+	require.Equal(t, APIKey{ID: "VuaCfGcBCdbkQm-e5aOx", Name: "ns-filebeat-beat-user", APIKey: "ui2lp2axTNmsyakw9tvNnw", Expiration: 1544068612110}, apiKey)
+}
+
+func TestClient_InvalidateAPIKeys(t *testing.T) {
+	client := NewMockClient(version.MustParse("8.5.0"), func(req *http.Request) *http.Response {
+		require.Equal(t, http.MethodDelete, req.Method)
+		require.Equal(t, "/_security/api_key", req.URL.Path)
+		body, err := io.ReadAll(req.Body)
+		require.NoError(t, err)
+		require.JSONEq(t, `{"name":"ns-filebeat-beat-user"}`, string(body))
+		return NewMockResponse(200, req, `{"invalidated_api_keys":["VuaCfGcBCdbkQm-e5aOx"],"previously_invalidated_api_keys":[],"error_count":0}`)
+	})
+	require.NoError(t, client.InvalidateAPIKeys(context.Background(), "ns-filebeat-beat-user"))
+}
+
+func TestUser_Equal(t *testing.T) {
+	user := User{Roles: []string{"admin", "viewer"}, FullName: "Jack Nicholson", Enabled: true}
+	require.True(t, user.Equal(User{Roles: []string{"viewer", "admin"}, FullName: "Jack Nicholson", Enabled: true, Password: "ignored"}))
+	require.False(t, user.Equal(User{Roles: []string{"admin"}, FullName: "Jack Nicholson", Enabled: true}))
+	require.False(t, user.Equal(User{Roles: []string{"admin", "viewer"}, Enabled: true}))
+	require.False(t, user.Equal(User{Roles: []string{"admin", "viewer"}, FullName: "Jack Nicholson"}))
+}
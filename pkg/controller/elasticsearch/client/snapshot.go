@@ -0,0 +1,306 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"reflect"
+	"strings"
+	"time"
+)
+
+type SnapshotClient interface {
+	// GetSnapshotRepositories returns the snapshot repositories registered in Elasticsearch, indexed by name.
+	GetSnapshotRepositories(ctx context.Context) (SnapshotRepositories, error)
+	// UpdateSnapshotRepository registers a snapshot repository, or updates its settings if it already exists.
+	UpdateSnapshotRepository(ctx context.Context, name string, repository SnapshotRepository) error
+	// VerifySnapshotRepository checks that all the nodes can access the given snapshot repository.
+	VerifySnapshotRepository(ctx context.Context, name string) error
+	// DeleteSnapshotRepository unregisters a snapshot repository. The snapshots it contains are not deleted.
+	DeleteSnapshotRepository(ctx context.Context, name string) error
+	// GetSnapshots returns the snapshots of the given repository.
+	GetSnapshots(ctx context.Context, repository string) ([]Snapshot, error)
+	// GetSnapshot returns the given snapshot, or nil if it does not exist.
+	GetSnapshot(ctx context.Context, repository, name string) (*Snapshot, error)
+	// CreateSnapshot starts a snapshot of the given indices, or of all of them if none is given, without waiting for its
+	// completion.
+	CreateSnapshot(ctx context.Context, repository, name string, indices []string) error
+	// DeleteSnapshot deletes a snapshot.
+	DeleteSnapshot(ctx context.Context, repository, name string) error
+	// GetSLMPolicies returns the snapshot lifecycle management policies, indexed by name.
+	GetSLMPolicies(ctx context.Context) (SLMPolicies, error)
+	// UpdateSLMPolicy creates or updates a snapshot lifecycle management policy.
+	UpdateSLMPolicy(ctx context.Context, name string, policy SLMPolicy) error
+	// DeleteSLMPolicy deletes a snapshot lifecycle management policy. The snapshots it took are not deleted.
+	DeleteSLMPolicy(ctx context.Context, name string) error
+	// RestoreSnapshot starts the restore of the given indices of a snapshot, or of all of them if none is given, without
+	// waiting for its completion.
+	RestoreSnapshot(ctx context.Context, repository, name string, indices []string) error
+	// CountRestoringShards returns the number of shards being restored from the given snapshot.
+	CountRestoringShards(ctx context.Context, repository, name string) (int, error)
+	// CountDocuments returns the number of documents in the given indices, or in all of them if none is given.
+	CountDocuments(ctx context.Context, indices []string) (int64, error)
+}
+
+// SnapshotRepositories maps the name of the snapshot repositories to their definition.
+type SnapshotRepositories map[string]SnapshotRepository
+
+// SnapshotRepository is the definition of a snapshot repository.
+type SnapshotRepository struct {
+	Type     string                 `json:"type"`
+	Settings map[string]interface{} `json:"settings,omitempty"`
+}
+
+// Equal returns true if both repositories have the same type and settings. Settings are compared by their flattened
+// string representation, since Elasticsearch returns all the setting values as strings.
+func (r SnapshotRepository) Equal(other SnapshotRepository) bool {
+	if r.Type != other.Type {
+		return false
+	}
+	settings, otherSettings := flattenSettings("", r.Settings), flattenSettings("", other.Settings)
+	if len(settings) != len(otherSettings) {
+		return false
+	}
+	for k, v := range settings {
+		if otherValue, exists := otherSettings[k]; !exists || otherValue != v {
+			return false
+		}
+	}
+	return true
+}
+
+// flattenSettings returns the given settings indexed by their dotted name, with their value formatted as a string.
+func flattenSettings(prefix string, settings map[string]interface{}) map[string]string {
+	flattened := make(map[string]string, len(settings))
+	for k, v := range settings {
+		if prefix != "" {
+			k = prefix + "." + k
+		}
+		if nested, isMap := v.(map[string]interface{}); isMap {
+			for nestedKey, nestedValue := range flattenSettings(k, nested) {
+				flattened[nestedKey] = nestedValue
+			}
+			continue
+		}
+		flattened[k] = fmt.Sprint(v)
+	}
+	return flattened
+}
+
+// Snapshot is a snapshot as returned by the snapshot API.
+type Snapshot struct {
+	Snapshot          string                 `json:"snapshot"`
+	State             string                 `json:"state"`
+	StartTimeInMillis int64                  `json:"start_time_in_millis"`
+	Reason            string                 `json:"reason,omitempty"`
+	Failures          []SnapshotShardFailure `json:"failures,omitempty"`
+}
+
+// SnapshotShardFailure is the failure of the snapshot of a shard.
+type SnapshotShardFailure struct {
+	Index   string `json:"index"`
+	ShardID int    `json:"shard_id"`
+	Reason  string `json:"reason"`
+}
+
+// StartTime returns the time the snapshot started.
+func (s Snapshot) StartTime() time.Time {
+	return time.UnixMilli(s.StartTimeInMillis)
+}
+
+// FailureReason returns the reason of the failure of the snapshot, or the reason of the failure of its first failed
+// shard if no reason is given for the snapshot as a whole.
+func (s Snapshot) FailureReason() string {
+	if s.Reason != "" || len(s.Failures) == 0 {
+		return s.Reason
+	}
+	failure := s.Failures[0]
+	return fmt.Sprintf("shard %d of index %s: %s", failure.ShardID, failure.Index, failure.Reason)
+}
+
+type snapshots struct {
+	Snapshots []Snapshot `json:"snapshots"`
+}
+
+type createSnapshotRequest struct {
+	Indices []string `json:"indices,omitempty"`
+}
+
+type countResponse struct {
+	Count int64 `json:"count"`
+}
+
+type restoreSnapshotRequest struct {
+	Indices []string `json:"indices,omitempty"`
+}
+
+// recoveries maps index names to their ongoing shard recoveries, as returned by the recovery API.
+type recoveries map[string]struct {
+	Shards []shardRecovery `json:"shards"`
+}
+
+type shardRecovery struct {
+	Type   string `json:"type"`
+	Source struct {
+		Repository string `json:"repository"`
+		Snapshot   string `json:"snapshot"`
+	} `json:"source"`
+}
+
+// SLMPolicies maps the name of the snapshot lifecycle management policies to their definition.
+type SLMPolicies map[string]SLMPolicyDefinition
+
+// SLMPolicyDefinition is a snapshot lifecycle management policy along with its last executions.
+type SLMPolicyDefinition struct {
+	Policy              SLMPolicy      `json:"policy"`
+	LastSuccess         *SLMInvocation `json:"last_success,omitempty"`
+	LastFailure         *SLMInvocation `json:"last_failure,omitempty"`
+	NextExecutionMillis int64          `json:"next_execution_millis,omitempty"`
+	InProgress          *SLMInProgress `json:"in_progress,omitempty"`
+}
+
+// SLMInvocation is an execution of a snapshot lifecycle management policy.
+type SLMInvocation struct {
+	SnapshotName string `json:"snapshot_name"`
+	Time         int64  `json:"time"`
+	// Details of a failed execution, as the JSON representation of the error.
+	Details string `json:"details,omitempty"`
+}
+
+// FailureReason returns the reason of the error of a failed execution, or its raw details if they cannot be parsed.
+func (i SLMInvocation) FailureReason() string {
+	var details struct {
+		Reason string `json:"reason"`
+	}
+	if err := json.Unmarshal([]byte(i.Details), &details); err != nil || details.Reason == "" {
+		return i.Details
+	}
+	return details.Reason
+}
+
+// SLMInProgress is the snapshot being taken by a snapshot lifecycle management policy.
+type SLMInProgress struct {
+	Name            string `json:"name"`
+	State           string `json:"state"`
+	StartTimeMillis int64  `json:"start_time_millis"`
+}
+
+// SLMPolicy is a snapshot lifecycle management policy.
+type SLMPolicy struct {
+	Schedule   string           `json:"schedule"`
+	Name       string           `json:"name"`
+	Repository string           `json:"repository"`
+	Config     *SLMPolicyConfig `json:"config,omitempty"`
+	Retention  *SLMRetention    `json:"retention,omitempty"`
+}
+
+// SLMPolicyConfig is the configuration of the snapshots taken by a snapshot lifecycle management policy.
+type SLMPolicyConfig struct {
+	Indices []string `json:"indices,omitempty"`
+}
+
+// SLMRetention is the retention of the snapshots taken by a snapshot lifecycle management policy.
+type SLMRetention struct {
+	ExpireAfter string `json:"expire_after,omitempty"`
+	MinCount    *int32 `json:"min_count,omitempty"`
+	MaxCount    *int32 `json:"max_count,omitempty"`
+}
+
+// Equal returns true if both policies are the same.
+func (p SLMPolicy) Equal(other SLMPolicy) bool {
+	return reflect.DeepEqual(p, other)
+}
+
+func (c *baseClient) GetSnapshotRepositories(ctx context.Context) (SnapshotRepositories, error) {
+	var repositories SnapshotRepositories
+	err := c.get(ctx, "/_snapshot", &repositories)
+	return repositories, err
+}
+
+func (c *baseClient) UpdateSnapshotRepository(ctx context.Context, name string, repository SnapshotRepository) error {
+	return c.put(ctx, "/_snapshot/"+url.PathEscape(name), repository, nil)
+}
+
+func (c *baseClient) VerifySnapshotRepository(ctx context.Context, name string) error {
+	return c.post(ctx, "/_snapshot/"+url.PathEscape(name)+"/_verify", nil, nil)
+}
+
+func (c *baseClient) DeleteSnapshotRepository(ctx context.Context, name string) error {
+	return c.delete(ctx, "/_snapshot/"+url.PathEscape(name))
+}
+
+func (c *baseClient) GetSnapshots(ctx context.Context, repository string) ([]Snapshot, error) {
+	var result snapshots
+	err := c.get(ctx, "/_snapshot/"+url.PathEscape(repository)+"/_all", &result)
+	return result.Snapshots, err
+}
+
+func (c *baseClient) GetSnapshot(ctx context.Context, repository, name string) (*Snapshot, error) {
+	var result snapshots
+	err := c.get(ctx, "/_snapshot/"+url.PathEscape(repository)+"/"+url.PathEscape(name), &result)
+	if IsNotFound(err) || (err == nil && len(result.Snapshots) == 0) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &result.Snapshots[0], nil
+}
+
+func (c *baseClient) CreateSnapshot(ctx context.Context, repository, name string, indices []string) error {
+	return c.put(ctx, "/_snapshot/"+url.PathEscape(repository)+"/"+url.PathEscape(name), createSnapshotRequest{Indices: indices}, nil)
+}
+
+func (c *baseClient) DeleteSnapshot(ctx context.Context, repository, name string) error {
+	return c.delete(ctx, "/_snapshot/"+url.PathEscape(repository)+"/"+url.PathEscape(name))
+}
+
+func (c *baseClient) GetSLMPolicies(ctx context.Context) (SLMPolicies, error) {
+	var policies SLMPolicies
+	err := c.get(ctx, "/_slm/policy", &policies)
+	return policies, err
+}
+
+func (c *baseClient) UpdateSLMPolicy(ctx context.Context, name string, policy SLMPolicy) error {
+	return c.put(ctx, "/_slm/policy/"+url.PathEscape(name), policy, nil)
+}
+
+func (c *baseClient) DeleteSLMPolicy(ctx context.Context, name string) error {
+	return c.delete(ctx, "/_slm/policy/"+url.PathEscape(name))
+}
+
+func (c *baseClient) RestoreSnapshot(ctx context.Context, repository, name string, indices []string) error {
+	path := "/_snapshot/" + url.PathEscape(repository) + "/" + url.PathEscape(name) + "/_restore"
+	return c.post(ctx, path, restoreSnapshotRequest{Indices: indices}, nil)
+}
+
+func (c *baseClient) CountRestoringShards(ctx context.Context, repository, name string) (int, error) {
+	var result recoveries
+	if err := c.get(ctx, "/_recovery?active_only=true", &result); err != nil {
+		return 0, err
+	}
+	count := 0
+	for _, index := range result {
+		for _, shard := range index.Shards {
+			if shard.Type == "SNAPSHOT" && shard.Source.Repository == repository && shard.Source.Snapshot == name {
+				count++
+			}
+		}
+	}
+	return count, nil
+}
+
+func (c *baseClient) CountDocuments(ctx context.Context, indices []string) (int64, error) {
+	path := "/_count"
+	if len(indices) > 0 {
+		path = "/" + url.PathEscape(strings.Join(indices, ",")) + path
+	}
+	var result countResponse
+	err := c.get(ctx, path, &result)
+	return result.Count, err
+}
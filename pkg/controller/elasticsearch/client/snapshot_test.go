@@ -0,0 +1,217 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/version"
+)
+
+const snapshotRepositoriesSample = `{
+  "s3-backups": {
+    "type": "s3",
+    "settings": {
+      "bucket": "backups",
+      "compress": "true",
+      "client": "default",
+      "max_restore_bytes_per_sec": "40mb"
+    }
+  }
+}`
+
+func TestSnapshotRepository_Equal(t *testing.T) {
+	var repositories SnapshotRepositories
+	require.NoError(t, json.Unmarshal([]byte(snapshotRepositoriesSample), &repositories))
+	actual := repositories["s3-backups"]
+
+	tests := []struct {
+		name     string
+		expected SnapshotRepository
+		want     bool
+	}{
+		{
+			name: "same settings with different value types",
+			expected: SnapshotRepository{Type: "s3", Settings: map[string]interface{}{
+				"bucket": "backups", "compress": true, "client": "default", "max_restore_bytes_per_sec": "40mb",
+			}},
+			want: true,
+		},
+		{
+			name:     "different type",
+			expected: SnapshotRepository{Type: "gcs", Settings: actual.Settings},
+			want:     false,
+		},
+		{
+			name: "different setting value",
+			expected: SnapshotRepository{Type: "s3", Settings: map[string]interface{}{
+				"bucket": "other", "compress": true, "client": "default", "max_restore_bytes_per_sec": "40mb",
+			}},
+			want: false,
+		},
+		{
+			name:     "missing settings",
+			expected: SnapshotRepository{Type: "s3", Settings: map[string]interface{}{"bucket": "backups"}},
+			want:     false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.Equal(t, tt.want, tt.expected.Equal(actual))
+			require.Equal(t, tt.want, actual.Equal(tt.expected))
+		})
+	}
+
+	// nested and dotted settings are equivalent
+	nested := SnapshotRepository{Type: "fs", Settings: map[string]interface{}{"location": "/mnt/snapshots", "readonly": "false"}}
+	dotted := SnapshotRepository{Type: "fs", Settings: map[string]interface{}{"location": "/mnt/snapshots", "readonly": false}}
+	require.True(t, nested.Equal(dotted))
+	nested.Settings["client"] = map[string]interface{}{"name": "default"}
+	dotted.Settings["client.name"] = "default"
+	require.True(t, nested.Equal(dotted))
+}
+
+const slmPoliciesSample = `{
+  "eck-s3-backups": {
+    "version": 1,
+    "modified_date_millis": 1665990000000,
+    "policy": {
+      "name": "<eck-s3-backups-{now/d}>",
+      "schedule": "0 30 1 * * ?",
+      "repository": "s3-backups",
+      "config": {
+        "indices": ["logs-*"]
+      },
+      "retention": {
+        "expire_after": "30d",
+        "min_count": 5
+      }
+    },
+    "last_success": {
+      "snapshot_name": "eck-s3-backups-2022.10.17-abc",
+      "time": 1665970200000
+    },
+    "last_failure": {
+      "snapshot_name": "eck-s3-backups-2022.10.16-def",
+      "time": 1665883800000,
+      "details": "{\"type\":\"snapshot_exception\",\"reason\":\"[s3-backups:eck-s3-backups-2022.10.16-def] failed to write metadata\"}"
+    },
+    "next_execution_millis": 1666056600000,
+    "in_progress": {
+      "name": "eck-s3-backups-2022.10.17-ghi",
+      "uuid": "a1b2c3",
+      "state": "STARTED",
+      "start_time_millis": 1666056600000
+    },
+    "stats": {
+      "policy": "eck-s3-backups",
+      "snapshots_taken": 1
+    }
+  }
+}`
+
+func TestSLMPolicies(t *testing.T) {
+	var policies SLMPolicies
+	require.NoError(t, json.Unmarshal([]byte(slmPoliciesSample), &policies))
+	definition, exists := policies["eck-s3-backups"]
+	require.True(t, exists)
+
+	minCount := int32(5)
+	expected := SLMPolicy{
+		Schedule:   "0 30 1 * * ?",
+		Name:       "<eck-s3-backups-{now/d}>",
+		Repository: "s3-backups",
+		Config:     &SLMPolicyConfig{Indices: []string{"logs-*"}},
+		Retention:  &SLMRetention{ExpireAfter: "30d", MinCount: &minCount},
+	}
+	require.True(t, expected.Equal(definition.Policy))
+	expected.Retention = nil
+	require.False(t, expected.Equal(definition.Policy))
+
+	require.Equal(t, &SLMInvocation{SnapshotName: "eck-s3-backups-2022.10.17-abc", Time: 1665970200000}, definition.LastSuccess)
+	require.Equal(t, "[s3-backups:eck-s3-backups-2022.10.16-def] failed to write metadata", definition.LastFailure.FailureReason())
+	require.Equal(t, int64(1666056600000), definition.NextExecutionMillis)
+	require.Equal(t, &SLMInProgress{Name: "eck-s3-backups-2022.10.17-ghi", State: "STARTED", StartTimeMillis: 1666056600000}, definition.InProgress)
+
+	// details which are not a JSON error are returned as is
+	require.Equal(t, "unexpected", SLMInvocation{Details: "unexpected"}.FailureReason())
+}
+
+func TestSnapshot_FailureReason(t *testing.T) {
+	require.Equal(t, "", Snapshot{State: "SUCCESS"}.FailureReason())
+	require.Equal(t, "repository is readonly", Snapshot{State: "FAILED", Reason: "repository is readonly"}.FailureReason())
+	require.Equal(t, "shard 1 of index logs: IndexShardSnapshotFailedException[aborted]", Snapshot{
+		State:    "PARTIAL",
+		Failures: []SnapshotShardFailure{{Index: "logs", ShardID: 1, Reason: "IndexShardSnapshotFailedException[aborted]"}},
+	}.FailureReason())
+}
+
+const recoveriesSample = `{
+  "logs-1": {
+    "shards": [
+      {
+        "id": 0,
+        "type": "SNAPSHOT",
+        "stage": "INDEX",
+        "source": {"repository": "s3-backups", "snapshot": "snap-1", "version": "8.5.0", "index": "logs-1"}
+      },
+      {
+        "id": 1,
+        "type": "SNAPSHOT",
+        "stage": "INDEX",
+        "source": {"repository": "s3-backups", "snapshot": "snap-2", "version": "8.5.0", "index": "logs-1"}
+      }
+    ]
+  },
+  "metrics-1": {
+    "shards": [
+      {
+        "id": 0,
+        "type": "SNAPSHOT",
+        "stage": "TRANSLOG",
+        "source": {"repository": "s3-backups", "snapshot": "snap-1", "version": "8.5.0", "index": "metrics-1"}
+      },
+      {
+        "id": 0,
+        "type": "PEER",
+        "stage": "INDEX",
+        "source": {"id": "abc", "name": "es-default-0"}
+      }
+    ]
+  }
+}`
+
+func TestClient_CountRestoringShards(t *testing.T) {
+	client := NewMockClient(version.MustParse("8.5.0"), func(req *http.Request) *http.Response {
+		require.Equal(t, "/_recovery", req.URL.Path)
+		require.Equal(t, "true", req.URL.Query().Get("active_only"))
+		return NewMockResponse(200, req, recoveriesSample)
+	})
+	count, err := client.CountRestoringShards(context.Background(), "s3-backups", "snap-1")
+	require.NoError(t, err)
+	require.Equal(t, 2, count)
+	count, err = client.CountRestoringShards(context.Background(), "other", "snap-1")
+	require.NoError(t, err)
+	require.Equal(t, 0, count)
+}
+
+func TestClient_CountDocuments(t *testing.T) {
+	var paths []string
+	client := NewMockClient(version.MustParse("8.5.0"), func(req *http.Request) *http.Response {
+		paths = append(paths, req.URL.Path)
+		return NewMockResponse(200, req, `{"count":42,"_shards":{"total":2,"successful":2,"skipped":0,"failed":0}}`)
+	})
+	count, err := client.CountDocuments(context.Background(), nil)
+	require.NoError(t, err)
+	require.Equal(t, int64(42), count)
+	_, err = client.CountDocuments(context.Background(), []string{"logs-*", "metrics"})
+	require.NoError(t, err)
+	require.Equal(t, []string{"/_count", "/logs-*,metrics/_count"}, paths)
+}
@@ -0,0 +1,122 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package client
+
+import (
+	"context"
+	"net/url"
+)
+
+// IndexTemplates maps the name of the index or component templates to their definition.
+type IndexTemplates map[string]map[string]interface{}
+
+// componentTemplatesResponse is the response of the component templates API.
+type componentTemplatesResponse struct {
+	ComponentTemplates []struct {
+		Name              string                 `json:"name"`
+		ComponentTemplate map[string]interface{} `json:"component_template"`
+	} `json:"component_templates"`
+}
+
+// indexTemplatesResponse is the response of the composable index templates API.
+type indexTemplatesResponse struct {
+	IndexTemplates []struct {
+		Name          string                 `json:"name"`
+		IndexTemplate map[string]interface{} `json:"index_template"`
+	} `json:"index_templates"`
+}
+
+type TemplateClient interface {
+	// GetComponentTemplates returns all the component templates.
+	// Introduced in: Elasticsearch 7.8.0
+	GetComponentTemplates(ctx context.Context) (IndexTemplates, error)
+	// UpdateComponentTemplate creates a component template, or updates it if it already exists.
+	// Introduced in: Elasticsearch 7.8.0
+	UpdateComponentTemplate(ctx context.Context, name string, template map[string]interface{}) error
+	// DeleteComponentTemplate deletes a component template.
+	// Introduced in: Elasticsearch 7.8.0
+	DeleteComponentTemplate(ctx context.Context, name string) error
+	// GetIndexTemplates returns all the composable index templates.
+	// Introduced in: Elasticsearch 7.8.0
+	GetIndexTemplates(ctx context.Context) (IndexTemplates, error)
+	// UpdateIndexTemplate creates a composable index template, or updates it if it already exists.
+	// Introduced in: Elasticsearch 7.8.0
+	UpdateIndexTemplate(ctx context.Context, name string, template map[string]interface{}) error
+	// DeleteIndexTemplate deletes a composable index template.
+	// Introduced in: Elasticsearch 7.8.0
+	DeleteIndexTemplate(ctx context.Context, name string) error
+	// GetLegacyIndexTemplates returns all the legacy index templates.
+	GetLegacyIndexTemplates(ctx context.Context) (IndexTemplates, error)
+	// UpdateLegacyIndexTemplate creates a legacy index template, or updates it if it already exists.
+	UpdateLegacyIndexTemplate(ctx context.Context, name string, template map[string]interface{}) error
+	// DeleteLegacyIndexTemplate deletes a legacy index template.
+	DeleteLegacyIndexTemplate(ctx context.Context, name string) error
+}
+
+func (c *baseClient) GetComponentTemplates(ctx context.Context) (IndexTemplates, error) {
+	var response componentTemplatesResponse
+	if err := c.get(ctx, "/_component_template", &response); err != nil {
+		if IsNotFound(err) {
+			// returned when there is no component template
+			return IndexTemplates{}, nil
+		}
+		return nil, err
+	}
+	templates := make(IndexTemplates, len(response.ComponentTemplates))
+	for _, template := range response.ComponentTemplates {
+		templates[template.Name] = template.ComponentTemplate
+	}
+	return templates, nil
+}
+
+func (c *baseClient) UpdateComponentTemplate(ctx context.Context, name string, template map[string]interface{}) error {
+	return c.put(ctx, "/_component_template/"+url.PathEscape(name), template, nil)
+}
+
+func (c *baseClient) DeleteComponentTemplate(ctx context.Context, name string) error {
+	return c.delete(ctx, "/_component_template/"+url.PathEscape(name))
+}
+
+func (c *baseClient) GetIndexTemplates(ctx context.Context) (IndexTemplates, error) {
+	var response indexTemplatesResponse
+	if err := c.get(ctx, "/_index_template", &response); err != nil {
+		if IsNotFound(err) {
+			// returned when there is no index template
+			return IndexTemplates{}, nil
+		}
+		return nil, err
+	}
+	templates := make(IndexTemplates, len(response.IndexTemplates))
+	for _, template := range response.IndexTemplates {
+		templates[template.Name] = template.IndexTemplate
+	}
+	return templates, nil
+}
+
+func (c *baseClient) UpdateIndexTemplate(ctx context.Context, name string, template map[string]interface{}) error {
+	return c.put(ctx, "/_index_template/"+url.PathEscape(name), template, nil)
+}
+
+func (c *baseClient) DeleteIndexTemplate(ctx context.Context, name string) error {
+	return c.delete(ctx, "/_index_template/"+url.PathEscape(name))
+}
+
+func (c *baseClient) GetLegacyIndexTemplates(ctx context.Context) (IndexTemplates, error) {
+	templates := IndexTemplates{}
+	err := c.get(ctx, "/_template", &templates)
+	if IsNotFound(err) {
+		// returned by some versions of Elasticsearch when there is no index template
+		return IndexTemplates{}, nil
+	}
+	return templates, err
+}
+
+func (c *baseClient) UpdateLegacyIndexTemplate(ctx context.Context, name string, template map[string]interface{}) error {
+	return c.put(ctx, "/_template/"+url.PathEscape(name), template, nil)
+}
+
+func (c *baseClient) DeleteLegacyIndexTemplate(ctx context.Context, name string) error {
+	return c.delete(ctx, "/_template/"+url.PathEscape(name))
+}
@@ -132,6 +132,16 @@ func (c *clientV6) GetRemoteClusterSettings(ctx context.Context) (RemoteClusters
 	return remoteClustersSettings, err
 }
 
+func (c *clientV6) UpdateClusterSettings(ctx context.Context, settings ClusterSettings) error {
+	return c.put(ctx, "/_cluster/settings", &settings, nil)
+}
+
+func (c *clientV6) GetClusterSettings(ctx context.Context) (ClusterSettings, error) {
+	clusterSettings := ClusterSettings{}
+	err := c.get(ctx, "/_cluster/settings?flat_settings=true", &clusterSettings)
+	return clusterSettings, err
+}
+
 func (c *clientV6) GetLicense(ctx context.Context) (License, error) {
 	var license LicenseResponse
 	err := c.get(ctx, "/_xpack/license", &license)
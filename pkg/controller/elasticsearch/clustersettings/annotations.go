@@ -0,0 +1,65 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package clustersettings
+
+import (
+	"context"
+	"sort"
+	"strings"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	esv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/elasticsearch/v1"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/utils/k8s"
+)
+
+const (
+	// ManagedClusterSettingsAnnotationName holds the list of the dynamic cluster settings applied by the operator
+	// through the Elasticsearch API.
+	ManagedClusterSettingsAnnotationName = "elasticsearch.k8s.elastic.co/managed-cluster-settings"
+)
+
+// getSettingsInAnnotation returns the set of the cluster settings which may have been applied by the operator.
+// If there are no such settings the map is empty but not nil.
+func getSettingsInAnnotation(es esv1.Elasticsearch) map[string]struct{} {
+	settings := make(map[string]struct{})
+	serializedSettings, ok := es.Annotations[ManagedClusterSettingsAnnotationName]
+	if !ok || strings.TrimSpace(serializedSettings) == "" {
+		return settings
+	}
+	for _, setting := range strings.Split(serializedSettings, ",") {
+		settings[setting] = struct{}{}
+	}
+	return settings
+}
+
+// annotateWithManagedSettings stores the given settings in an annotation of the Elasticsearch resource. A merge patch is
+// used to not conflict with other updates of the resource during the same reconciliation.
+func annotateWithManagedSettings(ctx context.Context, c k8s.Client, es esv1.Elasticsearch, settings map[string]struct{}) error {
+	current, exists := es.Annotations[ManagedClusterSettingsAnnotationName]
+	keys := make([]string, 0, len(settings))
+	for setting := range settings {
+		keys = append(keys, setting)
+	}
+	sort.Strings(keys)
+	expected := strings.Join(keys, ",")
+	if (len(keys) == 0 && !exists) || (exists && current == expected) {
+		return nil
+	}
+
+	patch := client.MergeFrom(es.DeepCopy())
+	// copy the annotations to not mutate the ones of the caller
+	annotations := make(map[string]string, len(es.Annotations)+1)
+	for k, v := range es.Annotations {
+		annotations[k] = v
+	}
+	es.Annotations = annotations
+	if len(keys) == 0 {
+		delete(es.Annotations, ManagedClusterSettingsAnnotationName)
+	} else {
+		es.Annotations[ManagedClusterSettingsAnnotationName] = expected
+	}
+	return c.Patch(ctx, &es, patch)
+}
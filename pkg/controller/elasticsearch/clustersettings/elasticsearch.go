@@ -0,0 +1,122 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package clustersettings
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"go.elastic.co/apm/v2"
+
+	esv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/elasticsearch/v1"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/tracing"
+	esclient "github.com/elastic/cloud-on-k8s/v2/pkg/controller/elasticsearch/client"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/elasticsearch/settings"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/utils/k8s"
+	ulog "github.com/elastic/cloud-on-k8s/v2/pkg/utils/log"
+)
+
+// UpdateSettings applies the dynamic cluster settings of the Elasticsearch spec to the persistent cluster settings by
+// calling the Elasticsearch API. Those settings are excluded from the Pod template hash: changing them does not restart
+// the nodes. A boolean is returned to indicate if a requeue should be scheduled to sync the annotation on the
+// Elasticsearch object when the settings not expected anymore are actually reset in Elasticsearch.
+// See the documentation of updateSettingsInternal for more information about the algorithm.
+func UpdateSettings(ctx context.Context, c k8s.Client, esClient esclient.Client, es esv1.Elasticsearch) (bool, error) {
+	settingsInSpec, err := settings.DynamicClusterSettings(es.Spec.NodeSets)
+	if err != nil {
+		return false, err
+	}
+	_, isSettingsAnnotation := es.Annotations[ManagedClusterSettingsAnnotationName]
+	if len(settingsInSpec) == 0 && !isSettingsAnnotation {
+		// nothing to do, skip
+		return false, nil
+	}
+
+	span, _ := apm.StartSpan(ctx, "update_cluster_settings", tracing.SpanTypeApp)
+	defer span.End()
+
+	return updateSettingsInternal(ctx, settingsInSpec, c, esClient, es)
+}
+
+// updateSettingsInternal applies the dynamic cluster settings in Elasticsearch. It keeps track of the settings applied by
+// the operator in an annotation, in order to reset the ones removed from the spec without resetting the ones set by the
+// user through the API. The following algorithm is used:
+//  1. Get the list of the previously applied settings from the annotation
+//  2. For each setting in the annotation which is not in the spec, either:
+//     2.1 Schedule its reset if it is still set in Elasticsearch
+//     2.2 Otherwise remove it from the annotation
+//  3. Ensure that all the settings in the spec are present in the annotation, and schedule the update of the ones
+//     whose value differs in Elasticsearch
+//  4. Update the annotation on the Elasticsearch object
+//  5. Apply the settings through the Elasticsearch API
+func updateSettingsInternal(
+	ctx context.Context,
+	settingsInSpec map[string]interface{},
+	c k8s.Client,
+	esClient esclient.Client,
+	es esv1.Elasticsearch,
+) (requeue bool, err error) {
+	settingsInAnnotation := getSettingsInAnnotation(es)
+
+	// Retrieve the settings currently set in Elasticsearch
+	clusterSettings, err := esClient.GetClusterSettings(ctx)
+	if err != nil {
+		return true, err
+	}
+	settingsInEs := clusterSettings.PersistentSettings
+
+	settingsToApply := make(map[string]interface{})
+	var settingsToReset []string
+	for setting := range settingsInAnnotation {
+		if _, inSpec := settingsInSpec[setting]; inSpec {
+			continue
+		}
+		if _, inElasticsearch := settingsInEs[setting]; inElasticsearch {
+			settingsToReset = append(settingsToReset, setting)
+			// a nil value resets the setting to its default
+			settingsToApply[setting] = nil
+		} else {
+			delete(settingsInAnnotation, setting)
+		}
+	}
+
+	settingsToUpdate := make([]string, 0, len(settingsInSpec)) // only used for logging
+	for setting, value := range settingsInSpec {
+		settingsInAnnotation[setting] = struct{}{}
+		if current, inElasticsearch := settingsInEs[setting]; inElasticsearch && sameValue(current, value) {
+			continue
+		}
+		settingsToUpdate = append(settingsToUpdate, setting)
+		settingsToApply[setting] = value
+	}
+
+	// Update the annotation first to not lose track of the settings applied in Elasticsearch
+	if err := annotateWithManagedSettings(ctx, c, es, settingsInAnnotation); err != nil {
+		return true, err
+	}
+
+	// Since the annotation is updated before Elasticsearch we should requeue to sync the annotation
+	// if some settings are reset in Elasticsearch.
+	requeue = len(settingsToReset) > 0
+	if len(settingsToApply) == 0 {
+		return requeue, nil
+	}
+	sort.Strings(settingsToUpdate)
+	sort.Strings(settingsToReset)
+	ulog.FromContext(ctx).Info("Updating dynamic cluster settings",
+		"namespace", es.Namespace,
+		"es_name", es.Name,
+		"updated_settings", settingsToUpdate,
+		"reset_settings", settingsToReset,
+	)
+	return requeue, esClient.UpdateClusterSettings(ctx, esclient.ClusterSettings{PersistentSettings: settingsToApply})
+}
+
+// sameValue compares a setting value returned by Elasticsearch, where scalars are strings, with a value from the
+// Elasticsearch spec.
+func sameValue(actual, expected interface{}) bool {
+	return fmt.Sprint(actual) == fmt.Sprint(expected)
+}
@@ -0,0 +1,144 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package clustersettings
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	commonv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/common/v1"
+	esv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/elasticsearch/v1"
+	esclient "github.com/elastic/cloud-on-k8s/v2/pkg/controller/elasticsearch/client"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/utils/k8s"
+)
+
+type fakeESClient struct {
+	esclient.Client
+	existingSettings              esclient.ClusterSettings
+	updatedSettings               *esclient.ClusterSettings
+	getClusterSettingsCalled      bool
+	updateClusterSettingsRequests int
+}
+
+func (f *fakeESClient) GetClusterSettings(_ context.Context) (esclient.ClusterSettings, error) {
+	f.getClusterSettingsCalled = true
+	return f.existingSettings, nil
+}
+
+func (f *fakeESClient) UpdateClusterSettings(_ context.Context, settings esclient.ClusterSettings) error {
+	f.updatedSettings = &settings
+	f.updateClusterSettingsRequests++
+	return nil
+}
+
+func newEsWithConfig(annotations map[string]string, config map[string]interface{}) esv1.Elasticsearch {
+	return esv1.Elasticsearch{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "es", Annotations: annotations},
+		Spec: esv1.ElasticsearchSpec{
+			NodeSets: []esv1.NodeSet{{Name: "default", Config: &commonv1.Config{Data: config}}},
+		},
+	}
+}
+
+func TestUpdateSettings(t *testing.T) {
+	tests := []struct {
+		name                  string
+		es                    esv1.Elasticsearch
+		existingSettings      map[string]interface{}
+		wantGetSettings       bool
+		wantUpdatedSettings   *esclient.ClusterSettings
+		wantAnnotation        string
+		wantAnnotationDeleted bool
+		wantRequeue           bool
+	}{
+		{
+			name:             "no dynamic settings: nothing to do",
+			es:               newEsWithConfig(nil, map[string]interface{}{"node.roles": []string{"master"}}),
+			wantGetSettings:  false,
+			existingSettings: nil,
+		},
+		{
+			name:            "apply new dynamic settings",
+			es:              newEsWithConfig(nil, map[string]interface{}{"node.roles": []string{"master"}, "cluster.routing.allocation.awareness.attributes": "zone"}),
+			wantGetSettings: true,
+			wantUpdatedSettings: &esclient.ClusterSettings{PersistentSettings: map[string]interface{}{
+				"cluster.routing.allocation.awareness.attributes": "zone",
+			}},
+			wantAnnotation: "cluster.routing.allocation.awareness.attributes",
+		},
+		{
+			name: "settings already applied",
+			es: newEsWithConfig(
+				map[string]string{ManagedClusterSettingsAnnotationName: "action.destructive_requires_name"},
+				map[string]interface{}{"action.destructive_requires_name": true},
+			),
+			existingSettings:    map[string]interface{}{"action.destructive_requires_name": "true"},
+			wantGetSettings:     true,
+			wantUpdatedSettings: nil,
+			wantAnnotation:      "action.destructive_requires_name",
+		},
+		{
+			name: "reset settings removed from the spec",
+			es: newEsWithConfig(
+				map[string]string{ManagedClusterSettingsAnnotationName: "action.destructive_requires_name,cluster.routing.allocation.awareness.attributes"},
+				map[string]interface{}{"action.destructive_requires_name": true},
+			),
+			existingSettings: map[string]interface{}{
+				"action.destructive_requires_name":                "true",
+				"cluster.routing.allocation.awareness.attributes": "zone",
+				"cluster.routing.rebalance.enable":                "none",
+			},
+			wantGetSettings: true,
+			wantUpdatedSettings: &esclient.ClusterSettings{PersistentSettings: map[string]interface{}{
+				"cluster.routing.allocation.awareness.attributes": nil,
+			}},
+			wantAnnotation: "action.destructive_requires_name,cluster.routing.allocation.awareness.attributes",
+			wantRequeue:    true,
+		},
+		{
+			name: "stop tracking settings reset in Elasticsearch",
+			es: newEsWithConfig(
+				map[string]string{ManagedClusterSettingsAnnotationName: "cluster.routing.allocation.awareness.attributes"},
+				nil,
+			),
+			existingSettings:      map[string]interface{}{"cluster.routing.rebalance.enable": "none"},
+			wantGetSettings:       true,
+			wantUpdatedSettings:   nil,
+			wantAnnotationDeleted: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			esClient := &fakeESClient{existingSettings: esclient.ClusterSettings{PersistentSettings: tt.existingSettings}}
+			c := k8s.NewFakeClient(&tt.es)
+			requeue, err := UpdateSettings(context.Background(), c, esClient, tt.es)
+			require.NoError(t, err)
+			require.Equal(t, tt.wantRequeue, requeue)
+			require.Equal(t, tt.wantGetSettings, esClient.getClusterSettingsCalled)
+			require.Equal(t, tt.wantUpdatedSettings, esClient.updatedSettings)
+
+			var actual esv1.Elasticsearch
+			require.NoError(t, c.Get(context.Background(), k8s.ExtractNamespacedName(&tt.es), &actual))
+			annotation, exists := actual.Annotations[ManagedClusterSettingsAnnotationName]
+			if tt.wantAnnotationDeleted {
+				require.False(t, exists)
+				return
+			}
+			require.Equal(t, tt.wantAnnotation, annotation)
+		})
+	}
+}
+
+func Test_sameValue(t *testing.T) {
+	require.True(t, sameValue("true", true))
+	require.True(t, sameValue("100", uint64(100)))
+	require.True(t, sameValue("0.5", 0.5))
+	require.True(t, sameValue([]interface{}{"a", "b"}, []interface{}{"a", "b"}))
+	require.False(t, sameValue("primaries", "all"))
+	require.False(t, sameValue(nil, "all"))
+}
@@ -0,0 +1,155 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package driver
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"go.elastic.co/apm/v2"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	esv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/elasticsearch/v1"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/reconciler"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/tracing"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/elasticsearch/label"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/elasticsearch/sset"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/utils/k8s"
+	ulog "github.com/elastic/cloud-on-k8s/v2/pkg/utils/log"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/utils/stringsutil"
+)
+
+const (
+	// clusterAutoscalerSafeToEvictAnnotation prevents the cluster autoscaler from evicting a Pod when set to "false".
+	clusterAutoscalerSafeToEvictAnnotation = "cluster-autoscaler.kubernetes.io/safe-to-evict"
+	// clusterAutoscalerToBeDeletedTaint is set by the cluster autoscaler on the nodes it is draining before their removal.
+	// The soft DeletionCandidateOfClusterAutoscaler taint of the nodes it only considers for removal is ignored, as most
+	// of them are not removed in the end.
+	clusterAutoscalerToBeDeletedTaint = "ToBeDeletedByClusterAutoscaler"
+)
+
+// expectedSafeToEvict returns the expected value of the cluster autoscaler safe-to-evict annotation of the given Pod,
+// and whether it should override a value already set on the Pod, for example through the Pod template.
+// An empty value means the Pod does not need to be annotated.
+func expectedSafeToEvict(es esv1.Elasticsearch, pod corev1.Pod) (string, bool) {
+	if value, exists := es.Annotations[esv1.SafeToEvictAnnotation]; exists && (value == "true" || value == "false") {
+		return value, true
+	}
+	// nodes holding cluster state or data should not be evicted without the operator knowing
+	if label.IsMasterNode(pod) || label.IsDataNode(pod) {
+		return "false", false
+	}
+	return "", false
+}
+
+// annotatePodsForClusterAutoscaler sets the cluster autoscaler safe-to-evict annotation on the Pods. The Pods are
+// patched in place to not restart them.
+func annotatePodsForClusterAutoscaler(ctx context.Context, c k8s.Client, es esv1.Elasticsearch) *reconciler.Results {
+	span, ctx := apm.StartSpan(ctx, "annotate_pods_for_cluster_autoscaler", tracing.SpanTypeApp)
+	defer span.End()
+	results := reconciler.NewResult(ctx)
+	actualPods, err := sset.GetActualPodsForCluster(c, es)
+	if err != nil {
+		return results.WithError(err)
+	}
+	for _, pod := range actualPods {
+		results.WithError(annotatePodForClusterAutoscaler(ctx, c, es, pod))
+	}
+	return results
+}
+
+func annotatePodForClusterAutoscaler(ctx context.Context, c k8s.Client, es esv1.Elasticsearch, pod corev1.Pod) error {
+	expected, override := expectedSafeToEvict(es, pod)
+	if expected == "" {
+		return nil
+	}
+	if actual, exists := pod.Annotations[clusterAutoscalerSafeToEvictAnnotation]; exists && (actual == expected || !override) {
+		return nil
+	}
+	ulog.FromContext(ctx).V(1).Info("Setting cluster autoscaler Pod annotation", "namespace", es.Namespace, "es_name", es.Name, "pod", pod.Name, "safe_to_evict", expected)
+	mergePatch, err := json.Marshal(map[string]interface{}{
+		"metadata": map[string]interface{}{
+			"annotations": map[string]string{clusterAutoscalerSafeToEvictAnnotation: expected},
+		},
+	})
+	if err != nil {
+		return err
+	}
+	if err := c.Patch(ctx, &pod, client.RawPatch(types.StrategicMergePatchType, mergePatch)); err != nil && !errors.IsNotFound(err) {
+		return err
+	}
+	return nil
+}
+
+// clusterAutoscalerLeavingNodes returns the names of the Pods scheduled on Kubernetes nodes the cluster autoscaler is
+// about to remove, in order to migrate data away from them before they are evicted. Kubernetes nodes are only inspected
+// if the Pods are safe to evict, the cluster autoscaler does not remove the nodes running Pods which are not.
+func clusterAutoscalerLeavingNodes(ctx context.Context, c k8s.Client, es esv1.Elasticsearch, pods []corev1.Pod) ([]string, error) {
+	if es.Annotations[esv1.SafeToEvictAnnotation] != "true" {
+		return nil, nil
+	}
+	var leaving []string
+	nodes := map[string]bool{}
+	for i := range pods {
+		scheduled, nodeName := isPodScheduled(&pods[i])
+		// only nodes which joined the cluster can be shut down
+		if !scheduled || !k8s.IsPodReady(pods[i]) {
+			continue
+		}
+		removed, checked := nodes[nodeName]
+		if !checked {
+			node := &corev1.Node{}
+			if err := c.Get(ctx, types.NamespacedName{Name: nodeName}, node); err != nil {
+				if errors.IsNotFound(err) {
+					continue
+				}
+				// the operator may not be allowed to read Kubernetes nodes: consider the node is not being removed
+				if errors.IsForbidden(err) {
+					ulog.FromContext(ctx).V(1).Info("Not allowed to get Kubernetes node, skipping cluster autoscaler check",
+						"namespace", es.Namespace, "es_name", es.Name, "node", nodeName)
+					nodes[nodeName] = false
+					continue
+				}
+				return nil, fmt.Errorf("while getting node %s: %w", nodeName, err)
+			}
+			removed = isRemovedByClusterAutoscaler(*node)
+			nodes[nodeName] = removed
+		}
+		if removed {
+			leaving = append(leaving, pods[i].Name)
+		}
+	}
+	return leaving, nil
+}
+
+// withClusterAutoscalerLeavingNodes returns the nodes leaving because of a downscale, followed by the nodes leaving
+// because of the cluster autoscaler.
+func withClusterAutoscalerLeavingNodes(leavingNodes, autoscalerLeavingNodes []string) []string {
+	if len(autoscalerLeavingNodes) == 0 {
+		return leavingNodes
+	}
+	leaving := stringsutil.SliceToMap(leavingNodes)
+	result := make([]string, 0, len(leavingNodes)+len(autoscalerLeavingNodes))
+	result = append(result, leavingNodes...)
+	for _, name := range autoscalerLeavingNodes {
+		if _, exists := leaving[name]; !exists {
+			result = append(result, name)
+		}
+	}
+	return result
+}
+
+func isRemovedByClusterAutoscaler(node corev1.Node) bool {
+	for _, taint := range node.Spec.Taints {
+		if taint.Key == clusterAutoscalerToBeDeletedTaint {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,166 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package driver
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	esv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/elasticsearch/v1"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/elasticsearch/label"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/utils/k8s"
+)
+
+func newAutoscalerTestPod(name, nodeName string, ready bool, labels, annotations map[string]string) *corev1.Pod {
+	pod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: name, Labels: labels, Annotations: annotations},
+		Spec:       corev1.PodSpec{NodeName: nodeName},
+		Status: corev1.PodStatus{
+			Conditions: []corev1.PodCondition{{Type: corev1.PodScheduled, Status: corev1.ConditionTrue}},
+		},
+	}
+	if ready {
+		pod.Status.Conditions = append(pod.Status.Conditions,
+			corev1.PodCondition{Type: corev1.PodReady, Status: corev1.ConditionTrue},
+			corev1.PodCondition{Type: corev1.ContainersReady, Status: corev1.ConditionTrue},
+		)
+	}
+	return pod
+}
+
+func Test_annotatePodForClusterAutoscaler(t *testing.T) {
+	masterLabels := map[string]string{string(label.NodeTypesMasterLabelName): "true"}
+	ingestLabels := map[string]string{string(label.NodeTypesIngestLabelName): "true"}
+	tests := []struct {
+		name            string
+		esAnnotations   map[string]string
+		pod             *corev1.Pod
+		wantSafeToEvict string
+	}{
+		{
+			name:            "master Pods are not safe to evict by default",
+			pod:             newAutoscalerTestPod("master", "node", true, masterLabels, nil),
+			wantSafeToEvict: "false",
+		},
+		{
+			name:            "coordinating Pods are not annotated by default",
+			pod:             newAutoscalerTestPod("ingest", "node", true, ingestLabels, nil),
+			wantSafeToEvict: "",
+		},
+		{
+			name:            "annotation set by the user in the Pod template is preserved by default",
+			pod:             newAutoscalerTestPod("master", "node", true, masterLabels, map[string]string{clusterAutoscalerSafeToEvictAnnotation: "true"}),
+			wantSafeToEvict: "true",
+		},
+		{
+			name:            "Elasticsearch annotation takes precedence",
+			esAnnotations:   map[string]string{esv1.SafeToEvictAnnotation: "true"},
+			pod:             newAutoscalerTestPod("master", "node", true, masterLabels, map[string]string{clusterAutoscalerSafeToEvictAnnotation: "false"}),
+			wantSafeToEvict: "true",
+		},
+		{
+			name:            "Elasticsearch annotation applies to all Pods",
+			esAnnotations:   map[string]string{esv1.SafeToEvictAnnotation: "false"},
+			pod:             newAutoscalerTestPod("ingest", "node", true, ingestLabels, nil),
+			wantSafeToEvict: "false",
+		},
+		{
+			name:            "invalid Elasticsearch annotation is ignored",
+			esAnnotations:   map[string]string{esv1.SafeToEvictAnnotation: "maybe"},
+			pod:             newAutoscalerTestPod("master", "node", true, masterLabels, nil),
+			wantSafeToEvict: "false",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			es := esv1.Elasticsearch{ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "es", Annotations: tt.esAnnotations}}
+			c := k8s.NewFakeClient(tt.pod)
+			require.NoError(t, annotatePodForClusterAutoscaler(context.Background(), c, es, *tt.pod))
+
+			var actual corev1.Pod
+			require.NoError(t, c.Get(context.Background(), k8s.ExtractNamespacedName(tt.pod), &actual))
+			require.Equal(t, tt.wantSafeToEvict, actual.Annotations[clusterAutoscalerSafeToEvictAnnotation])
+		})
+	}
+}
+
+func Test_clusterAutoscalerLeavingNodes(t *testing.T) {
+	taintedNode := &corev1.Node{
+		ObjectMeta: metav1.ObjectMeta{Name: "tainted"},
+		Spec:       corev1.NodeSpec{Taints: []corev1.Taint{{Key: clusterAutoscalerToBeDeletedTaint, Effect: corev1.TaintEffectNoSchedule}}},
+	}
+	candidateNode := &corev1.Node{
+		ObjectMeta: metav1.ObjectMeta{Name: "candidate"},
+		Spec:       corev1.NodeSpec{Taints: []corev1.Taint{{Key: "DeletionCandidateOfClusterAutoscaler", Effect: corev1.TaintEffectPreferNoSchedule}}},
+	}
+	healthyNode := &corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: "healthy"}}
+	pods := []corev1.Pod{
+		*newAutoscalerTestPod("pod-0", "tainted", true, nil, nil),
+		*newAutoscalerTestPod("pod-1", "candidate", true, nil, nil),
+		*newAutoscalerTestPod("pod-2", "healthy", true, nil, nil),
+		*newAutoscalerTestPod("pod-3", "tainted", false, nil, nil),
+		*newAutoscalerTestPod("pod-4", "unknown", true, nil, nil),
+		*newAutoscalerTestPod("pod-5", "", false, nil, nil),
+	}
+	tests := []struct {
+		name          string
+		esAnnotations map[string]string
+		want          []string
+	}{
+		{
+			name: "Pods are not safe to evict: nodes are not inspected",
+			want: nil,
+		},
+		{
+			name:          "Pods are explicitly not safe to evict: nodes are not inspected",
+			esAnnotations: map[string]string{esv1.SafeToEvictAnnotation: "false"},
+			want:          nil,
+		},
+		{
+			name:          "Pods are safe to evict: ready Pods on nodes to be deleted are leaving",
+			esAnnotations: map[string]string{esv1.SafeToEvictAnnotation: "true"},
+			want:          []string{"pod-0"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			es := esv1.Elasticsearch{ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "es", Annotations: tt.esAnnotations}}
+			c := k8s.NewFakeClient([]client.Object{taintedNode, candidateNode, healthyNode}...)
+			got, err := clusterAutoscalerLeavingNodes(context.Background(), c, es, pods)
+			require.NoError(t, err)
+			require.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func Test_clusterAutoscalerLeavingNodes_forbidden(t *testing.T) {
+	es := esv1.Elasticsearch{ObjectMeta: metav1.ObjectMeta{
+		Namespace: "ns", Name: "es", Annotations: map[string]string{esv1.SafeToEvictAnnotation: "true"},
+	}}
+	pods := []corev1.Pod{*newAutoscalerTestPod("pod-0", "node", true, nil, nil)}
+
+	forbidden := apierrors.NewForbidden(schema.GroupResource{Resource: "nodes"}, "node", errors.New("RBAC"))
+	got, err := clusterAutoscalerLeavingNodes(context.Background(), k8s.NewFailingClient(forbidden), es, pods)
+	require.NoError(t, err)
+	require.Empty(t, got)
+
+	_, err = clusterAutoscalerLeavingNodes(context.Background(), k8s.NewFailingClient(errors.New("boom")), es, pods)
+	require.Error(t, err)
+}
+
+func Test_withClusterAutoscalerLeavingNodes(t *testing.T) {
+	require.Equal(t, []string{"a"}, withClusterAutoscalerLeavingNodes([]string{"a"}, nil))
+	require.Equal(t, []string{"a"}, withClusterAutoscalerLeavingNodes([]string{"a"}, []string{"a"}))
+	require.Equal(t, []string{"b"}, withClusterAutoscalerLeavingNodes(nil, []string{"b"}))
+	require.Equal(t, []string{"b", "a", "c"}, withClusterAutoscalerLeavingNodes([]string{"b", "a"}, []string{"c", "a"}))
+}
@@ -15,6 +15,7 @@ import (
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 
 	esv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/elasticsearch/v1"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/decisions"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/events"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/reconciler"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/elasticsearch/certificates/transport"
@@ -53,6 +54,13 @@ func HandleDownscale(
 
 	// Compute the desired downscale, applying a budget filter to make sure we only downscale nodes we're allowed to.
 	downscaleState := newDownscaleState(actualPods, downscaleCtx.es)
+	if len(desiredLeavingNodes) > 0 {
+		dataTiers, err := ilmDataTiers(downscaleCtx)
+		if err != nil {
+			return results.WithError(err)
+		}
+		downscaleState.trackDataTiers(actualPods, dataTiers)
+	}
 
 	// compute the list of StatefulSet downscales and deletions to perform
 	downscales, deletions := calculateDownscales(downscaleCtx.parentCtx, *downscaleState, expectedStatefulSets, actualStatefulSets, downscaleBudgetFilter)
@@ -66,7 +74,12 @@ func HandleDownscale(
 	// initiate shutdown of nodes that should be removed
 	// if leaving nodes is empty this should cancel any ongoing shutdowns
 	leavingNodes := leavingNodeNames(downscales)
-	if err := downscaleCtx.nodeShutdown.ReconcileShutdowns(downscaleCtx.parentCtx, leavingNodes); err != nil {
+	// also migrate data away from the nodes running on Kubernetes nodes the cluster autoscaler is about to remove
+	autoscalerLeavingNodes, err := clusterAutoscalerLeavingNodes(downscaleCtx.parentCtx, downscaleCtx.k8sClient, downscaleCtx.es, actualPods)
+	if err != nil {
+		return results.WithError(err)
+	}
+	if err := downscaleCtx.nodeShutdown.ReconcileShutdowns(downscaleCtx.parentCtx, withClusterAutoscalerLeavingNodes(leavingNodes, autoscalerLeavingNodes)); err != nil {
 		return results.WithError(err)
 	}
 
@@ -90,6 +103,28 @@ func HandleDownscale(
 	return results
 }
 
+// ilmDataTiers returns the data tiers ILM policies move indices to, if the cluster is organized in data tiers.
+func ilmDataTiers(downscaleCtx downscaleContext) ([]esv1.DataTier, error) {
+	usesDataTiers := false
+	for _, nodeSet := range downscaleCtx.es.Spec.NodeSets {
+		usesDataTiers = usesDataTiers || nodeSet.DataTier != ""
+	}
+	if !usesDataTiers {
+		return nil, nil
+	}
+	policies, err := downscaleCtx.esClient.GetILMPolicies(downscaleCtx.parentCtx)
+	if err != nil {
+		return nil, err
+	}
+	var dataTiers []esv1.DataTier
+	for _, name := range policies.DataTiers(esv1.DataTierAttr) {
+		if esv1.IsDataTier(name) {
+			dataTiers = append(dataTiers, esv1.DataTier(name))
+		}
+	}
+	return dataTiers, nil
+}
+
 func podsToDownscale(
 	ctx context.Context,
 	actualPods []corev1.Pod,
@@ -167,6 +202,7 @@ func downscaleBudgetFilter(ctx context.Context, state *downscaleState, actualSse
 	allowedDeletes, reason := checkDownscaleInvariants(*state, actualSset, requestedDeletes)
 	if allowedDeletes == 0 {
 		ssetLogger(ctx, actualSset).V(1).Info("Cannot downscale StatefulSet", "reason", reason)
+		decisions.Fail(ctx, "downscale_invariants", fmt.Sprintf("Cannot downscale StatefulSet %s: %s", actualSset.Name, reason))
 		return 0
 	}
 	state.recordNodeRemoval(actualSset, allowedDeletes)
@@ -17,6 +17,7 @@ const (
 	OneMasterAtATimeInvariant        = "A master node is already in the process of being removed"
 	AtLeastOneRunningMasterInvariant = "Cannot remove the last running master node"
 	RespectMaxUnavailableInvariant   = "Not removing node to respect maxUnavailable setting"
+	AtLeastOneDataTierNodeInvariant  = "Cannot remove the last running node of a data tier used by an ILM policy"
 )
 
 // checkDownscaleInvariants returns the number of nodes that can be removed if the given state state allows downscaling
@@ -31,6 +32,18 @@ func checkDownscaleInvariants(state downscaleState, statefulSet appsv1.StatefulS
 		}
 		requestedDeletes = 1 // only one removal allowed for masters
 	}
+	for dataTier, runningNodes := range state.runningDataTierNodes {
+		if !label.HasDataTier(dataTier, statefulSet.Spec.Template.Labels) {
+			continue
+		}
+		// ILM must still be able to move indices to this data tier
+		if runningNodes <= 1 {
+			return 0, AtLeastOneDataTierNodeInvariant
+		}
+		if requestedDeletes > int32(runningNodes-1) {
+			requestedDeletes = int32(runningNodes - 1)
+		}
+	}
 	allowedDeletes := state.getMaxNodesToRemove(requestedDeletes)
 
 	if allowedDeletes == 0 {
@@ -49,6 +62,9 @@ type downscaleState struct {
 	removalsAllowed *int32
 	// masterRemovalInProgress indicates whether a master node is in the process of being removed already.
 	masterRemovalInProgress bool
+	// runningDataTierNodes indicates how many nodes are currently running in the data tiers used by ILM policies.
+	// Data tiers not used by any ILM policy are not tracked.
+	runningDataTierNodes map[esv1.DataTier]int
 }
 
 // newDownscaleState creates a new downscaleState.
@@ -67,6 +83,24 @@ func newDownscaleState(actualPods []corev1.Pod, es esv1.Elasticsearch) *downscal
 	}
 }
 
+// trackDataTiers records the number of running nodes in the given data tiers, to prevent removing all the nodes of a
+// data tier ILM policies move indices to.
+func (s *downscaleState) trackDataTiers(actualPods []corev1.Pod, dataTiers []esv1.DataTier) {
+	if len(dataTiers) == 0 {
+		return
+	}
+	nodesReady := reconcile.AvailableElasticsearchNodes(actualPods)
+	s.runningDataTierNodes = make(map[esv1.DataTier]int, len(dataTiers))
+	for _, dataTier := range dataTiers {
+		s.runningDataTierNodes[dataTier] = 0
+		for _, pod := range nodesReady {
+			if label.HasDataTier(dataTier, pod.Labels) {
+				s.runningDataTierNodes[dataTier]++
+			}
+		}
+	}
+}
+
 func calculateRemovalsAllowed(nodesReady, desiredNodes int32, maxUnavailable *int32) *int32 {
 	if maxUnavailable == nil {
 		return nil
@@ -103,6 +137,12 @@ func (s *downscaleState) recordNodeRemoval(statefulSet appsv1.StatefulSet, accou
 		s.runningMasters--
 	}
 
+	for dataTier := range s.runningDataTierNodes {
+		if label.HasDataTier(dataTier, statefulSet.Spec.Template.Labels) {
+			s.runningDataTierNodes[dataTier] -= int(accountedRemovals)
+		}
+	}
+
 	if s.removalsAllowed != nil {
 		*s.removalsAllowed -= accountedRemovals
 	}
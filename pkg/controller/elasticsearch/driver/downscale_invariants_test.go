@@ -221,6 +221,19 @@ func Test_checkDownscaleInvariants(t *testing.T) {
 			wantCanDownscale: false,
 			wantReason:       RespectMaxUnavailableInvariant,
 		},
+		{
+			name:             "should allow removing a data node if another one of the data tiers used by ILM is running",
+			state:            &downscaleState{runningMasters: 1, removalsAllowed: pointer.Int32(1), runningDataTierNodes: map[esv1.DataTier]int{esv1.DataTierWarm: 2}},
+			statefulSet:      ssetData4Replicas,
+			wantCanDownscale: true,
+		},
+		{
+			name:             "should not allow removing the last data node of a data tier used by ILM",
+			state:            &downscaleState{runningMasters: 1, removalsAllowed: pointer.Int32(1), runningDataTierNodes: map[esv1.DataTier]int{esv1.DataTierWarm: 1}},
+			statefulSet:      ssetData4Replicas,
+			wantCanDownscale: false,
+			wantReason:       AtLeastOneDataTierNodeInvariant,
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
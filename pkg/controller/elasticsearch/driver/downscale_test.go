@@ -1101,7 +1101,7 @@ func Test_doDownscale_zen1MinimumMasterNodes(t *testing.T) {
 func Test_deleteStatefulSetResources(t *testing.T) {
 	es := esv1.Elasticsearch{ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "cluster"}}
 	sset := sset.TestSset{Namespace: "ns", Name: "sset", ClusterName: es.Name}.Build()
-	cfg := settings.ConfigSecret(es, sset.Name, []byte("fake config data"))
+	cfg := settings.ConfigSecret(es, sset.Name, []byte("fake config data"), nil)
 	svc := nodespec.HeadlessService(&es, sset.Name)
 
 	tests := []struct {
@@ -13,6 +13,7 @@ import (
 
 	"github.com/pkg/errors"
 	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/client-go/tools/record"
 	"k8s.io/utils/pointer"
 	controller "sigs.k8s.io/controller-runtime/pkg/reconcile"
@@ -23,6 +24,7 @@ import (
 	commondriver "github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/driver"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/events"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/expectations"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/expose"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/keystore"
 	commonlicense "github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/license"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/operator"
@@ -33,6 +35,7 @@ import (
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/elasticsearch/certificates"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/elasticsearch/cleanup"
 	esclient "github.com/elastic/cloud-on-k8s/v2/pkg/controller/elasticsearch/client"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/elasticsearch/clustersettings"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/elasticsearch/configmap"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/elasticsearch/hints"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/elasticsearch/initcontainer"
@@ -43,6 +46,8 @@ import (
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/elasticsearch/remotecluster"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/elasticsearch/services"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/elasticsearch/settings"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/elasticsearch/snapshot"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/elasticsearch/stackconfig"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/elasticsearch/stackmon"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/elasticsearch/user"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/dev"
@@ -165,6 +170,7 @@ func (d *defaultDriver) Reconcile(ctx context.Context) *reconciler.Results {
 		d.ES,
 		[]corev1.Service{*externalService, *internalService},
 		d.OperatorParameters.GlobalCA,
+		d.OperatorParameters.NamespaceCA,
 		d.OperatorParameters.CACertRotation,
 		d.OperatorParameters.CertRotation,
 	)
@@ -173,6 +179,18 @@ func (d *defaultDriver) Reconcile(ctx context.Context) *reconciler.Results {
 		return results
 	}
 
+	err = expose.Reconciler{
+		K8sClient: d.Client,
+		Owner:     &d.ES,
+		HTTP:      d.ES.Spec.HTTP,
+		Service:   *externalService,
+		Namer:     esv1.ESNamer,
+		Labels:    label.NewLabels(k8s.ExtractNamespacedName(&d.ES)),
+	}.Reconcile(ctx)
+	if err != nil {
+		return results.WithError(err)
+	}
+
 	// start the ES observer
 	min, err := version.MinInPods(resourcesState.CurrentPods, label.VersionLabelName)
 	if err != nil {
@@ -204,6 +222,7 @@ func (d *defaultDriver) Reconcile(ctx context.Context) *reconciler.Results {
 		d,
 		d.ES,
 		d.OperatorParameters.GlobalCA,
+		d.OperatorParameters.NamespaceCA,
 		d.OperatorParameters.CACertRotation,
 		d.OperatorParameters.CertRotation,
 	)
@@ -215,6 +234,8 @@ func (d *defaultDriver) Reconcile(ctx context.Context) *reconciler.Results {
 	// Patch the Pods to add the expected node labels as annotations. Record the error, if any, but do not stop the
 	// reconciliation loop as we don't want to prevent other updates from being applied to the cluster.
 	results.WithResults(annotatePodsWithNodeLabels(ctx, d.Client, d.ES))
+	// Patch the Pods to prevent the cluster autoscaler from evicting them without the operator knowing.
+	results.WithResults(annotatePodsForClusterAutoscaler(ctx, d.Client, d.ES))
 
 	if err := d.verifySupportsExistingPods(resourcesState.CurrentPods); err != nil {
 		if !d.ES.IsConfiguredToAllowDowngrades() {
@@ -278,12 +299,17 @@ func (d *defaultDriver) Reconcile(ctx context.Context) *reconciler.Results {
 
 	// reconcile the Elasticsearch license
 	if esReachable {
-		err = license.Reconcile(ctx, d.Client, d.ES, esClient, currentLicense)
+		reconciledLicense, err := license.Reconcile(ctx, d.Client, d.ES, esClient, currentLicense)
 		if err != nil {
 			msg := "Could not reconcile cluster license, re-queuing"
 			log.Info(msg, "err", err, "namespace", d.ES.Namespace, "es_name", d.ES.Name)
 			d.ReconcileState.AddEvent(corev1.EventTypeWarning, events.EventReasonUnexpected, fmt.Sprintf("%s: %s", msg, err.Error()))
 			results.WithReconciliationState(defaultRequeue.WithReason(msg))
+		} else {
+			d.ReconcileState.UpdateLicense(license.NewLicenseStatus(reconciledLicense))
+			if msg, expiring := license.ReportExpiry(k8s.ExtractNamespacedName(&d.ES), reconciledLicense, d.OperatorParameters.LicenseExpiryWarningPeriod, time.Now()); expiring {
+				d.ReconcileState.AddEvent(corev1.EventTypeWarning, events.EventReasonLicenseExpiring, msg)
+			}
 		}
 	}
 
@@ -301,6 +327,137 @@ func (d *defaultDriver) Reconcile(ctx context.Context) *reconciler.Results {
 		}
 	}
 
+	// apply the dynamic cluster settings through the API rather than by restarting the nodes
+	if esReachable {
+		requeue, err := clustersettings.UpdateSettings(ctx, d.Client, esClient, d.ES)
+		msg := "Could not update dynamic cluster settings, re-queuing"
+		if err != nil {
+			log.Info(msg, "err", err, "namespace", d.ES.Namespace, "es_name", d.ES.Name)
+			d.ReconcileState.AddEvent(corev1.EventTypeWarning, events.EventReasonUnexpected, fmt.Sprintf("%s: %s", msg, err.Error()))
+			results.WithError(err)
+		}
+		if requeue {
+			results.WithReconciliationState(defaultRequeue.WithReason("Updating dynamic cluster settings, re-queuing"))
+		}
+	}
+
+	// create the users of the native realm, and delete the ones removed from the spec
+	if esReachable {
+		if err := user.ReconcileNativeUsers(ctx, d.Client, esClient, d.ES, d.DynamicWatches(), d.Recorder()); err != nil {
+			msg := "Could not reconcile native users, re-queuing"
+			log.Info(msg, "err", err, "namespace", d.ES.Namespace, "es_name", d.ES.Name)
+			d.ReconcileState.AddEvent(corev1.EventTypeWarning, events.EventReasonUnexpected, fmt.Sprintf("%s: %s", msg, err.Error()))
+			results.WithReconciliationState(defaultRequeue.WithReason(msg))
+		}
+	}
+
+	// register the snapshot repositories, and unregister the ones removed from the spec
+	if esReachable {
+		if err := snapshot.ReconcileRepositories(ctx, d.Client, esClient, &d.ES, *min); err != nil {
+			msg := "Could not reconcile snapshot repositories, re-queuing"
+			log.Info(msg, "err", err, "namespace", d.ES.Namespace, "es_name", d.ES.Name)
+			d.ReconcileState.AddEvent(corev1.EventTypeWarning, events.EventReasonUnexpected, fmt.Sprintf("%s: %s", msg, err.Error()))
+			results.WithReconciliationState(defaultRequeue.WithReason(msg))
+		}
+	}
+
+	// create the resources declared in the spec, delete the ones removed from the spec, and check the declared cluster
+	// settings for changes made through the Elasticsearch API
+	if esReachable {
+		specResult, err := stackconfig.ReconcileSpec(ctx, d.Client, esClient, d.ES, d.DynamicWatches())
+		if err != nil {
+			msg := "Could not reconcile Elasticsearch resources declared in the spec, re-queuing"
+			log.Info(msg, "err", err, "namespace", d.ES.Namespace, "es_name", d.ES.Name)
+			d.ReconcileState.AddEvent(corev1.EventTypeWarning, events.EventReasonUnexpected, fmt.Sprintf("%s: %s", msg, err.Error()))
+			results.WithReconciliationState(defaultRequeue.WithReason(msg))
+		}
+		for _, conflict := range specResult.Conflicts {
+			msg := fmt.Sprintf("Elasticsearch resource %s already exists and is not managed by the operator, re-queuing", conflict)
+			log.Info(msg, "namespace", d.ES.Namespace, "es_name", d.ES.Name)
+			d.ReconcileState.AddEvent(corev1.EventTypeWarning, events.EventReasonConflict, msg)
+			results.WithReconciliationState(defaultRequeue.WithReason(msg))
+		}
+		if len(specResult.DriftedSettings) > 0 {
+			msg := fmt.Sprintf("Cluster settings changed through the Elasticsearch API: %s", strings.Join(specResult.DriftedSettings, ", "))
+			if d.ES.Spec.ClusterSettings.ConflictPolicyOrDefault() == esv1.RevertClusterSettingsConflictPolicy {
+				msg += ", reverted to their declared value"
+			}
+			log.Info(msg, "namespace", d.ES.Namespace, "es_name", d.ES.Name)
+			d.ReconcileState.AddEvent(corev1.EventTypeWarning, events.EventReasonDrift, msg)
+		}
+		if d.ES.Spec.ClusterSettings != nil {
+			results.WithReconciliationState(reconciler.RequeueAfter(stackconfig.DriftCheckInterval).ReconciliationComplete())
+		}
+		if err == nil {
+			reportILMDataTiers(d.ReconcileState, specResult.MissingDataTiers)
+		}
+	}
+
+	// verify the snapshot repositories periodically to report the broken ones
+	if esReachable {
+		now := time.Now()
+		verification, requeueAfter, err := snapshot.VerifyRepositories(ctx, esClient, d.ReconcileState.Recorder, d.ES, now)
+		if err != nil {
+			msg := "Could not verify snapshot repositories, re-queuing"
+			log.Info(msg, "err", err, "namespace", d.ES.Namespace, "es_name", d.ES.Name)
+			d.ReconcileState.AddEvent(corev1.EventTypeWarning, events.EventReasonUnexpected, fmt.Sprintf("%s: %s", msg, err.Error()))
+			results.WithReconciliationState(defaultRequeue.WithReason(msg))
+		}
+		if verification.Verified {
+			d.ReconcileState.UpdateSnapshotRepositoriesVerification(verification.Status, verification.Message, metav1.NewTime(now))
+		}
+		if requeueAfter > 0 {
+			results.WithReconciliationState(reconciler.RequeueAfter(requeueAfter).ReconciliationComplete())
+		}
+	}
+
+	// take the scheduled snapshots
+	if esReachable {
+		statuses, requeueAfter, err := snapshot.ReconcileSchedules(ctx, esClient, d.ReconcileState.Recorder, d.ES, *min, time.Now())
+		if err != nil {
+			msg := "Could not reconcile snapshot schedules, re-queuing"
+			log.Info(msg, "err", err, "namespace", d.ES.Namespace, "es_name", d.ES.Name)
+			d.ReconcileState.AddEvent(corev1.EventTypeWarning, events.EventReasonUnexpected, fmt.Sprintf("%s: %s", msg, err.Error()))
+			results.WithReconciliationState(defaultRequeue.WithReason(msg))
+		} else {
+			d.ReconcileState.UpdateSnapshotRepositories(statuses)
+		}
+		if requeueAfter > 0 {
+			results.WithReconciliationState(reconciler.RequeueAfter(requeueAfter).ReconciliationComplete())
+		}
+	}
+
+	// restore the initial snapshot once the cluster is formed, the cluster is not ready before the restore completes
+	if esReachable && d.ES.Spec.InitialRestore != nil {
+		status, err := snapshot.ReconcileInitialRestore(ctx, esClient, d.ReconcileState.Recorder, d.ES, time.Now())
+		if err != nil {
+			msg := "Could not restore initial snapshot, re-queuing"
+			log.Info(msg, "err", err, "namespace", d.ES.Namespace, "es_name", d.ES.Name)
+			d.ReconcileState.AddEvent(corev1.EventTypeWarning, events.EventReasonUnexpected, fmt.Sprintf("%s: %s", msg, err.Error()))
+		}
+		d.ReconcileState.UpdateInitialRestore(status)
+		if status.Phase != esv1.InitialRestoreCompleted {
+			results.WithReconciliationState(defaultRequeue.WithReason(
+				fmt.Sprintf("Restoring snapshot %s from repository %s", status.Snapshot, status.Repository),
+			))
+		}
+	}
+
+	// periodically verify that the snapshots can be restored, in a temporary cluster
+	if esReachable {
+		status, requeueAfter, err := snapshot.ReconcileRestoreVerification(ctx, d.Client, esClient, d.ReconcileState.Recorder, d.ES, time.Now())
+		if err != nil {
+			msg := "Could not reconcile snapshot restore verification, re-queuing"
+			log.Info(msg, "err", err, "namespace", d.ES.Namespace, "es_name", d.ES.Name)
+			d.ReconcileState.AddEvent(corev1.EventTypeWarning, events.EventReasonUnexpected, fmt.Sprintf("%s: %s", msg, err.Error()))
+			results.WithReconciliationState(defaultRequeue.WithReason(msg))
+		}
+		d.ReconcileState.UpdateRestoreVerification(status)
+		if requeueAfter > 0 {
+			results.WithReconciliationState(reconciler.RequeueAfter(requeueAfter).ReconciliationComplete())
+		}
+	}
+
 	// Compute seed hosts based on current masters with a podIP
 	if err := settings.UpdateSeedHostsConfigMap(ctx, d.Client, d.ES, resourcesState.AllPods); err != nil {
 		return results.WithError(err)
@@ -319,6 +476,20 @@ func (d *defaultDriver) Reconcile(ctx context.Context) *reconciler.Results {
 		return results.WithError(err)
 	}
 
+	// reload the secure settings once an update of their secrets is propagated to the keystore of the nodes
+	if esReachable {
+		requeueAfter, err := d.reconcileSecureSettingsReload(ctx, esClient, keystoreResources, time.Now())
+		if err != nil {
+			msg := "Could not reload secure settings, re-queuing"
+			log.Info(msg, "err", err, "namespace", d.ES.Namespace, "es_name", d.ES.Name)
+			d.ReconcileState.AddEvent(corev1.EventTypeWarning, events.EventReasonUnexpected, fmt.Sprintf("%s: %s", msg, err.Error()))
+			results.WithReconciliationState(defaultRequeue.WithReason(msg))
+		}
+		if requeueAfter > 0 {
+			results.WithReconciliationState(reconciler.RequeueAfter(requeueAfter).ReconciliationComplete())
+		}
+	}
+
 	// set an annotation with the ClusterUUID, if bootstrapped
 	requeue, err := bootstrap.ReconcileClusterUUID(ctx, d.Client, &d.ES, esClient, esReachable)
 	if err != nil {
@@ -372,6 +543,7 @@ func (d *defaultDriver) newElasticsearchClient(
 		caCerts,
 		esclient.Timeout(ctx, d.ES),
 		dev.Enabled,
+		d.OperatorParameters.DryRun.ReporterFor(&d.ES),
 	)
 }
 
@@ -507,3 +679,21 @@ func esReachableConditionMessage(internalService *corev1.Service, isServiceReady
 		return fmt.Sprintf("Service %s/%s has endpoints", internalService.Namespace, internalService.Name)
 	}
 }
+
+// reportILMDataTiers surfaces in the status the data tiers the ILM policies declared in the spec move indices to, but
+// which no NodeSet provides: indices would remain on their current tier.
+func reportILMDataTiers(reconcileState *reconcile.State, missing []esv1.DataTier) {
+	if len(missing) == 0 {
+		reconcileState.ReportCondition(esv1.ILMDataTiersAvailable, corev1.ConditionTrue, "All the data tiers used by the declared ILM policies are available")
+		return
+	}
+	tiers := make([]string, len(missing))
+	for i, tier := range missing {
+		tiers[i] = string(tier)
+	}
+	reconcileState.ReportCondition(
+		esv1.ILMDataTiersAvailable,
+		corev1.ConditionFalse,
+		fmt.Sprintf("No NodeSet provides the data tiers used by the declared ILM policies: %s", strings.Join(tiers, ", ")),
+	)
+}
@@ -166,6 +166,18 @@ func (f *fakeSecurityClient) GetServiceAccountCredentials(_ context.Context, nam
 	return serviceAccountCredential, nil
 }
 
+func (f *fakeSecurityClient) GetUsers(_ context.Context) (client.Users, error) {
+	return nil, nil
+}
+
+func (f *fakeSecurityClient) UpdateUser(_ context.Context, _ string, _ client.User) error {
+	return nil
+}
+
+func (f *fakeSecurityClient) DeleteUser(_ context.Context, _ string) error {
+	return nil
+}
+
 func newFakeSecurityClient() *fakeSecurityClient {
 	return &fakeSecurityClient{
 		serviceAccountCredentials: make(map[string]esclient.ServiceAccountCredential),
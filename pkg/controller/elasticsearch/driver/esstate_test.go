@@ -50,6 +50,8 @@ type fakeESClient struct { //nolint:maligned
 	health                      esclient.Health
 	GetClusterHealthCalledCount int
 	version                     version.Version
+
+	ReloadSecureSettingsCalledCount int
 }
 
 func (f *fakeESClient) SetMinimumMasterNodes(_ context.Context, n int) error {
@@ -130,6 +132,11 @@ func (f *fakeESClient) DeleteShutdown(_ context.Context, _ string) error {
 	return nil
 }
 
+func (f *fakeESClient) ReloadSecureSettings(_ context.Context) error {
+	f.ReloadSecureSettingsCalledCount++
+	return nil
+}
+
 func (f *fakeESClient) Version() version.Version {
 	return f.version
 }
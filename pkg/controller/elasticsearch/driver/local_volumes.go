@@ -0,0 +1,54 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package driver
+
+import (
+	"context"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+
+	esv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/elasticsearch/v1"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/utils/k8s"
+)
+
+// podsPinnedToUnavailableNodes returns the names of the Pods scheduled on Kubernetes nodes which cannot host them again
+// once deleted, if the Elasticsearch resource is annotated to indicate that its data is stored on local volumes.
+// Such Pods can only be scheduled again on the Kubernetes node holding their PersistentVolumes: if that node has been
+// cordoned or removed, a deleted Pod would stay Pending, away from its data.
+func podsPinnedToUnavailableNodes(ctx context.Context, c k8s.Client, es esv1.Elasticsearch, pods []corev1.Pod) (map[string]struct{}, error) {
+	if es.Annotations[esv1.LocalVolumesAnnotation] != "true" {
+		return nil, nil
+	}
+	pinned := map[string]struct{}{}
+	nodes := map[string]bool{}
+	for i := range pods {
+		scheduled, nodeName := isPodScheduled(&pods[i])
+		if !scheduled {
+			continue
+		}
+		available, checked := nodes[nodeName]
+		if !checked {
+			node := &corev1.Node{}
+			err := c.Get(ctx, types.NamespacedName{Name: nodeName}, node)
+			switch {
+			case errors.IsNotFound(err):
+				// the node and its local data are gone
+				available = false
+			case err != nil:
+				return nil, fmt.Errorf("while getting node %s: %w", nodeName, err)
+			default:
+				available = !node.Spec.Unschedulable
+			}
+			nodes[nodeName] = available
+		}
+		if !available {
+			pinned[pods[i].Name] = struct{}{}
+		}
+	}
+	return pinned, nil
+}
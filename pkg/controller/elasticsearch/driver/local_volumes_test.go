@@ -0,0 +1,71 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package driver
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	esv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/elasticsearch/v1"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/utils/k8s"
+)
+
+func Test_podsPinnedToUnavailableNodes(t *testing.T) {
+	localVolumes := map[string]string{esv1.LocalVolumesAnnotation: "true"}
+	nodes := []corev1.Node{
+		{ObjectMeta: metav1.ObjectMeta{Name: "node-1"}},
+		{ObjectMeta: metav1.ObjectMeta{Name: "cordoned"}, Spec: corev1.NodeSpec{Unschedulable: true}},
+	}
+	pods := []corev1.Pod{
+		*newAutoscalerTestPod("es-0", "node-1", true, nil, nil),
+		*newAutoscalerTestPod("es-1", "cordoned", true, nil, nil),
+		*newAutoscalerTestPod("es-2", "removed", false, nil, nil),
+		*newAutoscalerTestPod("es-3", "", false, nil, nil),
+	}
+	pods[3].Status.Conditions = nil
+
+	tests := []struct {
+		name          string
+		esAnnotations map[string]string
+		want          map[string]struct{}
+	}{
+		{
+			name: "no local volumes annotation",
+			want: nil,
+		},
+		{
+			name:          "Pods on cordoned or removed nodes are pinned",
+			esAnnotations: localVolumes,
+			want:          map[string]struct{}{"es-1": {}, "es-2": {}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			es := esv1.Elasticsearch{ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "es", Annotations: tt.esAnnotations}}
+			c := k8s.NewFakeClient(&nodes[0], &nodes[1])
+			got, err := podsPinnedToUnavailableNodes(context.Background(), c, es, pods)
+			require.NoError(t, err)
+			require.Equal(t, tt.want, got)
+
+			// pinned Pods are not restarted
+			predicateContext := PredicateContext{pinnedToUnavailableNodes: got}
+			for _, pod := range pods {
+				_, isPinned := tt.want[pod.Name]
+				for _, p := range predicates {
+					if p.name != "do_not_restart_pods_pinned_to_unavailable_nodes" {
+						continue
+					}
+					ok, err := p.fn(predicateContext, pod, nil, false)
+					require.NoError(t, err)
+					require.Equal(t, !isPinned, ok)
+				}
+			}
+		})
+	}
+}
@@ -13,6 +13,7 @@ import (
 	corev1 "k8s.io/api/core/v1"
 
 	esv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/elasticsearch/v1"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/decisions"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/events"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/keystore"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/reconciler"
@@ -49,6 +50,7 @@ func (d *defaultDriver) reconcileNodeSpecs(
 	if ok, err := d.autoscaledResourcesSynced(ctx, d.ES); err != nil {
 		return results.WithError(fmt.Errorf("StatefulSet recreation: %w", err))
 	} else if !ok {
+		decisions.Fail(ctx, "autoscaling_synced", "Waiting for autoscaling controller to sync node sets")
 		return results.WithReconciliationState(defaultRequeue.WithReason("Waiting for autoscaling controller to sync node sets"))
 	}
 
@@ -58,8 +60,10 @@ func (d *defaultDriver) reconcileNodeSpecs(
 		return results.WithError(err)
 	}
 	if !ok {
+		decisions.Fail(ctx, "expectations_satisfied", reason)
 		return results.WithReconciliationState(defaultRequeue.WithReason(reason))
 	}
+	decisions.Pass(ctx, "expectations_satisfied", "")
 
 	// recreate any StatefulSet that needs to account for PVC expansion
 	recreations, err := recreateStatefulSets(ctx, d.K8sClient(), d.ES)
@@ -73,6 +77,7 @@ func (d *defaultDriver) reconcileNodeSpecs(
 		// the sset doesn't exist (was just deleted), but the Pods do actually exist.
 		log.V(1).Info("StatefulSets recreation in progress, re-queueing.",
 			"namespace", d.ES.Namespace, "es_name", d.ES.Name, "recreations", recreations)
+		decisions.Fail(ctx, "statefulsets_recreation", fmt.Sprintf("%d StatefulSets are being recreated", recreations))
 		return results.WithReconciliationState(defaultRequeue.WithReason("StatefulSets recreation in progress"))
 	}
 
@@ -81,10 +86,24 @@ func (d *defaultDriver) reconcileNodeSpecs(
 		return results.WithError(err)
 	}
 
-	expectedResources, err := nodespec.BuildExpectedResources(ctx, d.Client, d.ES, keystoreResources, actualStatefulSets, d.OperatorParameters.IPFamily, d.OperatorParameters.SetDefaultSecurityContext)
+	// replace the StatefulSets whose volume claim templates cannot be updated in place
+	migrated, err := migrateStorage(ctx, d.K8sClient(), d.ES, actualStatefulSets)
+	if err != nil {
+		return results.WithError(fmt.Errorf("storage migration: %w", err))
+	}
+	if migrated {
+		// the certificates and configuration of the new StatefulSets are reconciled before they are created
+		decisions.Decide(ctx, "storage_migration", "Replacing StatefulSets to migrate their storage")
+		return results.WithReconciliationState(defaultRequeue.WithReason("Storage migration in progress"))
+	}
+
+	expectedResources, err := nodespec.BuildExpectedResources(ctx, d.Client, d.ES, keystoreResources, actualStatefulSets, d.OperatorParameters.IPFamily, d.OperatorParameters.SetDefaultSecurityContext, d.ES.Spec.SetVMMaxMapCountOrDefault(d.OperatorParameters.SetVMMaxMapCount), d.OperatorParameters.ElasticsearchDefaultConfig)
 	if err != nil {
 		return results.WithError(err)
 	}
+	// surface the settings defined with different values by several configuration sources, for example operator-managed
+	// settings overridden by the user configuration, which would otherwise go unnoticed
+	reconcileState.UpdateConfigOverrides(expectedResources.ConfigOverrides())
 
 	if esClient.IsDesiredNodesSupported() {
 		results.WithResults(d.updateDesiredNodes(ctx, esClient, esReachable, expectedResources))
@@ -137,9 +156,14 @@ func (d *defaultDriver) reconcileNodeSpecs(
 		return results.WithError(err)
 	}
 
-	if err := GarbageCollectPVCs(ctx, d.K8sClient(), d.ES, actualStatefulSets, expectedResources.StatefulSets()); err != nil {
+	pvcDeletionRequeue, err := GarbageCollectPVCs(ctx, d.K8sClient(), d.ES, actualStatefulSets, expectedResources.StatefulSets(), d.OperatorParameters.PVCDeletionGracePeriod)
+	if err != nil {
 		return results.WithError(err)
 	}
+	if pvcDeletionRequeue > 0 {
+		// unused PVCs are kept during the grace period, this does not prevent the reconciliation from being complete
+		results.WithReconciliationState(reconciler.RequeueAfter(pvcDeletionRequeue).ReconciliationComplete())
+	}
 
 	// Phase 2: if there is any Pending or bootlooping Pod to upgrade, do it.
 	attempted, err := d.MaybeForceUpgrade(ctx, actualStatefulSets)
@@ -155,6 +179,7 @@ func (d *defaultDriver) reconcileNodeSpecs(
 	if !esReachable {
 		msg := "Elasticsearch cannot be reached yet, re-queuing"
 		log.Info(msg, "namespace", d.ES.Namespace, "es_name", d.ES.Name)
+		decisions.Fail(ctx, "elasticsearch_reachable", "Elasticsearch cannot be reached yet")
 		reconcileState.UpdateWithPhase(esv1.ElasticsearchApplyingChangesPhase)
 		return results.WithReconciliationState(defaultRequeue.WithReason(msg))
 	}
@@ -6,6 +6,7 @@ package driver
 
 import (
 	"context"
+	"time"
 
 	corev1 "k8s.io/api/core/v1"
 	"sigs.k8s.io/controller-runtime/pkg/client"
@@ -18,6 +19,10 @@ import (
 	"github.com/elastic/cloud-on-k8s/v2/pkg/utils/stringsutil"
 )
 
+// UnusedSinceAnnotation records when a PVC was found to be no longer used, in order to delay its deletion until the
+// PVC deletion grace period is over.
+const UnusedSinceAnnotation = "eck.k8s.elastic.co/unused-since"
+
 // GarbageCollectPVCs ensures PersistentVolumeClaims created for the given es resource are deleted
 // when no longer used, since this is not done automatically by the StatefulSet controller.
 // Related issue in the k8s repo: https://github.com/kubernetes/kubernetes/issues/55045
@@ -25,24 +30,86 @@ import (
 // This covers:
 // * leftover PVCs created for StatefulSets that do not exist anymore
 // * leftover PVCs created for StatefulSets replicas that don't exist anymore (eg. downscale from 5 to 3 nodes)
+// If a grace period is set, unused PVCs are only deleted once they have been unused for that long, so that their data
+// can be recovered by reverting an accidental change of the specification. The returned duration is the time after
+// which the next unused PVC can be deleted, or 0 if there is none.
 func GarbageCollectPVCs(
 	ctx context.Context,
 	k8sClient k8s.Client,
 	es esv1.Elasticsearch,
 	actualStatefulSets sset.StatefulSetList,
 	expectedStatefulSets sset.StatefulSetList,
-) error {
+	gracePeriod time.Duration,
+) (time.Duration, error) {
 	// PVCs are using the same labels as their corresponding StatefulSet, so we can filter on ES cluster name.
 	var pvcs corev1.PersistentVolumeClaimList
 	ns := client.InNamespace(es.Namespace)
 	matchLabels := label.NewLabelSelectorForElasticsearch(es)
 	if err := k8sClient.List(ctx, &pvcs, ns, matchLabels); err != nil {
-		return err
+		return 0, err
+	}
+	toRemove := pvcsToRemove(pvcs.Items, actualStatefulSets, expectedStatefulSets)
+	if err := clearUnusedSince(ctx, k8sClient, pvcs.Items, toRemove); err != nil {
+		return 0, err
 	}
-	for _, pvc := range pvcsToRemove(pvcs.Items, actualStatefulSets, expectedStatefulSets) {
+	now := time.Now()
+	var requeueIn time.Duration
+	for _, pvc := range toRemove {
 		pvc := pvc
+		if gracePeriod > 0 {
+			remaining, err := remainingGracePeriod(ctx, k8sClient, &pvc, now, gracePeriod)
+			if err != nil {
+				return 0, err
+			}
+			if remaining > 0 {
+				if requeueIn == 0 || remaining < requeueIn {
+					requeueIn = remaining
+				}
+				continue
+			}
+		}
 		ulog.FromContext(ctx).Info("Deleting PVC", "namespace", pvc.Namespace, "pvc_name", pvc.Name)
 		if err := k8sClient.Delete(ctx, &pvc); err != nil {
+			return 0, err
+		}
+	}
+	return requeueIn, nil
+}
+
+// remainingGracePeriod returns how long the given unused PVC must be kept before it can be deleted. The time at which
+// the PVC was first found to be unused is recorded in an annotation of the PVC, to survive operator restarts.
+func remainingGracePeriod(ctx context.Context, k8sClient k8s.Client, pvc *corev1.PersistentVolumeClaim, now time.Time, gracePeriod time.Duration) (time.Duration, error) {
+	unusedSince, err := time.Parse(time.RFC3339, pvc.Annotations[UnusedSinceAnnotation])
+	if err != nil {
+		// not annotated yet, or the annotation was tampered with: start the grace period now
+		ulog.FromContext(ctx).Info("Delaying deletion of unused PVC", "namespace", pvc.Namespace, "pvc_name", pvc.Name, "grace_period", gracePeriod)
+		if pvc.Annotations == nil {
+			pvc.Annotations = map[string]string{}
+		}
+		pvc.Annotations[UnusedSinceAnnotation] = now.Format(time.RFC3339)
+		return gracePeriod, k8sClient.Update(ctx, pvc)
+	}
+	return unusedSince.Add(gracePeriod).Sub(now), nil
+}
+
+// clearUnusedSince removes the unused annotation from the PVCs used again, for example because a removed nodeSet was
+// restored during the grace period.
+func clearUnusedSince(ctx context.Context, k8sClient k8s.Client, pvcs []corev1.PersistentVolumeClaim, toRemove []corev1.PersistentVolumeClaim) error {
+	unused := make(map[string]struct{}, len(toRemove))
+	for _, pvc := range toRemove {
+		unused[pvc.Name] = struct{}{}
+	}
+	for _, pvc := range pvcs {
+		pvc := pvc
+		if _, annotated := pvc.Annotations[UnusedSinceAnnotation]; !annotated {
+			continue
+		}
+		if _, isUnused := unused[pvc.Name]; isUnused {
+			continue
+		}
+		ulog.FromContext(ctx).Info("PVC is used again, cancelling its deletion", "namespace", pvc.Namespace, "pvc_name", pvc.Name)
+		delete(pvc.Annotations, UnusedSinceAnnotation)
+		if err := k8sClient.Update(ctx, &pvc); err != nil {
 			return err
 		}
 	}
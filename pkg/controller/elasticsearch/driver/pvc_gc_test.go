@@ -8,12 +8,14 @@ import (
 	"context"
 	"reflect"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/require"
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
 
 	esv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/elasticsearch/v1"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/elasticsearch/label"
@@ -177,7 +179,7 @@ func TestGarbageCollectPVCs(t *testing.T) {
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			if err := GarbageCollectPVCs(context.Background(), tt.args.k8sClient, tt.args.es, tt.args.actualStatefulSets, tt.args.expectedStatefulSets); (err != nil) != tt.wantErr {
+			if _, err := GarbageCollectPVCs(context.Background(), tt.args.k8sClient, tt.args.es, tt.args.actualStatefulSets, tt.args.expectedStatefulSets, 0); (err != nil) != tt.wantErr {
 				t.Errorf("GarbageCollectPVCs() error = %v, wantErr %v", err, tt.wantErr)
 			}
 			var retrievedPVCs corev1.PersistentVolumeClaimList
@@ -186,3 +188,35 @@ func TestGarbageCollectPVCs(t *testing.T) {
 		})
 	}
 }
+
+func TestGarbageCollectPVCs_GracePeriod(t *testing.T) {
+	es := esv1.Elasticsearch{ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "es"}}
+	expiredPVC := buildPVCPtr("claim1-expired-0")
+	expiredPVC.Annotations = map[string]string{UnusedSinceAnnotation: time.Now().Add(-2 * time.Hour).Format(time.RFC3339)}
+	restoredPVC := buildPVCPtr("claim1-sset1-0")
+	restoredPVC.Annotations = map[string]string{UnusedSinceAnnotation: time.Now().Format(time.RFC3339)}
+	k8sClient := k8s.NewFakeClient(restoredPVC, buildPVCPtr("claim1-oldsset-0"), expiredPVC)
+	statefulSets := sset.StatefulSetList{buildSsetWithClaims("sset1", 1, "claim1")}
+
+	requeueIn, err := GarbageCollectPVCs(context.Background(), k8sClient, es, statefulSets, statefulSets, time.Hour)
+	require.NoError(t, err)
+	// the newly unused PVC is kept for the whole grace period
+	require.Equal(t, time.Hour, requeueIn)
+
+	getPVC := func(name string) (corev1.PersistentVolumeClaim, error) {
+		var pvc corev1.PersistentVolumeClaim
+		err := k8sClient.Get(context.Background(), types.NamespacedName{Namespace: "ns", Name: name}, &pvc)
+		return pvc, err
+	}
+	// the PVC unused for longer than the grace period is deleted
+	_, err = getPVC("claim1-expired-0")
+	require.Error(t, err)
+	// the newly unused PVC is annotated
+	unused, err := getPVC("claim1-oldsset-0")
+	require.NoError(t, err)
+	require.Contains(t, unused.Annotations, UnusedSinceAnnotation)
+	// the PVC used again is not annotated anymore
+	restored, err := getPVC("claim1-sset1-0")
+	require.NoError(t, err)
+	require.NotContains(t, restored.Annotations, UnusedSinceAnnotation)
+}
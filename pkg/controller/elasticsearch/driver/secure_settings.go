@@ -0,0 +1,101 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package driver
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	esv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/elasticsearch/v1"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/keystore"
+	esclient "github.com/elastic/cloud-on-k8s/v2/pkg/controller/elasticsearch/client"
+	ulog "github.com/elastic/cloud-on-k8s/v2/pkg/utils/log"
+)
+
+// secureSettingsPropagationDelay is the time to wait for an update of the secure settings secrets to be propagated to
+// the keystore of every node before reloading the secure settings: the kubelet refreshes the content of the secret
+// volumes according to its sync period and cache TTL (1 minute each by default), then the keystore updater container
+// rebuilds the keystore within its poll interval.
+const secureSettingsPropagationDelay = 2*time.Minute + 30*time.Second
+
+// secureSettingsReload is the state of the reload of the secure settings, stored in the esv1.SecureSettingsReloadAnnotation.
+type secureSettingsReload struct {
+	// Version is the version of the secure settings secrets.
+	Version string `json:"version"`
+	// ObservedAt is the time at which the operator observed this version.
+	ObservedAt time.Time `json:"observedAt"`
+	// Reloaded is true once the secure settings of this version are reloaded.
+	Reloaded bool `json:"reloaded"`
+}
+
+// reconcileSecureSettingsReload reloads the secure settings of all the nodes once an update of the secure settings
+// secrets is propagated to their keystore, so that credentials can be rotated without restarting the Pods.
+// This is only done if enabled with the ReloadSecureSettingsAnnotation, otherwise the Pods are restarted.
+// It returns the duration after which the reconciliation should be requeued to reload the secure settings, if any.
+func (d *defaultDriver) reconcileSecureSettingsReload(
+	ctx context.Context,
+	esClient esclient.Client,
+	keystoreResources *keystore.Resources,
+	now time.Time,
+) (time.Duration, error) {
+	if keystoreResources == nil || !d.ES.IsSecureSettingsReloadEnabled() {
+		return 0, nil
+	}
+
+	var state secureSettingsReload
+	serialized, exists := d.ES.Annotations[esv1.SecureSettingsReloadAnnotation]
+	if exists {
+		if err := json.Unmarshal([]byte(serialized), &state); err != nil {
+			// start over from the current version rather than blocking the reconciliation on a corrupted annotation
+			ulog.FromContext(ctx).Error(err, "Ignoring invalid annotation", "annotation", esv1.SecureSettingsReloadAnnotation,
+				"namespace", d.ES.Namespace, "es_name", d.ES.Name)
+			exists = false
+		}
+	}
+	if !exists {
+		// the Pods are created or rotated along with the keystore, there is nothing to reload
+		return 0, d.annotateSecureSettingsReload(ctx, secureSettingsReload{Version: keystoreResources.Version, ObservedAt: now, Reloaded: true})
+	}
+
+	if state.Version != keystoreResources.Version {
+		// new version of the secure settings, wait for it to be propagated to the keystore of every node
+		state = secureSettingsReload{Version: keystoreResources.Version, ObservedAt: now}
+		if err := d.annotateSecureSettingsReload(ctx, state); err != nil {
+			return 0, err
+		}
+	}
+	if state.Reloaded {
+		return 0, nil
+	}
+	if remaining := state.ObservedAt.Add(secureSettingsPropagationDelay).Sub(now); remaining > 0 {
+		return remaining, nil
+	}
+
+	ulog.FromContext(ctx).Info("Reloading secure settings", "namespace", d.ES.Namespace, "es_name", d.ES.Name,
+		"version", state.Version)
+	if err := esClient.ReloadSecureSettings(ctx); err != nil {
+		return 0, err
+	}
+	state.Reloaded = true
+	return 0, d.annotateSecureSettingsReload(ctx, state)
+}
+
+// annotateSecureSettingsReload stores the given state of the reload of the secure settings in the Elasticsearch
+// resource.
+func (d *defaultDriver) annotateSecureSettingsReload(ctx context.Context, state secureSettingsReload) error {
+	serialized, err := json.Marshal(state)
+	if err != nil {
+		return err
+	}
+	patch := client.MergeFrom(d.ES.DeepCopy())
+	if d.ES.Annotations == nil {
+		d.ES.Annotations = make(map[string]string)
+	}
+	d.ES.Annotations[esv1.SecureSettingsReloadAnnotation] = string(serialized)
+	return d.Client.Patch(ctx, &d.ES, patch)
+}
@@ -0,0 +1,118 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package driver
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	esv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/elasticsearch/v1"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/keystore"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/utils/k8s"
+)
+
+func Test_defaultDriver_reconcileSecureSettingsReload(t *testing.T) {
+	now := time.Date(2022, 10, 1, 12, 0, 0, 0, time.UTC)
+	annotation := func(state secureSettingsReload) string {
+		serialized, err := json.Marshal(state)
+		require.NoError(t, err)
+		return string(serialized)
+	}
+
+	tests := []struct {
+		name              string
+		reloadDisabled    bool
+		annotations       map[string]string
+		keystoreResources *keystore.Resources
+		wantRequeueAfter  time.Duration
+		wantReloaded      bool
+		wantState         *secureSettingsReload
+	}{
+		{
+			name: "no secure settings",
+		},
+		{
+			name:              "reload not enabled: the Pods are restarted instead",
+			reloadDisabled:    true,
+			keystoreResources: &keystore.Resources{Version: "1"},
+		},
+		{
+			name:              "first reconciliation: nothing to reload",
+			keystoreResources: &keystore.Resources{Version: "1"},
+			wantState:         &secureSettingsReload{Version: "1", ObservedAt: now, Reloaded: true},
+		},
+		{
+			name:              "invalid annotation: start over from the current version",
+			annotations:       map[string]string{esv1.SecureSettingsReloadAnnotation: "{"},
+			keystoreResources: &keystore.Resources{Version: "1"},
+			wantState:         &secureSettingsReload{Version: "1", ObservedAt: now, Reloaded: true},
+		},
+		{
+			name:              "secure settings already reloaded",
+			annotations:       map[string]string{esv1.SecureSettingsReloadAnnotation: annotation(secureSettingsReload{Version: "1", ObservedAt: now.Add(-time.Hour), Reloaded: true})},
+			keystoreResources: &keystore.Resources{Version: "1"},
+			wantState:         &secureSettingsReload{Version: "1", ObservedAt: now.Add(-time.Hour), Reloaded: true},
+		},
+		{
+			name:              "new version: wait for the keystores to be updated",
+			annotations:       map[string]string{esv1.SecureSettingsReloadAnnotation: annotation(secureSettingsReload{Version: "1", ObservedAt: now.Add(-time.Hour), Reloaded: true})},
+			keystoreResources: &keystore.Resources{Version: "2"},
+			wantRequeueAfter:  secureSettingsPropagationDelay,
+			wantState:         &secureSettingsReload{Version: "2", ObservedAt: now},
+		},
+		{
+			name:              "new version being propagated",
+			annotations:       map[string]string{esv1.SecureSettingsReloadAnnotation: annotation(secureSettingsReload{Version: "2", ObservedAt: now.Add(-time.Minute)})},
+			keystoreResources: &keystore.Resources{Version: "2"},
+			wantRequeueAfter:  secureSettingsPropagationDelay - time.Minute,
+			wantState:         &secureSettingsReload{Version: "2", ObservedAt: now.Add(-time.Minute)},
+		},
+		{
+			name:              "new version propagated: reload the secure settings",
+			annotations:       map[string]string{esv1.SecureSettingsReloadAnnotation: annotation(secureSettingsReload{Version: "2", ObservedAt: now.Add(-secureSettingsPropagationDelay)})},
+			keystoreResources: &keystore.Resources{Version: "2"},
+			wantReloaded:      true,
+			wantState:         &secureSettingsReload{Version: "2", ObservedAt: now.Add(-secureSettingsPropagationDelay), Reloaded: true},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			annotations := map[string]string{esv1.ReloadSecureSettingsAnnotation: "true"}
+			if tt.reloadDisabled {
+				delete(annotations, esv1.ReloadSecureSettingsAnnotation)
+			}
+			for k, v := range tt.annotations {
+				annotations[k] = v
+			}
+			es := esv1.Elasticsearch{ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "es", Annotations: annotations}}
+			k8sClient := k8s.NewFakeClient(&es)
+			d := &defaultDriver{DefaultDriverParameters: DefaultDriverParameters{Client: k8sClient, ES: es}}
+			esClient := &fakeESClient{}
+
+			requeueAfter, err := d.reconcileSecureSettingsReload(context.Background(), esClient, tt.keystoreResources, now)
+			require.NoError(t, err)
+			require.Equal(t, tt.wantRequeueAfter, requeueAfter)
+			require.Equal(t, tt.wantReloaded, esClient.ReloadSecureSettingsCalledCount == 1)
+
+			var updated esv1.Elasticsearch
+			require.NoError(t, k8sClient.Get(context.Background(), k8s.ExtractNamespacedName(&es), &updated))
+			serialized, exists := updated.Annotations[esv1.SecureSettingsReloadAnnotation]
+			if tt.wantState == nil {
+				require.False(t, exists)
+				return
+			}
+			var state secureSettingsReload
+			require.NoError(t, json.Unmarshal([]byte(serialized), &state))
+			require.True(t, tt.wantState.ObservedAt.Equal(state.ObservedAt))
+			state.ObservedAt = tt.wantState.ObservedAt
+			require.Equal(t, *tt.wantState, state)
+		})
+	}
+}
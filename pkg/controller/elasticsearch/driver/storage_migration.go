@@ -0,0 +1,92 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package driver
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+
+	esv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/elasticsearch/v1"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/defaults"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/elasticsearch/sset"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/elasticsearch/validation"
+	esvolume "github.com/elastic/cloud-on-k8s/v2/pkg/controller/elasticsearch/volume"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/utils/k8s"
+	ulog "github.com/elastic/cloud-on-k8s/v2/pkg/utils/log"
+)
+
+// migrateStorage assigns a new StatefulSet to the NodeSets whose volume claim templates changed in a way which cannot be
+// applied to their existing StatefulSet, such as a storage class change, if storage migration is enabled. The new
+// StatefulSet is then created next to the existing one, which is removed once its data has been migrated, as if the
+// NodeSet had been renamed. It returns true if the Elasticsearch resource was updated.
+func migrateStorage(ctx context.Context, k8sClient k8s.Client, es esv1.Elasticsearch, actualStatefulSets sset.StatefulSetList) (bool, error) {
+	current := es.MigratedStatefulSets()
+	expected := make(map[string]string, len(current))
+	for _, nodeSet := range es.Spec.NodeSets {
+		// forget the NodeSets removed from the specification
+		if name, migrated := current[nodeSet.Name]; migrated {
+			expected[nodeSet.Name] = name
+		}
+		if !es.IsStorageMigrationEnabled() {
+			continue
+		}
+		actualSset, exists := actualStatefulSets.GetByName(es.StatefulSetName(nodeSet.Name))
+		if !exists {
+			continue
+		}
+		claims := defaults.AppendDefaultPVCs(nodeSet.VolumeClaimTemplates, esvolume.PodSpecWithMemoryDataVolume(nodeSet), esvolume.DefaultVolumeClaimTemplates...)
+		if !validation.StorageMigrationRequired(actualSset.Spec.VolumeClaimTemplates, claims) {
+			continue
+		}
+		name, err := nextStatefulSetName(es, nodeSet.Name, actualStatefulSets)
+		if err != nil {
+			return false, err
+		}
+		if err := esv1.ValidateStatefulSetNameLength(name, nodeSet.Count); err != nil {
+			return false, fmt.Errorf("while migrating the storage of nodeSet %s: %w", nodeSet.Name, err)
+		}
+		ulog.FromContext(ctx).Info("Replacing StatefulSet to migrate storage",
+			"namespace", es.Namespace, "es_name", es.Name, "statefulset_name", actualSset.Name, "new_statefulset_name", name)
+		expected[nodeSet.Name] = name
+	}
+
+	if reflect.DeepEqual(current, expected) || (len(current) == 0 && len(expected) == 0) {
+		return false, nil
+	}
+	if len(expected) == 0 {
+		delete(es.Annotations, esv1.NodeSetStatefulSetsAnnotation)
+	} else {
+		asJSON, err := json.Marshal(expected)
+		if err != nil {
+			return false, err
+		}
+		if es.Annotations == nil {
+			es.Annotations = make(map[string]string, 1)
+		}
+		es.Annotations[esv1.NodeSetStatefulSetsAnnotation] = string(asJSON)
+	}
+	return true, k8sClient.Update(ctx, &es)
+}
+
+// nextStatefulSetName returns the name of a new StatefulSet for the given NodeSet, which is neither used by an existing
+// StatefulSet nor by another NodeSet.
+func nextStatefulSetName(es esv1.Elasticsearch, nodeSetName string, actualStatefulSets sset.StatefulSetList) (string, error) {
+	taken := actualStatefulSets.Names()
+	for _, nodeSet := range es.Spec.NodeSets {
+		taken.Add(es.StatefulSetName(nodeSet.Name))
+		taken.Add(esv1.StatefulSet(es.Name, nodeSet.Name))
+	}
+	for i := 1; ; i++ {
+		name, err := esv1.ESNamer.SafeSuffix(es.Name, fmt.Sprintf("%s-%d", nodeSetName, i))
+		if err != nil {
+			return "", fmt.Errorf("while generating a new StatefulSet name for nodeSet %s: %w", nodeSetName, err)
+		}
+		if !taken.Has(name) {
+			return name, nil
+		}
+	}
+}
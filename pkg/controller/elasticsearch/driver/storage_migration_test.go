@@ -0,0 +1,127 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package driver
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/pointer"
+
+	esv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/elasticsearch/v1"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/elasticsearch/sset"
+	esvolume "github.com/elastic/cloud-on-k8s/v2/pkg/controller/elasticsearch/volume"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/utils/k8s"
+)
+
+func dataClaim(storageClass, size string) corev1.PersistentVolumeClaim {
+	return corev1.PersistentVolumeClaim{
+		ObjectMeta: metav1.ObjectMeta{Name: esvolume.ElasticsearchDataVolumeName},
+		Spec: corev1.PersistentVolumeClaimSpec{
+			AccessModes:      []corev1.PersistentVolumeAccessMode{corev1.ReadWriteOnce},
+			StorageClassName: pointer.StringPtr(storageClass),
+			Resources: corev1.ResourceRequirements{Requests: map[corev1.ResourceName]resource.Quantity{
+				corev1.ResourceStorage: resource.MustParse(size),
+			}}}}
+}
+
+func Test_migrateStorage(t *testing.T) {
+	migrationEnabled := map[string]string{esv1.StorageMigrationAnnotation: "true"}
+	withMigrated := func(annotations map[string]string, migrated string) map[string]string {
+		result := map[string]string{esv1.NodeSetStatefulSetsAnnotation: migrated}
+		for k, v := range annotations {
+			result[k] = v
+		}
+		return result
+	}
+	actualSset := func(name string, claim corev1.PersistentVolumeClaim) appsv1.StatefulSet {
+		return withClaims(appsv1.StatefulSet{ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: name}}, claim)
+	}
+	dataNodeSet := func(claim corev1.PersistentVolumeClaim) []esv1.NodeSet {
+		return []esv1.NodeSet{{Name: "data", Count: 3, VolumeClaimTemplates: []corev1.PersistentVolumeClaim{claim}}}
+	}
+
+	tests := []struct {
+		name              string
+		annotations       map[string]string
+		nodeSets          []esv1.NodeSet
+		actual            sset.StatefulSetList
+		wantUpdated       bool
+		wantStatefulSet   string
+		wantMigratedCount int
+	}{
+		{
+			name:            "storage class change without storage migration enabled",
+			nodeSets:        dataNodeSet(dataClaim("fast", "1Gi")),
+			actual:          sset.StatefulSetList{actualSset("es-es-data", dataClaim("slow", "1Gi"))},
+			wantStatefulSet: "es-es-data",
+		},
+		{
+			name:            "storage increase",
+			annotations:     migrationEnabled,
+			nodeSets:        dataNodeSet(dataClaim("slow", "2Gi")),
+			actual:          sset.StatefulSetList{actualSset("es-es-data", dataClaim("slow", "1Gi"))},
+			wantStatefulSet: "es-es-data",
+		},
+		{
+			name:              "storage class change",
+			annotations:       migrationEnabled,
+			nodeSets:          dataNodeSet(dataClaim("fast", "1Gi")),
+			actual:            sset.StatefulSetList{actualSset("es-es-data", dataClaim("slow", "1Gi"))},
+			wantUpdated:       true,
+			wantStatefulSet:   "es-es-data-1",
+			wantMigratedCount: 1,
+		},
+		{
+			name:              "storage decrease of a migrated NodeSet",
+			annotations:       withMigrated(migrationEnabled, `{"data":"es-es-data-1"}`),
+			nodeSets:          dataNodeSet(dataClaim("fast", "1Gi")),
+			actual:            sset.StatefulSetList{actualSset("es-es-data", dataClaim("slow", "1Gi")), actualSset("es-es-data-1", dataClaim("fast", "2Gi"))},
+			wantUpdated:       true,
+			wantStatefulSet:   "es-es-data-2",
+			wantMigratedCount: 1,
+		},
+		{
+			name:              "migration in progress",
+			annotations:       withMigrated(migrationEnabled, `{"data":"es-es-data-1"}`),
+			nodeSets:          dataNodeSet(dataClaim("fast", "1Gi")),
+			actual:            sset.StatefulSetList{actualSset("es-es-data", dataClaim("slow", "1Gi")), actualSset("es-es-data-1", dataClaim("fast", "1Gi"))},
+			wantStatefulSet:   "es-es-data-1",
+			wantMigratedCount: 1,
+		},
+		{
+			name:        "NodeSet removed after a migration",
+			annotations: withMigrated(nil, `{"removed":"es-es-removed-1"}`),
+			nodeSets:    dataNodeSet(dataClaim("fast", "1Gi")),
+			actual: sset.StatefulSetList{
+				actualSset("es-es-data", dataClaim("fast", "1Gi")), actualSset("es-es-removed-1", dataClaim("fast", "1Gi")),
+			},
+			wantUpdated:     true,
+			wantStatefulSet: "es-es-data",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			es := esv1.Elasticsearch{
+				ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "es", Annotations: tt.annotations},
+				Spec:       esv1.ElasticsearchSpec{NodeSets: tt.nodeSets},
+			}
+			k8sClient := k8s.NewFakeClient(es.DeepCopy())
+			updated, err := migrateStorage(context.Background(), k8sClient, *es.DeepCopy(), tt.actual)
+			require.NoError(t, err)
+			require.Equal(t, tt.wantUpdated, updated)
+
+			var actualES esv1.Elasticsearch
+			require.NoError(t, k8sClient.Get(context.Background(), k8s.ExtractNamespacedName(&es), &actualES))
+			require.Equal(t, tt.wantStatefulSet, actualES.StatefulSetName("data"))
+			require.Len(t, actualES.MigratedStatefulSets(), tt.wantMigratedCount)
+		})
+	}
+}
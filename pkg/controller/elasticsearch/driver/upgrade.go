@@ -13,6 +13,8 @@ import (
 	"k8s.io/apimachinery/pkg/types"
 
 	esv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/elasticsearch/v1"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/decisions"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/events"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/expectations"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/reconciler"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/version"
@@ -21,6 +23,7 @@ import (
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/elasticsearch/nodespec"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/elasticsearch/reconcile"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/elasticsearch/shutdown"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/elasticsearch/snapshot"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/elasticsearch/sset"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/utils/k8s"
 	ulog "github.com/elastic/cloud-on-k8s/v2/pkg/utils/log"
@@ -101,6 +104,15 @@ func (d *defaultDriver) handleUpgrades(
 	if err != nil {
 		return results.WithError(err)
 	}
+	if isVersionUpgrade && len(podsToUpgrade) > 0 {
+		reason, err := d.reconcileUpgradeSnapshot(ctx, esClient, currentPods)
+		if err != nil {
+			return results.WithError(err)
+		}
+		if reason != "" {
+			return results.WithReconciliationState(defaultRequeue.WithReason(reason))
+		}
+	}
 	shouldDoFullRestartUpgrade := isNonHACluster(currentPods, expectedMasters) && isVersionUpgrade
 	if shouldDoFullRestartUpgrade {
 		// unconditional full cluster upgrade
@@ -211,6 +223,56 @@ func isVersionUpgrade(es esv1.Elasticsearch) (bool, error) {
 	return specVersion.GT(statusVersion), nil
 }
 
+// reconcileUpgradeSnapshot takes a snapshot before a version upgrade if required by the spec, unless some nodes already
+// run the upgraded version. It returns a non-empty reason if the upgrade must wait for the snapshot, or is aborted because
+// the snapshot failed.
+func (d *defaultDriver) reconcileUpgradeSnapshot(ctx context.Context, esClient esclient.Client, currentPods []corev1.Pod) (string, error) {
+	if d.ES.Spec.UpgradeSnapshot == nil {
+		return "", nil
+	}
+	started, err := isVersionUpgradeStarted(d.ES, currentPods)
+	if err != nil {
+		return "", err
+	}
+	if started {
+		return "", nil
+	}
+	state, err := snapshot.ReconcileUpgradeSnapshot(ctx, esClient, d.ES)
+	if err != nil {
+		return "", err
+	}
+	if state.Taken {
+		d.ReconcileState.ReportCondition(esv1.UpgradeSnapshotTaken, corev1.ConditionTrue, state.Message)
+		return "", nil
+	}
+	if state.Failed {
+		// emit the event once, when the upgrade is aborted
+		if i := d.ES.Status.Conditions.Index(esv1.UpgradeSnapshotTaken); i < 0 || d.ES.Status.Conditions[i].Message != state.Message {
+			d.ReconcileState.AddEvent(corev1.EventTypeWarning, events.EventReasonSnapshot, state.Message)
+		}
+	}
+	d.ReconcileState.ReportCondition(esv1.UpgradeSnapshotTaken, corev1.ConditionFalse, state.Message)
+	return state.Message, nil
+}
+
+// isVersionUpgradeStarted returns true if some Pods already run the version of the spec.
+func isVersionUpgradeStarted(es esv1.Elasticsearch, pods []corev1.Pod) (bool, error) {
+	specVersion, err := version.Parse(es.Spec.Version)
+	if err != nil {
+		return false, err
+	}
+	for _, pod := range pods {
+		podVersion, err := version.FromLabels(pod.Labels, label.VersionLabelName)
+		if err != nil {
+			return false, err
+		}
+		if podVersion.GTE(specVersion) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
 func healthyPods(
 	client k8s.Client,
 	statefulSets sset.StatefulSetList,
@@ -395,7 +457,11 @@ func (ctx *upgradeCtx) readyToDelete(pod corev1.Pod) (bool, error) {
 	if err != nil {
 		return false, err
 	}
-	return response.Status == esclient.ShutdownComplete, nil
+	if response.Status != esclient.ShutdownComplete {
+		return false, nil
+	}
+	decisions.Pass(ctx.parentCtx, "node_shutdown_complete", fmt.Sprintf("Node shutdown of Pod %s is complete", pod.Name))
+	return true, nil
 }
 
 func (ctx *upgradeCtx) requestNodeRestarts(podsToRestart []corev1.Pod) error {
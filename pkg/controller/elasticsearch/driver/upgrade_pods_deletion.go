@@ -6,12 +6,16 @@ package driver
 
 import (
 	"context"
+	"fmt"
 	"sort"
+	"strings"
 
 	corev1 "k8s.io/api/core/v1"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	esv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/elasticsearch/v1"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/decisions"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/events"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/expectations"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/elasticsearch/label"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/elasticsearch/reconcile"
@@ -21,6 +25,9 @@ import (
 	"github.com/elastic/cloud-on-k8s/v2/pkg/utils/stringsutil"
 )
 
+// nodeShutdownPendingMsg is reported in the upgrade status of a node waiting for its node shutdown to complete.
+const nodeShutdownPendingMsg = "Waiting for the node shutdown to complete before restarting the node"
+
 // Delete runs through a list of potential candidates and select the ones that can be deleted.
 // Do not run this function unless driver expectations are met.
 func (ctx *upgradeCtx) Delete() ([]corev1.Pod, error) {
@@ -34,6 +41,10 @@ func (ctx *upgradeCtx) Delete() ([]corev1.Pod, error) {
 	}
 	// Get allowed deletions and check if maxUnavailable has been reached.
 	allowedDeletions, maxUnavailableReached := ctx.getAllowedDeletions()
+	decisions.Decide(ctx.parentCtx, "pods_to_upgrade", fmt.Sprintf(
+		"Pods to upgrade: %s, allowed deletions: %d, max unavailable reached: %t",
+		strings.Join(k8s.PodNames(ctx.podsToUpgrade), ","), allowedDeletions, maxUnavailableReached,
+	))
 
 	// Step 1. Sort the Pods to get the ones with the higher priority
 	candidates := make([]corev1.Pod, len(ctx.podsToUpgrade)) // work on a copy in order to have no side effect
@@ -41,6 +52,10 @@ func (ctx *upgradeCtx) Delete() ([]corev1.Pod, error) {
 	sortCandidates(candidates)
 
 	// Step 2: Apply predicates
+	pinnedToUnavailableNodes, err := podsPinnedToUnavailableNodes(ctx.parentCtx, ctx.client, ctx.ES, candidates)
+	if err != nil {
+		return nil, err
+	}
 	predicateContext := NewPredicateContext(
 		ctx.parentCtx,
 		ctx.ES,
@@ -51,6 +66,7 @@ func (ctx *upgradeCtx) Delete() ([]corev1.Pod, error) {
 		ctx.podsToUpgrade,
 		ctx.expectedMasters,
 		ctx.currentPods,
+		pinnedToUnavailableNodes,
 	)
 	log.V(1).Info("Applying predicates",
 		"maxUnavailableReached", maxUnavailableReached,
@@ -80,6 +96,15 @@ func (ctx *upgradeCtx) Delete() ([]corev1.Pod, error) {
 			return deletedPods, err
 		}
 		if readyToDelete, err := ctx.readyToDelete(podToDelete); err != nil || !readyToDelete {
+			if err == nil {
+				decisions.Fail(ctx.parentCtx, "node_shutdown_complete", fmt.Sprintf("Node shutdown of Pod %s is not complete yet", podToDelete.Name))
+				// only emit an event when the wait starts, it is then reported in the upgrade status
+				if !ctx.waitingForNodeShutdown(podToDelete.Name) {
+					ctx.reconcileState.AddEvent(corev1.EventTypeNormal, events.EventReasonDelayed,
+						fmt.Sprintf("Waiting for the node shutdown of Pod %s to complete before restarting it", podToDelete.Name))
+				}
+				ctx.reconcileState.RecordNodesToBeUpgradedWithMessage([]string{podToDelete.Name}, nodeShutdownPendingMsg)
+			}
 			return deletedPods, err
 		}
 
@@ -91,6 +116,17 @@ func (ctx *upgradeCtx) Delete() ([]corev1.Pod, error) {
 	return deletedPods, nil
 }
 
+// waitingForNodeShutdown returns true if the last reconciliation already reported the given node as waiting for its
+// node shutdown to complete.
+func (ctx *upgradeCtx) waitingForNodeShutdown(podName string) bool {
+	for _, node := range ctx.ES.Status.InProgressOperations.UpgradeOperation.Nodes {
+		if node.Name == podName {
+			return node.Message != nil && *node.Message == nodeShutdownPendingMsg
+		}
+	}
+	return false
+}
+
 // DeleteAll unconditionally deletes all upgradeable Pods after calling the node shutdown API and accounting for quorum
 // changes on older versions of Elasticsearch as applicable.
 func (ctx *upgradeCtx) DeleteAll() ([]corev1.Pod, error) {
@@ -120,8 +156,10 @@ func (ctx *upgradeCtx) DeleteAll() ([]corev1.Pod, error) {
 	if len(nonReadyPods) > 0 {
 		ulog.FromContext(ctx.parentCtx).Info("Not all Pods are ready for a full cluster upgrade", "pods", nonReadyPods, "namespace", ctx.ES.Namespace, "es_name", ctx.ES.Name)
 		ctx.reconcileState.RecordNodesToBeUpgradedWithMessage(k8s.PodNames(ctx.podsToUpgrade), "Not all Pods are ready for a full cluster upgrade")
+		decisions.Fail(ctx.parentCtx, "full_cluster_upgrade_ready", fmt.Sprintf("Not all Pods are ready for a full cluster upgrade: %s", strings.Join(nonReadyPods, ",")))
 		return nil, nil
 	}
+	decisions.Pass(ctx.parentCtx, "full_cluster_upgrade_ready", fmt.Sprintf("All Pods are ready for a full cluster upgrade: %s", strings.Join(k8s.PodNames(ctx.podsToUpgrade), ",")))
 
 	var deletedPods []corev1.Pod //nolint:prealloc
 	for _, podToDelete := range ctx.podsToUpgrade {
@@ -220,6 +258,7 @@ func deletePod(
 	msg string,
 ) error {
 	ulog.FromContext(ctx).Info(msg, "es_name", es.Name, "namespace", es.Namespace, "pod_name", pod.Name, "pod_uid", pod.UID)
+	decisions.Decide(ctx, "delete_pod", fmt.Sprintf("%s: %s", msg, pod.Name))
 	// The name of the Pod we want to delete is not enough as it may have been already deleted/recreated.
 	// The uid of the Pod we want to delete is used as a precondition to check that we actually delete the right one.
 	// We also check the version of the Pod resource, to make sure its status is the current one and we're not deleting
@@ -236,6 +275,7 @@ func deletePod(
 	expectations.ExpectDeletion(pod)
 	// Update status
 	reconcileState.RecordDeletedNode(pod.Name, msg)
+	reconcileState.AddEvent(corev1.EventTypeNormal, events.EventReasonUpgraded, fmt.Sprintf("%s: %s", msg, pod.Name))
 	return nil
 }
 
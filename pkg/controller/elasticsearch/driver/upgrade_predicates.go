@@ -6,11 +6,13 @@ package driver
 
 import (
 	"context"
+	"fmt"
 	"sort"
 
 	corev1 "k8s.io/api/core/v1"
 
 	esv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/elasticsearch/v1"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/decisions"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/labels"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/version"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/elasticsearch/client"
@@ -100,6 +102,8 @@ type PredicateContext struct {
 	ctx                    context.Context
 	// all Pods for the existing StatefulSets from k8s API
 	currentPods []corev1.Pod
+	// Pods pinned by their local volumes to a Kubernetes node which cannot host them anymore
+	pinnedToUnavailableNodes map[string]struct{}
 }
 
 // Predicate is a function that indicates if a Pod can be deleted (or not).
@@ -142,6 +146,7 @@ func NewPredicateContext(
 	podsToUpgrade []corev1.Pod,
 	masterNodesNames []string,
 	currentPods []corev1.Pod,
+	pinnedToUnavailableNodes map[string]struct{},
 ) PredicateContext {
 	return PredicateContext{
 		es:                       es,
@@ -153,6 +158,7 @@ func NewPredicateContext(
 		shardLister:              shardLister,
 		ctx:                      ctx,
 		currentPods:              currentPods,
+		pinnedToUnavailableNodes: pinnedToUnavailableNodes,
 	}
 }
 
@@ -173,6 +179,7 @@ Loop:
 		case predicateErr != nil:
 			// A predicate has failed on this Pod
 			failedPredicates[predicateErr.pod] = predicateErr.predicate
+			decisions.Fail(ctx.ctx, "upgrade_predicates", fmt.Sprintf("Pod %s cannot be restarted: predicate %s", predicateErr.pod, predicateErr.predicate))
 		default:
 			candidate := candidate
 			if label.IsMasterNode(candidate) || willBecomeMasterNode(candidate.Name, ctx.expectedMasterNodesNames) {
@@ -183,6 +190,7 @@ Loop:
 			delete(ctx.healthyPods, candidate.Name)
 			// Append to the deletedPods list
 			deletedPods = append(deletedPods, candidate)
+			decisions.Decide(ctx.ctx, "restart_pod", fmt.Sprintf("Pod %s passed all upgrade predicates", candidate.Name))
 			allowedDeletions--
 			if allowedDeletions <= 0 {
 				break Loop
@@ -558,6 +566,20 @@ var predicates = [...]Predicate{
 			return true, nil
 		},
 	},
+	{
+		// Pods using local volumes can only be scheduled again on the Kubernetes node holding their data. If that
+		// node has been cordoned or removed, a deleted Pod would stay Pending: keep it running instead.
+		name: "do_not_restart_pods_pinned_to_unavailable_nodes",
+		fn: func(
+			context PredicateContext,
+			candidate corev1.Pod,
+			_ []corev1.Pod,
+			_ bool,
+		) (bool, error) {
+			_, pinned := context.pinnedToUnavailableNodes[candidate.Name]
+			return !pinned, nil
+		},
+	},
 	{
 		name: "do_not_delete_all_members_of_a_tier",
 		fn: func(
@@ -14,6 +14,7 @@ import (
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/utils/pointer"
 	crlog "sigs.k8s.io/controller-runtime/pkg/log"
 
 	esv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/elasticsearch/v1"
@@ -22,6 +23,7 @@ import (
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/version"
 	esclient "github.com/elastic/cloud-on-k8s/v2/pkg/controller/elasticsearch/client"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/elasticsearch/hints"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/elasticsearch/label"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/elasticsearch/reconcile"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/elasticsearch/shutdown"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/elasticsearch/sset"
@@ -379,6 +381,39 @@ func Test_isVersionUpgrade(t *testing.T) {
 	}
 }
 
+func Test_isVersionUpgradeStarted(t *testing.T) {
+	podWithVersion := func(v string) corev1.Pod {
+		return corev1.Pod{ObjectMeta: metav1.ObjectMeta{Labels: map[string]string{label.VersionLabelName: v}}}
+	}
+	es := esv1.Elasticsearch{Spec: esv1.ElasticsearchSpec{Version: "8.5.0"}}
+
+	started, err := isVersionUpgradeStarted(es, []corev1.Pod{podWithVersion("8.4.3"), podWithVersion("8.4.3")})
+	require.NoError(t, err)
+	require.False(t, started)
+
+	started, err = isVersionUpgradeStarted(es, []corev1.Pod{podWithVersion("8.4.3"), podWithVersion("8.5.0")})
+	require.NoError(t, err)
+	require.True(t, started)
+
+	_, err = isVersionUpgradeStarted(es, []corev1.Pod{{}})
+	require.Error(t, err)
+}
+
+func Test_upgradeCtx_waitingForNodeShutdown(t *testing.T) {
+	es := esv1.Elasticsearch{Status: esv1.ElasticsearchStatus{InProgressOperations: esv1.InProgressOperations{
+		UpgradeOperation: esv1.UpgradeOperation{Nodes: []esv1.UpgradedNode{
+			{Name: "waiting", Status: "PENDING", Message: pointer.String(nodeShutdownPendingMsg)},
+			{Name: "predicate", Status: "PENDING", Message: pointer.String("Cannot restart node because of failed predicate")},
+			{Name: "pending", Status: "PENDING"},
+		}},
+	}}}
+	ctx := upgradeCtx{ES: es}
+	require.True(t, ctx.waitingForNodeShutdown("waiting"))
+	require.False(t, ctx.waitingForNodeShutdown("predicate"))
+	require.False(t, ctx.waitingForNodeShutdown("pending"))
+	require.False(t, ctx.waitingForNodeShutdown("unknown"))
+}
+
 func Test_defaultDriver_maybeCompleteNodeUpgrades(t *testing.T) {
 	esVersion := "8.1.0"
 	clusterName = "test-cluster"
@@ -6,6 +6,7 @@ package elasticsearch
 
 import (
 	"context"
+	"fmt"
 	"reflect"
 	"sync/atomic"
 
@@ -14,6 +15,7 @@ import (
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/client-go/tools/record"
 	"sigs.k8s.io/controller-runtime/pkg/controller"
@@ -23,9 +25,11 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 	"sigs.k8s.io/controller-runtime/pkg/source"
 
+	commonv1alpha1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/common/v1alpha1"
 	esv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/elasticsearch/v1"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/certificates"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/decisions"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/events"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/expectations"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/finalizer"
@@ -39,8 +43,10 @@ import (
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/elasticsearch/certificates/transport"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/elasticsearch/driver"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/elasticsearch/label"
+	eslicense "github.com/elastic/cloud-on-k8s/v2/pkg/controller/elasticsearch/license"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/elasticsearch/observer"
 	esreconcile "github.com/elastic/cloud-on-k8s/v2/pkg/controller/elasticsearch/reconcile"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/elasticsearch/stackconfig"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/elasticsearch/user"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/elasticsearch/validation"
 	esversion "github.com/elastic/cloud-on-k8s/v2/pkg/controller/elasticsearch/version"
@@ -126,6 +132,11 @@ func addWatches(c controller.Controller, r *ReconcileElasticsearch) error {
 		return err
 	}
 
+	// Dynamically watch the ConfigMaps referenced in the spec
+	if err := c.Watch(&source.Kind{Type: &corev1.ConfigMap{}}, r.dynamicWatches.ConfigMaps); err != nil {
+		return err
+	}
+
 	// Trigger a reconciliation when observers report a cluster health change
 	return c.Watch(observer.WatchClusterHealthChange(r.esObservers), reconciler.GenericEventHandler())
 }
@@ -166,9 +177,18 @@ func (r *ReconcileElasticsearch) Reconcile(ctx context.Context, request reconcil
 		return reconcile.Result{}, tracing.CaptureError(ctx, err)
 	}
 
+	// record the checks and decisions of this reconciliation if enabled
+	ctx, saveDecisions := decisions.Start(ctx, r.DecisionTraces, esv1.Kind, request.NamespacedName)
+	defer saveDecisions()
+
 	if common.IsUnmanaged(ctx, &es) {
 		log.Info("Object is currently not managed by this controller. Skipping reconciliation", "namespace", es.Namespace, "es_name", es.Name)
-		return reconcile.Result{}, nil
+		return reconcile.Result{}, tracing.CaptureError(ctx, r.reportPaused(ctx, es))
+	}
+
+	// do not reconcile resources last managed by a newer operator
+	if managed, err := common.ReconcileOperatorVersion(ctx, r.Client, &es); err != nil || !managed {
+		return reconcile.Result{}, tracing.CaptureError(ctx, err)
 	}
 
 	// Remove any previous Finalizers
@@ -183,6 +203,10 @@ func (r *ReconcileElasticsearch) Reconcile(ctx context.Context, request reconcil
 
 	// ReconciliationComplete is initially set to True until another condition with the same type is reported.
 	state.ReportCondition(esv1.ReconciliationComplete, corev1.ConditionTrue, "")
+	// only report ReconciliationPaused for clusters which were paused before, to leave the others untouched
+	if es.Status.Conditions.Index(esv1.ReconciliationPaused) >= 0 {
+		state.ReportCondition(esv1.ReconciliationPaused, corev1.ConditionFalse, "")
+	}
 
 	results := r.internalReconcile(ctx, es, state)
 
@@ -324,6 +348,27 @@ func (r *ReconcileElasticsearch) updateStatus(
 	return common.UpdateStatus(ctx, r.Client, cluster)
 }
 
+// reportPaused sets the ReconciliationPaused condition of an unmanaged Elasticsearch resource, leaving the rest of its
+// status as it was last reported while the reconciliation is paused.
+func (r *ReconcileElasticsearch) reportPaused(ctx context.Context, es esv1.Elasticsearch) error {
+	conditions := es.Status.Conditions.MergeWith(commonv1alpha1.Condition{
+		Type:               esv1.ReconciliationPaused,
+		Status:             corev1.ConditionTrue,
+		LastTransitionTime: metav1.Now(),
+		Message:            fmt.Sprintf("Reconciliation paused by the %s=false annotation", common.ManagedAnnotation),
+	})
+	if reflect.DeepEqual(conditions, es.Status.Conditions) {
+		return nil
+	}
+	es.Status.Conditions = conditions
+	err := common.UpdateStatus(ctx, r.Client, &es)
+	if apierrors.IsConflict(err) {
+		// the next reconciliation reports the condition on the latest version of the resource
+		return nil
+	}
+	return err
+}
+
 // annotateResource adds the orchestration hints annotation to the Elasticsearch resource. The purpose of this annotation
 // is to capture additional state about aspects of the operator's orchestration of Elasticsearch resources. Currently,
 // it captures whether transient settings are in use.  Future expansion is possible if deemed necessary.
@@ -356,11 +401,15 @@ func (r *ReconcileElasticsearch) annotateResource(
 // onDelete garbage collect resources when an Elasticsearch cluster is deleted
 func (r *ReconcileElasticsearch) onDelete(ctx context.Context, es types.NamespacedName) error {
 	r.expectations.RemoveCluster(es)
+	r.DecisionTraces.Forget(esv1.Kind, es)
 	r.esObservers.StopObserving(es)
+	eslicense.ForgetExpiry(es)
 	r.dynamicWatches.Secrets.RemoveHandlerForKey(keystore.SecureSettingsWatchName(es))
 	r.dynamicWatches.Secrets.RemoveHandlerForKey(certificates.CertificateWatchKey(esv1.ESNamer, es.Name))
 	r.dynamicWatches.Secrets.RemoveHandlerForKey(transport.CustomTransportCertsWatchKey(es))
 	r.dynamicWatches.Secrets.RemoveHandlerForKey(user.UserProvidedRolesWatchName(es))
 	r.dynamicWatches.Secrets.RemoveHandlerForKey(user.UserProvidedFileRealmWatchName(es))
+	r.dynamicWatches.Secrets.RemoveHandlerForKey(user.NativeUsersPasswordsWatchName(es))
+	r.dynamicWatches.ConfigMaps.RemoveHandlerForKey(stackconfig.ConfigMapsWatchName(es))
 	return reconciler.GarbageCollectSoftOwnedSecrets(ctx, r.Client, es, esv1.Kind)
 }
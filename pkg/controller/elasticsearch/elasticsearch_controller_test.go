@@ -114,7 +114,7 @@ func TestReconcileElasticsearch_Reconcile(t *testing.T) {
 		expected        esv1.Elasticsearch
 	}{
 		{
-			name: "unmanaged ES has no error, and no observedGeneration update",
+			name: "unmanaged ES has no error, no observedGeneration update, and reports the paused reconciliation",
 			k8sClientFields: k8sClientFields{
 				[]runtime.Object{
 					newBuilder("testES", "test").
@@ -134,7 +134,53 @@ func TestReconcileElasticsearch_Reconcile(t *testing.T) {
 			expected: newBuilder("testES", "test").
 				WithGeneration(2).
 				WithAnnotations(map[string]string{common.ManagedAnnotation: "false"}).
-				WithStatus(esv1.ElasticsearchStatus{ObservedGeneration: 1}).BuildAndCopy(),
+				WithStatus(esv1.ElasticsearchStatus{
+					ObservedGeneration: 1,
+					Conditions: commonv1alpha1.Conditions{commonv1alpha1.Condition{
+						Type:    esv1.ReconciliationPaused,
+						Status:  "True",
+						Message: "Reconciliation paused by the eck.k8s.elastic.co/managed=false annotation",
+					}},
+				}).BuildAndCopy(),
+		},
+		{
+			name: "ES managed again reports the resumed reconciliation",
+			k8sClientFields: k8sClientFields{
+				[]runtime.Object{
+					newBuilder("testES", "test").
+						WithGeneration(2).
+						WithVersion("invalid").
+						WithAnnotations(map[string]string{hints.OrchestrationsHintsAnnotation: `{"no_transient_settings":false}`}).
+						WithStatus(esv1.ElasticsearchStatus{
+							ObservedGeneration: 1,
+							Conditions:         commonv1alpha1.Conditions{commonv1alpha1.Condition{Type: esv1.ReconciliationPaused, Status: "True"}},
+						}).Build()},
+			},
+			args: args{
+				request: reconcile.Request{
+					NamespacedName: types.NamespacedName{
+						Name:      "testES",
+						Namespace: "test",
+					},
+				},
+			},
+			wantErr: false,
+			expected: newBuilder("testES", "test").
+				WithGeneration(2).
+				WithVersion("invalid").
+				WithAnnotations(map[string]string{hints.OrchestrationsHintsAnnotation: `{"no_transient_settings":false}`}).
+				WithStatus(
+					esv1.ElasticsearchStatus{
+						ObservedGeneration: 2,
+						Phase:              esv1.ElasticsearchResourceInvalid,
+						Health:             esv1.ElasticsearchUnknownHealth,
+						Conditions: commonv1alpha1.Conditions{
+							commonv1alpha1.Condition{Type: esv1.ReconciliationPaused, Status: "False"},
+							commonv1alpha1.Condition{Type: "ReconciliationComplete", Status: "True"},
+						},
+						InProgressOperations: noInProgressOperations,
+					},
+				).BuildAndCopy(),
 		},
 		{
 			name: "ES with too long name, fails initial reconcile, but has observedGeneration updated",
@@ -7,6 +7,7 @@ package initcontainer
 import (
 	corev1 "k8s.io/api/core/v1"
 
+	esv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/elasticsearch/v1"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/keystore"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/volume"
 )
@@ -16,6 +17,10 @@ const (
 	PrepareFilesystemContainerName = "elastic-internal-init-filesystem"
 	// SuspendContainerName is the name of the container that is used to suspend Elasticsearch if requested by the user.
 	SuspendContainerName = "elastic-internal-suspend"
+	// PluginsContainerName is the name of the container that installs the plugins declared in the specification.
+	PluginsContainerName = "elastic-internal-install-plugins"
+	// SysctlContainerName is the name of the container that sets the vm.max_map_count kernel setting.
+	SysctlContainerName = "elastic-internal-sysctl"
 )
 
 // NewInitContainers creates init containers according to the given parameters
@@ -23,14 +28,24 @@ func NewInitContainers(
 	transportCertificatesVolume volume.SecretVolume,
 	keystoreResources *keystore.Resources,
 	nodeLabelsAsAnnotations []string,
+	plugins []esv1.Plugin,
+	setVMMaxMapCount bool,
 ) ([]corev1.Container, error) {
 	var containers []corev1.Container
+	if setVMMaxMapCount {
+		containers = append(containers, NewSysctlInitContainer())
+	}
+
 	prepareFsContainer, err := NewPrepareFSInitContainer(transportCertificatesVolume, nodeLabelsAsAnnotations)
 	if err != nil {
 		return nil, err
 	}
 	containers = append(containers, prepareFsContainer)
 
+	if len(plugins) > 0 {
+		containers = append(containers, NewPluginsInitContainer(plugins))
+	}
+
 	if keystoreResources != nil {
 		containers = append(containers, keystoreResources.InitContainer)
 	}
@@ -9,6 +9,7 @@ import (
 
 	"github.com/stretchr/testify/assert"
 
+	esv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/elasticsearch/v1"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/keystore"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/volume"
 )
@@ -16,6 +17,8 @@ import (
 func TestNewInitContainers(t *testing.T) {
 	type args struct {
 		keystoreResources *keystore.Resources
+		plugins           []esv1.Plugin
+		setVMMaxMapCount  bool
 	}
 	tests := []struct {
 		name                       string
@@ -36,10 +39,24 @@ func TestNewInitContainers(t *testing.T) {
 			},
 			expectedNumberOfContainers: 2,
 		},
+		{
+			name: "with plugins",
+			args: args{
+				plugins: []esv1.Plugin{{Name: "analysis-icu"}},
+			},
+			expectedNumberOfContainers: 3,
+		},
+		{
+			name: "with vm.max_map_count",
+			args: args{
+				setVMMaxMapCount: true,
+			},
+			expectedNumberOfContainers: 3,
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			containers, err := NewInitContainers(volume.SecretVolume{}, tt.args.keystoreResources, []string{})
+			containers, err := NewInitContainers(volume.SecretVolume{}, tt.args.keystoreResources, []string{}, tt.args.plugins, tt.args.setVMMaxMapCount)
 			assert.NoError(t, err)
 			assert.Equal(t, tt.expectedNumberOfContainers, len(containers))
 		})
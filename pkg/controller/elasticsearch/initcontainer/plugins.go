@@ -0,0 +1,80 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package initcontainer
+
+import (
+	"fmt"
+	"path"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+
+	esv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/elasticsearch/v1"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/defaults"
+)
+
+const (
+	PluginBinPath = "/usr/share/elasticsearch/bin/elasticsearch-plugin"
+	// installedPluginsFile keeps track of the plugins already installed in the shared plugins volume, so that the
+	// init container does not fail when executed more than once.
+	installedPluginsFile = "elastic-internal-installed-plugins"
+)
+
+// pluginsScriptHeader installs a plugin, by name or from a URL. Plugin archives with a checksum are downloaded and
+// verified before being installed from the local filesystem.
+var pluginsScriptHeader = fmt.Sprintf(`#!/usr/bin/env bash
+set -eu
+
+installed=%s
+touch "$installed"
+
+install_plugin() {
+	local plugin=$1 sha512=$2
+	if grep -Fxq "$plugin" "$installed"; then
+		echo "Plugin $plugin already installed"
+		return
+	fi
+	local source=$plugin
+	if [[ -n "$sha512" ]]; then
+		local archive
+		archive="$(mktemp -d)/plugin.zip"
+		echo "Downloading $plugin"
+		curl -sSfL -o "$archive" "$plugin"
+		echo "$sha512  $archive" | sha512sum -c -
+		source="file://$archive"
+	fi
+	echo "Installing plugin $plugin"
+	%s install --batch "$source"
+	echo "$plugin" >> "$installed"
+}
+`, path.Join(EsConfigSharedVolume.ContainerMountPath, installedPluginsFile), PluginBinPath)
+
+// RenderPluginsScript renders the script installing the given plugins.
+func RenderPluginsScript(plugins []esv1.Plugin) string {
+	script := strings.Builder{}
+	script.WriteString(pluginsScriptHeader)
+	for _, plugin := range plugins {
+		script.WriteString(fmt.Sprintf("\ninstall_plugin %s %s", shellQuote(plugin.Name), shellQuote(plugin.SHA512)))
+	}
+	script.WriteString("\n")
+	return script.String()
+}
+
+// shellQuote quotes the given string to be used as a single argument in a shell script.
+func shellQuote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
+
+// NewPluginsInitContainer creates an init container installing the given plugins into the plugins volume shared with
+// the Elasticsearch container. It inherits the image, the volume mounts and the resources of the main container,
+// which already contains the plugins bundled in the image at this point.
+func NewPluginsInitContainer(plugins []esv1.Plugin) corev1.Container {
+	return corev1.Container{
+		ImagePullPolicy: corev1.PullIfNotPresent,
+		Name:            PluginsContainerName,
+		Env:             defaults.PodDownwardEnvVars(),
+		Command:         []string{"bash", "-c", RenderPluginsScript(plugins)},
+	}
+}
@@ -0,0 +1,52 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package initcontainer
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	esv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/elasticsearch/v1"
+)
+
+func TestRenderPluginsScript(t *testing.T) {
+	tests := []struct {
+		name    string
+		plugins []esv1.Plugin
+		want    []string
+	}{
+		{
+			name:    "official plugin",
+			plugins: []esv1.Plugin{{Name: "analysis-icu"}},
+			want:    []string{"install_plugin 'analysis-icu' ''"},
+		},
+		{
+			name: "plugins from URLs with and without checksum",
+			plugins: []esv1.Plugin{
+				{Name: "https://example.com/plugin.zip", SHA512: "abcd"},
+				{Name: "file:///mnt/plugins/plugin.zip"},
+			},
+			want: []string{
+				"install_plugin 'https://example.com/plugin.zip' 'abcd'",
+				"install_plugin 'file:///mnt/plugins/plugin.zip' ''",
+			},
+		},
+		{
+			name:    "quotes are escaped",
+			plugins: []esv1.Plugin{{Name: "file:///mnt/it's.zip"}},
+			want:    []string{`install_plugin 'file:///mnt/it'\''s.zip' ''`},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			script := RenderPluginsScript(tt.plugins)
+			assert.True(t, strings.HasPrefix(script, pluginsScriptHeader))
+			lines := strings.Split(strings.TrimSpace(strings.TrimPrefix(script, pluginsScriptHeader)), "\n")
+			assert.Equal(t, tt.want, lines)
+		})
+	}
+}
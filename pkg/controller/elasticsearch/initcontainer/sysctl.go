@@ -0,0 +1,41 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package initcontainer
+
+import (
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/utils/pointer"
+)
+
+// MinVMMaxMapCount is the minimum value of the vm.max_map_count kernel setting required by Elasticsearch when using mmap.
+// See https://www.elastic.co/guide/en/elasticsearch/reference/current/vm-max-map-count.html.
+const MinVMMaxMapCount = 262144
+
+// SysctlScript raises vm.max_map_count to the minimum required value. Nodes configured with a higher value are left untouched.
+var SysctlScript = fmt.Sprintf(`#!/usr/bin/env bash
+set -eu
+
+current=$(sysctl -n vm.max_map_count)
+if [[ "$current" -lt %[1]d ]]; then
+	echo "Setting vm.max_map_count from $current to %[1]d"
+	sysctl -w vm.max_map_count=%[1]d
+fi
+`, MinVMMaxMapCount)
+
+// NewSysctlInitContainer creates an init container setting the vm.max_map_count kernel setting of the Kubernetes node.
+// Kernel settings are not namespaced: the container has to run as a privileged root user.
+func NewSysctlInitContainer() corev1.Container {
+	return corev1.Container{
+		ImagePullPolicy: corev1.PullIfNotPresent,
+		Name:            SysctlContainerName,
+		Command:         []string{"bash", "-c", SysctlScript},
+		SecurityContext: &corev1.SecurityContext{
+			Privileged: pointer.Bool(true),
+			RunAsUser:  pointer.Int64(0),
+		},
+	}
+}
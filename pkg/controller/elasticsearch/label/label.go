@@ -92,6 +92,25 @@ func IsIngestNodeSet(statefulSet appsv1.StatefulSet) bool {
 	return NodeTypesIngestLabelName.HasValue(true, statefulSet.Spec.Template.Labels)
 }
 
+// HasDataTier returns true if the given labels specify a node holding data of the given data tier, either through the
+// role of the data tier or through the generic data role.
+func HasDataTier(dataTier esv1.DataTier, podLabels map[string]string) bool {
+	if NodeTypesDataLabelName.HasValue(true, podLabels) {
+		return true
+	}
+	switch dataTier {
+	case esv1.DataTierHot:
+		return NodeTypesDataHotLabelName.HasValue(true, podLabels)
+	case esv1.DataTierWarm:
+		return NodeTypesDataWarmLabelName.HasValue(true, podLabels)
+	case esv1.DataTierCold:
+		return NodeTypesDataColdLabelName.HasValue(true, podLabels)
+	case esv1.DataTierFrozen:
+		return NodeTypesDataFrozenLabelName.HasValue(true, podLabels)
+	}
+	return false
+}
+
 func FilterMasterNodePods(pods []corev1.Pod) []corev1.Pod {
 	masters := []corev1.Pod{}
 	for _, pod := range pods {
@@ -38,13 +38,16 @@ func isBasic(l esclient.License) bool {
 	return l.Type == string(esclient.ElasticsearchLicenseTypeBasic)
 }
 
+// applyLinkedLicense applies the cluster license. If there is none, it starts a trial if requested, or reverts to a basic
+// license. It returns true if the license of the cluster was changed.
 func applyLinkedLicense(
 	ctx context.Context,
 	c k8s.Client,
 	esCluster types.NamespacedName,
 	updater esclient.LicenseClient,
 	currentLicense esclient.License,
-) error {
+	trialRequested bool,
+) (bool, error) {
 	// get the expected license
 	// the underlying assumption here is that either a user or a
 	// license controller has created a cluster license in the
@@ -59,14 +62,17 @@ func applyLinkedLicense(
 		&license,
 	)
 	if err != nil && !apierrors.IsNotFound(err) {
-		return err
+		return false, err
 	}
 	if err != nil && apierrors.IsNotFound(err) {
 		// no license expected, let's look at the current cluster license
 		switch {
+		case isBasic(currentLicense) && trialRequested:
+			// start a self-generated trial in Elasticsearch, this can only be done once.
+			return true, pkgerrors.Wrap(startTrial(ctx, updater, esCluster), "failed to start trial")
 		case isBasic(currentLicense):
 			// nothing to do
-			return nil
+			return false, nil
 		case isTrial(currentLicense):
 			// Elasticsearch reports a trial license, but there's no ECK enterprise trial requested.
 			// This can be the case if:
@@ -76,22 +82,22 @@ func applyLinkedLicense(
 			// we tolerate it to avoid a bad user experience because trials can only be started once.
 			ulog.FromContext(ctx).V(1).Info("Preserving existing stack-level trial license",
 				"namespace", esCluster.Namespace, "es_name", esCluster.Name)
-			return nil
+			return false, nil
 		default:
 			// revert the current license to basic
-			return startBasic(ctx, updater)
+			return true, startBasic(ctx, updater)
 		}
 	}
 
 	bytes, err := commonlicense.FetchLicenseData(license.Data)
 	if err != nil {
-		return err
+		return false, err
 	}
 
 	var desired esclient.License
 	err = json.Unmarshal(bytes, &desired)
 	if err != nil {
-		return pkgerrors.Wrap(err, "no valid license found in license secret")
+		return false, pkgerrors.Wrap(err, "no valid license found in license secret")
 	}
 	return updateLicense(ctx, esCluster, updater, currentLicense, desired)
 }
@@ -105,16 +111,17 @@ func startBasic(ctx context.Context, updater esclient.LicenseClient) error {
 	return pkgerrors.Wrap(err, "failed to revert to basic")
 }
 
-// updateLicense make the call to Elasticsearch to set the license. This function exists mainly to facilitate testing.
+// updateLicense make the call to Elasticsearch to set the license, and returns true if the license was not already
+// applied. This function exists mainly to facilitate testing.
 func updateLicense(
 	ctx context.Context,
 	esCluster types.NamespacedName,
 	updater esclient.LicenseClient,
 	current esclient.License,
 	desired esclient.License,
-) error {
+) (bool, error) {
 	if current.UID == desired.UID || (isTrial(current) && current.Type == desired.Type) {
-		return nil // we are done already applied
+		return false, nil // we are done already applied
 	}
 	request := esclient.LicenseUpdateRequest{
 		Licenses: []esclient.License{
@@ -124,17 +131,17 @@ func updateLicense(
 
 	if isECKManagedTrial(desired) {
 		// start a self-generated trial in Elasticsearch, this can only be done once.
-		return pkgerrors.Wrap(startTrial(ctx, updater, esCluster), "failed to start trial")
+		return true, pkgerrors.Wrap(startTrial(ctx, updater, esCluster), "failed to start trial")
 	}
 
 	response, err := updater.UpdateLicense(ctx, request)
 	if err != nil {
-		return pkgerrors.Wrap(err, fmt.Sprintf("failed to update license to %s", desired.Type))
+		return false, pkgerrors.Wrap(err, fmt.Sprintf("failed to update license to %s", desired.Type))
 	}
 	if !response.IsSuccess() {
-		return pkgerrors.Errorf("failed to apply license: %s", response.LicenseStatus)
+		return false, pkgerrors.Errorf("failed to apply license: %s", response.LicenseStatus)
 	}
-	return nil
+	return true, nil
 }
 
 // startTrial starts the trial license after checking that the trial is not yet activated by directly hitting the
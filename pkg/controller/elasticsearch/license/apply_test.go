@@ -110,7 +110,7 @@ func Test_updateLicense(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			c := esclient.NewMockClient(version.MustParse("6.8.0"), tt.reqFn)
-			if err := updateLicense(context.Background(), types.NamespacedName{}, c, tt.args.current, tt.args.desired); (err != nil) != tt.wantErr {
+			if _, err := updateLicense(context.Background(), types.NamespacedName{}, c, tt.args.current, tt.args.desired); (err != nil) != tt.wantErr {
 				t.Errorf("updateLicense() error = %v, wantErr %v", err, tt.wantErr)
 			}
 		})
@@ -127,6 +127,7 @@ func Test_applyLinkedLicense(t *testing.T) {
 		initialObjs      []runtime.Object
 		currentLicense   esclient.License
 		errors           map[client.ObjectKey]error
+		trialRequested   bool
 		wantErr          bool
 		clientAssertions func(updater fakeLicenseUpdater)
 	}{
@@ -164,6 +165,24 @@ func Test_applyLinkedLicense(t *testing.T) {
 				require.False(t, updater.startBasicCalled, "should not call start_basic if already basic")
 			},
 		},
+		{
+			name:           "no error: no license found, start the requested trial",
+			wantErr:        false,
+			currentLicense: esclient.License{Type: string(esclient.ElasticsearchLicenseTypeBasic)},
+			trialRequested: true,
+			clientAssertions: func(updater fakeLicenseUpdater) {
+				require.True(t, updater.startTrialCalled, "should start the trial")
+			},
+		},
+		{
+			name:           "no error: no license found, trial requested but already running",
+			wantErr:        false,
+			currentLicense: esclient.License{Type: string(esclient.ElasticsearchLicenseTypeTrial)},
+			trialRequested: true,
+			clientAssertions: func(updater fakeLicenseUpdater) {
+				require.False(t, updater.startTrialCalled, "should not start the trial again")
+			},
+		},
 		{
 			name:           "no error: no license found but tolerate a cluster level trial",
 			wantErr:        false,
@@ -217,12 +236,13 @@ func Test_applyLinkedLicense(t *testing.T) {
 				errors: tt.errors,
 			}
 			updater := fakeLicenseUpdater{license: tt.currentLicense}
-			if err := applyLinkedLicense(
+			if _, err := applyLinkedLicense(
 				context.Background(),
 				c,
 				clusterName,
 				&updater,
 				tt.currentLicense,
+				tt.trialRequested,
 			); (err != nil) != tt.wantErr {
 				t.Errorf("applyLinkedLicense() error = %v, wantErr %v", err, tt.wantErr)
 			}
@@ -325,10 +345,12 @@ func (f *fakeInvalidLicenseUpdater) GetLicense(ctx context.Context) (esclient.Li
 type fakeLicenseUpdater struct {
 	license             esclient.License
 	startBasicCalled    bool
+	startTrialCalled    bool
 	updateLicenseCalled bool
 }
 
 func (f *fakeLicenseUpdater) StartTrial(ctx context.Context) (esclient.StartTrialResponse, error) {
+	f.startTrialCalled = true
 	return esclient.StartTrialResponse{
 		Acknowledged:    true,
 		TrialWasStarted: true,
@@ -6,24 +6,78 @@ package license
 
 import (
 	"context"
+	"fmt"
+	"time"
 
 	"github.com/pkg/errors"
+	"github.com/prometheus/client_golang/prometheus"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
 
 	esv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/elasticsearch/v1"
+	commonlicense "github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/license"
 	esclient "github.com/elastic/cloud-on-k8s/v2/pkg/controller/elasticsearch/client"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/utils/k8s"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/utils/metrics"
 )
 
-// Reconcile reconciles the current Elasticsearch license with the desired one.
+// Reconcile reconciles the current Elasticsearch license with the desired one. It returns the license of the cluster
+// once reconciled.
 func Reconcile(
 	ctx context.Context,
 	c k8s.Client,
 	esCluster esv1.Elasticsearch,
 	clusterClient esclient.Client,
 	currentLicense esclient.License,
-) error {
+) (esclient.License, error) {
 	clusterName := k8s.ExtractNamespacedName(&esCluster)
-	return applyLinkedLicense(ctx, c, clusterName, clusterClient, currentLicense)
+	trialRequested := esCluster.Annotations[commonlicense.StartTrialAnnotation] == "true"
+	changed, err := applyLinkedLicense(ctx, c, clusterName, clusterClient, currentLicense, trialRequested)
+	if err != nil || !changed {
+		return currentLicense, err
+	}
+	// read back the license, which is not known in advance when reverting to basic or starting a trial
+	return clusterClient.GetLicense(ctx)
+}
+
+// ReportExpiry exposes the expiry date of the license of the given cluster as a metric, and returns a warning message if
+// the license expires within the given period. Trial licenses, which are short-lived by design, are not warned about.
+func ReportExpiry(es types.NamespacedName, l esclient.License, warningPeriod time.Duration, now time.Time) (string, bool) {
+	// the license type is part of the labels, remove the series of the previous license
+	ForgetExpiry(es)
+	if l.ExpiryDateInMillis <= 0 {
+		return "", false
+	}
+	metrics.ElasticsearchLicenseExpiryGauge.With(prometheus.Labels{
+		metrics.NamespaceLabel:    es.Namespace,
+		metrics.NameLabel:         es.Name,
+		metrics.LicenseLevelLabel: l.Type,
+	}).Set(float64(l.ExpiryTime().Unix()))
+	if l.Type == string(esclient.ElasticsearchLicenseTypeTrial) || !commonlicense.ExpiresWithin(l.ExpiryTime(), warningPeriod, now) {
+		return "", false
+	}
+	return fmt.Sprintf("Elasticsearch license of type %s expires on %s", l.Type, l.ExpiryTime().UTC().Format(time.RFC3339)), true
+}
+
+// ForgetExpiry removes the expiry date of the license of the given cluster from the metrics.
+func ForgetExpiry(es types.NamespacedName) {
+	metrics.ElasticsearchLicenseExpiryGauge.DeletePartialMatch(prometheus.Labels{
+		metrics.NamespaceLabel: es.Namespace,
+		metrics.NameLabel:      es.Name,
+	})
+}
+
+// NewLicenseStatus returns the status of the given license, as reported in the status of the Elasticsearch resource.
+func NewLicenseStatus(l esclient.License) *esv1.LicenseStatus {
+	if l.Type == "" {
+		return nil
+	}
+	status := esv1.LicenseStatus{Type: l.Type, UID: l.UID, Status: l.Status}
+	if l.ExpiryDateInMillis > 0 {
+		expiry := metav1.NewTime(l.ExpiryTime())
+		status.ExpiryTime = &expiry
+	}
+	return &status
 }
 
 // CheckElasticsearchLicense checks that Elasticsearch is licensed, which ensures that the operator is communicating
@@ -0,0 +1,133 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package nodespec
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+
+	esv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/elasticsearch/v1"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/version"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/elasticsearch/settings"
+)
+
+// defaultHeapSizeAnnotationName marks the Pod templates whose heap size is set by the operator, so that the heap of
+// the existing nodes is not changed.
+const defaultHeapSizeAnnotationName = "elasticsearch.k8s.elastic.co/default-heap-size"
+
+var (
+	// minAutoHeapSizeVersion is the first version of Elasticsearch sizing the JVM heap according to the memory
+	// available to the container.
+	minAutoHeapSizeVersion = version.From(7, 11, 0)
+	// maxHeapSize keeps the heap below the threshold of compressed ordinary object pointers.
+	maxHeapSize = resource.MustParse("31Gi")
+	// heapSizeOptions are the prefixes of the JVM options setting the heap size.
+	heapSizeOptions = []string{
+		"-Xms",
+		"-Xmx",
+		"-XX:InitialHeapSize=",
+		"-XX:MaxHeapSize=",
+		"-XX:MinHeapSize=",
+		"-XX:InitialRAMPercentage=",
+		"-XX:MaxRAMPercentage=",
+		"-XX:MinRAMPercentage=",
+	}
+)
+
+// heapSize returns the heap size for the given memory limit: half of the memory, up to 31Gi.
+func heapSize(memoryLimit resource.Quantity) int64 {
+	heap := memoryLimit.Value() / 2
+	if heap > maxHeapSize.Value() {
+		heap = maxHeapSize.Value()
+	}
+	return heap
+}
+
+// setsHeapSize returns true if one of the given JVM options sets the heap size.
+func setsHeapSize(jvmOptions []string) bool {
+	for _, option := range jvmOptions {
+		for _, prefix := range heapSizeOptions {
+			if strings.HasPrefix(strings.TrimSpace(option), prefix) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// heapSizeSetByUser returns true if the heap size may be set by the user: in the JVM options of the NodeSet, in the
+// ES_JAVA_OPTS environment variable of the Elasticsearch container, or in JVM options files mounted by the user in
+// the jvm.options.d directory, whose content is not known to the operator.
+func heapSizeSetByUser(nodeSet esv1.NodeSet, esContainer corev1.Container) bool {
+	if setsHeapSize(nodeSet.JVMOptions) {
+		return true
+	}
+	for _, envVar := range esContainer.Env {
+		if envVar.Name != settings.EnvEsJavaOpts {
+			continue
+		}
+		if envVar.ValueFrom != nil || setsHeapSize(strings.Fields(envVar.Value)) {
+			return true
+		}
+	}
+	for _, mount := range esContainer.VolumeMounts {
+		if mount.Name == settings.JVMOptionsVolumeName {
+			// JVM options of the NodeSet
+			continue
+		}
+		if rel, err := filepath.Rel(settings.JVMOptionsVolumeMountPath, mount.MountPath); err == nil && !strings.HasPrefix(rel, "..") {
+			return true
+		}
+	}
+	return false
+}
+
+// withDefaultHeapSize sets the JVM heap size of Elasticsearch versions which do not size it automatically, to half of
+// the memory limit of the Elasticsearch container instead of the default 1GB, unless the heap size is set by the user.
+// The Pod template is annotated accordingly.
+func withDefaultHeapSize(podTemplate *corev1.PodTemplateSpec, nodeSet esv1.NodeSet, ver version.Version) {
+	if ver.GTE(minAutoHeapSizeVersion) {
+		return
+	}
+	if podTemplate.Annotations == nil {
+		podTemplate.Annotations = make(map[string]string)
+	}
+	podTemplate.Annotations[defaultHeapSizeAnnotationName] = "true"
+
+	for c, esContainer := range podTemplate.Spec.Containers {
+		if esContainer.Name != esv1.ElasticsearchContainerName {
+			continue
+		}
+		memoryLimit, hasLimit := esContainer.Resources.Limits[corev1.ResourceMemory]
+		if !hasLimit || memoryLimit.IsZero() || heapSizeSetByUser(nodeSet, esContainer) {
+			continue
+		}
+		heapMiB := heapSize(memoryLimit) / (1024 * 1024)
+		heapParams := fmt.Sprintf("-Xms%dm -Xmx%dm", heapMiB, heapMiB)
+
+		found := false
+		for e, envVar := range esContainer.Env {
+			if envVar.Name != settings.EnvEsJavaOpts {
+				continue
+			}
+			found = true
+			value := heapParams
+			if envVar.Value != "" {
+				value = heapParams + " " + envVar.Value
+			}
+			podTemplate.Spec.Containers[c].Env[e].Value = value
+		}
+		if !found {
+			podTemplate.Spec.Containers[c].Env = append(
+				podTemplate.Spec.Containers[c].Env,
+				corev1.EnvVar{Name: settings.EnvEsJavaOpts, Value: heapParams},
+			)
+		}
+	}
+}
@@ -0,0 +1,173 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package nodespec
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	esv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/elasticsearch/v1"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/version"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/elasticsearch/settings"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/elasticsearch/sset"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/utils/k8s"
+)
+
+func Test_withDefaultHeapSize(t *testing.T) {
+	tests := []struct {
+		name           string
+		version        string
+		memoryLimit    string
+		env            []corev1.EnvVar
+		volumeMounts   []corev1.VolumeMount
+		jvmOptions     []string
+		want           []corev1.EnvVar
+		wantAnnotation bool
+	}{
+		{
+			name:           "heap is set to half of the memory limit",
+			version:        "7.10.0",
+			memoryLimit:    "4Gi",
+			want:           []corev1.EnvVar{{Name: settings.EnvEsJavaOpts, Value: "-Xms2048m -Xmx2048m"}},
+			wantAnnotation: true,
+		},
+		{
+			name:           "heap is capped to 31GB",
+			version:        "7.10.0",
+			memoryLimit:    "128Gi",
+			want:           []corev1.EnvVar{{Name: settings.EnvEsJavaOpts, Value: "-Xms31744m -Xmx31744m"}},
+			wantAnnotation: true,
+		},
+		{
+			name:           "heap is merged with user-provided JVM parameters",
+			version:        "6.8.0",
+			memoryLimit:    "2Gi",
+			env:            []corev1.EnvVar{{Name: settings.EnvEsJavaOpts, Value: "-XX:+UseG1GC"}},
+			want:           []corev1.EnvVar{{Name: settings.EnvEsJavaOpts, Value: "-Xms1024m -Xmx1024m -XX:+UseG1GC"}},
+			wantAnnotation: true,
+		},
+		{
+			name:           "heap set by the user in ES_JAVA_OPTS is not overridden",
+			version:        "7.10.0",
+			memoryLimit:    "4Gi",
+			env:            []corev1.EnvVar{{Name: settings.EnvEsJavaOpts, Value: "-Xms1g -Xmx1g"}},
+			want:           []corev1.EnvVar{{Name: settings.EnvEsJavaOpts, Value: "-Xms1g -Xmx1g"}},
+			wantAnnotation: true,
+		},
+		{
+			name:           "heap percentage set by the user in ES_JAVA_OPTS is not overridden",
+			version:        "7.10.0",
+			memoryLimit:    "4Gi",
+			env:            []corev1.EnvVar{{Name: settings.EnvEsJavaOpts, Value: "-XX:MaxRAMPercentage=75"}},
+			want:           []corev1.EnvVar{{Name: settings.EnvEsJavaOpts, Value: "-XX:MaxRAMPercentage=75"}},
+			wantAnnotation: true,
+		},
+		{
+			name:           "ES_JAVA_OPTS from a reference is not overridden",
+			version:        "7.10.0",
+			memoryLimit:    "4Gi",
+			env:            []corev1.EnvVar{{Name: settings.EnvEsJavaOpts, ValueFrom: &corev1.EnvVarSource{}}},
+			want:           []corev1.EnvVar{{Name: settings.EnvEsJavaOpts, ValueFrom: &corev1.EnvVarSource{}}},
+			wantAnnotation: true,
+		},
+		{
+			name:           "heap set by the user in the JVM options of the NodeSet is not overridden",
+			version:        "7.10.0",
+			memoryLimit:    "4Gi",
+			jvmOptions:     []string{"-Xmx3g"},
+			want:           nil,
+			wantAnnotation: true,
+		},
+		{
+			name:        "JVM options of the NodeSet without heap size",
+			version:     "7.10.0",
+			memoryLimit: "4Gi",
+			jvmOptions:  []string{"-XX:+UseG1GC"},
+			volumeMounts: []corev1.VolumeMount{
+				{Name: settings.JVMOptionsVolumeName, MountPath: settings.JVMOptionsVolumeMountPath},
+			},
+			want:           []corev1.EnvVar{{Name: settings.EnvEsJavaOpts, Value: "-Xms2048m -Xmx2048m"}},
+			wantAnnotation: true,
+		},
+		{
+			name:        "JVM options file mounted by the user in jvm.options.d: heap is not set",
+			version:     "7.10.0",
+			memoryLimit: "4Gi",
+			volumeMounts: []corev1.VolumeMount{
+				{Name: "heap", MountPath: "/usr/share/elasticsearch/config/jvm.options.d/heap.options", SubPath: "heap.options"},
+			},
+			want:           nil,
+			wantAnnotation: true,
+		},
+		{
+			name:           "no memory limit: heap is not set",
+			version:        "7.10.0",
+			memoryLimit:    "",
+			want:           nil,
+			wantAnnotation: true,
+		},
+		{
+			name:        "since 7.11.0, heap is sized by Elasticsearch",
+			version:     "7.11.0",
+			memoryLimit: "4Gi",
+			want:        nil,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			esContainer := corev1.Container{Name: esv1.ElasticsearchContainerName, Env: tt.env, VolumeMounts: tt.volumeMounts}
+			if tt.memoryLimit != "" {
+				esContainer.Resources.Limits = corev1.ResourceList{corev1.ResourceMemory: resource.MustParse(tt.memoryLimit)}
+			}
+			podTemplate := corev1.PodTemplateSpec{Spec: corev1.PodSpec{Containers: []corev1.Container{esContainer}}}
+			withDefaultHeapSize(&podTemplate, esv1.NodeSet{JVMOptions: tt.jvmOptions}, version.MustParse(tt.version))
+			assert.Equal(t, tt.want, podTemplate.Spec.Containers[0].Env)
+			assert.Equal(t, tt.wantAnnotation, podTemplate.Annotations[defaultHeapSizeAnnotationName] == "true")
+		})
+	}
+}
+
+func TestBuildStatefulSet_DefaultHeapSize(t *testing.T) {
+	es := *sampleES.DeepCopy()
+	nodeSet := es.Spec.NodeSets[0]
+	ssetName := es.StatefulSetName(nodeSet.Name)
+	cfg, err := settings.NewMergedESConfig(es.Name, version.MustParse(es.Spec.Version), corev1.IPv4Protocol, es.Spec.HTTP, nodeSet, nil, esv1.Auth{}, nil, nil)
+	require.NoError(t, err)
+	client := k8s.NewFakeClient(&corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Namespace: es.Namespace, Name: esv1.ScriptsConfigMap(es.Name)}})
+	javaOpts := func(existing sset.StatefulSetList) string {
+		statefulSet, err := BuildStatefulSet(context.Background(), client, es, nodeSet, cfg, nil, existing, false, false)
+		require.NoError(t, err)
+		for _, c := range statefulSet.Spec.Template.Spec.Containers {
+			for _, envVar := range c.Env {
+				if c.Name == esv1.ElasticsearchContainerName && envVar.Name == settings.EnvEsJavaOpts {
+					return envVar.Value
+				}
+			}
+		}
+		return ""
+	}
+	existing := func(annotations map[string]string) sset.StatefulSetList {
+		return sset.StatefulSetList{{
+			ObjectMeta: metav1.ObjectMeta{Namespace: es.Namespace, Name: ssetName},
+			Spec: appsv1.StatefulSetSpec{Template: corev1.PodTemplateSpec{
+				ObjectMeta: metav1.ObjectMeta{Annotations: annotations},
+			}},
+		}}
+	}
+
+	// new StatefulSet
+	assert.Equal(t, "-Xms1024m -Xmx1024m -Dlog4j2.formatMsgNoLookups=true", javaOpts(nil))
+	// existing StatefulSet already sized by the operator
+	assert.Equal(t, "-Xms1024m -Xmx1024m -Dlog4j2.formatMsgNoLookups=true", javaOpts(existing(map[string]string{defaultHeapSizeAnnotationName: "true"})))
+	// existing StatefulSet not sized by the operator: the heap of the nodes is not changed
+	assert.Equal(t, "-Dlog4j2.formatMsgNoLookups=true", javaOpts(existing(nil)))
+}
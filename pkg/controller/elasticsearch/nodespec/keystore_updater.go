@@ -0,0 +1,169 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package nodespec
+
+import (
+	"bytes"
+	"sort"
+	"strings"
+	"text/template"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/utils/pointer"
+
+	esv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/elasticsearch/v1"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/hash"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/keystore"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/elasticsearch/initcontainer"
+)
+
+const (
+	// KeystoreUpdaterContainerName is the name of the sidecar container updating the keystore when the secure settings
+	// change.
+	KeystoreUpdaterContainerName = "elastic-internal-keystore-updater"
+	// KeystoreUpdaterPollInterval is the interval at which the keystore updater checks the secure settings for changes.
+	KeystoreUpdaterPollInterval = 10
+)
+
+// reloadableSecureSettingsPrefixes are the prefixes of the secure settings that Elasticsearch applies when they are
+// reloaded through the reload secure settings API.
+// See https://www.elastic.co/guide/en/elasticsearch/reference/current/secure-settings.html#reloadable-secure-settings.
+var reloadableSecureSettingsPrefixes = []string{
+	"azure.client.",
+	"gcs.client.",
+	"s3.client.",
+	"xpack.notification.email.account.",
+	"xpack.notification.jira.account.",
+	"xpack.notification.pagerduty.account.",
+	"xpack.notification.slack.account.",
+}
+
+// isReloadableSecureSetting returns true if the given secure setting is applied when the secure settings are reloaded.
+func isReloadableSecureSetting(name string) bool {
+	for _, prefix := range reloadableSecureSettingsPrefixes {
+		if strings.HasPrefix(name, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// keystoreRestartVersion returns the version of the secure settings whose change requires the Pods to be restarted.
+// It is the version of the secure settings secret, unless the reload of the secure settings is enabled: only adding or
+// removing secure settings, or updating the value of secure settings which are not reloadable, then requires a restart.
+func keystoreRestartVersion(es esv1.Elasticsearch, keystoreResources keystore.Resources) string {
+	if !es.IsSecureSettingsReloadEnabled() {
+		return keystoreResources.Version
+	}
+	names := make([]string, 0, len(keystoreResources.SettingsHashes))
+	for name := range keystoreResources.SettingsHashes {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	notReloadable := make([]string, 0, len(names))
+	for _, name := range names {
+		if !isReloadableSecureSetting(name) {
+			notReloadable = append(notReloadable, keystoreResources.SettingsHashes[name])
+		}
+	}
+	return hash.HashObject([][]string{names, notReloadable})
+}
+
+// keystoreUpdaterResources are the resources of the keystore updater, which idles until the keystore must be rebuilt.
+var keystoreUpdaterResources = corev1.ResourceRequirements{
+	Requests: map[corev1.ResourceName]resource.Quantity{
+		corev1.ResourceMemory: resource.MustParse("196Mi"),
+		corev1.ResourceCPU:    resource.MustParse("10m"),
+	},
+	Limits: map[corev1.ResourceName]resource.Quantity{
+		corev1.ResourceMemory: resource.MustParse("196Mi"),
+		corev1.ResourceCPU:    resource.MustParse("500m"),
+	},
+}
+
+// keystoreUpdaterScript rebuilds the keystore in the config directory shared with the Elasticsearch container whenever
+// the content of the secure settings volume, which the kubelet updates along with its secret, changes. The keystore is
+// created in a temporary directory then moved in place, so that Elasticsearch never reads a partial keystore when its
+// secure settings are reloaded.
+const keystoreUpdaterScript = `#!/usr/bin/env bash
+
+set -u
+
+secure_settings={{ .SecureSettingsVolumeMountPath }}
+config={{ .ConfigPath }}
+
+checksum() {
+	for filename in $(LC_ALL=C ls "${secure_settings}"); do
+		echo "${filename}"
+		cat "${secure_settings}/${filename}"
+	done | sha256sum
+}
+
+applied=$(checksum)
+while true; do
+	sleep {{ .PollInterval }}
+	current=$(checksum)
+	[[ "${current}" == "${applied}" ]] && continue
+
+	echo "Secure settings changed, updating the keystore."
+	tmp=$(mktemp -d "${config}/.keystore-XXXXXX")
+	if ES_PATH_CONF="${tmp}" {{ .KeystoreCreateCommand }}; then
+		failed=false
+		for filename in "${secure_settings}"/*; do
+			[[ -e "$filename" ]] || continue # glob does not match
+			key=$(basename "$filename")
+			ES_PATH_CONF="${tmp}" {{ .KeystoreAddCommand }} || failed=true
+		done
+		if [[ "${failed}" == "false" ]] && mv "${tmp}/elasticsearch.keystore" "${config}/elasticsearch.keystore"; then
+			applied=${current}
+			echo "Keystore updated."
+		fi
+	fi
+	rm -rf "${tmp}"
+done
+`
+
+var keystoreUpdaterScriptTemplate = template.Must(template.New("").Parse(keystoreUpdaterScript))
+
+// NewKeystoreUpdaterContainer returns a sidecar container keeping the keystore of the Elasticsearch container up to date
+// with the secure settings, so that the operator can reload them without restarting the Pod.
+// It runs the given Elasticsearch image, which includes the keystore tool.
+func NewKeystoreUpdaterContainer(image string, keystoreResources keystore.Resources) (corev1.Container, error) {
+	var script bytes.Buffer
+	if err := keystoreUpdaterScriptTemplate.Execute(&script, struct {
+		SecureSettingsVolumeMountPath string
+		ConfigPath                    string
+		PollInterval                  int
+		KeystoreCreateCommand         string
+		KeystoreAddCommand            string
+	}{
+		SecureSettingsVolumeMountPath: initcontainer.KeystoreParams.SecureSettingsVolumeMountPath,
+		ConfigPath:                    initcontainer.EsConfigSharedVolume.ContainerMountPath,
+		PollInterval:                  KeystoreUpdaterPollInterval,
+		KeystoreCreateCommand:         initcontainer.KeystoreParams.KeystoreCreateCommand,
+		KeystoreAddCommand:            initcontainer.KeystoreParams.KeystoreAddCommand,
+	}); err != nil {
+		return corev1.Container{}, err
+	}
+
+	// access the secure settings as mounted in the keystore init container, and write the keystore in the config
+	// directory of the Elasticsearch container
+	volumeMounts := make([]corev1.VolumeMount, 0, len(keystoreResources.InitContainer.VolumeMounts)+1)
+	volumeMounts = append(volumeMounts, keystoreResources.InitContainer.VolumeMounts...)
+	volumeMounts = append(volumeMounts, initcontainer.EsConfigSharedVolume.VolumeMount())
+
+	return corev1.Container{
+		Name:            KeystoreUpdaterContainerName,
+		Image:           image,
+		ImagePullPolicy: corev1.PullIfNotPresent,
+		SecurityContext: &corev1.SecurityContext{
+			Privileged: pointer.Bool(false),
+		},
+		Command:      []string{"/usr/bin/env", "bash", "-c", script.String()},
+		VolumeMounts: volumeMounts,
+		Resources:    keystoreUpdaterResources,
+	}, nil
+}
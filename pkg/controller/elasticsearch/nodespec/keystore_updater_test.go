@@ -0,0 +1,60 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package nodespec
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	esv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/elasticsearch/v1"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/keystore"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/elasticsearch/initcontainer"
+)
+
+func TestNewKeystoreUpdaterContainer(t *testing.T) {
+	secureSettingsMount := corev1.VolumeMount{Name: "elastic-internal-secure-settings", MountPath: keystore.SecureSettingsVolumeMountPath, ReadOnly: true}
+	keystoreResources := keystore.Resources{
+		InitContainer: corev1.Container{VolumeMounts: []corev1.VolumeMount{secureSettingsMount}},
+		Version:       "42",
+	}
+
+	container, err := NewKeystoreUpdaterContainer("docker.elastic.co/elasticsearch/elasticsearch:8.5.0", keystoreResources)
+	require.NoError(t, err)
+	require.Equal(t, KeystoreUpdaterContainerName, container.Name)
+	require.Equal(t, "docker.elastic.co/elasticsearch/elasticsearch:8.5.0", container.Image)
+	require.Equal(t, []corev1.VolumeMount{secureSettingsMount, initcontainer.EsConfigSharedVolume.VolumeMount()}, container.VolumeMounts)
+	// the mounts of the init container must not be shared with the sidecar
+	require.Len(t, keystoreResources.InitContainer.VolumeMounts, 1)
+
+	script := container.Command[len(container.Command)-1]
+	require.Contains(t, script, "secure_settings="+keystore.SecureSettingsVolumeMountPath)
+	require.Contains(t, script, "config="+initcontainer.EsConfigSharedVolume.ContainerMountPath)
+	require.Contains(t, script, `ES_PATH_CONF="${tmp}" `+initcontainer.KeystoreParams.KeystoreCreateCommand)
+	require.Contains(t, script, `ES_PATH_CONF="${tmp}" `+initcontainer.KeystoreParams.KeystoreAddCommand)
+	require.Contains(t, script, "sleep 10")
+}
+
+func Test_keystoreRestartVersion(t *testing.T) {
+	withReload := esv1.Elasticsearch{ObjectMeta: metav1.ObjectMeta{Annotations: map[string]string{esv1.ReloadSecureSettingsAnnotation: "true"}}}
+	resources := func(version string, settingsHashes map[string]string) keystore.Resources {
+		return keystore.Resources{Version: version, SettingsHashes: settingsHashes}
+	}
+	initial := resources("1", map[string]string{"s3.client.default.access_key": "a", "xpack.security.authc.realms.oidc.oidc1.rp.client_secret": "b"})
+
+	// without reload, any change of the secure settings restarts the Pods
+	require.Equal(t, "1", keystoreRestartVersion(esv1.Elasticsearch{}, initial))
+	require.Equal(t, "2", keystoreRestartVersion(esv1.Elasticsearch{}, resources("2", initial.SettingsHashes)))
+
+	// with reload, only changes of non-reloadable settings or of the list of settings restart the Pods
+	reloadable := resources("2", map[string]string{"s3.client.default.access_key": "c", "xpack.security.authc.realms.oidc.oidc1.rp.client_secret": "b"})
+	require.Equal(t, keystoreRestartVersion(withReload, initial), keystoreRestartVersion(withReload, reloadable))
+	notReloadable := resources("2", map[string]string{"s3.client.default.access_key": "a", "xpack.security.authc.realms.oidc.oidc1.rp.client_secret": "c"})
+	require.NotEqual(t, keystoreRestartVersion(withReload, initial), keystoreRestartVersion(withReload, notReloadable))
+	added := resources("2", map[string]string{"s3.client.default.access_key": "a", "s3.client.default.secret_key": "d", "xpack.security.authc.realms.oidc.oidc1.rp.client_secret": "b"})
+	require.NotEqual(t, keystoreRestartVersion(withReload, initial), keystoreRestartVersion(withReload, added))
+}
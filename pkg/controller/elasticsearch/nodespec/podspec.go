@@ -47,6 +47,10 @@ const (
 // podTemplate securityContext to an empty value.
 var minDefaultSecurityContextVersion = version.MinFor(8, 0, 0)
 
+// Starting 7.14.0, Elasticsearch can be configured to write its logs to disk instead of the console through the
+// ES_LOG_STYLE environment variable.
+var minFileLogStyleVersion = version.MinFor(7, 14, 0)
+
 // BuildPodTemplateSpec builds a new PodTemplateSpec for an Elasticsearch node.
 func BuildPodTemplateSpec(
 	ctx context.Context,
@@ -56,9 +60,10 @@ func BuildPodTemplateSpec(
 	cfg settings.CanonicalConfig,
 	keystoreResources *keystore.Resources,
 	setDefaultSecurityContext bool,
+	setVMMaxMapCount bool,
 ) (corev1.PodTemplateSpec, error) {
 	downwardAPIVolume := volume.DownwardAPI{}.WithAnnotations(es.HasDownwardNodeLabels())
-	volumes, volumeMounts := buildVolumes(es.Name, nodeSet, keystoreResources, downwardAPIVolume)
+	volumes, volumeMounts := buildVolumes(es.Name, es.StatefulSetName(nodeSet.Name), nodeSet, es.Spec.SnapshotVolumes, es.Spec.Auth, keystoreResources, downwardAPIVolume)
 
 	labels, err := buildLabels(es, cfg, nodeSet)
 	if err != nil {
@@ -69,9 +74,11 @@ func BuildPodTemplateSpec(
 
 	// now build the initContainers using the effective main container resources as an input
 	initContainers, err := initcontainer.NewInitContainers(
-		transportCertificatesVolume(esv1.StatefulSet(es.Name, nodeSet.Name)),
+		transportCertificatesVolume(es.StatefulSetName(nodeSet.Name)),
 		keystoreResources,
 		es.DownwardNodeLabels(),
+		es.Spec.Plugins,
+		setVMMaxMapCount,
 	)
 	if err != nil {
 		return corev1.PodTemplateSpec{}, err
@@ -89,14 +96,14 @@ func BuildPodTemplateSpec(
 		})
 	}
 
-	headlessServiceName := HeadlessServiceName(esv1.StatefulSet(es.Name, nodeSet.Name))
+	headlessServiceName := HeadlessServiceName(es.StatefulSetName(nodeSet.Name))
 
 	// We retrieve the ConfigMap that holds the scripts to trigger a Pod restart if it is updated.
 	esScripts := &corev1.ConfigMap{}
 	if err := client.Get(context.Background(), types.NamespacedName{Namespace: es.Namespace, Name: esv1.ScriptsConfigMap(es.Name)}, esScripts); err != nil {
 		return corev1.PodTemplateSpec{}, err
 	}
-	annotations := buildAnnotations(es, cfg, keystoreResources, esScripts.ResourceVersion)
+	annotations := buildAnnotations(es, cfg, nodeSet.JVMOptions, keystoreResources, esScripts.ResourceVersion)
 
 	// build the podTemplate until we have the effective resources configured
 	builder = builder.
@@ -106,9 +113,10 @@ func BuildPodTemplateSpec(
 		WithResources(DefaultResources).
 		WithTerminationGracePeriod(DefaultTerminationGracePeriodSeconds).
 		WithPorts(defaultContainerPorts).
-		WithReadinessProbe(*NewReadinessProbe()).
+		WithReadinessProbe(*NewReadinessProbe(nodeSet.ReadinessProbe)).
 		WithAffinity(DefaultAffinity(es.Name)).
 		WithEnv(DefaultEnvVars(es.Spec.HTTP, headlessServiceName)...).
+		WithEnv(readinessProbeEnv(nodeSet.ReadinessProbe)...).
 		WithVolumes(volumes...).
 		WithVolumeMounts(volumeMounts...).
 		WithInitContainers(initContainers...).
@@ -116,6 +124,20 @@ func BuildPodTemplateSpec(
 		WithInitContainerDefaults(builder.MainContainer().Env...).
 		WithPreStopHook(*NewPreStopHook())
 
+	if esvolume.HasLogsVolumeClaim(nodeSet.VolumeClaimTemplates) && ver.GTE(minFileLogStyleVersion) {
+		// write logs to the persistent logs volume so that they survive Pod restarts
+		builder = builder.WithEnv(corev1.EnvVar{Name: settings.EnvEsLogStyle, Value: "file"})
+	}
+
+	if keystoreResources != nil && es.IsSecureSettingsReloadEnabled() {
+		// keep the keystore up to date with the secure settings, which are then reloaded by the operator
+		keystoreUpdater, err := NewKeystoreUpdaterContainer(builder.MainContainer().Image, *keystoreResources)
+		if err != nil {
+			return corev1.PodTemplateSpec{}, err
+		}
+		builder = builder.WithContainers(keystoreUpdater)
+	}
+
 	builder, err = stackmon.WithMonitoring(ctx, client, builder, es)
 	if err != nil {
 		return corev1.PodTemplateSpec{}, err
@@ -163,7 +185,7 @@ func buildLabels(
 	node := unpackedCfg.Node
 	podLabels := label.NewPodLabels(
 		k8s.ExtractNamespacedName(&es),
-		esv1.StatefulSet(es.Name, nodeSet.Name),
+		es.StatefulSetName(nodeSet.Name),
 		ver, node, es.Spec.HTTP.Protocol(),
 	)
 
@@ -173,6 +195,7 @@ func buildLabels(
 func buildAnnotations(
 	es esv1.Elasticsearch,
 	cfg settings.CanonicalConfig,
+	jvmOptions []string,
 	keystoreResources *keystore.Resources,
 	scriptsVersion string,
 ) map[string]string {
@@ -187,14 +210,19 @@ func buildAnnotations(
 	// hash of the scripts' version to rotate the pod if the scripts have changed
 	_, _ = configHash.Write([]byte(scriptsVersion))
 
+	if len(jvmOptions) > 0 {
+		// JVM options of the NodeSet to rotate the pod when they change
+		hash.WriteHashObject(configHash, jvmOptions)
+	}
+
 	if es.HasDownwardNodeLabels() {
 		// list of node labels expected on the pod to rotate the pod when the list is updated
 		_, _ = configHash.Write([]byte(es.Annotations[esv1.DownwardNodeLabelsAnnotation]))
 	}
 
 	if keystoreResources != nil {
-		// resource version of the secure settings secret to rotate the pod on secure settings change
-		_, _ = configHash.Write([]byte(keystoreResources.Version))
+		// version of the secure settings to rotate the pod on secure settings change, unless they can be reloaded
+		_, _ = configHash.Write([]byte(keystoreRestartVersion(es, *keystoreResources)))
 	}
 
 	// set the annotation in place
@@ -14,12 +14,14 @@ import (
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
 	commonv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/common/v1"
 	esv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/elasticsearch/v1"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/defaults"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/keystore"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/pod"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/version"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/volume"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/elasticsearch/initcontainer"
@@ -208,27 +210,92 @@ func TestBuildPodTemplateSpecWithDefaultSecurityContext(t *testing.T) {
 			es.Spec.Version = tt.version.String()
 			es.Spec.NodeSets[0].PodTemplate.Spec.SecurityContext = tt.userSecurityContext
 
-			cfg, err := settings.NewMergedESConfig(es.Name, tt.version, corev1.IPv4Protocol, es.Spec.HTTP, *es.Spec.NodeSets[0].Config)
+			cfg, err := settings.NewMergedESConfig(es.Name, tt.version, corev1.IPv4Protocol, es.Spec.HTTP, es.Spec.NodeSets[0], nil, esv1.Auth{}, nil, nil)
 			require.NoError(t, err)
 
 			client := k8s.NewFakeClient(&corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Namespace: es.Namespace, Name: esv1.ScriptsConfigMap(es.Name)}})
-			actual, err := BuildPodTemplateSpec(context.Background(), client, es, es.Spec.NodeSets[0], cfg, nil, tt.setDefaultFSGroup)
+			actual, err := BuildPodTemplateSpec(context.Background(), client, es, es.Spec.NodeSets[0], cfg, nil, tt.setDefaultFSGroup, false)
 			require.NoError(t, err)
 			require.Equal(t, tt.wantSecurityContext, actual.Spec.SecurityContext)
 		})
 	}
 }
 
+func TestBuildPodTemplateSpecWithLogsVolumeClaim(t *testing.T) {
+	logsClaim := corev1.PersistentVolumeClaim{ObjectMeta: metav1.ObjectMeta{Name: esvolume.ElasticsearchLogsVolumeName}}
+	for _, tt := range []struct {
+		name             string
+		version          version.Version
+		claims           []corev1.PersistentVolumeClaim
+		wantEmptyDir     bool
+		wantFileLogStyle bool
+	}{
+		{
+			name:         "no logs volume claim",
+			version:      version.MustParse("8.0.0"),
+			wantEmptyDir: true,
+		},
+		{
+			name:             "logs volume claim",
+			version:          version.MustParse("8.0.0"),
+			claims:           []corev1.PersistentVolumeClaim{logsClaim},
+			wantFileLogStyle: true,
+		},
+		{
+			name:    "logs volume claim, ES_LOG_STYLE not supported",
+			version: version.MustParse("7.13.0"),
+			claims:  []corev1.PersistentVolumeClaim{logsClaim},
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			es := newEsSampleBuilder().build()
+			es.Spec.Version = tt.version.String()
+			es.Spec.NodeSets[0].VolumeClaimTemplates = tt.claims
+
+			cfg, err := settings.NewMergedESConfig(es.Name, tt.version, corev1.IPv4Protocol, es.Spec.HTTP, es.Spec.NodeSets[0], nil, esv1.Auth{}, nil, nil)
+			require.NoError(t, err)
+
+			client := k8s.NewFakeClient(&corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Namespace: es.Namespace, Name: esv1.ScriptsConfigMap(es.Name)}})
+			actual, err := BuildPodTemplateSpec(context.Background(), client, es, es.Spec.NodeSets[0], cfg, nil, false, false)
+			require.NoError(t, err)
+
+			var logsVolumes []corev1.Volume
+			for _, v := range actual.Spec.Volumes {
+				if v.Name == esvolume.ElasticsearchLogsVolumeName {
+					logsVolumes = append(logsVolumes, v)
+				}
+			}
+			require.Len(t, logsVolumes, 1)
+			require.Equal(t, tt.wantEmptyDir, logsVolumes[0].EmptyDir != nil)
+			require.Equal(t, !tt.wantEmptyDir, logsVolumes[0].PersistentVolumeClaim != nil)
+
+			esContainer := pod.ContainerByName(actual.Spec, esv1.ElasticsearchContainerName)
+			require.NotNil(t, esContainer)
+			require.Contains(t, esContainer.VolumeMounts, esvolume.DefaultLogsVolumeMount)
+			require.Equal(t, tt.wantFileLogStyle, containsEnv(esContainer.Env, corev1.EnvVar{Name: settings.EnvEsLogStyle, Value: "file"}))
+		})
+	}
+}
+
+func containsEnv(vars []corev1.EnvVar, v corev1.EnvVar) bool {
+	for _, e := range vars {
+		if e.Name == v.Name && e.Value == v.Value {
+			return true
+		}
+	}
+	return false
+}
+
 func TestBuildPodTemplateSpec(t *testing.T) {
 	sampleES := newEsSampleBuilder().build()
 	nodeSet := sampleES.Spec.NodeSets[0]
 	ver, err := version.Parse(sampleES.Spec.Version)
 	require.NoError(t, err)
-	cfg, err := settings.NewMergedESConfig(sampleES.Name, ver, corev1.IPv4Protocol, sampleES.Spec.HTTP, *nodeSet.Config)
+	cfg, err := settings.NewMergedESConfig(sampleES.Name, ver, corev1.IPv4Protocol, sampleES.Spec.HTTP, nodeSet, nil, esv1.Auth{}, nil, nil)
 	require.NoError(t, err)
 
 	client := k8s.NewFakeClient(&corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Namespace: sampleES.Namespace, Name: esv1.ScriptsConfigMap(sampleES.Name)}})
-	actual, err := BuildPodTemplateSpec(context.Background(), client, sampleES, sampleES.Spec.NodeSets[0], cfg, nil, false)
+	actual, err := BuildPodTemplateSpec(context.Background(), client, sampleES, sampleES.Spec.NodeSets[0], cfg, nil, false, false)
 	require.NoError(t, err)
 
 	// build expected PodTemplateSpec
@@ -236,12 +303,12 @@ func TestBuildPodTemplateSpec(t *testing.T) {
 	terminationGracePeriodSeconds := DefaultTerminationGracePeriodSeconds
 	varFalse := false
 
-	volumes, volumeMounts := buildVolumes(sampleES.Name, nodeSet, nil, volume.DownwardAPI{})
+	volumes, volumeMounts := buildVolumes(sampleES.Name, esv1.StatefulSet(sampleES.Name, nodeSet.Name), nodeSet, nil, esv1.Auth{}, nil, volume.DownwardAPI{})
 	// should be sorted
 	sort.Slice(volumes, func(i, j int) bool { return volumes[i].Name < volumes[j].Name })
 	sort.Slice(volumeMounts, func(i, j int) bool { return volumeMounts[i].Name < volumeMounts[j].Name })
 
-	initContainers, err := initcontainer.NewInitContainers(transportCertificatesVolume(sampleES.Name), nil, nil)
+	initContainers, err := initcontainer.NewInitContainers(transportCertificatesVolume(sampleES.Name), nil, nil, nil, false)
 	require.NoError(t, err)
 	// init containers should be patched with volume and inherited env vars and image
 	// init container env vars come in a slightly different order than main container ones which is an artefact of how the pod template builder works
@@ -321,7 +388,7 @@ func TestBuildPodTemplateSpec(t *testing.T) {
 						DefaultEnvVars(sampleES.Spec.HTTP, HeadlessServiceName(esv1.StatefulSet(sampleES.Name, nodeSet.Name)))...),
 					Resources:      DefaultResources,
 					VolumeMounts:   volumeMounts,
-					ReadinessProbe: NewReadinessProbe(),
+					ReadinessProbe: NewReadinessProbe(nil),
 					Lifecycle: &corev1.Lifecycle{
 						PreStop: NewPreStopHook(),
 					},
@@ -429,9 +496,9 @@ func Test_buildAnnotations(t *testing.T) {
 			es := newEsSampleBuilder().withKeystoreResources(tt.args.keystoreResources).withUserConfig(tt.args.cfg).addEsAnnotations(tt.args.esAnnotations).build()
 			ver, err := version.Parse(sampleES.Spec.Version)
 			require.NoError(t, err)
-			cfg, err := settings.NewMergedESConfig(es.Name, ver, corev1.IPv4Protocol, es.Spec.HTTP, *es.Spec.NodeSets[0].Config)
+			cfg, err := settings.NewMergedESConfig(es.Name, ver, corev1.IPv4Protocol, es.Spec.HTTP, es.Spec.NodeSets[0], nil, esv1.Auth{}, nil, nil)
 			require.NoError(t, err)
-			got := buildAnnotations(es, cfg, tt.args.keystoreResources, tt.args.scriptsVersion)
+			got := buildAnnotations(es, cfg, nil, tt.args.keystoreResources, tt.args.scriptsVersion)
 
 			for expectedAnnotation, expectedValue := range tt.expectedAnnotations {
 				actualValue, exists := got[expectedAnnotation]
@@ -442,6 +509,24 @@ func Test_buildAnnotations(t *testing.T) {
 	}
 }
 
+func Test_buildAnnotations_JVMOptions(t *testing.T) {
+	es := newEsSampleBuilder().build()
+	ver, err := version.Parse(sampleES.Spec.Version)
+	require.NoError(t, err)
+	cfg, err := settings.NewMergedESConfig(es.Name, ver, corev1.IPv4Protocol, es.Spec.HTTP, es.Spec.NodeSets[0], nil, esv1.Auth{}, nil, nil)
+	require.NoError(t, err)
+
+	withoutOptions := buildAnnotations(es, cfg, nil, nil, "")
+	// no JVM options must not rotate existing pods
+	require.Equal(t, "533641620", withoutOptions[configHashAnnotationName])
+
+	withOptions := buildAnnotations(es, cfg, []string{"-XX:+UseG1GC"}, nil, "")
+	require.NotEqual(t, withoutOptions[configHashAnnotationName], withOptions[configHashAnnotationName])
+
+	withOtherOptions := buildAnnotations(es, cfg, []string{"-XX:+UseG1GC", "-XX:HeapDumpPath=/tmp"}, nil, "")
+	require.NotEqual(t, withOptions[configHashAnnotationName], withOtherOptions[configHashAnnotationName])
+}
+
 func Test_getDefaultContainerPorts(t *testing.T) {
 	tt := []struct {
 		name string
@@ -526,10 +611,10 @@ func Test_enableLog4JFormatMsgNoLookups(t *testing.T) {
 
 			ver, err := version.Parse(sampleES.Spec.Version)
 			require.NoError(t, err)
-			cfg, err := settings.NewMergedESConfig(sampleES.Name, ver, corev1.IPv4Protocol, sampleES.Spec.HTTP, *sampleES.Spec.NodeSets[0].Config)
+			cfg, err := settings.NewMergedESConfig(sampleES.Name, ver, corev1.IPv4Protocol, sampleES.Spec.HTTP, sampleES.Spec.NodeSets[0], nil, esv1.Auth{}, nil, nil)
 			require.NoError(t, err)
 			client := k8s.NewFakeClient(&corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Namespace: sampleES.Namespace, Name: esv1.ScriptsConfigMap(sampleES.Name)}})
-			actual, err := BuildPodTemplateSpec(context.Background(), client, sampleES, sampleES.Spec.NodeSets[0], cfg, nil, false)
+			actual, err := BuildPodTemplateSpec(context.Background(), client, sampleES, sampleES.Spec.NodeSets[0], cfg, nil, false, false)
 			require.NoError(t, err)
 
 			env := actual.Spec.Containers[1].Env
@@ -542,3 +627,28 @@ func Test_enableLog4JFormatMsgNoLookups(t *testing.T) {
 		})
 	}
 }
+
+func Test_buildVolumes_memoryDataVolume(t *testing.T) {
+	nodeSet := esv1.NodeSet{
+		Name:             "default",
+		Count:            1,
+		MemoryDataVolume: &esv1.MemoryDataVolume{SizeLimit: resource.MustParse("1Gi")},
+	}
+	// the default data volume claim is not appended
+	nodeSet.VolumeClaimTemplates = defaults.AppendDefaultPVCs(
+		nodeSet.VolumeClaimTemplates,
+		esvolume.PodSpecWithMemoryDataVolume(nodeSet),
+		esvolume.DefaultVolumeClaimTemplates...,
+	)
+	require.Empty(t, nodeSet.VolumeClaimTemplates)
+
+	volumes, volumeMounts := buildVolumes("es", esv1.StatefulSet("es", nodeSet.Name), nodeSet, nil, esv1.Auth{}, nil, volume.DownwardAPI{})
+	sizeLimit := resource.MustParse("1Gi")
+	require.Contains(t, volumes, corev1.Volume{
+		Name: esvolume.ElasticsearchDataVolumeName,
+		VolumeSource: corev1.VolumeSource{
+			EmptyDir: &corev1.EmptyDirVolumeSource{Medium: corev1.StorageMediumMemory, SizeLimit: &sizeLimit},
+		},
+	})
+	require.Contains(t, volumeMounts, esvolume.DefaultDataVolumeMount)
+}
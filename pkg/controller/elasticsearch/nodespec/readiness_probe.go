@@ -6,15 +6,58 @@ package nodespec
 
 import (
 	"path"
+	"strconv"
 
 	corev1 "k8s.io/api/core/v1"
 
+	esv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/elasticsearch/v1"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/http"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/elasticsearch/label"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/elasticsearch/volume"
 )
 
-func NewReadinessProbe() *corev1.Probe {
+const (
+	// ReadinessProbeTimeoutEnvVar is the timeout in seconds of the requests sent to Elasticsearch by the readiness probe.
+	ReadinessProbeTimeoutEnvVar = "READINESS_PROBE_TIMEOUT"
+	// readinessProbeRequestMargin is the number of seconds the requests to Elasticsearch time out before the probe does.
+	readinessProbeRequestMargin = 2
+)
+
+// NewReadinessProbe returns the readiness probe of the Elasticsearch container, with the timeouts and thresholds
+// of the given settings if any.
+func NewReadinessProbe(settings *esv1.ReadinessProbe) *corev1.Probe {
+	probe := defaultReadinessProbe()
+	if settings == nil {
+		return probe
+	}
+	if settings.InitialDelaySeconds > 0 {
+		probe.InitialDelaySeconds = settings.InitialDelaySeconds
+	}
+	if settings.PeriodSeconds > 0 {
+		probe.PeriodSeconds = settings.PeriodSeconds
+	}
+	if settings.TimeoutSeconds > 0 {
+		probe.TimeoutSeconds = settings.TimeoutSeconds
+	}
+	if settings.FailureThreshold > 0 {
+		probe.FailureThreshold = settings.FailureThreshold
+	}
+	return probe
+}
+
+// readinessProbeEnv returns the environment variable setting the timeout of the requests sent to Elasticsearch by the
+// readiness probe, if a custom probe timeout is set.
+func readinessProbeEnv(settings *esv1.ReadinessProbe) []corev1.EnvVar {
+	if settings == nil || settings.TimeoutSeconds <= readinessProbeRequestMargin {
+		return nil
+	}
+	return []corev1.EnvVar{{
+		Name:  ReadinessProbeTimeoutEnvVar,
+		Value: strconv.Itoa(int(settings.TimeoutSeconds - readinessProbeRequestMargin)),
+	}}
+}
+
+func defaultReadinessProbe() *corev1.Probe {
 	return &corev1.Probe{
 		FailureThreshold:    3,
 		InitialDelaySeconds: 10,
@@ -73,21 +116,36 @@ else
   LOOPBACK=127.0.0.1
 fi
 
-# request Elasticsearch on /
 # we are turning globbing off to allow for unescaped [] in case of IPv6
-ENDPOINT="${READINESS_PROBE_PROTOCOL:-https}://${LOOPBACK}:9200/"
 ORIGIN_HEADER="` + http.InternalProductRequestHeaderString + `"
-status=$(curl -o /dev/null -w "%{http_code}" --max-time ${READINESS_PROBE_TIMEOUT} -H "${ORIGIN_HEADER}" -XGET -g -s -k ${BASIC_AUTH} $ENDPOINT)
+function request {
+  curl -w " %{http_code}" --max-time ${READINESS_PROBE_TIMEOUT} -H "${ORIGIN_HEADER}" -XGET -g -s -k ${BASIC_AUTH} "$1"
+}
+
+if [[ ${version:0:2} == "6." ]] || [[ -z "${version}" ]]; then
+  # request Elasticsearch on /
+  response=$(request "${READINESS_PROBE_PROTOCOL:-https}://${LOOPBACK}:9200/")
+else
+  # request the master node known by the local node: the node is ready if it is responsive and has joined a cluster
+  # with an elected master
+  response=$(request "${READINESS_PROBE_PROTOCOL:-https}://${LOOPBACK}:9200/_cluster/state/master_node?local=true&filter_path=master_node")
+fi
 curl_rc=$?
 
 if [[ ${curl_rc} -ne 0 ]]; then
   fail "\"curl_rc\": \"${curl_rc}\""
 fi
 
+status="${response##* }"
+body="${response% *}"
+
 # ready if status code 200, 503 is tolerable if ES version is 6.x
-if [[ ${status} == "200" ]] || [[ ${status} == "503" && ${version:0:2} == "6." ]]; then
+if [[ ${status} == "503" && ${version:0:2} == "6." ]]; then
   exit 0
-else
+elif [[ ${status} != "200" ]]; then
   fail " \"status\": \"${status}\", \"version\":\"${version}\" "
+elif [[ -n "${version}" ]] && [[ ${version:0:2} != "6." ]] && [[ ${body} != *'"master_node"'* ]]; then
+  fail " \"status\": \"${status}\", \"version\":\"${version}\", \"master_node\": \"unknown\" "
 fi
+exit 0
 `
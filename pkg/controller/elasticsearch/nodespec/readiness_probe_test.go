@@ -0,0 +1,56 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package nodespec
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+
+	esv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/elasticsearch/v1"
+)
+
+func TestNewReadinessProbe(t *testing.T) {
+	tests := []struct {
+		name     string
+		settings *esv1.ReadinessProbe
+		want     func(probe *corev1.Probe)
+		wantEnv  []corev1.EnvVar
+	}{
+		{
+			name:     "default probe",
+			settings: nil,
+			want:     func(probe *corev1.Probe) {},
+			wantEnv:  nil,
+		},
+		{
+			name:     "thresholds",
+			settings: &esv1.ReadinessProbe{PeriodSeconds: 10, FailureThreshold: 6},
+			want: func(probe *corev1.Probe) {
+				probe.PeriodSeconds = 10
+				probe.FailureThreshold = 6
+			},
+			wantEnv: nil,
+		},
+		{
+			name:     "timeout",
+			settings: &esv1.ReadinessProbe{InitialDelaySeconds: 30, TimeoutSeconds: 12},
+			want: func(probe *corev1.Probe) {
+				probe.InitialDelaySeconds = 30
+				probe.TimeoutSeconds = 12
+			},
+			wantEnv: []corev1.EnvVar{{Name: ReadinessProbeTimeoutEnvVar, Value: "10"}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			want := defaultReadinessProbe()
+			tt.want(want)
+			require.Equal(t, want, NewReadinessProbe(tt.settings))
+			require.Equal(t, tt.wantEnv, readinessProbeEnv(tt.settings))
+		})
+	}
+}
@@ -13,7 +13,9 @@ import (
 
 	commonv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/common/v1"
 	esv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/elasticsearch/v1"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/hash"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/keystore"
+	commonsettings "github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/settings"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/version"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/elasticsearch/label"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/elasticsearch/settings"
@@ -21,12 +23,18 @@ import (
 	"github.com/elastic/cloud-on-k8s/v2/pkg/utils/k8s"
 )
 
+// staticConfigHashAnnotationName marks the Pod templates whose configuration hash excludes the dynamic cluster settings.
+const staticConfigHashAnnotationName = "elasticsearch.k8s.elastic.co/static-config-hash"
+
 // Resources contain per-NodeSet resources to be created.
 type Resources struct {
 	NodeSet         string
 	StatefulSet     appsv1.StatefulSet
 	HeadlessService corev1.Service
 	Config          settings.CanonicalConfig
+	JVMOptions      []string
+	// ConfigOverrides are the settings defined with different values by several configuration sources.
+	ConfigOverrides commonsettings.ConfigOverrides
 }
 
 type ResourcesList []Resources
@@ -48,6 +56,18 @@ func (l ResourcesList) StatefulSets() sset.StatefulSetList {
 	return ssetList
 }
 
+// ConfigOverrides returns the settings defined with different values by several configuration sources, prefixed with
+// the name of their NodeSet.
+func (l ResourcesList) ConfigOverrides() []string {
+	var overrides []string
+	for _, resource := range l {
+		for _, override := range resource.ConfigOverrides.Strings() {
+			overrides = append(overrides, resource.NodeSet+": "+override)
+		}
+	}
+	return overrides
+}
+
 func (l ResourcesList) ExpectedNodeCount() int32 {
 	return l.StatefulSets().ExpectedNodeCount()
 }
@@ -60,6 +80,8 @@ func BuildExpectedResources(
 	existingStatefulSets sset.StatefulSetList,
 	ipFamily corev1.IPFamily,
 	setDefaultSecurityContext bool,
+	setVMMaxMapCount bool,
+	defaultConfig *commonv1.Config,
 ) (ResourcesList, error) {
 	nodesResources := make(ResourcesList, 0, len(es.Spec.NodeSets))
 
@@ -68,19 +90,27 @@ func BuildExpectedResources(
 		return nil, err
 	}
 
+	// dynamic cluster settings are applied through the Elasticsearch API and must not restart the nodes
+	dynamicSettings, err := settings.DynamicClusterSettings(es.Spec.NodeSets)
+	if err != nil {
+		return nil, err
+	}
+
 	for _, nodeSpec := range es.Spec.NodeSets {
 		// build es config
-		userCfg := commonv1.Config{}
-		if nodeSpec.Config != nil {
-			userCfg = *nodeSpec.Config
+		cfg, overrides, err := settings.NewMergedESConfigWithOverrides(es.Name, ver, ipFamily, es.Spec.HTTP, nodeSpec, es.Spec.SnapshotVolumes, es.Spec.Auth, es.Spec.AuditLogging, defaultConfig)
+		if err != nil {
+			return nil, err
 		}
-		cfg, err := settings.NewMergedESConfig(es.Name, ver, ipFamily, es.Spec.HTTP, userCfg)
+
+		// build the Pod template from the config without the dynamic settings, so their changes do not rotate the Pods
+		staticCfg, err := staticESConfig(cfg, es, ver, ipFamily, nodeSpec, defaultConfig, dynamicSettings)
 		if err != nil {
 			return nil, err
 		}
 
 		// build stateful set and associated headless service
-		statefulSet, err := BuildStatefulSet(ctx, client, es, nodeSpec, cfg, keystoreResources, existingStatefulSets, setDefaultSecurityContext)
+		statefulSet, err := buildStatefulSet(ctx, client, es, nodeSpec, cfg, staticCfg, keystoreResources, existingStatefulSets, setDefaultSecurityContext, setVMMaxMapCount)
 		if err != nil {
 			return nil, err
 		}
@@ -91,12 +121,72 @@ func BuildExpectedResources(
 			StatefulSet:     statefulSet,
 			HeadlessService: headlessSvc,
 			Config:          cfg,
+			JVMOptions:      nodeSpec.JVMOptions,
+			ConfigOverrides: overrides,
 		})
 	}
 
 	return nodesResources, nil
 }
 
+// buildStatefulSet builds the StatefulSet of the given NodeSet with a Pod template whose configuration hash does not
+// depend on the dynamic cluster settings. The Pod templates of the StatefulSets created by previous versions of the
+// operator keep a hash of the full configuration until it changes, not to restart the nodes of existing clusters.
+func buildStatefulSet(
+	ctx context.Context,
+	client k8s.Client,
+	es esv1.Elasticsearch,
+	nodeSpec esv1.NodeSet,
+	cfg settings.CanonicalConfig,
+	staticCfg settings.CanonicalConfig,
+	keystoreResources *keystore.Resources,
+	existingStatefulSets sset.StatefulSetList,
+	setDefaultSecurityContext bool,
+	setVMMaxMapCount bool,
+) (appsv1.StatefulSet, error) {
+	existing, exists := existingStatefulSets.GetByName(es.StatefulSetName(nodeSpec.Name))
+	if exists && existing.Spec.Template.Annotations[staticConfigHashAnnotationName] != "true" {
+		legacy, err := BuildStatefulSet(ctx, client, es, nodeSpec, cfg, keystoreResources, existingStatefulSets, setDefaultSecurityContext, setVMMaxMapCount)
+		if err != nil {
+			return appsv1.StatefulSet{}, err
+		}
+		if legacy.Spec.Template.Annotations[configHashAnnotationName] == existing.Spec.Template.Annotations[configHashAnnotationName] {
+			// the configuration is unchanged, keep the existing Pods
+			return legacy, nil
+		}
+		// the Pods are rotated anyway
+	}
+
+	statefulSet, err := BuildStatefulSet(ctx, client, es, nodeSpec, staticCfg, keystoreResources, existingStatefulSets, setDefaultSecurityContext, setVMMaxMapCount)
+	if err != nil {
+		return appsv1.StatefulSet{}, err
+	}
+	statefulSet.Spec.Template.Annotations[staticConfigHashAnnotationName] = "true"
+	statefulSet.Labels = hash.SetTemplateHashLabel(statefulSet.Labels, statefulSet.Spec)
+	return statefulSet, nil
+}
+
+// staticESConfig returns the configuration of the given NodeSet as if the given dynamic settings were not set by the
+// user. The given merged config is returned as is if there is no dynamic setting, to not alter its hash.
+func staticESConfig(
+	cfg settings.CanonicalConfig,
+	es esv1.Elasticsearch,
+	ver version.Version,
+	ipFamily corev1.IPFamily,
+	nodeSpec esv1.NodeSet,
+	defaultConfig *commonv1.Config,
+	dynamicSettings map[string]interface{},
+) (settings.CanonicalConfig, error) {
+	if len(dynamicSettings) == 0 {
+		return cfg, nil
+	}
+	staticNodeSpec, err := settings.WithoutClusterSettings(nodeSpec, dynamicSettings)
+	if err != nil {
+		return settings.CanonicalConfig{}, err
+	}
+	return settings.NewMergedESConfig(es.Name, ver, ipFamily, es.Spec.HTTP, staticNodeSpec, es.Spec.SnapshotVolumes, es.Spec.Auth, es.Spec.AuditLogging, defaultConfig)
+}
+
 // MasterNodesNames returns the names of the master nodes for this ResourcesList.
 func (l ResourcesList) MasterNodesNames() []string {
 	var masters []string
@@ -5,10 +5,21 @@
 package nodespec
 
 import (
+	"context"
 	"reflect"
 	"testing"
 
+	"github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	commonv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/common/v1"
+	esv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/elasticsearch/v1"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/version"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/elasticsearch/settings"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/elasticsearch/sset"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/utils/k8s"
 )
 
 func TestResourcesList_MasterNodesNames(t *testing.T) {
@@ -43,3 +54,47 @@ func TestResourcesList_MasterNodesNames(t *testing.T) {
 		})
 	}
 }
+
+func TestBuildExpectedResources_DynamicClusterSettings(t *testing.T) {
+	withConfig := func(config map[string]interface{}) esv1.Elasticsearch {
+		es := *sampleES.DeepCopy()
+		es.Spec.Version = "8.5.0"
+		es.Spec.NodeSets[0].Config = &commonv1.Config{Data: config}
+		return es
+	}
+	client := k8s.NewFakeClient(&corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Namespace: sampleES.Namespace, Name: esv1.ScriptsConfigMap(sampleES.Name)}})
+	build := func(es esv1.Elasticsearch, existing sset.StatefulSetList) appsv1.StatefulSet {
+		resources, err := BuildExpectedResources(context.Background(), client, es, nil, existing, corev1.IPv4Protocol, false, false, nil)
+		require.NoError(t, err)
+		require.Len(t, resources, 1)
+		return resources[0].StatefulSet
+	}
+	configHash := func(statefulSet appsv1.StatefulSet) string {
+		return statefulSet.Spec.Template.Annotations[configHashAnnotationName]
+	}
+
+	initial := withConfig(map[string]interface{}{"node.store.allow_mmap": false, "logger.org.elasticsearch.discovery": "DEBUG"})
+	dynamicChange := withConfig(map[string]interface{}{"node.store.allow_mmap": false, "logger.org.elasticsearch.discovery": "INFO"})
+	staticChange := withConfig(map[string]interface{}{"node.store.allow_mmap": true, "logger.org.elasticsearch.discovery": "DEBUG"})
+
+	// new StatefulSet: changes of dynamic cluster settings do not rotate the Pods
+	created := build(initial, nil)
+	require.Equal(t, "true", created.Spec.Template.Annotations[staticConfigHashAnnotationName])
+	require.Equal(t, configHash(created), configHash(build(dynamicChange, sset.StatefulSetList{created})))
+	require.NotEqual(t, configHash(created), configHash(build(staticChange, sset.StatefulSetList{created})))
+
+	// StatefulSet created by a previous version of the operator, with the hash of the full configuration
+	cfg, err := settings.NewMergedESConfig(initial.Name, version.MustParse(initial.Spec.Version), corev1.IPv4Protocol, initial.Spec.HTTP, initial.Spec.NodeSets[0], nil, esv1.Auth{}, nil, nil)
+	require.NoError(t, err)
+	legacy, err := BuildStatefulSet(context.Background(), client, initial, initial.Spec.NodeSets[0], cfg, nil, nil, false, false)
+	require.NoError(t, err)
+	require.NotEqual(t, configHash(created), configHash(legacy))
+	// the configuration hash is stable
+	unchanged := build(initial, sset.StatefulSetList{legacy})
+	require.Equal(t, configHash(legacy), configHash(unchanged))
+	require.NotContains(t, unchanged.Spec.Template.Annotations, staticConfigHashAnnotationName)
+	// the Pods are rotated on the next configuration change, which then excludes the dynamic settings from the hash
+	updated := build(dynamicChange, sset.StatefulSetList{legacy})
+	require.NotEqual(t, configHash(legacy), configHash(updated))
+	require.Equal(t, "true", updated.Spec.Template.Annotations[staticConfigHashAnnotationName])
+}
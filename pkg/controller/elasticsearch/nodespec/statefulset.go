@@ -15,6 +15,7 @@ import (
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/defaults"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/hash"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/keystore"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/version"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/elasticsearch/label"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/elasticsearch/network"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/elasticsearch/settings"
@@ -65,8 +66,9 @@ func BuildStatefulSet(
 	keystoreResources *keystore.Resources,
 	existingStatefulSets sset.StatefulSetList,
 	setDefaultSecurityContext bool,
+	setVMMaxMapCount bool,
 ) (appsv1.StatefulSet, error) {
-	statefulSetName := esv1.StatefulSet(es.Name, nodeSet.Name)
+	statefulSetName := es.StatefulSetName(nodeSet.Name)
 
 	// ssetSelector is used to match the sset pods
 	ssetSelector := label.NewStatefulSetLabels(k8s.ExtractNamespacedName(&es), statefulSetName)
@@ -74,16 +76,27 @@ func BuildStatefulSet(
 	// add default PVCs to the node spec only if no user defined PVCs exist
 	nodeSet.VolumeClaimTemplates = defaults.AppendDefaultPVCs(
 		nodeSet.VolumeClaimTemplates,
-		nodeSet.PodTemplate.Spec,
+		esvolume.PodSpecWithMemoryDataVolume(nodeSet),
 		esvolume.DefaultVolumeClaimTemplates...,
 	)
 
 	// build pod template
-	podTemplate, err := BuildPodTemplateSpec(ctx, client, es, nodeSet, cfg, keystoreResources, setDefaultSecurityContext)
+	podTemplate, err := BuildPodTemplateSpec(ctx, client, es, nodeSet, cfg, keystoreResources, setDefaultSecurityContext, setVMMaxMapCount)
 	if err != nil {
 		return appsv1.StatefulSet{}, err
 	}
 
+	existingSset, exists := existingStatefulSets.GetByName(statefulSetName)
+	// only size the heap of new StatefulSets, or of StatefulSets already sized by the operator, not to change the heap of
+	// the nodes of existing clusters
+	if !exists || existingSset.Spec.Template.Annotations[defaultHeapSizeAnnotationName] == "true" {
+		ver, verErr := version.Parse(es.Spec.Version)
+		if verErr != nil {
+			return appsv1.StatefulSet{}, verErr
+		}
+		withDefaultHeapSize(&podTemplate, nodeSet, ver)
+	}
+
 	// build sset labels on top of the selector
 	// TODO: inherit user-provided labels and annotations from the CRD?
 	ssetLabels := make(map[string]string)
@@ -93,7 +106,7 @@ func BuildStatefulSet(
 
 	// maybe inherit volumeClaimTemplates ownerRefs from the existing StatefulSet
 	var existingClaims []corev1.PersistentVolumeClaim
-	if existingSset, exists := existingStatefulSets.GetByName(statefulSetName); exists {
+	if exists {
 		existingClaims = existingSset.Spec.VolumeClaimTemplates
 	}
 	claims := preserveExistingVolumeClaimsOwnerRefs(nodeSet.VolumeClaimTemplates, existingClaims)
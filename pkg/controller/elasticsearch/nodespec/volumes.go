@@ -19,11 +19,15 @@ import (
 
 func buildVolumes(
 	esName string,
+	ssetName string,
 	nodeSpec esv1.NodeSet,
+	snapshotVolumes []esv1.SnapshotVolume,
+	auth esv1.Auth,
 	keystoreResources *keystore.Resources,
 	downwardAPIVolume volume.DownwardAPI,
 ) ([]corev1.Volume, []corev1.VolumeMount) {
-	configVolume := settings.ConfigSecretVolume(esv1.StatefulSet(esName, nodeSpec.Name))
+	configVolume := settings.ConfigSecretVolume(ssetName)
+	jvmOptionsVolume := settings.JVMOptionsSecretVolume(ssetName)
 	probeSecret := volume.NewSelectiveSecretVolumeWithMountPath(
 		esv1.InternalUsersSecret(esName), esvolume.ProbeUserVolumeName,
 		esvolume.ProbeUserSecretMountPath, []string{user.ProbeUserName},
@@ -33,7 +37,7 @@ func buildVolumes(
 		esvolume.HTTPCertificatesSecretVolumeName,
 		esvolume.HTTPCertificatesSecretVolumeMountPath,
 	)
-	transportCertificatesVolume := transportCertificatesVolume(esv1.StatefulSet(esName, nodeSpec.Name))
+	transportCertificatesVolume := transportCertificatesVolume(ssetName)
 	remoteCertificateAuthoritiesVolume := volume.NewSecretVolumeWithMountPath(
 		esv1.RemoteCaSecretName(esName),
 		esvolume.RemoteCertificateAuthoritiesSecretVolumeName,
@@ -68,11 +72,16 @@ func buildVolumes(
 	}
 
 	volumes := persistentVolumes
+	if dataVolume, ok := esvolume.MemoryDataVolume(nodeSpec); ok {
+		volumes = append(volumes, dataVolume)
+	}
+	if !esvolume.HasLogsVolumeClaim(nodeSpec.VolumeClaimTemplates) {
+		volumes = append(volumes, esvolume.DefaultLogsVolume)
+	}
 	volumes = append(
 		volumes, // includes the data volume, unless specified differently in the pod template
 		append(
 			initcontainer.PluginVolumes.Volumes(),
-			esvolume.DefaultLogsVolume,
 			usersSecretVolume.Volume(),
 			unicastHostsVolume.Volume(),
 			probeSecret.Volume(),
@@ -86,6 +95,15 @@ func buildVolumes(
 	if keystoreResources != nil {
 		volumes = append(volumes, keystoreResources.Volume)
 	}
+	if len(nodeSpec.JVMOptions) > 0 {
+		volumes = append(volumes, jvmOptionsVolume.Volume())
+	}
+	sharedSnapshotVolumes, snapshotVolumeMounts := esvolume.SnapshotVolumes(snapshotVolumes)
+	volumes = append(volumes, sharedSnapshotVolumes...)
+	samlVolumes, samlVolumeMounts := esvolume.SAMLVolumes(auth.SAML)
+	volumes = append(volumes, samlVolumes...)
+	ldapVolumes, ldapVolumeMounts := esvolume.LDAPVolumes(auth.LDAP)
+	volumes = append(volumes, ldapVolumes...)
 
 	volumeMounts := append(
 		initcontainer.PluginVolumes.ContainerVolumeMounts(),
@@ -101,6 +119,13 @@ func buildVolumes(
 		downwardAPIVolume.VolumeMount(),
 	)
 
+	if len(nodeSpec.JVMOptions) > 0 {
+		volumeMounts = append(volumeMounts, jvmOptionsVolume.VolumeMount())
+	}
+	volumeMounts = append(volumeMounts, snapshotVolumeMounts...)
+	volumeMounts = append(volumeMounts, samlVolumeMounts...)
+	volumeMounts = append(volumeMounts, ldapVolumeMounts...)
+
 	volumeMounts = esvolume.AppendDefaultDataVolumeMount(volumeMounts, volumes)
 
 	return volumes, volumeMounts
@@ -10,6 +10,7 @@ import (
 	"reflect"
 
 	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
 	esv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/elasticsearch/v1"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/events"
@@ -164,6 +165,43 @@ func (s *State) UpdateMinRunningVersion(
 	return s
 }
 
+// UpdateSnapshotRepositories updates the status of the scheduled snapshots of the snapshot repositories.
+func (s *State) UpdateSnapshotRepositories(statuses []esv1.SnapshotRepositoryStatus) *State {
+	s.status.SnapshotRepositories = statuses
+	return s
+}
+
+// UpdateSnapshotRepositoriesVerification reports the result of the verification of the snapshot repositories.
+func (s *State) UpdateSnapshotRepositoriesVerification(status corev1.ConditionStatus, message string, verificationTime metav1.Time) *State {
+	s.ReportCondition(esv1.SnapshotRepositoriesVerified, status, message)
+	s.status.SnapshotRepositoriesVerificationTime = &verificationTime
+	return s
+}
+
+// UpdateInitialRestore updates the status of the restore of the initial snapshot.
+func (s *State) UpdateInitialRestore(status esv1.InitialRestoreStatus) *State {
+	s.status.InitialRestore = &status
+	return s
+}
+
+// UpdateRestoreVerification updates the status of the verification of the snapshots, or removes it if nil.
+func (s *State) UpdateRestoreVerification(status *esv1.RestoreVerificationStatus) *State {
+	s.status.RestoreVerification = status
+	return s
+}
+
+// UpdateLicense updates the status of the license applied to the cluster.
+func (s *State) UpdateLicense(status *esv1.LicenseStatus) *State {
+	s.status.License = status
+	return s
+}
+
+// UpdateConfigOverrides updates the settings defined with different values by several configuration sources.
+func (s *State) UpdateConfigOverrides(overrides []string) *State {
+	s.status.ConfigOverrides = overrides
+	return s
+}
+
 // UpdateElasticsearchInvalidWithEvent is a convenient method to set the phase to esv1.ElasticsearchResourceInvalid
 // and generate an event at the same time.
 func (s *State) UpdateElasticsearchInvalidWithEvent(msg string) {
@@ -6,6 +6,7 @@ package settings
 
 import (
 	"context"
+	"strings"
 
 	pkgerrors "github.com/pkg/errors"
 	corev1 "k8s.io/api/core/v1"
@@ -27,6 +28,13 @@ const (
 	ConfigVolumeMountPath = "/mnt/elastic-internal/elasticsearch-config"
 )
 
+// Constants to use for the JVM options file rendered from the JVM options of a NodeSet.
+const (
+	JVMOptionsFileName        = "eck.options"
+	JVMOptionsVolumeName      = "elastic-internal-jvm-options"
+	JVMOptionsVolumeMountPath = "/usr/share/elasticsearch/config/jvm.options.d"
+)
+
 // ConfigSecretName is the name of the secret that holds the ES config for the given StatefulSet.
 func ConfigSecretName(ssetName string) string {
 	return esv1.ConfigSecret(ssetName)
@@ -41,6 +49,25 @@ func ConfigSecretVolume(ssetName string) volume.SecretVolume {
 	)
 }
 
+// JVMOptionsSecretVolume returns a SecretVolume projecting the JVM options of the nodes in the given stateful set into
+// the jvm.options.d directory.
+func JVMOptionsSecretVolume(ssetName string) volume.SecretVolume {
+	return volume.NewSelectiveSecretVolumeWithMountPath(
+		ConfigSecretName(ssetName),
+		JVMOptionsVolumeName,
+		JVMOptionsVolumeMountPath,
+		[]string{JVMOptionsFileName},
+	)
+}
+
+// RenderJVMOptions renders the given JVM options in the format of a jvm.options file, one option per line.
+func RenderJVMOptions(jvmOptions []string) []byte {
+	if len(jvmOptions) == 0 {
+		return nil
+	}
+	return []byte(strings.Join(jvmOptions, "\n") + "\n")
+}
+
 // GetESConfigContent retrieves the configuration secret of the given stateful set,
 // and returns the corresponding CanonicalConfig.
 func GetESConfigContent(client k8s.Client, namespace string, ssetName string) (CanonicalConfig, error) {
@@ -75,8 +102,8 @@ func GetESConfigSecret(client k8s.Client, namespace string, ssetName string) (co
 	return secret, nil
 }
 
-func ConfigSecret(es esv1.Elasticsearch, ssetName string, configData []byte) corev1.Secret {
-	return corev1.Secret{
+func ConfigSecret(es esv1.Elasticsearch, ssetName string, configData []byte, jvmOptions []string) corev1.Secret {
+	secret := corev1.Secret{
 		ObjectMeta: metav1.ObjectMeta{
 			Namespace: es.Namespace,
 			Name:      ConfigSecretName(ssetName),
@@ -86,15 +113,19 @@ func ConfigSecret(es esv1.Elasticsearch, ssetName string, configData []byte) cor
 			ConfigFileName: configData,
 		},
 	}
+	if len(jvmOptions) > 0 {
+		secret.Data[JVMOptionsFileName] = RenderJVMOptions(jvmOptions)
+	}
+	return secret
 }
 
-// ReconcileConfig ensures the ES config for the pod is set in the apiserver.
-func ReconcileConfig(ctx context.Context, client k8s.Client, es esv1.Elasticsearch, ssetName string, config CanonicalConfig) error {
+// ReconcileConfig ensures the ES config and the JVM options for the pod are set in the apiserver.
+func ReconcileConfig(ctx context.Context, client k8s.Client, es esv1.Elasticsearch, ssetName string, config CanonicalConfig, jvmOptions []string) error {
 	rendered, err := config.Render()
 	if err != nil {
 		return err
 	}
-	expected := ConfigSecret(es, ssetName, rendered)
+	expected := ConfigSecret(es, ssetName, rendered, jvmOptions)
 	_, err = reconciler.ReconcileSecret(ctx, client, expected, &es)
 	return err
 }
@@ -0,0 +1,35 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package settings
+
+import (
+	"fmt"
+
+	"github.com/ghodss/yaml"
+
+	commonv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/common/v1"
+	esv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/elasticsearch/v1"
+	common "github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/settings"
+)
+
+// ParseDefaultConfig parses the Elasticsearch configuration the operator merges beneath the configuration of every
+// NodeSet. Settings reserved for internal use and node roles, which are specific to each NodeSet, are rejected.
+func ParseDefaultConfig(yml string) (*commonv1.Config, error) {
+	if yml == "" {
+		return nil, nil
+	}
+	var data map[string]interface{}
+	if err := yaml.Unmarshal([]byte(yml), &data); err != nil {
+		return nil, err
+	}
+	cfg, err := common.NewCanonicalConfigFrom(data)
+	if err != nil {
+		return nil, err
+	}
+	if forbidden := cfg.HasKeys(append([]string{esv1.NodeRoles}, esv1.UnsupportedSettings...)); len(forbidden) > 0 {
+		return nil, fmt.Errorf("default Elasticsearch configuration cannot contain the following settings: %v", forbidden)
+	}
+	return &commonv1.Config{Data: data}, nil
+}
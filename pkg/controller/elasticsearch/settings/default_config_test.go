@@ -0,0 +1,56 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package settings
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseDefaultConfig(t *testing.T) {
+	tests := []struct {
+		name    string
+		yml     string
+		wantNil bool
+		wantErr bool
+	}{
+		{
+			name:    "no default config",
+			yml:     "",
+			wantNil: true,
+		},
+		{
+			name: "valid default config",
+			yml:  "xpack.security.authc.api_key.enabled: true",
+		},
+		{
+			name:    "invalid yaml",
+			yml:     "xpack: [",
+			wantErr: true,
+		},
+		{
+			name:    "reserved setting",
+			yml:     "cluster.name: foo",
+			wantErr: true,
+		},
+		{
+			name:    "node roles",
+			yml:     "node.roles: [master]",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg, err := ParseDefaultConfig(tt.yml)
+			if tt.wantErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tt.wantNil, cfg == nil)
+		})
+	}
+}
@@ -0,0 +1,134 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package settings
+
+import (
+	"reflect"
+	"strings"
+
+	commonv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/common/v1"
+	esv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/elasticsearch/v1"
+	common "github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/settings"
+)
+
+// dynamicClusterSettings are the cluster-wide settings which can be updated through the cluster settings API without
+// restarting the nodes. Entries ending with a dot are prefixes.
+var dynamicClusterSettings = []string{
+	"action.auto_create_index",
+	"action.destructive_requires_name",
+	"cluster.blocks.",
+	"cluster.indices.close.enable",
+	"cluster.info.update.interval",
+	"cluster.max_shards_per_node",
+	"cluster.max_shards_per_node.frozen",
+	"cluster.persistent_tasks.allocation.enable",
+	"cluster.routing.allocation.",
+	"cluster.routing.rebalance.enable",
+	"indices.breaker.fielddata.limit",
+	"indices.breaker.fielddata.overhead",
+	"indices.breaker.request.limit",
+	"indices.breaker.request.overhead",
+	"indices.breaker.total.limit",
+	"indices.lifecycle.poll_interval",
+	"indices.recovery.",
+	"ingest.geoip.downloader.enabled",
+	"logger.",
+	"network.breaker.inflight_requests.limit",
+	"search.allow_expensive_queries",
+	"search.default_search_timeout",
+	"search.max_buckets",
+	"xpack.monitoring.collection.",
+}
+
+// staticClusterSettings are the exceptions to the prefixes of dynamicClusterSettings.
+var staticClusterSettings = []string{
+	"cluster.routing.allocation.type",
+}
+
+// operatorClusterSettings are the persistent cluster settings updated by the operator to orchestrate the cluster, which
+// must not be overwritten or reset with the value of the configuration of the nodes.
+var operatorClusterSettings = []string{
+	"cluster.routing.allocation.enable",
+	"cluster.routing.allocation.exclude._name",
+}
+
+// IsDynamicClusterSetting returns true if the given flattened setting key can be updated through the cluster settings
+// API without restarting the nodes.
+func IsDynamicClusterSetting(key string) bool {
+	for _, static := range staticClusterSettings {
+		if key == static {
+			return false
+		}
+	}
+	for _, managed := range operatorClusterSettings {
+		if key == managed {
+			return false
+		}
+	}
+	for _, dynamic := range dynamicClusterSettings {
+		if key == dynamic || (strings.HasSuffix(dynamic, ".") && strings.HasPrefix(key, dynamic)) {
+			return true
+		}
+	}
+	return false
+}
+
+// DynamicClusterSettings returns the dynamic cluster settings set with the same value in the configuration of all the
+// given NodeSets, indexed by their flattened key. Those settings are applied through the cluster settings API rather
+// than by restarting the nodes. Dynamic settings which differ between NodeSets are node-level settings in practice and
+// remain applied through the configuration file only.
+func DynamicClusterSettings(nodeSets []esv1.NodeSet) (map[string]interface{}, error) {
+	var dynamic map[string]interface{}
+	for i, nodeSet := range nodeSets {
+		userConfig, err := flatUserConfig(nodeSet)
+		if err != nil {
+			return nil, err
+		}
+		if i == 0 {
+			dynamic = make(map[string]interface{})
+			for key, value := range userConfig {
+				if IsDynamicClusterSetting(key) {
+					dynamic[key] = value
+				}
+			}
+			continue
+		}
+		for key, value := range dynamic {
+			if other, exists := userConfig[key]; !exists || !reflect.DeepEqual(value, other) {
+				delete(dynamic, key)
+			}
+		}
+	}
+	return dynamic, nil
+}
+
+// WithoutClusterSettings returns a copy of the given NodeSet whose configuration does not contain the given settings.
+// The copy is only meant to build the configuration of the NodeSet, it must not be stored in the Elasticsearch resource.
+func WithoutClusterSettings(nodeSet esv1.NodeSet, settings map[string]interface{}) (esv1.NodeSet, error) {
+	if len(settings) == 0 || nodeSet.Config == nil {
+		return nodeSet, nil
+	}
+	userConfig, err := flatUserConfig(nodeSet)
+	if err != nil {
+		return nodeSet, err
+	}
+	for key := range settings {
+		delete(userConfig, key)
+	}
+	nodeSet.Config = &commonv1.Config{Data: userConfig}
+	return nodeSet, nil
+}
+
+// flatUserConfig returns the user provided configuration of the given NodeSet, indexed by flattened key.
+func flatUserConfig(nodeSet esv1.NodeSet) (map[string]interface{}, error) {
+	if nodeSet.Config == nil {
+		return map[string]interface{}{}, nil
+	}
+	cfg, err := common.NewCanonicalConfigFrom(nodeSet.Config.Data)
+	if err != nil {
+		return nil, err
+	}
+	return cfg.Flatten()
+}
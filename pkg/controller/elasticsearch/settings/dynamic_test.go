@@ -0,0 +1,115 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package settings
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	commonv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/common/v1"
+	esv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/elasticsearch/v1"
+)
+
+func TestIsDynamicClusterSetting(t *testing.T) {
+	require.True(t, IsDynamicClusterSetting("cluster.routing.allocation.awareness.attributes"))
+	require.True(t, IsDynamicClusterSetting("cluster.routing.allocation.disk.watermark.low"))
+	require.True(t, IsDynamicClusterSetting("indices.breaker.total.limit"))
+	require.True(t, IsDynamicClusterSetting("logger.org.elasticsearch.discovery"))
+	require.False(t, IsDynamicClusterSetting("cluster.routing.allocation.type"))
+	require.False(t, IsDynamicClusterSetting("cluster.routing.allocation.enable"))
+	require.False(t, IsDynamicClusterSetting("cluster.routing.allocation.exclude._name"))
+	require.False(t, IsDynamicClusterSetting("indices.breaker.total.use_real_memory"))
+	require.False(t, IsDynamicClusterSetting("node.roles"))
+	require.False(t, IsDynamicClusterSetting("logger"))
+}
+
+func nodeSetWithConfig(name string, config map[string]interface{}) esv1.NodeSet {
+	nodeSet := esv1.NodeSet{Name: name}
+	if config != nil {
+		nodeSet.Config = &commonv1.Config{Data: config}
+	}
+	return nodeSet
+}
+
+func TestDynamicClusterSettings(t *testing.T) {
+	tests := []struct {
+		name     string
+		nodeSets []esv1.NodeSet
+		want     map[string]interface{}
+	}{
+		{
+			name:     "no NodeSet",
+			nodeSets: nil,
+			want:     nil,
+		},
+		{
+			name:     "no config",
+			nodeSets: []esv1.NodeSet{nodeSetWithConfig("a", nil)},
+			want:     map[string]interface{}{},
+		},
+		{
+			name: "static settings and settings managed by the operator are ignored",
+			nodeSets: []esv1.NodeSet{nodeSetWithConfig("a", map[string]interface{}{
+				"node.roles":                                      []string{"master"},
+				"cluster.routing.allocation.enable":               "primaries",
+				"cluster.routing.allocation.awareness.attributes": "zone",
+			})},
+			want: map[string]interface{}{"cluster.routing.allocation.awareness.attributes": "zone"},
+		},
+		{
+			name: "nested settings are flattened",
+			nodeSets: []esv1.NodeSet{nodeSetWithConfig("a", map[string]interface{}{
+				"action": map[string]interface{}{"destructive_requires_name": true},
+			})},
+			want: map[string]interface{}{"action.destructive_requires_name": true},
+		},
+		{
+			name: "settings must be set with the same value in all NodeSets",
+			nodeSets: []esv1.NodeSet{
+				nodeSetWithConfig("a", map[string]interface{}{
+					"cluster.routing.allocation.awareness.attributes": "zone",
+					"cluster.routing.rebalance.enable":                "none",
+					"indices.recovery.max_bytes_per_sec":              "100mb",
+					"xpack.monitoring.collection.enabled":             true,
+					"cluster.routing.allocation.awareness.x":          "y",
+				}),
+				nodeSetWithConfig("b", map[string]interface{}{
+					"cluster.routing.allocation.awareness.attributes": "zone",
+					"cluster.routing.rebalance.enable":                "all",
+					"xpack.monitoring.collection.enabled":             true,
+				}),
+			},
+			want: map[string]interface{}{
+				"cluster.routing.allocation.awareness.attributes": "zone",
+				"xpack.monitoring.collection.enabled":             true,
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := DynamicClusterSettings(tt.nodeSets)
+			require.NoError(t, err)
+			require.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestWithoutClusterSettings(t *testing.T) {
+	nodeSet := nodeSetWithConfig("a", map[string]interface{}{
+		"node.roles": []string{"master"},
+		"cluster":    map[string]interface{}{"routing.allocation.awareness.attributes": "zone"},
+	})
+	got, err := WithoutClusterSettings(nodeSet, map[string]interface{}{"cluster.routing.allocation.awareness.attributes": "zone"})
+	require.NoError(t, err)
+	require.Equal(t, map[string]interface{}{"node.roles": []interface{}{"master"}}, got.Config.Data)
+	// the given NodeSet is not modified
+	require.Contains(t, nodeSet.Config.Data, "cluster")
+
+	// no settings to remove
+	got, err = WithoutClusterSettings(nodeSet, nil)
+	require.NoError(t, err)
+	require.Equal(t, nodeSet, got)
+}
@@ -7,6 +7,7 @@ package settings
 // Environment variables applied to an Elasticsearch pod
 const (
 	EnvEsJavaOpts = "ES_JAVA_OPTS"
+	EnvEsLogStyle = "ES_LOG_STYLE"
 
 	EnvProbePasswordPath      = "PROBE_PASSWORD_PATH"
 	EnvProbeUsername          = "PROBE_USERNAME"
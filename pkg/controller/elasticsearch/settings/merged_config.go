@@ -21,36 +21,83 @@ import (
 )
 
 // the name of the ES attribute indicating the pod's current k8s node
-const nodeAttrK8sNodeName = "k8s_node_name"
+var nodeAttrNodeName = fmt.Sprintf("%s.%s", esv1.NodeAttr, esv1.K8sNodeNameAttr)
 
-var nodeAttrNodeName = fmt.Sprintf("%s.%s", esv1.NodeAttr, nodeAttrK8sNodeName)
+// Names of the configuration sources merged into the Elasticsearch configuration, used to report conflicts.
+const (
+	baseConfigSource       = "operator base settings"
+	xpackConfigSource      = "operator security settings"
+	realmsConfigSource     = "operator realm settings"
+	auditConfigSource      = "operator audit settings"
+	defaultConfigSource    = "operator default configuration"
+	dataTierConfigSource   = "data tier settings"
+	attributesConfigSource = "node attributes"
+	userConfigSource       = "user configuration"
+)
 
-// NewMergedESConfig merges user provided Elasticsearch configuration with configuration derived from the given
-// parameters. The user provided config overrides have precedence over the ECK config.
+// NewMergedESConfig merges the configuration of the given NodeSet with configuration derived from the given
+// parameters. The user provided config overrides have precedence over the operator default config, which itself
+// has precedence over the ECK config.
 func NewMergedESConfig(
 	clusterName string,
 	ver version.Version,
 	ipFamily corev1.IPFamily,
 	httpConfig commonv1.HTTPConfig,
-	userConfig commonv1.Config,
+	nodeSet esv1.NodeSet,
+	snapshotVolumes []esv1.SnapshotVolume,
+	auth esv1.Auth,
+	auditLogging *esv1.AuditLogging,
+	defaultConfig *commonv1.Config,
 ) (CanonicalConfig, error) {
+	config, _, err := NewMergedESConfigWithOverrides(clusterName, ver, ipFamily, httpConfig, nodeSet, snapshotVolumes, auth, auditLogging, defaultConfig)
+	return config, err
+}
+
+// NewMergedESConfigWithOverrides is like NewMergedESConfig but also returns the settings defined with different values
+// by several configuration sources, for example operator-managed settings overridden by the user configuration.
+func NewMergedESConfigWithOverrides(
+	clusterName string,
+	ver version.Version,
+	ipFamily corev1.IPFamily,
+	httpConfig commonv1.HTTPConfig,
+	nodeSet esv1.NodeSet,
+	snapshotVolumes []esv1.SnapshotVolume,
+	auth esv1.Auth,
+	auditLogging *esv1.AuditLogging,
+	defaultConfig *commonv1.Config,
+) (CanonicalConfig, common.ConfigOverrides, error) {
+	userConfig := commonv1.Config{}
+	if nodeSet.Config != nil {
+		userConfig = *nodeSet.Config
+	}
 	userCfg, err := common.NewCanonicalConfigFrom(userConfig.Data)
 	if err != nil {
-		return CanonicalConfig{}, err
+		return CanonicalConfig{}, nil, err
+	}
+	defaultCfg := common.NewCanonicalConfig()
+	if defaultConfig != nil {
+		if defaultCfg, err = common.NewCanonicalConfigFrom(defaultConfig.Data); err != nil {
+			return CanonicalConfig{}, nil, err
+		}
 	}
-	config := baseConfig(clusterName, ver, ipFamily).CanonicalConfig
-	err = config.MergeWith(
-		xpackConfig(ver, httpConfig).CanonicalConfig,
-		userCfg,
+	config, overrides, err := common.MergeSources(
+		common.ConfigSource{Name: baseConfigSource, Config: baseConfig(clusterName, ver, ipFamily, snapshotVolumes).CanonicalConfig},
+		common.ConfigSource{Name: xpackConfigSource, Config: xpackConfig(ver, httpConfig).CanonicalConfig},
+		common.ConfigSource{Name: realmsConfigSource, Config: realmsConfig(auth).CanonicalConfig},
+		common.ConfigSource{Name: auditConfigSource, Config: auditConfig(ver, auditLogging).CanonicalConfig},
+		common.ConfigSource{Name: defaultConfigSource, Config: defaultCfg},
+		common.ConfigSource{Name: dataTierConfigSource, Config: dataTierConfig(nodeSet.DataTier, userCfg).CanonicalConfig},
+		common.ConfigSource{Name: attributesConfigSource, Config: attributesConfig(nodeSet.Attributes).CanonicalConfig},
+		common.ConfigSource{Name: userConfigSource, Config: userCfg},
 	)
 	if err != nil {
-		return CanonicalConfig{}, err
+		return CanonicalConfig{}, nil, err
 	}
-	return CanonicalConfig{config}, nil
+	return CanonicalConfig{config}, overrides, nil
 }
 
 // baseConfig returns the base ES configuration to apply for the given cluster
-func baseConfig(clusterName string, ver version.Version, ipFamily corev1.IPFamily) *CanonicalConfig {
+func baseConfig(clusterName string, ver version.Version, ipFamily corev1.IPFamily, snapshotVolumes []esv1.SnapshotVolume) *CanonicalConfig {
 	cfg := map[string]interface{}{
 		// derive node name dynamically from the pod name, injected as env var
 		esv1.NodeName:    "${" + EnvPodName + "}",
@@ -62,13 +109,18 @@ func baseConfig(clusterName string, ver version.Version, ipFamily corev1.IPFamil
 		esv1.NetworkHost:        "0",
 
 		// allow ES to be aware of k8s node the pod is running on when allocating shards
-		esv1.ShardAwarenessAttributes: nodeAttrK8sNodeName,
+		esv1.ShardAwarenessAttributes: esv1.K8sNodeNameAttr,
 		nodeAttrNodeName:              "${" + EnvNodeName + "}",
 
 		esv1.PathData: volume.ElasticsearchDataMountPath,
 		esv1.PathLogs: volume.ElasticsearchLogsMountPath,
 	}
 
+	// register the shared filesystem volumes mounted on all the nodes as snapshot repository locations
+	if len(snapshotVolumes) > 0 {
+		cfg[esv1.PathRepo] = volume.SnapshotRepositoryPaths(snapshotVolumes)
+	}
+
 	// seed hosts setting name changed starting ES 7.X
 	fileProvider := "file"
 	if ver.Major < 7 {
@@ -137,3 +189,186 @@ func xpackConfig(ver version.Version, httpCfg commonv1.HTTPConfig) *CanonicalCon
 
 	return &CanonicalConfig{common.MustCanonicalConfig(cfg)}
 }
+
+// realmsConfig returns the settings of the SAML, OIDC and LDAP realms declared in the Elasticsearch spec.
+func realmsConfig(auth esv1.Auth) *CanonicalConfig {
+	if len(auth.SAML) == 0 && len(auth.OIDC) == 0 && len(auth.LDAP) == 0 {
+		return &CanonicalConfig{common.NewCanonicalConfig()}
+	}
+	cfg := map[string]interface{}{}
+	if len(auth.SAML) > 0 || len(auth.OIDC) > 0 {
+		// SAML and OIDC authentication rely on the token service, only enabled by default with TLS on the HTTP layer
+		cfg[esv1.XPackSecurityAuthcTokenEnabled] = true
+	}
+	addSAMLRealms(cfg, auth.SAML)
+	addOIDCRealms(cfg, auth.OIDC)
+	addLDAPRealms(cfg, auth.LDAP)
+	return &CanonicalConfig{common.MustCanonicalConfig(cfg)}
+}
+
+// addSAMLRealms adds the settings of the given SAML realms to cfg, referencing the identity provider metadata and the
+// signing keys mounted in the Elasticsearch container.
+func addSAMLRealms(cfg map[string]interface{}, realms []esv1.SAMLRealm) {
+	for _, realm := range realms {
+		prefix := esv1.XPackSecurityAuthcRealmsSAML + "." + realm.Name + "."
+		cfg[prefix+"order"] = realm.Order
+		cfg[prefix+"idp.entity_id"] = realm.IdP.EntityID
+		metadataPath := realm.IdP.MetadataURL
+		if realm.IdP.MetadataConfigMapName != "" {
+			metadataPath = volume.SAMLMetadataPath(realm)
+		}
+		cfg[prefix+"idp.metadata.path"] = metadataPath
+		cfg[prefix+"sp.entity_id"] = realm.SP.EntityID
+		cfg[prefix+"sp.acs"] = realm.SP.ACS
+		if realm.SP.Logout != "" {
+			cfg[prefix+"sp.logout"] = realm.SP.Logout
+		}
+		addAttributes(cfg, prefix+"attributes.", realm.Attributes.Principal, realm.Attributes.Groups, realm.Attributes.Name, realm.Attributes.Mail)
+		if realm.SigningSecretName != "" {
+			cfg[prefix+"signing.certificate"] = volume.SAMLSigningCertificatePath(realm)
+			cfg[prefix+"signing.key"] = volume.SAMLSigningKeyPath(realm)
+		}
+	}
+}
+
+// addOIDCRealms adds the settings of the given OIDC realms to cfg. The client secrets are part of the secure settings.
+func addOIDCRealms(cfg map[string]interface{}, realms []esv1.OIDCRealm) {
+	for _, realm := range realms {
+		prefix := esv1.XPackSecurityAuthcRealmsOIDC + "." + realm.Name + "."
+		cfg[prefix+"order"] = realm.Order
+		cfg[prefix+"rp.client_id"] = realm.RP.ClientID
+		cfg[prefix+"rp.redirect_uri"] = realm.RP.RedirectURI
+		responseType := realm.RP.ResponseType
+		if responseType == "" {
+			responseType = "code"
+		}
+		cfg[prefix+"rp.response_type"] = responseType
+		if realm.RP.PostLogoutRedirectURI != "" {
+			cfg[prefix+"rp.post_logout_redirect_uri"] = realm.RP.PostLogoutRedirectURI
+		}
+		if len(realm.RP.RequestedScopes) > 0 {
+			cfg[prefix+"rp.requested_scopes"] = realm.RP.RequestedScopes
+		}
+		cfg[prefix+"op.issuer"] = realm.OP.Issuer
+		cfg[prefix+"op.authorization_endpoint"] = realm.OP.AuthorizationEndpoint
+		cfg[prefix+"op.jwkset_path"] = realm.OP.JWKSetURL
+		optional := map[string]string{
+			"op.token_endpoint":      realm.OP.TokenEndpoint,
+			"op.userinfo_endpoint":   realm.OP.UserinfoEndpoint,
+			"op.endsession_endpoint": realm.OP.EndSessionEndpoint,
+		}
+		for setting, value := range optional {
+			if value != "" {
+				cfg[prefix+setting] = value
+			}
+		}
+		addAttributes(cfg, prefix+"claims.", realm.Claims.Principal, realm.Claims.Groups, realm.Claims.Name, realm.Claims.Mail)
+	}
+}
+
+// addLDAPRealms adds the settings of the given LDAP and Active Directory realms to cfg, referencing the certificate
+// authorities mounted in the Elasticsearch container. The bind passwords are part of the secure settings.
+func addLDAPRealms(cfg map[string]interface{}, realms []esv1.LDAPRealm) {
+	for _, realm := range realms {
+		prefix := realm.SettingsPrefix() + "."
+		cfg[prefix+"order"] = realm.Order
+		if len(realm.URLs) > 0 {
+			cfg[prefix+"url"] = realm.URLs
+		}
+		if realm.RealmType() == esv1.LDAPRealmTypeActiveDirectory {
+			cfg[prefix+"domain_name"] = realm.Domain
+		}
+		optional := map[string]string{
+			"bind_dn":              realm.BindDN,
+			"user_search.base_dn":  realm.UserSearchBaseDN,
+			"user_search.filter":   realm.UserSearchFilter,
+			"group_search.base_dn": realm.GroupSearchBaseDN,
+		}
+		for setting, value := range optional {
+			if value != "" {
+				cfg[prefix+setting] = value
+			}
+		}
+		if len(realm.UserDNTemplates) > 0 {
+			cfg[prefix+"user_dn_templates"] = realm.UserDNTemplates
+		}
+		if realm.UnmappedGroupsAsRoles {
+			cfg[prefix+"unmapped_groups_as_roles"] = true
+		}
+		if realm.CASecretName != "" {
+			cfg[prefix+"ssl.certificate_authorities"] = []string{volume.LDAPCAPath(realm)}
+		}
+	}
+}
+
+// auditConfig returns the settings enabling the security audit log as declared in the Elasticsearch spec. Audit events
+// are written to the logfile output, next to the other logs of the node.
+func auditConfig(ver version.Version, audit *esv1.AuditLogging) *CanonicalConfig {
+	if audit == nil {
+		return &CanonicalConfig{common.NewCanonicalConfig()}
+	}
+	cfg := map[string]interface{}{
+		esv1.XPackSecurityAuditEnabled: true,
+	}
+	if ver.Major < 7 {
+		// the logfile output is the only one left starting 7.0, where this setting was removed
+		cfg[esv1.XPackSecurityAuditOutputs] = []string{"logfile"}
+	}
+	if len(audit.IncludeEvents) > 0 {
+		cfg[esv1.XPackSecurityAuditLogfileEventsInclude] = audit.IncludeEvents
+	}
+	if len(audit.ExcludeEvents) > 0 {
+		cfg[esv1.XPackSecurityAuditLogfileEventsExclude] = audit.ExcludeEvents
+	}
+	if audit.EmitRequestBody {
+		cfg[esv1.XPackSecurityAuditLogfileEventsEmitBody] = true
+	}
+	for _, filter := range audit.IgnoreFilters {
+		prefix := esv1.XPackSecurityAuditLogfileEventsIgnoreFilters + "." + filter.Name + "."
+		for setting, values := range map[string][]string{
+			"users": filter.Users, "realms": filter.Realms, "roles": filter.Roles, "indices": filter.Indices,
+		} {
+			if len(values) > 0 {
+				cfg[prefix+setting] = values
+			}
+		}
+	}
+	return &CanonicalConfig{common.MustCanonicalConfig(cfg)}
+}
+
+// addAttributes adds to cfg the user properties mapped from the given SAML attributes or OIDC claims.
+func addAttributes(cfg map[string]interface{}, prefix string, principal, groups, name, mail string) {
+	cfg[prefix+"principal"] = principal
+	for property, attribute := range map[string]string{"groups": groups, "name": name, "mail": mail} {
+		if attribute != "" {
+			cfg[prefix+property] = attribute
+		}
+	}
+}
+
+// dataTierConfig returns the node attribute and roles derived from the data tier of a NodeSet. Node roles are only
+// set if they are not already part of the user provided configuration.
+func dataTierConfig(dataTier esv1.DataTier, userCfg *common.CanonicalConfig) *CanonicalConfig {
+	if dataTier == "" {
+		return &CanonicalConfig{common.NewCanonicalConfig()}
+	}
+	cfg := map[string]interface{}{
+		esv1.NodeAttrDataTier: string(dataTier),
+	}
+	if len(userCfg.HasKeys([]string{esv1.NodeRoles})) == 0 {
+		cfg[esv1.NodeRoles] = dataTier.Roles()
+	}
+	return &CanonicalConfig{common.MustCanonicalConfig(cfg)}
+}
+
+// attributesConfig returns the node.attr.* settings corresponding to the given custom node attributes.
+func attributesConfig(attributes map[string]string) *CanonicalConfig {
+	if len(attributes) == 0 {
+		return &CanonicalConfig{common.NewCanonicalConfig()}
+	}
+	cfg := make(map[string]interface{}, len(attributes))
+	for name, value := range attributes {
+		cfg[esv1.NodeAttr+"."+name] = value
+	}
+	return &CanonicalConfig{common.MustCanonicalConfig(cfg)}
+}
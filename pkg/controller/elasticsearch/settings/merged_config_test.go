@@ -34,14 +34,26 @@ func TestNewMergedESConfig(t *testing.T) {
 		Network struct {
 			PublishHost string `yaml:"publish_host"`
 		} `yaml:"network"`
+		Node struct {
+			Roles []string `yaml:"roles"`
+			Attr  struct {
+				Data string `yaml:"data"`
+			} `yaml:"attr"`
+		} `yaml:"node"`
 	}
 
 	tests := []struct {
-		name     string
-		version  string
-		ipFamily corev1.IPFamily
-		cfgData  map[string]interface{}
-		assert   func(cfg CanonicalConfig)
+		name          string
+		version       string
+		ipFamily      corev1.IPFamily
+		cfgData       map[string]interface{}
+		dataTier      esv1.DataTier
+		attributes    map[string]string
+		defaultConfig *commonv1.Config
+		snapshotVols  []esv1.SnapshotVolume
+		auth          esv1.Auth
+		auditLogging  *esv1.AuditLogging
+		assert        func(cfg CanonicalConfig)
 	}{
 		{
 			name:     "in 6.x, empty config should have the default file and native realm settings configured",
@@ -209,6 +221,390 @@ func TestNewMergedESConfig(t *testing.T) {
 				require.Equal(t, "[${POD_IP}]", esCfg.Network.PublishHost)
 			},
 		},
+		{
+			name:     "data tier roles and attribute are set",
+			version:  "7.10.0",
+			ipFamily: corev1.IPv4Protocol,
+			cfgData:  map[string]interface{}{},
+			dataTier: esv1.DataTierHot,
+			assert: func(cfg CanonicalConfig) {
+				cfgBytes, err := cfg.Render()
+				require.NoError(t, err)
+				esCfg := &elasticsearchCfg{}
+				require.NoError(t, yaml.Unmarshal(cfgBytes, &esCfg))
+				require.Equal(t, []string{"data_hot", "data_content"}, esCfg.Node.Roles)
+				require.Equal(t, "hot", esCfg.Node.Attr.Data)
+			},
+		},
+		{
+			name:     "user provided node roles have precedence over the data tier roles",
+			version:  "7.10.0",
+			ipFamily: corev1.IPv4Protocol,
+			cfgData: map[string]interface{}{
+				esv1.NodeRoles: []string{"data_warm", "ingest"},
+			},
+			dataTier: esv1.DataTierWarm,
+			assert: func(cfg CanonicalConfig) {
+				cfgBytes, err := cfg.Render()
+				require.NoError(t, err)
+				esCfg := &elasticsearchCfg{}
+				require.NoError(t, yaml.Unmarshal(cfgBytes, &esCfg))
+				require.Equal(t, []string{"data_warm", "ingest"}, esCfg.Node.Roles)
+				require.Equal(t, "warm", esCfg.Node.Attr.Data)
+			},
+		},
+		{
+			name:     "node attributes are set",
+			version:  "7.10.0",
+			ipFamily: corev1.IPv4Protocol,
+			cfgData: map[string]interface{}{
+				"node.attr.rack": "user-rack",
+			},
+			attributes: map[string]string{"rack": "rack-1", "storage": "ssd"},
+			assert: func(cfg CanonicalConfig) {
+				cfgBytes, err := cfg.Render()
+				require.NoError(t, err)
+				esCfg := &struct {
+					Node struct {
+						Attr map[string]string `yaml:"attr"`
+					} `yaml:"node"`
+				}{}
+				require.NoError(t, yaml.Unmarshal(cfgBytes, &esCfg))
+				require.Equal(t, "ssd", esCfg.Node.Attr["storage"])
+				// the user config takes precedence
+				require.Equal(t, "user-rack", esCfg.Node.Attr["rack"])
+				// operator managed attributes are preserved
+				require.Equal(t, "${NODE_NAME}", esCfg.Node.Attr["k8s_node_name"])
+			},
+		},
+		{
+			name:     "operator default config is merged beneath the user config",
+			version:  "7.10.0",
+			ipFamily: corev1.IPv4Protocol,
+			cfgData: map[string]interface{}{
+				"index.search.slowlog.threshold.query.warn": "5s",
+			},
+			defaultConfig: &commonv1.Config{Data: map[string]interface{}{
+				"xpack.security.authc.api_key.enabled":      true,
+				"index.search.slowlog.threshold.query.warn": "10s",
+			}},
+			assert: func(cfg CanonicalConfig) {
+				require.Equal(t, 1, len(cfg.HasKeys([]string{"xpack.security.authc.api_key.enabled"})))
+				cfgBytes, err := cfg.Render()
+				require.NoError(t, err)
+				// but the user config takes precedence
+				require.Contains(t, string(cfgBytes), "warn: 5s")
+				require.NotContains(t, string(cfgBytes), "10s")
+			},
+		},
+		{
+			name:     "snapshot volumes are registered in path.repo",
+			version:  "8.5.0",
+			ipFamily: corev1.IPv4Protocol,
+			cfgData:  map[string]interface{}{},
+			snapshotVols: []esv1.SnapshotVolume{
+				{Name: "nfs", NFS: &corev1.NFSVolumeSource{Server: "nfs.example.com", Path: "/exports/snapshots"}},
+				{Name: "shared", ClaimName: "shared-snapshots"},
+			},
+			assert: func(cfg CanonicalConfig) {
+				cfgBytes, err := cfg.Render()
+				require.NoError(t, err)
+				esCfg := &struct {
+					Path struct {
+						Repo []string `yaml:"repo"`
+					} `yaml:"path"`
+				}{}
+				require.NoError(t, yaml.Unmarshal(cfgBytes, &esCfg))
+				require.Equal(t, []string{"/usr/share/elasticsearch/snapshots/nfs", "/usr/share/elasticsearch/snapshots/shared"}, esCfg.Path.Repo)
+			},
+		},
+		{
+			name:     "SAML realms are rendered with the mounted metadata and signing keys",
+			version:  "8.5.0",
+			ipFamily: corev1.IPv4Protocol,
+			cfgData:  map[string]interface{}{},
+			auth: esv1.Auth{SAML: []esv1.SAMLRealm{
+				{
+					Name:              "okta",
+					Order:             2,
+					IdP:               esv1.SAMLIdentityProvider{EntityID: "http://www.okta.com/abc", MetadataConfigMapName: "okta-metadata"},
+					SP:                esv1.SAMLServiceProvider{EntityID: "https://kibana.example.com", ACS: "https://kibana.example.com/api/security/saml/callback"},
+					Attributes:        esv1.SAMLAttributes{Principal: "nameid", Groups: "groups"},
+					SigningSecretName: "okta-signing",
+				},
+				{
+					Name:       "adfs",
+					Order:      3,
+					IdP:        esv1.SAMLIdentityProvider{EntityID: "https://adfs.example.com", MetadataURL: "https://adfs.example.com/metadata.xml"},
+					SP:         esv1.SAMLServiceProvider{EntityID: "https://kibana.example.com", ACS: "https://kibana.example.com/api/security/saml/callback"},
+					Attributes: esv1.SAMLAttributes{Principal: "nameid"},
+				},
+			}},
+			assert: func(cfg CanonicalConfig) {
+				cfgBytes, err := cfg.Render()
+				require.NoError(t, err)
+				type samlRealmCfg struct {
+					Order int `yaml:"order"`
+					IdP   struct {
+						EntityID string `yaml:"entity_id"`
+						Metadata struct {
+							Path string `yaml:"path"`
+						} `yaml:"metadata"`
+					} `yaml:"idp"`
+					SP         map[string]string `yaml:"sp"`
+					Attributes map[string]string `yaml:"attributes"`
+					Signing    map[string]string `yaml:"signing"`
+				}
+				esCfg := &struct {
+					XPack struct {
+						Security struct {
+							Authc struct {
+								Token struct {
+									Enabled bool `yaml:"enabled"`
+								} `yaml:"token"`
+								Realms struct {
+									SAML map[string]samlRealmCfg `yaml:"saml"`
+								} `yaml:"realms"`
+							} `yaml:"authc"`
+						} `yaml:"security"`
+					} `yaml:"xpack"`
+				}{}
+				require.NoError(t, yaml.Unmarshal(cfgBytes, &esCfg))
+				require.True(t, esCfg.XPack.Security.Authc.Token.Enabled)
+				realms := esCfg.XPack.Security.Authc.Realms.SAML
+				require.Len(t, realms, 2)
+				okta := realms["okta"]
+				require.Equal(t, 2, okta.Order)
+				require.Equal(t, "http://www.okta.com/abc", okta.IdP.EntityID)
+				require.Equal(t, "/usr/share/elasticsearch/config/saml/okta/idp/metadata.xml", okta.IdP.Metadata.Path)
+				require.Equal(t, map[string]string{
+					"entity_id": "https://kibana.example.com",
+					"acs":       "https://kibana.example.com/api/security/saml/callback",
+				}, okta.SP)
+				require.Equal(t, map[string]string{"principal": "nameid", "groups": "groups"}, okta.Attributes)
+				require.Equal(t, map[string]string{
+					"certificate": "/usr/share/elasticsearch/config/saml/okta/signing/tls.crt",
+					"key":         "/usr/share/elasticsearch/config/saml/okta/signing/tls.key",
+				}, okta.Signing)
+				adfs := realms["adfs"]
+				require.Equal(t, "https://adfs.example.com/metadata.xml", adfs.IdP.Metadata.Path)
+				require.Equal(t, map[string]string{"principal": "nameid"}, adfs.Attributes)
+				require.Empty(t, adfs.Signing)
+			},
+		},
+		{
+			name:     "OIDC realms are rendered without the client secret",
+			version:  "8.5.0",
+			ipFamily: corev1.IPv4Protocol,
+			cfgData:  map[string]interface{}{},
+			auth: esv1.Auth{OIDC: []esv1.OIDCRealm{
+				{
+					Name:  "google",
+					Order: 2,
+					RP: esv1.OIDCRelyingParty{
+						ClientID:        "client-id",
+						ClientSecretRef: commonv1.SecretRef{SecretName: "google-client-secret"},
+						RedirectURI:     "https://kibana.example.com/api/security/oidc/callback",
+						RequestedScopes: []string{"openid", "email"},
+					},
+					OP: esv1.OIDCProvider{
+						Issuer:                "https://accounts.google.com",
+						AuthorizationEndpoint: "https://accounts.google.com/o/oauth2/v2/auth",
+						TokenEndpoint:         "https://oauth2.googleapis.com/token",
+						JWKSetURL:             "https://www.googleapis.com/oauth2/v3/certs",
+					},
+					Claims: esv1.OIDCClaims{Principal: "email", Mail: "email"},
+				},
+			}},
+			assert: func(cfg CanonicalConfig) {
+				cfgBytes, err := cfg.Render()
+				require.NoError(t, err)
+				type oidcRealmCfg struct {
+					Order int `yaml:"order"`
+					RP    struct {
+						ClientID        string   `yaml:"client_id"`
+						ClientSecret    string   `yaml:"client_secret"`
+						RedirectURI     string   `yaml:"redirect_uri"`
+						ResponseType    string   `yaml:"response_type"`
+						RequestedScopes []string `yaml:"requested_scopes"`
+					} `yaml:"rp"`
+					OP     map[string]string `yaml:"op"`
+					Claims map[string]string `yaml:"claims"`
+				}
+				esCfg := &struct {
+					XPack struct {
+						Security struct {
+							Authc struct {
+								Token struct {
+									Enabled bool `yaml:"enabled"`
+								} `yaml:"token"`
+								Realms struct {
+									OIDC map[string]oidcRealmCfg `yaml:"oidc"`
+								} `yaml:"realms"`
+							} `yaml:"authc"`
+						} `yaml:"security"`
+					} `yaml:"xpack"`
+				}{}
+				require.NoError(t, yaml.Unmarshal(cfgBytes, &esCfg))
+				require.True(t, esCfg.XPack.Security.Authc.Token.Enabled)
+				google, exists := esCfg.XPack.Security.Authc.Realms.OIDC["google"]
+				require.True(t, exists)
+				require.Equal(t, 2, google.Order)
+				require.Equal(t, "client-id", google.RP.ClientID)
+				// the client secret is a secure setting stored in the keystore
+				require.Empty(t, google.RP.ClientSecret)
+				require.Equal(t, "https://kibana.example.com/api/security/oidc/callback", google.RP.RedirectURI)
+				require.Equal(t, "code", google.RP.ResponseType)
+				require.Equal(t, []string{"openid", "email"}, google.RP.RequestedScopes)
+				require.Equal(t, map[string]string{
+					"issuer":                 "https://accounts.google.com",
+					"authorization_endpoint": "https://accounts.google.com/o/oauth2/v2/auth",
+					"token_endpoint":         "https://oauth2.googleapis.com/token",
+					"jwkset_path":            "https://www.googleapis.com/oauth2/v3/certs",
+				}, google.OP)
+				require.Equal(t, map[string]string{"principal": "email", "mail": "email"}, google.Claims)
+			},
+		},
+		{
+			name:     "LDAP realms are rendered with the mounted certificate authority",
+			version:  "8.5.0",
+			ipFamily: corev1.IPv4Protocol,
+			cfgData:  map[string]interface{}{},
+			auth: esv1.Auth{LDAP: []esv1.LDAPRealm{
+				{
+					Name:                  "corp",
+					Order:                 2,
+					URLs:                  []string{"ldaps://ldap.example.com:636"},
+					BindDN:                "cn=admin,dc=example,dc=com",
+					BindPasswordSecretRef: &commonv1.SecretRef{SecretName: "corp-bind-password"},
+					UserSearchBaseDN:      "ou=users,dc=example,dc=com",
+					GroupSearchBaseDN:     "ou=groups,dc=example,dc=com",
+					CASecretName:          "corp-ca",
+				},
+				{
+					Name:   "ad",
+					Type:   esv1.LDAPRealmTypeActiveDirectory,
+					Order:  3,
+					Domain: "example.com",
+				},
+			}},
+			assert: func(cfg CanonicalConfig) {
+				cfgBytes, err := cfg.Render()
+				require.NoError(t, err)
+				type ldapRealmCfg struct {
+					Order       int               `yaml:"order"`
+					URL         []string          `yaml:"url"`
+					DomainName  string            `yaml:"domain_name"`
+					BindDN      string            `yaml:"bind_dn"`
+					UserSearch  map[string]string `yaml:"user_search"`
+					GroupSearch map[string]string `yaml:"group_search"`
+					SSL         struct {
+						CertificateAuthorities []string `yaml:"certificate_authorities"`
+					} `yaml:"ssl"`
+				}
+				esCfg := &struct {
+					XPack struct {
+						Security struct {
+							Authc struct {
+								Token struct {
+									Enabled *bool `yaml:"enabled"`
+								} `yaml:"token"`
+								Realms struct {
+									LDAP            map[string]ldapRealmCfg `yaml:"ldap"`
+									ActiveDirectory map[string]ldapRealmCfg `yaml:"active_directory"`
+								} `yaml:"realms"`
+							} `yaml:"authc"`
+						} `yaml:"security"`
+					} `yaml:"xpack"`
+				}{}
+				require.NoError(t, yaml.Unmarshal(cfgBytes, &esCfg))
+				// LDAP realms do not rely on the token service
+				require.Nil(t, esCfg.XPack.Security.Authc.Token.Enabled)
+				corp := esCfg.XPack.Security.Authc.Realms.LDAP["corp"]
+				require.Equal(t, 2, corp.Order)
+				require.Equal(t, []string{"ldaps://ldap.example.com:636"}, corp.URL)
+				require.Equal(t, "cn=admin,dc=example,dc=com", corp.BindDN)
+				require.Equal(t, map[string]string{"base_dn": "ou=users,dc=example,dc=com"}, corp.UserSearch)
+				require.Equal(t, map[string]string{"base_dn": "ou=groups,dc=example,dc=com"}, corp.GroupSearch)
+				require.Equal(t, []string{"/usr/share/elasticsearch/config/ldap/corp/ca.crt"}, corp.SSL.CertificateAuthorities)
+				ad := esCfg.XPack.Security.Authc.Realms.ActiveDirectory["ad"]
+				require.Equal(t, 3, ad.Order)
+				require.Equal(t, "example.com", ad.DomainName)
+				require.Empty(t, ad.URL)
+				require.Empty(t, ad.SSL.CertificateAuthorities)
+			},
+		},
+		{
+			name:     "audit logging should be disabled by default",
+			version:  "8.5.0",
+			ipFamily: corev1.IPv4Protocol,
+			assert: func(cfg CanonicalConfig) {
+				require.Empty(t, cfg.HasKeys([]string{esv1.XPackSecurityAuditEnabled}))
+			},
+		},
+		{
+			name:     "audit logging should be enabled with its event filters",
+			version:  "8.5.0",
+			ipFamily: corev1.IPv4Protocol,
+			auditLogging: &esv1.AuditLogging{
+				IncludeEvents:   []string{"access_denied", "authentication_failed"},
+				ExcludeEvents:   []string{"access_granted"},
+				EmitRequestBody: true,
+				IgnoreFilters: []esv1.AuditIgnoreFilter{
+					{Name: "kibana", Users: []string{"kibana_system"}},
+					{Name: "monitoring", Realms: []string{"file1"}, Indices: []string{".monitoring-*"}},
+				},
+			},
+			assert: func(cfg CanonicalConfig) {
+				cfgBytes, err := cfg.Render()
+				require.NoError(t, err)
+				type ignoreFilterCfg struct {
+					Users   []string `yaml:"users"`
+					Realms  []string `yaml:"realms"`
+					Roles   []string `yaml:"roles"`
+					Indices []string `yaml:"indices"`
+				}
+				esCfg := &struct {
+					XPack struct {
+						Security struct {
+							Audit struct {
+								Enabled bool     `yaml:"enabled"`
+								Outputs []string `yaml:"outputs"`
+								Logfile struct {
+									Events struct {
+										Include         []string                   `yaml:"include"`
+										Exclude         []string                   `yaml:"exclude"`
+										EmitRequestBody bool                       `yaml:"emit_request_body"`
+										IgnoreFilters   map[string]ignoreFilterCfg `yaml:"ignore_filters"`
+									} `yaml:"events"`
+								} `yaml:"logfile"`
+							} `yaml:"audit"`
+						} `yaml:"security"`
+					} `yaml:"xpack"`
+				}{}
+				require.NoError(t, yaml.Unmarshal(cfgBytes, &esCfg))
+				audit := esCfg.XPack.Security.Audit
+				require.True(t, audit.Enabled)
+				require.Empty(t, audit.Outputs)
+				require.Equal(t, []string{"access_denied", "authentication_failed"}, audit.Logfile.Events.Include)
+				require.Equal(t, []string{"access_granted"}, audit.Logfile.Events.Exclude)
+				require.True(t, audit.Logfile.Events.EmitRequestBody)
+				require.Equal(t, map[string]ignoreFilterCfg{
+					"kibana":     {Users: []string{"kibana_system"}},
+					"monitoring": {Realms: []string{"file1"}, Indices: []string{".monitoring-*"}},
+				}, audit.Logfile.Events.IgnoreFilters)
+			},
+		},
+		{
+			name:         "in 6.x, audit logging should be written to the logfile output",
+			version:      "6.8.0",
+			ipFamily:     corev1.IPv4Protocol,
+			auditLogging: &esv1.AuditLogging{},
+			assert: func(cfg CanonicalConfig) {
+				require.Len(t, cfg.HasKeys([]string{esv1.XPackSecurityAuditEnabled, esv1.XPackSecurityAuditOutputs}), 2)
+				require.Empty(t, cfg.HasKeys([]string{esv1.XPackSecurityAuditLogfileEventsInclude}))
+			},
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -219,10 +615,57 @@ func TestNewMergedESConfig(t *testing.T) {
 				ver,
 				tt.ipFamily,
 				commonv1.HTTPConfig{},
-				commonv1.Config{Data: tt.cfgData},
+				esv1.NodeSet{Config: &commonv1.Config{Data: tt.cfgData}, DataTier: tt.dataTier, Attributes: tt.attributes},
+				tt.snapshotVols,
+				tt.auth,
+				tt.auditLogging,
+				tt.defaultConfig,
 			)
 			require.NoError(t, err)
 			tt.assert(cfg)
 		})
 	}
 }
+
+func TestNewMergedESConfigWithOverrides(t *testing.T) {
+	tests := []struct {
+		name          string
+		cfgData       map[string]interface{}
+		defaultConfig *commonv1.Config
+		wantOverrides []string
+	}{
+		{
+			name:          "no conflicting settings",
+			cfgData:       map[string]interface{}{"node.attr.zone": "a"},
+			wantOverrides: nil,
+		},
+		{
+			name:          "operator setting overridden by the user configuration",
+			cfgData:       map[string]interface{}{esv1.NetworkHost: "127.0.0.1"},
+			wantOverrides: []string{"network.host: operator base settings value overridden by user configuration"},
+		},
+		{
+			name:          "operator default configuration overridden by the user configuration",
+			cfgData:       map[string]interface{}{"indices.memory.index_buffer_size": "20%"},
+			defaultConfig: &commonv1.Config{Data: map[string]interface{}{"indices.memory.index_buffer_size": "10%"}},
+			wantOverrides: []string{"indices.memory.index_buffer_size: operator default configuration value overridden by user configuration"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, overrides, err := NewMergedESConfigWithOverrides(
+				"clusterName",
+				version.MustParse("8.5.0"),
+				corev1.IPv4Protocol,
+				commonv1.HTTPConfig{},
+				esv1.NodeSet{Config: &commonv1.Config{Data: tt.cfgData}},
+				nil,
+				esv1.Auth{},
+				nil,
+				tt.defaultConfig,
+			)
+			require.NoError(t, err)
+			require.Equal(t, tt.wantOverrides, overrides.Strings())
+		})
+	}
+}
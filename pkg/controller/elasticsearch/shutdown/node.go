@@ -88,11 +88,24 @@ func (ns *NodeShutdown) ReconcileShutdowns(ctx context.Context, leavingNodes []s
 		return ns.Clear(ctx)
 	}
 
+	leavingNodeIDs := make(map[string]string, len(leavingNodes))
 	for _, node := range leavingNodes {
 		nodeID, err := ns.lookupNodeID(node)
 		if err != nil {
 			return err
 		}
+		leavingNodeIDs[node] = nodeID
+	}
+	// cancel the removal of the nodes which are not leaving anymore, for example because the cluster autoscaler gave up
+	// removing their Kubernetes node, restart shutdowns are cleared once complete by the rolling upgrade
+	if ns.typ == esclient.Remove {
+		if err := ns.Clear(ctx, ns.OnlyNodesInCluster, notLeaving(leavingNodeIDs)); err != nil {
+			return err
+		}
+	}
+
+	for _, node := range leavingNodes {
+		nodeID := leavingNodeIDs[node]
 		if shutdown, exists := ns.shutdowns[nodeID]; exists && shutdown.Is(ns.typ) {
 			continue
 		}
@@ -176,6 +189,18 @@ func (ns *NodeShutdown) Clear(ctx context.Context, conditions ...ClearCondition)
 	return nil
 }
 
+// notLeaving returns a predicate to limit the shutdowns to delete to nodes that are not in the given leaving nodes.
+func notLeaving(leavingNodeIDs map[string]string) ClearCondition {
+	return func(s esclient.NodeShutdown) bool {
+		for _, nodeID := range leavingNodeIDs {
+			if nodeID == s.NodeID {
+				return false
+			}
+		}
+		return true
+	}
+}
+
 func allApply(conditions []ClearCondition, s esclient.NodeShutdown) bool {
 	for _, c := range conditions {
 		if !c(s) {
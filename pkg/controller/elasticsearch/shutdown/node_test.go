@@ -414,6 +414,23 @@ func TestNodeShutdown_ReconcileShutdowns(t *testing.T) {
 			wantErr:     false,
 			wantMethods: []string{"GET"},
 		},
+		{
+			name: "cancel the shutdown of a node not leaving anymore",
+			args: args{
+				typ: esclient.Remove,
+				podToNodeID: map[string]string{
+					"pod-1": "txXw-Kd2Q6K0PbYMAPzH-Q",
+					"pod-2": "sh013PAoQFqkF92fBv1fzg",
+				},
+				leavingNodes: []string{"pod-1"},
+			},
+			fixtures: []string{
+				shutdownFixture,
+				ackFixture,
+			},
+			wantErr:     false,
+			wantMethods: []string{"GET", "DELETE"},
+		},
 		{
 			name: "unknown node",
 			args: args{
@@ -0,0 +1,172 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package snapshot
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// cronField describes a field of a cron expression.
+type cronField struct {
+	name  string
+	min   int
+	max   int
+	names map[string]int
+	// anyAllowed is true if the field accepts the ? wildcard.
+	anyAllowed bool
+}
+
+// cronFields are the fields of the cron expressions of Elasticsearch, in order. Days of week start with 1 for Sunday.
+var cronFields = []cronField{
+	{name: "seconds", min: 0, max: 59},
+	{name: "minutes", min: 0, max: 59},
+	{name: "hours", min: 0, max: 23},
+	{name: "day of month", min: 1, max: 31, anyAllowed: true},
+	{name: "month", min: 1, max: 12, names: map[string]int{
+		"JAN": 1, "FEB": 2, "MAR": 3, "APR": 4, "MAY": 5, "JUN": 6, "JUL": 7, "AUG": 8, "SEP": 9, "OCT": 10, "NOV": 11, "DEC": 12,
+	}},
+	{name: "day of week", min: 1, max: 7, anyAllowed: true, names: map[string]int{
+		"SUN": 1, "MON": 2, "TUE": 3, "WED": 4, "THU": 5, "FRI": 6, "SAT": 7,
+	}},
+}
+
+// CronSchedule is a parsed cron expression. Each field is a bit set of the values it matches.
+type CronSchedule struct {
+	second     uint64
+	minute     uint64
+	hour       uint64
+	dayOfMonth uint64
+	month      uint64
+	dayOfWeek  uint64
+}
+
+// ParseCron parses a cron expression in the format of Elasticsearch: seconds, minutes, hours, day of month, month and
+// day of week. Fields can be a wildcard, a value, a range, a value or range with an increment, or a list of them.
+// The special characters L, W and # and the optional year field are not supported.
+func ParseCron(expr string) (CronSchedule, error) {
+	fields := strings.Fields(expr)
+	if len(fields) != len(cronFields) {
+		return CronSchedule{}, fmt.Errorf("cron expression %q must have %d fields", expr, len(cronFields))
+	}
+	bits := make([]uint64, len(fields))
+	for i, value := range fields {
+		b, err := cronFields[i].parse(value)
+		if err != nil {
+			return CronSchedule{}, fmt.Errorf("invalid cron expression %q: %w", expr, err)
+		}
+		bits[i] = b
+	}
+	return CronSchedule{
+		second:     bits[0],
+		minute:     bits[1],
+		hour:       bits[2],
+		dayOfMonth: bits[3],
+		month:      bits[4],
+		dayOfWeek:  bits[5],
+	}, nil
+}
+
+// parse returns the bit set of the values matched by the given field value.
+func (f cronField) parse(value string) (uint64, error) {
+	var bits uint64
+	for _, part := range strings.Split(value, ",") {
+		if part == "?" {
+			if !f.anyAllowed {
+				return 0, fmt.Errorf("? is not allowed in the %s field", f.name)
+			}
+			bits |= rangeBits(f.min, f.max, 1)
+			continue
+		}
+		expr, step := part, 1
+		if i := strings.Index(part, "/"); i >= 0 {
+			s, err := strconv.Atoi(part[i+1:])
+			if err != nil || s <= 0 {
+				return 0, fmt.Errorf("invalid increment in %s field: %s", f.name, part)
+			}
+			expr, step = part[:i], s
+		}
+		start, end := f.min, f.max
+		switch {
+		case expr == "*":
+		case strings.Contains(expr, "-"):
+			bounds := strings.SplitN(expr, "-", 2)
+			var err error
+			if start, err = f.value(bounds[0]); err != nil {
+				return 0, err
+			}
+			if end, err = f.value(bounds[1]); err != nil {
+				return 0, err
+			}
+			if start > end {
+				return 0, fmt.Errorf("invalid range in %s field: %s", f.name, expr)
+			}
+		default:
+			var err error
+			if start, err = f.value(expr); err != nil {
+				return 0, err
+			}
+			// a single value with an increment, such as 5/15, starts at the value and spans the end of the range
+			if step == 1 {
+				end = start
+			}
+		}
+		bits |= rangeBits(start, end, step)
+	}
+	return bits, nil
+}
+
+// value parses a single value of the field, either a number or a name.
+func (f cronField) value(s string) (int, error) {
+	v, isName := f.names[strings.ToUpper(s)]
+	if !isName {
+		var err error
+		if v, err = strconv.Atoi(s); err != nil {
+			return 0, fmt.Errorf("invalid value in %s field: %s", f.name, s)
+		}
+	}
+	if v < f.min || v > f.max {
+		return 0, fmt.Errorf("value %d of %s field is out of range [%d, %d]", v, f.name, f.min, f.max)
+	}
+	return v, nil
+}
+
+func rangeBits(start, end, step int) uint64 {
+	var bits uint64
+	for i := start; i <= end; i += step {
+		bits |= 1 << uint(i)
+	}
+	return bits
+}
+
+func has(bits uint64, value int) bool {
+	return bits&(1<<uint(value)) != 0
+}
+
+// Next returns the first time matching the schedule strictly after t, in UTC. It returns the zero time if no time
+// matches the schedule in the next five years, such as for the 31st of February.
+func (s CronSchedule) Next(t time.Time) time.Time {
+	t = t.UTC().Truncate(time.Second).Add(time.Second)
+	limit := t.AddDate(5, 0, 0)
+	for t.Before(limit) {
+		switch {
+		case !has(s.month, int(t.Month())):
+			t = time.Date(t.Year(), t.Month()+1, 1, 0, 0, 0, 0, time.UTC)
+		case !has(s.dayOfMonth, t.Day()) || !has(s.dayOfWeek, int(t.Weekday())+1):
+			t = time.Date(t.Year(), t.Month(), t.Day()+1, 0, 0, 0, 0, time.UTC)
+		case !has(s.hour, t.Hour()):
+			t = t.Truncate(time.Hour).Add(time.Hour)
+		case !has(s.minute, t.Minute()):
+			t = t.Truncate(time.Minute).Add(time.Minute)
+		case !has(s.second, t.Second()):
+			t = t.Add(time.Second)
+		default:
+			return t
+		}
+	}
+	return time.Time{}
+}
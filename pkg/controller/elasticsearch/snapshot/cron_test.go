@@ -0,0 +1,107 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package snapshot
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func mustParseTime(t *testing.T, value string) time.Time {
+	t.Helper()
+	parsed, err := time.Parse(time.RFC3339, value)
+	require.NoError(t, err)
+	return parsed
+}
+
+func TestCronSchedule_Next(t *testing.T) {
+	tests := []struct {
+		name string
+		cron string
+		from string
+		want string
+	}{
+		{
+			name: "every day",
+			cron: "0 30 1 * * ?",
+			from: "2022-10-17T10:00:00Z",
+			want: "2022-10-18T01:30:00Z",
+		},
+		{
+			name: "scheduled time is excluded",
+			cron: "0 30 1 * * ?",
+			from: "2022-10-18T01:30:00Z",
+			want: "2022-10-19T01:30:00Z",
+		},
+		{
+			name: "increment",
+			cron: "0 0/15 * * * ?",
+			from: "2022-10-17T10:07:30Z",
+			want: "2022-10-17T10:15:00Z",
+		},
+		{
+			name: "increment of seconds",
+			cron: "*/10 * * * * ?",
+			from: "2022-10-17T10:00:05Z",
+			want: "2022-10-17T10:00:10Z",
+		},
+		{
+			name: "range of days of week",
+			cron: "0 0 12 ? * MON-FRI",
+			from: "2022-10-15T13:00:00Z",
+			want: "2022-10-17T12:00:00Z",
+		},
+		{
+			name: "list of months",
+			cron: "0 0 0 1 JAN,JUL ?",
+			from: "2022-10-17T10:00:00Z",
+			want: "2023-01-01T00:00:00Z",
+		},
+		{
+			name: "leap day",
+			cron: "0 0 0 29 2 ?",
+			from: "2022-03-01T00:00:00Z",
+			want: "2024-02-29T00:00:00Z",
+		},
+		{
+			name: "time zones are converted to UTC",
+			cron: "0 30 1 * * ?",
+			from: "2022-10-17T01:00:00+02:00",
+			want: "2022-10-17T01:30:00Z",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			schedule, err := ParseCron(tt.cron)
+			require.NoError(t, err)
+			require.Equal(t, mustParseTime(t, tt.want), schedule.Next(mustParseTime(t, tt.from)))
+		})
+	}
+
+	// no matching time
+	schedule, err := ParseCron("0 0 0 31 2 ?")
+	require.NoError(t, err)
+	require.True(t, schedule.Next(mustParseTime(t, "2022-10-17T10:00:00Z")).IsZero())
+}
+
+func TestParseCron_Invalid(t *testing.T) {
+	for _, cron := range []string{
+		"",
+		"0 30 1 * *",
+		"0 30 1 * * ? 2022",
+		"60 * * * * ?",
+		"0 ? * * * ?",
+		"0 0 12 ? * MON#2",
+		"0 0 0 L * ?",
+		"0 0 5-1 * * ?",
+		"0 */0 * * * ?",
+		"0 0 0 * FOO ?",
+	} {
+		_, err := ParseCron(cron)
+		require.Error(t, err, cron)
+	}
+}
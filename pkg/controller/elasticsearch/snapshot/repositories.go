@@ -0,0 +1,162 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package snapshot
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"go.elastic.co/apm/v2"
+
+	esv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/elasticsearch/v1"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/tracing"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/version"
+	esclient "github.com/elastic/cloud-on-k8s/v2/pkg/controller/elasticsearch/client"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/utils/k8s"
+	ulog "github.com/elastic/cloud-on-k8s/v2/pkg/utils/log"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/utils/set"
+)
+
+// ManagedRepositoriesAnnotation holds the names of the snapshot repositories registered by the operator, so that the
+// repositories removed from the spec can be unregistered without removing the ones registered by the user.
+const ManagedRepositoriesAnnotation = "elasticsearch.k8s.elastic.co/managed-snapshot-repositories"
+
+// ReconcileRepositories registers the snapshot repositories of the Elasticsearch spec through the Elasticsearch API,
+// and updates the registered repositories whose type or settings drifted from the spec. Repositories removed from the
+// spec are unregistered along with the SLM policy of their schedule, their snapshots are kept in the repository.
+// Credentials of the repositories are not handled here: they are part of the secure settings of the cluster and are
+// added to the Elasticsearch keystore.
+func ReconcileRepositories(
+	ctx context.Context,
+	k8sClient k8s.Client,
+	esClient esclient.SnapshotClient,
+	es *esv1.Elasticsearch,
+	v version.Version,
+) error {
+	managed := ManagedRepositories(*es)
+	if len(es.Spec.SnapshotRepositories) == 0 && len(managed) == 0 {
+		return nil
+	}
+
+	span, ctx := apm.StartSpan(ctx, "reconcile_snapshot_repositories", tracing.SpanTypeApp)
+	defer span.End()
+
+	// track the declared repositories before registering them
+	declared := set.Make()
+	for _, repository := range es.Spec.SnapshotRepositories {
+		declared.Add(repository.Name)
+	}
+	tracked := set.Make()
+	tracked.MergeWith(managed)
+	tracked.MergeWith(declared)
+	if err := annotateWithManagedRepositories(ctx, k8sClient, es, tracked); err != nil {
+		return err
+	}
+
+	actual, err := esClient.GetSnapshotRepositories(ctx)
+	if err != nil {
+		return err
+	}
+	for _, repository := range es.Spec.SnapshotRepositories {
+		expected := expectedRepository(repository)
+		if current, exists := actual[repository.Name]; exists && current.Equal(expected) {
+			continue
+		}
+		ulog.FromContext(ctx).Info("Updating snapshot repository",
+			"namespace", es.Namespace, "es_name", es.Name, "repository", repository.Name, "type", repository.Type)
+		if err := esClient.UpdateSnapshotRepository(ctx, repository.Name, expected); err != nil {
+			return fmt.Errorf("while updating snapshot repository %s: %w", repository.Name, err)
+		}
+	}
+
+	removed := managed.Diff(declared)
+	if removed.Count() == 0 {
+		return nil
+	}
+	if err := deleteRepositories(ctx, esClient, *es, v, actual, removed.AsSortedSlice()); err != nil {
+		return err
+	}
+	return annotateWithManagedRepositories(ctx, k8sClient, es, declared)
+}
+
+// deleteRepositories deletes the SLM policies of the given repositories, if any, then unregisters the repositories.
+func deleteRepositories(
+	ctx context.Context,
+	esClient esclient.SnapshotClient,
+	es esv1.Elasticsearch,
+	v version.Version,
+	actual esclient.SnapshotRepositories,
+	removed []string,
+) error {
+	var policies esclient.SLMPolicies
+	if IsSLMSupported(v) {
+		var err error
+		if policies, err = esClient.GetSLMPolicies(ctx); err != nil {
+			return err
+		}
+	}
+	for _, name := range removed {
+		if policy, exists := policies[SLMPolicyName(name)]; exists && policy.Policy.Repository == name {
+			ulog.FromContext(ctx).Info("Deleting snapshot lifecycle management policy",
+				"namespace", es.Namespace, "es_name", es.Name, "repository", name, "policy", SLMPolicyName(name))
+			if err := esClient.DeleteSLMPolicy(ctx, SLMPolicyName(name)); err != nil {
+				return fmt.Errorf("while deleting SLM policy %s: %w", SLMPolicyName(name), err)
+			}
+		}
+		if _, exists := actual[name]; !exists {
+			continue
+		}
+		ulog.FromContext(ctx).Info("Deleting snapshot repository",
+			"namespace", es.Namespace, "es_name", es.Name, "repository", name)
+		if err := esClient.DeleteSnapshotRepository(ctx, name); err != nil {
+			return fmt.Errorf("while deleting snapshot repository %s: %w", name, err)
+		}
+	}
+	return nil
+}
+
+// ManagedRepositories returns the names of the snapshot repositories registered by the operator.
+func ManagedRepositories(es esv1.Elasticsearch) set.StringSet {
+	managed := set.Make()
+	serialized := strings.TrimSpace(es.Annotations[ManagedRepositoriesAnnotation])
+	if serialized == "" {
+		return managed
+	}
+	for _, name := range strings.Split(serialized, ",") {
+		managed.Add(name)
+	}
+	return managed
+}
+
+// annotateWithManagedRepositories stores the names of the given repositories in the ManagedRepositoriesAnnotation, or
+// removes the annotation if there is none.
+func annotateWithManagedRepositories(ctx context.Context, k8sClient k8s.Client, es *esv1.Elasticsearch, repositories set.StringSet) error {
+	serialized := strings.Join(repositories.AsSortedSlice(), ",")
+	current, exists := es.Annotations[ManagedRepositoriesAnnotation]
+	switch {
+	case repositories.Count() == 0 && !exists:
+		return nil
+	case repositories.Count() == 0:
+		delete(es.Annotations, ManagedRepositoriesAnnotation)
+	case exists && current == serialized:
+		return nil
+	default:
+		if es.Annotations == nil {
+			es.Annotations = make(map[string]string)
+		}
+		es.Annotations[ManagedRepositoriesAnnotation] = serialized
+	}
+	return k8sClient.Update(ctx, es)
+}
+
+// expectedRepository returns the definition of the given snapshot repository expected in Elasticsearch.
+func expectedRepository(repository esv1.SnapshotRepository) esclient.SnapshotRepository {
+	expected := esclient.SnapshotRepository{Type: string(repository.Type), Settings: map[string]interface{}{}}
+	if repository.Settings != nil && repository.Settings.Data != nil {
+		expected.Settings = repository.Settings.Data
+	}
+	return expected
+}
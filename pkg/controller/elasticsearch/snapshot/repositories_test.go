@@ -0,0 +1,228 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package snapshot
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	commonv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/common/v1"
+	esv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/elasticsearch/v1"
+	controllerscheme "github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/scheme"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/version"
+	esclient "github.com/elastic/cloud-on-k8s/v2/pkg/controller/elasticsearch/client"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/utils/k8s"
+)
+
+type fakeESClient struct {
+	esclient.Client
+	repositories        esclient.SnapshotRepositories
+	updated             []string
+	deletedRepositories []string
+	verifyErrs          map[string]error
+	snapshots           []esclient.Snapshot
+	created             []string
+	deleted             []string
+	policies            esclient.SLMPolicies
+	updatedPolicies     []string
+	deletedPolicies     []string
+	restored            []string
+	restoreErr          error
+	restoringShards     int
+	documentCount       int64
+	health              esv1.ElasticsearchHealth
+}
+
+func (f *fakeESClient) GetSnapshotRepositories(_ context.Context) (esclient.SnapshotRepositories, error) {
+	return f.repositories, nil
+}
+
+func (f *fakeESClient) UpdateSnapshotRepository(_ context.Context, name string, repository esclient.SnapshotRepository) error {
+	f.updated = append(f.updated, name)
+	f.repositories[name] = repository
+	return nil
+}
+
+func (f *fakeESClient) VerifySnapshotRepository(_ context.Context, name string) error {
+	return f.verifyErrs[name]
+}
+
+func (f *fakeESClient) DeleteSnapshotRepository(_ context.Context, name string) error {
+	f.deletedRepositories = append(f.deletedRepositories, name)
+	delete(f.repositories, name)
+	return nil
+}
+
+func (f *fakeESClient) GetSnapshots(_ context.Context, _ string) ([]esclient.Snapshot, error) {
+	return f.snapshots, nil
+}
+
+func (f *fakeESClient) GetSnapshot(_ context.Context, _, name string) (*esclient.Snapshot, error) {
+	for i := range f.snapshots {
+		if f.snapshots[i].Snapshot == name {
+			return &f.snapshots[i], nil
+		}
+	}
+	return nil, nil
+}
+
+func (f *fakeESClient) CreateSnapshot(_ context.Context, _, name string, _ []string) error {
+	f.created = append(f.created, name)
+	return nil
+}
+
+func (f *fakeESClient) DeleteSnapshot(_ context.Context, _, name string) error {
+	f.deleted = append(f.deleted, name)
+	return nil
+}
+
+func (f *fakeESClient) GetSLMPolicies(_ context.Context) (esclient.SLMPolicies, error) {
+	return f.policies, nil
+}
+
+func (f *fakeESClient) UpdateSLMPolicy(_ context.Context, name string, _ esclient.SLMPolicy) error {
+	f.updatedPolicies = append(f.updatedPolicies, name)
+	return nil
+}
+
+func (f *fakeESClient) DeleteSLMPolicy(_ context.Context, name string) error {
+	f.deletedPolicies = append(f.deletedPolicies, name)
+	delete(f.policies, name)
+	return nil
+}
+
+func (f *fakeESClient) RestoreSnapshot(_ context.Context, _, name string, _ []string) error {
+	if f.restoreErr != nil {
+		return f.restoreErr
+	}
+	f.restored = append(f.restored, name)
+	return nil
+}
+
+func (f *fakeESClient) CountRestoringShards(_ context.Context, _, _ string) (int, error) {
+	return f.restoringShards, nil
+}
+
+func (f *fakeESClient) CountDocuments(_ context.Context, _ []string) (int64, error) {
+	return f.documentCount, nil
+}
+
+func (f *fakeESClient) GetClusterHealth(_ context.Context) (esclient.Health, error) {
+	return esclient.Health{Status: f.health}, nil
+}
+
+func newEsWithRepositories(repositories ...esv1.SnapshotRepository) esv1.Elasticsearch {
+	return esv1.Elasticsearch{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "es"},
+		Spec:       esv1.ElasticsearchSpec{SnapshotRepositories: repositories},
+	}
+}
+
+func TestReconcileRepositories(t *testing.T) {
+	controllerscheme.SetupScheme()
+	s3Repository := esv1.SnapshotRepository{
+		Name:     "s3-backups",
+		Type:     esv1.S3SnapshotRepository,
+		Settings: &commonv1.Config{Data: map[string]interface{}{"bucket": "backups", "compress": true}},
+	}
+	fsRepository := esv1.SnapshotRepository{
+		Name:     "nfs",
+		Type:     esv1.FSSnapshotRepository,
+		Settings: &commonv1.Config{Data: map[string]interface{}{"location": "/usr/share/elasticsearch/snapshots/nfs"}},
+	}
+
+	withManaged := func(es esv1.Elasticsearch, managed string) esv1.Elasticsearch {
+		es.Annotations = map[string]string{ManagedRepositoriesAnnotation: managed}
+		return es
+	}
+
+	tests := []struct {
+		name                    string
+		es                      esv1.Elasticsearch
+		repositories            esclient.SnapshotRepositories
+		policies                esclient.SLMPolicies
+		wantUpdated             []string
+		wantDeletedRepositories []string
+		wantDeletedPolicies     []string
+		wantAnnotation          string
+	}{
+		{
+			name:         "no repositories",
+			es:           newEsWithRepositories(),
+			repositories: esclient.SnapshotRepositories{},
+		},
+		{
+			name:           "register new repositories",
+			es:             newEsWithRepositories(s3Repository, fsRepository),
+			repositories:   esclient.SnapshotRepositories{},
+			wantUpdated:    []string{"s3-backups", "nfs"},
+			wantAnnotation: "nfs,s3-backups",
+		},
+		{
+			name: "repositories up to date",
+			es:   withManaged(newEsWithRepositories(s3Repository, fsRepository), "nfs,s3-backups"),
+			repositories: esclient.SnapshotRepositories{
+				"s3-backups": {Type: "s3", Settings: map[string]interface{}{"bucket": "backups", "compress": "true"}},
+				"nfs":        {Type: "fs", Settings: map[string]interface{}{"location": "/usr/share/elasticsearch/snapshots/nfs"}},
+				"manual":     {Type: "fs", Settings: map[string]interface{}{"location": "/mnt/other"}},
+			},
+			wantAnnotation: "nfs,s3-backups",
+		},
+		{
+			name: "repository settings drifted",
+			es:   withManaged(newEsWithRepositories(s3Repository, fsRepository), "nfs,s3-backups"),
+			repositories: esclient.SnapshotRepositories{
+				"s3-backups": {Type: "s3", Settings: map[string]interface{}{"bucket": "backups", "compress": "false"}},
+				"nfs":        {Type: "fs", Settings: map[string]interface{}{"location": "/usr/share/elasticsearch/snapshots/nfs"}},
+			},
+			wantUpdated:    []string{"s3-backups"},
+			wantAnnotation: "nfs,s3-backups",
+		},
+		{
+			name: "repository removed from the spec",
+			es:   withManaged(newEsWithRepositories(fsRepository), "nfs,s3-backups"),
+			repositories: esclient.SnapshotRepositories{
+				"s3-backups": {Type: "s3", Settings: map[string]interface{}{"bucket": "backups", "compress": "true"}},
+				"nfs":        {Type: "fs", Settings: map[string]interface{}{"location": "/usr/share/elasticsearch/snapshots/nfs"}},
+				"manual":     {Type: "fs", Settings: map[string]interface{}{"location": "/mnt/other"}},
+			},
+			policies: esclient.SLMPolicies{
+				"eck-s3-backups": {Policy: esclient.SLMPolicy{Repository: "s3-backups"}},
+				"nightly":        {Policy: esclient.SLMPolicy{Repository: "manual"}},
+			},
+			wantDeletedRepositories: []string{"s3-backups"},
+			wantDeletedPolicies:     []string{"eck-s3-backups"},
+			wantAnnotation:          "nfs",
+		},
+		{
+			name:                    "all repositories removed from the spec",
+			es:                      withManaged(newEsWithRepositories(), "nfs,s3-backups"),
+			repositories:            esclient.SnapshotRepositories{"nfs": {Type: "fs"}},
+			policies:                esclient.SLMPolicies{},
+			wantDeletedRepositories: []string{"nfs"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			es := tt.es
+			k8sClient := k8s.NewFakeClient(&es)
+			esClient := &fakeESClient{repositories: tt.repositories, policies: tt.policies}
+			require.NoError(t, ReconcileRepositories(context.Background(), k8sClient, esClient, &es, version.MustParse("8.5.0")))
+			require.Equal(t, tt.wantUpdated, esClient.updated)
+			require.Equal(t, tt.wantDeletedRepositories, esClient.deletedRepositories)
+			require.Equal(t, tt.wantDeletedPolicies, esClient.deletedPolicies)
+			for _, repository := range tt.es.Spec.SnapshotRepositories {
+				require.True(t, esClient.repositories[repository.Name].Equal(expectedRepository(repository)))
+			}
+
+			var updated esv1.Elasticsearch
+			require.NoError(t, k8sClient.Get(context.Background(), k8s.ExtractNamespacedName(&es), &updated))
+			require.Equal(t, tt.wantAnnotation, updated.Annotations[ManagedRepositoriesAnnotation])
+		})
+	}
+}
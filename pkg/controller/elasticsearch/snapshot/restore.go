@@ -0,0 +1,89 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package snapshot
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+
+	esv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/elasticsearch/v1"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/events"
+	esclient "github.com/elastic/cloud-on-k8s/v2/pkg/controller/elasticsearch/client"
+	ulog "github.com/elastic/cloud-on-k8s/v2/pkg/utils/log"
+)
+
+// ReconcileInitialRestore restores the snapshot declared in the initial restore of the spec, unless it was already
+// restored according to the status, and returns the updated status of the restore. The status of a previous restore of
+// another snapshot is discarded.
+// The restore is complete once no shard is being restored from the snapshot anymore and the cluster health is not red.
+// The number of documents in the restored indices is then reported in the status.
+// A restore rejected by Elasticsearch is reported as failed and retried on the next call.
+func ReconcileInitialRestore(
+	ctx context.Context,
+	esClient esclient.Client,
+	recorder *events.Recorder,
+	es esv1.Elasticsearch,
+	now time.Time,
+) (esv1.InitialRestoreStatus, error) {
+	restore := es.Spec.InitialRestore
+	status := esv1.InitialRestoreStatus{Repository: restore.Repository, Snapshot: restore.Snapshot}
+	if current := es.Status.InitialRestore; current != nil &&
+		current.Repository == restore.Repository && current.Snapshot == restore.Snapshot {
+		status = *current.DeepCopy()
+	}
+	log := ulog.FromContext(ctx).WithValues("namespace", es.Namespace, "es_name", es.Name,
+		"repository", status.Repository, "snapshot", status.Snapshot)
+
+	switch status.Phase {
+	case esv1.InitialRestoreCompleted:
+		return status, nil
+	case esv1.InitialRestoreInProgress:
+		restoring, err := esClient.CountRestoringShards(ctx, status.Repository, status.Snapshot)
+		if err != nil {
+			return status, err
+		}
+		health, err := esClient.GetClusterHealth(ctx)
+		if err != nil {
+			return status, err
+		}
+		if restoring > 0 || health.Status == esv1.ElasticsearchRedHealth {
+			status.Message = fmt.Sprintf("%d shards being restored, cluster health is %s", restoring, health.Status)
+			return status, nil
+		}
+		documentCount, err := esClient.CountDocuments(ctx, restore.Indices)
+		if err != nil {
+			return status, err
+		}
+		log.Info("Snapshot restored", "document_count", documentCount)
+		status.Phase = esv1.InitialRestoreCompleted
+		status.Message = ""
+		status.CompletionTime = statusTime(now)
+		status.DocumentCount = &documentCount
+		recorder.AddEvent(corev1.EventTypeNormal, events.EventReasonRestore,
+			fmt.Sprintf("Snapshot %s restored from repository %s", status.Snapshot, status.Repository))
+		return status, nil
+	default:
+		log.Info("Restoring snapshot")
+		if err := esClient.RestoreSnapshot(ctx, restore.Repository, restore.Snapshot, restore.Indices); err != nil {
+			if !esclient.Is4xx(err) {
+				return status, err
+			}
+			message := fmt.Sprintf("Failed to restore snapshot %s from repository %s: %s", restore.Snapshot, restore.Repository, err.Error())
+			if status.Phase != esv1.InitialRestoreFailed || status.Message != message {
+				recorder.AddEvent(corev1.EventTypeWarning, events.EventReasonRestore, message)
+			}
+			status.Phase = esv1.InitialRestoreFailed
+			status.Message = message
+			return status, nil
+		}
+		status.Phase = esv1.InitialRestoreInProgress
+		status.Message = ""
+		status.StartTime = statusTime(now)
+		return status, nil
+	}
+}
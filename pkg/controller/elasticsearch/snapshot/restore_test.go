@@ -0,0 +1,111 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package snapshot
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/pointer"
+
+	esv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/elasticsearch/v1"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/events"
+	esclient "github.com/elastic/cloud-on-k8s/v2/pkg/controller/elasticsearch/client"
+)
+
+func TestReconcileInitialRestore(t *testing.T) {
+	now := mustParseTime(t, "2022-10-17T10:00:00Z")
+	started := statusTime(mustParseTime(t, "2022-10-17T09:00:00Z"))
+	inProgress := &esv1.InitialRestoreStatus{
+		Repository: "s3-backups", Snapshot: "snap-1", Phase: esv1.InitialRestoreInProgress, StartTime: started,
+	}
+	rejected := &esclient.APIError{StatusCode: 404, Status: "404 Not Found"}
+
+	tests := []struct {
+		name         string
+		status       *esv1.InitialRestoreStatus
+		esClient     *fakeESClient
+		wantRestored []string
+		wantStatus   esv1.InitialRestoreStatus
+		wantEvents   int
+		wantErr      bool
+	}{
+		{
+			name:         "start the restore",
+			esClient:     &fakeESClient{},
+			wantRestored: []string{"snap-1"},
+			wantStatus: esv1.InitialRestoreStatus{
+				Repository: "s3-backups", Snapshot: "snap-1", Phase: esv1.InitialRestoreInProgress, StartTime: statusTime(now),
+			},
+		},
+		{
+			name:     "restore rejected",
+			esClient: &fakeESClient{restoreErr: rejected},
+			wantStatus: esv1.InitialRestoreStatus{
+				Repository: "s3-backups", Snapshot: "snap-1", Phase: esv1.InitialRestoreFailed,
+				Message: "Failed to restore snapshot snap-1 from repository s3-backups: " + rejected.Error(),
+			},
+			wantEvents: 1,
+		},
+		{
+			name:     "restore request error",
+			esClient: &fakeESClient{restoreErr: errors.New("connection refused")},
+			wantStatus: esv1.InitialRestoreStatus{
+				Repository: "s3-backups", Snapshot: "snap-1",
+			},
+			wantErr: true,
+		},
+		{
+			name:     "shards being restored",
+			status:   inProgress,
+			esClient: &fakeESClient{restoringShards: 3, health: esv1.ElasticsearchRedHealth},
+			wantStatus: esv1.InitialRestoreStatus{
+				Repository: "s3-backups", Snapshot: "snap-1", Phase: esv1.InitialRestoreInProgress, StartTime: started,
+				Message: "3 shards being restored, cluster health is red",
+			},
+		},
+		{
+			name:     "restore completed",
+			status:   inProgress,
+			esClient: &fakeESClient{health: esv1.ElasticsearchGreenHealth, documentCount: 1234},
+			wantStatus: esv1.InitialRestoreStatus{
+				Repository: "s3-backups", Snapshot: "snap-1", Phase: esv1.InitialRestoreCompleted,
+				StartTime: started, CompletionTime: statusTime(now), DocumentCount: pointer.Int64(1234),
+			},
+			wantEvents: 1,
+		},
+		{
+			name: "restore of another snapshot completed",
+			status: &esv1.InitialRestoreStatus{
+				Repository: "s3-backups", Snapshot: "snap-0", Phase: esv1.InitialRestoreCompleted,
+			},
+			esClient:     &fakeESClient{},
+			wantRestored: []string{"snap-1"},
+			wantStatus: esv1.InitialRestoreStatus{
+				Repository: "s3-backups", Snapshot: "snap-1", Phase: esv1.InitialRestoreInProgress, StartTime: statusTime(now),
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			es := esv1.Elasticsearch{
+				ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "es"},
+				Spec: esv1.ElasticsearchSpec{
+					InitialRestore: &esv1.InitialRestore{Repository: "s3-backups", Snapshot: "snap-1"},
+				},
+				Status: esv1.ElasticsearchStatus{InitialRestore: tt.status},
+			}
+			recorder := events.NewRecorder()
+			status, err := ReconcileInitialRestore(context.Background(), tt.esClient, recorder, es, now)
+			require.Equal(t, tt.wantErr, err != nil)
+			require.Equal(t, tt.wantRestored, tt.esClient.restored)
+			require.Equal(t, tt.wantStatus, status)
+			require.Len(t, recorder.Events(), tt.wantEvents)
+		})
+	}
+}
@@ -0,0 +1,339 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package snapshot
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+
+	commonv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/common/v1"
+	esv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/elasticsearch/v1"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/events"
+	esclient "github.com/elastic/cloud-on-k8s/v2/pkg/controller/elasticsearch/client"
+	esvolume "github.com/elastic/cloud-on-k8s/v2/pkg/controller/elasticsearch/volume"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/utils/k8s"
+	ulog "github.com/elastic/cloud-on-k8s/v2/pkg/utils/log"
+)
+
+const (
+	// RestoreVerificationOfLabelName is the label set on the verification clusters, holding the name of the cluster
+	// whose snapshots they verify.
+	RestoreVerificationOfLabelName = "elasticsearch.k8s.elastic.co/restore-verification-of"
+	// RestoreVerificationTimeout is the maximum duration of the verification of a snapshot, including the creation of
+	// the verification cluster.
+	RestoreVerificationTimeout = 6 * time.Hour
+
+	// restoreVerificationCheckInterval is the interval at which the progress of an ongoing verification is checked.
+	restoreVerificationCheckInterval = time.Minute
+	// VerificationClusterSuffix is the suffix of the name of the verification clusters, reserved to them.
+	VerificationClusterSuffix = "-eck-verify"
+	// verificationNodeSetName is the name of the single node set of the verification clusters.
+	verificationNodeSetName = "default"
+)
+
+// VerificationClusterName returns the name of the cluster in which the snapshots of the given cluster are verified.
+func VerificationClusterName(esName string) string {
+	return esName + VerificationClusterSuffix
+}
+
+// HasReservedName returns true if the given cluster is named like a verification cluster without being the
+// verification cluster created by the operator for the cluster it is named after.
+func HasReservedName(es esv1.Elasticsearch) bool {
+	if !strings.HasSuffix(es.Name, VerificationClusterSuffix) {
+		return false
+	}
+	return es.Labels[RestoreVerificationOfLabelName] != strings.TrimSuffix(es.Name, VerificationClusterSuffix)
+}
+
+// ReconcileRestoreVerification periodically restores the latest successful snapshot of the repository declared in the
+// restore verification of the spec in a temporary single-node cluster, checks the number of restored documents, and
+// deletes the temporary cluster. It returns the updated status of the verification along with the delay after which
+// it should be reconciled again. The verification cluster is deleted if the restore verification is removed from the
+// spec.
+func ReconcileRestoreVerification(
+	ctx context.Context,
+	k8sClient k8s.Client,
+	esClient esclient.SnapshotClient,
+	recorder *events.Recorder,
+	es esv1.Elasticsearch,
+	now time.Time,
+) (*esv1.RestoreVerificationStatus, time.Duration, error) {
+	spec := es.Spec.RestoreVerification
+	if spec == nil {
+		if es.Status.RestoreVerification == nil {
+			return nil, 0, nil
+		}
+		return nil, 0, deleteVerificationCluster(ctx, k8sClient, es)
+	}
+
+	schedule, err := ParseCron(spec.Cron)
+	if err != nil {
+		return es.Status.RestoreVerification, 0, fmt.Errorf("while parsing the schedule of the restore verification: %w", err)
+	}
+
+	var status esv1.RestoreVerificationStatus
+	if current := es.Status.RestoreVerification; current != nil {
+		status = *current.DeepCopy()
+	}
+	if status.Phase == esv1.RestoreVerificationInProgress {
+		completed, err := checkVerification(ctx, k8sClient, recorder, es, &status, now)
+		if err != nil || !completed {
+			return &status, restoreVerificationCheckInterval, err
+		}
+		return &status, durationUntil(schedule.Next(now), now), nil
+	}
+
+	// delete the cluster of the previous verification if it could not be deleted on completion
+	if err := deleteVerificationCluster(ctx, k8sClient, es); err != nil {
+		return es.Status.RestoreVerification, 0, err
+	}
+
+	// the first verification is due at the first scheduled time after the creation of the cluster
+	last := es.CreationTimestamp.Time
+	if status.StartTime != nil {
+		last = status.StartTime.Time
+	}
+	if next := schedule.Next(last); next.IsZero() || next.After(now) {
+		return es.Status.RestoreVerification, durationUntil(next, now), nil
+	}
+
+	status, err = startVerification(ctx, k8sClient, esClient, recorder, es, now)
+	if err != nil {
+		return es.Status.RestoreVerification, 0, err
+	}
+	if status.Phase == esv1.RestoreVerificationInProgress {
+		return &status, restoreVerificationCheckInterval, nil
+	}
+	return &status, durationUntil(schedule.Next(now), now), nil
+}
+
+// startVerification creates a verification cluster restoring the latest successful snapshot of the repository. The
+// verification fails immediately if the repository does not contain any successful snapshot.
+func startVerification(
+	ctx context.Context,
+	k8sClient k8s.Client,
+	esClient esclient.SnapshotClient,
+	recorder *events.Recorder,
+	es esv1.Elasticsearch,
+	now time.Time,
+) (esv1.RestoreVerificationStatus, error) {
+	spec := es.Spec.RestoreVerification
+	status := esv1.RestoreVerificationStatus{StartTime: statusTime(now)}
+
+	repository, declared := declaredRepository(es, spec.Repository)
+	if !declared {
+		return status, fmt.Errorf("restore verification repository %s is not declared in snapshotRepositories", spec.Repository)
+	}
+	snapshots, err := esClient.GetSnapshots(ctx, spec.Repository)
+	if err != nil {
+		return status, err
+	}
+	latest := latestSuccessfulSnapshot(snapshots)
+	if latest == nil {
+		completeVerification(recorder, &status, esv1.RestoreVerificationFailed,
+			fmt.Sprintf("No successful snapshot to verify in repository %s", spec.Repository), now)
+		return status, nil
+	}
+
+	verification, err := newVerificationCluster(es, repository, latest.Snapshot)
+	if err != nil {
+		return status, err
+	}
+	ulog.FromContext(ctx).Info("Creating restore verification cluster", "namespace", es.Namespace, "es_name", es.Name,
+		"repository", spec.Repository, "snapshot", latest.Snapshot)
+	if err := k8sClient.Create(ctx, &verification); err != nil {
+		return status, fmt.Errorf("while creating restore verification cluster %s: %w", verification.Name, err)
+	}
+	status.Snapshot = latest.Snapshot
+	status.Phase = esv1.RestoreVerificationInProgress
+	return status, nil
+}
+
+// checkVerification updates the status of an ongoing verification from the status of the restore in the verification
+// cluster. It returns true if the verification is completed, in which case the verification cluster is deleted.
+func checkVerification(
+	ctx context.Context,
+	k8sClient k8s.Client,
+	recorder *events.Recorder,
+	es esv1.Elasticsearch,
+	status *esv1.RestoreVerificationStatus,
+	now time.Time,
+) (bool, error) {
+	var verification esv1.Elasticsearch
+	err := k8sClient.Get(ctx, types.NamespacedName{Namespace: es.Namespace, Name: VerificationClusterName(es.Name)}, &verification)
+	if err != nil && !apierrors.IsNotFound(err) {
+		return false, err
+	}
+	restore := verification.Status.InitialRestore
+	switch {
+	case err == nil && verification.Labels[RestoreVerificationOfLabelName] != es.Name:
+		completeVerification(recorder, status, esv1.RestoreVerificationFailed,
+			fmt.Sprintf("Cluster %s was not created by the operator to verify snapshot %s", verification.Name, status.Snapshot), now)
+		return true, nil
+	case apierrors.IsNotFound(err):
+		completeVerification(recorder, status, esv1.RestoreVerificationFailed,
+			fmt.Sprintf("Verification cluster of snapshot %s was deleted before the end of the verification", status.Snapshot), now)
+		return true, nil
+	case restore != nil && restore.Phase == esv1.InitialRestoreCompleted:
+		var count int64
+		if restore.DocumentCount != nil {
+			count = *restore.DocumentCount
+		}
+		status.DocumentCount = &count
+		if minCount := es.Spec.RestoreVerification.MinDocumentCountOrDefault(); count < minCount {
+			completeVerification(recorder, status, esv1.RestoreVerificationFailed,
+				fmt.Sprintf("Restored %d documents from snapshot %s, expected at least %d", count, status.Snapshot, minCount), now)
+		} else {
+			completeVerification(recorder, status, esv1.RestoreVerificationSucceeded,
+				fmt.Sprintf("Restored %d documents from snapshot %s", count, status.Snapshot), now)
+		}
+	case restore != nil && restore.Phase == esv1.InitialRestoreFailed:
+		completeVerification(recorder, status, esv1.RestoreVerificationFailed, restore.Message, now)
+	case status.StartTime != nil && now.Sub(status.StartTime.Time) > RestoreVerificationTimeout:
+		completeVerification(recorder, status, esv1.RestoreVerificationFailed,
+			fmt.Sprintf("Verification of snapshot %s did not complete within %s", status.Snapshot, RestoreVerificationTimeout), now)
+	default:
+		if restore != nil {
+			status.Message = restore.Message
+		}
+		return false, nil
+	}
+	return true, deleteVerificationCluster(ctx, k8sClient, es)
+}
+
+// completeVerification sets the result of a verification in its status, and records it in an event.
+func completeVerification(
+	recorder *events.Recorder,
+	status *esv1.RestoreVerificationStatus,
+	phase esv1.RestoreVerificationPhase,
+	message string,
+	now time.Time,
+) {
+	status.Phase = phase
+	status.Message = message
+	status.CompletionTime = statusTime(now)
+	eventType := corev1.EventTypeNormal
+	if phase == esv1.RestoreVerificationFailed {
+		eventType = corev1.EventTypeWarning
+	}
+	recorder.AddEvent(eventType, events.EventReasonRestore, "Restore verification: "+message)
+}
+
+// deleteVerificationCluster deletes the verification cluster of the given cluster, if it exists.
+func deleteVerificationCluster(ctx context.Context, k8sClient k8s.Client, es esv1.Elasticsearch) error {
+	var verification esv1.Elasticsearch
+	err := k8sClient.Get(ctx, types.NamespacedName{Namespace: es.Namespace, Name: VerificationClusterName(es.Name)}, &verification)
+	if apierrors.IsNotFound(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	// do not delete a cluster which happens to have the same name but was not created by the operator
+	if verification.Labels[RestoreVerificationOfLabelName] != es.Name {
+		return nil
+	}
+	ulog.FromContext(ctx).Info("Deleting restore verification cluster", "namespace", es.Namespace, "es_name", es.Name)
+	if err := k8sClient.Delete(ctx, &verification); err != nil && !apierrors.IsNotFound(err) {
+		return err
+	}
+	return nil
+}
+
+// newVerificationCluster returns a single-node cluster restoring the given snapshot from the given repository, which
+// is registered as read-only to not interfere with the snapshots of the verified cluster.
+func newVerificationCluster(es esv1.Elasticsearch, repository esv1.SnapshotRepository, snapshot string) (esv1.Elasticsearch, error) {
+	spec := es.Spec.RestoreVerification
+
+	settings := map[string]interface{}{}
+	if repository.Settings != nil {
+		for k, v := range repository.Settings.DeepCopy().Data {
+			settings[k] = v
+		}
+	}
+	settings["readonly"] = true
+	repository.Settings = &commonv1.Config{Data: settings}
+	repository.Schedule = nil
+
+	// the restored data is discarded along with the cluster
+	podTemplate := *spec.PodTemplate.DeepCopy()
+	podTemplate.Spec.Volumes = append(podTemplate.Spec.Volumes, corev1.Volume{
+		Name:         esvolume.ElasticsearchDataVolumeName,
+		VolumeSource: corev1.VolumeSource{EmptyDir: &corev1.EmptyDirVolumeSource{}},
+	})
+
+	verification := esv1.Elasticsearch{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: es.Namespace,
+			Name:      VerificationClusterName(es.Name),
+			Labels:    map[string]string{RestoreVerificationOfLabelName: es.Name},
+		},
+		Spec: esv1.ElasticsearchSpec{
+			Version:              es.Spec.Version,
+			Image:                es.Spec.Image,
+			Plugins:              es.Spec.Plugins,
+			SetVMMaxMapCount:     es.Spec.SetVMMaxMapCount,
+			SecureSettings:       es.Spec.SecureSettings,
+			SnapshotVolumes:      es.Spec.SnapshotVolumes,
+			SnapshotRepositories: []esv1.SnapshotRepository{repository},
+			InitialRestore: &esv1.InitialRestore{
+				Repository: repository.Name,
+				Snapshot:   snapshot,
+				Indices:    spec.Indices,
+			},
+			NodeSets: []esv1.NodeSet{{
+				Name:        verificationNodeSetName,
+				Count:       1,
+				PodTemplate: podTemplate,
+			}},
+		},
+	}
+	// the verification cluster is garbage collected along with the verified cluster
+	if err := controllerutil.SetControllerReference(&es, &verification, scheme.Scheme); err != nil {
+		return esv1.Elasticsearch{}, err
+	}
+	return verification, nil
+}
+
+// declaredRepository returns the repository of the given name declared in the spec.
+func declaredRepository(es esv1.Elasticsearch, name string) (esv1.SnapshotRepository, bool) {
+	for _, repository := range es.Spec.SnapshotRepositories {
+		if repository.Name == name {
+			return *repository.DeepCopy(), true
+		}
+	}
+	return esv1.SnapshotRepository{}, false
+}
+
+// latestSuccessfulSnapshot returns the successful snapshot which started last, or nil if there is none.
+func latestSuccessfulSnapshot(snapshots []esclient.Snapshot) *esclient.Snapshot {
+	var latest *esclient.Snapshot
+	for i := range snapshots {
+		if esv1.SnapshotState(snapshots[i].State) != esv1.SnapshotSuccess {
+			continue
+		}
+		if latest == nil || snapshots[i].StartTimeInMillis > latest.StartTimeInMillis {
+			latest = &snapshots[i]
+		}
+	}
+	return latest
+}
+
+// durationUntil returns the duration from now until the given time, or zero if the time is zero.
+func durationUntil(t time.Time, now time.Time) time.Duration {
+	if t.IsZero() {
+		return 0
+	}
+	return t.Sub(now)
+}
@@ -0,0 +1,238 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package snapshot
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/utils/pointer"
+
+	commonv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/common/v1"
+	esv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/elasticsearch/v1"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/events"
+	controllerscheme "github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/scheme"
+	esclient "github.com/elastic/cloud-on-k8s/v2/pkg/controller/elasticsearch/client"
+	esvolume "github.com/elastic/cloud-on-k8s/v2/pkg/controller/elasticsearch/volume"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/utils/k8s"
+)
+
+func TestReconcileRestoreVerification(t *testing.T) {
+	controllerscheme.SetupScheme()
+	// every day at 3 AM UTC
+	cron := "0 0 3 * * ?"
+	created := mustParseTime(t, "2022-10-17T10:00:00Z")
+	now := mustParseTime(t, "2022-10-18T03:00:30Z")
+	started := statusTime(mustParseTime(t, "2022-10-18T03:00:00Z"))
+	nextVerification := mustParseTime(t, "2022-10-19T03:00:00Z").Sub(now)
+
+	repository := esv1.SnapshotRepository{
+		Name:     "s3-backups",
+		Type:     esv1.S3SnapshotRepository,
+		Settings: &commonv1.Config{Data: map[string]interface{}{"bucket": "backups"}},
+		Schedule: &esv1.SnapshotSchedule{Cron: "0 30 1 * * ?"},
+	}
+	snapshots := []esclient.Snapshot{
+		{Snapshot: "snap-1", State: string(esv1.SnapshotSuccess), StartTimeInMillis: 1000},
+		{Snapshot: "snap-3", State: string(esv1.SnapshotFailed), StartTimeInMillis: 3000},
+		{Snapshot: "snap-2", State: string(esv1.SnapshotSuccess), StartTimeInMillis: 2000},
+	}
+	inProgress := &esv1.RestoreVerificationStatus{Snapshot: "snap-2", Phase: esv1.RestoreVerificationInProgress, StartTime: started}
+	verificationCluster := func(restore *esv1.InitialRestoreStatus) *esv1.Elasticsearch {
+		return &esv1.Elasticsearch{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace: "ns", Name: "es-eck-verify", Labels: map[string]string{RestoreVerificationOfLabelName: "es"},
+			},
+			Status: esv1.ElasticsearchStatus{InitialRestore: restore},
+		}
+	}
+	restoreCompleted := func(count int64) *esv1.InitialRestoreStatus {
+		return &esv1.InitialRestoreStatus{
+			Repository: "s3-backups", Snapshot: "snap-2", Phase: esv1.InitialRestoreCompleted, DocumentCount: &count,
+		}
+	}
+
+	tests := []struct {
+		name             string
+		verification     *esv1.RestoreVerification
+		status           *esv1.RestoreVerificationStatus
+		snapshots        []esclient.Snapshot
+		existing         []runtime.Object
+		now              time.Time
+		want             *esv1.RestoreVerificationStatus
+		wantRequeueAfter time.Duration
+		wantCluster      bool
+		wantEvent        bool
+	}{
+		{
+			name: "no restore verification",
+			now:  now,
+		},
+		{
+			name:     "restore verification removed: delete the verification cluster",
+			status:   inProgress,
+			existing: []runtime.Object{verificationCluster(nil)},
+			now:      now,
+		},
+		{
+			name:             "first verification not due yet",
+			verification:     &esv1.RestoreVerification{Repository: "s3-backups", Cron: cron},
+			now:              mustParseTime(t, "2022-10-18T01:00:00Z"),
+			wantRequeueAfter: 2 * time.Hour,
+		},
+		{
+			name:         "no successful snapshot to verify",
+			verification: &esv1.RestoreVerification{Repository: "s3-backups", Cron: cron},
+			snapshots:    []esclient.Snapshot{{Snapshot: "snap-3", State: string(esv1.SnapshotFailed), StartTimeInMillis: 3000}},
+			now:          now,
+			want: &esv1.RestoreVerificationStatus{
+				Phase: esv1.RestoreVerificationFailed, Message: "No successful snapshot to verify in repository s3-backups",
+				StartTime: statusTime(now), CompletionTime: statusTime(now),
+			},
+			wantRequeueAfter: nextVerification,
+			wantEvent:        true,
+		},
+		{
+			name:         "verification due: create the verification cluster",
+			verification: &esv1.RestoreVerification{Repository: "s3-backups", Cron: cron},
+			snapshots:    snapshots,
+			now:          now,
+			want: &esv1.RestoreVerificationStatus{
+				Snapshot: "snap-2", Phase: esv1.RestoreVerificationInProgress, StartTime: statusTime(now),
+			},
+			wantRequeueAfter: restoreVerificationCheckInterval,
+			wantCluster:      true,
+		},
+		{
+			name:         "snapshot being restored",
+			verification: &esv1.RestoreVerification{Repository: "s3-backups", Cron: cron},
+			status:       inProgress,
+			existing: []runtime.Object{verificationCluster(&esv1.InitialRestoreStatus{
+				Repository: "s3-backups", Snapshot: "snap-2", Phase: esv1.InitialRestoreInProgress, Message: "3 shards being restored, cluster health is red",
+			})},
+			now: now,
+			want: &esv1.RestoreVerificationStatus{
+				Snapshot: "snap-2", Phase: esv1.RestoreVerificationInProgress, StartTime: started,
+				Message: "3 shards being restored, cluster health is red",
+			},
+			wantRequeueAfter: restoreVerificationCheckInterval,
+			wantCluster:      true,
+		},
+		{
+			name:         "snapshot restored: verification succeeded",
+			verification: &esv1.RestoreVerification{Repository: "s3-backups", Cron: cron},
+			status:       inProgress,
+			existing:     []runtime.Object{verificationCluster(restoreCompleted(1234))},
+			now:          now,
+			want: &esv1.RestoreVerificationStatus{
+				Snapshot: "snap-2", Phase: esv1.RestoreVerificationSucceeded, StartTime: started, CompletionTime: statusTime(now),
+				Message: "Restored 1234 documents from snapshot snap-2", DocumentCount: pointer.Int64(1234),
+			},
+			wantRequeueAfter: nextVerification,
+			wantEvent:        true,
+		},
+		{
+			name:         "not enough documents restored: verification failed",
+			verification: &esv1.RestoreVerification{Repository: "s3-backups", Cron: cron, MinDocumentCount: pointer.Int64(2000)},
+			status:       inProgress,
+			existing:     []runtime.Object{verificationCluster(restoreCompleted(1234))},
+			now:          now,
+			want: &esv1.RestoreVerificationStatus{
+				Snapshot: "snap-2", Phase: esv1.RestoreVerificationFailed, StartTime: started, CompletionTime: statusTime(now),
+				Message: "Restored 1234 documents from snapshot snap-2, expected at least 2000", DocumentCount: pointer.Int64(1234),
+			},
+			wantRequeueAfter: nextVerification,
+			wantEvent:        true,
+		},
+		{
+			name:         "verification timed out",
+			verification: &esv1.RestoreVerification{Repository: "s3-backups", Cron: cron},
+			status:       inProgress,
+			existing:     []runtime.Object{verificationCluster(nil)},
+			now:          started.Add(RestoreVerificationTimeout + time.Minute),
+			want: &esv1.RestoreVerificationStatus{
+				Snapshot: "snap-2", Phase: esv1.RestoreVerificationFailed, StartTime: started,
+				CompletionTime: statusTime(started.Add(RestoreVerificationTimeout + time.Minute)),
+				Message:        "Verification of snapshot snap-2 did not complete within 6h0m0s",
+			},
+			wantRequeueAfter: mustParseTime(t, "2022-10-19T03:00:00Z").Sub(started.Add(RestoreVerificationTimeout + time.Minute)),
+			wantEvent:        true,
+		},
+		{
+			name:         "cluster not created by the operator: verification failed, cluster not deleted",
+			verification: &esv1.RestoreVerification{Repository: "s3-backups", Cron: cron},
+			status:       inProgress,
+			existing: []runtime.Object{&esv1.Elasticsearch{
+				ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "es-eck-verify"},
+				Status:     esv1.ElasticsearchStatus{InitialRestore: restoreCompleted(1234)},
+			}},
+			now: now,
+			want: &esv1.RestoreVerificationStatus{
+				Snapshot: "snap-2", Phase: esv1.RestoreVerificationFailed, StartTime: started, CompletionTime: statusTime(now),
+				Message: "Cluster es-eck-verify was not created by the operator to verify snapshot snap-2",
+			},
+			wantRequeueAfter: nextVerification,
+			wantCluster:      true,
+			wantEvent:        true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			es := esv1.Elasticsearch{
+				ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "es", CreationTimestamp: metav1.NewTime(created)},
+				Spec: esv1.ElasticsearchSpec{
+					Version:              "8.4.0",
+					SnapshotRepositories: []esv1.SnapshotRepository{repository},
+					RestoreVerification:  tt.verification,
+				},
+				Status: esv1.ElasticsearchStatus{RestoreVerification: tt.status},
+			}
+			k8sClient := k8s.NewFakeClient(append(tt.existing, &es)...)
+			recorder := events.NewRecorder()
+			esClient := &fakeESClient{snapshots: tt.snapshots}
+
+			got, requeueAfter, err := ReconcileRestoreVerification(context.Background(), k8sClient, esClient, recorder, es, tt.now)
+			require.NoError(t, err)
+			require.Equal(t, tt.want, got)
+			require.Equal(t, tt.wantRequeueAfter, requeueAfter)
+			require.Equal(t, tt.wantEvent, len(recorder.Events()) == 1)
+
+			var verification esv1.Elasticsearch
+			err = k8sClient.Get(context.Background(), types.NamespacedName{Namespace: "ns", Name: "es-eck-verify"}, &verification)
+			if !tt.wantCluster {
+				require.True(t, apierrors.IsNotFound(err))
+				return
+			}
+			require.NoError(t, err)
+			if tt.status != nil {
+				return
+			}
+			// the verification cluster restores the latest successful snapshot from the read-only repository
+			require.Equal(t, "es", verification.Labels[RestoreVerificationOfLabelName])
+			require.Len(t, verification.OwnerReferences, 1)
+			require.Equal(t, &esv1.InitialRestore{Repository: "s3-backups", Snapshot: "snap-2"}, verification.Spec.InitialRestore)
+			require.Len(t, verification.Spec.SnapshotRepositories, 1)
+			verificationRepository := verification.Spec.SnapshotRepositories[0]
+			require.Nil(t, verificationRepository.Schedule)
+			require.Equal(t, map[string]interface{}{"bucket": "backups", "readonly": true}, verificationRepository.Settings.Data)
+			require.Len(t, verification.Spec.NodeSets, 1)
+			require.Equal(t, int32(1), verification.Spec.NodeSets[0].Count)
+			require.Equal(t, []corev1.Volume{{
+				Name:         esvolume.ElasticsearchDataVolumeName,
+				VolumeSource: corev1.VolumeSource{EmptyDir: &corev1.EmptyDirVolumeSource{}},
+			}}, verification.Spec.NodeSets[0].PodTemplate.Spec.Volumes)
+			// the repository of the verified cluster is left untouched
+			require.NotNil(t, es.Spec.SnapshotRepositories[0].Schedule)
+			require.NotContains(t, es.Spec.SnapshotRepositories[0].Settings.Data, "readonly")
+		})
+	}
+}
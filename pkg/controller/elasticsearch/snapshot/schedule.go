@@ -0,0 +1,413 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package snapshot
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"go.elastic.co/apm/v2"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	esv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/elasticsearch/v1"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/events"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/tracing"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/version"
+	esclient "github.com/elastic/cloud-on-k8s/v2/pkg/controller/elasticsearch/client"
+	ulog "github.com/elastic/cloud-on-k8s/v2/pkg/utils/log"
+)
+
+const (
+	// managedPrefix prefixes the names of the SLM policies and of the snapshots managed by the operator.
+	managedPrefix = "eck-"
+	// snapshotTimeFormat is the format of the time suffix of the snapshots taken by the operator.
+	snapshotTimeFormat = "2006.01.02-15.04.05"
+	// snapshotInProgressRequeue is the delay after which the state of an in progress snapshot is checked again.
+	snapshotInProgressRequeue = time.Minute
+)
+
+// minSLMRetentionVersion is the first version of Elasticsearch supporting the retention of snapshots in snapshot
+// lifecycle management policies.
+var minSLMRetentionVersion = version.MinFor(7, 5, 0)
+
+// IsSLMSupported returns true if the snapshot schedules are configured as SLM policies in the given version of
+// Elasticsearch, rather than run by the operator.
+func IsSLMSupported(v version.Version) bool {
+	return v.GTE(minSLMRetentionVersion)
+}
+
+// SLMPolicyName returns the name of the SLM policy taking the scheduled snapshots of the given repository.
+func SLMPolicyName(repository string) string {
+	return managedPrefix + repository
+}
+
+// snapshotNamePrefix returns the prefix of the names of the scheduled snapshots of the given repository.
+func snapshotNamePrefix(repository string) string {
+	return strings.ToLower(managedPrefix+repository) + "-"
+}
+
+// ReconcileSchedules takes the snapshots scheduled in the snapshot repositories of the Elasticsearch spec. Schedules are
+// configured as SLM policies if the running version of Elasticsearch supports them. Otherwise the operator takes the
+// snapshots when they are due, and deletes the ones expired according to the retention of the schedule.
+// It returns the status of the scheduled snapshots, and the delay after which they should be reconciled again.
+func ReconcileSchedules(
+	ctx context.Context,
+	esClient esclient.SnapshotClient,
+	recorder *events.Recorder,
+	es esv1.Elasticsearch,
+	v version.Version,
+	now time.Time,
+) ([]esv1.SnapshotRepositoryStatus, time.Duration, error) {
+	var scheduled []esv1.SnapshotRepository
+	for _, repository := range es.Spec.SnapshotRepositories {
+		if repository.Schedule != nil {
+			scheduled = append(scheduled, repository)
+		}
+	}
+	// keep reconciling the schedules until the SLM policies of the schedules removed from the spec are deleted
+	if len(scheduled) == 0 && len(es.Status.SnapshotRepositories) == 0 {
+		return nil, 0, nil
+	}
+
+	span, ctx := apm.StartSpan(ctx, "reconcile_snapshot_schedules", tracing.SpanTypeApp)
+	defer span.End()
+
+	var statuses []esv1.SnapshotRepositoryStatus
+	var requeueAfter time.Duration
+	var err error
+	if IsSLMSupported(v) {
+		statuses, requeueAfter, err = reconcileSLMPolicies(ctx, esClient, es, scheduled, now)
+	} else {
+		statuses, requeueAfter, err = takeScheduledSnapshots(ctx, esClient, es, scheduled, now)
+	}
+	if err != nil {
+		return nil, 0, err
+	}
+	reportSnapshotEvents(recorder, es.Status.SnapshotRepositories, statuses)
+	return statuses, requeueAfter, nil
+}
+
+// reconcileSLMPolicies creates or updates the SLM policies of the scheduled repositories, and deletes the SLM policies of
+// the declared repositories which are not scheduled anymore. It returns the status of the last executions of the
+// policies along with the delay after which the next execution should be checked.
+func reconcileSLMPolicies(
+	ctx context.Context,
+	esClient esclient.SnapshotClient,
+	es esv1.Elasticsearch,
+	scheduled []esv1.SnapshotRepository,
+	now time.Time,
+) ([]esv1.SnapshotRepositoryStatus, time.Duration, error) {
+	policies, err := esClient.GetSLMPolicies(ctx)
+	if err != nil {
+		return nil, 0, err
+	}
+	var requeueAfter time.Duration
+	requeueIn := func(d time.Duration) {
+		if d > 0 && (requeueAfter == 0 || d < requeueAfter) {
+			requeueAfter = d
+		}
+	}
+	statuses := make([]esv1.SnapshotRepositoryStatus, 0, len(scheduled))
+	for _, repository := range scheduled {
+		name := SLMPolicyName(repository.Name)
+		expected := expectedSLMPolicy(repository)
+		current, exists := policies[name]
+		if !exists || !current.Policy.Equal(expected) {
+			ulog.FromContext(ctx).Info("Updating snapshot lifecycle management policy",
+				"namespace", es.Namespace, "es_name", es.Name, "repository", repository.Name, "policy", name)
+			if err := esClient.UpdateSLMPolicy(ctx, name, expected); err != nil {
+				return nil, 0, fmt.Errorf("while updating SLM policy %s: %w", name, err)
+			}
+		}
+		status := slmPolicyStatus(repository.Name, current)
+		switch {
+		case status.SnapshotInProgress:
+			requeueIn(snapshotInProgressRequeue)
+		case status.NextSnapshotTime != nil:
+			requeueIn(status.NextSnapshotTime.Sub(now))
+		}
+		statuses = append(statuses, status)
+	}
+	for _, repository := range es.Spec.SnapshotRepositories {
+		name := SLMPolicyName(repository.Name)
+		current, exists := policies[name]
+		if repository.Schedule != nil || !exists || current.Policy.Repository != repository.Name {
+			continue
+		}
+		ulog.FromContext(ctx).Info("Deleting snapshot lifecycle management policy",
+			"namespace", es.Namespace, "es_name", es.Name, "repository", repository.Name, "policy", name)
+		if err := esClient.DeleteSLMPolicy(ctx, name); err != nil {
+			return nil, 0, fmt.Errorf("while deleting SLM policy %s: %w", name, err)
+		}
+	}
+	return statuses, requeueAfter, nil
+}
+
+// expectedSLMPolicy returns the SLM policy expected in Elasticsearch for the given scheduled repository.
+func expectedSLMPolicy(repository esv1.SnapshotRepository) esclient.SLMPolicy {
+	schedule := repository.Schedule
+	policy := esclient.SLMPolicy{
+		Schedule:   schedule.Cron,
+		Name:       "<" + snapshotNamePrefix(repository.Name) + "{now/d}>",
+		Repository: repository.Name,
+	}
+	if len(schedule.Indices) > 0 {
+		policy.Config = &esclient.SLMPolicyConfig{Indices: schedule.Indices}
+	}
+	if schedule.Retention != nil {
+		policy.Retention = &esclient.SLMRetention{
+			ExpireAfter: schedule.Retention.ExpireAfter,
+			MinCount:    schedule.Retention.MinCount,
+			MaxCount:    schedule.Retention.MaxCount,
+		}
+	}
+	return policy
+}
+
+// slmPolicyStatus returns the status of the scheduled snapshots of a repository from the executions of its SLM policy.
+func slmPolicyStatus(repository string, policy esclient.SLMPolicyDefinition) esv1.SnapshotRepositoryStatus {
+	status := esv1.SnapshotRepositoryStatus{Name: repository}
+	last, state := policy.LastSuccess, esv1.SnapshotSuccess
+	if policy.LastFailure != nil && (last == nil || policy.LastFailure.Time > last.Time) {
+		last, state = policy.LastFailure, esv1.SnapshotFailed
+	}
+	if last != nil {
+		status.LastSnapshot = last.SnapshotName
+		status.LastSnapshotTime = statusTime(time.UnixMilli(last.Time))
+		status.LastSnapshotState = state
+	}
+	if policy.LastSuccess != nil {
+		status.LastSuccessTime = statusTime(time.UnixMilli(policy.LastSuccess.Time))
+	}
+	if policy.LastFailure != nil {
+		status.LastFailureTime = statusTime(time.UnixMilli(policy.LastFailure.Time))
+		status.LastFailureReason = policy.LastFailure.FailureReason()
+	}
+	status.SnapshotInProgress = policy.InProgress != nil
+	if policy.NextExecutionMillis > 0 {
+		status.NextSnapshotTime = statusTime(time.UnixMilli(policy.NextExecutionMillis))
+	}
+	return status
+}
+
+// takeScheduledSnapshots takes the snapshots of the scheduled repositories which are due, and deletes the snapshots
+// expired according to their retention. The first snapshot of a repository is due at the first scheduled time after the
+// creation of the cluster.
+func takeScheduledSnapshots(
+	ctx context.Context,
+	esClient esclient.SnapshotClient,
+	es esv1.Elasticsearch,
+	scheduled []esv1.SnapshotRepository,
+	now time.Time,
+) ([]esv1.SnapshotRepositoryStatus, time.Duration, error) {
+	var requeueAfter time.Duration
+	requeueIn := func(d time.Duration) {
+		if requeueAfter == 0 || d < requeueAfter {
+			requeueAfter = d
+		}
+	}
+	statuses := make([]esv1.SnapshotRepositoryStatus, 0, len(scheduled))
+	for _, repository := range scheduled {
+		cron, err := ParseCron(repository.Schedule.Cron)
+		if err != nil {
+			return nil, 0, fmt.Errorf("while parsing the schedule of snapshot repository %s: %w", repository.Name, err)
+		}
+		all, err := esClient.GetSnapshots(ctx, repository.Name)
+		if err != nil {
+			return nil, 0, err
+		}
+		taken := scheduledSnapshots(repository.Name, all)
+
+		lastTime, inProgress := es.CreationTimestamp.Time, false
+		if len(taken) > 0 {
+			last := taken[len(taken)-1]
+			lastTime, inProgress = last.StartTime(), last.State == string(esv1.SnapshotInProgress)
+		}
+		switch next := cron.Next(lastTime); {
+		case inProgress:
+			// wait for the completion of the snapshot before taking or deleting another one
+		case !next.IsZero() && !next.After(now):
+			name := snapshotNamePrefix(repository.Name) + now.UTC().Format(snapshotTimeFormat)
+			ulog.FromContext(ctx).Info("Taking scheduled snapshot",
+				"namespace", es.Namespace, "es_name", es.Name, "repository", repository.Name, "snapshot", name)
+			if err := esClient.CreateSnapshot(ctx, repository.Name, name, repository.Schedule.Indices); err != nil {
+				return nil, 0, fmt.Errorf("while taking snapshot %s in repository %s: %w", name, repository.Name, err)
+			}
+			taken = append(taken, esclient.Snapshot{Snapshot: name, State: string(esv1.SnapshotInProgress), StartTimeInMillis: now.UnixMilli()})
+		default:
+			expired, err := expiredSnapshots(repository.Schedule.Retention, taken, now)
+			if err != nil {
+				return nil, 0, err
+			}
+			for _, snapshot := range expired {
+				ulog.FromContext(ctx).Info("Deleting expired snapshot",
+					"namespace", es.Namespace, "es_name", es.Name, "repository", repository.Name, "snapshot", snapshot.Snapshot)
+				if err := esClient.DeleteSnapshot(ctx, repository.Name, snapshot.Snapshot); err != nil {
+					return nil, 0, fmt.Errorf("while deleting snapshot %s in repository %s: %w", snapshot.Snapshot, repository.Name, err)
+				}
+			}
+		}
+
+		status := takenSnapshotsStatus(repository.Name, taken)
+		if status.SnapshotInProgress {
+			requeueIn(snapshotInProgressRequeue)
+		}
+		if next := cron.Next(now); !next.IsZero() {
+			status.NextSnapshotTime = statusTime(next)
+			requeueIn(next.Sub(now))
+		}
+		statuses = append(statuses, status)
+	}
+	return statuses, requeueAfter, nil
+}
+
+// takenSnapshotsStatus returns the status of the scheduled snapshots of a repository from the snapshots taken by the
+// operator, sorted by start time.
+func takenSnapshotsStatus(repository string, taken []esclient.Snapshot) esv1.SnapshotRepositoryStatus {
+	status := esv1.SnapshotRepositoryStatus{Name: repository}
+	if len(taken) == 0 {
+		return status
+	}
+	last := taken[len(taken)-1]
+	status.LastSnapshot = last.Snapshot
+	status.LastSnapshotTime = statusTime(last.StartTime())
+	status.LastSnapshotState = esv1.SnapshotState(last.State)
+	status.SnapshotInProgress = status.LastSnapshotState == esv1.SnapshotInProgress
+	for i := len(taken) - 1; i >= 0; i-- {
+		snapshot := taken[i]
+		switch esv1.SnapshotState(snapshot.State) {
+		case esv1.SnapshotInProgress:
+			continue
+		case esv1.SnapshotSuccess:
+			if status.LastSuccessTime == nil {
+				status.LastSuccessTime = statusTime(snapshot.StartTime())
+			}
+		default:
+			if status.LastFailureTime == nil {
+				status.LastFailureTime = statusTime(snapshot.StartTime())
+				status.LastFailureReason = snapshot.FailureReason()
+				if status.LastFailureReason == "" {
+					status.LastFailureReason = fmt.Sprintf("snapshot %s ended in state %s", snapshot.Snapshot, snapshot.State)
+				}
+			}
+		}
+		if status.LastSuccessTime != nil && status.LastFailureTime != nil {
+			break
+		}
+	}
+	return status
+}
+
+// scheduledSnapshots returns the snapshots taken by the operator in the given repository, sorted by start time.
+func scheduledSnapshots(repository string, snapshots []esclient.Snapshot) []esclient.Snapshot {
+	prefix := snapshotNamePrefix(repository)
+	var scheduled []esclient.Snapshot
+	for _, snapshot := range snapshots {
+		if strings.HasPrefix(snapshot.Snapshot, prefix) {
+			scheduled = append(scheduled, snapshot)
+		}
+	}
+	sort.SliceStable(scheduled, func(i, j int) bool {
+		return scheduled[i].StartTimeInMillis < scheduled[j].StartTimeInMillis
+	})
+	return scheduled
+}
+
+// expiredSnapshots returns the snapshots to delete according to the given retention, from snapshots sorted by start
+// time. Snapshots are expired if they are older than ExpireAfter or beyond MaxCount, but MinCount snapshots are kept.
+func expiredSnapshots(retention *esv1.SnapshotRetention, snapshots []esclient.Snapshot, now time.Time) ([]esclient.Snapshot, error) {
+	if retention == nil {
+		return nil, nil
+	}
+	var expireAfter time.Duration
+	if retention.ExpireAfter != "" {
+		var err error
+		if expireAfter, err = ParseTimeValue(retention.ExpireAfter); err != nil {
+			return nil, err
+		}
+	}
+	var expired []esclient.Snapshot
+	for i, snapshot := range snapshots {
+		remaining := len(snapshots) - i
+		if retention.MinCount != nil && remaining <= int(*retention.MinCount) {
+			break
+		}
+		tooMany := retention.MaxCount != nil && remaining > int(*retention.MaxCount)
+		tooOld := expireAfter > 0 && now.Sub(snapshot.StartTime()) > expireAfter
+		if tooMany || tooOld {
+			expired = append(expired, snapshot)
+		}
+	}
+	return expired, nil
+}
+
+// timeUnits are the time units of Elasticsearch, longest suffixes first.
+var timeUnits = []struct {
+	suffix string
+	unit   time.Duration
+}{
+	{suffix: "nanos", unit: time.Nanosecond},
+	{suffix: "micros", unit: time.Microsecond},
+	{suffix: "ms", unit: time.Millisecond},
+	{suffix: "d", unit: 24 * time.Hour},
+	{suffix: "h", unit: time.Hour},
+	{suffix: "m", unit: time.Minute},
+	{suffix: "s", unit: time.Second},
+}
+
+// ParseTimeValue parses a duration expressed with the time units of Elasticsearch, such as 30d or 12h.
+func ParseTimeValue(value string) (time.Duration, error) {
+	for _, u := range timeUnits {
+		if !strings.HasSuffix(value, u.suffix) {
+			continue
+		}
+		n, err := strconv.ParseInt(strings.TrimSuffix(value, u.suffix), 10, 64)
+		if err != nil || n < 0 {
+			break
+		}
+		return time.Duration(n) * u.unit, nil
+	}
+	return 0, fmt.Errorf("invalid time value %q, expected a positive integer followed by a time unit such as 30d", value)
+}
+
+// reportSnapshotEvents records an event for each scheduled snapshot which completed since the previous status. Failed
+// snapshots are reported as warnings along with the reason of their failure.
+func reportSnapshotEvents(recorder *events.Recorder, previous, current []esv1.SnapshotRepositoryStatus) {
+	previousByName := make(map[string]esv1.SnapshotRepositoryStatus, len(previous))
+	for _, status := range previous {
+		previousByName[status.Name] = status
+	}
+	for _, status := range current {
+		if status.LastSnapshot == "" || status.LastSnapshotState == esv1.SnapshotInProgress {
+			continue
+		}
+		if prev, exists := previousByName[status.Name]; exists &&
+			prev.LastSnapshot == status.LastSnapshot && prev.LastSnapshotState == status.LastSnapshotState {
+			continue
+		}
+		if status.LastSnapshotState == esv1.SnapshotSuccess {
+			recorder.AddEvent(corev1.EventTypeNormal, events.EventReasonSnapshot,
+				fmt.Sprintf("Snapshot %s completed in repository %s", status.LastSnapshot, status.Name))
+			continue
+		}
+		msg := fmt.Sprintf("Snapshot %s in repository %s ended in state %s", status.LastSnapshot, status.Name, status.LastSnapshotState)
+		if status.LastFailureReason != "" && status.LastFailureTime.Equal(status.LastSnapshotTime) {
+			msg = fmt.Sprintf("%s: %s", msg, status.LastFailureReason)
+		}
+		recorder.AddEvent(corev1.EventTypeWarning, events.EventReasonSnapshot, msg)
+	}
+}
+
+// statusTime returns the given time as read back from the status, truncated to the second and in the local time zone,
+// so that statuses can be compared.
+func statusTime(t time.Time) *metav1.Time {
+	converted := metav1.NewTime(t.Truncate(time.Second).Local())
+	return &converted
+}
@@ -0,0 +1,266 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package snapshot
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/pointer"
+
+	esv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/elasticsearch/v1"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/events"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/version"
+	esclient "github.com/elastic/cloud-on-k8s/v2/pkg/controller/elasticsearch/client"
+)
+
+func snapshotAt(t *testing.T, name, state, startTime string) esclient.Snapshot {
+	t.Helper()
+	return esclient.Snapshot{Snapshot: name, State: state, StartTimeInMillis: mustParseTime(t, startTime).UnixMilli()}
+}
+
+func TestReconcileSchedules_SLM(t *testing.T) {
+	scheduled := esv1.SnapshotRepository{
+		Name:     "s3-backups",
+		Type:     esv1.S3SnapshotRepository,
+		Schedule: &esv1.SnapshotSchedule{Cron: "0 30 1 * * ?", Retention: &esv1.SnapshotRetention{ExpireAfter: "30d"}},
+	}
+	es := newEsWithRepositories(scheduled, esv1.SnapshotRepository{Name: "unscheduled", Type: esv1.FSSnapshotRepository})
+	now := mustParseTime(t, "2022-10-17T10:00:00Z")
+
+	// the policy is created
+	esClient := &fakeESClient{policies: esclient.SLMPolicies{}}
+	recorder := events.NewRecorder()
+	statuses, requeueAfter, err := ReconcileSchedules(context.Background(), esClient, recorder, es, version.MustParse("8.5.0"), now)
+	require.NoError(t, err)
+	require.Equal(t, []string{"eck-s3-backups"}, esClient.updatedPolicies)
+	require.Equal(t, []esv1.SnapshotRepositoryStatus{{Name: "s3-backups"}}, statuses)
+	require.Zero(t, requeueAfter)
+	require.Empty(t, recorder.Events())
+
+	// the policy is up to date, its last failure is reported
+	esClient = &fakeESClient{policies: esclient.SLMPolicies{"eck-s3-backups": {
+		Policy:      expectedSLMPolicy(scheduled),
+		LastSuccess: &esclient.SLMInvocation{SnapshotName: "eck-s3-backups-2022.10.15-a", Time: mustParseTime(t, "2022-10-15T01:30:00Z").UnixMilli()},
+		LastFailure: &esclient.SLMInvocation{
+			SnapshotName: "eck-s3-backups-2022.10.16-b",
+			Time:         mustParseTime(t, "2022-10-16T01:30:00Z").UnixMilli(),
+			Details:      `{"type":"snapshot_exception","reason":"repository is readonly"}`,
+		},
+		NextExecutionMillis: mustParseTime(t, "2022-10-18T01:30:00Z").UnixMilli(),
+	}}}
+	statuses, requeueAfter, err = ReconcileSchedules(context.Background(), esClient, recorder, es, version.MustParse("8.5.0"), now)
+	require.NoError(t, err)
+	require.Empty(t, esClient.updatedPolicies)
+	require.Equal(t, []esv1.SnapshotRepositoryStatus{{
+		Name:              "s3-backups",
+		LastSnapshot:      "eck-s3-backups-2022.10.16-b",
+		LastSnapshotTime:  statusTime(mustParseTime(t, "2022-10-16T01:30:00Z")),
+		LastSnapshotState: esv1.SnapshotFailed,
+		LastSuccessTime:   statusTime(mustParseTime(t, "2022-10-15T01:30:00Z")),
+		LastFailureTime:   statusTime(mustParseTime(t, "2022-10-16T01:30:00Z")),
+		LastFailureReason: "repository is readonly",
+		NextSnapshotTime:  statusTime(mustParseTime(t, "2022-10-18T01:30:00Z")),
+	}}, statuses)
+	require.Equal(t, 15*time.Hour+30*time.Minute, requeueAfter)
+	require.Len(t, recorder.Events(), 1)
+	require.Contains(t, recorder.Events()[0].Message, "repository is readonly")
+
+	// a snapshot is in progress
+	esClient.policies["eck-s3-backups"] = esclient.SLMPolicyDefinition{
+		Policy:     expectedSLMPolicy(scheduled),
+		InProgress: &esclient.SLMInProgress{Name: "eck-s3-backups-2022.10.17-c", State: "STARTED"},
+	}
+	statuses, requeueAfter, err = ReconcileSchedules(context.Background(), esClient, recorder, es, version.MustParse("8.5.0"), now)
+	require.NoError(t, err)
+	require.True(t, statuses[0].SnapshotInProgress)
+	require.Equal(t, snapshotInProgressRequeue, requeueAfter)
+
+	// the schedule is removed from the spec, the policy is deleted
+	es.Spec.SnapshotRepositories[0].Schedule = nil
+	es.Status.SnapshotRepositories = statuses
+	statuses, requeueAfter, err = ReconcileSchedules(context.Background(), esClient, recorder, es, version.MustParse("8.5.0"), now)
+	require.NoError(t, err)
+	require.Empty(t, statuses)
+	require.Zero(t, requeueAfter)
+	require.Equal(t, []string{"eck-s3-backups"}, esClient.deletedPolicies)
+}
+
+func TestReconcileSchedules_Operator(t *testing.T) {
+	scheduled := esv1.SnapshotRepository{
+		Name: "s3-backups",
+		Type: esv1.S3SnapshotRepository,
+		Schedule: &esv1.SnapshotSchedule{
+			Cron:      "0 30 1 * * ?",
+			Retention: &esv1.SnapshotRetention{MaxCount: pointer.Int32(2)},
+		},
+	}
+	now := mustParseTime(t, "2022-10-17T10:00:00Z")
+
+	tests := []struct {
+		name             string
+		snapshots        []esclient.Snapshot
+		wantCreated      []string
+		wantDeleted      []string
+		wantLastSnapshot string
+		wantRequeueAfter time.Duration
+	}{
+		{
+			name:             "first snapshot due since the creation of the cluster",
+			wantCreated:      []string{"eck-s3-backups-2022.10.17-10.00.00"},
+			wantLastSnapshot: "eck-s3-backups-2022.10.17-10.00.00",
+			wantRequeueAfter: snapshotInProgressRequeue,
+		},
+		{
+			name: "snapshot due",
+			snapshots: []esclient.Snapshot{
+				snapshotAt(t, "eck-s3-backups-2022.10.16-01.30.00", "SUCCESS", "2022-10-16T01:30:00Z"),
+				snapshotAt(t, "manual", "SUCCESS", "2022-10-17T09:00:00Z"),
+			},
+			wantCreated:      []string{"eck-s3-backups-2022.10.17-10.00.00"},
+			wantLastSnapshot: "eck-s3-backups-2022.10.17-10.00.00",
+			wantRequeueAfter: snapshotInProgressRequeue,
+		},
+		{
+			name: "snapshot not due, expired snapshots deleted",
+			snapshots: []esclient.Snapshot{
+				snapshotAt(t, "eck-s3-backups-2022.10.17-01.30.00", "SUCCESS", "2022-10-17T01:30:00Z"),
+				snapshotAt(t, "eck-s3-backups-2022.10.15-01.30.00", "SUCCESS", "2022-10-15T01:30:00Z"),
+				snapshotAt(t, "eck-s3-backups-2022.10.16-01.30.00", "FAILED", "2022-10-16T01:30:00Z"),
+			},
+			wantDeleted:      []string{"eck-s3-backups-2022.10.15-01.30.00"},
+			wantLastSnapshot: "eck-s3-backups-2022.10.17-01.30.00",
+			wantRequeueAfter: 15*time.Hour + 30*time.Minute,
+		},
+		{
+			name: "snapshot in progress",
+			snapshots: []esclient.Snapshot{
+				snapshotAt(t, "eck-s3-backups-2022.10.15-01.30.00", "SUCCESS", "2022-10-15T01:30:00Z"),
+				snapshotAt(t, "eck-s3-backups-2022.10.16-01.30.00", "SUCCESS", "2022-10-16T01:30:00Z"),
+				snapshotAt(t, "eck-s3-backups-2022.10.17-01.30.00", "IN_PROGRESS", "2022-10-17T01:30:00Z"),
+			},
+			wantLastSnapshot: "eck-s3-backups-2022.10.17-01.30.00",
+			wantRequeueAfter: snapshotInProgressRequeue,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			es := newEsWithRepositories(scheduled)
+			es.CreationTimestamp = metav1.NewTime(mustParseTime(t, "2022-10-01T00:00:00Z"))
+			esClient := &fakeESClient{snapshots: tt.snapshots}
+			statuses, requeueAfter, err := ReconcileSchedules(context.Background(), esClient, events.NewRecorder(), es, version.MustParse("7.3.2"), now)
+			require.NoError(t, err)
+			require.Equal(t, tt.wantCreated, esClient.created)
+			require.Equal(t, tt.wantDeleted, esClient.deleted)
+			require.Len(t, statuses, 1)
+			require.Equal(t, tt.wantLastSnapshot, statuses[0].LastSnapshot)
+			require.Equal(t, statusTime(mustParseTime(t, "2022-10-18T01:30:00Z")), statuses[0].NextSnapshotTime)
+			require.Equal(t, tt.wantRequeueAfter, requeueAfter)
+		})
+	}
+}
+
+func Test_takenSnapshotsStatus(t *testing.T) {
+	require.Equal(t, esv1.SnapshotRepositoryStatus{Name: "s3-backups"}, takenSnapshotsStatus("s3-backups", nil))
+
+	failed := snapshotAt(t, "eck-s3-backups-2022.10.15-01.30.00", "FAILED", "2022-10-15T01:30:00Z")
+	failed.Reason = "repository is readonly"
+	taken := []esclient.Snapshot{
+		snapshotAt(t, "eck-s3-backups-2022.10.14-01.30.00", "SUCCESS", "2022-10-14T01:30:00Z"),
+		failed,
+		snapshotAt(t, "eck-s3-backups-2022.10.16-01.30.00", "PARTIAL", "2022-10-16T01:30:00Z"),
+		snapshotAt(t, "eck-s3-backups-2022.10.17-01.30.00", "IN_PROGRESS", "2022-10-17T01:30:00Z"),
+	}
+	require.Equal(t, esv1.SnapshotRepositoryStatus{
+		Name:               "s3-backups",
+		LastSnapshot:       "eck-s3-backups-2022.10.17-01.30.00",
+		LastSnapshotTime:   statusTime(mustParseTime(t, "2022-10-17T01:30:00Z")),
+		LastSnapshotState:  esv1.SnapshotInProgress,
+		LastSuccessTime:    statusTime(mustParseTime(t, "2022-10-14T01:30:00Z")),
+		LastFailureTime:    statusTime(mustParseTime(t, "2022-10-16T01:30:00Z")),
+		LastFailureReason:  "snapshot eck-s3-backups-2022.10.16-01.30.00 ended in state PARTIAL",
+		SnapshotInProgress: true,
+	}, takenSnapshotsStatus("s3-backups", taken))
+
+	status := takenSnapshotsStatus("s3-backups", taken[:2])
+	require.Equal(t, "repository is readonly", status.LastFailureReason)
+	require.False(t, status.SnapshotInProgress)
+}
+
+func Test_expiredSnapshots(t *testing.T) {
+	now := mustParseTime(t, "2022-10-17T10:00:00Z")
+	snapshots := []esclient.Snapshot{
+		snapshotAt(t, "a", "SUCCESS", "2022-10-01T00:00:00Z"),
+		snapshotAt(t, "b", "SUCCESS", "2022-10-10T00:00:00Z"),
+		snapshotAt(t, "c", "SUCCESS", "2022-10-15T00:00:00Z"),
+		snapshotAt(t, "d", "SUCCESS", "2022-10-17T00:00:00Z"),
+	}
+	names := func(snapshots []esclient.Snapshot) []string {
+		var result []string
+		for _, s := range snapshots {
+			result = append(result, s.Snapshot)
+		}
+		return result
+	}
+
+	tests := []struct {
+		name      string
+		retention *esv1.SnapshotRetention
+		want      []string
+	}{
+		{
+			name: "no retention",
+		},
+		{
+			name:      "expired snapshots",
+			retention: &esv1.SnapshotRetention{ExpireAfter: "7d"},
+			want:      []string{"a", "b"},
+		},
+		{
+			name:      "expired snapshots, minimum count kept",
+			retention: &esv1.SnapshotRetention{ExpireAfter: "1d", MinCount: pointer.Int32(2)},
+			want:      []string{"a", "b"},
+		},
+		{
+			name:      "maximum count",
+			retention: &esv1.SnapshotRetention{MaxCount: pointer.Int32(3)},
+			want:      []string{"a"},
+		},
+		{
+			name:      "expired snapshots and maximum count",
+			retention: &esv1.SnapshotRetention{ExpireAfter: "10d", MaxCount: pointer.Int32(2)},
+			want:      []string{"a", "b"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			expired, err := expiredSnapshots(tt.retention, snapshots, now)
+			require.NoError(t, err)
+			require.Equal(t, tt.want, names(expired))
+		})
+	}
+}
+
+func TestParseTimeValue(t *testing.T) {
+	for value, want := range map[string]time.Duration{
+		"30d":     30 * 24 * time.Hour,
+		"12h":     12 * time.Hour,
+		"90m":     90 * time.Minute,
+		"45s":     45 * time.Second,
+		"500ms":   500 * time.Millisecond,
+		"10nanos": 10 * time.Nanosecond,
+	} {
+		got, err := ParseTimeValue(value)
+		require.NoError(t, err, value)
+		require.Equal(t, want, got, value)
+	}
+	for _, value := range []string{"", "30", "d", "-1d", "1.5h", "30 days"} {
+		_, err := ParseTimeValue(value)
+		require.Error(t, err, value)
+	}
+}
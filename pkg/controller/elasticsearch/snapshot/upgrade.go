@@ -0,0 +1,66 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package snapshot
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	esv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/elasticsearch/v1"
+	esclient "github.com/elastic/cloud-on-k8s/v2/pkg/controller/elasticsearch/client"
+	ulog "github.com/elastic/cloud-on-k8s/v2/pkg/utils/log"
+)
+
+// UpgradeSnapshotState is the state of the snapshot taken before a version upgrade.
+type UpgradeSnapshotState struct {
+	// Taken is true if the snapshot succeeded.
+	Taken bool
+	// Failed is true if the snapshot failed, in which case the upgrade must not be started.
+	Failed bool
+	// Message describes the state of the snapshot.
+	Message string
+}
+
+// UpgradeSnapshotName returns the name of the snapshot taken before upgrading the cluster to the version of its spec.
+// The generation of the spec is part of the name, so that a new snapshot is taken if the spec is updated before the
+// upgrade starts, for example to retry an upgrade after a failed snapshot.
+func UpgradeSnapshotName(es esv1.Elasticsearch) string {
+	return fmt.Sprintf("%supgrade-%s-%d", managedPrefix, strings.ToLower(es.Spec.Version), es.Generation)
+}
+
+// ReconcileUpgradeSnapshot takes a snapshot of all the indices in the repository of the upgrade snapshot of the spec,
+// unless it was already taken, and returns its state.
+func ReconcileUpgradeSnapshot(ctx context.Context, esClient esclient.SnapshotClient, es esv1.Elasticsearch) (UpgradeSnapshotState, error) {
+	repository := es.Spec.UpgradeSnapshot.Repository
+	name := UpgradeSnapshotName(es)
+	snapshot, err := esClient.GetSnapshot(ctx, repository, name)
+	if err != nil {
+		return UpgradeSnapshotState{}, err
+	}
+	if snapshot == nil {
+		ulog.FromContext(ctx).Info("Taking snapshot before version upgrade",
+			"namespace", es.Namespace, "es_name", es.Name, "repository", repository, "snapshot", name)
+		if err := esClient.CreateSnapshot(ctx, repository, name, nil); err != nil {
+			return UpgradeSnapshotState{}, fmt.Errorf("while taking snapshot %s in repository %s: %w", name, repository, err)
+		}
+		return UpgradeSnapshotState{Message: fmt.Sprintf("Taking snapshot %s in repository %s before upgrading to %s", name, repository, es.Spec.Version)}, nil
+	}
+	switch esv1.SnapshotState(snapshot.State) {
+	case esv1.SnapshotSuccess:
+		return UpgradeSnapshotState{
+			Taken:   true,
+			Message: fmt.Sprintf("Snapshot %s taken in repository %s before upgrading to %s", name, repository, es.Spec.Version),
+		}, nil
+	case esv1.SnapshotInProgress:
+		return UpgradeSnapshotState{Message: fmt.Sprintf("Taking snapshot %s in repository %s before upgrading to %s", name, repository, es.Spec.Version)}, nil
+	default:
+		return UpgradeSnapshotState{
+			Failed: true,
+			Message: fmt.Sprintf("Upgrade to %s aborted: snapshot %s in repository %s ended in state %s. "+
+				"Delete the snapshot or update the Elasticsearch resource to retry", es.Spec.Version, name, repository, snapshot.State),
+		}, nil
+	}
+}
@@ -0,0 +1,72 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package snapshot
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	esv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/elasticsearch/v1"
+	esclient "github.com/elastic/cloud-on-k8s/v2/pkg/controller/elasticsearch/client"
+)
+
+func TestReconcileUpgradeSnapshot(t *testing.T) {
+	es := esv1.Elasticsearch{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "es", Generation: 3},
+		Spec: esv1.ElasticsearchSpec{
+			Version:         "8.5.0",
+			UpgradeSnapshot: &esv1.UpgradeSnapshot{Repository: "backups"},
+		},
+	}
+	name := UpgradeSnapshotName(es)
+	require.Equal(t, "eck-upgrade-8.5.0-3", name)
+
+	tests := []struct {
+		name        string
+		snapshots   []esclient.Snapshot
+		wantCreated []string
+		wantTaken   bool
+		wantFailed  bool
+	}{
+		{
+			name:        "snapshot not taken yet",
+			snapshots:   []esclient.Snapshot{{Snapshot: "eck-upgrade-8.4.0-1", State: "SUCCESS"}},
+			wantCreated: []string{name},
+		},
+		{
+			name:      "snapshot in progress",
+			snapshots: []esclient.Snapshot{{Snapshot: name, State: "IN_PROGRESS"}},
+		},
+		{
+			name:      "snapshot taken",
+			snapshots: []esclient.Snapshot{{Snapshot: name, State: "SUCCESS"}},
+			wantTaken: true,
+		},
+		{
+			name:       "partial snapshot",
+			snapshots:  []esclient.Snapshot{{Snapshot: name, State: "PARTIAL"}},
+			wantFailed: true,
+		},
+		{
+			name:       "failed snapshot",
+			snapshots:  []esclient.Snapshot{{Snapshot: name, State: "FAILED"}},
+			wantFailed: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			esClient := &fakeESClient{snapshots: tt.snapshots}
+			state, err := ReconcileUpgradeSnapshot(context.Background(), esClient, es)
+			require.NoError(t, err)
+			require.Equal(t, tt.wantCreated, esClient.created)
+			require.Equal(t, tt.wantTaken, state.Taken)
+			require.Equal(t, tt.wantFailed, state.Failed)
+			require.NotEmpty(t, state.Message)
+		})
+	}
+}
@@ -0,0 +1,128 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package snapshot
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"go.elastic.co/apm/v2"
+	corev1 "k8s.io/api/core/v1"
+
+	"github.com/elastic/cloud-on-k8s/v2/pkg/apis/common/v1alpha1"
+	esv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/elasticsearch/v1"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/events"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/tracing"
+	esclient "github.com/elastic/cloud-on-k8s/v2/pkg/controller/elasticsearch/client"
+	ulog "github.com/elastic/cloud-on-k8s/v2/pkg/utils/log"
+)
+
+const (
+	// VerificationInterval is the interval at which the declared snapshot repositories are verified.
+	VerificationInterval = time.Hour
+	// FailedVerificationInterval is the interval at which the declared snapshot repositories are verified again after a
+	// failed verification.
+	FailedVerificationInterval = 5 * time.Minute
+
+	// noRepositoryMessage is the message of the verification once all the snapshot repositories are removed from the spec.
+	noRepositoryMessage = "No snapshot repository declared"
+)
+
+// RepositoriesVerification is the result of the verification of the declared snapshot repositories.
+type RepositoriesVerification struct {
+	// Verified is true if the repositories were verified during this reconciliation.
+	Verified bool
+	// Status is ConditionTrue if all the repositories were verified successfully, ConditionFalse otherwise.
+	Status corev1.ConditionStatus
+	// Message lists the repositories which failed the verification, along with the reason of their failure.
+	Message string
+}
+
+// VerifyRepositories verifies that all the nodes can access the snapshot repositories of the Elasticsearch spec, if the
+// last verification is older than the verification interval. It returns the result of the verification along with the
+// delay after which the repositories should be verified again. A warning event is recorded when the verification fails
+// for a different reason than the previous one.
+func VerifyRepositories(
+	ctx context.Context,
+	esClient esclient.SnapshotClient,
+	recorder *events.Recorder,
+	es esv1.Elasticsearch,
+	now time.Time,
+) (RepositoriesVerification, time.Duration, error) {
+	var previous *v1alpha1.Condition
+	if i := es.Status.Conditions.Index(esv1.SnapshotRepositoriesVerified); i >= 0 {
+		previous = &es.Status.Conditions[i]
+	}
+
+	if len(es.Spec.SnapshotRepositories) == 0 {
+		if previous == nil || previous.Message == noRepositoryMessage {
+			return RepositoriesVerification{}, 0, nil
+		}
+		// clear the result of the verification of the removed repositories
+		return RepositoriesVerification{Verified: true, Status: corev1.ConditionTrue, Message: noRepositoryMessage}, 0, nil
+	}
+
+	interval := VerificationInterval
+	if previous != nil && previous.Status != corev1.ConditionTrue {
+		interval = FailedVerificationInterval
+	}
+	if last := es.Status.SnapshotRepositoriesVerificationTime; last != nil {
+		if remaining := last.Add(interval).Sub(now); remaining > 0 {
+			return RepositoriesVerification{}, remaining, nil
+		}
+	}
+
+	span, ctx := apm.StartSpan(ctx, "verify_snapshot_repositories", tracing.SpanTypeApp)
+	defer span.End()
+
+	failures := make(map[string]string)
+	for _, repository := range es.Spec.SnapshotRepositories {
+		err := esClient.VerifySnapshotRepository(ctx, repository.Name)
+		if err == nil {
+			continue
+		}
+		apiErr := new(esclient.APIError)
+		if !errors.As(err, &apiErr) {
+			return RepositoriesVerification{}, 0, fmt.Errorf("while verifying snapshot repository %s: %w", repository.Name, err)
+		}
+		reason := apiErr.ErrorResponse.Error.Reason
+		if reason == "" {
+			reason = apiErr.Error()
+		}
+		ulog.FromContext(ctx).Info("Snapshot repository verification failed",
+			"namespace", es.Namespace, "es_name", es.Name, "repository", repository.Name, "reason", reason)
+		failures[repository.Name] = reason
+	}
+
+	if len(failures) == 0 {
+		return RepositoriesVerification{
+			Verified: true,
+			Status:   corev1.ConditionTrue,
+			Message:  fmt.Sprintf("All %d snapshot repositories verified", len(es.Spec.SnapshotRepositories)),
+		}, VerificationInterval, nil
+	}
+	names := make([]string, 0, len(failures))
+	for name := range failures {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	messages := make([]string, 0, len(names))
+	for _, name := range names {
+		messages = append(messages, fmt.Sprintf("%s: %s", name, failures[name]))
+	}
+	verification := RepositoriesVerification{
+		Verified: true,
+		Status:   corev1.ConditionFalse,
+		Message:  "Snapshot repository verification failed for " + strings.Join(messages, "; "),
+	}
+	if previous == nil || previous.Message != verification.Message {
+		recorder.AddEvent(corev1.EventTypeWarning, events.EventReasonSnapshot, verification.Message)
+	}
+	return verification, FailedVerificationInterval, nil
+}
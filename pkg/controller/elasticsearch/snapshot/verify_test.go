@@ -0,0 +1,132 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package snapshot
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/elastic/cloud-on-k8s/v2/pkg/apis/common/v1alpha1"
+	esv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/elasticsearch/v1"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/events"
+	esclient "github.com/elastic/cloud-on-k8s/v2/pkg/controller/elasticsearch/client"
+)
+
+func TestVerifyRepositories(t *testing.T) {
+	now := mustParseTime(t, "2022-10-17T10:00:00Z")
+	repositories := []esv1.SnapshotRepository{
+		{Name: "s3-backups", Type: esv1.S3SnapshotRepository},
+		{Name: "nfs", Type: esv1.FSSnapshotRepository},
+	}
+	verificationFailure := &esclient.APIError{StatusCode: 500, Status: "500 Internal Server Error"}
+	verificationFailure.ErrorResponse.Error.Reason = "[nfs] store location [/mnt/nfs] is not accessible on the node"
+	failedMessage := "Snapshot repository verification failed for nfs: [nfs] store location [/mnt/nfs] is not accessible on the node"
+	verifiedAt := func(d time.Duration) *metav1.Time {
+		verified := metav1.NewTime(now.Add(-d))
+		return &verified
+	}
+	withCondition := func(status corev1.ConditionStatus, message string) v1alpha1.Conditions {
+		return v1alpha1.Conditions{{Type: esv1.SnapshotRepositoriesVerified, Status: status, Message: message}}
+	}
+
+	tests := []struct {
+		name             string
+		repositories     []esv1.SnapshotRepository
+		status           esv1.ElasticsearchStatus
+		verifyErrs       map[string]error
+		want             RepositoriesVerification
+		wantRequeueAfter time.Duration
+		wantEvent        bool
+	}{
+		{
+			name: "no repositories",
+		},
+		{
+			name:   "all repositories removed",
+			status: esv1.ElasticsearchStatus{Conditions: withCondition(corev1.ConditionFalse, failedMessage)},
+			want:   RepositoriesVerification{Verified: true, Status: corev1.ConditionTrue, Message: noRepositoryMessage},
+		},
+		{
+			name:   "all repositories removed, verification already cleared",
+			status: esv1.ElasticsearchStatus{Conditions: withCondition(corev1.ConditionTrue, noRepositoryMessage)},
+		},
+		{
+			name:             "repositories verified for the first time",
+			repositories:     repositories,
+			want:             RepositoriesVerification{Verified: true, Status: corev1.ConditionTrue, Message: "All 2 snapshot repositories verified"},
+			wantRequeueAfter: VerificationInterval,
+		},
+		{
+			name:         "repositories recently verified",
+			repositories: repositories,
+			status: esv1.ElasticsearchStatus{
+				Conditions:                           withCondition(corev1.ConditionTrue, "All 2 snapshot repositories verified"),
+				SnapshotRepositoriesVerificationTime: verifiedAt(10 * time.Minute),
+			},
+			wantRequeueAfter: 50 * time.Minute,
+		},
+		{
+			name:         "verification failed",
+			repositories: repositories,
+			status: esv1.ElasticsearchStatus{
+				Conditions:                           withCondition(corev1.ConditionTrue, "All 2 snapshot repositories verified"),
+				SnapshotRepositoriesVerificationTime: verifiedAt(time.Hour),
+			},
+			verifyErrs:       map[string]error{"nfs": verificationFailure},
+			want:             RepositoriesVerification{Verified: true, Status: corev1.ConditionFalse, Message: failedMessage},
+			wantRequeueAfter: FailedVerificationInterval,
+			wantEvent:        true,
+		},
+		{
+			name:         "failed repositories are verified again sooner",
+			repositories: repositories,
+			status: esv1.ElasticsearchStatus{
+				Conditions:                           withCondition(corev1.ConditionFalse, failedMessage),
+				SnapshotRepositoriesVerificationTime: verifiedAt(2 * time.Minute),
+			},
+			wantRequeueAfter: 3 * time.Minute,
+		},
+		{
+			name:         "verification still failing: no new event",
+			repositories: repositories,
+			status: esv1.ElasticsearchStatus{
+				Conditions:                           withCondition(corev1.ConditionFalse, failedMessage),
+				SnapshotRepositoriesVerificationTime: verifiedAt(FailedVerificationInterval),
+			},
+			verifyErrs:       map[string]error{"nfs": verificationFailure},
+			want:             RepositoriesVerification{Verified: true, Status: corev1.ConditionFalse, Message: failedMessage},
+			wantRequeueAfter: FailedVerificationInterval,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			es := newEsWithRepositories(tt.repositories...)
+			es.Status = tt.status
+			recorder := events.NewRecorder()
+			esClient := &fakeESClient{verifyErrs: tt.verifyErrs}
+			got, requeueAfter, err := VerifyRepositories(context.Background(), esClient, recorder, es, now)
+			require.NoError(t, err)
+			require.Equal(t, tt.want, got)
+			require.Equal(t, tt.wantRequeueAfter, requeueAfter)
+			if tt.wantEvent {
+				require.Len(t, recorder.Events(), 1)
+				require.Equal(t, failedMessage, recorder.Events()[0].Message)
+			} else {
+				require.Empty(t, recorder.Events())
+			}
+		})
+	}
+
+	// unexpected errors are returned
+	esClient := &fakeESClient{verifyErrs: map[string]error{"nfs": errors.New("connection refused")}}
+	_, _, err := VerifyRepositories(context.Background(), esClient, events.NewRecorder(), newEsWithRepositories(repositories...), now)
+	require.Error(t, err)
+}
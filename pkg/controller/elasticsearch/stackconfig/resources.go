@@ -0,0 +1,340 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package stackconfig
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+
+	"go.elastic.co/apm/v2"
+	"k8s.io/apimachinery/pkg/types"
+
+	commonv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/common/v1"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/hash"
+	common "github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/settings"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/tracing"
+	esclient "github.com/elastic/cloud-on-k8s/v2/pkg/controller/elasticsearch/client"
+	ulog "github.com/elastic/cloud-on-k8s/v2/pkg/utils/log"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/utils/set"
+)
+
+// Kind is a kind of Elasticsearch resources applied through the Elasticsearch API.
+type Kind string
+
+const (
+	ClusterSettingsKind      Kind = "cluster_settings"
+	SnapshotRepositoriesKind Kind = "snapshot_repositories"
+	RoleMappingsKind         Kind = "security_role_mappings"
+	ILMPoliciesKind          Kind = "index_lifecycle_policies"
+	IngestPipelinesKind      Kind = "ingest_pipelines"
+	ComponentTemplatesKind   Kind = "component_templates"
+	IndexTemplatesKind       Kind = "composable_index_templates"
+	LegacyIndexTemplatesKind Kind = "legacy_index_templates"
+)
+
+// Resources maps the kinds of resources to the definitions of the resources of that kind, indexed by name. Cluster
+// settings are indexed by their flattened key.
+type Resources map[Kind]map[string]interface{}
+
+func (r Resources) set(kind Kind, name string, definition interface{}) {
+	if r[kind] == nil {
+		r[kind] = map[string]interface{}{}
+	}
+	r[kind][name] = definition
+}
+
+func (r Resources) isEmpty() bool {
+	for _, resources := range r {
+		if len(resources) > 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// Applied maps the kinds of resources to the hash of the definition with which the resources of that kind were last
+// applied, indexed by name.
+type Applied map[Kind]map[string]string
+
+func (a Applied) set(kind Kind, name string, definitionHash string) {
+	if a[kind] == nil {
+		a[kind] = map[string]string{}
+	}
+	a[kind][name] = definitionHash
+}
+
+func (a Applied) remove(kind Kind, name string) {
+	delete(a[kind], name)
+	if len(a[kind]) == 0 {
+		delete(a, kind)
+	}
+}
+
+// Conflict is a declared resource which already exists in Elasticsearch, and was not applied by the operator.
+type Conflict struct {
+	Kind Kind
+	Name string
+}
+
+func (c Conflict) String() string {
+	return fmt.Sprintf("%s %s", c.Kind, c.Name)
+}
+
+// kindClient updates and deletes the resources of a kind through the Elasticsearch API.
+type kindClient struct {
+	kind   Kind
+	update func(ctx context.Context, c esclient.Client, name string, definition interface{}) error
+	delete func(ctx context.Context, c esclient.Client, name string) error
+	// existing returns the names of the resources of the kind which exist in Elasticsearch, to detect conflicts with
+	// the resources not applied by the operator. Conflicts are not detected if nil.
+	existing func(ctx context.Context, c esclient.Client) (set.StringSet, error)
+}
+
+// kinds are the kinds of resources, in the order in which they are updated: resources may reference resources of the
+// previous kinds, such as index templates composed of component templates and referring to ILM policies and ingest
+// pipelines. Resources are deleted in the reverse order.
+var kinds = []kindClient{
+	{
+		kind: ClusterSettingsKind,
+		update: func(ctx context.Context, c esclient.Client, name string, definition interface{}) error {
+			return c.UpdateClusterSettings(ctx, esclient.ClusterSettings{PersistentSettings: map[string]interface{}{name: definition}})
+		},
+		delete: func(ctx context.Context, c esclient.Client, name string) error {
+			return c.UpdateClusterSettings(ctx, esclient.ClusterSettings{PersistentSettings: map[string]interface{}{name: nil}})
+		},
+	},
+	{
+		kind: SnapshotRepositoriesKind,
+		update: func(ctx context.Context, c esclient.Client, name string, definition interface{}) error {
+			var repository esclient.SnapshotRepository
+			if err := convert(definition, &repository); err != nil {
+				return err
+			}
+			return c.UpdateSnapshotRepository(ctx, name, repository)
+		},
+		delete: func(ctx context.Context, c esclient.Client, name string) error {
+			return c.DeleteSnapshotRepository(ctx, name)
+		},
+	},
+	{
+		kind: RoleMappingsKind,
+		update: func(ctx context.Context, c esclient.Client, name string, definition interface{}) error {
+			return c.UpdateRoleMapping(ctx, name, asObject(definition))
+		},
+		delete: func(ctx context.Context, c esclient.Client, name string) error {
+			return c.DeleteRoleMapping(ctx, name)
+		},
+	},
+	{
+		kind: ILMPoliciesKind,
+		update: func(ctx context.Context, c esclient.Client, name string, definition interface{}) error {
+			return c.UpdateILMPolicy(ctx, name, asObject(definition))
+		},
+		delete: func(ctx context.Context, c esclient.Client, name string) error {
+			return c.DeleteILMPolicy(ctx, name)
+		},
+		existing: func(ctx context.Context, c esclient.Client) (set.StringSet, error) {
+			policies, err := c.GetILMPolicies(ctx)
+			names := set.Make()
+			for name := range policies {
+				names.Add(name)
+			}
+			return names, err
+		},
+	},
+	{
+		kind: IngestPipelinesKind,
+		update: func(ctx context.Context, c esclient.Client, name string, definition interface{}) error {
+			return c.UpdateIngestPipeline(ctx, name, asObject(definition))
+		},
+		delete: func(ctx context.Context, c esclient.Client, name string) error {
+			return c.DeleteIngestPipeline(ctx, name)
+		},
+		existing: func(ctx context.Context, c esclient.Client) (set.StringSet, error) {
+			pipelines, err := c.GetIngestPipelines(ctx)
+			return namesOf(pipelines), err
+		},
+	},
+	{
+		kind: ComponentTemplatesKind,
+		update: func(ctx context.Context, c esclient.Client, name string, definition interface{}) error {
+			return c.UpdateComponentTemplate(ctx, name, asObject(definition))
+		},
+		delete: func(ctx context.Context, c esclient.Client, name string) error {
+			return c.DeleteComponentTemplate(ctx, name)
+		},
+		existing: func(ctx context.Context, c esclient.Client) (set.StringSet, error) {
+			templates, err := c.GetComponentTemplates(ctx)
+			return namesOf(templates), err
+		},
+	},
+	{
+		kind: IndexTemplatesKind,
+		update: func(ctx context.Context, c esclient.Client, name string, definition interface{}) error {
+			return c.UpdateIndexTemplate(ctx, name, asObject(definition))
+		},
+		delete: func(ctx context.Context, c esclient.Client, name string) error {
+			return c.DeleteIndexTemplate(ctx, name)
+		},
+		existing: func(ctx context.Context, c esclient.Client) (set.StringSet, error) {
+			templates, err := c.GetIndexTemplates(ctx)
+			return namesOf(templates), err
+		},
+	},
+	{
+		kind: LegacyIndexTemplatesKind,
+		update: func(ctx context.Context, c esclient.Client, name string, definition interface{}) error {
+			return c.UpdateLegacyIndexTemplate(ctx, name, asObject(definition))
+		},
+		delete: func(ctx context.Context, c esclient.Client, name string) error {
+			return c.DeleteLegacyIndexTemplate(ctx, name)
+		},
+		existing: func(ctx context.Context, c esclient.Client) (set.StringSet, error) {
+			templates, err := c.GetLegacyIndexTemplates(ctx)
+			return namesOf(templates), err
+		},
+	},
+}
+
+// Apply applies the declared resources through the Elasticsearch API, and deletes the previously applied resources
+// which are not declared anymore. A resource is only updated if its definition changed since it was last applied, so
+// that it is not updated at every reconciliation. Declared resources which already exist in Elasticsearch without
+// having been applied are left untouched, and returned as conflicts. The returned resources are the ones applied,
+// including when an error is returned, so that the resources applied before the error are tracked.
+func Apply(
+	ctx context.Context,
+	esClient esclient.Client,
+	es types.NamespacedName,
+	declared Resources,
+	applied Applied,
+) (Applied, []Conflict, error) {
+	span, ctx := apm.StartSpan(ctx, "apply_stack_config", tracing.SpanTypeApp)
+	defer span.End()
+	log := ulog.FromContext(ctx)
+
+	result := make(Applied, len(applied))
+	for kind, resources := range applied {
+		for name, definitionHash := range resources {
+			result.set(kind, name, definitionHash)
+		}
+	}
+	var conflicts []Conflict
+	for _, k := range kinds {
+		var existing set.StringSet
+		for _, name := range sortedNames(declared[k.kind]) {
+			definition := declared[k.kind][name]
+			definitionHash := hash.HashObject(definition)
+			currentHash, isApplied := result[k.kind][name]
+			if currentHash == definitionHash {
+				continue
+			}
+			if !isApplied && k.existing != nil {
+				if existing == nil {
+					var err error
+					if existing, err = k.existing(ctx, esClient); err != nil {
+						return result, conflicts, fmt.Errorf("while getting %s: %w", k.kind, err)
+					}
+				}
+				if existing.Has(name) {
+					conflicts = append(conflicts, Conflict{Kind: k.kind, Name: name})
+					continue
+				}
+			}
+			log.Info("Updating Elasticsearch resource", "namespace", es.Namespace, "es_name", es.Name, "kind", k.kind, "name", name)
+			if err := k.update(ctx, esClient, name, definition); err != nil {
+				return result, conflicts, fmt.Errorf("while updating %s %s: %w", k.kind, name, err)
+			}
+			result.set(k.kind, name, definitionHash)
+		}
+	}
+	for i := len(kinds) - 1; i >= 0; i-- {
+		k := kinds[i]
+		for _, name := range result.sortedNames(k.kind) {
+			if _, isDeclared := declared[k.kind][name]; isDeclared {
+				continue
+			}
+			log.Info("Deleting Elasticsearch resource", "namespace", es.Namespace, "es_name", es.Name, "kind", k.kind, "name", name)
+			if err := k.delete(ctx, esClient, name); err != nil && !esclient.IsNotFound(err) {
+				return result, conflicts, fmt.Errorf("while deleting %s %s: %w", k.kind, name, err)
+			}
+			result.remove(k.kind, name)
+		}
+	}
+	return result, conflicts, nil
+}
+
+// NamedResources returns the resources of the given configuration, indexed by name. The definition of each resource
+// must be an object.
+func NamedResources(cfg *commonv1.Config) (map[string]interface{}, error) {
+	if cfg == nil || len(cfg.Data) == 0 {
+		return nil, nil
+	}
+	resources := make(map[string]interface{}, len(cfg.Data))
+	for name, definition := range cfg.Data {
+		if _, isObject := definition.(map[string]interface{}); !isObject {
+			return nil, fmt.Errorf("the definition of %s must be an object", name)
+		}
+		resources[name] = definition
+	}
+	return resources, nil
+}
+
+// ClusterSettings returns the cluster settings of the given configuration, indexed by their flattened key.
+func ClusterSettings(cfg *commonv1.Config) (map[string]interface{}, error) {
+	if cfg == nil || len(cfg.Data) == 0 {
+		return nil, nil
+	}
+	canonical, err := common.NewCanonicalConfigFrom(cfg.Data)
+	if err != nil {
+		return nil, err
+	}
+	return canonical.Flatten()
+}
+
+// asObject returns the given definition as an object, definitions are validated by NamedResources.
+func asObject(definition interface{}) map[string]interface{} {
+	object, _ := definition.(map[string]interface{})
+	return object
+}
+
+// convert converts the given definition to the given type through its JSON representation.
+func convert(definition interface{}, out interface{}) error {
+	bytes, err := json.Marshal(definition)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(bytes, out)
+}
+
+// namesOf returns the names of the given resources returned by Elasticsearch.
+func namesOf(resources map[string]map[string]interface{}) set.StringSet {
+	names := set.Make()
+	for name := range resources {
+		names.Add(name)
+	}
+	return names
+}
+
+// sortedNames returns the names of the given resources, in alphabetical order.
+func sortedNames(resources map[string]interface{}) []string {
+	names := make([]string, 0, len(resources))
+	for name := range resources {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// sortedNames returns the names of the applied resources of the given kind, in alphabetical order.
+func (a Applied) sortedNames(kind Kind) []string {
+	names := make([]string, 0, len(a[kind]))
+	for name := range a[kind] {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
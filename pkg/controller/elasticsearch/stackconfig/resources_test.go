@@ -0,0 +1,155 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package stackconfig
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	commonv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/common/v1"
+	esv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/elasticsearch/v1"
+	controllerscheme "github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/scheme"
+	esclient "github.com/elastic/cloud-on-k8s/v2/pkg/controller/elasticsearch/client"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/utils/k8s"
+)
+
+type fakeClient struct {
+	esclient.Client
+	calls []string
+}
+
+func (f *fakeClient) UpdateClusterSettings(_ context.Context, settings esclient.ClusterSettings) error {
+	for name, value := range settings.PersistentSettings {
+		if value == nil {
+			f.calls = append(f.calls, "reset setting "+name)
+			continue
+		}
+		f.calls = append(f.calls, "update setting "+name)
+	}
+	return nil
+}
+
+func (f *fakeClient) GetILMPolicies(_ context.Context) (esclient.ILMPolicies, error) {
+	return esclient.ILMPolicies{}, nil
+}
+
+func (f *fakeClient) UpdateILMPolicy(_ context.Context, name string, _ map[string]interface{}) error {
+	f.calls = append(f.calls, "update ilm policy "+name)
+	return nil
+}
+
+func (f *fakeClient) DeleteILMPolicy(_ context.Context, name string) error {
+	f.calls = append(f.calls, "delete ilm policy "+name)
+	return nil
+}
+
+func (f *fakeClient) GetIndexTemplates(_ context.Context) (esclient.IndexTemplates, error) {
+	return esclient.IndexTemplates{}, nil
+}
+
+func (f *fakeClient) UpdateIndexTemplate(_ context.Context, name string, _ map[string]interface{}) error {
+	f.calls = append(f.calls, "update index template "+name)
+	return nil
+}
+
+func (f *fakeClient) DeleteIndexTemplate(_ context.Context, name string) error {
+	f.calls = append(f.calls, "delete index template "+name)
+	return nil
+}
+
+func TestApply(t *testing.T) {
+	es := types.NamespacedName{Namespace: "ns", Name: "es"}
+	esClient := &fakeClient{}
+	apply := func(declared Resources, applied Applied) Applied {
+		t.Helper()
+		esClient.calls = nil
+		result, conflicts, err := Apply(context.Background(), esClient, es, declared, applied)
+		require.NoError(t, err)
+		require.Empty(t, conflicts)
+		return result
+	}
+	declared := Resources{
+		ClusterSettingsKind: {"indices.recovery.max_bytes_per_sec": "100mb"},
+		ILMPoliciesKind:     {"logs": map[string]interface{}{"phases": map[string]interface{}{}}},
+		IndexTemplatesKind:  {"logs": map[string]interface{}{"index_patterns": []interface{}{"logs-*"}}},
+	}
+
+	// resources are updated in order, templates last as they may refer to the other resources
+	applied := apply(declared, Applied{})
+	require.Equal(t, []string{
+		"update setting indices.recovery.max_bytes_per_sec",
+		"update ilm policy logs",
+		"update index template logs",
+	}, esClient.calls)
+
+	// nothing to do if the resources did not change
+	applied = apply(declared, applied)
+	require.Empty(t, esClient.calls)
+
+	// only the changed resources are updated
+	declared[ILMPoliciesKind]["logs"] = map[string]interface{}{"phases": map[string]interface{}{"hot": map[string]interface{}{}}}
+	applied = apply(declared, applied)
+	require.Equal(t, []string{"update ilm policy logs"}, esClient.calls)
+
+	// resources which are not declared anymore are deleted, templates first
+	applied = apply(Resources{ClusterSettingsKind: declared[ClusterSettingsKind]}, applied)
+	require.Equal(t, []string{"delete index template logs", "delete ilm policy logs"}, esClient.calls)
+	require.Equal(t, Applied{ClusterSettingsKind: applied[ClusterSettingsKind]}, applied)
+
+	// settings are reset once removed
+	applied = apply(nil, applied)
+	require.Equal(t, []string{"reset setting indices.recovery.max_bytes_per_sec"}, esClient.calls)
+	require.Empty(t, applied)
+}
+
+func TestNamedResources(t *testing.T) {
+	resources, err := NamedResources(&commonv1.Config{Data: map[string]interface{}{
+		"logs": map[string]interface{}{"phases": map[string]interface{}{}},
+	}})
+	require.NoError(t, err)
+	require.Equal(t, map[string]interface{}{"logs": map[string]interface{}{"phases": map[string]interface{}{}}}, resources)
+
+	_, err = NamedResources(&commonv1.Config{Data: map[string]interface{}{"logs": "invalid"}})
+	require.Error(t, err)
+}
+
+func TestClusterSettings(t *testing.T) {
+	settings, err := ClusterSettings(&commonv1.Config{Data: map[string]interface{}{
+		"indices": map[string]interface{}{"recovery": map[string]interface{}{"max_bytes_per_sec": "100mb"}},
+	}})
+	require.NoError(t, err)
+	require.Equal(t, map[string]interface{}{"indices.recovery.max_bytes_per_sec": "100mb"}, settings)
+}
+
+func TestReconcileApplied(t *testing.T) {
+	controllerscheme.SetupScheme()
+	es := esv1.Elasticsearch{ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "es"}}
+	c := k8s.NewFakeClient(&es)
+	key := types.NamespacedName{Namespace: "ns", Name: esv1.StackConfigPolicySecret("es")}
+	applied := Applied{ILMPoliciesKind: {"logs": "1234"}}
+
+	// applied resources are tracked in a Secret
+	require.NoError(t, ReconcileApplied(context.Background(), c, es, key, map[string]string{"policy": "p"}, applied))
+	var secret corev1.Secret
+	require.NoError(t, c.Get(context.Background(), key, &secret))
+	require.Equal(t, "p", secret.Labels["policy"])
+	tracked, err := GetApplied(context.Background(), c, key)
+	require.NoError(t, err)
+	require.Equal(t, applied, tracked)
+
+	// the Secret is deleted once no resource is applied
+	require.NoError(t, ReconcileApplied(context.Background(), c, es, key, nil, Applied{}))
+	err = c.Get(context.Background(), key, &corev1.Secret{})
+	require.True(t, apierrors.IsNotFound(err))
+	tracked, err = GetApplied(context.Background(), c, key)
+	require.NoError(t, err)
+	require.Empty(t, tracked)
+}
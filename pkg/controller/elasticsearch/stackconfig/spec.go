@@ -0,0 +1,314 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package stackconfig
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	commonv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/common/v1"
+	esv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/elasticsearch/v1"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/version"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/watches"
+	esclient "github.com/elastic/cloud-on-k8s/v2/pkg/controller/elasticsearch/client"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/utils/k8s"
+	ulog "github.com/elastic/cloud-on-k8s/v2/pkg/utils/log"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/utils/stringsutil"
+)
+
+// DriftCheckInterval is the interval at which the cluster settings declared in the Elasticsearch spec are compared with
+// their current value, to detect the settings changed through the Elasticsearch API.
+const DriftCheckInterval = 5 * time.Minute
+
+// ConfigMapsWatchName returns the watch registered for the ConfigMaps holding the definition of the resources declared
+// in the Elasticsearch spec.
+func ConfigMapsWatchName(es types.NamespacedName) string {
+	return fmt.Sprintf("%s-%s-managed-resources-configmaps", es.Namespace, es.Name)
+}
+
+// SpecResult is the result of the reconciliation of the resources declared in the Elasticsearch spec.
+type SpecResult struct {
+	// Conflicts are the declared resources left untouched as they already exist in Elasticsearch.
+	Conflicts []Conflict
+	// DriftedSettings are the keys of the declared cluster settings which were changed through the Elasticsearch API.
+	DriftedSettings []string
+	// MissingDataTiers are the data tiers the declared ILM policies move indices to, but which no NodeSet provides.
+	MissingDataTiers []esv1.DataTier
+}
+
+// ReconcileSpec applies the resources declared in the Elasticsearch spec through the Elasticsearch API, and deletes the
+// resources removed from the spec. The applied resources are tracked in a Secret owned by the Elasticsearch resource,
+// distinct from the one tracking the resources applied by a StackConfigPolicy. Declared resources which already exist
+// in Elasticsearch and were not applied from the spec are returned as conflicts. Declared cluster settings changed
+// through the Elasticsearch API are returned as drifted, and reverted depending on the conflict policy.
+func ReconcileSpec(
+	ctx context.Context,
+	c k8s.Client,
+	esClient esclient.Client,
+	es esv1.Elasticsearch,
+	watched watches.DynamicWatches,
+) (SpecResult, error) {
+	esKey := k8s.ExtractNamespacedName(&es)
+	if err := watches.WatchUserProvidedConfigMaps(esKey, watched, ConfigMapsWatchName(esKey), configMapNames(es)); err != nil {
+		return SpecResult{}, err
+	}
+	key := types.NamespacedName{Namespace: es.Namespace, Name: esv1.ManagedResourcesSecret(es.Name)}
+	applied, err := GetApplied(ctx, c, key)
+	if err != nil {
+		return SpecResult{}, err
+	}
+	declared, err := specResources(ctx, c, es)
+	if err != nil {
+		return SpecResult{}, err
+	}
+	if len(applied) == 0 && declared.isEmpty() {
+		return SpecResult{}, nil
+	}
+	var result SpecResult
+	if result.MissingDataTiers, err = missingDataTiers(es, declared[ILMPoliciesKind]); err != nil {
+		return result, err
+	}
+	applied, result.Conflicts, err = Apply(ctx, esClient, esKey, declared, applied)
+	if err := ReconcileApplied(ctx, c, es, key, nil, applied); err != nil {
+		return result, err
+	}
+	if err != nil {
+		return result, err
+	}
+	// the cluster settings also applied by a StackConfigPolicy are managed by the policy
+	policyApplied, err := GetApplied(ctx, c, types.NamespacedName{Namespace: es.Namespace, Name: esv1.StackConfigPolicySecret(es.Name)})
+	if err != nil {
+		return result, err
+	}
+	result.DriftedSettings, err = reconcileDrift(ctx, esClient, es, declared[ClusterSettingsKind], applied, policyApplied)
+	return result, err
+}
+
+// reconcileDrift compares the declared cluster settings applied from the spec with their current value, and reverts
+// the settings changed through the Elasticsearch API unless the conflict policy is to report them. The keys of the
+// changed settings are returned. Settings also applied by a StackConfigPolicy are left to the policy.
+func reconcileDrift(
+	ctx context.Context,
+	esClient esclient.Client,
+	es esv1.Elasticsearch,
+	declared map[string]interface{},
+	applied Applied,
+	policyApplied Applied,
+) ([]string, error) {
+	if len(declared) == 0 || es.Spec.ClusterSettings == nil {
+		return nil, nil
+	}
+	current, err := esClient.GetClusterSettings(ctx)
+	if err != nil {
+		return nil, err
+	}
+	var keys []string
+	drifted := map[string]interface{}{}
+	for _, key := range applied.sortedNames(ClusterSettingsKind) {
+		value, isDeclared := declared[key]
+		if !isDeclared || settingValue(value) == settingValue(current.PersistentSettings[key]) {
+			continue
+		}
+		if _, isPolicySetting := policyApplied[ClusterSettingsKind][key]; isPolicySetting {
+			continue
+		}
+		keys = append(keys, key)
+		drifted[key] = value
+	}
+	if len(drifted) == 0 || es.Spec.ClusterSettings.ConflictPolicyOrDefault() == esv1.ReportClusterSettingsConflictPolicy {
+		return keys, nil
+	}
+	ulog.FromContext(ctx).Info("Reverting cluster settings changed through the Elasticsearch API",
+		"namespace", es.Namespace, "es_name", es.Name, "settings", keys)
+	return keys, esClient.UpdateClusterSettings(ctx, esclient.ClusterSettings{PersistentSettings: drifted})
+}
+
+// settingValue returns the string representation of a cluster setting value, to compare the declared values with the
+// values returned by Elasticsearch as strings.
+func settingValue(value interface{}) string {
+	switch v := value.(type) {
+	case nil:
+		return ""
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	case []interface{}:
+		values := make([]string, len(v))
+		for i := range v {
+			values[i] = settingValue(v[i])
+		}
+		return "[" + strings.Join(values, ",") + "]"
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
+// configMapNames returns the names of the ConfigMaps referenced in the Elasticsearch spec.
+func configMapNames(es esv1.Elasticsearch) []string {
+	var names []string
+	for _, pipeline := range es.Spec.IngestPipelines {
+		if pipeline.ConfigMapName != "" {
+			names = append(names, pipeline.ConfigMapName)
+		}
+	}
+	for _, template := range es.Spec.ComponentTemplates {
+		if template.ConfigMapName != "" {
+			names = append(names, template.ConfigMapName)
+		}
+	}
+	for _, template := range es.Spec.IndexTemplates {
+		if template.ConfigMapName != "" {
+			names = append(names, template.ConfigMapName)
+		}
+	}
+	for _, policy := range es.Spec.ILMPolicies {
+		if policy.ConfigMapName != "" {
+			names = append(names, policy.ConfigMapName)
+		}
+	}
+	return names
+}
+
+// specResources returns the resources declared in the Elasticsearch spec.
+func specResources(ctx context.Context, c k8s.Client, es esv1.Elasticsearch) (Resources, error) {
+	resources := Resources{}
+	if es.Spec.ClusterSettings != nil {
+		settings, err := ClusterSettings(es.Spec.ClusterSettings.Persistent)
+		if err != nil {
+			return nil, fmt.Errorf("cluster settings: %w", err)
+		}
+		for key, value := range settings {
+			resources.set(ClusterSettingsKind, key, value)
+		}
+	}
+	for _, pipeline := range es.Spec.IngestPipelines {
+		definition, err := specDefinition(ctx, c, es.Namespace, pipeline.Definition, pipeline.ConfigMapName, esv1.IngestPipelineConfigMapKey)
+		if err != nil {
+			return nil, fmt.Errorf("ingest pipeline %s: %w", pipeline.Name, err)
+		}
+		resources.set(IngestPipelinesKind, pipeline.Name, definition)
+	}
+	for _, template := range es.Spec.ComponentTemplates {
+		definition, err := specDefinition(ctx, c, es.Namespace, template.Definition, template.ConfigMapName, esv1.ComponentTemplateConfigMapKey)
+		if err != nil {
+			return nil, fmt.Errorf("component template %s: %w", template.Name, err)
+		}
+		resources.set(ComponentTemplatesKind, template.Name, definition)
+	}
+	for _, template := range es.Spec.IndexTemplates {
+		definition, err := specDefinition(ctx, c, es.Namespace, template.Definition, template.ConfigMapName, esv1.IndexTemplateConfigMapKey)
+		if err != nil {
+			return nil, fmt.Errorf("index template %s: %w", template.Name, err)
+		}
+		kind := IndexTemplatesKind
+		if template.TypeOrDefault() == esv1.LegacyIndexTemplateType {
+			kind = LegacyIndexTemplatesKind
+		}
+		resources.set(kind, template.Name, definition)
+	}
+	for _, policy := range es.Spec.ILMPolicies {
+		definition, err := specDefinition(ctx, c, es.Namespace, policy.Definition, policy.ConfigMapName, esv1.ILMPolicyConfigMapKey)
+		if err != nil {
+			return nil, fmt.Errorf("ILM policy %s: %w", policy.Name, err)
+		}
+		resources.set(ILMPoliciesKind, policy.Name, definition)
+	}
+	return resources, nil
+}
+
+// missingDataTiers returns the data tiers the given ILM policies move indices to, either implicitly through their warm,
+// cold and frozen phases or explicitly through allocate actions, without any NodeSet providing nodes of that tier.
+func missingDataTiers(es esv1.Elasticsearch, policies map[string]interface{}) ([]esv1.DataTier, error) {
+	if len(policies) == 0 {
+		return nil, nil
+	}
+	v, err := version.Parse(es.Spec.Version)
+	if err != nil {
+		return nil, err
+	}
+	definitions := make(esclient.ILMPolicies, len(policies))
+	for _, name := range sortedNames(policies) {
+		var definition esclient.ILMPolicyDefinition
+		if err := convert(map[string]interface{}{"policy": policies[name]}, &definition); err != nil {
+			return nil, fmt.Errorf("ILM policy %s: %w", name, err)
+		}
+		definitions[name] = definition
+	}
+	targeted := definitions.DataTiers(esv1.DataTierAttr)
+	var missing []esv1.DataTier
+	for _, tier := range esv1.DataTiers {
+		if !stringsutil.StringInSlice(string(tier), targeted) {
+			continue
+		}
+		if !hasDataTier(es.Spec.NodeSets, v, tier) {
+			missing = append(missing, tier)
+		}
+	}
+	return missing, nil
+}
+
+// hasDataTier returns true if at least one of the given NodeSets provides nodes of the given data tier, either through
+// its data tier or through the roles of its nodes.
+func hasDataTier(nodeSets []esv1.NodeSet, v version.Version, tier esv1.DataTier) bool {
+	for _, nodeSet := range nodeSets {
+		if nodeSet.Count == 0 {
+			continue
+		}
+		if nodeSet.DataTier != "" {
+			if nodeSet.DataTier == tier {
+				return true
+			}
+			continue
+		}
+		var cfg esv1.ElasticsearchSettings
+		if err := esv1.UnpackConfig(nodeSet.Config, v, &cfg); err != nil {
+			// reported by the validation of the NodeSets
+			continue
+		}
+		if cfg.Node.HasRole(tier.Role()) {
+			return true
+		}
+	}
+	return false
+}
+
+// specDefinition returns the definition of a resource declared in the Elasticsearch spec, either inlined or held in the
+// given entry of a ConfigMap.
+func specDefinition(
+	ctx context.Context,
+	c k8s.Client,
+	namespace string,
+	inlined *commonv1.Config,
+	configMapName string,
+	key string,
+) (map[string]interface{}, error) {
+	if inlined != nil {
+		return inlined.Data, nil
+	}
+	return configMapDefinition(ctx, c, namespace, configMapName, key)
+}
+
+// configMapDefinition returns the JSON definition held in the given entry of a ConfigMap.
+func configMapDefinition(ctx context.Context, c k8s.Client, namespace, name, key string) (map[string]interface{}, error) {
+	var configMap corev1.ConfigMap
+	if err := c.Get(ctx, types.NamespacedName{Namespace: namespace, Name: name}, &configMap); err != nil {
+		return nil, err
+	}
+	data, exists := configMap.Data[key]
+	if !exists {
+		return nil, fmt.Errorf("%s not found in ConfigMap %s/%s", key, namespace, name)
+	}
+	var definition map[string]interface{}
+	if err := json.Unmarshal([]byte(data), &definition); err != nil {
+		return nil, fmt.Errorf("invalid %s in ConfigMap %s/%s: %w", key, namespace, name, err)
+	}
+	return definition, nil
+}
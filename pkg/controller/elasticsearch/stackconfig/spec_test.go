@@ -0,0 +1,351 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package stackconfig
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	commonv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/common/v1"
+	esv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/elasticsearch/v1"
+	controllerscheme "github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/scheme"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/watches"
+	esclient "github.com/elastic/cloud-on-k8s/v2/pkg/controller/elasticsearch/client"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/utils/k8s"
+)
+
+type fakeIngestClient struct {
+	esclient.Client
+	pipelines esclient.IngestPipelines
+}
+
+func (f *fakeIngestClient) GetIngestPipelines(_ context.Context) (esclient.IngestPipelines, error) {
+	return f.pipelines, nil
+}
+
+func (f *fakeIngestClient) UpdateIngestPipeline(_ context.Context, name string, pipeline map[string]interface{}) error {
+	f.pipelines[name] = pipeline
+	return nil
+}
+
+func (f *fakeIngestClient) DeleteIngestPipeline(_ context.Context, name string) error {
+	delete(f.pipelines, name)
+	return nil
+}
+
+func TestReconcileSpec_IngestPipelines(t *testing.T) {
+	controllerscheme.SetupScheme()
+	es := esv1.Elasticsearch{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "es"},
+		Spec: esv1.ElasticsearchSpec{IngestPipelines: []esv1.IngestPipeline{
+			{Name: "inline", Definition: &commonv1.Config{Data: map[string]interface{}{"description": "inline"}}},
+			{Name: "from-configmap", ConfigMapName: "pipelines"},
+			{Name: "external", Definition: &commonv1.Config{Data: map[string]interface{}{"description": "declared"}}},
+		}},
+	}
+	configMap := corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "pipelines"},
+		Data:       map[string]string{esv1.IngestPipelineConfigMapKey: `{"description": "from ConfigMap"}`},
+	}
+	c := k8s.NewFakeClient(&es, &configMap)
+	esClient := &fakeIngestClient{pipelines: esclient.IngestPipelines{
+		// created through the API by the user, not managed by the operator
+		"external": {"description": "external"},
+	}}
+	watched := watches.NewDynamicWatches()
+	reconcile := func(es esv1.Elasticsearch) []Conflict {
+		t.Helper()
+		result, err := ReconcileSpec(context.Background(), c, esClient, es, watched)
+		require.NoError(t, err)
+		return result.Conflicts
+	}
+
+	// the pipelines are created, except the one created outside the operator which is reported as a conflict
+	conflicts := reconcile(es)
+	require.Equal(t, []Conflict{{Kind: IngestPipelinesKind, Name: "external"}}, conflicts)
+	require.Equal(t, map[string]interface{}{"description": "inline"}, esClient.pipelines["inline"])
+	require.Equal(t, map[string]interface{}{"description": "from ConfigMap"}, esClient.pipelines["from-configmap"])
+	require.Equal(t, map[string]interface{}{"description": "external"}, esClient.pipelines["external"])
+	require.Len(t, watched.ConfigMaps.Registrations(), 1)
+
+	// the pipeline is updated when the ConfigMap changes
+	configMap.Data[esv1.IngestPipelineConfigMapKey] = `{"description": "updated"}`
+	require.NoError(t, c.Update(context.Background(), &configMap))
+	reconcile(es)
+	require.Equal(t, map[string]interface{}{"description": "updated"}, esClient.pipelines["from-configmap"])
+
+	// the pipelines are deleted once removed from the spec, the pipeline created outside the operator is left untouched
+	withoutPipelines := *es.DeepCopy()
+	withoutPipelines.Spec.IngestPipelines = nil
+	require.Empty(t, reconcile(withoutPipelines))
+	require.Equal(t, esclient.IngestPipelines{"external": {"description": "external"}}, esClient.pipelines)
+	require.Empty(t, watched.ConfigMaps.Registrations())
+	err := c.Get(context.Background(), types.NamespacedName{Namespace: "ns", Name: esv1.ManagedResourcesSecret("es")}, &corev1.Secret{})
+	require.True(t, apierrors.IsNotFound(err))
+}
+
+func TestReconcileSpec_missingConfigMap(t *testing.T) {
+	controllerscheme.SetupScheme()
+	es := esv1.Elasticsearch{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "es"},
+		Spec: esv1.ElasticsearchSpec{IngestPipelines: []esv1.IngestPipeline{
+			{Name: "from-configmap", ConfigMapName: "pipelines"},
+		}},
+	}
+	esClient := &fakeIngestClient{pipelines: esclient.IngestPipelines{}}
+	_, err := ReconcileSpec(context.Background(), k8s.NewFakeClient(&es), esClient, es, watches.NewDynamicWatches())
+	require.Error(t, err)
+	require.Empty(t, esClient.pipelines)
+}
+
+type fakeSettingsClient struct {
+	esclient.Client
+	settings map[string]interface{}
+}
+
+func (f *fakeSettingsClient) GetClusterSettings(_ context.Context) (esclient.ClusterSettings, error) {
+	// Elasticsearch returns the values as strings
+	settings := make(map[string]interface{}, len(f.settings))
+	for key, value := range f.settings {
+		settings[key] = settingValue(value)
+	}
+	return esclient.ClusterSettings{PersistentSettings: settings}, nil
+}
+
+func (f *fakeSettingsClient) UpdateClusterSettings(_ context.Context, settings esclient.ClusterSettings) error {
+	for key, value := range settings.PersistentSettings {
+		if value == nil {
+			delete(f.settings, key)
+			continue
+		}
+		f.settings[key] = value
+	}
+	return nil
+}
+
+func TestReconcileSpec_ClusterSettings(t *testing.T) {
+	controllerscheme.SetupScheme()
+	es := esv1.Elasticsearch{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "es"},
+		Spec: esv1.ElasticsearchSpec{ClusterSettings: &esv1.ManagedClusterSettings{
+			Persistent: &commonv1.Config{Data: map[string]interface{}{
+				"indices.recovery.max_bytes_per_sec": "100mb",
+				"search":                             map[string]interface{}{"max_buckets": float64(1000000)},
+			}},
+		}},
+	}
+	c := k8s.NewFakeClient(&es)
+	esClient := &fakeSettingsClient{settings: map[string]interface{}{}}
+	reconcile := func(es esv1.Elasticsearch) SpecResult {
+		t.Helper()
+		result, err := ReconcileSpec(context.Background(), c, esClient, es, watches.NewDynamicWatches())
+		require.NoError(t, err)
+		return result
+	}
+
+	// the settings are applied, without drift
+	require.Equal(t, SpecResult{}, reconcile(es))
+	require.Len(t, esClient.settings, 2)
+	require.Equal(t, "100mb", esClient.settings["indices.recovery.max_bytes_per_sec"])
+	require.Equal(t, "1000000", settingValue(esClient.settings["search.max_buckets"]))
+
+	// settings changed through the API are reverted by default
+	esClient.settings["indices.recovery.max_bytes_per_sec"] = "50mb"
+	require.Equal(t, SpecResult{DriftedSettings: []string{"indices.recovery.max_bytes_per_sec"}}, reconcile(es))
+	require.Equal(t, "100mb", esClient.settings["indices.recovery.max_bytes_per_sec"])
+
+	// settings also applied by a StackConfigPolicy are left to the policy
+	policyKey := types.NamespacedName{Namespace: "ns", Name: esv1.StackConfigPolicySecret("es")}
+	policyApplied := Applied{}
+	policyApplied.set(ClusterSettingsKind, "indices.recovery.max_bytes_per_sec", "1234")
+	require.NoError(t, ReconcileApplied(context.Background(), c, es, policyKey, nil, policyApplied))
+	esClient.settings["indices.recovery.max_bytes_per_sec"] = "50mb"
+	require.Equal(t, SpecResult{}, reconcile(es))
+	require.Equal(t, "50mb", esClient.settings["indices.recovery.max_bytes_per_sec"])
+	require.NoError(t, ReconcileApplied(context.Background(), c, es, policyKey, nil, Applied{}))
+
+	// or only reported
+	es.Spec.ClusterSettings.ConflictPolicy = esv1.ReportClusterSettingsConflictPolicy
+	esClient.settings["indices.recovery.max_bytes_per_sec"] = "50mb"
+	require.Equal(t, SpecResult{DriftedSettings: []string{"indices.recovery.max_bytes_per_sec"}}, reconcile(es))
+	require.Equal(t, "50mb", esClient.settings["indices.recovery.max_bytes_per_sec"])
+
+	// a setting changed in the spec is applied regardless of the conflict policy
+	es.Spec.ClusterSettings.Persistent.Data["indices.recovery.max_bytes_per_sec"] = "200mb"
+	require.Equal(t, SpecResult{}, reconcile(es))
+	require.Equal(t, "200mb", esClient.settings["indices.recovery.max_bytes_per_sec"])
+
+	// the settings are reset once removed from the spec
+	es.Spec.ClusterSettings = nil
+	require.Equal(t, SpecResult{}, reconcile(es))
+	require.Empty(t, esClient.settings)
+}
+
+type fakeTemplatesClient struct {
+	esclient.Client
+	component  esclient.IndexTemplates
+	composable esclient.IndexTemplates
+	legacy     esclient.IndexTemplates
+}
+
+func (f *fakeTemplatesClient) GetComponentTemplates(_ context.Context) (esclient.IndexTemplates, error) {
+	return f.component, nil
+}
+
+func (f *fakeTemplatesClient) UpdateComponentTemplate(_ context.Context, name string, template map[string]interface{}) error {
+	f.component[name] = template
+	return nil
+}
+
+func (f *fakeTemplatesClient) DeleteComponentTemplate(_ context.Context, name string) error {
+	delete(f.component, name)
+	return nil
+}
+
+func (f *fakeTemplatesClient) GetIndexTemplates(_ context.Context) (esclient.IndexTemplates, error) {
+	return f.composable, nil
+}
+
+func (f *fakeTemplatesClient) UpdateIndexTemplate(_ context.Context, name string, template map[string]interface{}) error {
+	f.composable[name] = template
+	return nil
+}
+
+func (f *fakeTemplatesClient) DeleteIndexTemplate(_ context.Context, name string) error {
+	delete(f.composable, name)
+	return nil
+}
+
+func (f *fakeTemplatesClient) GetLegacyIndexTemplates(_ context.Context) (esclient.IndexTemplates, error) {
+	return f.legacy, nil
+}
+
+func (f *fakeTemplatesClient) UpdateLegacyIndexTemplate(_ context.Context, name string, template map[string]interface{}) error {
+	f.legacy[name] = template
+	return nil
+}
+
+func (f *fakeTemplatesClient) DeleteLegacyIndexTemplate(_ context.Context, name string) error {
+	delete(f.legacy, name)
+	return nil
+}
+
+func TestReconcileSpec_IndexTemplates(t *testing.T) {
+	controllerscheme.SetupScheme()
+	es := esv1.Elasticsearch{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "es"},
+		Spec: esv1.ElasticsearchSpec{
+			ComponentTemplates: []esv1.ComponentTemplate{
+				{Name: "logs-mappings", Definition: &commonv1.Config{Data: map[string]interface{}{"template": map[string]interface{}{}}}},
+			},
+			IndexTemplates: []esv1.IndexTemplate{
+				{Name: "logs", ConfigMapName: "templates"},
+				{Name: "logs", Type: esv1.LegacyIndexTemplateType, Definition: &commonv1.Config{Data: map[string]interface{}{"order": float64(1)}}},
+				{Name: "metrics", Definition: &commonv1.Config{Data: map[string]interface{}{"priority": float64(1)}}},
+			},
+		},
+	}
+	configMap := corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "templates"},
+		Data:       map[string]string{esv1.IndexTemplateConfigMapKey: `{"index_patterns": ["logs-*"], "composed_of": ["logs-mappings"]}`},
+	}
+	c := k8s.NewFakeClient(&es, &configMap)
+	esClient := &fakeTemplatesClient{
+		component: esclient.IndexTemplates{},
+		// built-in template, not managed by the operator
+		composable: esclient.IndexTemplates{"metrics": {"priority": float64(100)}},
+		legacy:     esclient.IndexTemplates{},
+	}
+	watched := watches.NewDynamicWatches()
+
+	// component templates and index templates of both types are created, except the one which already exists
+	result, err := ReconcileSpec(context.Background(), c, esClient, es, watched)
+	require.NoError(t, err)
+	require.Equal(t, []Conflict{{Kind: IndexTemplatesKind, Name: "metrics"}}, result.Conflicts)
+	require.Equal(t, esclient.IndexTemplates{
+		"logs":    {"index_patterns": []interface{}{"logs-*"}, "composed_of": []interface{}{"logs-mappings"}},
+		"metrics": {"priority": float64(100)},
+	}, esClient.composable)
+	require.Equal(t, esclient.IndexTemplates{"logs": {"order": float64(1)}}, esClient.legacy)
+	require.Contains(t, esClient.component, "logs-mappings")
+
+	// templates are deleted once removed from the spec
+	es.Spec.ComponentTemplates = nil
+	es.Spec.IndexTemplates = es.Spec.IndexTemplates[2:]
+	result, err = ReconcileSpec(context.Background(), c, esClient, es, watched)
+	require.NoError(t, err)
+	require.Len(t, result.Conflicts, 1)
+	require.Equal(t, esclient.IndexTemplates{"metrics": {"priority": float64(100)}}, esClient.composable)
+	require.Empty(t, esClient.legacy)
+	require.Empty(t, esClient.component)
+}
+
+func Test_missingDataTiers(t *testing.T) {
+	warmPolicy := map[string]interface{}{"phases": map[string]interface{}{
+		"hot":  map[string]interface{}{"actions": map[string]interface{}{}},
+		"warm": map[string]interface{}{"actions": map[string]interface{}{}},
+	}}
+	frozenPolicy := map[string]interface{}{"phases": map[string]interface{}{
+		"frozen": map[string]interface{}{"actions": map[string]interface{}{"searchable_snapshot": map[string]interface{}{}}},
+	}}
+	noMigratePolicy := map[string]interface{}{"phases": map[string]interface{}{
+		"cold": map[string]interface{}{"actions": map[string]interface{}{"migrate": map[string]interface{}{"enabled": false}}},
+	}}
+	roles := func(roles ...interface{}) *commonv1.Config {
+		return &commonv1.Config{Data: map[string]interface{}{"node.roles": roles}}
+	}
+	tests := []struct {
+		name     string
+		nodeSets []esv1.NodeSet
+		policies map[string]interface{}
+		want     []esv1.DataTier
+	}{
+		{
+			name:     "no policy",
+			nodeSets: []esv1.NodeSet{{Name: "hot", Count: 1, DataTier: esv1.DataTierHot}},
+		},
+		{
+			name:     "nodes with all the roles by default",
+			nodeSets: []esv1.NodeSet{{Name: "default", Count: 3}},
+			policies: map[string]interface{}{"warm": warmPolicy, "frozen": frozenPolicy},
+		},
+		{
+			name: "tiers provided by the data tier or the roles of the NodeSets",
+			nodeSets: []esv1.NodeSet{
+				{Name: "hot", Count: 1, DataTier: esv1.DataTierHot},
+				{Name: "warm", Count: 1, Config: roles("data_warm")},
+			},
+			policies: map[string]interface{}{"warm": warmPolicy},
+		},
+		{
+			name: "missing tiers",
+			nodeSets: []esv1.NodeSet{
+				{Name: "hot", Count: 1, DataTier: esv1.DataTierHot},
+				{Name: "warm", Count: 0, DataTier: esv1.DataTierWarm},
+			},
+			policies: map[string]interface{}{"warm": warmPolicy, "frozen": frozenPolicy},
+			want:     []esv1.DataTier{esv1.DataTierWarm, esv1.DataTierFrozen},
+		},
+		{
+			name:     "migration disabled",
+			nodeSets: []esv1.NodeSet{{Name: "hot", Count: 1, Config: roles("master", "data_hot", "data_content")}},
+			policies: map[string]interface{}{"cold": noMigratePolicy},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			es := esv1.Elasticsearch{Spec: esv1.ElasticsearchSpec{Version: "8.5.0", NodeSets: tt.nodeSets}}
+			got, err := missingDataTiers(es, tt.policies)
+			require.NoError(t, err)
+			require.Equal(t, tt.want, got)
+		})
+	}
+}
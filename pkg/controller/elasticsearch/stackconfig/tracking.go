@@ -0,0 +1,78 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package stackconfig
+
+import (
+	"context"
+	"encoding/json"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	esv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/elasticsearch/v1"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/reconciler"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/elasticsearch/label"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/utils/k8s"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/utils/maps"
+)
+
+// GetApplied returns the resources applied by the operator, tracked in the given Secret. There are none if the Secret
+// does not exist.
+func GetApplied(ctx context.Context, c k8s.Client, key types.NamespacedName) (Applied, error) {
+	var secret corev1.Secret
+	err := c.Get(ctx, key, &secret)
+	if apierrors.IsNotFound(err) {
+		return Applied{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	applied := make(Applied, len(secret.Data))
+	for kind, serialized := range secret.Data {
+		var resources map[string]string
+		if err := json.Unmarshal(serialized, &resources); err != nil {
+			return nil, err
+		}
+		for name, definitionHash := range resources {
+			applied.set(Kind(kind), name, definitionHash)
+		}
+	}
+	return applied, nil
+}
+
+// ReconcileApplied tracks the applied resources in the given Secret, owned by the Elasticsearch resource, or deletes
+// the Secret if no resource is applied.
+func ReconcileApplied(
+	ctx context.Context,
+	c k8s.Client,
+	es esv1.Elasticsearch,
+	key types.NamespacedName,
+	labels map[string]string,
+	applied Applied,
+) error {
+	if len(applied) == 0 {
+		return k8s.DeleteSecretIfExists(ctx, c, key)
+	}
+	data := make(map[string][]byte, len(applied))
+	for kind, resources := range applied {
+		serialized, err := json.Marshal(resources)
+		if err != nil {
+			return err
+		}
+		data[string(kind)] = serialized
+	}
+	expected := corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: key.Namespace,
+			Name:      key.Name,
+			Labels:    maps.Merge(label.NewLabels(k8s.ExtractNamespacedName(&es)), labels),
+		},
+		Data: data,
+	}
+	_, err := reconciler.ReconcileSecret(ctx, c, expected, &es)
+	return err
+}
@@ -6,9 +6,11 @@ package stackmon
 
 import (
 	corev1 "k8s.io/api/core/v1"
+
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/elasticsearch/settings"
 )
 
 // fileLogStyleEnvVar returns the environment variable to configure the Elasticsearch container to write logs to disk
 func fileLogStyleEnvVar() corev1.EnvVar {
-	return corev1.EnvVar{Name: "ES_LOG_STYLE", Value: "file"}
+	return corev1.EnvVar{Name: settings.EnvEsLogStyle, Value: "file"}
 }
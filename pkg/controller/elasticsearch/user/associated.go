@@ -23,6 +23,8 @@ const (
 	AssociatedUserType = "user"
 	// ServiceAccountTokenType is used to annotate a secret that contains a service account token, most likely created by an association controller.
 	ServiceAccountTokenType = "service-account-token"
+	// AssociatedAPIKeyType is used to annotate a secret that tracks an API key created by an association controller.
+	AssociatedAPIKeyType = "api-key"
 
 	// UserNameField is the field in the secret that contains the username.
 	UserNameField = "name"
@@ -0,0 +1,87 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package user
+
+import (
+	"context"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	esv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/elasticsearch/v1"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/certificates"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/dryrun"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/tracing"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/version"
+	esclient "github.com/elastic/cloud-on-k8s/v2/pkg/controller/elasticsearch/client"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/elasticsearch/services"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/dev"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/utils/k8s"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/utils/net"
+)
+
+// NewControllerClient returns a client to the given Elasticsearch cluster, authenticated as the controller user, for
+// the controllers other than the Elasticsearch controller. In dry-run mode, the changes are reported on the
+// Elasticsearch resource instead of being sent.
+func NewControllerClient(
+	ctx context.Context,
+	c k8s.Client,
+	dialer net.Dialer,
+	dryRun *dryrun.Mode,
+	es esv1.Elasticsearch,
+) (esclient.Client, error) {
+	defer tracing.Span(&ctx)()
+	url := services.ExternalServiceURL(es)
+	v, err := version.Parse(es.Spec.Version)
+	if err != nil {
+		return nil, err
+	}
+	// Get user Secret
+	var controllerUserSecret corev1.Secret
+	key := types.NamespacedName{
+		Namespace: es.Namespace,
+		Name:      esv1.InternalUsersSecret(es.Name),
+	}
+	if err := c.Get(ctx, key, &controllerUserSecret); err != nil {
+		return nil, err
+	}
+	password, ok := controllerUserSecret.Data[ControllerUserName]
+	if !ok {
+		return nil, fmt.Errorf("controller user %s not found in Secret %s/%s", ControllerUserName, key.Namespace, key.Name)
+	}
+
+	// Get public certs
+	var caSecret corev1.Secret
+	key = types.NamespacedName{
+		Namespace: es.Namespace,
+		Name:      certificates.PublicCertsSecretName(esv1.ESNamer, es.Name),
+	}
+	if err := c.Get(ctx, key, &caSecret); err != nil {
+		return nil, err
+	}
+	trustedCerts, ok := caSecret.Data[certificates.CertFileName]
+	if !ok {
+		return nil, fmt.Errorf("%s not found in Secret %s/%s", certificates.CertFileName, key.Namespace, key.Name)
+	}
+	caCerts, err := certificates.ParsePEMCerts(trustedCerts)
+	if err != nil {
+		return nil, err
+	}
+	return esclient.NewElasticsearchClient(
+		dialer,
+		k8s.ExtractNamespacedName(&es),
+		url,
+		esclient.BasicAuth{
+			Name:     ControllerUserName,
+			Password: string(password),
+		},
+		v,
+		caCerts,
+		esclient.Timeout(ctx, es),
+		dev.Enabled,
+		dryRun.ReporterFor(&es),
+	), nil
+}
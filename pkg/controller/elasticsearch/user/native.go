@@ -0,0 +1,218 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package user
+
+import (
+	"context"
+	"fmt"
+
+	"go.elastic.co/apm/v2"
+	"golang.org/x/crypto/bcrypt"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+
+	esv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/elasticsearch/v1"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/reconciler"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/tracing"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/watches"
+	esclient "github.com/elastic/cloud-on-k8s/v2/pkg/controller/elasticsearch/client"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/elasticsearch/label"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/utils/k8s"
+	ulog "github.com/elastic/cloud-on-k8s/v2/pkg/utils/log"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/utils/set"
+)
+
+// NativeUsersPasswordsWatchName returns the watch registered for the secrets holding the passwords of the native users.
+func NativeUsersPasswordsWatchName(es types.NamespacedName) string {
+	return fmt.Sprintf("%s-%s-native-users-passwords", es.Namespace, es.Name)
+}
+
+// nativeUsers maps the names of the native users managed by the operator to the bcrypt hash of the password they were
+// last created or updated with, or to an empty hash if they were not created yet.
+type nativeUsers map[string][]byte
+
+// ReconcileNativeUsers creates the users of the native realm declared in the Elasticsearch spec through the security
+// API, and deletes the users removed from the spec. Users whose roles, full name or email drifted from the spec, or
+// which were disabled, are updated. Passwords cannot be read back from Elasticsearch: the password of a user is only
+// updated when the content of its password secret changes. The managed users are tracked in a Secret owned by the
+// Elasticsearch resource, so that the users created through the API by other means are left untouched.
+func ReconcileNativeUsers(
+	ctx context.Context,
+	c k8s.Client,
+	esClient esclient.SecurityClient,
+	es esv1.Elasticsearch,
+	watched watches.DynamicWatches,
+	recorder record.EventRecorder,
+) error {
+	esKey := k8s.ExtractNamespacedName(&es)
+	secretNames := make([]string, 0, len(es.Spec.Auth.Users))
+	for _, u := range es.Spec.Auth.Users {
+		if u.PasswordSecretRef.SecretName == "" {
+			continue
+		}
+		secretNames = append(secretNames, u.PasswordSecretRef.SecretName)
+	}
+	if err := watches.WatchUserProvidedSecrets(esKey, watched, NativeUsersPasswordsWatchName(esKey), secretNames); err != nil {
+		return err
+	}
+
+	tracked, err := getTrackedNativeUsers(ctx, c, es)
+	if err != nil {
+		return err
+	}
+	if len(es.Spec.Auth.Users) == 0 && len(tracked) == 0 {
+		return nil
+	}
+
+	span, ctx := apm.StartSpan(ctx, "reconcile_native_users", tracing.SpanTypeApp)
+	defer span.End()
+	log := ulog.FromContext(ctx)
+
+	// track the declared users before creating them
+	declared := set.Make()
+	for _, u := range es.Spec.Auth.Users {
+		declared.Add(u.Name)
+		if _, exists := tracked[u.Name]; !exists {
+			tracked[u.Name] = []byte{}
+		}
+	}
+	if err := reconcileTrackedNativeUsers(ctx, c, es, tracked); err != nil {
+		return err
+	}
+
+	actual, err := esClient.GetUsers(ctx)
+	if err != nil {
+		return err
+	}
+	for _, u := range es.Spec.Auth.Users {
+		password, err := nativeUserPassword(ctx, c, es, u, recorder)
+		if err != nil {
+			return err
+		}
+		if password == nil {
+			// the password secret is missing or invalid, which is reported in an event
+			continue
+		}
+		expected := expectedNativeUser(u)
+		current, exists := actual[u.Name]
+		passwordChanged := bcrypt.CompareHashAndPassword(tracked[u.Name], password) != nil
+		if exists && current.Equal(expected) && !passwordChanged {
+			continue
+		}
+		if !exists || passwordChanged {
+			expected.Password = string(password)
+		}
+		log.Info("Updating native user", "namespace", es.Namespace, "es_name", es.Name, "user_name", u.Name,
+			"password_updated", expected.Password != "")
+		if err := esClient.UpdateUser(ctx, u.Name, expected); err != nil {
+			return fmt.Errorf("while updating native user %s: %w", u.Name, err)
+		}
+		if passwordChanged {
+			if tracked[u.Name], err = bcrypt.GenerateFromPassword(password, bcrypt.DefaultCost); err != nil {
+				return err
+			}
+		}
+	}
+
+	for _, name := range set.Make(trackedNames(tracked)...).Diff(declared).AsSortedSlice() {
+		if _, exists := actual[name]; exists {
+			log.Info("Deleting native user", "namespace", es.Namespace, "es_name", es.Name, "user_name", name)
+			if err := esClient.DeleteUser(ctx, name); err != nil && !esclient.IsNotFound(err) {
+				return fmt.Errorf("while deleting native user %s: %w", name, err)
+			}
+		}
+		delete(tracked, name)
+	}
+	return reconcileTrackedNativeUsers(ctx, c, es, tracked)
+}
+
+// expectedNativeUser returns the definition of the given native user expected in Elasticsearch, without its password.
+func expectedNativeUser(u esv1.NativeUser) esclient.User {
+	roles := u.Roles
+	if roles == nil {
+		// roles are mandatory in the security API
+		roles = []string{}
+	}
+	return esclient.User{Roles: roles, FullName: u.FullName, Email: u.Email, Enabled: true}
+}
+
+// nativeUserPassword returns the password of the given native user, or nil if its password secret does not exist or
+// does not hold a password.
+func nativeUserPassword(
+	ctx context.Context,
+	c k8s.Client,
+	es esv1.Elasticsearch,
+	u esv1.NativeUser,
+	recorder record.EventRecorder,
+) ([]byte, error) {
+	log := ulog.FromContext(ctx)
+	secretName := u.PasswordSecretRef.SecretName
+	var secret corev1.Secret
+	err := c.Get(ctx, types.NamespacedName{Namespace: es.Namespace, Name: secretName}, &secret)
+	if apierrors.IsNotFound(err) {
+		handleSecretNotFound(log, recorder, es, secretName)
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	password := k8s.GetSecretEntry(secret, corev1.BasicAuthPasswordKey)
+	if len(password) == 0 {
+		handleInvalidSecretData(log, recorder, es, secretName, fmt.Errorf("no password for native user %s", u.Name))
+		return nil, nil
+	}
+	return password, nil
+}
+
+// getTrackedNativeUsers returns the native users managed by the operator.
+func getTrackedNativeUsers(ctx context.Context, c k8s.Client, es esv1.Elasticsearch) (nativeUsers, error) {
+	var secret corev1.Secret
+	err := c.Get(ctx, types.NamespacedName{Namespace: es.Namespace, Name: esv1.NativeUsersSecret(es.Name)}, &secret)
+	if apierrors.IsNotFound(err) {
+		return nativeUsers{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	tracked := make(nativeUsers, len(secret.Data))
+	for name, hash := range secret.Data {
+		tracked[name] = hash
+	}
+	return tracked, nil
+}
+
+// reconcileTrackedNativeUsers stores the given native users in the Secret tracking the native users managed by the
+// operator, or deletes the Secret if there is none.
+func reconcileTrackedNativeUsers(ctx context.Context, c k8s.Client, es esv1.Elasticsearch, tracked nativeUsers) error {
+	secretRef := types.NamespacedName{Namespace: es.Namespace, Name: esv1.NativeUsersSecret(es.Name)}
+	if len(tracked) == 0 {
+		return k8s.DeleteSecretIfExists(ctx, c, secretRef)
+	}
+	data := make(map[string][]byte, len(tracked))
+	for name, hash := range tracked {
+		data[name] = hash
+	}
+	expected := corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: secretRef.Namespace,
+			Name:      secretRef.Name,
+			Labels:    label.NewLabels(k8s.ExtractNamespacedName(&es)),
+		},
+		Data: data,
+	}
+	_, err := reconciler.ReconcileSecret(ctx, c, expected, &es)
+	return err
+}
+
+func trackedNames(tracked nativeUsers) []string {
+	names := make([]string, 0, len(tracked))
+	for name := range tracked {
+		names = append(names, name)
+	}
+	return names
+}
@@ -0,0 +1,156 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package user
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/bcrypt"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+
+	commonv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/common/v1"
+	esv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/elasticsearch/v1"
+	controllerscheme "github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/scheme"
+	esclient "github.com/elastic/cloud-on-k8s/v2/pkg/controller/elasticsearch/client"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/utils/k8s"
+)
+
+type fakeSecurityClient struct {
+	esclient.SecurityClient
+	users   esclient.Users
+	updated []string
+	deleted []string
+}
+
+func (f *fakeSecurityClient) GetUsers(_ context.Context) (esclient.Users, error) {
+	users := make(esclient.Users, len(f.users))
+	for name, u := range f.users {
+		users[name] = u
+	}
+	return users, nil
+}
+
+func (f *fakeSecurityClient) UpdateUser(_ context.Context, name string, user esclient.User) error {
+	f.updated = append(f.updated, name)
+	if current, exists := f.users[name]; exists && user.Password == "" {
+		user.Password = current.Password
+	}
+	f.users[name] = user
+	return nil
+}
+
+func (f *fakeSecurityClient) DeleteUser(_ context.Context, name string) error {
+	f.deleted = append(f.deleted, name)
+	delete(f.users, name)
+	return nil
+}
+
+func TestReconcileNativeUsers(t *testing.T) {
+	controllerscheme.SetupScheme()
+	es := esv1.Elasticsearch{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "es"},
+		Spec: esv1.ElasticsearchSpec{Auth: esv1.Auth{Users: []esv1.NativeUser{
+			{Name: "jacknich", PasswordSecretRef: commonv1.SecretRef{SecretName: "jacknich-password"}, Roles: []string{"admin"}, FullName: "Jack Nicholson"},
+		}}},
+	}
+	passwordSecret := corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "jacknich-password"},
+		Data:       map[string][]byte{corev1.BasicAuthPasswordKey: []byte("password1")},
+	}
+	c := k8s.NewFakeClient(&es, &passwordSecret)
+	esClient := &fakeSecurityClient{users: esclient.Users{
+		"elastic": {Roles: []string{"superuser"}, Enabled: true},
+		// created through the API by the user, not managed by the operator
+		"rdeniro": {Roles: []string{"viewer"}, Enabled: true},
+	}}
+	reconcile := func(es esv1.Elasticsearch) {
+		t.Helper()
+		esClient.updated, esClient.deleted = nil, nil
+		require.NoError(t, ReconcileNativeUsers(context.Background(), c, esClient, es, initDynamicWatches(), record.NewFakeRecorder(10)))
+	}
+	trackedHash := func() []byte {
+		t.Helper()
+		tracked, err := getTrackedNativeUsers(context.Background(), c, es)
+		require.NoError(t, err)
+		return tracked["jacknich"]
+	}
+
+	// the user is created with its password
+	reconcile(es)
+	require.Equal(t, []string{"jacknich"}, esClient.updated)
+	require.Equal(t, esclient.User{Roles: []string{"admin"}, FullName: "Jack Nicholson", Enabled: true, Password: "password1"}, esClient.users["jacknich"])
+	require.NoError(t, bcrypt.CompareHashAndPassword(trackedHash(), []byte("password1")))
+
+	// nothing to do if the user did not change
+	reconcile(es)
+	require.Empty(t, esClient.updated)
+
+	// the user is updated if it drifted from the spec, without updating its password
+	drifted := esClient.users["jacknich"]
+	drifted.Roles = []string{"admin", "superuser"}
+	drifted.Password = "changed-through-the-api"
+	esClient.users["jacknich"] = drifted
+	reconcile(es)
+	require.Equal(t, []string{"jacknich"}, esClient.updated)
+	require.Equal(t, []string{"admin"}, esClient.users["jacknich"].Roles)
+	require.Equal(t, "changed-through-the-api", esClient.users["jacknich"].Password)
+
+	// the password is updated when the password secret changes
+	passwordSecret.Data[corev1.BasicAuthPasswordKey] = []byte("password2")
+	require.NoError(t, c.Update(context.Background(), &passwordSecret))
+	reconcile(es)
+	require.Equal(t, []string{"jacknich"}, esClient.updated)
+	require.Equal(t, "password2", esClient.users["jacknich"].Password)
+	require.NoError(t, bcrypt.CompareHashAndPassword(trackedHash(), []byte("password2")))
+
+	// the user is recreated with its password if it was deleted through the API
+	delete(esClient.users, "jacknich")
+	reconcile(es)
+	require.Equal(t, []string{"jacknich"}, esClient.updated)
+	require.Equal(t, "password2", esClient.users["jacknich"].Password)
+
+	// the user is deleted once removed from the spec, other users are left untouched
+	withoutUsers := *es.DeepCopy()
+	withoutUsers.Spec.Auth.Users = nil
+	reconcile(withoutUsers)
+	require.Equal(t, []string{"jacknich"}, esClient.deleted)
+	require.Contains(t, esClient.users, "rdeniro")
+	err := c.Get(context.Background(), types.NamespacedName{Namespace: "ns", Name: esv1.NativeUsersSecret("es")}, &corev1.Secret{})
+	require.True(t, apierrors.IsNotFound(err))
+}
+
+func TestReconcileNativeUsers_missingPasswordSecret(t *testing.T) {
+	controllerscheme.SetupScheme()
+	es := esv1.Elasticsearch{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "es"},
+		Spec: esv1.ElasticsearchSpec{Auth: esv1.Auth{Users: []esv1.NativeUser{
+			{Name: "jacknich", PasswordSecretRef: commonv1.SecretRef{SecretName: "jacknich-password"}},
+		}}},
+	}
+	c := k8s.NewFakeClient(&es)
+	esClient := &fakeSecurityClient{users: esclient.Users{}}
+	recorder := record.NewFakeRecorder(10)
+
+	require.NoError(t, ReconcileNativeUsers(context.Background(), c, esClient, es, initDynamicWatches(), recorder))
+	require.Empty(t, esClient.updated)
+	require.Len(t, recorder.Events, 1)
+	// the user is tracked to be deleted if removed from the spec before its secret is created
+	tracked, err := getTrackedNativeUsers(context.Background(), c, es)
+	require.NoError(t, err)
+	require.Contains(t, tracked, "jacknich")
+}
+
+func TestReservedUserNames(t *testing.T) {
+	// the users created by the operator cannot be declared as native users
+	for _, name := range []string{ElasticUserName, ControllerUserName, ProbeUserName, MonitoringUserName} {
+		require.True(t, esv1.ReservedUserNames.Has(name), name)
+	}
+}
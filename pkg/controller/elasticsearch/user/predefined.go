@@ -6,6 +6,7 @@ package user
 
 import (
 	"context"
+	"fmt"
 
 	"github.com/pkg/errors"
 	"golang.org/x/crypto/bcrypt"
@@ -13,14 +14,17 @@ import (
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
 
 	esv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/elasticsearch/v1"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/events"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/labels"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/reconciler"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/elasticsearch/label"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/elasticsearch/user/filerealm"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/utils/k8s"
+	ulog "github.com/elastic/cloud-on-k8s/v2/pkg/utils/log"
 )
 
 const (
@@ -33,10 +37,24 @@ const (
 	ProbeUserName = "elastic-internal-probe"
 	// MonitoringUserName is used for the Elasticsearch monitoring.
 	MonitoringUserName = "elastic-internal-monitoring"
+
+	// RotateElasticPasswordAnnotation can be set on the Elasticsearch resource to request the rotation of the password
+	// of the elastic user. A new password is generated every time the value of the annotation changes.
+	RotateElasticPasswordAnnotation = "elasticsearch.k8s.elastic.co/rotate-elastic-password"
+	// elasticPasswordRotationAnnotation records on the elastic user secret the value of the rotation annotation for
+	// which the current password was generated.
+	elasticPasswordRotationAnnotation = "elasticsearch.k8s.elastic.co/elastic-password-rotation"
 )
 
 // reconcileElasticUser reconciles a single secret holding the "elastic" user password.
-func reconcileElasticUser(ctx context.Context, c k8s.Client, es esv1.Elasticsearch, existingFileRealm, userProvidedFileRealm filerealm.Realm) (users, error) {
+// A new password is generated if a rotation is requested through the RotateElasticPasswordAnnotation.
+func reconcileElasticUser(
+	ctx context.Context,
+	c k8s.Client,
+	es esv1.Elasticsearch,
+	existingFileRealm, userProvidedFileRealm filerealm.Realm,
+	recorder record.EventRecorder,
+) (users, error) {
 	secretName := esv1.ElasticUserSecret(es.Name)
 	// if user has set up the elastic user via the file realm do not create the operator managed secret to avoid confusion
 	if userProvidedFileRealm.PasswordHashForUser(ElasticUserName) != nil {
@@ -45,8 +63,19 @@ func reconcileElasticUser(ctx context.Context, c k8s.Client, es esv1.Elasticsear
 			Name:      secretName,
 		})
 	}
+
+	rotation := es.Annotations[RotateElasticPasswordAnnotation]
+	rotate, err := isElasticPasswordRotationRequested(c, types.NamespacedName{Namespace: es.Namespace, Name: secretName}, rotation)
+	if err != nil {
+		return nil, err
+	}
+	var annotations map[string]string
+	if rotation != "" {
+		annotations = map[string]string{elasticPasswordRotationAnnotation: rotation}
+	}
+
 	// regular reconciliation if user did not choose to set a password for the elastic user
-	return reconcilePredefinedUsers(
+	elasticUser, err := reconcilePredefinedUsers(
 		ctx,
 		c,
 		es,
@@ -55,10 +84,41 @@ func reconcileElasticUser(ctx context.Context, c k8s.Client, es esv1.Elasticsear
 			{Name: ElasticUserName, Roles: []string{SuperUserBuiltinRole}},
 		},
 		secretName,
+		annotations,
+		rotate,
 		// Don't set an ownerRef for the elastic user secret, likely to be copied into different namespaces.
 		// See https://github.com/elastic/cloud-on-k8s/issues/3986.
 		false,
 	)
+	if err != nil {
+		return nil, err
+	}
+	if rotate {
+		ulog.FromContext(ctx).Info("Rotated the password of the elastic user", "namespace", es.Namespace, "es_name", es.Name)
+		recorder.Event(&es, corev1.EventTypeNormal, events.EventReasonPasswordRotation,
+			fmt.Sprintf("Rotated the password of the elastic user stored in secret %s", secretName))
+	}
+	return elasticUser, nil
+}
+
+// isElasticPasswordRotationRequested returns true if the given rotation differs from the rotation for which the
+// existing password of the elastic user was generated. The first password of the elastic user is not a rotation.
+func isElasticPasswordRotationRequested(c k8s.Client, secretRef types.NamespacedName, rotation string) (bool, error) {
+	if rotation == "" {
+		return false, nil
+	}
+	var secret corev1.Secret
+	err := c.Get(context.Background(), secretRef, &secret)
+	if apierrors.IsNotFound(err) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	if _, exists := secret.Data[ElasticUserName]; !exists {
+		return false, nil
+	}
+	return secret.Annotations[elasticPasswordRotationAnnotation] != rotation, nil
 }
 
 // reconcileInternalUsers reconciles a single secret holding the internal users passwords.
@@ -74,12 +134,15 @@ func reconcileInternalUsers(ctx context.Context, c k8s.Client, es esv1.Elasticse
 			{Name: MonitoringUserName, Roles: []string{RemoteMonitoringCollectorBuiltinRole}},
 		},
 		esv1.InternalUsersSecret(es.Name),
+		nil,
+		false,
 		true,
 	)
 }
 
 // reconcilePredefinedUsers reconciles a secret with the given name holding the given users.
-// It attempts to reuse passwords from pre-existing secrets, and reuse hashes from pre-existing file realms.
+// It attempts to reuse passwords from pre-existing secrets, and reuse hashes from pre-existing file realms, unless new
+// passwords must be generated.
 func reconcilePredefinedUsers(
 	ctx context.Context,
 	c k8s.Client,
@@ -87,15 +150,21 @@ func reconcilePredefinedUsers(
 	existingFileRealm filerealm.Realm,
 	users users,
 	secretName string,
+	annotations map[string]string,
+	generatePasswords bool,
 	setOwnerRef bool,
 ) (users, error) {
 	secretNsn := types.NamespacedName{Namespace: es.Namespace, Name: secretName}
 
 	// build users, reusing existing passwords and bcrypt hashes if possible
 	var err error
-	users, err = reuseOrGeneratePassword(c, users, secretNsn)
-	if err != nil {
-		return nil, err
+	if generatePasswords {
+		users = generatePassword(users)
+	} else {
+		users, err = reuseOrGeneratePassword(c, users, secretNsn)
+		if err != nil {
+			return nil, err
+		}
 	}
 	users, err = reuseOrGenerateHashes(users, existingFileRealm)
 	if err != nil {
@@ -110,9 +179,10 @@ func reconcilePredefinedUsers(
 
 	expected := corev1.Secret{
 		ObjectMeta: metav1.ObjectMeta{
-			Namespace: secretNsn.Namespace,
-			Name:      secretNsn.Name,
-			Labels:    labels.AddCredentialsLabel(label.NewLabels(k8s.ExtractNamespacedName(&es))),
+			Namespace:   secretNsn.Namespace,
+			Name:        secretNsn.Name,
+			Labels:      labels.AddCredentialsLabel(label.NewLabels(k8s.ExtractNamespacedName(&es))),
+			Annotations: annotations,
 		},
 		Data: secretData,
 	}
@@ -151,6 +221,14 @@ func reuseOrGeneratePassword(c k8s.Client, users users, secretRef types.Namespac
 	return users, nil
 }
 
+// generatePassword updates the users with newly generated passwords.
+func generatePassword(users users) users {
+	for i := range users {
+		users[i].Password = common.FixedLengthRandomPasswordBytes()
+	}
+	return users
+}
+
 // reuseOrGenerateHashes updates the users with existing hashes from the given file realm, or generates new ones.
 func reuseOrGenerateHashes(users users, fileRealm filerealm.Realm) (users, error) {
 	for i, u := range users {
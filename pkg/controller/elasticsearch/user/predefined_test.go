@@ -5,6 +5,7 @@
 package user
 
 import (
+	"bytes"
 	"context"
 	"testing"
 
@@ -14,6 +15,7 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
 
 	esv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/elasticsearch/v1"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/elasticsearch/user/filerealm"
@@ -110,7 +112,7 @@ func Test_reconcileElasticUser(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			c := k8s.NewFakeClient(tt.existingSecrets...)
-			got, err := reconcileElasticUser(context.Background(), c, es, tt.existingFileRealm, filerealm.New())
+			got, err := reconcileElasticUser(context.Background(), c, es, tt.existingFileRealm, filerealm.New(), record.NewFakeRecorder(10))
 			require.NoError(t, err)
 			// check returned user
 			require.Len(t, got, 1)
@@ -154,7 +156,7 @@ func Test_reconcileElasticUser_conditionalCreation(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			c := k8s.NewFakeClient()
-			got, err := reconcileElasticUser(context.Background(), c, es, filerealm.New(), tt.userFileReam)
+			got, err := reconcileElasticUser(context.Background(), c, es, filerealm.New(), tt.userFileReam, record.NewFakeRecorder(10))
 			require.NoError(t, err)
 			// check returned user
 			wantLen := 1
@@ -166,6 +168,87 @@ func Test_reconcileElasticUser_conditionalCreation(t *testing.T) {
 	}
 }
 
+func Test_reconcileElasticUser_rotation(t *testing.T) {
+	existingHash := []byte("$2a$10$lwsLdS0ZSyUv73WNdaRaTe8X9oeft4BoqjxtNHHH7LP7m1YImnvr6")
+	existingSecret := func(annotations map[string]string) *corev1.Secret {
+		return &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: esv1.ElasticUserSecret("es"), Annotations: annotations},
+			Data:       map[string][]byte{ElasticUserName: []byte("existingPassword")},
+		}
+	}
+	tests := []struct {
+		name           string
+		rotation       string
+		existingSecret *corev1.Secret
+		wantRotated    bool
+	}{
+		{
+			name:           "no rotation requested",
+			existingSecret: existingSecret(nil),
+			wantRotated:    false,
+		},
+		{
+			name:        "rotation requested before the first password is generated",
+			rotation:    "2022-10-20",
+			wantRotated: false,
+		},
+		{
+			name:           "rotation requested",
+			rotation:       "2022-10-20",
+			existingSecret: existingSecret(nil),
+			wantRotated:    true,
+		},
+		{
+			name:           "rotation already done",
+			rotation:       "2022-10-20",
+			existingSecret: existingSecret(map[string]string{elasticPasswordRotationAnnotation: "2022-10-20"}),
+			wantRotated:    false,
+		},
+		{
+			name:           "new rotation requested",
+			rotation:       "2022-10-21",
+			existingSecret: existingSecret(map[string]string{elasticPasswordRotationAnnotation: "2022-10-20"}),
+			wantRotated:    true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			es := esv1.Elasticsearch{ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "es"}}
+			if tt.rotation != "" {
+				es.Annotations = map[string]string{RotateElasticPasswordAnnotation: tt.rotation}
+			}
+			var existing []runtime.Object
+			if tt.existingSecret != nil {
+				existing = append(existing, tt.existingSecret)
+			}
+			c := k8s.NewFakeClient(existing...)
+			recorder := record.NewFakeRecorder(10)
+
+			got, err := reconcileElasticUser(context.Background(), c, es, filerealm.New().WithUser(ElasticUserName, existingHash), filerealm.New(), recorder)
+			require.NoError(t, err)
+			require.Len(t, got, 1)
+			if tt.existingSecret != nil {
+				require.Equal(t, tt.wantRotated, !bytes.Equal([]byte("existingPassword"), got[0].Password))
+			}
+			if tt.wantRotated {
+				// the file realm hash matches the new password
+				require.NotEqual(t, existingHash, got[0].PasswordHash)
+				require.NoError(t, bcrypt.CompareHashAndPassword(got[0].PasswordHash, got[0].Password))
+			}
+			require.Equal(t, tt.wantRotated, len(recorder.Events) == 1)
+
+			// the secret holds the new password and records the rotation
+			var secret corev1.Secret
+			err = c.Get(context.Background(), types.NamespacedName{Namespace: "ns", Name: esv1.ElasticUserSecret("es")}, &secret)
+			require.NoError(t, err)
+			require.Equal(t, got[0].Password, secret.Data[ElasticUserName])
+			if tt.rotation != "" {
+				require.Equal(t, tt.rotation, secret.Annotations[elasticPasswordRotationAnnotation])
+			}
+		})
+	}
+}
+
 func Test_reconcileInternalUsers(t *testing.T) {
 	es := esv1.Elasticsearch{ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "es"}}
 	tests := []struct {
@@ -103,7 +103,7 @@ func aggregateFileRealm(
 	}
 
 	// reconcile predefined users
-	elasticUser, err := reconcileElasticUser(ctx, c, es, existingFileRealm, userProvidedFileRealm)
+	elasticUser, err := reconcileElasticUser(ctx, c, es, existingFileRealm, userProvidedFileRealm, recorder)
 	if err != nil {
 		return filerealm.Realm{}, esclient.BasicAuth{}, err
 	}
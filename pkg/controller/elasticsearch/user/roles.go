@@ -42,6 +42,9 @@ const (
 
 	FleetAdminUserRole = "eck_fleet_admin_user_role"
 
+	// LogstashUserRole is the name of the role used by Logstash pipelines to send data to Elasticsearch.
+	LogstashUserRole = "eck_logstash_user_role"
+
 	// V70 indicates version 7.0
 	V70 = "v70"
 
@@ -147,6 +150,24 @@ var (
 				},
 			},
 		},
+		// LogstashUserRole allows Logstash to write to the default indices and data streams of the Elasticsearch output
+		// plugin, and to manage their index templates and ILM policies.
+		LogstashUserRole: esclient.Role{
+			Cluster: []string{
+				"monitor",
+				"manage_ilm",
+				"read_ilm",
+				"manage_logstash_pipelines",
+				"manage_index_templates",
+				"cluster:admin/ingest/pipeline/get",
+			},
+			Indices: []esclient.IndexRole{
+				{
+					Names:      []string{"logstash", "logstash-*", "ecs-logstash", "ecs-logstash-*", "logs-*", "metrics-*", "synthetics-*", "traces-*"},
+					Privileges: []string{"manage", "write", "create_index", "read", "view_index_metadata"},
+				},
+			},
+		},
 	}
 )
 
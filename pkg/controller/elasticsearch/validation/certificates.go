@@ -0,0 +1,48 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package validation
+
+import (
+	"fmt"
+
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+
+	esv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/elasticsearch/v1"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/certificates"
+)
+
+const certificateIssuanceLimitMsg = "%d certificates were issued in namespace %s during the last %s, which reaches the limit configured in the operator. Retry later"
+
+// certificateIssuanceLimitReached returns an error if the number of certificates issued in the given namespace during the
+// last issuance window reached the given limit. A limit lower or equal to 0 disables the check.
+func certificateIssuanceLimitReached(path *field.Path, namespace string, limit int, issued func(string) int) field.ErrorList {
+	if limit <= 0 {
+		return nil
+	}
+	if count := issued(namespace); count >= limit {
+		return field.ErrorList{field.Forbidden(path, fmt.Sprintf(certificateIssuanceLimitMsg, count, namespace, certificates.IssuanceWindow))}
+	}
+	return nil
+}
+
+// validCertificateIssuancesOnCreate prevents the creation of new clusters, which require new certificates, in
+// namespaces where too many certificates were issued recently.
+func validCertificateIssuancesOnCreate(es esv1.Elasticsearch, limit int, issued func(string) int) field.ErrorList {
+	return certificateIssuanceLimitReached(field.NewPath("metadata").Child("namespace"), es.Namespace, limit, issued)
+}
+
+// validCertificateIssuancesOnUpdate prevents changes to the HTTP and transport settings, which may require new
+// certificates, in namespaces where too many certificates were issued recently. Other changes are always allowed, as
+// well as the renewal of the certificates by the operator.
+func validCertificateIssuancesOnUpdate(prev, curr esv1.Elasticsearch, limit int, issued func(string) int) field.ErrorList {
+	if !equality.Semantic.DeepEqual(prev.Spec.HTTP, curr.Spec.HTTP) {
+		return certificateIssuanceLimitReached(field.NewPath("spec").Child("http"), curr.Namespace, limit, issued)
+	}
+	if !equality.Semantic.DeepEqual(prev.Spec.Transport, curr.Spec.Transport) {
+		return certificateIssuanceLimitReached(field.NewPath("spec").Child("transport"), curr.Namespace, limit, issued)
+	}
+	return nil
+}
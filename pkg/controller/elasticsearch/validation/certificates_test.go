@@ -0,0 +1,90 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package validation
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	commonv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/common/v1"
+	esv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/elasticsearch/v1"
+)
+
+func Test_validCertificateIssuances(t *testing.T) {
+	issued := func(namespace string) int {
+		if namespace == "busy" {
+			return 100
+		}
+		return 1
+	}
+	es := func(namespace string, san string) esv1.Elasticsearch {
+		cluster := esv1.Elasticsearch{ObjectMeta: metav1.ObjectMeta{Namespace: namespace, Name: "es"}}
+		if san != "" {
+			cluster.Spec.HTTP.TLS.SelfSignedCertificate = &commonv1.SelfSignedCertificate{
+				SubjectAlternativeNames: []commonv1.SubjectAlternativeName{{DNS: san}},
+			}
+		}
+		return cluster
+	}
+
+	tests := []struct {
+		name       string
+		prev       esv1.Elasticsearch
+		update     bool
+		curr       esv1.Elasticsearch
+		limit      int
+		wantErrors int
+	}{
+		{
+			name:  "creation without limit",
+			curr:  es("busy", ""),
+			limit: 0,
+		},
+		{
+			name:  "creation below the limit",
+			curr:  es("quiet", ""),
+			limit: 10,
+		},
+		{
+			name:       "creation above the limit",
+			curr:       es("busy", ""),
+			limit:      10,
+			wantErrors: 1,
+		},
+		{
+			name:       "HTTP settings change above the limit",
+			prev:       es("busy", ""),
+			update:     true,
+			curr:       es("busy", "es.example.com"),
+			limit:      10,
+			wantErrors: 1,
+		},
+		{
+			name:   "HTTP settings change below the limit",
+			prev:   es("quiet", ""),
+			update: true,
+			curr:   es("quiet", "es.example.com"),
+			limit:  10,
+		},
+		{
+			name:   "other change above the limit",
+			prev:   es("busy", "es.example.com"),
+			update: true,
+			curr:   func() esv1.Elasticsearch { e := es("busy", "es.example.com"); e.Spec.Version = "8.5.0"; return e }(),
+			limit:  10,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			errs := validCertificateIssuancesOnCreate(tt.curr, tt.limit, issued)
+			if tt.update {
+				errs = validCertificateIssuancesOnUpdate(tt.prev, tt.curr, tt.limit, issued)
+			}
+			require.Len(t, errs, tt.wantErrors)
+		})
+	}
+}
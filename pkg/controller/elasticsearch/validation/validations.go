@@ -8,50 +8,98 @@ import (
 	"context"
 	"fmt"
 	"net"
+	"regexp"
+	"sort"
 	"strings"
 
+	"k8s.io/apimachinery/pkg/api/equality"
 	"k8s.io/apimachinery/pkg/util/validation/field"
 
 	commonv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/common/v1"
 	esv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/elasticsearch/v1"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/license"
+	common_name "github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/name"
+	common "github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/settings"
 	stackmon "github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/stackmon/validations"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/version"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/elasticsearch/snapshot"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/elasticsearch/stackconfig"
 	esversion "github.com/elastic/cloud-on-k8s/v2/pkg/controller/elasticsearch/version"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/utils/k8s"
 	ulog "github.com/elastic/cloud-on-k8s/v2/pkg/utils/log"
 	netutil "github.com/elastic/cloud-on-k8s/v2/pkg/utils/net"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/utils/stringsutil"
 )
 
 const (
-	autoscalingVersionMsg    = "autoscaling is not available in this version of Elasticsearch"
-	cfgInvalidMsg            = "Configuration invalid"
-	duplicateNodeSets        = "NodeSet names must be unique"
-	invalidNamesErrMsg       = "Elasticsearch configuration would generate resources with invalid names"
-	invalidSanIPErrMsg       = "Invalid SAN IP address. Must be a valid IPv4 address"
-	masterRequiredMsg        = "Elasticsearch needs to have at least one master node"
-	mixedRoleConfigMsg       = "Detected a combination of node.roles and %s. Use only node.roles"
-	noDowngradesMsg          = "Downgrades are not supported"
-	nodeRolesInOldVersionMsg = "node.roles setting is not available in this version of Elasticsearch"
-	parseStoredVersionErrMsg = "Cannot parse current Elasticsearch version. String format must be {major}.{minor}.{patch}[-{label}]"
-	parseVersionErrMsg       = "Cannot parse Elasticsearch version. String format must be {major}.{minor}.{patch}[-{label}]"
-	pvcImmutableErrMsg       = "volume claim templates can only have their storage requests increased, if the storage class allows volume expansion. Any other change is forbidden"
-	pvcNotMountedErrMsg      = "volume claim declared but volume not mounted in any container. Note that the Elasticsearch data volume should be named 'elasticsearch-data'"
-	unsupportedConfigErrMsg  = "Configuration setting is reserved for internal use. User-configured use is unsupported"
-	unsupportedUpgradeMsg    = "Unsupported version upgrade path. Check the Elasticsearch documentation for supported upgrade paths."
-	unsupportedVersionMsg    = "Unsupported version"
-	notAllowedNodesLabelMsg  = "Node label not in the exposed node labels list"
+	auditEventsOverlapMsg       = "event types cannot be both included and excluded"
+	autoscalingVersionMsg       = "autoscaling is not available in this version of Elasticsearch"
+	cfgInvalidMsg               = "Configuration invalid"
+	clusterSettingManagedMsg    = "Cluster setting is managed by the operator"
+	clusterSettingNodeSetMsg    = "Cluster setting is also set in the configuration of NodeSet %s"
+	componentTemplateVersionMsg = "component templates are not available in this version of Elasticsearch"
+	dataTierRolesMsg            = "node.roles must include %s and must not include the generic data role or the roles of other data tiers"
+	dataTierVersionMsg          = "data tier %s is not available in this version of Elasticsearch"
+	definitionSourceMsg         = "Exactly one of definition and configMapName must be set"
+	duplicateNodeSets           = "NodeSet names must be unique"
+	initialRestoreImmutableMsg  = "initialRestore can only be set at creation, or changed to retry a failed restore"
+	invalidNamesErrMsg          = "Elasticsearch configuration would generate resources with invalid names"
+	invalidSanIPErrMsg          = "Invalid SAN IP address. Must be a valid IPv4 address"
+	jvmOptionInvalidMsg         = "JVM options must be non-empty and fit on a single line"
+	jvmOptionsVersionMsg        = "JVM options are not supported in this version of Elasticsearch"
+	indexTemplateVersionMsg     = "composable index templates are not available in this version of Elasticsearch"
+	ldapBindPasswordMsg         = "bindPasswordSecretRef requires bindDN to be set"
+	ldapDomainMsg               = "domain must be set for Active Directory realms"
+	ldapURLsMsg                 = "urls must be set for LDAP realms"
+	ldapUserDNTemplatesMsg      = "userDNTemplates are only supported by LDAP realms, and cannot be combined with userSearchBaseDN"
+	ldapVersionMsg              = "LDAP realms are not supported in this version of Elasticsearch"
+	masterRequiredMsg           = "Elasticsearch needs to have at least one master node"
+	memoryDataVolumeConflictMsg = "memoryDataVolume cannot be combined with a volume claim template or a podTemplate volume named 'elasticsearch-data'"
+	memoryDataVolumeSizeMsg     = "sizeLimit must be greater than zero"
+	nativeUserPasswordMsg       = "passwordSecretRef must reference a secret"
+	nativeUserReservedMsg       = "User name is reserved for built-in users and users managed by the operator"
+	mixedRoleConfigMsg          = "Detected a combination of node.roles and %s. Use only node.roles"
+	noDowngradesMsg             = "Downgrades are not supported"
+	nodeAttributeInvalidMsg     = "Node attribute names must be non-empty and only contain alphanumeric characters, '-', '_' and '.'"
+	nodeAttributeReservedMsg    = "Node attribute is managed by the operator"
+	nodeRolesInOldVersionMsg    = "node.roles setting is not available in this version of Elasticsearch"
+	oidcClientSecretMsg         = "clientSecretRef must reference the secret holding the client secret"
+	oidcVersionMsg              = "OIDC realms are not supported in this version of Elasticsearch"
+	parseStoredVersionErrMsg    = "Cannot parse current Elasticsearch version. String format must be {major}.{minor}.{patch}[-{label}]"
+	parseVersionErrMsg          = "Cannot parse Elasticsearch version. String format must be {major}.{minor}.{patch}[-{label}]"
+	pluginChecksumMsg           = "A checksum can only be verified for plugins installed from a URL"
+	pvcImmutableErrMsg          = "volume claim templates can only have their storage requests increased, if the storage class allows volume expansion. Any other change is forbidden, unless the eck.k8s.elastic.co/storage-migration annotation is set to \"true\""
+	realmOrderMsg               = "order must be unique across realms, already used by realm %s"
+	reservedNameSuffixMsg       = "name must not end with %s, which is reserved to the restore verification clusters"
+	restoreVerificationNameMsg  = "name is too long to create the restore verification cluster %s, must be no more than %d characters"
+	restoreVerificationRepoMsg  = "repository must be declared in snapshotRepositories"
+	samlMetadataSourceMsg       = "Exactly one of metadataConfigMapName and metadataURL must be set"
+	samlVersionMsg              = "SAML realms are not supported in this version of Elasticsearch"
+	snapshotRetentionCountMsg   = "minCount must not be greater than maxCount"
+	snapshotVolumeSourceMsg     = "Exactly one of claimName and nfs must be set"
+	pvcNotMountedErrMsg         = "volume claim declared but volume not mounted in any container. Note that the Elasticsearch data volume should be named 'elasticsearch-data'"
+	unsupportedConfigErrMsg     = "Configuration setting is reserved for internal use. User-configured use is unsupported"
+	unsupportedUpgradeMsg       = "Unsupported version upgrade path. Check the Elasticsearch documentation for supported upgrade paths."
+	unsupportedVersionMsg       = "Unsupported version"
+	notAllowedNodesLabelMsg     = "Node label not in the exposed node labels list"
 )
 
 type validation func(esv1.Elasticsearch) field.ErrorList
 
 type updateValidation func(esv1.Elasticsearch, esv1.Elasticsearch) field.ErrorList
 
+// createValidations are the validation funcs that only apply to creates
+var createValidations = []validation{
+	noUnsupportedSettings,
+}
+
 // updateValidations are the validation funcs that only apply to updates
 func updateValidations(ctx context.Context, k8sClient k8s.Client, validateStorageClass bool) []updateValidation {
 	return []updateValidation{
 		noDowngrades,
 		validUpgradePath,
+		noNewUnsupportedSettings,
+		validInitialRestoreChange,
 		func(current esv1.Elasticsearch, proposed esv1.Elasticsearch) field.ErrorList {
 			return validPVCModification(ctx, current, proposed, k8sClient, validateStorageClass)
 		},
@@ -67,8 +115,27 @@ func validations(ctx context.Context, checker license.Checker, exposedNodeLabels
 		noUnknownFields,
 		validName,
 		hasCorrectNodeRoles,
+		validDataTiers,
 		supportedVersion,
 		validSanIP,
+		validPlugins,
+		validSnapshotVolumes,
+		validSnapshotRepositories,
+		validRestoreVerification,
+		validIngestPipelines,
+		validClusterSettings,
+		validComponentTemplates,
+		validIndexTemplates,
+		validILMPolicies,
+		validNativeUsers,
+		validSAMLRealms,
+		validOIDCRealms,
+		validLDAPRealms,
+		validRealmOrders,
+		validAuditLogging,
+		validJVMOptions,
+		validMemoryDataVolumes,
+		validNodeAttributes,
 		validAutoscalingConfiguration,
 		validPVCNaming,
 		validMonitoring,
@@ -154,14 +221,14 @@ func hasCorrectNodeRoles(es esv1.Elasticsearch) field.ErrorList {
 
 	for i, ns := range es.Spec.NodeSets {
 		cfg := esv1.ElasticsearchSettings{}
-		if err := esv1.UnpackConfig(ns.Config, v, &cfg); err != nil {
+		if err := esv1.UnpackNodeSetConfig(ns, v, &cfg); err != nil {
 			errs = append(errs, field.Invalid(confField(i), ns.Config, cfgInvalidMsg))
 
 			continue
 		}
 
-		// check that node.roles is not used with an older Elasticsearch version
-		if cfg.Node != nil && cfg.Node.Roles != nil && !v.GTE(version.From(7, 9, 0)) {
+		// check that node.roles is not used with an older Elasticsearch version, data tiers are checked in validDataTiers
+		if ns.DataTier == "" && cfg.Node != nil && cfg.Node.Roles != nil && !v.GTE(version.From(7, 9, 0)) {
 			errs = append(errs, field.Invalid(confField(i), ns.Config, nodeRolesInOldVersionMsg))
 
 			continue
@@ -184,6 +251,50 @@ func hasCorrectNodeRoles(es esv1.Elasticsearch) field.ErrorList {
 	return errs
 }
 
+// validDataTiers checks that data tiers are supported by the Elasticsearch version and that they are consistent with
+// node.roles if it is explicitly set in the configuration of the NodeSet.
+func validDataTiers(es esv1.Elasticsearch) field.ErrorList {
+	v, err := version.Parse(es.Spec.Version)
+	if err != nil {
+		return field.ErrorList{field.Invalid(field.NewPath("spec").Child("version"), es.Spec.Version, parseVersionErrMsg)}
+	}
+
+	var errs field.ErrorList
+	for i, ns := range es.Spec.NodeSets {
+		if ns.DataTier == "" {
+			continue
+		}
+		tierField := field.NewPath("spec").Child("nodeSets").Index(i).Child("dataTier")
+		if !v.GTE(ns.DataTier.MinVersion()) {
+			errs = append(errs, field.Invalid(tierField, ns.DataTier, fmt.Sprintf(dataTierVersionMsg, ns.DataTier)))
+			continue
+		}
+
+		cfg := esv1.ElasticsearchSettings{}
+		if err := esv1.UnpackConfig(ns.Config, v, &cfg); err != nil {
+			// reported by hasCorrectNodeRoles
+			continue
+		}
+		if cfg.Node == nil || cfg.Node.Roles == nil {
+			// roles are derived from the data tier
+			continue
+		}
+		if !cfg.Node.HasRole(ns.DataTier.Role()) || cfg.Node.HasRole(esv1.DataRole) || hasOtherDataTierRole(cfg.Node, ns.DataTier) {
+			errs = append(errs, field.Invalid(tierField, ns.DataTier, fmt.Sprintf(dataTierRolesMsg, ns.DataTier.Role())))
+		}
+	}
+	return errs
+}
+
+func hasOtherDataTierRole(node *esv1.Node, dataTier esv1.DataTier) bool {
+	for _, tier := range esv1.DataTiers {
+		if tier != dataTier && node.HasRole(tier.Role()) {
+			return true
+		}
+	}
+	return false
+}
+
 func getNodeRoleAttrs(cfg esv1.ElasticsearchSettings) []string {
 	var nodeRoleAttrs []string
 
@@ -237,6 +348,577 @@ func validSanIP(es esv1.Elasticsearch) field.ErrorList {
 	return errs
 }
 
+// validPlugins checks that plugins are declared only once, and that checksums are only set for plugins installed from
+// a URL since official plugins are already verified by the plugin tool.
+func validPlugins(es esv1.Elasticsearch) field.ErrorList {
+	var errs field.ErrorList
+	names := make(map[string]struct{}, len(es.Spec.Plugins))
+	for i, plugin := range es.Spec.Plugins {
+		path := field.NewPath("spec").Child("plugins").Index(i)
+		if _, exists := names[plugin.Name]; exists {
+			errs = append(errs, field.Duplicate(path.Child("name"), plugin.Name))
+		}
+		names[plugin.Name] = struct{}{}
+		if plugin.SHA512 != "" && !plugin.IsURL() {
+			errs = append(errs, field.Invalid(path.Child("sha512"), plugin.SHA512, pluginChecksumMsg))
+		}
+	}
+	return errs
+}
+
+// validSnapshotVolumes checks that snapshot volumes are declared only once, each with a single volume source.
+func validSnapshotVolumes(es esv1.Elasticsearch) field.ErrorList {
+	var errs field.ErrorList
+	names := make(map[string]struct{}, len(es.Spec.SnapshotVolumes))
+	for i, v := range es.Spec.SnapshotVolumes {
+		path := field.NewPath("spec").Child("snapshotVolumes").Index(i)
+		if _, exists := names[v.Name]; exists {
+			errs = append(errs, field.Duplicate(path.Child("name"), v.Name))
+		}
+		names[v.Name] = struct{}{}
+		if (v.ClaimName == "") == (v.NFS == nil) {
+			errs = append(errs, field.Invalid(path, v.Name, snapshotVolumeSourceMsg))
+		}
+	}
+	return errs
+}
+
+// validSnapshotRepositories checks that snapshot repositories are declared only once, and that their schedule is valid.
+func validSnapshotRepositories(es esv1.Elasticsearch) field.ErrorList {
+	var errs field.ErrorList
+	names := make(map[string]struct{}, len(es.Spec.SnapshotRepositories))
+	for i, repository := range es.Spec.SnapshotRepositories {
+		path := field.NewPath("spec").Child("snapshotRepositories").Index(i)
+		if _, exists := names[repository.Name]; exists {
+			errs = append(errs, field.Duplicate(path.Child("name"), repository.Name))
+		}
+		names[repository.Name] = struct{}{}
+		if repository.Schedule != nil {
+			errs = append(errs, validSnapshotSchedule(es, *repository.Schedule, path.Child("schedule"))...)
+		}
+	}
+	return errs
+}
+
+// validIngestPipelines checks that ingest pipelines are declared only once, each with a single source of definition.
+func validIngestPipelines(es esv1.Elasticsearch) field.ErrorList {
+	var errs field.ErrorList
+	names := make(map[string]struct{}, len(es.Spec.IngestPipelines))
+	for i, pipeline := range es.Spec.IngestPipelines {
+		path := field.NewPath("spec").Child("ingestPipelines").Index(i)
+		if _, exists := names[pipeline.Name]; exists {
+			errs = append(errs, field.Duplicate(path.Child("name"), pipeline.Name))
+		}
+		names[pipeline.Name] = struct{}{}
+		if (pipeline.Definition == nil) == (pipeline.ConfigMapName == "") {
+			errs = append(errs, field.Invalid(path, pipeline.Name, definitionSourceMsg))
+		}
+	}
+	return errs
+}
+
+var composableIndexTemplatesMinVersion = version.From(7, 8, 0)
+
+// validComponentTemplates checks that component templates are declared only once, each with a single source of
+// definition, and that they are supported by the Elasticsearch version.
+func validComponentTemplates(es esv1.Elasticsearch) field.ErrorList {
+	if len(es.Spec.ComponentTemplates) == 0 {
+		return nil
+	}
+	var errs field.ErrorList
+	if v, err := version.Parse(es.Spec.Version); err == nil && v.LT(composableIndexTemplatesMinVersion) {
+		errs = append(errs, field.Forbidden(field.NewPath("spec").Child("componentTemplates"), componentTemplateVersionMsg))
+	}
+	names := make(map[string]struct{}, len(es.Spec.ComponentTemplates))
+	for i, template := range es.Spec.ComponentTemplates {
+		path := field.NewPath("spec").Child("componentTemplates").Index(i)
+		if _, exists := names[template.Name]; exists {
+			errs = append(errs, field.Duplicate(path.Child("name"), template.Name))
+		}
+		names[template.Name] = struct{}{}
+		if (template.Definition == nil) == (template.ConfigMapName == "") {
+			errs = append(errs, field.Invalid(path, template.Name, definitionSourceMsg))
+		}
+	}
+	return errs
+}
+
+// validIndexTemplates checks that index templates are declared only once for each type, each with a single source of
+// definition, and that composable index templates are supported by the Elasticsearch version.
+func validIndexTemplates(es esv1.Elasticsearch) field.ErrorList {
+	var errs field.ErrorList
+	v, err := version.Parse(es.Spec.Version)
+	composableSupported := err != nil || v.GTE(composableIndexTemplatesMinVersion)
+	names := make(map[esv1.IndexTemplateType]map[string]struct{}, 2)
+	for i, template := range es.Spec.IndexTemplates {
+		path := field.NewPath("spec").Child("indexTemplates").Index(i)
+		templateType := template.TypeOrDefault()
+		if names[templateType] == nil {
+			names[templateType] = make(map[string]struct{})
+		}
+		if _, exists := names[templateType][template.Name]; exists {
+			errs = append(errs, field.Duplicate(path.Child("name"), template.Name))
+		}
+		names[templateType][template.Name] = struct{}{}
+		if (template.Definition == nil) == (template.ConfigMapName == "") {
+			errs = append(errs, field.Invalid(path, template.Name, definitionSourceMsg))
+		}
+		if templateType == esv1.ComposableIndexTemplateType && !composableSupported {
+			errs = append(errs, field.Forbidden(path.Child("type"), indexTemplateVersionMsg))
+		}
+	}
+	return errs
+}
+
+// validILMPolicies checks that ILM policies are declared only once, each with a single source of definition.
+func validILMPolicies(es esv1.Elasticsearch) field.ErrorList {
+	var errs field.ErrorList
+	names := make(map[string]struct{}, len(es.Spec.ILMPolicies))
+	for i, policy := range es.Spec.ILMPolicies {
+		path := field.NewPath("spec").Child("ilmPolicies").Index(i)
+		if _, exists := names[policy.Name]; exists {
+			errs = append(errs, field.Duplicate(path.Child("name"), policy.Name))
+		}
+		names[policy.Name] = struct{}{}
+		if (policy.Definition == nil) == (policy.ConfigMapName == "") {
+			errs = append(errs, field.Invalid(path, policy.Name, definitionSourceMsg))
+		}
+	}
+	return errs
+}
+
+// operatorClusterSettings are the cluster settings, or prefixes of cluster settings, updated by the operator to
+// orchestrate the cluster.
+var operatorClusterSettings = []string{
+	"cluster.remote.",
+	"cluster.routing.allocation.enable",
+	"cluster.routing.allocation.exclude._name",
+}
+
+// validClusterSettings checks that the declared cluster settings can be parsed, are not updated by the operator, and
+// are not also set in the configuration of the nodes where they would be applied as well.
+func validClusterSettings(es esv1.Elasticsearch) field.ErrorList {
+	if es.Spec.ClusterSettings == nil {
+		return nil
+	}
+	path := field.NewPath("spec").Child("clusterSettings", "persistent")
+	settings, err := stackconfig.ClusterSettings(es.Spec.ClusterSettings.Persistent)
+	if err != nil {
+		return field.ErrorList{field.Invalid(path, es.Spec.ClusterSettings.Persistent, cfgInvalidMsg)}
+	}
+	keys := make([]string, 0, len(settings))
+	for key := range settings {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	var errs field.ErrorList
+	for _, key := range keys {
+		for _, managed := range operatorClusterSettings {
+			if key == managed || (strings.HasSuffix(managed, ".") && strings.HasPrefix(key, managed)) {
+				errs = append(errs, field.Forbidden(path.Child(key), clusterSettingManagedMsg))
+			}
+		}
+	}
+	for _, nodeSet := range es.Spec.NodeSets {
+		// invalid configurations are reported by the validation of the NodeSets
+		nodeSettings, _ := stackconfig.ClusterSettings(nodeSet.Config)
+		for _, key := range keys {
+			if _, exists := nodeSettings[key]; exists {
+				errs = append(errs, field.Invalid(path.Child(key), settings[key], fmt.Sprintf(clusterSettingNodeSetMsg, nodeSet.Name)))
+			}
+		}
+	}
+	return errs
+}
+
+// validNativeUsers checks that native users are declared only once, do not use a reserved name, and reference a
+// password secret.
+func validNativeUsers(es esv1.Elasticsearch) field.ErrorList {
+	var errs field.ErrorList
+	names := make(map[string]struct{}, len(es.Spec.Auth.Users))
+	for i, u := range es.Spec.Auth.Users {
+		path := field.NewPath("spec").Child("auth", "users").Index(i)
+		if _, exists := names[u.Name]; exists {
+			errs = append(errs, field.Duplicate(path.Child("name"), u.Name))
+		}
+		names[u.Name] = struct{}{}
+		if esv1.ReservedUserNames.Has(u.Name) {
+			errs = append(errs, field.Forbidden(path.Child("name"), nativeUserReservedMsg))
+		}
+		if u.PasswordSecretRef.SecretName == "" {
+			errs = append(errs, field.Required(path.Child("passwordSecretRef", "secretName"), nativeUserPasswordMsg))
+		}
+	}
+	return errs
+}
+
+// validSAMLRealms checks that SAML realms are declared only once, each with a single source of identity provider
+// metadata, and that they use the 7.x realm settings syntax.
+func validSAMLRealms(es esv1.Elasticsearch) field.ErrorList {
+	if len(es.Spec.Auth.SAML) == 0 {
+		return nil
+	}
+	var errs field.ErrorList
+	if v, err := version.Parse(es.Spec.Version); err == nil && v.Major < 7 {
+		errs = append(errs, field.Forbidden(field.NewPath("spec").Child("auth", "saml"), samlVersionMsg))
+	}
+	names := make(map[string]struct{}, len(es.Spec.Auth.SAML))
+	for i, realm := range es.Spec.Auth.SAML {
+		path := field.NewPath("spec").Child("auth", "saml").Index(i)
+		if _, exists := names[realm.Name]; exists {
+			errs = append(errs, field.Duplicate(path.Child("name"), realm.Name))
+		}
+		names[realm.Name] = struct{}{}
+		if (realm.IdP.MetadataConfigMapName == "") == (realm.IdP.MetadataURL == "") {
+			errs = append(errs, field.Invalid(path.Child("idp"), realm.Name, samlMetadataSourceMsg))
+		}
+	}
+	return errs
+}
+
+var oidcMinVersion = version.From(7, 2, 0)
+
+// validOIDCRealms checks that OIDC realms are declared only once, with a name not already used by a SAML realm, and
+// that they reference their client secret.
+func validOIDCRealms(es esv1.Elasticsearch) field.ErrorList {
+	if len(es.Spec.Auth.OIDC) == 0 {
+		return nil
+	}
+	var errs field.ErrorList
+	if v, err := version.Parse(es.Spec.Version); err == nil && v.LT(oidcMinVersion) {
+		errs = append(errs, field.Forbidden(field.NewPath("spec").Child("auth", "oidc"), oidcVersionMsg))
+	}
+	names := make(map[string]struct{}, len(es.Spec.Auth.SAML)+len(es.Spec.Auth.OIDC))
+	for _, realm := range es.Spec.Auth.SAML {
+		names[realm.Name] = struct{}{}
+	}
+	for i, realm := range es.Spec.Auth.OIDC {
+		path := field.NewPath("spec").Child("auth", "oidc").Index(i)
+		if _, exists := names[realm.Name]; exists {
+			errs = append(errs, field.Duplicate(path.Child("name"), realm.Name))
+		}
+		names[realm.Name] = struct{}{}
+		if realm.RP.ClientSecretRef.SecretName == "" {
+			errs = append(errs, field.Required(path.Child("rp", "clientSecretRef"), oidcClientSecretMsg))
+		}
+	}
+	return errs
+}
+
+// validLDAPRealms checks that LDAP realms are declared only once, with a name not already used by a SAML or OIDC realm,
+// and that they are configured with the settings required by their type.
+func validLDAPRealms(es esv1.Elasticsearch) field.ErrorList {
+	if len(es.Spec.Auth.LDAP) == 0 {
+		return nil
+	}
+	var errs field.ErrorList
+	if v, err := version.Parse(es.Spec.Version); err == nil && v.Major < 7 {
+		errs = append(errs, field.Forbidden(field.NewPath("spec").Child("auth", "ldap"), ldapVersionMsg))
+	}
+	names := make(map[string]struct{}, len(es.Spec.Auth.SAML)+len(es.Spec.Auth.OIDC)+len(es.Spec.Auth.LDAP))
+	for _, realm := range es.Spec.Auth.SAML {
+		names[realm.Name] = struct{}{}
+	}
+	for _, realm := range es.Spec.Auth.OIDC {
+		names[realm.Name] = struct{}{}
+	}
+	for i, realm := range es.Spec.Auth.LDAP {
+		path := field.NewPath("spec").Child("auth", "ldap").Index(i)
+		if _, exists := names[realm.Name]; exists {
+			errs = append(errs, field.Duplicate(path.Child("name"), realm.Name))
+		}
+		names[realm.Name] = struct{}{}
+		switch realm.RealmType() {
+		case esv1.LDAPRealmTypeLDAP:
+			if len(realm.URLs) == 0 {
+				errs = append(errs, field.Required(path.Child("urls"), ldapURLsMsg))
+			}
+			if len(realm.UserDNTemplates) > 0 && realm.UserSearchBaseDN != "" {
+				errs = append(errs, field.Invalid(path.Child("userDNTemplates"), realm.UserDNTemplates, ldapUserDNTemplatesMsg))
+			}
+		case esv1.LDAPRealmTypeActiveDirectory:
+			if realm.Domain == "" {
+				errs = append(errs, field.Required(path.Child("domain"), ldapDomainMsg))
+			}
+			if len(realm.UserDNTemplates) > 0 {
+				errs = append(errs, field.Invalid(path.Child("userDNTemplates"), realm.UserDNTemplates, ldapUserDNTemplatesMsg))
+			}
+		}
+		if realm.BindPasswordSecretRef != nil && realm.BindPasswordSecretRef.SecretName != "" && realm.BindDN == "" {
+			errs = append(errs, field.Required(path.Child("bindDN"), ldapBindPasswordMsg))
+		}
+	}
+	return errs
+}
+
+// operatorRealmOrders are the orders of the file and native realms configured by the operator, by realm.
+var operatorRealmOrders = map[string]int64{
+	"file.file1":     -100,
+	"native.native1": -99,
+}
+
+// validRealmOrders checks that the realms declared in the spec have an order which is not already used by another
+// realm, either declared in the spec, in the configuration of the NodeSets, or configured by the operator.
+// Elasticsearch fails to start if two realms have the same order.
+func validRealmOrders(es esv1.Elasticsearch) field.ErrorList {
+	if len(es.Spec.Auth.SAML)+len(es.Spec.Auth.OIDC)+len(es.Spec.Auth.LDAP) == 0 {
+		return nil
+	}
+	realmsByOrder := make(map[int64]string)
+	for realm, order := range operatorRealmOrders {
+		realmsByOrder[order] = realm
+	}
+	for _, nodeSet := range es.Spec.NodeSets {
+		for realm, order := range configuredRealmOrders(nodeSet) {
+			realmsByOrder[order] = realm
+		}
+	}
+
+	var errs field.ErrorList
+	checkOrder := func(path *field.Path, realm string, order int32) {
+		if other, exists := realmsByOrder[int64(order)]; exists && other != realm {
+			errs = append(errs, field.Invalid(path.Child("order"), order, fmt.Sprintf(realmOrderMsg, other)))
+			return
+		}
+		realmsByOrder[int64(order)] = realm
+	}
+	for i, realm := range es.Spec.Auth.SAML {
+		checkOrder(field.NewPath("spec").Child("auth", "saml").Index(i), "saml."+realm.Name, realm.Order)
+	}
+	for i, realm := range es.Spec.Auth.OIDC {
+		checkOrder(field.NewPath("spec").Child("auth", "oidc").Index(i), "oidc."+realm.Name, realm.Order)
+	}
+	for i, realm := range es.Spec.Auth.LDAP {
+		checkOrder(field.NewPath("spec").Child("auth", "ldap").Index(i), string(realm.RealmType())+"."+realm.Name, realm.Order)
+	}
+	return errs
+}
+
+// configuredRealmOrders returns the order of the realms declared with the 7.x realm settings syntax in the
+// configuration of the given NodeSet, by realm type and name.
+func configuredRealmOrders(nodeSet esv1.NodeSet) map[string]int64 {
+	if nodeSet.Config == nil {
+		return nil
+	}
+	config, err := common.NewCanonicalConfigFrom(nodeSet.Config.Data)
+	if err != nil {
+		// invalid configurations are reported by other validations
+		return nil
+	}
+	var unpacked map[string]interface{}
+	if err := config.Unpack(&unpacked); err != nil {
+		return nil
+	}
+	realmsByType := nestedMap(unpacked, "xpack", "security", "authc", "realms")
+	orders := make(map[string]int64)
+	for realmType := range realmsByType {
+		for realmName := range nestedMap(realmsByType, realmType) {
+			if order, ok := toInt64(nestedMap(realmsByType, realmType, realmName)["order"]); ok {
+				orders[realmType+"."+realmName] = order
+			}
+		}
+	}
+	return orders
+}
+
+// nestedMap returns the map at the given path in the given unpacked configuration, or nil if there is none.
+func nestedMap(m map[string]interface{}, path ...string) map[string]interface{} {
+	for _, key := range path {
+		child, ok := m[key].(map[string]interface{})
+		if !ok {
+			return nil
+		}
+		m = child
+	}
+	return m
+}
+
+// toInt64 converts the given number of an unpacked configuration to an int64.
+func toInt64(value interface{}) (int64, bool) {
+	switch v := value.(type) {
+	case int64:
+		return v, true
+	case uint64:
+		return int64(v), true
+	case float64:
+		return int64(v), true
+	case int:
+		return int64(v), true
+	default:
+		return 0, false
+	}
+}
+
+// auditEventTypes are the types of events which can be included in or excluded from the audit log.
+var auditEventTypes = []string{
+	"_all",
+	"access_denied",
+	"access_granted",
+	"anonymous_access_denied",
+	"authentication_failed",
+	"authentication_success",
+	"connection_denied",
+	"connection_granted",
+	"realm_authentication_failed",
+	"run_as_denied",
+	"run_as_granted",
+	"security_config_change",
+	"system_access_granted",
+	"tampered_request",
+}
+
+// validAuditLogging checks that the audit log only filters known event types, without excluding included ones, and
+// that its ignore filters are declared only once.
+func validAuditLogging(es esv1.Elasticsearch) field.ErrorList {
+	audit := es.Spec.AuditLogging
+	if audit == nil {
+		return nil
+	}
+	var errs field.ErrorList
+	path := field.NewPath("spec").Child("auditLogging")
+	for i, event := range audit.IncludeEvents {
+		if !stringsutil.StringInSlice(event, auditEventTypes) {
+			errs = append(errs, field.NotSupported(path.Child("includeEvents").Index(i), event, auditEventTypes))
+		}
+	}
+	for i, event := range audit.ExcludeEvents {
+		if !stringsutil.StringInSlice(event, auditEventTypes) {
+			errs = append(errs, field.NotSupported(path.Child("excludeEvents").Index(i), event, auditEventTypes))
+		}
+		if stringsutil.StringInSlice(event, audit.IncludeEvents) {
+			errs = append(errs, field.Invalid(path.Child("excludeEvents").Index(i), event, auditEventsOverlapMsg))
+		}
+	}
+	names := make(map[string]struct{}, len(audit.IgnoreFilters))
+	for i, filter := range audit.IgnoreFilters {
+		if _, exists := names[filter.Name]; exists {
+			errs = append(errs, field.Duplicate(path.Child("ignoreFilters").Index(i).Child("name"), filter.Name))
+		}
+		names[filter.Name] = struct{}{}
+	}
+	return errs
+}
+
+// validSnapshotSchedule checks the retention of a snapshot schedule, and its cron expression if the snapshots are taken
+// by the operator rather than by an SLM policy.
+func validSnapshotSchedule(es esv1.Elasticsearch, schedule esv1.SnapshotSchedule, path *field.Path) field.ErrorList {
+	var errs field.ErrorList
+	v, err := version.Parse(es.Spec.Version)
+	if err == nil && !snapshot.IsSLMSupported(v) {
+		if _, err := snapshot.ParseCron(schedule.Cron); err != nil {
+			errs = append(errs, field.Invalid(path.Child("cron"), schedule.Cron, err.Error()))
+		}
+	}
+	retention := schedule.Retention
+	if retention == nil {
+		return errs
+	}
+	if retention.ExpireAfter != "" {
+		if _, err := snapshot.ParseTimeValue(retention.ExpireAfter); err != nil {
+			errs = append(errs, field.Invalid(path.Child("retention", "expireAfter"), retention.ExpireAfter, err.Error()))
+		}
+	}
+	if retention.MinCount != nil && retention.MaxCount != nil && *retention.MinCount > *retention.MaxCount {
+		errs = append(errs, field.Invalid(path.Child("retention", "minCount"), *retention.MinCount, snapshotRetentionCountMsg))
+	}
+	return errs
+}
+
+// validRestoreVerification checks that the name of the cluster does not end with the suffix reserved to the
+// verification clusters and, if a restore verification is declared, that its repository is declared, that its cron
+// expression is valid, and that the name of the verification cluster is not too long.
+func validRestoreVerification(es esv1.Elasticsearch) field.ErrorList {
+	var errs field.ErrorList
+	if snapshot.HasReservedName(es) {
+		errs = append(errs, field.Invalid(field.NewPath("metadata").Child("name"), es.Name,
+			fmt.Sprintf(reservedNameSuffixMsg, snapshot.VerificationClusterSuffix)))
+	}
+	verification := es.Spec.RestoreVerification
+	if verification == nil {
+		return errs
+	}
+	path := field.NewPath("spec").Child("restoreVerification")
+	declared := false
+	for _, repository := range es.Spec.SnapshotRepositories {
+		declared = declared || repository.Name == verification.Repository
+	}
+	if !declared {
+		errs = append(errs, field.Invalid(path.Child("repository"), verification.Repository, restoreVerificationRepoMsg))
+	}
+	if _, err := snapshot.ParseCron(verification.Cron); err != nil {
+		errs = append(errs, field.Invalid(path.Child("cron"), verification.Cron, err.Error()))
+	}
+	if name := snapshot.VerificationClusterName(es.Name); len(name) > common_name.MaxResourceNameLength {
+		errs = append(errs, field.Invalid(field.NewPath("metadata").Child("name"), es.Name,
+			fmt.Sprintf(restoreVerificationNameMsg, name, common_name.MaxResourceNameLength)))
+	}
+	return errs
+}
+
+// minJVMOptionsVersion is the first version of Elasticsearch reading JVM options from the jvm.options.d directory.
+var minJVMOptionsVersion = version.From(7, 7, 0)
+
+func validJVMOptions(es esv1.Elasticsearch) field.ErrorList {
+	v, err := version.Parse(es.Spec.Version)
+	if err != nil {
+		return field.ErrorList{field.Invalid(field.NewPath("spec").Child("version"), es.Spec.Version, parseVersionErrMsg)}
+	}
+
+	var errs field.ErrorList
+	for i, ns := range es.Spec.NodeSets {
+		if len(ns.JVMOptions) == 0 {
+			continue
+		}
+		optionsField := field.NewPath("spec").Child("nodeSets").Index(i).Child("jvmOptions")
+		if v.LT(minJVMOptionsVersion) {
+			errs = append(errs, field.Invalid(optionsField, ns.JVMOptions, jvmOptionsVersionMsg))
+			continue
+		}
+		for j, option := range ns.JVMOptions {
+			if strings.TrimSpace(option) == "" || strings.ContainsAny(option, "\r\n") {
+				errs = append(errs, field.Invalid(optionsField.Index(j), option, jvmOptionInvalidMsg))
+			}
+		}
+	}
+	return errs
+}
+
+func validMemoryDataVolumes(es esv1.Elasticsearch) field.ErrorList {
+	var errs field.ErrorList
+	for i, ns := range es.Spec.NodeSets {
+		if ns.MemoryDataVolume == nil {
+			continue
+		}
+		volumeField := field.NewPath("spec").Child("nodeSets").Index(i).Child("memoryDataVolume")
+		if ns.MemoryDataVolume.SizeLimit.Sign() <= 0 {
+			errs = append(errs, field.Invalid(volumeField.Child("sizeLimit"), ns.MemoryDataVolume.SizeLimit.String(), memoryDataVolumeSizeMsg))
+		}
+		if hasDefaultClaim(ns.VolumeClaimTemplates) || hasDataVolume(ns.PodTemplate.Spec.Volumes) {
+			errs = append(errs, field.Forbidden(volumeField, memoryDataVolumeConflictMsg))
+		}
+	}
+	return errs
+}
+
+// nodeAttributeNameRegexp matches the names of custom node attributes, which may be dot-separated.
+var nodeAttributeNameRegexp = regexp.MustCompile(`^[a-zA-Z0-9_-]+(\.[a-zA-Z0-9_-]+)*$`)
+
+func validNodeAttributes(es esv1.Elasticsearch) field.ErrorList {
+	var errs field.ErrorList
+	for i, ns := range es.Spec.NodeSets {
+		attributesField := field.NewPath("spec").Child("nodeSets").Index(i).Child("attributes")
+		for name := range ns.Attributes {
+			switch {
+			case !nodeAttributeNameRegexp.MatchString(name):
+				errs = append(errs, field.Invalid(attributesField.Key(name), name, nodeAttributeInvalidMsg))
+			case name == esv1.K8sNodeNameAttr || (name == esv1.DataTierAttr && ns.DataTier != ""):
+				errs = append(errs, field.Forbidden(attributesField.Key(name), nodeAttributeReservedMsg))
+			}
+		}
+	}
+	return errs
+}
+
 func checkNodeSetNameUniqueness(es esv1.Elasticsearch) field.ErrorList {
 	var errs field.ErrorList
 	nodeSets := es.Spec.NodeSets
@@ -254,6 +936,45 @@ func checkNodeSetNameUniqueness(es esv1.Elasticsearch) field.ErrorList {
 	return errs
 }
 
+// validInitialRestoreChange prevents the initial restore from being added to an existing cluster, or from being changed
+// once the restore started successfully. It can be changed before the restore starts or after it failed, and removed.
+func validInitialRestoreChange(current, proposed esv1.Elasticsearch) field.ErrorList {
+	if proposed.Spec.InitialRestore == nil || equality.Semantic.DeepEqual(current.Spec.InitialRestore, proposed.Spec.InitialRestore) {
+		return nil
+	}
+	if current.Spec.InitialRestore != nil &&
+		(current.Status.InitialRestore == nil || current.Status.InitialRestore.Phase == esv1.InitialRestoreFailed) {
+		return nil
+	}
+	return field.ErrorList{field.Forbidden(field.NewPath("spec").Child("initialRestore"), initialRestoreImmutableMsg)}
+}
+
+// noNewUnsupportedSettings prevents settings reserved for internal use from being added to the configuration of a
+// NodeSet. Settings already present in the current configuration are only reported as warnings, to not block updates
+// of existing clusters.
+func noNewUnsupportedSettings(current, proposed esv1.Elasticsearch) field.ErrorList {
+	currentSettings := make(map[string][]string, len(current.Spec.NodeSets))
+	for _, nodeSet := range current.Spec.NodeSets {
+		// an invalid current configuration is reported by the validation of the proposed one
+		currentSettings[nodeSet.Name], _ = unsupportedSettings(nodeSet)
+	}
+	var errs field.ErrorList
+	for i, nodeSet := range proposed.Spec.NodeSets {
+		unsupported, err := unsupportedSettings(nodeSet)
+		if err != nil {
+			errs = append(errs, field.Invalid(field.NewPath("spec").Child("nodeSets").Index(i).Child("config"), nodeSet.Config, cfgInvalidMsg))
+			continue
+		}
+		for _, setting := range unsupported {
+			if stringsutil.StringInSlice(setting, currentSettings[nodeSet.Name]) {
+				continue
+			}
+			errs = append(errs, field.Forbidden(field.NewPath("spec").Child("nodeSets").Index(i).Child("config").Child(setting), unsupportedConfigErrMsg))
+		}
+	}
+	return errs
+}
+
 func noDowngrades(current, proposed esv1.Elasticsearch) field.ErrorList {
 	var errs field.ErrorList
 
@@ -9,10 +9,13 @@ import (
 
 	"github.com/stretchr/testify/assert"
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/pointer"
 
 	commonv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/common/v1"
 	esv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/elasticsearch/v1"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/elasticsearch/snapshot"
 )
 
 func Test_checkNodeSetNameUniqueness(t *testing.T) {
@@ -153,6 +156,15 @@ func Test_hasCorrectNodeRoles(t *testing.T) {
 			name: "valid configuration (node attributes)",
 			es:   esWithRoles("7.6.0", 3, m{esv1.NodeMaster: "true", esv1.NodeData: "true"}, m{esv1.NodeData: "true"}),
 		},
+		{
+			name: "data tier nodes are not master nodes",
+			es: func() esv1.Elasticsearch {
+				x := es("7.10.0")
+				x.Spec.NodeSets = []esv1.NodeSet{{Count: 3, DataTier: esv1.DataTierHot}}
+				return x
+			}(),
+			expectErrors: true,
+		},
 		{
 			name: "valid configuration (node roles)",
 			es:   esWithRoles("7.9.0", 4, m{esv1.NodeRoles: []esv1.NodeRole{esv1.MasterRole, esv1.DataRole}}, m{esv1.NodeRoles: []esv1.NodeRole{esv1.DataRole}}, m{esv1.NodeRoles: []esv1.NodeRole{esv1.RemoteClusterClientRole}}),
@@ -169,6 +181,73 @@ func Test_hasCorrectNodeRoles(t *testing.T) {
 	}
 }
 
+func Test_validDataTiers(t *testing.T) {
+	type m map[string]interface{}
+
+	esWithTier := func(version string, tier esv1.DataTier, cfg m) esv1.Elasticsearch {
+		x := es(version)
+		nodeSet := esv1.NodeSet{Count: 1, DataTier: tier}
+		if cfg != nil {
+			nodeSet.Config = &commonv1.Config{Data: cfg}
+		}
+		x.Spec.NodeSets = append(x.Spec.NodeSets, nodeSet)
+		return x
+	}
+
+	tests := []struct {
+		name         string
+		es           esv1.Elasticsearch
+		expectErrors bool
+	}{
+		{
+			name: "no data tier",
+			es:   esWithTier("7.6.0", "", nil),
+		},
+		{
+			name: "data tier without node roles",
+			es:   esWithTier("7.10.0", esv1.DataTierWarm, nil),
+		},
+		{
+			name: "data tier with consistent node roles",
+			es:   esWithTier("7.10.0", esv1.DataTierHot, m{esv1.NodeRoles: []esv1.NodeRole{esv1.DataHotRole, esv1.DataContentRole, esv1.IngestRole}}),
+		},
+		{
+			name:         "data tier on older version",
+			es:           esWithTier("7.9.0", esv1.DataTierWarm, nil),
+			expectErrors: true,
+		},
+		{
+			name:         "frozen data tier on older version",
+			es:           esWithTier("7.11.0", esv1.DataTierFrozen, nil),
+			expectErrors: true,
+		},
+		{
+			name:         "node roles without the data tier role",
+			es:           esWithTier("7.10.0", esv1.DataTierCold, m{esv1.NodeRoles: []esv1.NodeRole{esv1.IngestRole}}),
+			expectErrors: true,
+		},
+		{
+			name:         "node roles with the generic data role",
+			es:           esWithTier("7.10.0", esv1.DataTierCold, m{esv1.NodeRoles: []esv1.NodeRole{esv1.DataColdRole, esv1.DataRole}}),
+			expectErrors: true,
+		},
+		{
+			name:         "node roles with the role of another data tier",
+			es:           esWithTier("7.10.0", esv1.DataTierCold, m{esv1.NodeRoles: []esv1.NodeRole{esv1.DataColdRole, esv1.DataWarmRole}}),
+			expectErrors: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := validDataTiers(tt.es)
+			hasErrors := len(result) > 0
+			if tt.expectErrors != hasErrors {
+				t.Errorf("expectedErrors=%t hasErrors=%t result=%+v", tt.expectErrors, hasErrors, result)
+			}
+		})
+	}
+}
+
 func Test_supportedVersion(t *testing.T) {
 	tests := []struct {
 		name         string
@@ -318,6 +397,1126 @@ func Test_validSanIP(t *testing.T) {
 	}
 }
 
+func Test_validPlugins(t *testing.T) {
+	tests := []struct {
+		name    string
+		plugins []esv1.Plugin
+		wantErr bool
+	}{
+		{
+			name:    "no plugins: OK",
+			wantErr: false,
+		},
+		{
+			name: "official plugin and plugin from a URL with a checksum: OK",
+			plugins: []esv1.Plugin{
+				{Name: "analysis-icu"},
+				{Name: "https://example.com/plugin.zip", SHA512: "abcd"},
+				{Name: "file:///mnt/plugins/other-plugin.zip"},
+			},
+			wantErr: false,
+		},
+		{
+			name: "duplicate plugins: NOT OK",
+			plugins: []esv1.Plugin{
+				{Name: "analysis-icu"},
+				{Name: "analysis-icu"},
+			},
+			wantErr: true,
+		},
+		{
+			name: "checksum for an official plugin: NOT OK",
+			plugins: []esv1.Plugin{
+				{Name: "analysis-icu", SHA512: "abcd"},
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			es := esv1.Elasticsearch{Spec: esv1.ElasticsearchSpec{Plugins: tt.plugins}}
+			errs := validPlugins(es)
+			assert.Equal(t, tt.wantErr, len(errs) > 0, errs)
+		})
+	}
+}
+
+func Test_validSnapshotVolumes(t *testing.T) {
+	nfs := &corev1.NFSVolumeSource{Server: "nfs.example.com", Path: "/exports/snapshots"}
+	tests := []struct {
+		name            string
+		snapshotVolumes []esv1.SnapshotVolume
+		wantErr         bool
+	}{
+		{
+			name:    "no snapshot volumes: OK",
+			wantErr: false,
+		},
+		{
+			name: "NFS and PVC snapshot volumes: OK",
+			snapshotVolumes: []esv1.SnapshotVolume{
+				{Name: "nfs", NFS: nfs},
+				{Name: "pvc", ClaimName: "shared-snapshots"},
+			},
+			wantErr: false,
+		},
+		{
+			name: "duplicate snapshot volumes: NOT OK",
+			snapshotVolumes: []esv1.SnapshotVolume{
+				{Name: "snapshots", NFS: nfs},
+				{Name: "snapshots", ClaimName: "shared-snapshots"},
+			},
+			wantErr: true,
+		},
+		{
+			name:            "no volume source: NOT OK",
+			snapshotVolumes: []esv1.SnapshotVolume{{Name: "snapshots"}},
+			wantErr:         true,
+		},
+		{
+			name:            "both volume sources: NOT OK",
+			snapshotVolumes: []esv1.SnapshotVolume{{Name: "snapshots", NFS: nfs, ClaimName: "shared-snapshots"}},
+			wantErr:         true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			es := esv1.Elasticsearch{Spec: esv1.ElasticsearchSpec{SnapshotVolumes: tt.snapshotVolumes}}
+			errs := validSnapshotVolumes(es)
+			assert.Equal(t, tt.wantErr, len(errs) > 0, errs)
+		})
+	}
+}
+
+func Test_validSnapshotRepositories(t *testing.T) {
+	tests := []struct {
+		name         string
+		repositories []esv1.SnapshotRepository
+		wantErr      bool
+	}{
+		{
+			name:    "no snapshot repositories: OK",
+			wantErr: false,
+		},
+		{
+			name: "distinct snapshot repositories: OK",
+			repositories: []esv1.SnapshotRepository{
+				{Name: "s3", Type: esv1.S3SnapshotRepository},
+				{Name: "gcs", Type: esv1.GCSSnapshotRepository},
+			},
+			wantErr: false,
+		},
+		{
+			name: "duplicate snapshot repositories: NOT OK",
+			repositories: []esv1.SnapshotRepository{
+				{Name: "backups", Type: esv1.S3SnapshotRepository},
+				{Name: "backups", Type: esv1.GCSSnapshotRepository},
+			},
+			wantErr: true,
+		},
+		{
+			name: "snapshot schedule: OK",
+			repositories: []esv1.SnapshotRepository{
+				{Name: "s3", Type: esv1.S3SnapshotRepository, Schedule: &esv1.SnapshotSchedule{
+					Cron:      "0 30 1 * * ?",
+					Retention: &esv1.SnapshotRetention{ExpireAfter: "30d", MinCount: pointer.Int32(5), MaxCount: pointer.Int32(50)},
+				}},
+			},
+			wantErr: false,
+		},
+		{
+			name: "invalid cron expression: NOT OK",
+			repositories: []esv1.SnapshotRepository{
+				{Name: "s3", Type: esv1.S3SnapshotRepository, Schedule: &esv1.SnapshotSchedule{Cron: "0 30 1 * *"}},
+			},
+			wantErr: true,
+		},
+		{
+			name: "invalid expireAfter: NOT OK",
+			repositories: []esv1.SnapshotRepository{
+				{Name: "s3", Type: esv1.S3SnapshotRepository, Schedule: &esv1.SnapshotSchedule{
+					Cron: "0 30 1 * * ?", Retention: &esv1.SnapshotRetention{ExpireAfter: "30 days"},
+				}},
+			},
+			wantErr: true,
+		},
+		{
+			name: "minCount greater than maxCount: NOT OK",
+			repositories: []esv1.SnapshotRepository{
+				{Name: "s3", Type: esv1.S3SnapshotRepository, Schedule: &esv1.SnapshotSchedule{
+					Cron: "0 30 1 * * ?", Retention: &esv1.SnapshotRetention{MinCount: pointer.Int32(10), MaxCount: pointer.Int32(5)},
+				}},
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			es := esv1.Elasticsearch{Spec: esv1.ElasticsearchSpec{Version: "7.3.0", SnapshotRepositories: tt.repositories}}
+			errs := validSnapshotRepositories(es)
+			assert.Equal(t, tt.wantErr, len(errs) > 0, errs)
+		})
+	}
+}
+
+func Test_validIngestPipelines(t *testing.T) {
+	definition := &commonv1.Config{Data: map[string]interface{}{"processors": []interface{}{}}}
+	tests := []struct {
+		name      string
+		pipelines []esv1.IngestPipeline
+		wantErr   bool
+	}{
+		{
+			name:    "no ingest pipelines: OK",
+			wantErr: false,
+		},
+		{
+			name: "distinct ingest pipelines: OK",
+			pipelines: []esv1.IngestPipeline{
+				{Name: "inline", Definition: definition},
+				{Name: "from-configmap", ConfigMapName: "pipelines"},
+			},
+			wantErr: false,
+		},
+		{
+			name: "duplicate ingest pipelines: NOT OK",
+			pipelines: []esv1.IngestPipeline{
+				{Name: "logs", Definition: definition},
+				{Name: "logs", ConfigMapName: "pipelines"},
+			},
+			wantErr: true,
+		},
+		{
+			name:      "no definition: NOT OK",
+			pipelines: []esv1.IngestPipeline{{Name: "logs"}},
+			wantErr:   true,
+		},
+		{
+			name:      "both definition and ConfigMap: NOT OK",
+			pipelines: []esv1.IngestPipeline{{Name: "logs", Definition: definition, ConfigMapName: "pipelines"}},
+			wantErr:   true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			es := esv1.Elasticsearch{Spec: esv1.ElasticsearchSpec{Version: "8.5.0", IngestPipelines: tt.pipelines}}
+			errs := validIngestPipelines(es)
+			assert.Equal(t, tt.wantErr, len(errs) > 0, errs)
+		})
+	}
+}
+
+func Test_validComponentTemplates(t *testing.T) {
+	definition := &commonv1.Config{Data: map[string]interface{}{"template": map[string]interface{}{}}}
+	tests := []struct {
+		name      string
+		version   string
+		templates []esv1.ComponentTemplate
+		wantErr   bool
+	}{
+		{
+			name:    "no component templates: OK",
+			version: "7.7.0",
+			wantErr: false,
+		},
+		{
+			name:    "distinct component templates: OK",
+			version: "8.5.0",
+			templates: []esv1.ComponentTemplate{
+				{Name: "mappings", Definition: definition},
+				{Name: "settings", ConfigMapName: "templates"},
+			},
+			wantErr: false,
+		},
+		{
+			name:    "duplicate component templates: NOT OK",
+			version: "8.5.0",
+			templates: []esv1.ComponentTemplate{
+				{Name: "mappings", Definition: definition},
+				{Name: "mappings", ConfigMapName: "templates"},
+			},
+			wantErr: true,
+		},
+		{
+			name:      "no definition: NOT OK",
+			version:   "8.5.0",
+			templates: []esv1.ComponentTemplate{{Name: "mappings"}},
+			wantErr:   true,
+		},
+		{
+			name:      "before 7.8.0: NOT OK",
+			version:   "7.7.0",
+			templates: []esv1.ComponentTemplate{{Name: "mappings", Definition: definition}},
+			wantErr:   true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			es := esv1.Elasticsearch{Spec: esv1.ElasticsearchSpec{Version: tt.version, ComponentTemplates: tt.templates}}
+			errs := validComponentTemplates(es)
+			assert.Equal(t, tt.wantErr, len(errs) > 0, errs)
+		})
+	}
+}
+
+func Test_validIndexTemplates(t *testing.T) {
+	definition := &commonv1.Config{Data: map[string]interface{}{"index_patterns": []interface{}{"logs-*"}}}
+	tests := []struct {
+		name      string
+		version   string
+		templates []esv1.IndexTemplate
+		wantErr   bool
+	}{
+		{
+			name:    "no index templates: OK",
+			version: "8.5.0",
+			wantErr: false,
+		},
+		{
+			name:    "templates of both types with the same name: OK",
+			version: "8.5.0",
+			templates: []esv1.IndexTemplate{
+				{Name: "logs", Definition: definition},
+				{Name: "logs", Type: esv1.LegacyIndexTemplateType, ConfigMapName: "templates"},
+			},
+			wantErr: false,
+		},
+		{
+			name:    "duplicate index templates: NOT OK",
+			version: "8.5.0",
+			templates: []esv1.IndexTemplate{
+				{Name: "logs", Definition: definition},
+				{Name: "logs", Type: esv1.ComposableIndexTemplateType, ConfigMapName: "templates"},
+			},
+			wantErr: true,
+		},
+		{
+			name:      "no definition: NOT OK",
+			version:   "8.5.0",
+			templates: []esv1.IndexTemplate{{Name: "logs"}},
+			wantErr:   true,
+		},
+		{
+			name:      "legacy template before 7.8.0: OK",
+			version:   "7.7.0",
+			templates: []esv1.IndexTemplate{{Name: "logs", Type: esv1.LegacyIndexTemplateType, Definition: definition}},
+			wantErr:   false,
+		},
+		{
+			name:      "composable template before 7.8.0: NOT OK",
+			version:   "7.7.0",
+			templates: []esv1.IndexTemplate{{Name: "logs", Definition: definition}},
+			wantErr:   true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			es := esv1.Elasticsearch{Spec: esv1.ElasticsearchSpec{Version: tt.version, IndexTemplates: tt.templates}}
+			errs := validIndexTemplates(es)
+			assert.Equal(t, tt.wantErr, len(errs) > 0, errs)
+		})
+	}
+}
+
+func Test_validILMPolicies(t *testing.T) {
+	definition := &commonv1.Config{Data: map[string]interface{}{"phases": map[string]interface{}{}}}
+	tests := []struct {
+		name     string
+		policies []esv1.ILMPolicy
+		wantErr  bool
+	}{
+		{
+			name:    "no ILM policies: OK",
+			wantErr: false,
+		},
+		{
+			name: "distinct ILM policies: OK",
+			policies: []esv1.ILMPolicy{
+				{Name: "logs", Definition: definition},
+				{Name: "metrics", ConfigMapName: "policies"},
+			},
+			wantErr: false,
+		},
+		{
+			name: "duplicate ILM policies: NOT OK",
+			policies: []esv1.ILMPolicy{
+				{Name: "logs", Definition: definition},
+				{Name: "logs", ConfigMapName: "policies"},
+			},
+			wantErr: true,
+		},
+		{
+			name:     "both definition and ConfigMap: NOT OK",
+			policies: []esv1.ILMPolicy{{Name: "logs", Definition: definition, ConfigMapName: "policies"}},
+			wantErr:  true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			es := esv1.Elasticsearch{Spec: esv1.ElasticsearchSpec{Version: "8.5.0", ILMPolicies: tt.policies}}
+			errs := validILMPolicies(es)
+			assert.Equal(t, tt.wantErr, len(errs) > 0, errs)
+		})
+	}
+}
+
+func Test_validClusterSettings(t *testing.T) {
+	tests := []struct {
+		name     string
+		settings map[string]interface{}
+		nodeSets []esv1.NodeSet
+		wantErr  bool
+	}{
+		{
+			name:    "no cluster settings: OK",
+			wantErr: false,
+		},
+		{
+			name:     "nested and flattened settings: OK",
+			settings: map[string]interface{}{"indices": map[string]interface{}{"recovery.max_bytes_per_sec": "100mb"}, "search.max_buckets": 20000},
+			nodeSets: []esv1.NodeSet{{Name: "default", Config: &commonv1.Config{Data: map[string]interface{}{"node.roles": []interface{}{"master"}}}}},
+			wantErr:  false,
+		},
+		{
+			name:     "setting managed by the operator: NOT OK",
+			settings: map[string]interface{}{"cluster.routing.allocation.enable": "primaries"},
+			wantErr:  true,
+		},
+		{
+			name:     "remote cluster setting: NOT OK",
+			settings: map[string]interface{}{"cluster": map[string]interface{}{"remote": map[string]interface{}{"other.seeds": []interface{}{"127.0.0.1:9300"}}}},
+			wantErr:  true,
+		},
+		{
+			name:     "setting also set in a NodeSet: NOT OK",
+			settings: map[string]interface{}{"search.max_buckets": 20000},
+			nodeSets: []esv1.NodeSet{{Name: "default", Config: &commonv1.Config{Data: map[string]interface{}{"search": map[string]interface{}{"max_buckets": 10000}}}}},
+			wantErr:  true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			es := esv1.Elasticsearch{Spec: esv1.ElasticsearchSpec{Version: "8.5.0", NodeSets: tt.nodeSets}}
+			if tt.settings != nil {
+				es.Spec.ClusterSettings = &esv1.ManagedClusterSettings{Persistent: &commonv1.Config{Data: tt.settings}}
+			}
+			errs := validClusterSettings(es)
+			assert.Equal(t, tt.wantErr, len(errs) > 0, errs)
+		})
+	}
+}
+
+func Test_validNativeUsers(t *testing.T) {
+	password := commonv1.SecretRef{SecretName: "password"}
+	tests := []struct {
+		name    string
+		users   []esv1.NativeUser
+		wantErr bool
+	}{
+		{
+			name:    "no native users: OK",
+			wantErr: false,
+		},
+		{
+			name: "distinct native users: OK",
+			users: []esv1.NativeUser{
+				{Name: "jacknich", PasswordSecretRef: password, Roles: []string{"admin"}},
+				{Name: "rdeniro", PasswordSecretRef: password},
+			},
+			wantErr: false,
+		},
+		{
+			name: "duplicate native users: NOT OK",
+			users: []esv1.NativeUser{
+				{Name: "jacknich", PasswordSecretRef: password},
+				{Name: "jacknich", PasswordSecretRef: password},
+			},
+			wantErr: true,
+		},
+		{
+			name:    "reserved user name: NOT OK",
+			users:   []esv1.NativeUser{{Name: "elastic", PasswordSecretRef: password}},
+			wantErr: true,
+		},
+		{
+			name:    "no password secret: NOT OK",
+			users:   []esv1.NativeUser{{Name: "jacknich"}},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			es := esv1.Elasticsearch{Spec: esv1.ElasticsearchSpec{Version: "8.5.0", Auth: esv1.Auth{Users: tt.users}}}
+			errs := validNativeUsers(es)
+			assert.Equal(t, tt.wantErr, len(errs) > 0, errs)
+		})
+	}
+}
+
+func Test_validSAMLRealms(t *testing.T) {
+	realm := func(name string, idp esv1.SAMLIdentityProvider) esv1.SAMLRealm {
+		return esv1.SAMLRealm{
+			Name:       name,
+			IdP:        idp,
+			SP:         esv1.SAMLServiceProvider{EntityID: "https://kibana.example.com", ACS: "https://kibana.example.com/api/security/saml/callback"},
+			Attributes: esv1.SAMLAttributes{Principal: "nameid"},
+		}
+	}
+	fromConfigMap := esv1.SAMLIdentityProvider{EntityID: "idp", MetadataConfigMapName: "idp-metadata"}
+	fromURL := esv1.SAMLIdentityProvider{EntityID: "idp", MetadataURL: "https://idp.example.com/metadata.xml"}
+	tests := []struct {
+		name    string
+		version string
+		realms  []esv1.SAMLRealm
+		wantErr bool
+	}{
+		{
+			name:    "no SAML realms: OK",
+			version: "6.8.0",
+			wantErr: false,
+		},
+		{
+			name:    "distinct SAML realms: OK",
+			version: "8.5.0",
+			realms:  []esv1.SAMLRealm{realm("okta", fromConfigMap), realm("adfs", fromURL)},
+			wantErr: false,
+		},
+		{
+			name:    "duplicate SAML realms: NOT OK",
+			version: "8.5.0",
+			realms:  []esv1.SAMLRealm{realm("okta", fromConfigMap), realm("okta", fromURL)},
+			wantErr: true,
+		},
+		{
+			name:    "no identity provider metadata: NOT OK",
+			version: "8.5.0",
+			realms:  []esv1.SAMLRealm{realm("okta", esv1.SAMLIdentityProvider{EntityID: "idp"})},
+			wantErr: true,
+		},
+		{
+			name:    "identity provider metadata from a ConfigMap and a URL: NOT OK",
+			version: "8.5.0",
+			realms: []esv1.SAMLRealm{realm("okta", esv1.SAMLIdentityProvider{
+				EntityID: "idp", MetadataConfigMapName: "idp-metadata", MetadataURL: "https://idp.example.com/metadata.xml",
+			})},
+			wantErr: true,
+		},
+		{
+			name:    "6.x realm syntax: NOT OK",
+			version: "6.8.0",
+			realms:  []esv1.SAMLRealm{realm("okta", fromConfigMap)},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			es := esv1.Elasticsearch{Spec: esv1.ElasticsearchSpec{Version: tt.version, Auth: esv1.Auth{SAML: tt.realms}}}
+			errs := validSAMLRealms(es)
+			assert.Equal(t, tt.wantErr, len(errs) > 0, errs)
+		})
+	}
+}
+
+func Test_validOIDCRealms(t *testing.T) {
+	realm := func(name string, clientSecret string) esv1.OIDCRealm {
+		return esv1.OIDCRealm{
+			Name: name,
+			RP: esv1.OIDCRelyingParty{
+				ClientID:        "client-id",
+				ClientSecretRef: commonv1.SecretRef{SecretName: clientSecret},
+				RedirectURI:     "https://kibana.example.com/api/security/oidc/callback",
+			},
+			OP:     esv1.OIDCProvider{Issuer: "https://op.example.com", AuthorizationEndpoint: "https://op.example.com/auth"},
+			Claims: esv1.OIDCClaims{Principal: "sub"},
+		}
+	}
+	tests := []struct {
+		name    string
+		version string
+		saml    []esv1.SAMLRealm
+		realms  []esv1.OIDCRealm
+		wantErr bool
+	}{
+		{
+			name:    "no OIDC realms: OK",
+			version: "6.8.0",
+			wantErr: false,
+		},
+		{
+			name:    "distinct OIDC realms: OK",
+			version: "8.5.0",
+			realms:  []esv1.OIDCRealm{realm("google", "google-secret"), realm("azure", "azure-secret")},
+			wantErr: false,
+		},
+		{
+			name:    "duplicate OIDC realms: NOT OK",
+			version: "8.5.0",
+			realms:  []esv1.OIDCRealm{realm("google", "google-secret"), realm("google", "azure-secret")},
+			wantErr: true,
+		},
+		{
+			name:    "OIDC realm named after a SAML realm: NOT OK",
+			version: "8.5.0",
+			saml:    []esv1.SAMLRealm{{Name: "google"}},
+			realms:  []esv1.OIDCRealm{realm("google", "google-secret")},
+			wantErr: true,
+		},
+		{
+			name:    "no client secret: NOT OK",
+			version: "8.5.0",
+			realms:  []esv1.OIDCRealm{realm("google", "")},
+			wantErr: true,
+		},
+		{
+			name:    "OIDC realms not supported: NOT OK",
+			version: "7.1.0",
+			realms:  []esv1.OIDCRealm{realm("google", "google-secret")},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			es := esv1.Elasticsearch{Spec: esv1.ElasticsearchSpec{Version: tt.version, Auth: esv1.Auth{SAML: tt.saml, OIDC: tt.realms}}}
+			errs := validOIDCRealms(es)
+			assert.Equal(t, tt.wantErr, len(errs) > 0, errs)
+		})
+	}
+}
+
+func Test_validLDAPRealms(t *testing.T) {
+	ldap := esv1.LDAPRealm{Name: "corp", URLs: []string{"ldaps://ldap.example.com:636"}, UserSearchBaseDN: "ou=users,dc=example,dc=com"}
+	ad := esv1.LDAPRealm{Name: "ad", Type: esv1.LDAPRealmTypeActiveDirectory, Domain: "example.com"}
+	with := func(realm esv1.LDAPRealm, mutate func(*esv1.LDAPRealm)) esv1.LDAPRealm {
+		mutate(&realm)
+		return realm
+	}
+	tests := []struct {
+		name    string
+		version string
+		oidc    []esv1.OIDCRealm
+		realms  []esv1.LDAPRealm
+		wantErr bool
+	}{
+		{
+			name:    "no LDAP realms: OK",
+			version: "6.8.0",
+			wantErr: false,
+		},
+		{
+			name:    "LDAP and Active Directory realms: OK",
+			version: "8.5.0",
+			realms:  []esv1.LDAPRealm{ldap, ad},
+			wantErr: false,
+		},
+		{
+			name:    "duplicate LDAP realms: NOT OK",
+			version: "8.5.0",
+			realms:  []esv1.LDAPRealm{ldap, with(ad, func(r *esv1.LDAPRealm) { r.Name = "corp" })},
+			wantErr: true,
+		},
+		{
+			name:    "LDAP realm named after an OIDC realm: NOT OK",
+			version: "8.5.0",
+			oidc:    []esv1.OIDCRealm{{Name: "corp"}},
+			realms:  []esv1.LDAPRealm{ldap},
+			wantErr: true,
+		},
+		{
+			name:    "LDAP realm without URLs: NOT OK",
+			version: "8.5.0",
+			realms:  []esv1.LDAPRealm{with(ldap, func(r *esv1.LDAPRealm) { r.URLs = nil })},
+			wantErr: true,
+		},
+		{
+			name:    "Active Directory realm without domain: NOT OK",
+			version: "8.5.0",
+			realms:  []esv1.LDAPRealm{with(ad, func(r *esv1.LDAPRealm) { r.Domain = "" })},
+			wantErr: true,
+		},
+		{
+			name:    "user DN templates combined with a user search: NOT OK",
+			version: "8.5.0",
+			realms:  []esv1.LDAPRealm{with(ldap, func(r *esv1.LDAPRealm) { r.UserDNTemplates = []string{"cn={0},ou=users"} })},
+			wantErr: true,
+		},
+		{
+			name:    "bind password without bind DN: NOT OK",
+			version: "8.5.0",
+			realms:  []esv1.LDAPRealm{with(ldap, func(r *esv1.LDAPRealm) { r.BindPasswordSecretRef = &commonv1.SecretRef{SecretName: "bind"} })},
+			wantErr: true,
+		},
+		{
+			name:    "6.x realm syntax: NOT OK",
+			version: "6.8.0",
+			realms:  []esv1.LDAPRealm{ldap},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			es := esv1.Elasticsearch{Spec: esv1.ElasticsearchSpec{Version: tt.version, Auth: esv1.Auth{OIDC: tt.oidc, LDAP: tt.realms}}}
+			errs := validLDAPRealms(es)
+			assert.Equal(t, tt.wantErr, len(errs) > 0, errs)
+		})
+	}
+}
+
+func Test_validRealmOrders(t *testing.T) {
+	withConfig := func(data map[string]interface{}) []esv1.NodeSet {
+		return []esv1.NodeSet{{Name: "default", Config: &commonv1.Config{Data: data}}}
+	}
+	tests := []struct {
+		name     string
+		auth     esv1.Auth
+		nodeSets []esv1.NodeSet
+		wantErr  bool
+	}{
+		{
+			name:    "no realms: OK",
+			wantErr: false,
+		},
+		{
+			name:    "single realm with the default order: OK",
+			auth:    esv1.Auth{SAML: []esv1.SAMLRealm{{Name: "saml1"}}},
+			wantErr: false,
+		},
+		{
+			name: "distinct orders across realm types: OK",
+			auth: esv1.Auth{
+				SAML: []esv1.SAMLRealm{{Name: "saml1", Order: 2}},
+				OIDC: []esv1.OIDCRealm{{Name: "oidc1", Order: 3}},
+				LDAP: []esv1.LDAPRealm{{Name: "ldap1", Order: 4}},
+			},
+			wantErr: false,
+		},
+		{
+			name: "default orders of several realms: NOT OK",
+			auth: esv1.Auth{
+				SAML: []esv1.SAMLRealm{{Name: "saml1"}},
+				LDAP: []esv1.LDAPRealm{{Name: "ldap1"}},
+			},
+			wantErr: true,
+		},
+		{
+			name:    "order of a realm managed by the operator: NOT OK",
+			auth:    esv1.Auth{OIDC: []esv1.OIDCRealm{{Name: "oidc1", Order: -100}}},
+			wantErr: true,
+		},
+		{
+			name:     "order of a realm declared in the configuration: NOT OK",
+			auth:     esv1.Auth{SAML: []esv1.SAMLRealm{{Name: "saml1", Order: 2}}},
+			nodeSets: withConfig(map[string]interface{}{"xpack.security.authc.realms.pki.pki1.order": 2}),
+			wantErr:  true,
+		},
+		{
+			name:     "other orders of realms declared in the configuration: OK",
+			auth:     esv1.Auth{SAML: []esv1.SAMLRealm{{Name: "saml1", Order: 2}}},
+			nodeSets: withConfig(map[string]interface{}{"xpack": map[string]interface{}{"security.authc.realms.pki.pki1.order": float64(3)}}),
+			wantErr:  false,
+		},
+		{
+			name:     "same realm declared in the spec and in the configuration: OK",
+			auth:     esv1.Auth{SAML: []esv1.SAMLRealm{{Name: "saml1", Order: 2}}},
+			nodeSets: withConfig(map[string]interface{}{"xpack.security.authc.realms.saml.saml1.order": 2}),
+			wantErr:  false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			es := esv1.Elasticsearch{Spec: esv1.ElasticsearchSpec{Version: "8.5.0", Auth: tt.auth, NodeSets: tt.nodeSets}}
+			errs := validRealmOrders(es)
+			assert.Equal(t, tt.wantErr, len(errs) > 0, errs)
+		})
+	}
+}
+
+func Test_validAuditLogging(t *testing.T) {
+	tests := []struct {
+		name    string
+		audit   *esv1.AuditLogging
+		wantErr bool
+	}{
+		{
+			name:    "no audit logging: OK",
+			wantErr: false,
+		},
+		{
+			name: "audit logging with event filters: OK",
+			audit: &esv1.AuditLogging{
+				IncludeEvents: []string{"access_denied", "authentication_failed"},
+				ExcludeEvents: []string{"access_granted"},
+				IgnoreFilters: []esv1.AuditIgnoreFilter{{Name: "kibana", Users: []string{"kibana_system"}}, {Name: "monitoring"}},
+			},
+			wantErr: false,
+		},
+		{
+			name:    "unknown event type: NOT OK",
+			audit:   &esv1.AuditLogging{IncludeEvents: []string{"access_refused"}},
+			wantErr: true,
+		},
+		{
+			name:    "event type both included and excluded: NOT OK",
+			audit:   &esv1.AuditLogging{IncludeEvents: []string{"access_denied"}, ExcludeEvents: []string{"access_denied"}},
+			wantErr: true,
+		},
+		{
+			name:    "duplicate ignore filters: NOT OK",
+			audit:   &esv1.AuditLogging{IgnoreFilters: []esv1.AuditIgnoreFilter{{Name: "kibana"}, {Name: "kibana"}}},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			es := esv1.Elasticsearch{Spec: esv1.ElasticsearchSpec{Version: "8.5.0", AuditLogging: tt.audit}}
+			errs := validAuditLogging(es)
+			assert.Equal(t, tt.wantErr, len(errs) > 0, errs)
+		})
+	}
+}
+
+func Test_validRestoreVerification(t *testing.T) {
+	tests := []struct {
+		name         string
+		esName       string
+		labels       map[string]string
+		verification *esv1.RestoreVerification
+		wantErr      bool
+	}{
+		{
+			name:    "no restore verification: OK",
+			esName:  "a-very-long-cluster-name-for-a-verification",
+			wantErr: false,
+		},
+		{
+			name:         "valid restore verification: OK",
+			esName:       "es",
+			verification: &esv1.RestoreVerification{Repository: "s3", Cron: "0 0 3 ? * SUN"},
+			wantErr:      false,
+		},
+		{
+			name:         "repository not declared: NOT OK",
+			esName:       "es",
+			verification: &esv1.RestoreVerification{Repository: "gcs", Cron: "0 0 3 ? * SUN"},
+			wantErr:      true,
+		},
+		{
+			name:         "invalid cron expression: NOT OK",
+			esName:       "es",
+			verification: &esv1.RestoreVerification{Repository: "s3", Cron: "0 0 3 * *"},
+			wantErr:      true,
+		},
+		{
+			name:         "verification cluster name too long: NOT OK",
+			esName:       "a-very-long-cluster-name-for-a-verification",
+			verification: &esv1.RestoreVerification{Repository: "s3", Cron: "0 0 3 ? * SUN"},
+			wantErr:      true,
+		},
+		{
+			name:    "name with the reserved suffix: NOT OK",
+			esName:  "es-eck-verify",
+			wantErr: true,
+		},
+		{
+			name:    "name with the reserved suffix of another cluster: NOT OK",
+			esName:  "es-eck-verify",
+			labels:  map[string]string{snapshot.RestoreVerificationOfLabelName: "other"},
+			wantErr: true,
+		},
+		{
+			name:    "verification cluster created by the operator: OK",
+			esName:  "es-eck-verify",
+			labels:  map[string]string{snapshot.RestoreVerificationOfLabelName: "es"},
+			wantErr: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			es := esv1.Elasticsearch{
+				ObjectMeta: metav1.ObjectMeta{Name: tt.esName, Labels: tt.labels},
+				Spec: esv1.ElasticsearchSpec{
+					SnapshotRepositories: []esv1.SnapshotRepository{{Name: "s3", Type: esv1.S3SnapshotRepository}},
+					RestoreVerification:  tt.verification,
+				},
+			}
+			errs := validRestoreVerification(es)
+			assert.Equal(t, tt.wantErr, len(errs) > 0, errs)
+		})
+	}
+}
+
+func Test_validJVMOptions(t *testing.T) {
+	tests := []struct {
+		name       string
+		version    string
+		jvmOptions []string
+		wantErr    bool
+	}{
+		{
+			name:    "no JVM options: OK",
+			version: "7.0.0",
+			wantErr: false,
+		},
+		{
+			name:       "JVM options: OK",
+			version:    "8.5.0",
+			jvmOptions: []string{"-XX:+HeapDumpOnOutOfMemoryError", "-XX:HeapDumpPath=/usr/share/elasticsearch/data"},
+			wantErr:    false,
+		},
+		{
+			name:       "JVM options before 7.7.0: NOT OK",
+			version:    "7.6.2",
+			jvmOptions: []string{"-XX:+HeapDumpOnOutOfMemoryError"},
+			wantErr:    true,
+		},
+		{
+			name:       "empty JVM option: NOT OK",
+			version:    "8.5.0",
+			jvmOptions: []string{" "},
+			wantErr:    true,
+		},
+		{
+			name:       "multi-line JVM option: NOT OK",
+			version:    "8.5.0",
+			jvmOptions: []string{"-XX:+HeapDumpOnOutOfMemoryError\n-Xmx1g"},
+			wantErr:    true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			es := esv1.Elasticsearch{Spec: esv1.ElasticsearchSpec{
+				Version:  tt.version,
+				NodeSets: []esv1.NodeSet{{Name: "default", Count: 1, JVMOptions: tt.jvmOptions}},
+			}}
+			errs := validJVMOptions(es)
+			assert.Equal(t, tt.wantErr, len(errs) > 0, errs)
+		})
+	}
+}
+
+func Test_validMemoryDataVolumes(t *testing.T) {
+	tests := []struct {
+		name    string
+		nodeSet esv1.NodeSet
+		wantErr bool
+	}{
+		{
+			name:    "no memory data volume: OK",
+			nodeSet: esv1.NodeSet{Name: "default", Count: 1},
+			wantErr: false,
+		},
+		{
+			name: "memory data volume: OK",
+			nodeSet: esv1.NodeSet{
+				Name:             "default",
+				Count:            1,
+				MemoryDataVolume: &esv1.MemoryDataVolume{SizeLimit: resource.MustParse("1Gi")},
+			},
+			wantErr: false,
+		},
+		{
+			name: "zero size limit: NOT OK",
+			nodeSet: esv1.NodeSet{
+				Name:             "default",
+				Count:            1,
+				MemoryDataVolume: &esv1.MemoryDataVolume{SizeLimit: resource.MustParse("0")},
+			},
+			wantErr: true,
+		},
+		{
+			name: "memory data volume and data volume claim: NOT OK",
+			nodeSet: esv1.NodeSet{
+				Name:                 "default",
+				Count:                1,
+				MemoryDataVolume:     &esv1.MemoryDataVolume{SizeLimit: resource.MustParse("1Gi")},
+				VolumeClaimTemplates: []corev1.PersistentVolumeClaim{{ObjectMeta: metav1.ObjectMeta{Name: "elasticsearch-data"}}},
+			},
+			wantErr: true,
+		},
+		{
+			name: "memory data volume and Pod template data volume: NOT OK",
+			nodeSet: esv1.NodeSet{
+				Name:             "default",
+				Count:            1,
+				MemoryDataVolume: &esv1.MemoryDataVolume{SizeLimit: resource.MustParse("1Gi")},
+				PodTemplate: corev1.PodTemplateSpec{Spec: corev1.PodSpec{Volumes: []corev1.Volume{{
+					Name:         "elasticsearch-data",
+					VolumeSource: corev1.VolumeSource{EmptyDir: &corev1.EmptyDirVolumeSource{}},
+				}}}},
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			es := esv1.Elasticsearch{Spec: esv1.ElasticsearchSpec{Version: "8.5.0", NodeSets: []esv1.NodeSet{tt.nodeSet}}}
+			errs := validMemoryDataVolumes(es)
+			assert.Equal(t, tt.wantErr, len(errs) > 0, errs)
+		})
+	}
+}
+
+func Test_validNodeAttributes(t *testing.T) {
+	tests := []struct {
+		name       string
+		dataTier   esv1.DataTier
+		attributes map[string]string
+		wantErr    bool
+	}{
+		{
+			name:    "no attributes: OK",
+			wantErr: false,
+		},
+		{
+			name:       "attributes: OK",
+			attributes: map[string]string{"rack": "rack-1", "storage_type": "ssd", "zone.group": "a"},
+			wantErr:    false,
+		},
+		{
+			name:       "data attribute without data tier: OK",
+			attributes: map[string]string{"data": "hot"},
+			wantErr:    false,
+		},
+		{
+			name:       "data attribute with data tier: NOT OK",
+			dataTier:   esv1.DataTierHot,
+			attributes: map[string]string{"data": "hot"},
+			wantErr:    true,
+		},
+		{
+			name:       "operator managed attribute: NOT OK",
+			attributes: map[string]string{"k8s_node_name": "node"},
+			wantErr:    true,
+		},
+		{
+			name:       "empty attribute name: NOT OK",
+			attributes: map[string]string{"": "a"},
+			wantErr:    true,
+		},
+		{
+			name:       "invalid attribute name: NOT OK",
+			attributes: map[string]string{"my rack": "a"},
+			wantErr:    true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			es := esv1.Elasticsearch{Spec: esv1.ElasticsearchSpec{
+				Version:  "8.5.0",
+				NodeSets: []esv1.NodeSet{{Name: "default", Count: 1, DataTier: tt.dataTier, Attributes: tt.attributes}},
+			}}
+			errs := validNodeAttributes(es)
+			assert.Equal(t, tt.wantErr, len(errs) > 0, errs)
+		})
+	}
+}
+
+func Test_noNewUnsupportedSettings(t *testing.T) {
+	nodeSet := func(name string, cfg map[string]interface{}) esv1.NodeSet {
+		return esv1.NodeSet{Name: name, Count: 1, Config: &commonv1.Config{Data: cfg}}
+	}
+	withNodeSets := func(nodeSets ...esv1.NodeSet) esv1.Elasticsearch {
+		return esv1.Elasticsearch{Spec: esv1.ElasticsearchSpec{Version: "8.5.0", NodeSets: nodeSets}}
+	}
+	tests := []struct {
+		name     string
+		current  esv1.Elasticsearch
+		proposed esv1.Elasticsearch
+		wantErr  bool
+	}{
+		{
+			name:     "no unsupported settings: OK",
+			current:  withNodeSets(nodeSet("default", nil)),
+			proposed: withNodeSets(nodeSet("default", map[string]interface{}{"node.attr.zone": "a"})),
+			wantErr:  false,
+		},
+		{
+			name:     "unsupported setting already present: OK",
+			current:  withNodeSets(nodeSet("default", map[string]interface{}{esv1.NetworkHost: "0.0.0.0"})),
+			proposed: withNodeSets(nodeSet("default", map[string]interface{}{esv1.NetworkHost: "0.0.0.0", "node.attr.zone": "a"})),
+			wantErr:  false,
+		},
+		{
+			name:     "unsupported setting added: NOT OK",
+			current:  withNodeSets(nodeSet("default", nil)),
+			proposed: withNodeSets(nodeSet("default", map[string]interface{}{esv1.ClusterName: "other"})),
+			wantErr:  true,
+		},
+		{
+			name:    "unsupported setting added to a new NodeSet: NOT OK",
+			current: withNodeSets(nodeSet("default", map[string]interface{}{esv1.NetworkHost: "0.0.0.0"})),
+			proposed: withNodeSets(
+				nodeSet("default", map[string]interface{}{esv1.NetworkHost: "0.0.0.0"}),
+				nodeSet("other", map[string]interface{}{esv1.NetworkHost: "0.0.0.0"}),
+			),
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			errs := noNewUnsupportedSettings(tt.current, tt.proposed)
+			assert.Equal(t, tt.wantErr, len(errs) > 0, errs)
+		})
+	}
+}
+
+func Test_validInitialRestoreChange(t *testing.T) {
+	withRestore := func(snapshot string, phase esv1.InitialRestorePhase) esv1.Elasticsearch {
+		es := esv1.Elasticsearch{Spec: esv1.ElasticsearchSpec{Version: "8.5.0"}}
+		if snapshot != "" {
+			es.Spec.InitialRestore = &esv1.InitialRestore{Repository: "s3-backups", Snapshot: snapshot}
+		}
+		if phase != "" {
+			es.Status.InitialRestore = &esv1.InitialRestoreStatus{Repository: "s3-backups", Snapshot: snapshot, Phase: phase}
+		}
+		return es
+	}
+	tests := []struct {
+		name     string
+		current  esv1.Elasticsearch
+		proposed esv1.Elasticsearch
+		wantErr  bool
+	}{
+		{
+			name:     "unchanged: OK",
+			current:  withRestore("snap-1", esv1.InitialRestoreCompleted),
+			proposed: withRestore("snap-1", ""),
+		},
+		{
+			name:     "removed: OK",
+			current:  withRestore("snap-1", esv1.InitialRestoreCompleted),
+			proposed: withRestore("", ""),
+		},
+		{
+			name:     "changed after a failed restore: OK",
+			current:  withRestore("snap-1", esv1.InitialRestoreFailed),
+			proposed: withRestore("snap-2", ""),
+		},
+		{
+			name:     "changed before the restore started: OK",
+			current:  withRestore("snap-1", ""),
+			proposed: withRestore("snap-2", ""),
+		},
+		{
+			name:     "changed after the restore started: NOT OK",
+			current:  withRestore("snap-1", esv1.InitialRestoreInProgress),
+			proposed: withRestore("snap-2", ""),
+			wantErr:  true,
+		},
+		{
+			name:     "added to an existing cluster: NOT OK",
+			current:  withRestore("", ""),
+			proposed: withRestore("snap-1", ""),
+			wantErr:  true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			errs := validInitialRestoreChange(tt.current, tt.proposed)
+			assert.Equal(t, tt.wantErr, len(errs) > 0, errs)
+		})
+	}
+}
+
 func TestValidation_noDowngrades(t *testing.T) {
 	tests := []struct {
 		name         string
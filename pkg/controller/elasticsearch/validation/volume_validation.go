@@ -10,6 +10,7 @@ import (
 	"fmt"
 
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/autoscaling"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/defaults"
 
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
@@ -68,6 +69,15 @@ func hasDefaultClaim(templates []corev1.PersistentVolumeClaim) bool {
 	return false
 }
 
+func hasDataVolume(volumes []corev1.Volume) bool {
+	for _, v := range volumes {
+		if v.Name == volume.ElasticsearchDataVolumeName {
+			return true
+		}
+	}
+	return false
+}
+
 // validPVCModification ensures the only part of volume claim templates that can be changed is storage requests.
 // Storage increase is allowed as long as the storage class supports volume expansion.
 // Storage decrease is not supported if the corresponding StatefulSet has been resized already.
@@ -104,6 +114,14 @@ func validPVCModification(ctx context.Context, current esv1.Elasticsearch, propo
 			continue
 		}
 
+		// Changes which cannot be applied in place are allowed if the operator can replace the StatefulSet of the NodeSet.
+		if proposed.IsStorageMigrationEnabled() && StorageMigrationRequired(
+			defaults.AppendDefaultPVCs(currentNodeSet.VolumeClaimTemplates, volume.PodSpecWithMemoryDataVolume(*currentNodeSet), volume.DefaultVolumeClaimTemplates...),
+			defaults.AppendDefaultPVCs(proposedNodeSet.VolumeClaimTemplates, volume.PodSpecWithMemoryDataVolume(proposedNodeSet), volume.DefaultVolumeClaimTemplates...),
+		) {
+			continue
+		}
+
 		// Check that no modification was made to the claims, except on storage requests.
 		if !apiequality.Semantic.DeepEqual(
 			claimsWithoutStorageReq(currentNodeSet.VolumeClaimTemplates),
@@ -123,7 +141,7 @@ func validPVCModification(ctx context.Context, current esv1.Elasticsearch, propo
 		// errors out for some reasons, then reverts the storage size to a correct 1GB. In that case the StatefulSet
 		// claim is still configured with 1GB even though the current Elasticsearch specifies 2GB.
 		// Hence here we compare proposed claims with **current StatefulSet** claims.
-		matchingSsetName := esv1.StatefulSet(proposed.Name, proposedNodeSet.Name)
+		matchingSsetName := proposed.StatefulSetName(proposedNodeSet.Name)
 		var matchingSset appsv1.StatefulSet
 		err := k8sClient.Get(context.Background(), types.NamespacedName{Namespace: proposed.Namespace, Name: matchingSsetName}, &matchingSset)
 		if err != nil && apierrors.IsNotFound(err) {
@@ -190,6 +208,33 @@ func ValidateClaimsStorageUpdate(
 	return nil
 }
 
+// StorageMigrationRequired returns true if the updated claims cannot be applied in place to the initial ones, because
+// claims were added or removed, or because their specification changed other than by a storage increase.
+func StorageMigrationRequired(initial []corev1.PersistentVolumeClaim, updated []corev1.PersistentVolumeClaim) bool {
+	if len(initial) != len(updated) {
+		return true
+	}
+	for _, updatedClaim := range updated {
+		initialClaim := claimMatchingName(initial, updatedClaim.Name)
+		if initialClaim == nil {
+			return true
+		}
+		if !apiequality.Semantic.DeepEqual(initialClaim.Spec.StorageClassName, updatedClaim.Spec.StorageClassName) ||
+			!apiequality.Semantic.DeepEqual(initialClaim.Spec.AccessModes, updatedClaim.Spec.AccessModes) ||
+			!apiequality.Semantic.DeepEqual(initialClaim.Spec.Selector, updatedClaim.Spec.Selector) {
+			return true
+		}
+		// the volume mode is defaulted by the API server if not specified
+		if updatedClaim.Spec.VolumeMode != nil && !apiequality.Semantic.DeepEqual(initialClaim.Spec.VolumeMode, updatedClaim.Spec.VolumeMode) {
+			return true
+		}
+		if k8s.CompareStorageRequests(initialClaim.Spec.Resources, updatedClaim.Spec.Resources).Decrease {
+			return true
+		}
+	}
+	return false
+}
+
 func claimMatchingName(claims []corev1.PersistentVolumeClaim, name string) *corev1.PersistentVolumeClaim {
 	for i, claim := range claims {
 		if claim.Name == name {
@@ -18,15 +18,11 @@ var warnings = []validation{
 func noUnsupportedSettings(es esv1.Elasticsearch) field.ErrorList {
 	var errs field.ErrorList
 	for i, nodeSet := range es.Spec.NodeSets {
-		if nodeSet.Config == nil {
-			continue
-		}
-		config, err := common.NewCanonicalConfigFrom(nodeSet.Config.Data)
+		unsupported, err := unsupportedSettings(nodeSet)
 		if err != nil {
 			errs = append(errs, field.Invalid(field.NewPath("spec").Child("nodeSets").Index(i).Child("config"), es.Spec.NodeSets[i].Config, cfgInvalidMsg))
 			continue
 		}
-		unsupported := config.HasKeys(esv1.UnsupportedSettings)
 		for _, setting := range unsupported {
 			errs = append(errs, field.Forbidden(field.NewPath("spec").Child("nodeSets").Index(i).Child("config").Child(setting), unsupportedConfigErrMsg))
 		}
@@ -34,6 +30,18 @@ func noUnsupportedSettings(es esv1.Elasticsearch) field.ErrorList {
 	return errs
 }
 
+// unsupportedSettings returns the settings reserved for internal use present in the configuration of the given NodeSet.
+func unsupportedSettings(nodeSet esv1.NodeSet) ([]string, error) {
+	if nodeSet.Config == nil {
+		return nil, nil
+	}
+	config, err := common.NewCanonicalConfigFrom(nodeSet.Config.Data)
+	if err != nil {
+		return nil, err
+	}
+	return config.HasKeys(esv1.UnsupportedSettings), nil
+}
+
 func CheckForWarnings(es esv1.Elasticsearch) error {
 	warnings := check(es, warnings)
 	if len(warnings) > 0 {
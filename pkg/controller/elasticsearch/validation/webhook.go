@@ -17,6 +17,7 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
 
 	esv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/elasticsearch/v1"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/certificates"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/license"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/utils/k8s"
 	ulog "github.com/elastic/cloud-on-k8s/v2/pkg/utils/log"
@@ -32,13 +33,16 @@ const (
 var eslog = ulog.Log.WithName("es-validation")
 
 // RegisterWebhook will register the Elasticsearch validating webhook.
-func RegisterWebhook(mgr ctrl.Manager, validateStorageClass bool, exposedNodeLabels NodeLabels, licenseChecker license.Checker, managedNamespaces []string) {
+// If maxCertificateIssuances is greater than 0, changes requiring new certificates are rejected in namespaces where at
+// least that many certificates were issued during the last certificates.IssuanceWindow.
+func RegisterWebhook(mgr ctrl.Manager, validateStorageClass bool, exposedNodeLabels NodeLabels, licenseChecker license.Checker, managedNamespaces []string, maxCertificateIssuances int) {
 	wh := &validatingWebhook{
-		client:               mgr.GetClient(),
-		validateStorageClass: validateStorageClass,
-		exposedNodeLabels:    exposedNodeLabels,
-		licenseChecker:       licenseChecker,
-		managedNamespaces:    set.Make(managedNamespaces...),
+		client:                  mgr.GetClient(),
+		validateStorageClass:    validateStorageClass,
+		exposedNodeLabels:       exposedNodeLabels,
+		licenseChecker:          licenseChecker,
+		managedNamespaces:       set.Make(managedNamespaces...),
+		maxCertificateIssuances: maxCertificateIssuances,
 	}
 	eslog.Info("Registering Elasticsearch validating webhook", "path", webhookPath)
 	mgr.GetWebhookServer().Register(webhookPath, &webhook.Admission{Handler: wh})
@@ -51,6 +55,9 @@ type validatingWebhook struct {
 	exposedNodeLabels    NodeLabels
 	licenseChecker       license.Checker
 	managedNamespaces    set.StringSet
+	// maxCertificateIssuances is the number of certificates issued in a namespace during the last issuance window
+	// above which changes requiring new certificates are rejected, 0 to disable the limit.
+	maxCertificateIssuances int
 }
 
 var _ admission.DecoderInjector = &validatingWebhook{}
@@ -61,8 +68,29 @@ func (wh *validatingWebhook) InjectDecoder(d *admission.Decoder) error {
 	return nil
 }
 
+// issuedCertificates returns a function counting the certificates issued in a namespace during the last issuance window,
+// from the issuance times recorded in its Secrets. Errors are logged and the count is then 0, consistently with the
+// failure policy of the webhook.
+func (wh *validatingWebhook) issuedCertificates(ctx context.Context) func(namespace string) int {
+	return func(namespace string) int {
+		count, err := certificates.IssuedInNamespace(ctx, wh.client, namespace)
+		if err != nil {
+			eslog.Error(err, "Failed to count the certificates issued in namespace", "namespace", namespace)
+			return 0
+		}
+		return count
+	}
+}
+
 func (wh *validatingWebhook) validateCreate(ctx context.Context, es esv1.Elasticsearch) error {
 	eslog.V(1).Info("validate create", "name", es.Name)
+	errs := check(es, createValidations)
+	errs = append(errs, validCertificateIssuancesOnCreate(es, wh.maxCertificateIssuances, wh.issuedCertificates(ctx))...)
+	if len(errs) > 0 {
+		return apierrors.NewInvalid(
+			schema.GroupKind{Group: "elasticsearch.k8s.elastic.co", Kind: esv1.Kind},
+			es.Name, errs)
+	}
 	return ValidateElasticsearch(ctx, es, wh.licenseChecker, wh.exposedNodeLabels)
 }
 
@@ -74,6 +102,7 @@ func (wh *validatingWebhook) validateUpdate(ctx context.Context, prev esv1.Elast
 			errs = append(errs, err...)
 		}
 	}
+	errs = append(errs, validCertificateIssuancesOnUpdate(prev, curr, wh.maxCertificateIssuances, wh.issuedCertificates(ctx))...)
 	if len(errs) > 0 {
 		return apierrors.NewInvalid(
 			schema.GroupKind{Group: "elasticsearch.k8s.elastic.co", Kind: esv1.Kind},
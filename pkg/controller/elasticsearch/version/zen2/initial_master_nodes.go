@@ -25,7 +25,7 @@ import (
 const (
 	// InitialMasterNodesAnnotation is applied on the Elasticsearch resource while a cluster is
 	// bootstrapping zen2, and removed when bootstrapping is done.
-	initialMasterNodesAnnotation = "elasticsearch.k8s.elastic.co/initial-master-nodes"
+	InitialMasterNodesAnnotation = "elasticsearch.k8s.elastic.co/initial-master-nodes"
 )
 
 // SetupInitialMasterNodes sets the `cluster.initial_master_nodes` configuration setting on
@@ -80,7 +80,7 @@ func shouldSetInitialMasterNodes(es esv1.Elasticsearch, k8sClient k8s.Client, no
 	return nonHAZen1MasterUpgrade(k8sClient, es, nodeSpecResources)
 }
 
-// RemoveZen2BootstrapAnnotation removes the initialMasterNodesAnnotation (if set) once zen2 is bootstrapped
+// RemoveZen2BootstrapAnnotation removes the InitialMasterNodesAnnotation (if set) once zen2 is bootstrapped
 // on the corresponding cluster.
 func RemoveZen2BootstrapAnnotation(ctx context.Context, k8sClient k8s.Client, es esv1.Elasticsearch, esClient client.Client) (bool, error) {
 	if v, err := version.Parse(es.Spec.Version); err != nil || !versionCompatibleWithZen2(v) {
@@ -106,7 +106,7 @@ func RemoveZen2BootstrapAnnotation(ctx context.Context, k8sClient k8s.Client, es
 		"es_name", es.Name,
 	)
 	// remove the annotation to indicate we're done with zen2 bootstrapping
-	delete(es.Annotations, initialMasterNodesAnnotation)
+	delete(es.Annotations, InitialMasterNodesAnnotation)
 	return false, k8sClient.Update(ctx, &es)
 }
 
@@ -182,18 +182,18 @@ func nonHAZen1MasterUpgrade(c k8s.Client, es esv1.Elasticsearch, nodeSpecResourc
 // annotations on es, or returns nil if not set.
 func getInitialMasterNodesAnnotation(es esv1.Elasticsearch) []string {
 	var nodes []string
-	if value := es.Annotations[initialMasterNodesAnnotation]; value != "" {
+	if value := es.Annotations[InitialMasterNodesAnnotation]; value != "" {
 		nodes = strings.Split(value, ",")
 	}
 	return nodes
 }
 
-// setInitialMasterNodesAnnotation sets initialMasterNodesAnnotation on the given es resource to initialMasterNodes,
+// setInitialMasterNodesAnnotation sets InitialMasterNodesAnnotation on the given es resource to initialMasterNodes,
 // and updates the es resource in the apiserver.
 func setInitialMasterNodesAnnotation(ctx context.Context, k8sClient k8s.Client, es esv1.Elasticsearch, initialMasterNodes []string) error {
 	if es.Annotations == nil {
 		es.Annotations = map[string]string{}
 	}
-	es.Annotations[initialMasterNodesAnnotation] = strings.Join(initialMasterNodes, ",")
+	es.Annotations[InitialMasterNodesAnnotation] = strings.Join(initialMasterNodes, ",")
 	return k8sClient.Update(ctx, &es)
 }
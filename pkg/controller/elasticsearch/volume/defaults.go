@@ -8,6 +8,8 @@ import (
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	esv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/elasticsearch/v1"
 )
 
 var (
@@ -52,6 +54,17 @@ var (
 	}
 )
 
+// HasLogsVolumeClaim returns true if the given volume claim templates include a claim for the logs volume, which then
+// replaces the default EmptyDir logs volume.
+func HasLogsVolumeClaim(claims []corev1.PersistentVolumeClaim) bool {
+	for _, claim := range claims {
+		if claim.Name == ElasticsearchLogsVolumeName {
+			return true
+		}
+	}
+	return false
+}
+
 // AppendDefaultDataVolumeMount appends a volume mount for the default data volume if the slice of volumes contains the default data volume.
 func AppendDefaultDataVolumeMount(mounts []corev1.VolumeMount, volumes []corev1.Volume) []corev1.VolumeMount {
 	for _, v := range volumes {
@@ -61,3 +74,30 @@ func AppendDefaultDataVolumeMount(mounts []corev1.VolumeMount, volumes []corev1.
 	}
 	return mounts
 }
+
+// MemoryDataVolume returns the emptyDir data volume backed by memory requested by the given NodeSet, if any.
+func MemoryDataVolume(nodeSet esv1.NodeSet) (corev1.Volume, bool) {
+	if nodeSet.MemoryDataVolume == nil {
+		return corev1.Volume{}, false
+	}
+	sizeLimit := nodeSet.MemoryDataVolume.SizeLimit.DeepCopy()
+	return corev1.Volume{
+		Name: ElasticsearchDataVolumeName,
+		VolumeSource: corev1.VolumeSource{
+			EmptyDir: &corev1.EmptyDirVolumeSource{
+				Medium:    corev1.StorageMediumMemory,
+				SizeLimit: &sizeLimit,
+			},
+		},
+	}, true
+}
+
+// PodSpecWithMemoryDataVolume returns the Pod spec of the given NodeSet including its memory-backed data volume, if any,
+// so that the default data volume claim is not appended to the volume claim templates of the NodeSet.
+func PodSpecWithMemoryDataVolume(nodeSet esv1.NodeSet) corev1.PodSpec {
+	podSpec := nodeSet.PodTemplate.Spec
+	if dataVolume, ok := MemoryDataVolume(nodeSet); ok {
+		podSpec.Volumes = append(append([]corev1.Volume{}, podSpec.Volumes...), dataVolume)
+	}
+	return podSpec
+}
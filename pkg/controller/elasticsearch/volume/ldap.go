@@ -0,0 +1,50 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package volume
+
+import (
+	"path"
+
+	corev1 "k8s.io/api/core/v1"
+
+	esv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/elasticsearch/v1"
+)
+
+// LDAPRealmMountPath returns the directory in which the certificate authority of the given LDAP realm is mounted in the
+// Elasticsearch container. Files referenced in the realm settings must be located in the configuration directory.
+func LDAPRealmMountPath(realm esv1.LDAPRealm) string {
+	return path.Join(LDAPVolumesMountPath, realm.Name)
+}
+
+// LDAPCAPath returns the path of the certificate authority of the LDAP servers of the given realm.
+func LDAPCAPath(realm esv1.LDAPRealm) string {
+	return path.Join(LDAPRealmMountPath(realm), esv1.LDAPCertificateAuthorityKey)
+}
+
+// LDAPVolumes returns the Pod volumes and the Elasticsearch container volume mounts for the certificate authorities of
+// the given LDAP realms.
+func LDAPVolumes(realms []esv1.LDAPRealm) ([]corev1.Volume, []corev1.VolumeMount) {
+	var volumes []corev1.Volume
+	var mounts []corev1.VolumeMount
+	for _, realm := range realms {
+		if realm.CASecretName == "" {
+			continue
+		}
+		volume := corev1.Volume{
+			Name: LDAPCAVolumeNamePrefix + realm.Name,
+			VolumeSource: corev1.VolumeSource{
+				Secret: &corev1.SecretVolumeSource{
+					SecretName: realm.CASecretName,
+					Items: []corev1.KeyToPath{
+						{Key: esv1.LDAPCertificateAuthorityKey, Path: esv1.LDAPCertificateAuthorityKey},
+					},
+				},
+			},
+		}
+		volumes = append(volumes, volume)
+		mounts = append(mounts, corev1.VolumeMount{Name: volume.Name, MountPath: LDAPRealmMountPath(realm), ReadOnly: true})
+	}
+	return volumes, mounts
+}
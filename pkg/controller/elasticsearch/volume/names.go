@@ -36,6 +36,21 @@ const (
 	ElasticsearchLogsVolumeName = "elasticsearch-logs"
 	ElasticsearchLogsMountPath  = "/usr/share/elasticsearch/logs"
 
+	SnapshotVolumeNamePrefix = "snapshots-"
+	SnapshotVolumesMountPath = "/usr/share/elasticsearch/snapshots"
+
+	SAMLMetadataVolumeNamePrefix = "saml-idp-"
+	SAMLSigningVolumeNamePrefix  = "saml-sign-"
+	SAMLVolumesMountPath         = "/usr/share/elasticsearch/config/saml"
+	SAMLMetadataPathSegment      = "idp"
+	SAMLMetadataFile             = "metadata.xml"
+	SAMLSigningPathSegment       = "signing"
+	SAMLSigningCertFile          = "tls.crt"
+	SAMLSigningKeyFile           = "tls.key"
+
+	LDAPCAVolumeNamePrefix = "ldap-ca-"
+	LDAPVolumesMountPath   = "/usr/share/elasticsearch/config/ldap"
+
 	ScriptsVolumeName      = "elastic-internal-scripts"
 	ScriptsVolumeMountPath = "/mnt/elastic-internal/scripts"
 
@@ -0,0 +1,77 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package volume
+
+import (
+	"path"
+
+	corev1 "k8s.io/api/core/v1"
+
+	esv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/elasticsearch/v1"
+)
+
+// SAMLRealmMountPath returns the directory in which the files of the given SAML realm are mounted in the
+// Elasticsearch container. Files referenced in the realm settings must be located in the configuration directory.
+func SAMLRealmMountPath(realm esv1.SAMLRealm) string {
+	return path.Join(SAMLVolumesMountPath, realm.Name)
+}
+
+// SAMLMetadataPath returns the path of the identity provider metadata file of the given SAML realm.
+func SAMLMetadataPath(realm esv1.SAMLRealm) string {
+	return path.Join(SAMLRealmMountPath(realm), SAMLMetadataPathSegment, SAMLMetadataFile)
+}
+
+// SAMLSigningCertificatePath returns the path of the signing certificate of the given SAML realm.
+func SAMLSigningCertificatePath(realm esv1.SAMLRealm) string {
+	return path.Join(SAMLRealmMountPath(realm), SAMLSigningPathSegment, SAMLSigningCertFile)
+}
+
+// SAMLSigningKeyPath returns the path of the signing private key of the given SAML realm.
+func SAMLSigningKeyPath(realm esv1.SAMLRealm) string {
+	return path.Join(SAMLRealmMountPath(realm), SAMLSigningPathSegment, SAMLSigningKeyFile)
+}
+
+// SAMLVolumes returns the Pod volumes and the Elasticsearch container volume mounts for the identity provider metadata
+// and the signing keys of the given SAML realms.
+func SAMLVolumes(realms []esv1.SAMLRealm) ([]corev1.Volume, []corev1.VolumeMount) {
+	var volumes []corev1.Volume
+	var mounts []corev1.VolumeMount
+	for _, realm := range realms {
+		if realm.IdP.MetadataConfigMapName != "" {
+			volume := corev1.Volume{
+				Name: SAMLMetadataVolumeNamePrefix + realm.Name,
+				VolumeSource: corev1.VolumeSource{
+					ConfigMap: &corev1.ConfigMapVolumeSource{
+						LocalObjectReference: corev1.LocalObjectReference{Name: realm.IdP.MetadataConfigMapName},
+						Items:                []corev1.KeyToPath{{Key: SAMLMetadataFile, Path: SAMLMetadataFile}},
+					},
+				},
+			}
+			volumes = append(volumes, volume)
+			mounts = append(mounts, corev1.VolumeMount{
+				Name: volume.Name, MountPath: path.Join(SAMLRealmMountPath(realm), SAMLMetadataPathSegment), ReadOnly: true,
+			})
+		}
+		if realm.SigningSecretName != "" {
+			volume := corev1.Volume{
+				Name: SAMLSigningVolumeNamePrefix + realm.Name,
+				VolumeSource: corev1.VolumeSource{
+					Secret: &corev1.SecretVolumeSource{
+						SecretName: realm.SigningSecretName,
+						Items: []corev1.KeyToPath{
+							{Key: SAMLSigningCertFile, Path: SAMLSigningCertFile},
+							{Key: SAMLSigningKeyFile, Path: SAMLSigningKeyFile},
+						},
+					},
+				},
+			}
+			volumes = append(volumes, volume)
+			mounts = append(mounts, corev1.VolumeMount{
+				Name: volume.Name, MountPath: path.Join(SAMLRealmMountPath(realm), SAMLSigningPathSegment), ReadOnly: true,
+			})
+		}
+	}
+	return volumes, mounts
+}
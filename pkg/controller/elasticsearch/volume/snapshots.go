@@ -0,0 +1,44 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package volume
+
+import (
+	"path"
+
+	corev1 "k8s.io/api/core/v1"
+
+	esv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/elasticsearch/v1"
+)
+
+// SnapshotVolumeMountPath returns the path where the given snapshot volume is mounted in the Elasticsearch container.
+func SnapshotVolumeMountPath(snapshotVolume esv1.SnapshotVolume) string {
+	return path.Join(SnapshotVolumesMountPath, snapshotVolume.Name)
+}
+
+// SnapshotRepositoryPaths returns the paths to register in the path.repo setting for the given snapshot volumes.
+func SnapshotRepositoryPaths(snapshotVolumes []esv1.SnapshotVolume) []string {
+	paths := make([]string, 0, len(snapshotVolumes))
+	for _, v := range snapshotVolumes {
+		paths = append(paths, SnapshotVolumeMountPath(v))
+	}
+	return paths
+}
+
+// SnapshotVolumes returns the Pod volumes and the Elasticsearch container volume mounts for the given snapshot volumes.
+func SnapshotVolumes(snapshotVolumes []esv1.SnapshotVolume) ([]corev1.Volume, []corev1.VolumeMount) {
+	volumes := make([]corev1.Volume, 0, len(snapshotVolumes))
+	mounts := make([]corev1.VolumeMount, 0, len(snapshotVolumes))
+	for _, v := range snapshotVolumes {
+		volume := corev1.Volume{Name: SnapshotVolumeNamePrefix + v.Name}
+		if v.NFS != nil {
+			volume.NFS = v.NFS.DeepCopy()
+		} else {
+			volume.PersistentVolumeClaim = &corev1.PersistentVolumeClaimVolumeSource{ClaimName: v.ClaimName}
+		}
+		volumes = append(volumes, volume)
+		mounts = append(mounts, corev1.VolumeMount{Name: volume.Name, MountPath: SnapshotVolumeMountPath(v)})
+	}
+	return volumes, mounts
+}
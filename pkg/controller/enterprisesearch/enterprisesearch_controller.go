@@ -31,6 +31,7 @@ import (
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/defaults"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/driver"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/events"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/expose"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/operator"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/reconciler"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/tracing"
@@ -160,6 +161,11 @@ func (r *ReconcileEnterpriseSearch) Reconcile(ctx context.Context, request recon
 		return reconcile.Result{}, nil
 	}
 
+	// do not reconcile resources last managed by a newer operator
+	if managed, err := common.ReconcileOperatorVersion(ctx, r.Client, &ent); err != nil || !managed {
+		return reconcile.Result{}, tracing.CaptureError(ctx, err)
+	}
+
 	results, status := r.doReconcile(ctx, ent)
 	if err := r.updateStatus(ctx, ent, status); err != nil {
 		if apierrors.IsConflict(err) {
@@ -203,15 +209,18 @@ func (r *ReconcileEnterpriseSearch) doReconcile(ctx context.Context, ent entv1.E
 	_, results = certificates.Reconciler{
 		K8sClient:             r.K8sClient(),
 		DynamicWatches:        r.DynamicWatches(),
+		Recorder:              r.Recorder(),
 		Owner:                 &ent,
 		TLSOptions:            ent.Spec.HTTP.TLS,
 		Namer:                 entv1.Namer,
 		Labels:                Labels(ent.Name),
 		Services:              []corev1.Service{*svc},
 		GlobalCA:              r.GlobalCA,
+		NamespaceCA:           r.NamespaceCA,
 		CACertRotation:        r.CACertRotation,
 		CertRotation:          r.CertRotation,
 		GarbageCollectSecrets: true,
+		ExtraHTTPSANs:         expose.SubjectAltNames(ent.Spec.HTTP),
 	}.ReconcileCAAndHTTPCerts(ctx)
 	if results.HasError() {
 		_, err := results.Aggregate()
@@ -219,6 +228,18 @@ func (r *ReconcileEnterpriseSearch) doReconcile(ctx context.Context, ent entv1.E
 		return results, status
 	}
 
+	err = expose.Reconciler{
+		K8sClient: r.Client,
+		Owner:     &ent,
+		HTTP:      ent.Spec.HTTP,
+		Service:   *svc,
+		Namer:     entv1.Namer,
+		Labels:    Labels(ent.Name),
+	}.Reconcile(ctx)
+	if err != nil {
+		return results.WithError(err), status
+	}
+
 	entVersion, err := version.Parse(ent.Spec.Version)
 	if err != nil {
 		return results.WithError(err), status
@@ -248,6 +269,14 @@ func (r *ReconcileEnterpriseSearch) doReconcile(ctx context.Context, ent entv1.E
 		return results.WithError(fmt.Errorf("build config hash: %w", err)), status
 	}
 
+	esReady, err := isElasticsearchReady(ctx, r.K8sClient(), ent)
+	if err != nil {
+		return results.WithError(err), status
+	}
+	if !esReady {
+		return results.WithResult(reconcile.Result{RequeueAfter: ElasticsearchNotReadyRequeue}), status
+	}
+
 	deploy, err := r.reconcileDeployment(ctx, ent, configHash)
 	if err != nil {
 		return results.WithError(fmt.Errorf("reconcile deployment: %w", err)), status
@@ -214,7 +214,10 @@ func TestReconcileEnterpriseSearch_Reconcile_Create_Update_Resources(t *testing.
 
 func TestReconcileEnterpriseSearch_doReconcile_AssociationDelaysVersionUpgrade(t *testing.T) {
 	// associate Enterprise Search 7.7.0 to Elasticsearch 7.7.0
-	es := esv1.Elasticsearch{ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "some-es"}}
+	es := esv1.Elasticsearch{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "some-es"},
+		Status:     esv1.ElasticsearchStatus{Health: esv1.ElasticsearchGreenHealth},
+	}
 	ent := entv1.EnterpriseSearch{
 		ObjectMeta: metav1.ObjectMeta{
 			Namespace: "ns",
@@ -0,0 +1,60 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package enterprisesearch
+
+import (
+	"context"
+	"time"
+
+	appsv1 "k8s.io/api/apps/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+
+	esv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/elasticsearch/v1"
+	entv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/enterprisesearch/v1"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/utils/k8s"
+	ulog "github.com/elastic/cloud-on-k8s/v2/pkg/utils/log"
+)
+
+// ElasticsearchNotReadyRequeue is the delay after which the creation of the Enterprise Search Deployment is retried
+// while the referenced Elasticsearch cluster is not ready.
+const ElasticsearchNotReadyRequeue = 10 * time.Second
+
+// isElasticsearchReady returns true if the Enterprise Search Deployment can be created.
+// Enterprise Search creates its indices on startup and exits if Elasticsearch cannot serve them, so its Pods are not
+// created before the referenced Elasticsearch cluster reports a yellow or green health. Once the Deployment exists it
+// is always reconciled, to not prevent changes to a running Enterprise Search while Elasticsearch is unhealthy.
+// External Elasticsearch clusters referenced through a Secret are not checked.
+func isElasticsearchReady(ctx context.Context, c k8s.Client, ent entv1.EnterpriseSearch) (bool, error) {
+	esRef := ent.AssociationRef()
+	if !esRef.IsDefined() || esRef.IsExternal() {
+		return true, nil
+	}
+
+	var deploy appsv1.Deployment
+	err := c.Get(ctx, types.NamespacedName{Namespace: ent.Namespace, Name: DeploymentName(ent.Name)}, &deploy)
+	if err == nil {
+		return true, nil
+	}
+	if !apierrors.IsNotFound(err) {
+		return false, err
+	}
+
+	var es esv1.Elasticsearch
+	if err := c.Get(ctx, esRef.NamespacedName(), &es); err != nil {
+		if apierrors.IsNotFound(err) {
+			return false, nil
+		}
+		return false, err
+	}
+	switch es.Status.Health {
+	case esv1.ElasticsearchGreenHealth, esv1.ElasticsearchYellowHealth:
+		return true, nil
+	default:
+		ulog.FromContext(ctx).Info("Delaying the creation of the Enterprise Search deployment until Elasticsearch is ready",
+			"namespace", ent.Namespace, "ent_name", ent.Name, "es_name", es.Name, "es_health", es.Status.Health)
+		return false, nil
+	}
+}
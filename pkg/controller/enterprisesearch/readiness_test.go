@@ -0,0 +1,95 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package enterprisesearch
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	commonv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/common/v1"
+	esv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/elasticsearch/v1"
+	entv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/enterprisesearch/v1"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/utils/k8s"
+)
+
+func Test_isElasticsearchReady(t *testing.T) {
+	entWithRef := func(ref commonv1.ObjectSelector) entv1.EnterpriseSearch {
+		return entv1.EnterpriseSearch{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "ent"},
+			Spec:       entv1.EnterpriseSearchSpec{ElasticsearchRef: ref},
+		}
+	}
+	esWithHealth := func(health esv1.ElasticsearchHealth) *esv1.Elasticsearch {
+		return &esv1.Elasticsearch{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "es"},
+			Status:     esv1.ElasticsearchStatus{Health: health},
+		}
+	}
+	deploy := &appsv1.Deployment{ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: DeploymentName("ent")}}
+	tests := []struct {
+		name string
+		ent  entv1.EnterpriseSearch
+		objs []runtime.Object
+		want bool
+	}{
+		{
+			name: "no Elasticsearch reference",
+			ent:  entWithRef(commonv1.ObjectSelector{}),
+			want: true,
+		},
+		{
+			name: "external Elasticsearch",
+			ent:  entWithRef(commonv1.ObjectSelector{SecretName: "es-ref"}),
+			want: true,
+		},
+		{
+			name: "Elasticsearch does not exist yet",
+			ent:  entWithRef(commonv1.ObjectSelector{Name: "es"}),
+			want: false,
+		},
+		{
+			name: "Elasticsearch health unknown",
+			ent:  entWithRef(commonv1.ObjectSelector{Name: "es"}),
+			objs: []runtime.Object{esWithHealth(esv1.ElasticsearchUnknownHealth)},
+			want: false,
+		},
+		{
+			name: "Elasticsearch red",
+			ent:  entWithRef(commonv1.ObjectSelector{Name: "es"}),
+			objs: []runtime.Object{esWithHealth(esv1.ElasticsearchRedHealth)},
+			want: false,
+		},
+		{
+			name: "Elasticsearch yellow",
+			ent:  entWithRef(commonv1.ObjectSelector{Name: "es"}),
+			objs: []runtime.Object{esWithHealth(esv1.ElasticsearchYellowHealth)},
+			want: true,
+		},
+		{
+			name: "Elasticsearch green",
+			ent:  entWithRef(commonv1.ObjectSelector{Name: "es"}),
+			objs: []runtime.Object{esWithHealth(esv1.ElasticsearchGreenHealth)},
+			want: true,
+		},
+		{
+			name: "Elasticsearch red but the Deployment already exists",
+			ent:  entWithRef(commonv1.ObjectSelector{Name: "es"}),
+			objs: []runtime.Object{esWithHealth(esv1.ElasticsearchRedHealth), deploy},
+			want: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := isElasticsearchReady(context.Background(), k8s.NewFakeClient(tt.objs...), tt.ent)
+			require.NoError(t, err)
+			require.Equal(t, tt.want, got)
+		})
+	}
+}
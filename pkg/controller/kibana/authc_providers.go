@@ -0,0 +1,82 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package kibana
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+
+	esv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/elasticsearch/v1"
+	kbv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/kibana/v1"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/version"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/watches"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/utils/k8s"
+)
+
+// AuthcProvidersMinVersion is the minimum version of Kibana in which authentication providers are configured for the
+// SAML and OIDC realms of the associated Elasticsearch cluster. The object syntax of the providers was introduced in
+// 7.10.0.
+var AuthcProvidersMinVersion = version.MinFor(7, 10, 0)
+
+// elasticsearchWatchName returns the name of the watch registered on the associated Elasticsearch resource.
+func elasticsearchWatchName(kb types.NamespacedName) string {
+	return fmt.Sprintf("%s-%s-elasticsearch", kb.Namespace, kb.Name)
+}
+
+// watchElasticsearch watches the Elasticsearch resource associated with the given Kibana, if it is managed by the
+// operator, to update the authentication providers when its SAML or OIDC realms change.
+func watchElasticsearch(kb kbv1.Kibana, watched watches.DynamicWatches) error {
+	kbKey := k8s.ExtractNamespacedName(&kb)
+	esRef := kb.EsAssociation().AssociationRef()
+	if !esRef.IsDefined() || esRef.IsExternal() {
+		watched.ReferencedResources.RemoveHandlerForKey(elasticsearchWatchName(kbKey))
+		return nil
+	}
+	return watched.ReferencedResources.AddHandler(watches.NamedWatch{
+		Name:    elasticsearchWatchName(kbKey),
+		Watched: []types.NamespacedName{esRef.NamespacedName()},
+		Watcher: kbKey,
+	})
+}
+
+// authcProvidersSettings returns the authentication providers matching the SAML and OIDC realms of the associated
+// Elasticsearch cluster, if it is managed by the operator. The basic provider comes first to keep the login form for the
+// users of the native and file realms, followed by the SAML then the OIDC providers.
+func authcProvidersSettings(ctx context.Context, c k8s.Client, kb kbv1.Kibana, v version.Version) (map[string]interface{}, error) {
+	cfg := map[string]interface{}{}
+	esRef := kb.EsAssociation().AssociationRef()
+	if !esRef.IsDefined() || esRef.IsExternal() || v.LT(AuthcProvidersMinVersion) {
+		return cfg, nil
+	}
+	var es esv1.Elasticsearch
+	if err := c.Get(ctx, esRef.NamespacedName(), &es); err != nil {
+		if apierrors.IsNotFound(err) {
+			return cfg, nil
+		}
+		return nil, err
+	}
+	// providers are named after the realms they authenticate against
+	var providers []string
+	for _, realm := range es.Spec.Auth.SAML {
+		providers = append(providers, "saml."+realm.Name)
+	}
+	for _, realm := range es.Spec.Auth.OIDC {
+		providers = append(providers, "oidc."+realm.Name)
+	}
+	if len(providers) == 0 {
+		return cfg, nil
+	}
+	cfg[XpackSecurityAuthcProviders+".basic.basic1.order"] = 0
+	for i, provider := range providers {
+		prefix := XpackSecurityAuthcProviders + "." + provider + "."
+		cfg[prefix+"order"] = i + 1
+		cfg[prefix+"realm"] = provider[strings.Index(provider, ".")+1:]
+	}
+	return cfg, nil
+}
@@ -0,0 +1,102 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package kibana
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	commonv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/common/v1"
+	esv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/elasticsearch/v1"
+	kbv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/kibana/v1"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/version"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/watches"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/utils/k8s"
+)
+
+func Test_authcProvidersSettings(t *testing.T) {
+	es := esv1.Elasticsearch{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "es-ns", Name: "es"},
+		Spec: esv1.ElasticsearchSpec{Auth: esv1.Auth{
+			SAML: []esv1.SAMLRealm{{Name: "okta"}, {Name: "adfs"}},
+			OIDC: []esv1.OIDCRealm{{Name: "google"}},
+		}},
+	}
+	esWithoutRealms := esv1.Elasticsearch{ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "es"}}
+	tests := []struct {
+		name    string
+		esRef   commonv1.ObjectSelector
+		version version.Version
+		want    map[string]interface{}
+	}{
+		{
+			name:    "providers for the SAML and OIDC realms of the associated Elasticsearch",
+			esRef:   commonv1.ObjectSelector{Namespace: "es-ns", Name: "es"},
+			version: version.MustParse("8.5.0"),
+			want: map[string]interface{}{
+				"xpack.security.authc.providers.basic.basic1.order": 0,
+				"xpack.security.authc.providers.saml.okta.order":    1,
+				"xpack.security.authc.providers.saml.okta.realm":    "okta",
+				"xpack.security.authc.providers.saml.adfs.order":    2,
+				"xpack.security.authc.providers.saml.adfs.realm":    "adfs",
+				"xpack.security.authc.providers.oidc.google.order":  3,
+				"xpack.security.authc.providers.oidc.google.realm":  "google",
+			},
+		},
+		{
+			name:    "no SAML or OIDC realm in the associated Elasticsearch",
+			esRef:   commonv1.ObjectSelector{Name: "es"},
+			version: version.MustParse("8.5.0"),
+			want:    map[string]interface{}{},
+		},
+		{
+			name:    "associated Elasticsearch not found",
+			esRef:   commonv1.ObjectSelector{Namespace: "es-ns", Name: "unknown"},
+			version: version.MustParse("8.5.0"),
+			want:    map[string]interface{}{},
+		},
+		{
+			name:    "external Elasticsearch",
+			esRef:   commonv1.ObjectSelector{SecretName: "es-ref"},
+			version: version.MustParse("8.5.0"),
+			want:    map[string]interface{}{},
+		},
+		{
+			name:    "providers object syntax not supported",
+			esRef:   commonv1.ObjectSelector{Namespace: "es-ns", Name: "es"},
+			version: version.MustParse("7.9.3"),
+			want:    map[string]interface{}{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			kb := kbv1.Kibana{
+				ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "kb"},
+				Spec:       kbv1.KibanaSpec{ElasticsearchRef: tt.esRef},
+			}
+			got, err := authcProvidersSettings(context.Background(), k8s.NewFakeClient(&es, &esWithoutRealms), kb, tt.version)
+			require.NoError(t, err)
+			require.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func Test_watchElasticsearch(t *testing.T) {
+	kb := kbv1.Kibana{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "kb"},
+		Spec:       kbv1.KibanaSpec{ElasticsearchRef: commonv1.ObjectSelector{Name: "es"}},
+	}
+	watched := watches.NewDynamicWatches()
+	require.NoError(t, watchElasticsearch(kb, watched))
+	require.Equal(t, []string{"ns-kb-elasticsearch"}, watched.ReferencedResources.Registrations())
+
+	// the watch is removed with the reference to Elasticsearch
+	kb.Spec.ElasticsearchRef = commonv1.ObjectSelector{}
+	require.NoError(t, watchElasticsearch(kb, watched))
+	require.Empty(t, watched.ReferencedResources.Registrations())
+}
@@ -36,11 +36,15 @@ const (
 	SettingsFilename = "kibana.yml"
 	// EnvNodeOptions is the environment variable name for the Node options that can be used to increase the Kibana maximum memory limit
 	EnvNodeOptions = "NODE_OPTIONS"
+	// EnvNodeExtraCACerts is the environment variable name for the additional CA certificates trusted by the Kibana server
+	EnvNodeExtraCACerts = "NODE_EXTRA_CA_CERTS"
 
 	// esCertsVolumeMountPath is the directory containing Elasticsearch certificates.
 	esCertsVolumeMountPath = "/usr/share/kibana/config/elasticsearch-certs"
 	// entCertsVolumeMountPath is the directory into which trusted Enterprise Search HTTP CA certs are mounted.
 	entCertsVolumeMountPath = "/usr/share/kibana/config/ent-certs"
+	// emsCertsVolumeMountPath is the directory into which trusted Elastic Maps Server HTTP CA certs are mounted.
+	emsCertsVolumeMountPath = "/usr/share/kibana/config/ems-certs"
 )
 
 // Constants to use for the Kibana configuration settings.
@@ -54,6 +58,7 @@ const (
 	XpackReportingEncryptionKey                    = "xpack.reporting.encryptionKey"
 	XpackEncryptedSavedObjects                     = "xpack.encryptedSavedObjects"
 	XpackEncryptedSavedObjectsEncryptionKey        = "xpack.encryptedSavedObjects.encryptionKey"
+	XpackSecurityAuthcProviders                    = "xpack.security.authc.providers"
 
 	ElasticsearchSslCertificateAuthorities = "elasticsearch.ssl.certificateAuthorities"
 	ElasticsearchSslVerificationMode       = "elasticsearch.ssl.verificationMode"
@@ -81,18 +86,25 @@ type CanonicalConfig struct {
 
 // NewConfigSettings returns the Kibana configuration settings for the given Kibana resource.
 func NewConfigSettings(ctx context.Context, client k8s.Client, kb kbv1.Kibana, v version.Version, ipFamily corev1.IPFamily) (CanonicalConfig, error) {
+	cfg, _, err := NewConfigSettingsWithOverrides(ctx, client, kb, v, ipFamily)
+	return cfg, err
+}
+
+// NewConfigSettingsWithOverrides is like NewConfigSettings but also returns the settings defined with different values
+// by several configuration sources, for example operator-managed settings overridden by the user configuration.
+func NewConfigSettingsWithOverrides(ctx context.Context, client k8s.Client, kb kbv1.Kibana, v version.Version, ipFamily corev1.IPFamily) (CanonicalConfig, settings.ConfigOverrides, error) {
 	span, _ := apm.StartSpan(ctx, "new_config_settings", tracing.SpanTypeApp)
 	defer span.End()
 
 	reusableSettings, err := getOrCreateReusableSettings(ctx, client, kb)
 	if err != nil {
-		return CanonicalConfig{}, err
+		return CanonicalConfig{}, nil, err
 	}
 
 	// hack to support pre-7.6.0 Kibana configs as it errors out with unsupported keys, ideally we would not unpack empty values and could skip this
 	err = filterConfigSettings(kb, reusableSettings)
 	if err != nil {
-		return CanonicalConfig{}, err
+		return CanonicalConfig{}, nil, err
 	}
 
 	// parse user-provided settings
@@ -102,42 +114,37 @@ func NewConfigSettings(ctx context.Context, client k8s.Client, kb kbv1.Kibana, v
 	}
 	userSettings, err := settings.NewCanonicalConfigFrom(specConfig.Data)
 	if err != nil {
-		return CanonicalConfig{}, err
+		return CanonicalConfig{}, nil, err
 	}
 
 	baseSettingsMap, err := baseSettings(&kb, ipFamily)
 	if err != nil {
-		return CanonicalConfig{}, err
+		return CanonicalConfig{}, nil, err
 	}
 
-	cfg := settings.MustCanonicalConfig(baseSettingsMap)
-	kibanaTLSCfg := settings.MustCanonicalConfig(kibanaTLSSettings(kb))
-	versionSpecificCfg := VersionDefaults(&kb, v)
-	entSearchCfg := settings.MustCanonicalConfig(enterpriseSearchSettings(kb))
 	monitoringCfg, err := settings.NewCanonicalConfigFrom(stackmon.MonitoringConfig(kb).Data)
 	if err != nil {
-		return CanonicalConfig{}, err
+		return CanonicalConfig{}, nil, err
 	}
 
-	err = cfg.MergeWith(
-		reusableSettings,
-		versionSpecificCfg,
-		kibanaTLSCfg,
-		entSearchCfg,
-		monitoringCfg)
-	if err != nil {
-		return CanonicalConfig{}, err
+	sources := []settings.ConfigSource{
+		{Name: "operator base settings", Config: settings.MustCanonicalConfig(baseSettingsMap)},
+		{Name: "operator generated secrets", Config: reusableSettings},
+		{Name: "operator version defaults", Config: VersionDefaults(&kb, v)},
+		{Name: "operator TLS settings", Config: settings.MustCanonicalConfig(kibanaTLSSettings(kb))},
+		{Name: "Enterprise Search association", Config: settings.MustCanonicalConfig(enterpriseSearchSettings(kb))},
+		{Name: "stack monitoring settings", Config: monitoringCfg},
 	}
 
 	// Elasticsearch configuration
 	esAssocConf, err := kb.EsAssociation().AssociationConf()
 	if err != nil {
-		return CanonicalConfig{}, err
+		return CanonicalConfig{}, nil, err
 	}
 	if esAssocConf.IsConfigured() {
 		credentials, err := association.ElasticsearchAuthSettings(ctx, client, kb.EsAssociation())
 		if err != nil {
-			return CanonicalConfig{}, err
+			return CanonicalConfig{}, nil, err
 		}
 		var esCreds map[string]interface{}
 		if credentials.HasServiceAccountToken() {
@@ -150,19 +157,25 @@ func NewConfigSettings(ctx context.Context, client k8s.Client, kb kbv1.Kibana, v
 				ElasticsearchPassword: credentials.Password,
 			}
 		}
-		credentialsCfg := settings.MustCanonicalConfig(esCreds)
-		esAssocCfg := settings.MustCanonicalConfig(elasticsearchTLSSettings(*esAssocConf))
-		if err = cfg.MergeWith(esAssocCfg, credentialsCfg); err != nil {
-			return CanonicalConfig{}, err
+		authcProviders, err := authcProvidersSettings(ctx, client, kb, v)
+		if err != nil {
+			return CanonicalConfig{}, nil, err
 		}
+		sources = append(sources,
+			settings.ConfigSource{Name: "Elasticsearch association", Config: settings.MustCanonicalConfig(elasticsearchTLSSettings(*esAssocConf))},
+			settings.ConfigSource{Name: "Elasticsearch credentials", Config: settings.MustCanonicalConfig(esCreds)},
+			settings.ConfigSource{Name: "Elasticsearch realms", Config: settings.MustCanonicalConfig(authcProviders)},
+		)
 	}
 
 	// merge the configuration with userSettings last so they take precedence
-	if err = cfg.MergeWith(userSettings); err != nil {
-		return CanonicalConfig{}, err
-	}
+	sources = append(sources, settings.ConfigSource{Name: "user configuration", Config: userSettings})
 
-	return CanonicalConfig{cfg}, nil
+	cfg, overrides, err := settings.MergeSources(sources...)
+	if err != nil {
+		return CanonicalConfig{}, nil, err
+	}
+	return CanonicalConfig{cfg}, overrides, nil
 }
 
 // Some previously-unsupported keys cause Kibana to error out even if the values are empty. ucfg cannot ignore fields easily so this is necessary to
@@ -320,6 +333,15 @@ func entCaCertSecretVolume(entAssocConf commonv1.AssociationConf) volume.SecretV
 	)
 }
 
+// emsCaCertSecretVolume returns a SecretVolume to hold the Elastic Maps Server CA certs for the given Kibana resource.
+func emsCaCertSecretVolume(emsAssocConf commonv1.AssociationConf) volume.SecretVolume {
+	return volume.NewSecretVolumeWithMountPath(
+		emsAssocConf.GetCASecretName(),
+		"ems-certs",
+		emsCertsVolumeMountPath,
+	)
+}
+
 func enterpriseSearchSettings(kb kbv1.Kibana) map[string]interface{} {
 	cfg := map[string]interface{}{}
 	assocConf, _ := kb.EntAssociation().AssociationConf()
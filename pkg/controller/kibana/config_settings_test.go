@@ -407,6 +407,30 @@ func TestNewConfigSettings(t *testing.T) {
 				return bytes
 			}(),
 			wantErr: false,
+		}, {
+			name: "with Elastic Maps Server association: the in-cluster URL is not set",
+			args: args{
+				kb: func() kbv1.Kibana {
+					kb := mkKibana()
+					kb.Spec.ElasticMapsServerRef = commonv1.ObjectSelector{Name: "test-ems"}
+					kb.EMSAssociation().SetAssociationConf(&commonv1.AssociationConf{
+						AuthSecretName: "-",
+						CASecretName:   "ems-ca-secret",
+						CACertProvided: true,
+						URL:            "https://ems-url:8080",
+					})
+					return kb
+				},
+				client:   k8s.NewFakeClient(existingSecret),
+				ipFamily: corev1.IPv4Protocol,
+			},
+			want: func() []byte {
+				cfg, err := settings.ParseConfig(defaultConfig)
+				require.NoError(t, err)
+				bytes, err := cfg.Render()
+				require.NoError(t, err)
+				return bytes
+			}(),
 		}, {
 			name: "with Elasticsearch and Enterprise Search associations",
 			args: args{
@@ -22,6 +22,7 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 	"sigs.k8s.io/controller-runtime/pkg/source"
 
+	esv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/elasticsearch/v1"
 	kbv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/kibana/v1"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/certificates"
@@ -101,8 +102,18 @@ func addWatches(c controller.Controller, r *ReconcileKibana) error {
 		return err
 	}
 
+	// dynamically watch the associated Elasticsearch resources for changes of their SAML and OIDC realms
+	if err := c.Watch(&source.Kind{Type: &esv1.Elasticsearch{}}, r.dynamicWatches.ReferencedResources); err != nil {
+		return err
+	}
+
 	// dynamically watch referenced secrets to connect to Elasticsearch
-	return c.Watch(&source.Kind{Type: &corev1.Secret{}}, r.dynamicWatches.Secrets)
+	if err := c.Watch(&source.Kind{Type: &corev1.Secret{}}, r.dynamicWatches.Secrets); err != nil {
+		return err
+	}
+
+	// dynamically watch the ConfigMaps holding the saved objects to import
+	return c.Watch(&source.Kind{Type: &corev1.ConfigMap{}}, r.dynamicWatches.ConfigMaps)
 }
 
 var _ reconcile.Reconciler = &ReconcileKibana{}
@@ -146,6 +157,11 @@ func (r *ReconcileKibana) Reconcile(ctx context.Context, request reconcile.Reque
 		return reconcile.Result{}, nil
 	}
 
+	// do not reconcile resources last managed by a newer operator
+	if managed, err := common.ReconcileOperatorVersion(ctx, r.Client, &kb); err != nil || !managed {
+		return reconcile.Result{}, tracing.CaptureError(ctx, err)
+	}
+
 	// Remove any previous Finalizers
 	if err := finalizer.RemoveAll(ctx, r.Client, &kb); err != nil {
 		return reconcile.Result{}, tracing.CaptureError(ctx, err)
@@ -237,6 +253,10 @@ func (r *ReconcileKibana) onDelete(ctx context.Context, obj types.NamespacedName
 	r.dynamicWatches.Secrets.RemoveHandlerForKey(keystore.SecureSettingsWatchName(obj))
 	// Clean up watches set on custom http tls certificates
 	r.dynamicWatches.Secrets.RemoveHandlerForKey(certificates.CertificateWatchKey(kbv1.KBNamer, obj.Name))
+	// Clean up watches set on the associated Elasticsearch
+	r.dynamicWatches.ReferencedResources.RemoveHandlerForKey(elasticsearchWatchName(obj))
+	// Clean up watches set on the ConfigMaps holding saved objects
+	r.dynamicWatches.ConfigMaps.RemoveHandlerForKey(SavedObjectsWatchName(obj))
 	return reconciler.GarbageCollectSoftOwnedSecrets(ctx, r.Client, obj, kbv1.Kind)
 }
 
@@ -26,6 +26,7 @@ import (
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/deployment"
 	driver2 "github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/driver"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/events"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/expose"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/keystore"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/operator"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/reconciler"
@@ -99,6 +100,9 @@ func (d *driver) Reconcile(
 	params operator.Parameters,
 ) *reconciler.Results {
 	results := reconciler.NewResult(ctx)
+	if err := watchElasticsearch(*kb, d.dynamicWatches); err != nil {
+		return results.WithError(err)
+	}
 	isEsAssocConfigured, err := association.IsConfiguredIfSet(ctx, kb.EsAssociation(), d.recorder)
 	if err != nil {
 		return results.WithError(err)
@@ -113,6 +117,13 @@ func (d *driver) Reconcile(
 	if !isEntAssocConfigured {
 		return results
 	}
+	isEMSAssocConfigured, err := association.IsConfiguredIfSet(ctx, kb.EMSAssociation(), d.recorder)
+	if err != nil {
+		return results.WithError(err)
+	}
+	if !isEMSAssocConfigured {
+		return results
+	}
 
 	svc, err := common.ReconcileService(ctx, d.client, NewService(*kb), kb)
 	if err != nil {
@@ -120,18 +131,21 @@ func (d *driver) Reconcile(
 		return results.WithError(err)
 	}
 
-	_, results = certificates.Reconciler{
+	httpCerts, results := certificates.Reconciler{
 		K8sClient:             d.K8sClient(),
 		DynamicWatches:        d.DynamicWatches(),
+		Recorder:              d.Recorder(),
 		Owner:                 kb,
 		TLSOptions:            kb.Spec.HTTP.TLS,
 		Namer:                 kbv1.KBNamer,
 		Labels:                NewLabels(kb.Name),
 		Services:              []corev1.Service{*svc},
 		GlobalCA:              params.GlobalCA,
+		NamespaceCA:           params.NamespaceCA,
 		CACertRotation:        params.CACertRotation,
 		CertRotation:          params.CertRotation,
 		GarbageCollectSecrets: true,
+		ExtraHTTPSANs:         expose.SubjectAltNames(kb.Spec.HTTP),
 	}.ReconcileCAAndHTTPCerts(ctx)
 	if results.HasError() {
 		_, err := results.Aggregate()
@@ -139,6 +153,18 @@ func (d *driver) Reconcile(
 		return results
 	}
 
+	err = expose.Reconciler{
+		K8sClient: d.client,
+		Owner:     kb,
+		HTTP:      kb.Spec.HTTP,
+		Service:   *svc,
+		Namer:     kbv1.KBNamer,
+		Labels:    NewLabels(kb.Name),
+	}.Reconcile(ctx)
+	if err != nil {
+		return results.WithError(err)
+	}
+
 	logger := ulog.FromContext(ctx)
 	assocAllowed, err := association.AllowVersion(d.version, kb, logger, d.Recorder())
 	if err != nil {
@@ -148,10 +174,11 @@ func (d *driver) Reconcile(
 		return results // will eventually retry
 	}
 
-	kbSettings, err := NewConfigSettings(ctx, d.client, *kb, d.version, d.ipFamily)
+	kbSettings, overrides, err := NewConfigSettingsWithOverrides(ctx, d.client, *kb, d.version, d.ipFamily)
 	if err != nil {
 		return results.WithError(err)
 	}
+	state.Kibana.Status.ConfigOverrides = overrides.Strings()
 
 	err = ReconcileConfigSecret(ctx, d.client, *kb, kbSettings)
 	if err != nil {
@@ -187,6 +214,10 @@ func (d *driver) Reconcile(
 	}
 	state.Kibana.Status.DeploymentStatus = deploymentStatus
 
+	if err := d.reconcileSavedObjects(ctx, kb, deploymentStatus.AvailableNodes > 0, httpCerts, params.Dialer, params.DryRun); err != nil {
+		return results.WithError(err)
+	}
+
 	return results
 }
 
@@ -319,6 +350,15 @@ func (d *driver) buildVolumes(kb *kbv1.Kibana) ([]commonvolume.VolumeLike, error
 		volumes = append(volumes, entCertsVolume)
 	}
 
+	emsAssocConf, err := kb.EMSAssociation().AssociationConf()
+	if err != nil {
+		return nil, err
+	}
+	if emsAssocConf.CAIsConfigured() {
+		emsCertsVolume := emsCaCertSecretVolume(*emsAssocConf)
+		volumes = append(volumes, emsCertsVolume)
+	}
+
 	if kb.Spec.HTTP.TLS.Enabled() {
 		httpCertsVolume := certificates.HTTPCertSecretVolume(kbv1.KBNamer, kb.Name)
 		volumes = append(volumes, httpCertsVolume)
@@ -0,0 +1,95 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package kibana
+
+import (
+	"fmt"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+
+	kbv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/kibana/v1"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/volume"
+)
+
+const (
+	// PluginsContainerName is the name of the init container that installs the plugins declared in the specification.
+	PluginsContainerName = "elastic-internal-install-plugins"
+
+	PluginsVolumeName                   = "kibana-plugins"
+	PluginsVolumeMountPath              = "/usr/share/kibana/plugins"
+	InitContainerPluginsVolumeMountPath = "/mnt/elastic-internal/kibana-plugins-local"
+
+	PluginBinPath = "/usr/share/kibana/bin/kibana-plugin"
+)
+
+var (
+	// PluginsSharedVolume holds the plugins installed by the init container. It is mounted over the plugins directory
+	// of the Kibana container.
+	PluginsSharedVolume = volume.SharedVolume{
+		VolumeName:             PluginsVolumeName,
+		InitContainerMountPath: InitContainerPluginsVolumeMountPath,
+		ContainerMountPath:     PluginsVolumeMountPath,
+	}
+
+	// pluginsScriptHeader installs a plugin from a URL in the plugins directory of the init container. Plugin archives
+	// with a checksum are downloaded and verified before being installed from the local filesystem.
+	pluginsScriptHeader = fmt.Sprintf(`#!/usr/bin/env bash
+set -eu
+
+install_plugin() {
+	local plugin=$1 sha512=$2
+	local source=$plugin
+	if [[ -n "$sha512" ]]; then
+		local archive
+		archive="$(mktemp -d)/plugin.zip"
+		echo "Downloading $plugin"
+		curl -sSfL -o "$archive" "$plugin"
+		echo "$sha512  $archive" | sha512sum -c -
+		source="file://$archive"
+	fi
+	echo "Installing plugin $plugin"
+	%s install "$source"
+}
+`, PluginBinPath)
+
+	// pluginsScriptFooter copies the plugins bundled in the image and the installed ones to the shared volume.
+	pluginsScriptFooter = fmt.Sprintf(`
+cp -a %s/. %s/
+echo "Plugins successfully installed."
+`, PluginsVolumeMountPath, InitContainerPluginsVolumeMountPath)
+)
+
+// RenderPluginsScript renders the script installing the given plugins.
+func RenderPluginsScript(plugins []kbv1.Plugin) string {
+	script := strings.Builder{}
+	script.WriteString(pluginsScriptHeader)
+	for _, plugin := range plugins {
+		script.WriteString(fmt.Sprintf("\ninstall_plugin %s %s", shellQuote(plugin.URL), shellQuote(plugin.SHA512)))
+	}
+	script.WriteString("\n")
+	script.WriteString(pluginsScriptFooter)
+	return script.String()
+}
+
+// shellQuote quotes the given string to be used as a single argument in a shell script.
+func shellQuote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
+
+// pluginsInitContainer returns an init container installing the given plugins into the plugins volume shared with the
+// Kibana container. It inherits the image and the volume mounts of the Kibana container, which allows installing
+// plugin archives from a volume mounted in the Pods.
+func pluginsInitContainer(plugins []kbv1.Plugin) corev1.Container {
+	return corev1.Container{
+		// Image will be inherited from pod template defaults
+		ImagePullPolicy: corev1.PullIfNotPresent,
+		Name:            PluginsContainerName,
+		Command:         []string{"/usr/bin/env", "bash", "-c", RenderPluginsScript(plugins)},
+		VolumeMounts: []corev1.VolumeMount{
+			PluginsSharedVolume.InitContainerVolumeMount(),
+		},
+	}
+}
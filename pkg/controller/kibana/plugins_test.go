@@ -0,0 +1,48 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package kibana
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	kbv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/kibana/v1"
+)
+
+func TestRenderPluginsScript(t *testing.T) {
+	tests := []struct {
+		name    string
+		plugins []kbv1.Plugin
+		want    []string
+	}{
+		{
+			name: "plugins with and without checksum",
+			plugins: []kbv1.Plugin{
+				{URL: "https://example.com/plugin.zip", SHA512: "abcd"},
+				{URL: "file:///mnt/plugins/plugin.zip"},
+			},
+			want: []string{
+				"install_plugin 'https://example.com/plugin.zip' 'abcd'",
+				"install_plugin 'file:///mnt/plugins/plugin.zip' ''",
+			},
+		},
+		{
+			name:    "quotes are escaped",
+			plugins: []kbv1.Plugin{{URL: "file:///mnt/it's.zip"}},
+			want:    []string{`install_plugin 'file:///mnt/it'\''s.zip' ''`},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			script := RenderPluginsScript(tt.plugins)
+			assert.True(t, strings.HasPrefix(script, pluginsScriptHeader))
+			assert.True(t, strings.HasSuffix(script, pluginsScriptFooter))
+			body := strings.TrimSuffix(strings.TrimPrefix(script, pluginsScriptHeader), pluginsScriptFooter)
+			assert.Equal(t, tt.want, strings.Split(strings.TrimSpace(body), "\n"))
+		})
+	}
+}
@@ -6,6 +6,7 @@ package kibana
 
 import (
 	"context"
+	"path"
 
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/resource"
@@ -14,6 +15,7 @@ import (
 
 	kbv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/kibana/v1"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/annotation"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/certificates"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/container"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/defaults"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/keystore"
@@ -84,8 +86,15 @@ func NewPodTemplateSpec(ctx context.Context, client k8sclient.Client, kb kbv1.Ki
 		WithAnnotations(DefaultAnnotations).
 		WithDockerImage(kb.Spec.Image, container.ImageRepository(container.KibanaImage, kb.Spec.Version)).
 		WithReadinessProbe(readinessProbe(kb.Spec.HTTP.TLS.Enabled())).
-		WithPorts(ports).
-		WithInitContainers(initConfigContainer(kb))
+		WithPorts(ports)
+
+	// plugins are installed once the configuration directory is ready
+	initContainers := []corev1.Container{initConfigContainer(kb)}
+	if len(kb.Spec.Plugins) > 0 {
+		initContainers = append(initContainers, pluginsInitContainer(kb.Spec.Plugins))
+		builder.WithVolumes(PluginsSharedVolume.Volume()).WithVolumeMounts(PluginsSharedVolume.VolumeMount())
+	}
+	builder.WithInitContainers(initContainers...)
 
 	for _, volume := range volumes {
 		builder.WithVolumes(volume.Volume()).WithVolumeMounts(volume.VolumeMount())
@@ -96,7 +105,16 @@ func NewPodTemplateSpec(ctx context.Context, client k8sclient.Client, kb kbv1.Ki
 			WithInitContainers(keystore.InitContainer)
 	}
 
-	builder, err := stackmon.WithMonitoring(ctx, client, builder, kb)
+	// the map tiles are fetched by the browsers, but the Kibana server also requests the associated Elastic Maps Server
+	emsAssocConf, err := kb.EMSAssociation().AssociationConf()
+	if err != nil {
+		return corev1.PodTemplateSpec{}, err
+	}
+	if emsAssocConf.CAIsConfigured() {
+		builder.WithEnv(corev1.EnvVar{Name: EnvNodeExtraCACerts, Value: path.Join(emsCertsVolumeMountPath, certificates.CAFileName)})
+	}
+
+	builder, err = stackmon.WithMonitoring(ctx, client, builder, kb)
 	if err != nil {
 		return corev1.PodTemplateSpec{}, err
 	}
@@ -67,6 +67,24 @@ func TestNewPodTemplateSpec(t *testing.T) {
 				assert.Len(t, pod.Spec.Volumes, 1)
 			},
 		},
+		{
+			name: "with plugins",
+			kb: kbv1.Kibana{
+				Spec: kbv1.KibanaSpec{
+					Version: "7.1.0",
+					Plugins: []kbv1.Plugin{{URL: "https://example.com/plugin.zip"}},
+				},
+			},
+			assertions: func(pod corev1.PodTemplateSpec) {
+				require.Len(t, pod.Spec.InitContainers, 2)
+				// plugins are installed after the configuration directory is prepared
+				assert.Equal(t, InitConfigContainerName, pod.Spec.InitContainers[0].Name)
+				assert.Equal(t, PluginsContainerName, pod.Spec.InitContainers[1].Name)
+				assert.Contains(t, pod.Spec.InitContainers[1].VolumeMounts, PluginsSharedVolume.InitContainerVolumeMount())
+				assert.Equal(t, []corev1.Volume{PluginsSharedVolume.Volume()}, pod.Spec.Volumes)
+				assert.Equal(t, []corev1.VolumeMount{PluginsSharedVolume.VolumeMount()}, GetKibanaContainer(pod.Spec).VolumeMounts)
+			},
+		},
 		{
 			name: "with custom image",
 			kb: kbv1.Kibana{Spec: kbv1.KibanaSpec{
@@ -187,6 +205,26 @@ func TestNewPodTemplateSpec(t *testing.T) {
 				assert.Len(t, GetKibanaContainer(pod.Spec).Env, 1)
 			},
 		},
+		{
+			name: "with an Elastic Maps Server association",
+			kb: func() kbv1.Kibana {
+				kb := kbv1.Kibana{Spec: kbv1.KibanaSpec{
+					Version:              "8.6.0",
+					ElasticMapsServerRef: commonv1.ObjectSelector{Name: "ems"},
+				}}
+				kb.EMSAssociation().SetAssociationConf(&commonv1.AssociationConf{
+					AuthSecretName: "-",
+					CASecretName:   "ems-ca",
+					CACertProvided: true,
+					URL:            "https://ems:8080",
+				})
+				return kb
+			}(),
+			assertions: func(pod corev1.PodTemplateSpec) {
+				assert.Contains(t, GetKibanaContainer(pod.Spec).Env,
+					corev1.EnvVar{Name: EnvNodeExtraCACerts, Value: "/usr/share/kibana/config/ems-certs/ca.crt"})
+			},
+		},
 		{
 			name: "with user-provided volumes and volume mounts",
 			kb: kbv1.Kibana{Spec: kbv1.KibanaSpec{
@@ -0,0 +1,351 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package kibana
+
+import (
+	"bytes"
+	"context"
+	"crypto/x509"
+	"encoding/json"
+	"fmt"
+	"hash/fnv"
+	"mime/multipart"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	"go.elastic.co/apm/module/apmhttp/v2"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	utilerrors "k8s.io/apimachinery/pkg/util/errors"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	kbv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/kibana/v1"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/association"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/certificates"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/dryrun"
+	commonhttp "github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/http"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/tracing"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/version"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/watches"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/utils/k8s"
+	ulog "github.com/elastic/cloud-on-k8s/v2/pkg/utils/log"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/utils/net"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/utils/stringsutil"
+)
+
+const (
+	// SavedObjectsHashesAnnotationName holds the hashes of the saved objects last imported into Kibana by the operator,
+	// by ConfigMap name.
+	SavedObjectsHashesAnnotationName = "kibana.k8s.elastic.co/saved-objects-hashes"
+	// SavedObjectsUserSecretSuffix is the suffix of the Secrets holding the Elasticsearch user the operator uses to import
+	// saved objects into Kibana.
+	SavedObjectsUserSecretSuffix = "kibana-saved-objects-user"
+
+	savedObjectsImportTimeout = 60 * time.Second
+)
+
+// kibanaAdminRoleMinVersion is the version introducing the kibana_admin built-in role, which replaces the kibana_user one.
+var kibanaAdminRoleMinVersion = version.From(7, 5, 0)
+
+// SavedObjectsWatchName returns the watch registered for the ConfigMaps holding the saved objects of a Kibana.
+func SavedObjectsWatchName(kb types.NamespacedName) string {
+	return fmt.Sprintf("%s-%s-saved-objects-configmaps", kb.Namespace, kb.Name)
+}
+
+// savedObjects are the saved objects of a ConfigMap to import into a Kibana space.
+type savedObjects struct {
+	configMapName string
+	space         string
+	ndjson        []byte
+	hash          string
+}
+
+// savedObjectsImportResult is the response of the Kibana saved objects import API.
+type savedObjectsImportResult struct {
+	Success      bool `json:"success"`
+	SuccessCount int  `json:"successCount"`
+	Errors       []struct {
+		ID    string `json:"id"`
+		Type  string `json:"type"`
+		Error struct {
+			Type string `json:"type"`
+		} `json:"error"`
+	} `json:"errors,omitempty"`
+}
+
+// errorsSummary returns a description of the saved objects which could not be imported.
+func (r savedObjectsImportResult) errorsSummary() string {
+	summary := make([]string, 0, len(r.Errors))
+	for _, e := range r.Errors {
+		summary = append(summary, fmt.Sprintf("%s %s: %s", e.Type, e.ID, e.Error.Type))
+	}
+	return strings.Join(summary, ", ")
+}
+
+// SavedObjectsUserRole returns the role of the Elasticsearch user the operator uses to import the saved objects of the
+// given Kibana, or an empty string if it has no saved objects to import. The role grants access to all the Kibana
+// features in all the spaces, but not to the Elasticsearch indices.
+func SavedObjectsUserRole(kb kbv1.Kibana) string {
+	if len(kb.Spec.SavedObjects) == 0 {
+		return ""
+	}
+	if ver, err := version.Parse(kb.Spec.Version); err == nil && !ver.GTE(kibanaAdminRoleMinVersion) {
+		return "kibana_user"
+	}
+	return "kibana_admin"
+}
+
+// reconcileSavedObjects imports the saved objects held in the ConfigMaps referenced in the Kibana spec through the
+// Kibana saved objects API, overwriting existing objects with the same identifiers. The hash of the saved objects
+// imported from each ConfigMap is stored in an annotation of the Kibana resource so that they are only imported again
+// when the ConfigMap changes. Saved objects which cannot be imported are retried with the backoff of the controller,
+// without importing again the ones of the other ConfigMaps. Saved objects removed from the ConfigMaps are left
+// untouched in Kibana.
+func (d *driver) reconcileSavedObjects(
+	ctx context.Context,
+	kb *kbv1.Kibana,
+	available bool,
+	httpCerts *certificates.CertificatesSecret,
+	dialer net.Dialer,
+	dryRun *dryrun.Mode,
+) error {
+	kbKey := k8s.ExtractNamespacedName(kb)
+	if err := watches.WatchUserProvidedConfigMaps(kbKey, d.dynamicWatches, SavedObjectsWatchName(kbKey), savedObjectsConfigMapNames(*kb)); err != nil {
+		return err
+	}
+	if len(kb.Spec.SavedObjects) == 0 {
+		return nil
+	}
+
+	sources, err := getSavedObjects(ctx, d.client, *kb)
+	if err != nil {
+		return err
+	}
+	imported := importedSavedObjectsHashes(*kb)
+	// only keep the hashes of the ConfigMaps still referenced in the spec
+	hashes := make(map[string]string, len(sources))
+	var toImport []savedObjects
+	for _, source := range sources {
+		if imported[source.configMapName] == source.hash {
+			hashes[source.configMapName] = source.hash
+			continue
+		}
+		toImport = append(toImport, source)
+	}
+	if len(toImport) == 0 {
+		return nil
+	}
+	log := ulog.FromContext(ctx)
+	if !available {
+		log.V(1).Info("Kibana is not available yet, delaying the import of saved objects", "namespace", kb.Namespace, "kibana_name", kb.Name)
+		return nil
+	}
+
+	api, err := d.newSavedObjectsAPI(ctx, *kb, httpCerts, dialer, dryRun.ReporterFor(kb))
+	if err != nil {
+		return err
+	}
+	var errs []error
+	for _, source := range toImport {
+		result, err := api.importSavedObjects(ctx, source.space, source.ndjson)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("failed to import saved objects from ConfigMap %s: %w", source.configMapName, err))
+			continue
+		}
+		if !result.Success {
+			errs = append(errs, fmt.Errorf("failed to import saved objects from ConfigMap %s: %s", source.configMapName, result.errorsSummary()))
+			continue
+		}
+		log.Info("Imported saved objects", "namespace", kb.Namespace, "kibana_name", kb.Name,
+			"configmap_name", source.configMapName, "space", source.space, "count", result.SuccessCount)
+		hashes[source.configMapName] = source.hash
+	}
+	if err := annotateWithSavedObjectsHashes(ctx, d.client, kb, hashes); err != nil {
+		errs = append(errs, err)
+	}
+	return utilerrors.NewAggregate(errs)
+}
+
+// savedObjectsConfigMapNames returns the names of the ConfigMaps referenced in the saved objects of the Kibana spec.
+func savedObjectsConfigMapNames(kb kbv1.Kibana) []string {
+	names := make([]string, 0, len(kb.Spec.SavedObjects))
+	for _, source := range kb.Spec.SavedObjects {
+		names = append(names, source.ConfigMapName)
+	}
+	return names
+}
+
+// getSavedObjects reads the saved objects from the ConfigMaps referenced in the Kibana spec, and returns them with a
+// hash of their content and of the space they are imported into. The entries of each ConfigMap are concatenated in
+// the order of their keys.
+func getSavedObjects(ctx context.Context, c k8s.Client, kb kbv1.Kibana) ([]savedObjects, error) {
+	sources := make([]savedObjects, 0, len(kb.Spec.SavedObjects))
+	for _, source := range kb.Spec.SavedObjects {
+		var cm corev1.ConfigMap
+		if err := c.Get(ctx, types.NamespacedName{Namespace: kb.Namespace, Name: source.ConfigMapName}, &cm); err != nil {
+			return nil, err
+		}
+		keys := make([]string, 0, len(cm.Data))
+		for k := range cm.Data {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		var ndjson bytes.Buffer
+		for _, k := range keys {
+			ndjson.WriteString(strings.TrimSpace(cm.Data[k]))
+			ndjson.WriteString("\n")
+		}
+		hash := fnv.New32a()
+		_, _ = hash.Write([]byte(source.Space))
+		_, _ = hash.Write(ndjson.Bytes())
+		sources = append(sources, savedObjects{
+			configMapName: source.ConfigMapName,
+			space:         source.Space,
+			ndjson:        ndjson.Bytes(),
+			hash:          fmt.Sprint(hash.Sum32()),
+		})
+	}
+	return sources, nil
+}
+
+// importedSavedObjectsHashes returns the hashes of the saved objects last imported into Kibana, by ConfigMap name.
+// An invalid annotation is ignored, which imports all the saved objects again.
+func importedSavedObjectsHashes(kb kbv1.Kibana) map[string]string {
+	hashes := map[string]string{}
+	if value, exists := kb.Annotations[SavedObjectsHashesAnnotationName]; exists {
+		if err := json.Unmarshal([]byte(value), &hashes); err != nil {
+			return map[string]string{}
+		}
+	}
+	return hashes
+}
+
+// annotateWithSavedObjectsHashes stores the hashes of the imported saved objects in an annotation of the Kibana
+// resource. A merge patch is used to not conflict with concurrent changes of the spec, and the annotations and the
+// resource version of the given Kibana are updated so that the update of its status at the end of the reconciliation
+// does not conflict with the patch.
+func annotateWithSavedObjectsHashes(ctx context.Context, c k8s.Client, kb *kbv1.Kibana, hashes map[string]string) error {
+	value, err := json.Marshal(hashes)
+	if err != nil {
+		return err
+	}
+	if kb.Annotations[SavedObjectsHashesAnnotationName] == string(value) {
+		return nil
+	}
+	// patch a copy to keep the status of the given Kibana, which may not be persisted yet
+	patched := kb.DeepCopy()
+	if patched.Annotations == nil {
+		patched.Annotations = map[string]string{}
+	}
+	patched.Annotations[SavedObjectsHashesAnnotationName] = string(value)
+	if err := c.Patch(ctx, patched, client.MergeFrom(kb.DeepCopy())); err != nil {
+		return err
+	}
+	kb.Annotations = patched.Annotations
+	kb.ResourceVersion = patched.ResourceVersion
+	return nil
+}
+
+// savedObjectsAPI imports saved objects into Kibana.
+type savedObjectsAPI struct {
+	client             *http.Client
+	endpoint           string
+	username, password string
+	// dryRun reports the imports instead of sending them if not nil.
+	dryRun *dryrun.Reporter
+}
+
+// newSavedObjectsAPI returns a client of the saved objects API of the given Kibana, authenticated as the Elasticsearch
+// user created for the import of saved objects by the association controller. In dry-run mode, the client only
+// reports the imports and does not need this user, which may not exist.
+func (d *driver) newSavedObjectsAPI(
+	ctx context.Context,
+	kb kbv1.Kibana,
+	httpCerts *certificates.CertificatesSecret,
+	dialer net.Dialer,
+	dryRun *dryrun.Reporter,
+) (savedObjectsAPI, error) {
+	if dryRun != nil {
+		return savedObjectsAPI{dryRun: dryRun}, nil
+	}
+	endpoint, err := association.ServiceURL(d.client, types.NamespacedName{Namespace: kb.Namespace, Name: kbv1.HTTPService(kb.Name)}, kb.Spec.HTTP.Protocol())
+	if err != nil {
+		return savedObjectsAPI{}, err
+	}
+	userSecretRef := association.UserSecretKeySelector(kb.EsAssociation(), SavedObjectsUserSecretSuffix)
+	var userSecret corev1.Secret
+	if err := d.client.Get(ctx, types.NamespacedName{Namespace: kb.Namespace, Name: userSecretRef.Name}, &userSecret); err != nil {
+		return savedObjectsAPI{}, err
+	}
+	password, exists := userSecret.Data[userSecretRef.Key]
+	if !exists {
+		return savedObjectsAPI{}, fmt.Errorf("no %s key in %s", userSecretRef.Key, k8s.ExtractNamespacedName(&userSecret))
+	}
+	var caCerts []*x509.Certificate
+	if httpCerts != nil {
+		if caCerts, err = certificates.ParsePEMCerts(httpCerts.CertPem()); err != nil {
+			return savedObjectsAPI{}, err
+		}
+	}
+	return savedObjectsAPI{
+		client: apmhttp.WrapClient(
+			commonhttp.Client(dialer, caCerts, savedObjectsImportTimeout),
+			apmhttp.WithClientRequestName(tracing.RequestName),
+			apmhttp.WithClientSpanType("external.kibana"),
+		),
+		endpoint: endpoint,
+		username: userSecretRef.Key,
+		password: string(password),
+	}, nil
+}
+
+// importSavedObjects imports saved objects in the NDJSON format into the given space, or into the default space if empty.
+func (a savedObjectsAPI) importSavedObjects(ctx context.Context, space string, ndjson []byte) (savedObjectsImportResult, error) {
+	if a.dryRun != nil {
+		a.dryRun.Report(ctx, fmt.Sprintf("Dry run: would import saved objects into the Kibana space %q", space))
+		return savedObjectsImportResult{Success: true}, nil
+	}
+	var body bytes.Buffer
+	writer := multipart.NewWriter(&body)
+	part, err := writer.CreateFormFile("file", "saved_objects.ndjson")
+	if err != nil {
+		return savedObjectsImportResult{}, err
+	}
+	if _, err := part.Write(ndjson); err != nil {
+		return savedObjectsImportResult{}, err
+	}
+	if err := writer.Close(); err != nil {
+		return savedObjectsImportResult{}, err
+	}
+
+	path := "/api/saved_objects/_import?overwrite=true"
+	if space != "" {
+		path = stringsutil.Concat("/s/", space, path)
+	}
+	request, err := http.NewRequestWithContext(ctx, http.MethodPost, stringsutil.Concat(a.endpoint, path), &body)
+	if err != nil {
+		return savedObjectsImportResult{}, err
+	}
+	request.Header.Set("Content-Type", writer.FormDataContentType())
+	request.Header.Set(commonhttp.InternalProductRequestHeaderKey, commonhttp.InternalProductRequestHeaderValue)
+	request.Header.Set("kbn-xsrf", "true")
+	request.SetBasicAuth(a.username, a.password)
+
+	resp, err := a.client.Do(request)
+	if err != nil {
+		return savedObjectsImportResult{}, err
+	}
+	defer resp.Body.Close()
+	if err := commonhttp.MaybeAPIError(resp); err != nil {
+		return savedObjectsImportResult{}, err
+	}
+	var result savedObjectsImportResult
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return savedObjectsImportResult{}, err
+	}
+	return result, nil
+}
@@ -0,0 +1,185 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package kibana
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+
+	kbv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/kibana/v1"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/dryrun"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/utils/k8s"
+)
+
+func Test_getSavedObjects(t *testing.T) {
+	cm := corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "dashboards"},
+		Data: map[string]string{
+			"b.ndjson": `{"type":"dashboard","id":"b"}`,
+			"a.ndjson": "{\"type\":\"index-pattern\",\"id\":\"a\"}\n",
+		},
+	}
+	kb := kbv1.Kibana{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "kb"},
+		Spec: kbv1.KibanaSpec{
+			SavedObjects: []kbv1.SavedObjectsSource{{ConfigMapName: "dashboards"}},
+		},
+	}
+	c := k8s.NewFakeClient(&cm)
+
+	sources, err := getSavedObjects(context.Background(), c, kb)
+	require.NoError(t, err)
+	require.Len(t, sources, 1)
+	require.Equal(t, "dashboards", sources[0].configMapName)
+	require.Equal(t, "{\"type\":\"index-pattern\",\"id\":\"a\"}\n{\"type\":\"dashboard\",\"id\":\"b\"}\n", string(sources[0].ndjson))
+
+	// the hash is stable
+	sameSources, err := getSavedObjects(context.Background(), c, kb)
+	require.NoError(t, err)
+	require.Equal(t, sources[0].hash, sameSources[0].hash)
+
+	// importing into another space changes the hash
+	kb.Spec.SavedObjects[0].Space = "marketing"
+	otherSources, err := getSavedObjects(context.Background(), c, kb)
+	require.NoError(t, err)
+	require.NotEqual(t, sources[0].hash, otherSources[0].hash)
+
+	// missing ConfigMaps are reported
+	kb.Spec.SavedObjects = append(kb.Spec.SavedObjects, kbv1.SavedObjectsSource{ConfigMapName: "missing"})
+	_, err = getSavedObjects(context.Background(), c, kb)
+	require.Error(t, err)
+}
+
+func Test_savedObjectsAPI_importSavedObjects(t *testing.T) {
+	tests := []struct {
+		name        string
+		space       string
+		response    string
+		status      int
+		wantPath    string
+		wantSuccess bool
+		wantErr     bool
+	}{
+		{
+			name:        "default space",
+			response:    `{"success":true,"successCount":2}`,
+			status:      http.StatusOK,
+			wantPath:    "/api/saved_objects/_import",
+			wantSuccess: true,
+		},
+		{
+			name:        "custom space",
+			space:       "marketing",
+			response:    `{"success":true,"successCount":2}`,
+			status:      http.StatusOK,
+			wantPath:    "/s/marketing/api/saved_objects/_import",
+			wantSuccess: true,
+		},
+		{
+			name:     "import errors",
+			response: `{"success":false,"successCount":1,"errors":[{"id":"b","type":"dashboard","error":{"type":"missing_references"}}]}`,
+			status:   http.StatusOK,
+			wantPath: "/api/saved_objects/_import",
+		},
+		{
+			name:     "API error",
+			status:   http.StatusBadRequest,
+			wantPath: "/api/saved_objects/_import",
+			wantErr:  true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				require.Equal(t, http.MethodPost, r.Method)
+				require.Equal(t, tt.wantPath, r.URL.Path)
+				require.Equal(t, "true", r.URL.Query().Get("overwrite"))
+				require.Equal(t, "true", r.Header.Get("kbn-xsrf"))
+				username, password, ok := r.BasicAuth()
+				require.True(t, ok)
+				require.Equal(t, "elastic", username)
+				require.Equal(t, "secret", password)
+				file, _, err := r.FormFile("file")
+				require.NoError(t, err)
+				content, err := io.ReadAll(file)
+				require.NoError(t, err)
+				require.Equal(t, `{"type":"dashboard","id":"b"}`, string(content))
+				w.WriteHeader(tt.status)
+				_, _ = w.Write([]byte(tt.response))
+			}))
+			defer server.Close()
+
+			api := savedObjectsAPI{client: server.Client(), endpoint: server.URL, username: "elastic", password: "secret"}
+			result, err := api.importSavedObjects(context.Background(), tt.space, []byte(`{"type":"dashboard","id":"b"}`))
+			if tt.wantErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tt.wantSuccess, result.Success)
+			if !tt.wantSuccess {
+				require.Equal(t, "dashboard b: missing_references", result.errorsSummary())
+			}
+		})
+	}
+}
+
+func Test_savedObjectsAPI_importSavedObjects_dryRun(t *testing.T) {
+	kb := kbv1.Kibana{ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "kb"}}
+	recorder := record.NewFakeRecorder(10)
+	// no credentials are needed since nothing is sent to Kibana
+	api, err := (&driver{client: k8s.NewFakeClient()}).newSavedObjectsAPI(context.Background(), kb, nil, nil, dryrun.NewMode(recorder).ReporterFor(&kb))
+	require.NoError(t, err)
+	result, err := api.importSavedObjects(context.Background(), "marketing", []byte(`{"type":"dashboard","id":"b"}`))
+	require.NoError(t, err)
+	require.True(t, result.Success)
+	require.Equal(t, `Normal DryRun Dry run: would import saved objects into the Kibana space "marketing"`, <-recorder.Events)
+}
+
+func Test_annotateWithSavedObjectsHashes(t *testing.T) {
+	kb := kbv1.Kibana{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "kb", Annotations: map[string]string{"a": "b"}},
+	}
+	c := k8s.NewFakeClient(&kb)
+	var kbToUpdate kbv1.Kibana
+	require.NoError(t, c.Get(context.Background(), types.NamespacedName{Namespace: "ns", Name: "kb"}, &kbToUpdate))
+	// the status is not persisted yet
+	kbToUpdate.Status.Version = "8.6.0"
+
+	require.NoError(t, annotateWithSavedObjectsHashes(context.Background(), c, &kbToUpdate, map[string]string{"dashboards": "1234"}))
+
+	var updated kbv1.Kibana
+	require.NoError(t, c.Get(context.Background(), types.NamespacedName{Namespace: "ns", Name: "kb"}, &updated))
+	require.Equal(t, map[string]string{"a": "b", SavedObjectsHashesAnnotationName: `{"dashboards":"1234"}`}, updated.Annotations)
+	// the annotations and the resource version of the Kibana are updated to not conflict with the update of its status
+	require.Equal(t, updated.Annotations, kbToUpdate.Annotations)
+	require.Equal(t, updated.ResourceVersion, kbToUpdate.ResourceVersion)
+	require.Equal(t, "8.6.0", kbToUpdate.Status.Version)
+	require.Equal(t, map[string]string{"dashboards": "1234"}, importedSavedObjectsHashes(kbToUpdate))
+}
+
+func Test_importedSavedObjectsHashes(t *testing.T) {
+	require.Equal(t, map[string]string{}, importedSavedObjectsHashes(kbv1.Kibana{}))
+	invalid := kbv1.Kibana{ObjectMeta: metav1.ObjectMeta{Annotations: map[string]string{SavedObjectsHashesAnnotationName: "1234"}}}
+	require.Equal(t, map[string]string{}, importedSavedObjectsHashes(invalid))
+}
+
+func TestSavedObjectsUserRole(t *testing.T) {
+	kb := kbv1.Kibana{Spec: kbv1.KibanaSpec{Version: "8.6.0"}}
+	require.Equal(t, "", SavedObjectsUserRole(kb))
+	kb.Spec.SavedObjects = []kbv1.SavedObjectsSource{{ConfigMapName: "dashboards"}}
+	require.Equal(t, "kibana_admin", SavedObjectsUserRole(kb))
+	kb.Spec.Version = "7.4.0"
+	require.Equal(t, "kibana_user", SavedObjectsUserRole(kb))
+}
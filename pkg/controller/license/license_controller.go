@@ -126,7 +126,13 @@ func addWatches(c controller.Controller, k8sClient k8s.Client) error {
 			return nil
 		}
 		if !license.IsOperatorLicense(*secret) {
-			return nil
+			// the secret may hold the license provided by the user for some clusters in the same namespace
+			rs, err := reconcileRequestsForClustersReferencing(k8sClient, *secret)
+			if err != nil {
+				log.Error(err, "failed to list affected clusters in license secret watch")
+				return nil
+			}
+			return rs
 		}
 
 		// if a license is added/modified we want to update for potentially all clusters managed by this instance
@@ -217,6 +223,10 @@ func reconcileSecret(
 // reconcileClusterLicense upserts a cluster license in the namespace of the given Elasticsearch cluster.
 // Returns time to next reconciliation, bool whether a license is configured at all and optional error.
 func (r *ReconcileLicenses) reconcileClusterLicense(ctx context.Context, cluster esv1.Elasticsearch) (time.Time, bool, error) {
+	if secretName := cluster.Annotations[license.LicenseSecretAnnotation]; secretName != "" {
+		return r.reconcileUserProvidedLicense(ctx, cluster, secretName)
+	}
+
 	log := ulog.FromContext(ctx)
 
 	var noResult time.Time
@@ -240,6 +250,38 @@ func (r *ReconcileLicenses) reconcileClusterLicense(ctx context.Context, cluster
 	return matchingSpec.ExpiryTime(), false, nil
 }
 
+// reconcileUserProvidedLicense upserts a cluster license from the Elasticsearch license held in the given secret of the
+// namespace of the cluster, which takes precedence over the enterprise licenses. The current cluster license is left
+// untouched if the secret does not exist or does not hold a valid license, which is reported in an event.
+// Returns the time to next reconciliation, always true as there is no safety margin for a license not renewed by the
+// operator, and an optional error.
+func (r *ReconcileLicenses) reconcileUserProvidedLicense(ctx context.Context, cluster esv1.Elasticsearch, secretName string) (time.Time, bool, error) {
+	var noResult time.Time
+	var secret corev1.Secret
+	err := r.Get(ctx, types.NamespacedName{Namespace: cluster.Namespace, Name: secretName}, &secret)
+	if apierrors.IsNotFound(err) {
+		r.recorder.Eventf(&cluster, corev1.EventTypeWarning, events.EventReasonInvalidLicense, "License secret %s not found", secretName)
+		return noResult, true, nil
+	}
+	if err != nil {
+		return noResult, true, err
+	}
+	esLicense, err := license.ParseElasticsearchLicense(secret.Data)
+	if err != nil {
+		r.recorder.Eventf(&cluster, corev1.EventTypeWarning, events.EventReasonInvalidLicense, "Invalid license in secret %s: %s", secretName, err.Error())
+		return noResult, true, nil
+	}
+	if !esLicense.IsValid(time.Now()) {
+		r.recorder.Eventf(&cluster, corev1.EventTypeWarning, events.EventReasonInvalidLicense, "License in secret %s is not valid at this time", secretName)
+		return noResult, true, nil
+	}
+	ulog.FromContext(ctx).V(1).Info("Found user provided license for cluster", "secret_name", secretName, "es_license", esLicense.UID, "license_type", esLicense.Type, "namespace", cluster.Namespace, "es_name", cluster.Name)
+	if err := reconcileSecret(ctx, r, cluster, secretName, esLicense); err != nil {
+		return noResult, true, err
+	}
+	return esLicense.ExpiryTime(), true, nil
+}
+
 func (r *ReconcileLicenses) minVersion(cluster esv1.Elasticsearch) (*version.Version, error) {
 	pods, err := sset.GetActualPodsForCluster(r, cluster)
 	if err != nil {
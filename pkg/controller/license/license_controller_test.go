@@ -16,6 +16,8 @@ import (
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 
 	esv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/elasticsearch/v1"
@@ -223,3 +225,81 @@ func TestReconcileLicenses_reconcileInternal(t *testing.T) {
 		})
 	}
 }
+
+func TestReconcileLicenses_reconcileUserProvidedLicense(t *testing.T) {
+	annotated := cluster.DeepCopy()
+	annotated.Annotations = map[string]string{commonlicense.LicenseSecretAnnotation: "my-license"}
+	userLicense := func(expiry time.Time, signature string) *corev1.Secret {
+		bytes, err := json.Marshal(map[string]client.License{"license": {
+			UID:                "user-license",
+			Type:               string(client.ElasticsearchLicenseTypePlatinum),
+			StartDateInMillis:  time.Now().Add(-1*time.Minute).Unix() * 1000,
+			ExpiryDateInMillis: expiry.Unix() * 1000,
+			Signature:          signature,
+		}})
+		require.NoError(t, err)
+		return &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: "my-license", Namespace: "namespace"},
+			Data:       map[string][]byte{"license.json": bytes},
+		}
+	}
+	tests := []struct {
+		name         string
+		k8sResources []runtime.Object
+		wantLicense  string
+		wantEvent    bool
+	}{
+		{
+			name: "user provided license takes precedence over the enterprise license",
+			k8sResources: []runtime.Object{
+				annotated,
+				enterpriseLicense(t, client.ElasticsearchLicenseTypePlatinum, 1, false),
+				userLicense(time.Now().Add(24*time.Hour), "signature"),
+			},
+			wantLicense: "user-license",
+		},
+		{
+			name:         "missing license secret",
+			k8sResources: []runtime.Object{annotated},
+			wantEvent:    true,
+		},
+		{
+			name:         "unsigned license",
+			k8sResources: []runtime.Object{annotated, userLicense(time.Now().Add(24*time.Hour), "")},
+			wantEvent:    true,
+		},
+		{
+			name:         "expired license",
+			k8sResources: []runtime.Object{annotated, userLicense(time.Now().Add(-24*time.Hour), "signature")},
+			wantEvent:    true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := k8s.NewFakeClient(tt.k8sResources...)
+			recorder := record.NewFakeRecorder(10)
+			r := &ReconcileLicenses{
+				Client:   c,
+				checker:  commonlicense.MockLicenseChecker{EnterpriseEnabled: true},
+				recorder: recorder,
+			}
+			nsn := k8s.ExtractNamespacedName(annotated)
+			res, err := r.reconcileInternal(context.Background(), reconcile.Request{NamespacedName: nsn}).Aggregate()
+			require.NoError(t, err)
+			require.NotZero(t, res.RequeueAfter)
+			require.Equal(t, tt.wantEvent, len(recorder.Events) > 0)
+
+			var secret corev1.Secret
+			err = c.Get(context.Background(), types.NamespacedName{Namespace: "namespace", Name: esv1.LicenseSecretName("cluster")}, &secret)
+			if tt.wantLicense == "" {
+				require.True(t, apierrors.IsNotFound(err))
+				return
+			}
+			require.NoError(t, err)
+			var applied client.License
+			require.NoError(t, json.Unmarshal(secret.Data[commonlicense.FileName], &applied))
+			require.Equal(t, tt.wantLicense, applied.UID)
+			require.Equal(t, "my-license", secret.Labels[commonlicense.LicenseLabelName])
+		})
+	}
+}
@@ -8,10 +8,13 @@ import (
 	"context"
 
 	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 
 	esv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/elasticsearch/v1"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/license"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/utils/k8s"
 )
 
@@ -34,3 +37,19 @@ func reconcileRequestsForAllClusters(c k8s.Client, log logr.Logger) ([]reconcile
 	}
 	return requests, nil
 }
+
+// reconcileRequestsForClustersReferencing returns a reconcile request for each cluster of the namespace of the given
+// secret which references it as the secret holding its license.
+func reconcileRequestsForClustersReferencing(c k8s.Client, secret corev1.Secret) ([]reconcile.Request, error) {
+	var clusters esv1.ElasticsearchList
+	if err := c.List(context.Background(), &clusters, client.InNamespace(secret.Namespace)); err != nil {
+		return nil, err
+	}
+	var requests []reconcile.Request
+	for i := range clusters.Items {
+		if clusters.Items[i].Annotations[license.LicenseSecretAnnotation] == secret.Name {
+			requests = append(requests, reconcile.Request{NamespacedName: k8s.ExtractNamespacedName(&clusters.Items[i])})
+		}
+	}
+	return requests, nil
+}
@@ -0,0 +1,101 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package logstash
+
+import (
+	"context"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	lsv1alpha1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/logstash/v1alpha1"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/driver"
+	commonlabels "github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/labels"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/reconciler"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/settings"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/utils/net"
+)
+
+const (
+	// ConfigFileName is the name of the Logstash settings file.
+	ConfigFileName = "logstash.yml"
+)
+
+// reconcileConfig reconciles the Secret holding the Logstash settings. Changes to the settings are not reloaded by
+// Logstash: the hash of the Secret content is part of the Pod template to rotate the Pods on any change.
+func reconcileConfig(ctx context.Context, d driver.Interface, ls lsv1alpha1.Logstash, ipFamily corev1.IPFamily) (corev1.Secret, error) {
+	cfg, err := buildConfig(d, ls, ipFamily)
+	if err != nil {
+		return corev1.Secret{}, err
+	}
+
+	expected := corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: ls.Namespace,
+			Name:      Config(ls.Name),
+			Labels:    commonlabels.AddCredentialsLabel(NewLabels(ls)),
+		},
+		Data: map[string][]byte{
+			ConfigFileName: cfg,
+		},
+	}
+
+	return reconciler.ReconcileSecret(ctx, d.K8sClient(), expected, &ls)
+}
+
+// buildConfig merges the user provided settings, from either the `config` or the `configRef` Secret, with the
+// default settings.
+func buildConfig(d driver.Interface, ls lsv1alpha1.Logstash, ipFamily corev1.IPFamily) ([]byte, error) {
+	// parse the configRef Secret first to always maintain the watch on the referenced Secret
+	userCfg, err := common.ParseConfigRef(d, &ls, ls.Spec.ConfigRef, ConfigFileName)
+	if err != nil {
+		return nil, err
+	}
+	if ls.Spec.Config != nil {
+		if userCfg, err = settings.NewCanonicalConfigFrom(ls.Spec.Config.Data); err != nil {
+			return nil, err
+		}
+	}
+
+	cfg := defaultConfig(ipFamily)
+	if err := cfg.MergeWith(userCfg); err != nil {
+		return nil, err
+	}
+	return cfg.Render()
+}
+
+// defaultConfig returns the default Logstash settings. The API is exposed on all interfaces for the readiness probe and
+// the API Service, and the automatic reload of the pipelines is enabled to apply changes to the pipelines without
+// restarting Logstash.
+func defaultConfig(ipFamily corev1.IPFamily) *settings.CanonicalConfig {
+	return settings.MustCanonicalConfig(map[string]interface{}{
+		"api.http.host":           net.InAddrAnyFor(ipFamily).String(),
+		"config.reload.automatic": true,
+	})
+}
+
+// reloadSettings holds the Logstash settings controlling the automatic reload of the pipelines.
+type reloadSettings struct {
+	Config struct {
+		Reload struct {
+			Automatic bool `config:"automatic"`
+		} `config:"reload"`
+	} `config:"config"`
+}
+
+// isReloadAutomatic returns true if Logstash automatically reloads the pipelines when they are updated, according to
+// the settings stored in the given Secret.
+func isReloadAutomatic(configSecret corev1.Secret) (bool, error) {
+	cfg, err := settings.ParseConfig(configSecret.Data[ConfigFileName])
+	if err != nil {
+		return false, err
+	}
+	var reload reloadSettings
+	if err := cfg.Unpack(&reload); err != nil {
+		return false, err
+	}
+	return reload.Config.Reload.Automatic, nil
+}
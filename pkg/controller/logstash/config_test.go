@@ -0,0 +1,73 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package logstash
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	commonv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/common/v1"
+	lsv1alpha1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/logstash/v1alpha1"
+)
+
+func Test_buildConfig(t *testing.T) {
+	configRef := corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "config"},
+		Data:       map[string][]byte{ConfigFileName: []byte("log.level: debug")},
+	}
+	tests := []struct {
+		name string
+		spec lsv1alpha1.LogstashSpec
+		want string
+	}{
+		{
+			name: "default settings",
+			want: "api:\n  http:\n    host: 0.0.0.0\nconfig:\n  reload:\n    automatic: true\n",
+		},
+		{
+			name: "inline settings override the default ones",
+			spec: lsv1alpha1.LogstashSpec{
+				Config: &commonv1.Config{Data: map[string]interface{}{"config.reload.automatic": false, "log.level": "debug"}},
+			},
+			want: "api:\n  http:\n    host: 0.0.0.0\nconfig:\n  reload:\n    automatic: false\nlog:\n  level: debug\n",
+		},
+		{
+			name: "settings from a Secret",
+			spec: lsv1alpha1.LogstashSpec{
+				ConfigRef: &commonv1.ConfigSource{SecretRef: commonv1.SecretRef{SecretName: "config"}},
+			},
+			want: "api:\n  http:\n    host: 0.0.0.0\nconfig:\n  reload:\n    automatic: true\nlog:\n  level: debug\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ls := lsv1alpha1.Logstash{
+				ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "ls"},
+				Spec:       tt.spec,
+			}
+			got, err := buildConfig(newTestReconciler(&configRef), ls, corev1.IPv4Protocol)
+			require.NoError(t, err)
+			require.Equal(t, tt.want, string(got))
+		})
+	}
+}
+
+func Test_isReloadAutomatic(t *testing.T) {
+	for _, tt := range []struct {
+		config string
+		want   bool
+	}{
+		{config: "config:\n  reload:\n    automatic: true\n", want: true},
+		{config: "config:\n  reload:\n    automatic: false\n", want: false},
+		{config: "log:\n  level: debug\n", want: false},
+	} {
+		got, err := isReloadAutomatic(corev1.Secret{Data: map[string][]byte{ConfigFileName: []byte(tt.config)}})
+		require.NoError(t, err)
+		require.Equal(t, tt.want, got)
+	}
+}
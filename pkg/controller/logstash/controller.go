@@ -0,0 +1,358 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package logstash
+
+import (
+	"context"
+	"fmt"
+	"hash/fnv"
+	"reflect"
+	"sync/atomic"
+
+	"go.elastic.co/apm/v2"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+
+	lsv1alpha1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/logstash/v1alpha1"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/association"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common"
+	commonassociation "github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/association"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/driver"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/events"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/operator"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/reconciler"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/tracing"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/version"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/watches"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/utils/k8s"
+	ulog "github.com/elastic/cloud-on-k8s/v2/pkg/utils/log"
+)
+
+const (
+	controllerName = "logstash-controller"
+)
+
+// Add creates a new Logstash Controller and adds it to the Manager with default RBAC. The Manager will set fields on the Controller
+// and Start it when the Manager is Started.
+func Add(mgr manager.Manager, params operator.Parameters) error {
+	r := newReconciler(mgr, params)
+	c, err := common.NewController(mgr, controllerName, r, params)
+	if err != nil {
+		return err
+	}
+	return addWatches(c, r)
+}
+
+// newReconciler returns a new reconcile.Reconciler.
+func newReconciler(mgr manager.Manager, params operator.Parameters) *ReconcileLogstash {
+	return &ReconcileLogstash{
+		Client:         mgr.GetClient(),
+		recorder:       mgr.GetEventRecorderFor(controllerName),
+		dynamicWatches: watches.NewDynamicWatches(),
+		Parameters:     params,
+	}
+}
+
+// addWatches adds watches for all resources this controller cares about
+func addWatches(c controller.Controller, r *ReconcileLogstash) error {
+	// Watch for changes to Logstash
+	if err := c.Watch(&source.Kind{Type: &lsv1alpha1.Logstash{}}, &handler.EnqueueRequestForObject{}); err != nil {
+		return err
+	}
+
+	// Watch StatefulSets
+	if err := c.Watch(&source.Kind{Type: &appsv1.StatefulSet{}}, &handler.EnqueueRequestForOwner{
+		IsController: true,
+		OwnerType:    &lsv1alpha1.Logstash{},
+	}); err != nil {
+		return err
+	}
+
+	// Watch Pods, to ensure `status.version` is correctly reconciled on any change.
+	// Watching StatefulSets only may lead to missing some events.
+	if err := watches.WatchPods(c, NameLabelName); err != nil {
+		return err
+	}
+
+	// Watch Services
+	if err := c.Watch(&source.Kind{Type: &corev1.Service{}}, &handler.EnqueueRequestForOwner{
+		IsController: true,
+		OwnerType:    &lsv1alpha1.Logstash{},
+	}); err != nil {
+		return err
+	}
+
+	// Watch owned and soft-owned Secrets
+	if err := c.Watch(&source.Kind{Type: &corev1.Secret{}}, &handler.EnqueueRequestForOwner{
+		IsController: true,
+		OwnerType:    &lsv1alpha1.Logstash{},
+	}); err != nil {
+		return err
+	}
+	if err := watches.WatchSoftOwnedSecrets(c, lsv1alpha1.Kind); err != nil {
+		return err
+	}
+
+	// Dynamically watch referenced Secrets: configRef, pipelinesRef, pipeline definitions and Secrets to connect to Elasticsearch
+	if err := c.Watch(&source.Kind{Type: &corev1.Secret{}}, r.dynamicWatches.Secrets); err != nil {
+		return err
+	}
+
+	// Dynamically watch the ConfigMaps holding pipeline definitions
+	return c.Watch(&source.Kind{Type: &corev1.ConfigMap{}}, r.dynamicWatches.ConfigMaps)
+}
+
+var _ reconcile.Reconciler = &ReconcileLogstash{}
+
+// ReconcileLogstash reconciles a Logstash object
+type ReconcileLogstash struct {
+	k8s.Client
+	operator.Parameters
+	recorder       record.EventRecorder
+	dynamicWatches watches.DynamicWatches
+	// iteration is the number of times this controller has run its Reconcile method
+	iteration uint64
+}
+
+func (r *ReconcileLogstash) K8sClient() k8s.Client {
+	return r.Client
+}
+
+func (r *ReconcileLogstash) DynamicWatches() watches.DynamicWatches {
+	return r.dynamicWatches
+}
+
+func (r *ReconcileLogstash) Recorder() record.EventRecorder {
+	return r.recorder
+}
+
+var _ driver.Interface = &ReconcileLogstash{}
+
+// Reconcile reads that state of the cluster for a Logstash object and makes changes based on the state read
+// and what is in the Logstash.Spec
+func (r *ReconcileLogstash) Reconcile(ctx context.Context, request reconcile.Request) (reconcile.Result, error) {
+	ctx = common.NewReconciliationContext(ctx, &r.iteration, r.Tracer, controllerName, "logstash_name", request)
+	defer common.LogReconciliationRun(ulog.FromContext(ctx))()
+	defer tracing.EndContextTransaction(ctx)
+
+	var ls lsv1alpha1.Logstash
+	if err := r.Client.Get(ctx, request.NamespacedName, &ls); err != nil {
+		if apierrors.IsNotFound(err) {
+			return reconcile.Result{}, r.onDelete(ctx, request.NamespacedName)
+		}
+		return reconcile.Result{}, tracing.CaptureError(ctx, err)
+	}
+
+	if common.IsUnmanaged(ctx, &ls) {
+		ulog.FromContext(ctx).Info("Object is currently not managed by this controller. Skipping reconciliation", "namespace", ls.Namespace, "logstash_name", ls.Name)
+		return reconcile.Result{}, nil
+	}
+
+	// do not reconcile resources last managed by a newer operator
+	if managed, err := common.ReconcileOperatorVersion(ctx, r.Client, &ls); err != nil || !managed {
+		return reconcile.Result{}, tracing.CaptureError(ctx, err)
+	}
+
+	if ls.IsMarkedForDeletion() {
+		return reconcile.Result{}, r.onDelete(ctx, k8s.ExtractNamespacedName(&ls))
+	}
+
+	results, status := r.doReconcile(ctx, ls)
+	if err := r.updateStatus(ctx, ls, status); err != nil {
+		if apierrors.IsConflict(err) {
+			return results.WithResult(reconcile.Result{Requeue: true}).Aggregate()
+		}
+		results.WithError(err)
+	}
+	return results.Aggregate()
+}
+
+func (r *ReconcileLogstash) doReconcile(ctx context.Context, ls lsv1alpha1.Logstash) (*reconciler.Results, lsv1alpha1.LogstashStatus) {
+	log := ulog.FromContext(ctx)
+	results := reconciler.NewResult(ctx)
+	status := newStatus(ls)
+
+	isEsAssocConfigured, err := association.IsConfiguredIfSet(ctx, &ls, r.recorder)
+	if err != nil {
+		return results.WithError(err), status
+	}
+	if !isEsAssocConfigured {
+		return results, status
+	}
+
+	// Run validation in case the webhook is disabled
+	if err := r.validate(ctx, ls); err != nil {
+		return results.WithError(err), status
+	}
+
+	if _, err := reconcileServices(ctx, r.Client, ls); err != nil {
+		return results.WithError(fmt.Errorf("reconcile services: %w", err)), status
+	}
+
+	lsVersion, err := version.Parse(ls.Spec.Version)
+	if err != nil {
+		return results.WithError(err), status
+	}
+	assocAllowed, err := association.AllowVersion(lsVersion, ls.Associated(), log, r.recorder)
+	if err != nil {
+		return results.WithError(err), status
+	}
+	if !assocAllowed {
+		// will eventually retry once updated
+		return results, status
+	}
+
+	configSecret, err := reconcileConfig(ctx, r, ls, r.IPFamily)
+	if err != nil {
+		return results.WithError(err), status
+	}
+
+	pipelinesSecret, err := reconcilePipelines(ctx, r, ls)
+	if err != nil {
+		return results.WithError(err), status
+	}
+	pipelines, err := parsePipelines(pipelinesSecret.Data[PipelinesFileName])
+	if err != nil {
+		return results.WithError(err), status
+	}
+
+	configHash, err := buildConfigHash(ctx, r, ls, configSecret, pipelinesSecret, pipelines)
+	if err != nil {
+		return results.WithError(fmt.Errorf("build config hash: %w", err)), status
+	}
+
+	sset, err := r.reconcileStatefulSet(ctx, ls, configHash)
+	if err != nil {
+		return results.WithError(fmt.Errorf("reconcile statefulset: %w", err)), status
+	}
+
+	status, err = r.getStatus(ctx, ls, sset)
+	if err != nil {
+		return results.WithError(fmt.Errorf("calculating status: %w", err)), status
+	}
+
+	// updates of the pipelines are reloaded asynchronously by Logstash, refresh their status until they run on all the Pods
+	var converged bool
+	status.Pipelines, converged = r.getPipelinesStatus(ctx, ls, pipelines)
+	if !converged {
+		results.WithResult(reconcile.Result{RequeueAfter: PipelinesStatusRequeue})
+	}
+
+	return results, status
+}
+
+func newStatus(ls lsv1alpha1.Logstash) lsv1alpha1.LogstashStatus {
+	status := ls.Status
+	status.ObservedGeneration = ls.Generation
+	return status
+}
+
+func (r *ReconcileLogstash) validate(ctx context.Context, ls lsv1alpha1.Logstash) error {
+	span, vctx := apm.StartSpan(ctx, "validate", tracing.SpanTypeApp)
+	defer span.End()
+
+	if err := ls.ValidateCreate(); err != nil {
+		ulog.FromContext(ctx).Error(err, "Validation failed")
+		k8s.EmitErrorEvent(r.recorder, err, &ls, events.EventReasonValidation, err.Error())
+		return tracing.CaptureError(vctx, err)
+	}
+
+	return nil
+}
+
+// buildConfigHash builds a hash of the Logstash settings, of the pipelines that are not reloaded by Logstash, and of
+// the associated Elasticsearch credentials and certificates, to rotate the Pods on any change.
+func buildConfigHash(
+	ctx context.Context,
+	d driver.Interface,
+	ls lsv1alpha1.Logstash,
+	configSecret corev1.Secret,
+	pipelinesSecret corev1.Secret,
+	pipelines []pipelineDefinition,
+) (string, error) {
+	configHash := fnv.New32a()
+
+	// - in the Logstash settings file content
+	_, _ = configHash.Write(configSecret.Data[ConfigFileName])
+
+	// - in the pipelines, if Logstash does not reload them
+	reloadAutomatic, err := isReloadAutomatic(configSecret)
+	if err != nil {
+		return "", err
+	}
+	if !reloadAutomatic {
+		_, _ = configHash.Write(pipelinesSecret.Data[PipelinesFileName])
+	}
+	if err := reconcilePipelinesSources(ctx, d, ls, pipelines, reloadAutomatic, configHash); err != nil {
+		return "", err
+	}
+
+	// - in the associated Elasticsearch credentials and TLS certificates
+	if err := commonassociation.WriteAssocsToConfigHash(d.K8sClient(), ls.GetAssociations(), configHash); err != nil {
+		return "", err
+	}
+
+	return fmt.Sprint(configHash.Sum32()), nil
+}
+
+func (r *ReconcileLogstash) reconcileStatefulSet(ctx context.Context, ls lsv1alpha1.Logstash, configHash string) (appsv1.StatefulSet, error) {
+	span, _ := apm.StartSpan(ctx, "reconcile_statefulset", tracing.SpanTypeApp)
+	defer span.End()
+
+	podTemplate, err := newPodTemplateSpec(ctx, r.Client, ls, configHash)
+	if err != nil {
+		return appsv1.StatefulSet{}, err
+	}
+	return reconcileStatefulSet(ctx, r.Client, ls, newStatefulSet(ls, podTemplate))
+}
+
+func (r *ReconcileLogstash) getStatus(ctx context.Context, ls lsv1alpha1.Logstash, sset appsv1.StatefulSet) (lsv1alpha1.LogstashStatus, error) {
+	status := newStatus(ls)
+	pods, err := k8s.PodsMatchingLabels(r.Client, ls.Namespace, map[string]string{NameLabelName: ls.Name})
+	if err != nil {
+		return status, err
+	}
+	ssetStatus, err := statefulSetStatus(ls.Status.DeploymentStatus, sset)
+	if err != nil {
+		return status, err
+	}
+	ssetStatus.Version = common.LowestVersionFromPods(ctx, ssetStatus.Version, pods, VersionLabelName)
+	status.DeploymentStatus = ssetStatus
+	return status, nil
+}
+
+func (r *ReconcileLogstash) updateStatus(ctx context.Context, ls lsv1alpha1.Logstash, status lsv1alpha1.LogstashStatus) error {
+	if reflect.DeepEqual(status, ls.Status) {
+		return nil // nothing to do
+	}
+	if status.IsDegraded(ls.Status.DeploymentStatus) {
+		r.recorder.Event(&ls, corev1.EventTypeWarning, events.EventReasonUnhealthy, "Logstash health degraded")
+	}
+	ulog.FromContext(ctx).V(1).Info("Updating status",
+		"iteration", atomic.LoadUint64(&r.iteration),
+		"namespace", ls.Namespace,
+		"logstash_name", ls.Name,
+		"status", status,
+	)
+	ls.Status = status
+	return common.UpdateStatus(ctx, r.Client, &ls)
+}
+
+func (r *ReconcileLogstash) onDelete(ctx context.Context, obj types.NamespacedName) error {
+	// Clean up watches set on the configRef and pipelinesRef Secrets, and on the pipeline definitions
+	r.dynamicWatches.Secrets.RemoveHandlerForKey(common.ConfigRefWatchName(obj))
+	r.dynamicWatches.Secrets.RemoveHandlerForKey(PipelinesRefWatchName(obj))
+	r.dynamicWatches.Secrets.RemoveHandlerForKey(PipelinesSourcesWatchName(obj))
+	r.dynamicWatches.ConfigMaps.RemoveHandlerForKey(PipelinesSourcesWatchName(obj))
+	return reconciler.GarbageCollectSoftOwnedSecrets(ctx, r.Client, obj, lsv1alpha1.Kind)
+}
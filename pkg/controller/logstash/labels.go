@@ -0,0 +1,35 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package logstash
+
+import (
+	lsv1alpha1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/logstash/v1alpha1"
+	commonlabels "github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/labels"
+)
+
+const (
+	// NameLabelName used to represent a Logstash in k8s resources
+	NameLabelName = "logstash.k8s.elastic.co/name"
+
+	// VersionLabelName used to propagate Logstash version from the spec to the pods
+	VersionLabelName = "logstash.k8s.elastic.co/version"
+
+	// Type represents the Logstash type
+	Type = "logstash"
+)
+
+// NewLabels constructs a new set of labels for a Logstash pod
+func NewLabels(ls lsv1alpha1.Logstash) map[string]string {
+	return map[string]string{
+		NameLabelName:              ls.Name,
+		commonlabels.TypeLabelName: Type,
+	}
+}
+
+func versionLabels(ls lsv1alpha1.Logstash) map[string]string {
+	return map[string]string{
+		VersionLabelName: ls.Spec.Version,
+	}
+}
@@ -0,0 +1,32 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package logstash
+
+import "github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/name"
+
+const (
+	configSuffix    = "config"
+	pipelinesSuffix = "pipeline"
+)
+
+// LSNamer is a Namer that is configured with the defaults for resources related to a Logstash resource.
+var LSNamer = name.NewNamer("ls")
+
+// Service returns the name of the Service with the given name suffix, for example "api" for the Logstash API Service.
+func Service(lsName string, svcName string) string {
+	return LSNamer.Suffix(lsName, svcName)
+}
+
+func StatefulSet(lsName string) string {
+	return LSNamer.Suffix(lsName)
+}
+
+func Config(lsName string) string {
+	return LSNamer.Suffix(lsName, configSuffix)
+}
+
+func Pipelines(lsName string) string {
+	return LSNamer.Suffix(lsName, pipelinesSuffix)
+}
@@ -0,0 +1,103 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package logstash
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/ghodss/yaml"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	lsv1alpha1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/logstash/v1alpha1"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/driver"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/events"
+	commonlabels "github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/labels"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/reconciler"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/watches"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/utils/k8s"
+)
+
+const (
+	// PipelinesFileName is the name of the file declaring the Logstash pipelines.
+	PipelinesFileName = "pipelines.yml"
+)
+
+// defaultPipelines runs the pipeline shipped in the Logstash Docker image when no pipeline is specified.
+var defaultPipelines = []byte(`- path.config: /usr/share/logstash/pipeline
+  pipeline.id: main
+`)
+
+// PipelinesRefWatchName returns the name of the watch registered on the Secret referenced in `pipelinesRef`.
+func PipelinesRefWatchName(ls types.NamespacedName) string {
+	return fmt.Sprintf("%s-%s-pipelinesref", ls.Namespace, ls.Name)
+}
+
+// reconcilePipelines reconciles the Secret holding the pipelines.yml file of Logstash. The Secret is mounted as a
+// directory in the Pods so that updates are propagated to the running Pods, where Logstash reloads the pipelines
+// without restarting.
+func reconcilePipelines(ctx context.Context, d driver.Interface, ls lsv1alpha1.Logstash) (corev1.Secret, error) {
+	pipelines, err := buildPipelines(ctx, d, ls)
+	if err != nil {
+		return corev1.Secret{}, err
+	}
+
+	expected := corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: ls.Namespace,
+			Name:      Pipelines(ls.Name),
+			Labels:    commonlabels.AddCredentialsLabel(NewLabels(ls)),
+		},
+		Data: map[string][]byte{
+			PipelinesFileName: pipelines,
+		},
+	}
+
+	return reconciler.ReconcileSecret(ctx, d.K8sClient(), expected, &ls)
+}
+
+// buildPipelines returns the content of the pipelines.yml file, from either the `pipelines` or the `pipelinesRef`
+// Secret.
+func buildPipelines(ctx context.Context, d driver.Interface, ls lsv1alpha1.Logstash) ([]byte, error) {
+	lsKey := k8s.ExtractNamespacedName(&ls)
+	// ensure the watch matches the referenced Secret, if any
+	var secretNames []string
+	if ls.Spec.PipelinesRef != nil && ls.Spec.PipelinesRef.SecretName != "" {
+		secretNames = append(secretNames, ls.Spec.PipelinesRef.SecretName)
+	}
+	if err := watches.WatchUserProvidedSecrets(lsKey, d.DynamicWatches(), PipelinesRefWatchName(lsKey), secretNames); err != nil {
+		return nil, err
+	}
+
+	switch {
+	case len(secretNames) > 0:
+		return getPipelinesRef(ctx, d, ls)
+	case len(ls.Spec.Pipelines) > 0:
+		return yaml.Marshal(ls.Spec.Pipelines)
+	default:
+		return defaultPipelines, nil
+	}
+}
+
+// getPipelinesRef returns the pipelines.yml entry of the Secret referenced in `pipelinesRef`. The content is not
+// parsed to be passed as is to Logstash.
+func getPipelinesRef(ctx context.Context, d driver.Interface, ls lsv1alpha1.Logstash) ([]byte, error) {
+	var secret corev1.Secret
+	key := types.NamespacedName{Namespace: ls.Namespace, Name: ls.Spec.PipelinesRef.SecretName}
+	if err := d.K8sClient().Get(ctx, key, &secret); err != nil {
+		// the Secret may not exist (yet) in the cache, let's explicitly error out and retry later
+		return nil, err
+	}
+	data, exists := secret.Data[PipelinesFileName]
+	if !exists {
+		msg := fmt.Sprintf("unable to parse pipelinesRef secret %s: missing key %s", key, PipelinesFileName)
+		d.Recorder().Event(&ls, corev1.EventTypeWarning, events.EventReasonUnexpected, msg)
+		return nil, errors.New(msg)
+	}
+	return data, nil
+}
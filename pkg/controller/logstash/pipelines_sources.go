@@ -0,0 +1,183 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package logstash
+
+import (
+	"context"
+	"fmt"
+	"hash"
+	"path"
+	"sort"
+	"strings"
+
+	"github.com/ghodss/yaml"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+
+	lsv1alpha1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/logstash/v1alpha1"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/driver"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/watches"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/utils/k8s"
+)
+
+// PipelinesSourcesWatchName returns the name of the watches registered on the ConfigMaps and Secrets mounted in the
+// Logstash container that hold the pipeline definitions.
+func PipelinesSourcesWatchName(ls types.NamespacedName) string {
+	return fmt.Sprintf("%s-%s-pipelines-sources", ls.Namespace, ls.Name)
+}
+
+// pipelineDefinition is the subset of the settings of a pipeline in pipelines.yml used by the operator.
+type pipelineDefinition struct {
+	ID         string `json:"pipeline.id"`
+	PathConfig string `json:"path.config,omitempty"`
+}
+
+// parsePipelines returns the pipelines declared in the given pipelines.yml content.
+func parsePipelines(pipelines []byte) ([]pipelineDefinition, error) {
+	var definitions []pipelineDefinition
+	if err := yaml.Unmarshal(pipelines, &definitions); err != nil {
+		return nil, fmt.Errorf("unable to parse %s: %w", PipelinesFileName, err)
+	}
+	return definitions, nil
+}
+
+// pipelinesSource is a ConfigMap or Secret volume mounted in the Logstash container and holding pipeline definitions
+// referenced with `path.config`.
+type pipelinesSource struct {
+	volume corev1.Volume
+	// subPath is true if the volume is mounted with a sub path, in which case updates are not propagated to the Pods.
+	subPath bool
+}
+
+// pipelinesSources returns the ConfigMap and Secret volumes of the Pod template mounted in the Logstash container at
+// a path referenced in the `path.config` setting of a pipeline.
+func pipelinesSources(ls lsv1alpha1.Logstash, definitions []pipelineDefinition) []pipelinesSource {
+	var mounts []corev1.VolumeMount
+	for _, c := range ls.Spec.PodTemplate.Spec.Containers {
+		if c.Name == lsv1alpha1.LogstashContainerName {
+			mounts = c.VolumeMounts
+		}
+	}
+
+	var sources []pipelinesSource
+	for _, v := range ls.Spec.PodTemplate.Spec.Volumes {
+		if v.ConfigMap == nil && v.Secret == nil {
+			continue
+		}
+		for _, m := range mounts {
+			if m.Name == v.Name && referencedByPipelines(m.MountPath, definitions) {
+				sources = append(sources, pipelinesSource{volume: v, subPath: m.SubPath != "" || m.SubPathExpr != ""})
+				break
+			}
+		}
+	}
+	return sources
+}
+
+// referencedByPipelines returns true if the `path.config` setting of one of the pipelines, which can be a glob
+// pattern, points to a file under the given mount path.
+func referencedByPipelines(mountPath string, definitions []pipelineDefinition) bool {
+	mountPath = path.Clean(mountPath)
+	for _, d := range definitions {
+		if d.PathConfig == "" {
+			continue
+		}
+		pathConfig := path.Clean(d.PathConfig)
+		if pathConfig == mountPath || strings.HasPrefix(pathConfig, mountPath+"/") {
+			return true
+		}
+	}
+	return false
+}
+
+// reconcilePipelinesSources watches the ConfigMaps and Secrets holding the pipeline definitions referenced with
+// `path.config`, and writes into the given hash the content of those whose updates are not reloaded by Logstash, for
+// the Pods to be rotated instead:
+// - all of them if the automatic reload of the pipelines is disabled
+// - the ones mounted with a sub path otherwise, as Kubernetes does not propagate their updates to the running Pods
+func reconcilePipelinesSources(
+	ctx context.Context,
+	d driver.Interface,
+	ls lsv1alpha1.Logstash,
+	definitions []pipelineDefinition,
+	reloadAutomatic bool,
+	configHash hash.Hash,
+) error {
+	sources := pipelinesSources(ls, definitions)
+
+	var configMaps, secrets []string
+	for _, s := range sources {
+		if s.volume.ConfigMap != nil {
+			configMaps = append(configMaps, s.volume.ConfigMap.Name)
+		} else {
+			secrets = append(secrets, s.volume.Secret.SecretName)
+		}
+	}
+	lsKey := k8s.ExtractNamespacedName(&ls)
+	if err := watches.WatchUserProvidedConfigMaps(lsKey, d.DynamicWatches(), PipelinesSourcesWatchName(lsKey), configMaps); err != nil {
+		return err
+	}
+	if err := watches.WatchUserProvidedSecrets(lsKey, d.DynamicWatches(), PipelinesSourcesWatchName(lsKey), secrets); err != nil {
+		return err
+	}
+
+	for _, s := range sources {
+		if reloadAutomatic && !s.subPath {
+			continue
+		}
+		data, err := pipelinesSourceData(ctx, d.K8sClient(), ls.Namespace, s.volume)
+		if err != nil {
+			return err
+		}
+		writeSortedData(configHash, data)
+	}
+	return nil
+}
+
+// pipelinesSourceData returns the content of the ConfigMap or Secret of the given volume. A missing ConfigMap or
+// Secret prevents the Pods from starting and is not reported as an error: the watch triggers a new reconciliation once
+// it is created.
+func pipelinesSourceData(ctx context.Context, c k8s.Client, namespace string, v corev1.Volume) (map[string][]byte, error) {
+	if v.ConfigMap != nil {
+		var cm corev1.ConfigMap
+		if err := c.Get(ctx, types.NamespacedName{Namespace: namespace, Name: v.ConfigMap.Name}, &cm); err != nil {
+			if apierrors.IsNotFound(err) {
+				return nil, nil
+			}
+			return nil, err
+		}
+		data := make(map[string][]byte, len(cm.Data)+len(cm.BinaryData))
+		for key, value := range cm.Data {
+			data[key] = []byte(value)
+		}
+		for key, value := range cm.BinaryData {
+			data[key] = value
+		}
+		return data, nil
+	}
+
+	var secret corev1.Secret
+	if err := c.Get(ctx, types.NamespacedName{Namespace: namespace, Name: v.Secret.SecretName}, &secret); err != nil {
+		if apierrors.IsNotFound(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return secret.Data, nil
+}
+
+// writeSortedData writes the given data into the hash, sorted by key for the hash to be stable.
+func writeSortedData(h hash.Hash, data map[string][]byte) {
+	keys := make([]string, 0, len(data))
+	for k := range data {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		_, _ = h.Write([]byte(k))
+		_, _ = h.Write(data[k])
+	}
+}
@@ -0,0 +1,106 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package logstash
+
+import (
+	"context"
+	"hash/fnv"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	lsv1alpha1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/logstash/v1alpha1"
+)
+
+func Test_parsePipelines(t *testing.T) {
+	definitions, err := parsePipelines([]byte("- pipeline.id: main\n  path.config: /usr/share/logstash/pipeline/*.conf\n- pipeline.id: other\n  config.string: input { stdin {} }\n"))
+	require.NoError(t, err)
+	require.Equal(t, []pipelineDefinition{
+		{ID: "main", PathConfig: "/usr/share/logstash/pipeline/*.conf"},
+		{ID: "other"},
+	}, definitions)
+
+	_, err = parsePipelines([]byte("pipeline.id: not-a-list"))
+	require.Error(t, err)
+}
+
+func Test_reconcilePipelinesSources(t *testing.T) {
+	configMap := corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "pipeline-definitions"},
+		Data:       map[string]string{"main.conf": "input { beats { port => 5044 } }"},
+	}
+	updatedConfigMap := configMap
+	updatedConfigMap.Data = map[string]string{"main.conf": "input { beats { port => 5045 } }"}
+
+	definitions := []pipelineDefinition{{ID: "main", PathConfig: "/usr/share/logstash/pipeline-definitions/*.conf"}}
+	newLogstash := func(subPath string) lsv1alpha1.Logstash {
+		return lsv1alpha1.Logstash{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "ls"},
+			Spec: lsv1alpha1.LogstashSpec{
+				PodTemplate: corev1.PodTemplateSpec{
+					Spec: corev1.PodSpec{
+						Containers: []corev1.Container{{
+							Name: lsv1alpha1.LogstashContainerName,
+							VolumeMounts: []corev1.VolumeMount{
+								{Name: "pipeline-definitions", MountPath: "/usr/share/logstash/pipeline-definitions", SubPath: subPath},
+								{Name: "other", MountPath: "/usr/share/logstash/other"},
+							},
+						}},
+						Volumes: []corev1.Volume{
+							{Name: "pipeline-definitions", VolumeSource: corev1.VolumeSource{
+								ConfigMap: &corev1.ConfigMapVolumeSource{LocalObjectReference: corev1.LocalObjectReference{Name: "pipeline-definitions"}},
+							}},
+							{Name: "other", VolumeSource: corev1.VolumeSource{
+								Secret: &corev1.SecretVolumeSource{SecretName: "other"},
+							}},
+						},
+					},
+				},
+			},
+		}
+	}
+
+	tests := []struct {
+		name            string
+		ls              lsv1alpha1.Logstash
+		reloadAutomatic bool
+		wantRotation    bool
+	}{
+		{
+			name:            "updates are reloaded by Logstash",
+			ls:              newLogstash(""),
+			reloadAutomatic: true,
+			wantRotation:    false,
+		},
+		{
+			name:            "automatic reload disabled",
+			ls:              newLogstash(""),
+			reloadAutomatic: false,
+			wantRotation:    true,
+		},
+		{
+			name:            "updates are not propagated to volumes mounted with a sub path",
+			ls:              newLogstash("main.conf"),
+			reloadAutomatic: true,
+			wantRotation:    true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			hashWith := func(cm corev1.ConfigMap) uint32 {
+				r := newTestReconciler(&cm)
+				h := fnv.New32a()
+				require.NoError(t, reconcilePipelinesSources(context.Background(), r, tt.ls, definitions, tt.reloadAutomatic, h))
+				// only the ConfigMap referenced by the pipelines is watched
+				require.Len(t, r.dynamicWatches.ConfigMaps.Registrations(), 1)
+				require.Len(t, r.dynamicWatches.Secrets.Registrations(), 0)
+				return h.Sum32()
+			}
+			require.Equal(t, tt.wantRotation, hashWith(configMap) != hashWith(updatedConfigMap))
+		})
+	}
+}
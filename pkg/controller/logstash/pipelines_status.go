@@ -0,0 +1,199 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package logstash
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	"go.elastic.co/apm/module/apmhttp/v2"
+	corev1 "k8s.io/api/core/v1"
+
+	lsv1alpha1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/logstash/v1alpha1"
+	commonhttp "github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/http"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/tracing"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/utils/k8s"
+	ulog "github.com/elastic/cloud-on-k8s/v2/pkg/utils/log"
+)
+
+const (
+	// PipelinesStatsPath is the path of the Logstash API returning the statistics of the running pipelines.
+	PipelinesStatsPath = "/_node/stats/pipelines"
+	// PipelinesStatsTimeout is the duration after which a request to the Logstash API is canceled.
+	PipelinesStatsTimeout = 10 * time.Second
+	// PipelinesStatusRequeue is the delay after which the status of the pipelines is refreshed while they do not run
+	// on all the Pods or failed to reload. Updates of the pipelines are propagated to the Pods and reloaded by Logstash
+	// asynchronously.
+	PipelinesStatusRequeue = 30 * time.Second
+
+	// defaultPipelineID is the ID given by Logstash to a pipeline without `pipeline.id`.
+	defaultPipelineID = "main"
+)
+
+// pipelinesStats is the subset of the response of the pipelines stats API used by the operator.
+type pipelinesStats struct {
+	Pipelines map[string]pipelineStats `json:"pipelines"`
+}
+
+type pipelineStats struct {
+	Reloads pipelineReloads `json:"reloads"`
+}
+
+type pipelineReloads struct {
+	LastError            *pipelineReloadError `json:"last_error"`
+	LastSuccessTimestamp *time.Time           `json:"last_success_timestamp"`
+	LastFailureTimestamp *time.Time           `json:"last_failure_timestamp"`
+}
+
+type pipelineReloadError struct {
+	Message string `json:"message"`
+}
+
+// lastReloadFailed returns true if the last reload of the pipeline failed, in which case Logstash keeps running the
+// previous version of the pipeline.
+func (r pipelineReloads) lastReloadFailed() bool {
+	if r.LastFailureTimestamp == nil {
+		return false
+	}
+	return r.LastSuccessTimestamp == nil || r.LastFailureTimestamp.After(*r.LastSuccessTimestamp)
+}
+
+// getPipelinesStatus returns the status of the pipelines aggregated over the ready Pods, and whether all the pipelines
+// run on all the Pods that could be reached. The previous status is kept if no Pod could be reached.
+func (r *ReconcileLogstash) getPipelinesStatus(
+	ctx context.Context,
+	ls lsv1alpha1.Logstash,
+	definitions []pipelineDefinition,
+) ([]lsv1alpha1.PipelineStatus, bool) {
+	log := ulog.FromContext(ctx)
+
+	pods, err := k8s.PodsMatchingLabels(r.Client, ls.Namespace, map[string]string{NameLabelName: ls.Name})
+	if err != nil {
+		log.Error(err, "Failed to list Logstash Pods", "namespace", ls.Namespace, "logstash_name", ls.Name)
+		return ls.Status.Pipelines, true
+	}
+
+	httpClient := apmhttp.WrapClient(
+		commonhttp.Client(r.Dialer, nil, PipelinesStatsTimeout),
+		apmhttp.WithClientRequestName(tracing.RequestName),
+		apmhttp.WithClientSpanType("external.logstash"),
+	)
+	defer httpClient.CloseIdleConnections()
+
+	var stats []pipelinesStats
+	for _, pod := range pods {
+		if !k8s.IsPodReady(pod) {
+			continue
+		}
+		podStats, err := getPipelinesStats(ctx, httpClient, ls, pod)
+		if err != nil {
+			// the operator may not be able to reach the Pods, the status is reported on a best effort basis
+			log.V(1).Info("Failed to retrieve the pipelines stats", "namespace", pod.Namespace, "pod_name", pod.Name, "error", err.Error())
+			continue
+		}
+		stats = append(stats, podStats)
+	}
+	if len(stats) == 0 {
+		return ls.Status.Pipelines, true
+	}
+
+	status := aggregatePipelinesStatus(definitions, stats)
+	return status, pipelinesConverged(status, int32(len(stats)))
+}
+
+// getPipelinesStats retrieves the stats of the pipelines running in the given Pod from the Logstash API. The Pod is
+// reached through its DNS record in the headless API Service.
+func getPipelinesStats(ctx context.Context, httpClient *http.Client, ls lsv1alpha1.Logstash, pod corev1.Pod) (pipelinesStats, error) {
+	url := fmt.Sprintf("http://%s.%s.%s.svc:%d%s",
+		pod.Name, Service(ls.Name, lsv1alpha1.APIServiceName), pod.Namespace, APIPort, PipelinesStatsPath)
+
+	timeoutCtx, cancel := context.WithTimeout(ctx, PipelinesStatsTimeout)
+	defer cancel()
+	req, err := http.NewRequestWithContext(timeoutCtx, http.MethodGet, url, nil)
+	if err != nil {
+		return pipelinesStats{}, err
+	}
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return pipelinesStats{}, err
+	}
+	defer resp.Body.Close()
+	if err := commonhttp.MaybeAPIError(resp); err != nil {
+		return pipelinesStats{}, err
+	}
+
+	var stats pipelinesStats
+	if err := json.NewDecoder(resp.Body).Decode(&stats); err != nil {
+		return pipelinesStats{}, err
+	}
+	return stats, nil
+}
+
+// aggregatePipelinesStatus builds the status of the declared pipelines, followed by the ones only reported by
+// Logstash, for example when the pipelines are loaded from the default `path.config`. Internal pipelines, whose ID
+// starts with a dot, are ignored.
+func aggregatePipelinesStatus(definitions []pipelineDefinition, stats []pipelinesStats) []lsv1alpha1.PipelineStatus {
+	var ids []string
+	declared := make(map[string]bool, len(definitions))
+	for _, d := range definitions {
+		id := d.ID
+		if id == "" {
+			id = defaultPipelineID
+		}
+		if !declared[id] {
+			declared[id] = true
+			ids = append(ids, id)
+		}
+	}
+	var reported []string
+	for _, podStats := range stats {
+		for id := range podStats.Pipelines {
+			if !declared[id] && !strings.HasPrefix(id, ".") {
+				declared[id] = true
+				reported = append(reported, id)
+			}
+		}
+	}
+	sort.Strings(reported)
+	ids = append(ids, reported...)
+
+	status := make([]lsv1alpha1.PipelineStatus, 0, len(ids))
+	for _, id := range ids {
+		pipelineStatus := lsv1alpha1.PipelineStatus{ID: id}
+		var lastFailure *time.Time
+		for _, podStats := range stats {
+			pipeline, running := podStats.Pipelines[id]
+			if !running {
+				continue
+			}
+			pipelineStatus.RunningPods++
+			if !pipeline.Reloads.lastReloadFailed() {
+				continue
+			}
+			pipelineStatus.ReloadFailedPods++
+			if pipeline.Reloads.LastError != nil && (lastFailure == nil || pipeline.Reloads.LastFailureTimestamp.After(*lastFailure)) {
+				lastFailure = pipeline.Reloads.LastFailureTimestamp
+				pipelineStatus.LastReloadError = pipeline.Reloads.LastError.Message
+			}
+		}
+		status = append(status, pipelineStatus)
+	}
+	return status
+}
+
+// pipelinesConverged returns true if all the pipelines run on the given number of Pods and were successfully reloaded.
+func pipelinesConverged(status []lsv1alpha1.PipelineStatus, pods int32) bool {
+	for _, s := range status {
+		if s.RunningPods != pods || s.ReloadFailedPods > 0 {
+			return false
+		}
+	}
+	return true
+}
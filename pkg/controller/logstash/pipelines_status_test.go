@@ -0,0 +1,99 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package logstash
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	lsv1alpha1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/logstash/v1alpha1"
+)
+
+const (
+	reloadedPipelinesStats = `{
+  "pipelines": {
+    "main": {
+      "reloads": {"successes": 1, "failures": 0, "last_error": null,
+        "last_success_timestamp": "2023-01-10T10:00:00.000Z", "last_failure_timestamp": null}
+    },
+    "beats": {
+      "reloads": {"successes": 0, "failures": 0, "last_error": null,
+        "last_success_timestamp": null, "last_failure_timestamp": null}
+    },
+    ".monitoring-logstash": {
+      "reloads": {"successes": 0, "failures": 0, "last_error": null,
+        "last_success_timestamp": null, "last_failure_timestamp": null}
+    }
+  }
+}`
+	failedReloadPipelinesStats = `{
+  "pipelines": {
+    "main": {
+      "reloads": {"successes": 1, "failures": 1, "last_error": {"message": "Expected one of [ \\t\\r\\n], \"#\", \"{\" at line 1"},
+        "last_success_timestamp": "2023-01-10T10:00:00.000Z", "last_failure_timestamp": "2023-01-10T10:05:00.000Z"}
+    }
+  }
+}`
+)
+
+func parseStats(t *testing.T, s string) pipelinesStats {
+	t.Helper()
+	var stats pipelinesStats
+	require.NoError(t, json.Unmarshal([]byte(s), &stats))
+	return stats
+}
+
+func Test_aggregatePipelinesStatus(t *testing.T) {
+	definitions := []pipelineDefinition{{ID: "main"}, {ID: "missing"}}
+
+	tests := []struct {
+		name          string
+		stats         []string
+		want          []lsv1alpha1.PipelineStatus
+		wantConverged bool
+	}{
+		{
+			name:  "pipelines reloaded on all the Pods",
+			stats: []string{reloadedPipelinesStats, reloadedPipelinesStats},
+			want: []lsv1alpha1.PipelineStatus{
+				{ID: "main", RunningPods: 2},
+				{ID: "missing", RunningPods: 0},
+				{ID: "beats", RunningPods: 2},
+			},
+			wantConverged: false,
+		},
+		{
+			name:  "failed reload on one Pod",
+			stats: []string{reloadedPipelinesStats, failedReloadPipelinesStats},
+			want: []lsv1alpha1.PipelineStatus{
+				{ID: "main", RunningPods: 2, ReloadFailedPods: 1, LastReloadError: `Expected one of [ \t\r\n], "#", "{" at line 1`},
+				{ID: "missing", RunningPods: 0},
+				{ID: "beats", RunningPods: 1},
+			},
+			wantConverged: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			stats := make([]pipelinesStats, 0, len(tt.stats))
+			for _, s := range tt.stats {
+				stats = append(stats, parseStats(t, s))
+			}
+			got := aggregatePipelinesStatus(definitions, stats)
+			require.Equal(t, tt.want, got)
+			require.Equal(t, tt.wantConverged, pipelinesConverged(got, int32(len(stats))))
+		})
+	}
+}
+
+func Test_pipelinesConverged(t *testing.T) {
+	stats := []pipelinesStats{parseStats(t, reloadedPipelinesStats), parseStats(t, reloadedPipelinesStats)}
+	status := aggregatePipelinesStatus([]pipelineDefinition{{ID: "main"}}, stats)
+	require.Equal(t, []lsv1alpha1.PipelineStatus{{ID: "main", RunningPods: 2}, {ID: "beats", RunningPods: 2}}, status)
+	require.True(t, pipelinesConverged(status, 2))
+	require.False(t, pipelinesConverged(status, 3))
+}
@@ -0,0 +1,97 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package logstash
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+
+	commonv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/common/v1"
+	lsv1alpha1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/logstash/v1alpha1"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/watches"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/utils/k8s"
+)
+
+func newTestReconciler(objs ...runtime.Object) *ReconcileLogstash {
+	return &ReconcileLogstash{
+		Client:         k8s.NewFakeClient(objs...),
+		recorder:       record.NewFakeRecorder(10),
+		dynamicWatches: watches.NewDynamicWatches(),
+	}
+}
+
+func Test_reconcilePipelines(t *testing.T) {
+	pipelinesRef := corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "pipelines"},
+		Data: map[string][]byte{
+			PipelinesFileName: []byte("- pipeline.id: from-ref\n  config.string: input { beats { port => 5044 } }\n"),
+		},
+	}
+	tests := []struct {
+		name      string
+		spec      lsv1alpha1.LogstashSpec
+		want      string
+		wantWatch bool
+		wantErr   bool
+	}{
+		{
+			name: "default pipeline",
+			spec: lsv1alpha1.LogstashSpec{},
+			want: string(defaultPipelines),
+		},
+		{
+			name: "inline pipelines",
+			spec: lsv1alpha1.LogstashSpec{
+				Pipelines: []commonv1.Config{
+					{Data: map[string]interface{}{"pipeline.id": "main", "config.string": "input { stdin {} }"}},
+				},
+			},
+			want: "- config.string: input { stdin {} }\n  pipeline.id: main\n",
+		},
+		{
+			name: "pipelines from a Secret",
+			spec: lsv1alpha1.LogstashSpec{
+				PipelinesRef: &commonv1.ConfigSource{SecretRef: commonv1.SecretRef{SecretName: "pipelines"}},
+			},
+			want:      string(pipelinesRef.Data[PipelinesFileName]),
+			wantWatch: true,
+		},
+		{
+			name: "missing Secret",
+			spec: lsv1alpha1.LogstashSpec{
+				PipelinesRef: &commonv1.ConfigSource{SecretRef: commonv1.SecretRef{SecretName: "missing"}},
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ls := lsv1alpha1.Logstash{
+				ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "ls"},
+				Spec:       tt.spec,
+			}
+			r := newTestReconciler(&ls, &pipelinesRef)
+
+			_, err := reconcilePipelines(context.Background(), r, ls)
+			if tt.wantErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+
+			var secret corev1.Secret
+			require.NoError(t, r.Client.Get(context.Background(), types.NamespacedName{Namespace: "ns", Name: "ls-ls-pipeline"}, &secret))
+			require.Equal(t, tt.want, string(secret.Data[PipelinesFileName]))
+			require.Equal(t, tt.wantWatch, len(r.dynamicWatches.Secrets.Registrations()) > 0)
+		})
+	}
+}
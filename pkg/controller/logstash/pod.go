@@ -0,0 +1,226 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package logstash
+
+import (
+	"context"
+	"path/filepath"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/apimachinery/pkg/util/intstr"
+
+	lsv1alpha1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/logstash/v1alpha1"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/association"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/certificates"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/container"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/defaults"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/volume"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/utils/k8s"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/utils/maps"
+)
+
+const (
+	configHashAnnotationName = "logstash.k8s.elastic.co/config-hash"
+
+	InitConfigContainerName = "elastic-internal-init-config"
+
+	// ConfigVolumeMountPath is the Logstash config/ directory, an empty volume prepared by the init container.
+	ConfigVolumeMountPath              = "/usr/share/logstash/config"
+	InitContainerConfigVolumeMountPath = "/mnt/elastic-internal/logstash-config-local"
+	// InternalConfigVolumeMountPath is where the Secret holding logstash.yml is mounted.
+	InternalConfigVolumeMountPath = "/mnt/elastic-internal/logstash-config"
+	// InternalPipelinesVolumeMountPath is where the Secret holding pipelines.yml is mounted.
+	InternalPipelinesVolumeMountPath = "/mnt/elastic-internal/logstash-pipelines"
+	// ESCertsPath is where the CA certificate of the associated Elasticsearch cluster is mounted.
+	ESCertsPath = "/mnt/elastic-internal/elasticsearch-association/certs"
+
+	DataVolumeMountPath = "/usr/share/logstash/data"
+
+	// Environment variables exposing the Elasticsearch association to the Logstash pipelines.
+	ElasticsearchHostsEnvVar                   = "ELASTICSEARCH_HOSTS"
+	ElasticsearchUsernameEnvVar                = "ELASTICSEARCH_USERNAME"
+	ElasticsearchPasswordEnvVar                = "ELASTICSEARCH_PASSWORD" //nolint:gosec
+	ElasticsearchSSLCertificateAuthorityEnvVar = "ELASTICSEARCH_SSL_CERTIFICATE_AUTHORITY"
+
+	// InitConfigScript is a small bash script to prepare the Logstash configuration directory. The default settings
+	// of the Logstash Docker image (JVM options, logging) are kept, logstash.yml is copied as Logstash does not reload
+	// it, and pipelines.yml is linked to the mounted Secret for Logstash to reload the pipelines when it is updated.
+	InitConfigScript = `#!/usr/bin/env bash
+set -eux
+
+init_config_initialized_flag=` + InitContainerConfigVolumeMountPath + `/elastic-internal-init-config.ok
+
+if [[ -f "${init_config_initialized_flag}" ]]; then
+    echo "Logstash configuration already initialized."
+    exit 0
+fi
+
+echo "Setup Logstash configuration"
+
+cp -f ` + ConfigVolumeMountPath + `/* ` + InitContainerConfigVolumeMountPath + `/
+cp -f ` + InternalConfigVolumeMountPath + `/` + ConfigFileName + ` ` + InitContainerConfigVolumeMountPath + `/` + ConfigFileName + `
+ln -sf ` + InternalPipelinesVolumeMountPath + `/` + PipelinesFileName + ` ` + InitContainerConfigVolumeMountPath + `/` + PipelinesFileName + `
+
+touch "${init_config_initialized_flag}"
+echo "Logstash configuration successfully prepared."
+`
+)
+
+var (
+	// ConfigSharedVolume contains the Logstash config/ directory, initialized by the init container.
+	ConfigSharedVolume = volume.SharedVolume{
+		VolumeName:             "elastic-internal-logstash-config-local",
+		InitContainerMountPath: InitContainerConfigVolumeMountPath,
+		ContainerMountPath:     ConfigVolumeMountPath,
+	}
+
+	// DataVolume holds the Logstash data directory. It can be replaced by a persistent volume in the Pod template,
+	// for example to use persistent queues.
+	DataVolume = volume.NewEmptyDirVolume("logstash-data", DataVolumeMountPath)
+
+	DefaultMemoryLimits = resource.MustParse("2Gi")
+	DefaultResources    = corev1.ResourceRequirements{
+		Requests: map[corev1.ResourceName]resource.Quantity{
+			corev1.ResourceMemory: DefaultMemoryLimits,
+			corev1.ResourceCPU:    resource.MustParse("1"),
+		},
+		Limits: map[corev1.ResourceName]resource.Quantity{
+			corev1.ResourceMemory: DefaultMemoryLimits,
+			corev1.ResourceCPU:    resource.MustParse("2"),
+		},
+	}
+)
+
+// configVolume returns a SecretVolume to hold the logstash.yml file of the given Logstash resource.
+func configVolume(ls lsv1alpha1.Logstash) volume.SecretVolume {
+	return volume.NewSecretVolumeWithMountPath(Config(ls.Name), "elastic-internal-logstash-config", InternalConfigVolumeMountPath)
+}
+
+// pipelinesVolume returns a SecretVolume to hold the pipelines.yml file of the given Logstash resource. It is mounted
+// as a directory, without sub path, for the updates of the Secret to be propagated to the running Pods.
+func pipelinesVolume(ls lsv1alpha1.Logstash) volume.SecretVolume {
+	return volume.NewSecretVolumeWithMountPath(Pipelines(ls.Name), "elastic-internal-logstash-pipelines", InternalPipelinesVolumeMountPath)
+}
+
+// readinessProbe is the readiness probe for the Logstash container
+func readinessProbe() corev1.Probe {
+	return corev1.Probe{
+		FailureThreshold:    3,
+		InitialDelaySeconds: 30,
+		PeriodSeconds:       10,
+		SuccessThreshold:    1,
+		TimeoutSeconds:      5,
+		ProbeHandler: corev1.ProbeHandler{
+			HTTPGet: &corev1.HTTPGetAction{
+				Port:   intstr.FromInt(APIPort),
+				Path:   "/",
+				Scheme: corev1.URISchemeHTTP,
+			},
+		},
+	}
+}
+
+// initConfigContainer returns an init container that executes a bash script to prepare the Logstash config directory.
+func initConfigContainer(ls lsv1alpha1.Logstash) corev1.Container {
+	privileged := false
+
+	return corev1.Container{
+		// Image will be inherited from pod template defaults
+		ImagePullPolicy: corev1.PullIfNotPresent,
+		Name:            InitConfigContainerName,
+		SecurityContext: &corev1.SecurityContext{
+			Privileged: &privileged,
+		},
+		Command: []string{"/usr/bin/env", "bash", "-c", InitConfigScript},
+		VolumeMounts: []corev1.VolumeMount{
+			ConfigSharedVolume.InitContainerVolumeMount(),
+			configVolume(ls).VolumeMount(),
+			pipelinesVolume(ls).VolumeMount(),
+		},
+		Resources: corev1.ResourceRequirements{
+			Requests: map[corev1.ResourceName]resource.Quantity{
+				corev1.ResourceMemory: resource.MustParse("50Mi"),
+				corev1.ResourceCPU:    resource.MustParse("0.1"),
+			},
+			Limits: map[corev1.ResourceName]resource.Quantity{
+				// Memory limit should be at least 12582912 when running with CRI-O
+				corev1.ResourceMemory: resource.MustParse("50Mi"),
+				corev1.ResourceCPU:    resource.MustParse("0.1"),
+			},
+		},
+	}
+}
+
+func newPodTemplateSpec(ctx context.Context, c k8s.Client, ls lsv1alpha1.Logstash, configHash string) (corev1.PodTemplateSpec, error) {
+	labels := maps.Merge(NewLabels(ls), versionLabels(ls))
+	// ensure the Pods get rotated on any change of the settings and of the pipelines that Logstash does not reload
+	annotations := map[string]string{configHashAnnotationName: configHash}
+
+	ports := []corev1.ContainerPort{
+		{Name: APIPortName, ContainerPort: int32(APIPort), Protocol: corev1.ProtocolTCP},
+	}
+
+	cfgVolume := configVolume(ls)
+	plVolume := pipelinesVolume(ls)
+
+	builder := defaults.NewPodTemplateBuilder(ls.Spec.PodTemplate, lsv1alpha1.LogstashContainerName).
+		WithResources(DefaultResources).
+		WithLabels(labels).
+		WithAnnotations(annotations).
+		WithDockerImage(ls.Spec.Image, container.ImageRepository(container.LogstashImage, ls.Spec.Version)).
+		WithReadinessProbe(readinessProbe()).
+		WithPorts(ports).
+		WithInitContainers(initConfigContainer(ls)).
+		WithVolumeLikes(ConfigSharedVolume, DataVolume).
+		WithVolumes(cfgVolume.Volume(), plVolume.Volume()).
+		WithVolumeMounts(plVolume.VolumeMount())
+
+	builder, err := withElasticsearchAssociation(ctx, c, builder, ls)
+	if err != nil {
+		return corev1.PodTemplateSpec{}, err
+	}
+
+	return builder.WithInitContainerDefaults().PodTemplate, nil
+}
+
+// withElasticsearchAssociation exposes the connection settings of the associated Elasticsearch cluster to the Logstash
+// pipelines as environment variables, and mounts the CA certificate of the cluster.
+func withElasticsearchAssociation(ctx context.Context, c k8s.Client, builder *defaults.PodTemplateBuilder, ls lsv1alpha1.Logstash) (*defaults.PodTemplateBuilder, error) {
+	assocConf, err := ls.AssociationConf()
+	if err != nil {
+		return nil, err
+	}
+	if !assocConf.IsConfigured() {
+		return builder, nil
+	}
+
+	builder = builder.WithEnv(corev1.EnvVar{Name: ElasticsearchHostsEnvVar, Value: assocConf.GetURL()})
+
+	if assocConf.AuthIsConfigured() && !assocConf.NoAuthRequired() {
+		credentials, err := association.ElasticsearchAuthSettings(ctx, c, &ls)
+		if err != nil {
+			return nil, err
+		}
+		builder = builder.WithEnv(
+			corev1.EnvVar{Name: ElasticsearchUsernameEnvVar, Value: credentials.Username},
+			corev1.EnvVar{Name: ElasticsearchPasswordEnvVar, ValueFrom: &corev1.EnvVarSource{
+				SecretKeyRef: &corev1.SecretKeySelector{
+					LocalObjectReference: corev1.LocalObjectReference{Name: assocConf.GetAuthSecretName()},
+					Key:                  assocConf.GetAuthSecretKey(),
+				},
+			}},
+		)
+	}
+
+	if assocConf.CAIsConfigured() {
+		vol := volume.NewSecretVolumeWithMountPath(assocConf.GetCASecretName(), "elasticsearch-certs", ESCertsPath)
+		builder = builder.
+			WithVolumes(vol.Volume()).
+			WithVolumeMounts(vol.VolumeMount()).
+			WithEnv(corev1.EnvVar{Name: ElasticsearchSSLCertificateAuthorityEnvVar, Value: filepath.Join(ESCertsPath, certificates.CAFileName)})
+	}
+	return builder, nil
+}
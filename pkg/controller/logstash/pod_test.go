@@ -0,0 +1,108 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package logstash
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	commonv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/common/v1"
+	lsv1alpha1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/logstash/v1alpha1"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/container"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/pod"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/utils/k8s"
+)
+
+func Test_newPodTemplateSpec(t *testing.T) {
+	ls := lsv1alpha1.Logstash{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "ls"},
+		Spec:       lsv1alpha1.LogstashSpec{Version: "8.6.0", ElasticsearchRef: commonv1.ObjectSelector{Name: "es"}},
+	}
+	authSecret := corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "ls-ls-user"},
+		Data:       map[string][]byte{"ns-ls-ls-user": []byte("password")},
+	}
+	ls.SetAssociationConf(&commonv1.AssociationConf{
+		AuthSecretName: authSecret.Name,
+		AuthSecretKey:  "ns-ls-ls-user",
+		CACertProvided: true,
+		CASecretName:   "ls-ls-es-ca",
+		URL:            "https://es-es-http.ns.svc:9200",
+	})
+
+	podTemplate, err := newPodTemplateSpec(context.Background(), k8s.NewFakeClient(&authSecret), ls, "1234")
+	require.NoError(t, err)
+
+	require.Equal(t, "1234", podTemplate.Annotations[configHashAnnotationName])
+	require.Equal(t, "8.6.0", podTemplate.Labels[VersionLabelName])
+
+	lsContainer := pod.ContainerByName(podTemplate.Spec, lsv1alpha1.LogstashContainerName)
+	require.NotNil(t, lsContainer)
+	require.Equal(t, container.ImageRepository(container.LogstashImage, "8.6.0"), lsContainer.Image)
+	require.Equal(t, []corev1.EnvVar{
+		{Name: ElasticsearchHostsEnvVar, Value: "https://es-es-http.ns.svc:9200"},
+		{Name: ElasticsearchUsernameEnvVar, Value: "ns-ls-ls-user"},
+		{Name: ElasticsearchPasswordEnvVar, ValueFrom: &corev1.EnvVarSource{
+			SecretKeyRef: &corev1.SecretKeySelector{
+				LocalObjectReference: corev1.LocalObjectReference{Name: authSecret.Name},
+				Key:                  "ns-ls-ls-user",
+			},
+		}},
+		{Name: ElasticsearchSSLCertificateAuthorityEnvVar, Value: "/mnt/elastic-internal/elasticsearch-association/certs/ca.crt"},
+	}, lsContainer.Env)
+
+	mountPaths := make(map[string]string)
+	for _, m := range lsContainer.VolumeMounts {
+		mountPaths[m.MountPath] = m.Name
+	}
+	require.Equal(t, map[string]string{
+		ConfigVolumeMountPath:            "elastic-internal-logstash-config-local",
+		DataVolumeMountPath:              "logstash-data",
+		InternalPipelinesVolumeMountPath: "elastic-internal-logstash-pipelines",
+		ESCertsPath:                      "elasticsearch-certs",
+	}, mountPaths)
+
+	// the init container prepares the config directory mounted in the Logstash container
+	require.Len(t, podTemplate.Spec.InitContainers, 1)
+	initContainer := podTemplate.Spec.InitContainers[0]
+	require.Equal(t, InitConfigContainerName, initContainer.Name)
+	require.Equal(t, lsContainer.Image, initContainer.Image)
+}
+
+func Test_newPodTemplateSpec_NoAssociation(t *testing.T) {
+	ls := lsv1alpha1.Logstash{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "ls"},
+		Spec: lsv1alpha1.LogstashSpec{
+			Version: "8.6.0",
+			PodTemplate: corev1.PodTemplateSpec{
+				Spec: corev1.PodSpec{
+					Volumes: []corev1.Volume{{
+						Name: DataVolume.Name(),
+						VolumeSource: corev1.VolumeSource{
+							PersistentVolumeClaim: &corev1.PersistentVolumeClaimVolumeSource{ClaimName: "data"},
+						},
+					}},
+				},
+			},
+		},
+	}
+
+	podTemplate, err := newPodTemplateSpec(context.Background(), k8s.NewFakeClient(), ls, "1234")
+	require.NoError(t, err)
+
+	lsContainer := pod.ContainerByName(podTemplate.Spec, lsv1alpha1.LogstashContainerName)
+	require.NotNil(t, lsContainer)
+	require.Empty(t, lsContainer.Env)
+	// the data volume provided by the user takes precedence
+	for _, v := range podTemplate.Spec.Volumes {
+		if v.Name == DataVolume.Name() {
+			require.NotNil(t, v.PersistentVolumeClaim)
+		}
+	}
+}
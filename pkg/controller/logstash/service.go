@@ -0,0 +1,106 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package logstash
+
+import (
+	"context"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	lsv1alpha1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/logstash/v1alpha1"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/defaults"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/utils/k8s"
+	ulog "github.com/elastic/cloud-on-k8s/v2/pkg/utils/log"
+)
+
+const (
+	// APIPort is the port of the Logstash API, used for the readiness probe and exposed by the API Service.
+	APIPort = 9600
+	// APIPortName is the name of the port of the Logstash API.
+	APIPortName = "api"
+)
+
+// reconcileServices reconciles the Service exposing the Logstash API and the Services declared in the Logstash spec,
+// and deletes the Services which are not declared anymore.
+func reconcileServices(ctx context.Context, c k8s.Client, ls lsv1alpha1.Logstash) ([]corev1.Service, error) {
+	expected := append([]corev1.Service{*newAPIService(ls)}, newServices(ls)...)
+	reconciled := make([]corev1.Service, 0, len(expected))
+	for i := range expected {
+		svc, err := common.ReconcileService(ctx, c, &expected[i], &ls)
+		if err != nil {
+			return nil, err
+		}
+		reconciled = append(reconciled, *svc)
+	}
+	return reconciled, deleteUndeclaredServices(ctx, c, ls, expected)
+}
+
+// newAPIService returns the headless Service exposing the Logstash API, which is also the governing Service of the
+// Logstash StatefulSet.
+func newAPIService(ls lsv1alpha1.Logstash) *corev1.Service {
+	svc := corev1.Service{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: ls.Namespace,
+			Name:      Service(ls.Name, lsv1alpha1.APIServiceName),
+		},
+		Spec: corev1.ServiceSpec{
+			ClusterIP: corev1.ClusterIPNone,
+		},
+	}
+	labels := NewLabels(ls)
+	ports := []corev1.ServicePort{
+		{
+			Name:     APIPortName,
+			Protocol: corev1.ProtocolTCP,
+			Port:     APIPort,
+		},
+	}
+	return defaults.SetServiceDefaults(&svc, labels, labels, ports)
+}
+
+// newServices returns the Services declared in the Logstash spec, for example to expose the Beats input of a pipeline.
+func newServices(ls lsv1alpha1.Logstash) []corev1.Service {
+	services := make([]corev1.Service, 0, len(ls.Spec.Services))
+	for _, service := range ls.Spec.Services {
+		svc := corev1.Service{
+			ObjectMeta: *service.Service.ObjectMeta.DeepCopy(),
+			Spec:       *service.Service.Spec.DeepCopy(),
+		}
+		svc.ObjectMeta.Namespace = ls.Namespace
+		svc.ObjectMeta.Name = Service(ls.Name, service.Name)
+		labels := NewLabels(ls)
+		services = append(services, *defaults.SetServiceDefaults(&svc, labels, labels, nil))
+	}
+	return services
+}
+
+// deleteUndeclaredServices deletes the Services owned by the given Logstash which are not expected anymore, usually
+// because they were removed from the Logstash spec.
+func deleteUndeclaredServices(ctx context.Context, c k8s.Client, ls lsv1alpha1.Logstash, expected []corev1.Service) error {
+	var actual corev1.ServiceList
+	if err := c.List(ctx, &actual, client.InNamespace(ls.Namespace), client.MatchingLabels{NameLabelName: ls.Name}); err != nil {
+		return err
+	}
+	expectedNames := make(map[string]struct{}, len(expected))
+	for _, svc := range expected {
+		expectedNames[svc.Name] = struct{}{}
+	}
+	for i := range actual.Items {
+		svc := actual.Items[i]
+		if _, exists := expectedNames[svc.Name]; exists || !metav1.IsControlledBy(&svc, &ls) {
+			continue
+		}
+		ulog.FromContext(ctx).Info("Deleting Service not declared in the Logstash spec anymore",
+			"namespace", svc.Namespace, "service_name", svc.Name, "logstash_name", ls.Name)
+		if err := c.Delete(ctx, &svc); err != nil && !apierrors.IsNotFound(err) {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,66 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package logstash
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	commonv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/common/v1"
+	lsv1alpha1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/logstash/v1alpha1"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/utils/k8s"
+)
+
+func Test_reconcileServices(t *testing.T) {
+	ls := lsv1alpha1.Logstash{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "ls", UID: "uid"},
+		Spec: lsv1alpha1.LogstashSpec{
+			Services: []lsv1alpha1.LogstashService{
+				{
+					Name: "beats",
+					Service: commonv1.ServiceTemplate{
+						Spec: corev1.ServiceSpec{
+							Type:  corev1.ServiceTypeLoadBalancer,
+							Ports: []corev1.ServicePort{{Name: "beats", Protocol: corev1.ProtocolTCP, Port: 5044}},
+						},
+					},
+				},
+			},
+		},
+	}
+	c := k8s.NewFakeClient(&ls)
+
+	services, err := reconcileServices(context.Background(), c, ls)
+	require.NoError(t, err)
+	require.Len(t, services, 2)
+
+	api := services[0]
+	require.Equal(t, "ls-ls-api", api.Name)
+	require.Equal(t, corev1.ClusterIPNone, api.Spec.ClusterIP)
+	require.Equal(t, int32(APIPort), api.Spec.Ports[0].Port)
+	require.Equal(t, NewLabels(ls), api.Spec.Selector)
+
+	beats := services[1]
+	require.Equal(t, "ls-ls-beats", beats.Name)
+	require.Equal(t, corev1.ServiceTypeLoadBalancer, beats.Spec.Type)
+	require.Equal(t, int32(5044), beats.Spec.Ports[0].Port)
+	require.Equal(t, NewLabels(ls), beats.Spec.Selector)
+
+	// removing the Service from the spec deletes it
+	ls.Spec.Services = nil
+	services, err = reconcileServices(context.Background(), c, ls)
+	require.NoError(t, err)
+	require.Len(t, services, 1)
+
+	var actual corev1.ServiceList
+	require.NoError(t, c.List(context.Background(), &actual, client.InNamespace("ns")))
+	require.Len(t, actual.Items, 1)
+	require.Equal(t, "ls-ls-api", actual.Items[0].Name)
+}
@@ -0,0 +1,87 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package logstash
+
+import (
+	"context"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	commonv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/common/v1"
+	lsv1alpha1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/logstash/v1alpha1"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/hash"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/reconciler"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/utils/k8s"
+)
+
+// newStatefulSet returns the StatefulSet running the Logstash Pods. Pods are rolled one at a time on any change of the
+// Pod template, for example when the Logstash settings change.
+func newStatefulSet(ls lsv1alpha1.Logstash, podTemplate corev1.PodTemplateSpec) appsv1.StatefulSet {
+	labels := NewLabels(ls)
+	sset := appsv1.StatefulSet{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      StatefulSet(ls.Name),
+			Namespace: ls.Namespace,
+			Labels:    labels,
+		},
+		Spec: appsv1.StatefulSetSpec{
+			Replicas:             &ls.Spec.Count,
+			RevisionHistoryLimit: ls.Spec.RevisionHistoryLimit,
+			Selector: &metav1.LabelSelector{
+				MatchLabels: labels,
+			},
+			ServiceName:         Service(ls.Name, lsv1alpha1.APIServiceName),
+			PodManagementPolicy: appsv1.ParallelPodManagement,
+			UpdateStrategy: appsv1.StatefulSetUpdateStrategy{
+				Type: appsv1.RollingUpdateStatefulSetStrategyType,
+			},
+			Template: podTemplate,
+		},
+	}
+	// label the StatefulSet with a hash of itself
+	sset.Labels = hash.SetTemplateHashLabel(sset.Labels, sset)
+	return sset
+}
+
+// reconcileStatefulSet creates or updates the given StatefulSet for the given Logstash.
+func reconcileStatefulSet(ctx context.Context, c k8s.Client, ls lsv1alpha1.Logstash, expected appsv1.StatefulSet) (appsv1.StatefulSet, error) {
+	var reconciled appsv1.StatefulSet
+	err := reconciler.ReconcileResource(reconciler.Params{
+		Context:    ctx,
+		Client:     c,
+		Owner:      &ls,
+		Expected:   &expected,
+		Reconciled: &reconciled,
+		NeedsUpdate: func() bool {
+			// compare hash of the StatefulSet at the time it was built
+			return hash.GetTemplateHashLabel(reconciled.Labels) != hash.GetTemplateHashLabel(expected.Labels)
+		},
+		UpdateReconciled: func() {
+			expected.DeepCopyInto(&reconciled)
+		},
+	})
+	return reconciled, err
+}
+
+// statefulSetStatus returns the status of the Logstash StatefulSet. Unknown fields are inherited from current.
+func statefulSetStatus(current commonv1.DeploymentStatus, sset appsv1.StatefulSet) (commonv1.DeploymentStatus, error) {
+	status := *current.DeepCopy()
+	if sset.Spec.Selector != nil {
+		selector, err := metav1.LabelSelectorAsSelector(sset.Spec.Selector)
+		if err != nil {
+			return commonv1.DeploymentStatus{}, err
+		}
+		status.Selector = selector.String()
+	}
+	status.Count = sset.Status.Replicas
+	status.AvailableNodes = sset.Status.ReadyReplicas
+	status.Health = commonv1.RedHealth
+	if sset.Spec.Replicas != nil && sset.Status.ReadyReplicas == *sset.Spec.Replicas {
+		status.Health = commonv1.GreenHealth
+	}
+	return status, nil
+}
@@ -33,6 +33,7 @@ import (
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/deployment"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/driver"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/events"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/expose"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/license"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/operator"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/reconciler"
@@ -166,6 +167,11 @@ func (r *ReconcileMapsServer) Reconcile(ctx context.Context, request reconcile.R
 		return reconcile.Result{}, nil
 	}
 
+	// do not reconcile resources last managed by a newer operator
+	if managed, err := common.ReconcileOperatorVersion(ctx, r.Client, &ems); err != nil || !managed {
+		return reconcile.Result{}, tracing.CaptureError(ctx, err)
+	}
+
 	// MapsServer will be deleted nothing to do other than remove the watches
 	if ems.IsMarkedForDeletion() {
 		return reconcile.Result{}, r.onDelete(ctx, k8s.ExtractNamespacedName(&ems))
@@ -221,15 +227,18 @@ func (r *ReconcileMapsServer) doReconcile(ctx context.Context, ems emsv1alpha1.E
 	_, results = certificates.Reconciler{
 		K8sClient:             r.K8sClient(),
 		DynamicWatches:        r.DynamicWatches(),
+		Recorder:              r.Recorder(),
 		Owner:                 &ems,
 		TLSOptions:            ems.Spec.HTTP.TLS,
 		Namer:                 EMSNamer,
 		Labels:                labels(ems.Name),
 		Services:              []corev1.Service{*svc},
 		GlobalCA:              r.GlobalCA,
+		NamespaceCA:           r.NamespaceCA,
 		CACertRotation:        r.CACertRotation,
 		CertRotation:          r.CertRotation,
 		GarbageCollectSecrets: true,
+		ExtraHTTPSANs:         expose.SubjectAltNames(ems.Spec.HTTP),
 	}.ReconcileCAAndHTTPCerts(ctx)
 	if results.HasError() {
 		_, err := results.Aggregate()
@@ -237,6 +246,18 @@ func (r *ReconcileMapsServer) doReconcile(ctx context.Context, ems emsv1alpha1.E
 		return results, status
 	}
 
+	err = expose.Reconciler{
+		K8sClient: r.Client,
+		Owner:     &ems,
+		HTTP:      ems.Spec.HTTP,
+		Service:   *svc,
+		Namer:     EMSNamer,
+		Labels:    labels(ems.Name),
+	}.Reconcile(ctx)
+	if err != nil {
+		return results.WithError(err), status
+	}
+
 	emsVersion, err := version.Parse(ems.Spec.Version)
 	if err != nil {
 		return results.WithError(err), status
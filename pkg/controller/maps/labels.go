@@ -12,6 +12,8 @@ import (
 const (
 	// NameLabelName used to represent a MapsServer in k8s resources
 	NameLabelName = "maps.k8s.elastic.co/name"
+	// NamespaceLabelName used to represent the namespace of a MapsServer in k8s resources
+	NamespaceLabelName = "maps.k8s.elastic.co/namespace"
 
 	// versionLabelName used to propagate MapsServer version from the spec to the pods
 	versionLabelName = "maps.k8s.elastic.co/version"
@@ -0,0 +1,238 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package stackconfigpolicy
+
+import (
+	"context"
+	"reflect"
+	"sync/atomic"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+
+	esv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/elasticsearch/v1"
+	policyv1alpha1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/stackconfigpolicy/v1alpha1"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/events"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/license"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/operator"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/reconciler"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/tracing"
+	esuser "github.com/elastic/cloud-on-k8s/v2/pkg/controller/elasticsearch/user"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/utils/k8s"
+	ulog "github.com/elastic/cloud-on-k8s/v2/pkg/utils/log"
+)
+
+const (
+	controllerName = "stackconfigpolicy-controller"
+
+	// PolicyNameLabelName and PolicyNamespaceLabelName are set on the Secrets tracking the resources applied by a
+	// StackConfigPolicy to an Elasticsearch cluster.
+	PolicyNameLabelName      = "stackconfigpolicy.k8s.elastic.co/name"
+	PolicyNamespaceLabelName = "stackconfigpolicy.k8s.elastic.co/namespace"
+)
+
+// defaultRequeue is the delay after which a policy is reconciled again if it could not be applied to all the selected
+// clusters yet.
+var defaultRequeue = reconcile.Result{Requeue: true, RequeueAfter: 30 * time.Second}
+
+// Add creates a new StackConfigPolicy Controller and adds it to the Manager with default RBAC. The Manager will set
+// fields on the Controller and Start it when the Manager is Started.
+func Add(mgr manager.Manager, params operator.Parameters) error {
+	r := newReconciler(mgr, params)
+	c, err := common.NewController(mgr, controllerName, r, params)
+	if err != nil {
+		return err
+	}
+	return addWatches(c, r)
+}
+
+// newReconciler returns a new reconcile.Reconciler
+func newReconciler(mgr manager.Manager, params operator.Parameters) *ReconcileStackConfigPolicy {
+	k8sClient := mgr.GetClient()
+	return &ReconcileStackConfigPolicy{
+		Client:           k8sClient,
+		esClientProvider: esuser.NewControllerClient,
+		recorder:         mgr.GetEventRecorderFor(controllerName),
+		licenseChecker:   license.NewLicenseChecker(k8sClient, params.OperatorNamespace),
+		Parameters:       params,
+	}
+}
+
+func addWatches(c controller.Controller, r *ReconcileStackConfigPolicy) error {
+	// Watch for changes to StackConfigPolicy
+	if err := c.Watch(&source.Kind{Type: &policyv1alpha1.StackConfigPolicy{}}, &handler.EnqueueRequestForObject{}); err != nil {
+		return err
+	}
+
+	// Watch Elasticsearch clusters, to apply the policies to new clusters or to clusters whose labels changed
+	return c.Watch(
+		&source.Kind{Type: &esv1.Elasticsearch{}},
+		handler.EnqueueRequestsFromMapFunc(requestsAllPoliciesFor(r.Client, r.OperatorNamespace)),
+	)
+}
+
+// requestsAllPoliciesFor returns the requests to reconcile all the policies which may select the given Elasticsearch
+// cluster: the policies of its namespace and of the operator namespace.
+func requestsAllPoliciesFor(c k8s.Client, operatorNamespace string) handler.MapFunc {
+	return func(object client.Object) []reconcile.Request {
+		var requests []reconcile.Request
+		for _, ns := range policyNamespaces(object.GetNamespace(), operatorNamespace) {
+			var policies policyv1alpha1.StackConfigPolicyList
+			if err := c.List(context.Background(), &policies, client.InNamespace(ns)); err != nil {
+				ulog.Log.Error(err, "Failed to list StackConfigPolicies", "namespace", ns)
+				continue
+			}
+			for _, policy := range policies.Items {
+				requests = append(requests, reconcile.Request{NamespacedName: k8s.ExtractNamespacedName(&policy)})
+			}
+		}
+		return requests
+	}
+}
+
+// policyNamespaces returns the namespaces of the policies which may select a resource of the given namespace.
+func policyNamespaces(namespace string, operatorNamespace string) []string {
+	if namespace == operatorNamespace {
+		return []string{namespace}
+	}
+	return []string{namespace, operatorNamespace}
+}
+
+var _ reconcile.Reconciler = &ReconcileStackConfigPolicy{}
+
+// ReconcileStackConfigPolicy reconciles a StackConfigPolicy object
+type ReconcileStackConfigPolicy struct {
+	k8s.Client
+	operator.Parameters
+	esClientProvider EsClientProvider
+	recorder         record.EventRecorder
+	licenseChecker   license.Checker
+	// iteration is the number of times this controller has run its Reconcile method
+	iteration uint64
+}
+
+// Reconcile applies the configuration of a StackConfigPolicy to the Elasticsearch clusters it selects, and resets the
+// configuration of the clusters it does not select anymore.
+func (r *ReconcileStackConfigPolicy) Reconcile(ctx context.Context, request reconcile.Request) (reconcile.Result, error) {
+	ctx = common.NewReconciliationContext(ctx, &r.iteration, r.Tracer, controllerName, "policy_name", request)
+	defer common.LogReconciliationRun(ulog.FromContext(ctx))()
+	defer tracing.EndContextTransaction(ctx)
+
+	var policy policyv1alpha1.StackConfigPolicy
+	if err := r.Client.Get(ctx, request.NamespacedName, &policy); err != nil {
+		if apierrors.IsNotFound(err) {
+			return r.onDelete(ctx, request.NamespacedName).Aggregate()
+		}
+		return reconcile.Result{}, tracing.CaptureError(ctx, err)
+	}
+
+	if common.IsUnmanaged(ctx, &policy) {
+		ulog.FromContext(ctx).Info("Object is currently not managed by this controller. Skipping reconciliation", "namespace", policy.Namespace, "policy_name", policy.Name)
+		return reconcile.Result{}, nil
+	}
+
+	// do not reconcile resources last managed by a newer operator
+	if managed, err := common.ReconcileOperatorVersion(ctx, r.Client, &policy); err != nil || !managed {
+		return reconcile.Result{}, tracing.CaptureError(ctx, err)
+	}
+
+	if policy.IsMarkedForDeletion() {
+		return r.onDelete(ctx, request.NamespacedName).Aggregate()
+	}
+
+	results, status := r.doReconcile(ctx, policy)
+	if err := r.updateStatus(ctx, policy, status); err != nil {
+		if apierrors.IsConflict(err) {
+			return results.WithResult(reconcile.Result{Requeue: true}).Aggregate()
+		}
+		results.WithError(err)
+	}
+	return results.Aggregate()
+}
+
+func (r *ReconcileStackConfigPolicy) doReconcile(
+	ctx context.Context,
+	policy policyv1alpha1.StackConfigPolicy,
+) (*reconciler.Results, policyv1alpha1.StackConfigPolicyStatus) {
+	log := ulog.FromContext(ctx)
+	results := reconciler.NewResult(ctx)
+	status := policyv1alpha1.NewStatus(policy)
+
+	enabled, err := r.licenseChecker.EnterpriseFeaturesEnabled(ctx)
+	if err != nil {
+		return results.WithError(err), status
+	}
+	if !enabled {
+		msg := "StackConfigPolicy is an enterprise feature. Enterprise features are disabled"
+		log.Info(msg, "namespace", policy.Namespace, "policy_name", policy.Name)
+		r.recorder.Eventf(&policy, corev1.EventTypeWarning, events.EventReconciliationError, msg)
+		// we don't have a good way of watching for the license level to change so just requeue with a reasonably long delay
+		return results.WithResult(reconcile.Result{Requeue: true, RequeueAfter: 5 * time.Minute}), status
+	}
+
+	declared, err := elasticsearchResources(policy.Spec.Elasticsearch)
+	if err == nil {
+		_, err = selectorOf(policy)
+	}
+	if err != nil {
+		log.Error(err, "Invalid StackConfigPolicy", "namespace", policy.Namespace, "policy_name", policy.Name)
+		k8s.EmitErrorEvent(r.recorder, err, &policy, events.EventReasonValidation, "Invalid StackConfigPolicy: %v", err)
+		status.Phase = policyv1alpha1.InvalidPhase
+		// the policy is reconciled again once its specification is updated
+		return results, status
+	}
+
+	clusters, err := r.selectedClusters(ctx, policy)
+	if err != nil {
+		return results.WithError(err), status
+	}
+	selected := make(map[types.NamespacedName]bool, len(clusters))
+	for _, es := range clusters {
+		esNsn := k8s.ExtractNamespacedName(&es)
+		selected[esNsn] = true
+		resourceStatus := r.reconcileElasticsearch(ctx, policy, es, declared)
+		status.AddResourceStatus(esNsn, resourceStatus)
+		switch resourceStatus.Phase {
+		case policyv1alpha1.ApplyingChangesPhase, policyv1alpha1.ErrorPhase:
+			results.WithResult(defaultRequeue)
+		}
+	}
+
+	// reset the configuration of the clusters which are not selected anymore
+	return results.WithResults(r.resetUnselected(ctx, k8s.ExtractNamespacedName(&policy), selected)), status
+}
+
+func (r *ReconcileStackConfigPolicy) updateStatus(
+	ctx context.Context,
+	policy policyv1alpha1.StackConfigPolicy,
+	status policyv1alpha1.StackConfigPolicyStatus,
+) error {
+	if reflect.DeepEqual(status, policy.Status) {
+		return nil // nothing to do
+	}
+	ulog.FromContext(ctx).V(1).Info("Updating status",
+		"iteration", atomic.LoadUint64(&r.iteration),
+		"namespace", policy.Namespace,
+		"policy_name", policy.Name,
+		"status", status,
+	)
+	policy.Status = status
+	return common.UpdateStatus(ctx, r.Client, &policy)
+}
+
+// onDelete resets the configuration of the clusters to which the deleted policy was applied.
+func (r *ReconcileStackConfigPolicy) onDelete(ctx context.Context, policy types.NamespacedName) *reconciler.Results {
+	return r.resetUnselected(ctx, policy, nil)
+}
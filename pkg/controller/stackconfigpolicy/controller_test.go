@@ -0,0 +1,171 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package stackconfigpolicy
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	commonv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/common/v1"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/apis/common/v1alpha1"
+	esv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/elasticsearch/v1"
+	policyv1alpha1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/stackconfigpolicy/v1alpha1"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/dryrun"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/license"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/operator"
+	controllerscheme "github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/scheme"
+	esclient "github.com/elastic/cloud-on-k8s/v2/pkg/controller/elasticsearch/client"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/utils/k8s"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/utils/net"
+)
+
+type fakeESClient struct {
+	esclient.Client
+	ilmPolicies map[string]map[string]interface{}
+}
+
+func (f *fakeESClient) GetILMPolicies(_ context.Context) (esclient.ILMPolicies, error) {
+	policies := make(esclient.ILMPolicies, len(f.ilmPolicies))
+	for name := range f.ilmPolicies {
+		policies[name] = esclient.ILMPolicyDefinition{}
+	}
+	return policies, nil
+}
+
+func (f *fakeESClient) UpdateILMPolicy(_ context.Context, name string, policy map[string]interface{}) error {
+	f.ilmPolicies[name] = policy
+	return nil
+}
+
+func (f *fakeESClient) DeleteILMPolicy(_ context.Context, name string) error {
+	delete(f.ilmPolicies, name)
+	return nil
+}
+
+func (f *fakeESClient) Close() {}
+
+func reachableES(namespace, name string, labels map[string]string) *esv1.Elasticsearch {
+	return &esv1.Elasticsearch{
+		ObjectMeta: metav1.ObjectMeta{Namespace: namespace, Name: name, Labels: labels},
+		Status: esv1.ElasticsearchStatus{Conditions: v1alpha1.Conditions{
+			{Type: esv1.ElasticsearchIsReachable, Status: corev1.ConditionTrue},
+		}},
+	}
+}
+
+func testPolicy(namespace string, selector map[string]string) *policyv1alpha1.StackConfigPolicy {
+	return &policyv1alpha1.StackConfigPolicy{
+		ObjectMeta: metav1.ObjectMeta{Namespace: namespace, Name: "policy", Generation: 1},
+		Spec: policyv1alpha1.StackConfigPolicySpec{
+			ResourceSelector: metav1.LabelSelector{MatchLabels: selector},
+			Elasticsearch: policyv1alpha1.ElasticsearchConfigPolicySpec{
+				IndexLifecyclePolicies: &commonv1.Config{Data: map[string]interface{}{
+					"logs": map[string]interface{}{"phases": map[string]interface{}{}},
+				}},
+			},
+		},
+	}
+}
+
+func TestReconcileStackConfigPolicy_Reconcile(t *testing.T) {
+	controllerscheme.SetupScheme()
+	prod := reachableES("ns", "prod", map[string]string{"env": "prod"})
+	dev := reachableES("ns", "dev", map[string]string{"env": "dev"})
+	policy := testPolicy("ns", map[string]string{"env": "prod"})
+	c := k8s.NewFakeClient(prod, dev, policy)
+	esClients := map[string]*fakeESClient{
+		"prod": {ilmPolicies: map[string]map[string]interface{}{}},
+		"dev":  {ilmPolicies: map[string]map[string]interface{}{}},
+	}
+	r := &ReconcileStackConfigPolicy{
+		Client: c,
+		esClientProvider: func(_ context.Context, _ k8s.Client, _ net.Dialer, _ *dryrun.Mode, es esv1.Elasticsearch) (esclient.Client, error) {
+			return esClients[es.Name], nil
+		},
+		recorder:       record.NewFakeRecorder(10),
+		licenseChecker: license.MockLicenseChecker{EnterpriseEnabled: true},
+		Parameters:     operator.Parameters{OperatorNamespace: "elastic-system"},
+	}
+	policyNsn := k8s.ExtractNamespacedName(policy)
+	reconcilePolicy := func() policyv1alpha1.StackConfigPolicyStatus {
+		t.Helper()
+		_, err := r.Reconcile(context.Background(), reconcile.Request{NamespacedName: policyNsn})
+		require.NoError(t, err)
+		var updated policyv1alpha1.StackConfigPolicy
+		err = c.Get(context.Background(), policyNsn, &updated)
+		if apierrors.IsNotFound(err) {
+			return policyv1alpha1.StackConfigPolicyStatus{}
+		}
+		require.NoError(t, err)
+		return updated.Status
+	}
+	trackingSecretExists := func(esName string) bool {
+		t.Helper()
+		err := c.Get(context.Background(), types.NamespacedName{Namespace: "ns", Name: esv1.StackConfigPolicySecret(esName)}, &corev1.Secret{})
+		return err == nil
+	}
+
+	// the policy is applied to the selected cluster only
+	status := reconcilePolicy()
+	require.Equal(t, policyv1alpha1.ReadyPhase, status.Phase)
+	require.Equal(t, "1/1", status.ReadyCount)
+	require.Contains(t, esClients["prod"].ilmPolicies, "logs")
+	require.Empty(t, esClients["dev"].ilmPolicies)
+	require.True(t, trackingSecretExists("prod"))
+
+	// the policy is also applied to a cluster once selected, and reset on a cluster once unselected
+	prod.Labels["env"] = "staging"
+	require.NoError(t, c.Update(context.Background(), prod))
+	dev.Labels["env"] = "prod"
+	require.NoError(t, c.Update(context.Background(), dev))
+	status = reconcilePolicy()
+	require.Equal(t, "1/1", status.ReadyCount)
+	require.Contains(t, status.ResourcesStatuses, "ns/dev")
+	require.Empty(t, esClients["prod"].ilmPolicies)
+	require.False(t, trackingSecretExists("prod"))
+	require.Contains(t, esClients["dev"].ilmPolicies, "logs")
+
+	// another policy selecting the same cluster conflicts with the policy
+	other := testPolicy("elastic-system", nil)
+	require.NoError(t, c.Create(context.Background(), other))
+	status = reconcilePolicy()
+	require.Equal(t, policyv1alpha1.ConflictPhase, status.Phase)
+	require.Equal(t, 1, status.Errors)
+	require.NoError(t, c.Delete(context.Background(), other))
+
+	// the policy is reset once deleted
+	require.NoError(t, c.Delete(context.Background(), policy))
+	reconcilePolicy()
+	require.Empty(t, esClients["dev"].ilmPolicies)
+	require.False(t, trackingSecretExists("dev"))
+}
+
+func TestReconcileStackConfigPolicy_invalidPolicy(t *testing.T) {
+	controllerscheme.SetupScheme()
+	policy := testPolicy("ns", nil)
+	policy.Spec.Elasticsearch.IngestPipelines = &commonv1.Config{Data: map[string]interface{}{"pipeline": "invalid"}}
+	c := k8s.NewFakeClient(reachableES("ns", "es", nil), policy)
+	recorder := record.NewFakeRecorder(10)
+	r := &ReconcileStackConfigPolicy{
+		Client:         c,
+		recorder:       recorder,
+		licenseChecker: license.MockLicenseChecker{EnterpriseEnabled: true},
+	}
+	res, err := r.Reconcile(context.Background(), reconcile.Request{NamespacedName: k8s.ExtractNamespacedName(policy)})
+	require.NoError(t, err)
+	require.Equal(t, reconcile.Result{}, res)
+	var updated policyv1alpha1.StackConfigPolicy
+	require.NoError(t, c.Get(context.Background(), k8s.ExtractNamespacedName(policy), &updated))
+	require.Equal(t, policyv1alpha1.InvalidPhase, updated.Status.Phase)
+	require.Len(t, recorder.Events, 1)
+}
@@ -0,0 +1,250 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package stackconfigpolicy
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	commonv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/common/v1"
+	esv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/elasticsearch/v1"
+	policyv1alpha1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/stackconfigpolicy/v1alpha1"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/dryrun"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/events"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/reconciler"
+	esclient "github.com/elastic/cloud-on-k8s/v2/pkg/controller/elasticsearch/client"
+	eslabel "github.com/elastic/cloud-on-k8s/v2/pkg/controller/elasticsearch/label"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/elasticsearch/stackconfig"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/utils/k8s"
+	ulog "github.com/elastic/cloud-on-k8s/v2/pkg/utils/log"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/utils/net"
+)
+
+// EsClientProvider returns a client to apply the policies to an Elasticsearch cluster.
+type EsClientProvider func(ctx context.Context, c k8s.Client, dialer net.Dialer, dryRun *dryrun.Mode, es esv1.Elasticsearch) (esclient.Client, error)
+
+// elasticsearchResources returns the resources declared in the given policy specification, indexed by kind.
+func elasticsearchResources(spec policyv1alpha1.ElasticsearchConfigPolicySpec) (stackconfig.Resources, error) {
+	resources := stackconfig.Resources{}
+	settings, err := stackconfig.ClusterSettings(spec.ClusterSettings)
+	if err != nil {
+		return nil, fmt.Errorf("invalid %s: %w", stackconfig.ClusterSettingsKind, err)
+	}
+	resources[stackconfig.ClusterSettingsKind] = settings
+	for _, named := range []struct {
+		kind stackconfig.Kind
+		cfg  *commonv1.Config
+	}{
+		{kind: stackconfig.SnapshotRepositoriesKind, cfg: spec.SnapshotRepositories},
+		{kind: stackconfig.RoleMappingsKind, cfg: spec.SecurityRoleMappings},
+		{kind: stackconfig.ILMPoliciesKind, cfg: spec.IndexLifecyclePolicies},
+		{kind: stackconfig.IngestPipelinesKind, cfg: spec.IngestPipelines},
+		{kind: stackconfig.ComponentTemplatesKind, cfg: spec.IndexTemplates.ComponentTemplates},
+		{kind: stackconfig.IndexTemplatesKind, cfg: spec.IndexTemplates.ComposableIndexTemplates},
+	} {
+		definitions, err := stackconfig.NamedResources(named.cfg)
+		if err != nil {
+			return nil, fmt.Errorf("invalid %s: %w", named.kind, err)
+		}
+		resources[named.kind] = definitions
+	}
+	return resources, nil
+}
+
+// selectorOf returns the label selector of the resources selected by the given policy.
+func selectorOf(policy policyv1alpha1.StackConfigPolicy) (labels.Selector, error) {
+	selector, err := metav1.LabelSelectorAsSelector(&policy.Spec.ResourceSelector)
+	if err != nil {
+		return nil, fmt.Errorf("invalid resourceSelector: %w", err)
+	}
+	return selector, nil
+}
+
+// selectedClusters returns the Elasticsearch clusters selected by the given policy: the clusters of the namespace of
+// the policy matching its selector, or of all the managed namespaces if the policy is in the operator namespace.
+func (r *ReconcileStackConfigPolicy) selectedClusters(ctx context.Context, policy policyv1alpha1.StackConfigPolicy) ([]esv1.Elasticsearch, error) {
+	selector, err := selectorOf(policy)
+	if err != nil {
+		return nil, err
+	}
+	opts := []client.ListOption{client.MatchingLabelsSelector{Selector: selector}}
+	if policy.Namespace != r.OperatorNamespace {
+		opts = append(opts, client.InNamespace(policy.Namespace))
+	}
+	var esList esv1.ElasticsearchList
+	if err := r.Client.List(ctx, &esList, opts...); err != nil {
+		return nil, err
+	}
+	clusters := make([]esv1.Elasticsearch, 0, len(esList.Items))
+	for _, es := range esList.Items {
+		if es.IsMarkedForDeletion() {
+			continue
+		}
+		clusters = append(clusters, es)
+	}
+	return clusters, nil
+}
+
+// conflictingPolicies returns the other policies selecting the given Elasticsearch cluster.
+func (r *ReconcileStackConfigPolicy) conflictingPolicies(
+	ctx context.Context,
+	policy policyv1alpha1.StackConfigPolicy,
+	es esv1.Elasticsearch,
+) ([]string, error) {
+	var conflicting []string
+	for _, ns := range policyNamespaces(es.Namespace, r.OperatorNamespace) {
+		var policies policyv1alpha1.StackConfigPolicyList
+		if err := r.Client.List(ctx, &policies, client.InNamespace(ns)); err != nil {
+			return nil, err
+		}
+		for _, other := range policies.Items {
+			if other.Namespace == policy.Namespace && other.Name == policy.Name {
+				continue
+			}
+			if other.IsMarkedForDeletion() {
+				continue
+			}
+			selector, err := selectorOf(other)
+			if err != nil {
+				// invalid policies are not applied
+				continue
+			}
+			if selector.Matches(labels.Set(es.Labels)) {
+				conflicting = append(conflicting, k8s.ExtractNamespacedName(&other).String())
+			}
+		}
+	}
+	sort.Strings(conflicting)
+	return conflicting, nil
+}
+
+// reconcileElasticsearch applies the declared resources to the given Elasticsearch cluster, and returns the status of
+// the application of the policy to that cluster.
+func (r *ReconcileStackConfigPolicy) reconcileElasticsearch(
+	ctx context.Context,
+	policy policyv1alpha1.StackConfigPolicy,
+	es esv1.Elasticsearch,
+	declared stackconfig.Resources,
+) policyv1alpha1.ResourcePolicyStatus {
+	conflicting, err := r.conflictingPolicies(ctx, policy, es)
+	if err != nil {
+		return policyv1alpha1.ResourcePolicyStatus{Phase: policyv1alpha1.ErrorPhase, Error: err.Error()}
+	}
+	if len(conflicting) > 0 {
+		msg := fmt.Sprintf("Elasticsearch cluster %s/%s is also selected by %s", es.Namespace, es.Name, strings.Join(conflicting, ", "))
+		r.recorder.Event(&policy, corev1.EventTypeWarning, events.EventReasonConflict, msg)
+		return policyv1alpha1.ResourcePolicyStatus{Phase: policyv1alpha1.ConflictPhase, Error: msg}
+	}
+	if !isReachable(es) {
+		// retry once the cluster is reachable
+		return policyv1alpha1.ResourcePolicyStatus{Phase: policyv1alpha1.ApplyingChangesPhase}
+	}
+	conflicts, err := r.apply(ctx, es, k8s.ExtractNamespacedName(&policy), declared)
+	if err != nil {
+		ulog.FromContext(ctx).Error(err, "Failed to apply StackConfigPolicy",
+			"namespace", policy.Namespace, "policy_name", policy.Name, "es_namespace", es.Namespace, "es_name", es.Name)
+		r.recorder.Eventf(&policy, corev1.EventTypeWarning, events.EventReconciliationError,
+			"Failed to apply policy to Elasticsearch cluster %s/%s: %v", es.Namespace, es.Name, err)
+		return policyv1alpha1.ResourcePolicyStatus{Phase: policyv1alpha1.ErrorPhase, Error: err.Error()}
+	}
+	if len(conflicts) > 0 {
+		names := make([]string, 0, len(conflicts))
+		for _, conflict := range conflicts {
+			names = append(names, conflict.String())
+		}
+		msg := fmt.Sprintf("Elasticsearch resources %s already exist in cluster %s/%s and are not managed by the policy",
+			strings.Join(names, ", "), es.Namespace, es.Name)
+		r.recorder.Event(&policy, corev1.EventTypeWarning, events.EventReasonConflict, msg)
+		return policyv1alpha1.ResourcePolicyStatus{Phase: policyv1alpha1.ConflictPhase, Error: msg}
+	}
+	return policyv1alpha1.ResourcePolicyStatus{Phase: policyv1alpha1.ReadyPhase}
+}
+
+// apply applies the declared resources to the given Elasticsearch cluster, deletes the resources previously applied
+// by a policy which are not declared anymore, and tracks the applied resources in a Secret labeled with the policy.
+// It returns the declared resources which conflict with resources not applied by a policy.
+func (r *ReconcileStackConfigPolicy) apply(
+	ctx context.Context,
+	es esv1.Elasticsearch,
+	policy types.NamespacedName,
+	declared stackconfig.Resources,
+) ([]stackconfig.Conflict, error) {
+	key := types.NamespacedName{Namespace: es.Namespace, Name: esv1.StackConfigPolicySecret(es.Name)}
+	applied, err := stackconfig.GetApplied(ctx, r.Client, key)
+	if err != nil {
+		return nil, err
+	}
+	esClient, err := r.esClientProvider(ctx, r.Client, r.Dialer, r.DryRun, es)
+	if err != nil {
+		return nil, err
+	}
+	defer esClient.Close()
+	applied, conflicts, applyErr := stackconfig.Apply(ctx, esClient, k8s.ExtractNamespacedName(&es), declared, applied)
+	policyLabels := map[string]string{PolicyNameLabelName: policy.Name, PolicyNamespaceLabelName: policy.Namespace}
+	if err := stackconfig.ReconcileApplied(ctx, r.Client, es, key, policyLabels, applied); err != nil {
+		return conflicts, err
+	}
+	return conflicts, applyErr
+}
+
+// resetUnselected deletes the resources applied by the given policy to the Elasticsearch clusters which are not
+// selected anymore.
+func (r *ReconcileStackConfigPolicy) resetUnselected(
+	ctx context.Context,
+	policy types.NamespacedName,
+	selected map[types.NamespacedName]bool,
+) *reconciler.Results {
+	results := reconciler.NewResult(ctx)
+	opts := []client.ListOption{client.MatchingLabels{PolicyNameLabelName: policy.Name, PolicyNamespaceLabelName: policy.Namespace}}
+	if policy.Namespace != r.OperatorNamespace {
+		opts = append(opts, client.InNamespace(policy.Namespace))
+	}
+	var secrets corev1.SecretList
+	if err := r.Client.List(ctx, &secrets, opts...); err != nil {
+		return results.WithError(err)
+	}
+	for _, secret := range secrets.Items {
+		esNsn := types.NamespacedName{Namespace: secret.Namespace, Name: secret.Labels[eslabel.ClusterNameLabelName]}
+		if selected[esNsn] {
+			continue
+		}
+		var es esv1.Elasticsearch
+		err := r.Client.Get(ctx, esNsn, &es)
+		if apierrors.IsNotFound(err) || (err == nil && es.IsMarkedForDeletion()) {
+			// nothing to reset, the Secret is garbage collected with the cluster
+			continue
+		}
+		if err != nil {
+			results.WithError(err)
+			continue
+		}
+		if !isReachable(es) {
+			results.WithResult(defaultRequeue)
+			continue
+		}
+		ulog.FromContext(ctx).Info("Resetting the resources applied by StackConfigPolicy",
+			"namespace", policy.Namespace, "policy_name", policy.Name, "es_namespace", es.Namespace, "es_name", es.Name)
+		if _, err := r.apply(ctx, es, policy, nil); err != nil {
+			results.WithError(err)
+		}
+	}
+	return results
+}
+
+// isReachable returns true if the operator could reach the Elasticsearch API of the given cluster during its last
+// reconciliation.
+func isReachable(es esv1.Elasticsearch) bool {
+	idx := es.Status.Conditions.Index(esv1.ElasticsearchIsReachable)
+	return idx >= 0 && es.Status.Conditions[idx].Status == corev1.ConditionTrue
+}
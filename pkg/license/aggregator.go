@@ -55,15 +55,36 @@ func (a Aggregator) AggregateMemory(ctx context.Context) (resource.Quantity, err
 	return totalMemory, nil
 }
 
-func (a Aggregator) aggregateElasticsearchMemory(ctx context.Context) (resource.Quantity, error) {
+// ElasticsearchUsage summarizes the Elasticsearch clusters managed by the operator
+type ElasticsearchUsage struct {
+	// Clusters is the number of Elasticsearch clusters.
+	Clusters int64
+	// Nodes is the number of Elasticsearch nodes declared in the specification of all clusters.
+	Nodes int64
+	// Memory is the total memory of all Elasticsearch nodes.
+	Memory resource.Quantity
+	// NodesByVersion is the number of Elasticsearch nodes per version.
+	NodesByVersion map[string]int64
+	// ClustersByLicenseLevel is the number of Elasticsearch clusters per license level.
+	ClustersByLicenseLevel map[string]int64
+}
+
+// AggregateElasticsearch aggregates the memory, the nodes, the versions and the license levels of all Elasticsearch
+// clusters
+func (a Aggregator) AggregateElasticsearch(ctx context.Context) (ElasticsearchUsage, error) {
 	var esList esv1.ElasticsearchList
 	err := a.client.List(context.Background(), &esList)
 	if err != nil {
-		return resource.Quantity{}, errors.Wrap(err, "failed to aggregate Elasticsearch memory")
+		return ElasticsearchUsage{}, errors.Wrap(err, "failed to aggregate Elasticsearch usage")
 	}
 
-	var total resource.Quantity
+	usage := ElasticsearchUsage{
+		NodesByVersion:         map[string]int64{},
+		ClustersByLicenseLevel: map[string]int64{},
+	}
 	for _, es := range esList.Items {
+		usage.Clusters++
+		usage.ClustersByLicenseLevel[esLicenseLevel(es)]++
 		for _, nodeSet := range es.Spec.NodeSets {
 			mem, err := containerMemLimits(
 				nodeSet.PodTemplate.Spec.Containers,
@@ -72,16 +93,35 @@ func (a Aggregator) aggregateElasticsearchMemory(ctx context.Context) (resource.
 				nodespec.DefaultMemoryLimits,
 			)
 			if err != nil {
-				return resource.Quantity{}, errors.Wrap(err, "failed to aggregate Elasticsearch memory")
+				return ElasticsearchUsage{}, errors.Wrap(err, "failed to aggregate Elasticsearch memory")
 			}
 
-			total.Add(multiply(mem, nodeSet.Count))
+			usage.Memory.Add(multiply(mem, nodeSet.Count))
+			usage.Nodes += int64(nodeSet.Count)
+			usage.NodesByVersion[es.Spec.Version] += int64(nodeSet.Count)
 			ulog.FromContext(ctx).V(1).Info("Collecting", "namespace", es.Namespace, "es_name", es.Name,
 				"memory", mem.String(), "count", nodeSet.Count)
 		}
 	}
 
-	return total, nil
+	return usage, nil
+}
+
+func (a Aggregator) aggregateElasticsearchMemory(ctx context.Context) (resource.Quantity, error) {
+	usage, err := a.AggregateElasticsearch(ctx)
+	if err != nil {
+		return resource.Quantity{}, err
+	}
+	return usage.Memory, nil
+}
+
+// esLicenseLevel returns the level of the license reported in the status of the given Elasticsearch cluster,
+// or unknownLicenseLevel if it has not been reported yet.
+func esLicenseLevel(es esv1.Elasticsearch) string {
+	if es.Status.License == nil || es.Status.License.Type == "" {
+		return unknownLicenseLevel
+	}
+	return es.Status.License.Type
 }
 
 func (a Aggregator) aggregateEnterpriseSearchMemory(ctx context.Context) (resource.Quantity, error) {
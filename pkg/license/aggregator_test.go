@@ -143,6 +143,19 @@ func TestAggregator(t *testing.T) {
 	require.Equal(t, 325.9073486328125, inGiB(val))
 }
 
+func TestAggregator_AggregateElasticsearch(t *testing.T) {
+	objects := readObjects(t, "testdata/stack.yaml")
+	client := k8s.NewFakeClient(objects...)
+	aggregator := Aggregator{client: client}
+
+	usage, err := aggregator.AggregateElasticsearch(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, int64(1), usage.Clusters)
+	require.Equal(t, int64(9), usage.Nodes)
+	require.Equal(t, map[string]int64{"7.9.0": 9}, usage.NodesByVersion)
+	require.Equal(t, map[string]int64{unknownLicenseLevel: 1}, usage.ClustersByLicenseLevel)
+}
+
 func readObjects(t *testing.T, filePath string) []runtime.Object {
 	t.Helper()
 
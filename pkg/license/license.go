@@ -6,6 +6,7 @@ package license
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"math"
 	"reflect"
@@ -31,6 +32,8 @@ import (
 const (
 	// defaultOperatorLicenseLevel is the default license level when no operator license is installed
 	defaultOperatorLicenseLevel = "basic"
+	// unknownLicenseLevel is the license level of Elasticsearch clusters that have not reported their license yet
+	unknownLicenseLevel = "unknown"
 	// LicensingCfgMapName is the name of the config map used to store licensing information
 	LicensingCfgMapName = "elastic-licensing"
 	// Type represents the Elastic usage type used to mark the config map that stores licensing information
@@ -49,6 +52,8 @@ type LicensingInfo struct {
 	TotalManagedMemoryBytes    int64
 	MaxEnterpriseResourceUnits int64
 	EnterpriseResourceUnits    int64
+	// Elasticsearch summarizes the Elasticsearch clusters, only reported if there is at least one cluster.
+	Elasticsearch *ElasticsearchUsage
 }
 
 // toMap transforms a LicensingInfo to a map of string, in order to fill in the data of a config map
@@ -69,9 +74,28 @@ func (li LicensingInfo) toMap() map[string]string {
 		m["eck_license_expiry_date"] = li.EckLicenseExpiryDate.Format(time.RFC3339)
 	}
 
+	if es := li.Elasticsearch; es != nil && es.Clusters > 0 {
+		m["elasticsearch_clusters"] = strconv.FormatInt(es.Clusters, 10)
+		m["elasticsearch_nodes"] = strconv.FormatInt(es.Nodes, 10)
+		m["elasticsearch_memory"] = fmt.Sprintf("%0.2fGiB", inGiB(es.Memory))
+		m["elasticsearch_memory_bytes"] = fmt.Sprintf("%d", es.Memory.Value())
+		m["elasticsearch_versions"] = toJSON(es.NodesByVersion)
+		m["elasticsearch_license_levels"] = toJSON(es.ClustersByLicenseLevel)
+	}
+
 	return m
 }
 
+// toJSON serializes a map of counters to JSON, keys are sorted to keep the config map stable.
+func toJSON(counters map[string]int64) string {
+	bytes, err := json.Marshal(counters)
+	if err != nil {
+		// cannot happen with a map of string to int64
+		return "{}"
+	}
+	return string(bytes)
+}
+
 func (li LicensingInfo) ReportAsMetrics() {
 	labels := prometheus.Labels{metrics.LicenseLevelLabel: li.EckLicenseLevel}
 	metrics.LicensingTotalMemoryGauge.With(labels).Set(li.TotalManagedMemoryGiB)
@@ -80,6 +104,26 @@ func (li LicensingInfo) ReportAsMetrics() {
 	if li.MaxEnterpriseResourceUnits > 0 {
 		metrics.LicensingMaxERUGauge.With(labels).Set(float64(li.MaxEnterpriseResourceUnits))
 	}
+
+	// only report the expiry of the current operator license
+	metrics.LicensingExpiryGauge.Reset()
+	if li.EckLicenseExpiryDate != nil {
+		metrics.LicensingExpiryGauge.With(labels).Set(float64(li.EckLicenseExpiryDate.Unix()))
+	}
+
+	// reset the Elasticsearch breakdowns to not report versions or license levels that are no longer in use
+	metrics.ElasticsearchNodesGauge.Reset()
+	metrics.ElasticsearchClustersGauge.Reset()
+	if li.Elasticsearch == nil {
+		return
+	}
+	metrics.ElasticsearchMemoryGauge.With(labels).Set(inGiB(li.Elasticsearch.Memory))
+	for version, nodes := range li.Elasticsearch.NodesByVersion {
+		metrics.ElasticsearchNodesGauge.With(prometheus.Labels{metrics.VersionLabel: version}).Set(float64(nodes))
+	}
+	for level, clusters := range li.Elasticsearch.ClustersByLicenseLevel {
+		metrics.ElasticsearchClustersGauge.With(prometheus.Labels{metrics.LicenseLevelLabel: level}).Set(float64(clusters))
+	}
 }
 
 // LicensingResolver resolves the licensing information of the operator
@@ -88,8 +132,9 @@ type LicensingResolver struct {
 	client     k8s.Client
 }
 
-// ToInfo returns licensing information given the total memory of all Elastic managed components
-func (r LicensingResolver) ToInfo(ctx context.Context, totalMemory resource.Quantity) (LicensingInfo, error) {
+// ToInfo returns licensing information given the total memory of all Elastic managed components and the summary of
+// the Elasticsearch clusters
+func (r LicensingResolver) ToInfo(ctx context.Context, totalMemory resource.Quantity, esUsage ElasticsearchUsage) (LicensingInfo, error) {
 	operatorLicense, err := r.getOperatorLicense(ctx)
 	if err != nil {
 		return LicensingInfo{}, err
@@ -102,6 +147,7 @@ func (r LicensingResolver) ToInfo(ctx context.Context, totalMemory resource.Quan
 		TotalManagedMemoryGiB:   inGiB(totalMemory),
 		TotalManagedMemoryBytes: totalMemory.Value(),
 		EnterpriseResourceUnits: inEnterpriseResourceUnits(totalMemory),
+		Elasticsearch:           &esUsage,
 	}
 
 	// include the max ERUs only for a non trial/basic license
@@ -9,6 +9,7 @@ import (
 	"time"
 
 	"github.com/stretchr/testify/assert"
+	"k8s.io/apimachinery/pkg/api/resource"
 
 	commonlicense "github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/license"
 )
@@ -38,6 +39,13 @@ func TestToMap(t *testing.T) {
 			TotalManagedMemoryBytes:    68719476736,
 			EnterpriseResourceUnits:    1,
 			MaxEnterpriseResourceUnits: 10,
+			Elasticsearch: &ElasticsearchUsage{
+				Clusters:               2,
+				Nodes:                  5,
+				Memory:                 resource.MustParse("10Gi"),
+				NodesByVersion:         map[string]int64{"8.5.0": 3, "7.17.7": 2},
+				ClustersByLicenseLevel: map[string]int64{"enterprise": 1, "basic": 1},
+			},
 		}
 
 		have := i.toMap()
@@ -49,6 +57,12 @@ func TestToMap(t *testing.T) {
 			"total_managed_memory_bytes":    "68719476736",
 			"enterprise_resource_units":     "1",
 			"max_enterprise_resource_units": "10",
+			"elasticsearch_clusters":        "2",
+			"elasticsearch_nodes":           "5",
+			"elasticsearch_memory":          "10.00GiB",
+			"elasticsearch_memory_bytes":    "10737418240",
+			"elasticsearch_versions":        `{"7.17.7":2,"8.5.0":3}`,
+			"elasticsearch_license_levels":  `{"basic":1,"enterprise":1}`,
 		}
 		assert.Equal(t, want, have)
 	})
@@ -9,8 +9,13 @@ import (
 	"time"
 
 	"go.elastic.co/apm/v2"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/events"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/license"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/tracing"
 	ulog "github.com/elastic/cloud-on-k8s/v2/pkg/utils/log"
 )
@@ -24,10 +29,20 @@ type ResourceReporter struct {
 	aggregator        Aggregator
 	licensingResolver LicensingResolver
 	tracer            *apm.Tracer
+	// recorder records the events warning about the expiry of the operator license, no event is recorded if nil
+	recorder record.EventRecorder
+	// expiryWarningPeriod is the period before the expiry of the operator license during which events are recorded
+	expiryWarningPeriod time.Duration
 }
 
 // NewResourceReporter returns a new ResourceReporter
-func NewResourceReporter(c client.Client, operatorNs string, tracer *apm.Tracer) ResourceReporter {
+func NewResourceReporter(
+	c client.Client,
+	operatorNs string,
+	tracer *apm.Tracer,
+	recorder record.EventRecorder,
+	expiryWarningPeriod time.Duration,
+) ResourceReporter {
 	return ResourceReporter{
 		aggregator: Aggregator{
 			client: c,
@@ -36,7 +51,9 @@ func NewResourceReporter(c client.Client, operatorNs string, tracer *apm.Tracer)
 			client:     c,
 			operatorNs: operatorNs,
 		},
-		tracer: tracer,
+		tracer:              tracer,
+		recorder:            recorder,
+		expiryWarningPeriod: expiryWarningPeriod,
 	}
 }
 
@@ -70,7 +87,37 @@ func (r ResourceReporter) Report(ctx context.Context) error {
 	}
 
 	licensingInfo.ReportAsMetrics()
-	return r.licensingResolver.Save(ctx, licensingInfo)
+	if err := r.licensingResolver.Save(ctx, licensingInfo); err != nil {
+		return err
+	}
+	return r.warnAboutExpiry(ctx, licensingInfo, time.Now())
+}
+
+// warnAboutExpiry records a Warning event on the licensing config map if the operator license expires within the
+// expiry warning period. Trial licenses are not reported, as they are short-lived by design.
+func (r ResourceReporter) warnAboutExpiry(ctx context.Context, info LicensingInfo, now time.Time) error {
+	if r.recorder == nil || info.EckLicenseExpiryDate == nil || isTrial(info.EckLicenseLevel) ||
+		!license.ExpiresWithin(*info.EckLicenseExpiryDate, r.expiryWarningPeriod, now) {
+		return nil
+	}
+	var cm corev1.ConfigMap
+	nsn := types.NamespacedName{Namespace: r.licensingResolver.operatorNs, Name: LicensingCfgMapName}
+	if err := r.licensingResolver.client.Get(ctx, nsn, &cm); err != nil {
+		return err
+	}
+	r.recorder.Eventf(&cm, corev1.EventTypeWarning, events.EventReasonLicenseExpiring,
+		"Operator license of type %s expires on %s", info.EckLicenseLevel, info.EckLicenseExpiryDate.Format(time.RFC3339))
+	return nil
+}
+
+// isTrial returns true if the given operator license level is a trial.
+func isTrial(licenseLevel string) bool {
+	switch license.OperatorLicenseType(licenseLevel) {
+	case license.LicenseTypeEnterpriseTrial, license.LicenseTypeLegacyTrial:
+		return true
+	default:
+		return false
+	}
 }
 
 // Get aggregates managed resources and returns the licensing information
@@ -81,6 +128,10 @@ func (r ResourceReporter) Get(ctx context.Context) (LicensingInfo, error) {
 	if err != nil {
 		return LicensingInfo{}, err
 	}
+	esUsage, err := r.aggregator.AggregateElasticsearch(ctx)
+	if err != nil {
+		return LicensingInfo{}, err
+	}
 
-	return r.licensingResolver.ToInfo(ctx, totalMemory)
+	return r.licensingResolver.ToInfo(ctx, totalMemory, esUsage)
 }
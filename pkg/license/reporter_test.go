@@ -16,6 +16,7 @@ import (
 	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	apmv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/apm/v1"
@@ -32,12 +33,16 @@ func TestGet(t *testing.T) {
 	t.Run("elasticsearch_defaults", func(t *testing.T) {
 		es := esv1.Elasticsearch{
 			Spec: esv1.ElasticsearchSpec{
+				Version: "8.5.0",
 				NodeSets: []esv1.NodeSet{{
 					Count: 10,
 				}},
 			},
+			Status: esv1.ElasticsearchStatus{
+				License: &esv1.LicenseStatus{Type: "basic"},
+			},
 		}
-		have, err := NewResourceReporter(k8s.NewFakeClient(&es), operatorNs, nil).Get(context.Background())
+		have, err := NewResourceReporter(k8s.NewFakeClient(&es), operatorNs, nil, nil, 0).Get(context.Background())
 		require.NoError(t, err)
 
 		want := LicensingInfo{
@@ -45,6 +50,13 @@ func TestGet(t *testing.T) {
 			TotalManagedMemoryBytes: 21474836480,
 			EnterpriseResourceUnits: 1,
 			EckLicenseLevel:         "basic",
+			Elasticsearch: &ElasticsearchUsage{
+				Clusters:               1,
+				Nodes:                  10,
+				Memory:                 resource.MustParse("20Gi"),
+				NodesByVersion:         map[string]int64{"8.5.0": 10},
+				ClustersByLicenseLevel: map[string]int64{"basic": 1},
+			},
 		}
 
 		assertEqual(t, want, have)
@@ -72,7 +84,7 @@ func TestGet(t *testing.T) {
 				}},
 			},
 		}
-		have, err := NewResourceReporter(k8s.NewFakeClient(&es), operatorNs, nil).Get(context.Background())
+		have, err := NewResourceReporter(k8s.NewFakeClient(&es), operatorNs, nil, nil, 0).Get(context.Background())
 		require.NoError(t, err)
 
 		want := LicensingInfo{
@@ -80,6 +92,13 @@ func TestGet(t *testing.T) {
 			TotalManagedMemoryBytes: 343597383680,
 			EnterpriseResourceUnits: 5,
 			EckLicenseLevel:         "basic",
+			Elasticsearch: &ElasticsearchUsage{
+				Clusters:               1,
+				Nodes:                  40,
+				Memory:                 resource.MustParse("320Gi"),
+				NodesByVersion:         map[string]int64{"": 40},
+				ClustersByLicenseLevel: map[string]int64{unknownLicenseLevel: 1},
+			},
 		}
 
 		assertEqual(t, want, have)
@@ -106,7 +125,7 @@ func TestGet(t *testing.T) {
 			},
 		}
 
-		have, err := NewResourceReporter(k8s.NewFakeClient(&es), operatorNs, nil).Get(context.Background())
+		have, err := NewResourceReporter(k8s.NewFakeClient(&es), operatorNs, nil, nil, 0).Get(context.Background())
 		require.NoError(t, err)
 
 		want := LicensingInfo{
@@ -114,6 +133,13 @@ func TestGet(t *testing.T) {
 			TotalManagedMemoryBytes: 223338299392,
 			EnterpriseResourceUnits: 4,
 			EckLicenseLevel:         "basic",
+			Elasticsearch: &ElasticsearchUsage{
+				Clusters:               1,
+				Nodes:                  13,
+				Memory:                 resource.MustParse("208Gi"),
+				NodesByVersion:         map[string]int64{"": 13},
+				ClustersByLicenseLevel: map[string]int64{unknownLicenseLevel: 1},
+			},
 		}
 
 		assertEqual(t, want, have)
@@ -126,7 +152,7 @@ func TestGet(t *testing.T) {
 			},
 		}
 
-		have, err := NewResourceReporter(k8s.NewFakeClient(&kb), operatorNs, nil).Get(context.Background())
+		have, err := NewResourceReporter(k8s.NewFakeClient(&kb), operatorNs, nil, nil, 0).Get(context.Background())
 		require.NoError(t, err)
 
 		want := LicensingInfo{
@@ -160,7 +186,7 @@ func TestGet(t *testing.T) {
 			},
 		}
 
-		have, err := NewResourceReporter(k8s.NewFakeClient(&kb), operatorNs, nil).Get(context.Background())
+		have, err := NewResourceReporter(k8s.NewFakeClient(&kb), operatorNs, nil, nil, 0).Get(context.Background())
 		require.NoError(t, err)
 		want := LicensingInfo{
 			TotalManagedMemoryGiB:   200.00,
@@ -190,7 +216,7 @@ func TestGet(t *testing.T) {
 				},
 			},
 		}
-		have, err := NewResourceReporter(k8s.NewFakeClient(&kb), operatorNs, nil).Get(context.Background())
+		have, err := NewResourceReporter(k8s.NewFakeClient(&kb), operatorNs, nil, nil, 0).Get(context.Background())
 		require.NoError(t, err)
 		want := LicensingInfo{
 			TotalManagedMemoryGiB:   190.73,
@@ -215,6 +241,56 @@ func assertEqual(t *testing.T, want, have LicensingInfo) {
 	require.Equal(t, wantMap, haveMap)
 }
 
+func TestResourceReporter_warnAboutExpiry(t *testing.T) {
+	now := time.Date(2022, 10, 18, 10, 0, 0, 0, time.UTC)
+	cm := corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Namespace: operatorNs, Name: LicensingCfgMapName}}
+	tests := []struct {
+		name       string
+		level      string
+		expiry     *time.Time
+		wantEvents int
+	}{
+		{
+			name: "no operator license",
+		},
+		{
+			name:   "trial license expiring within the warning period",
+			level:  "enterprise_trial",
+			expiry: timePtr(now.Add(29 * 24 * time.Hour)),
+		},
+		{
+			name:   "license expiring after the warning period",
+			expiry: timePtr(now.Add(31 * 24 * time.Hour)),
+		},
+		{
+			name:       "license expiring within the warning period",
+			expiry:     timePtr(now.Add(29 * 24 * time.Hour)),
+			wantEvents: 1,
+		},
+		{
+			name:   "expired license",
+			expiry: timePtr(now.Add(-time.Hour)),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			recorder := record.NewFakeRecorder(10)
+			r := NewResourceReporter(k8s.NewFakeClient(cm.DeepCopy()), operatorNs, nil, recorder, 30*24*time.Hour)
+			level := tt.level
+			if level == "" {
+				level = "enterprise"
+			}
+			info := LicensingInfo{EckLicenseLevel: level, EckLicenseExpiryDate: tt.expiry}
+			require.NoError(t, r.warnAboutExpiry(context.Background(), info, now))
+			require.Len(t, recorder.Events, tt.wantEvents)
+		})
+	}
+}
+
+func timePtr(t time.Time) *time.Time {
+	return &t
+}
+
 func Test_Start(t *testing.T) {
 	es := esv1.Elasticsearch{
 		ObjectMeta: metav1.ObjectMeta{
@@ -229,7 +305,7 @@ func Test_Start(t *testing.T) {
 	tick := refreshPeriod / 2
 
 	// start the resource reporter
-	go NewResourceReporter(k8sClient, operatorNs, nil).Start(context.Background(), refreshPeriod)
+	go NewResourceReporter(k8sClient, operatorNs, nil, nil, 0).Start(context.Background(), refreshPeriod)
 
 	// check that the licensing config map exists
 	assert.Eventually(t, func() bool {
@@ -24,6 +24,7 @@ import (
 	esv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/elasticsearch/v1"
 	entv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/enterprisesearch/v1"
 	kbv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/kibana/v1"
+	lsv1alpha1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/logstash/v1alpha1"
 	mapsv1alpha1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/maps/v1alpha1"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/reconciler"
 	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/stackmon/monitoring"
@@ -121,6 +122,7 @@ func (r *Reporter) getResourceStats(ctx context.Context) (map[string]interface{}
 		entStats,
 		agentStats,
 		mapsStats,
+		logstashStats,
 	} {
 		key, statsPart, err := f(r.client, r.managedNamespaces)
 		if err != nil {
@@ -412,3 +414,20 @@ func mapsStats(k8sClient k8s.Client, managedNamespaces []string) (string, interf
 	}
 	return "maps", stats, nil
 }
+
+func logstashStats(k8sClient k8s.Client, managedNamespaces []string) (string, interface{}, error) {
+	stats := map[string]int32{resourceCount: 0, podCount: 0}
+
+	var logstashList lsv1alpha1.LogstashList
+	for _, ns := range managedNamespaces {
+		if err := k8sClient.List(context.Background(), &logstashList, client.InNamespace(ns)); err != nil {
+			return "", nil, err
+		}
+
+		for _, ls := range logstashList.Items {
+			stats[resourceCount]++
+			stats[podCount] += ls.Status.AvailableNodes
+		}
+	}
+	return "logstashes", stats, nil
+}
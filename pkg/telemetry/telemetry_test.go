@@ -374,6 +374,9 @@ func TestNewReporter(t *testing.T) {
       helm_resource_count: 1
       pod_count: 0
       resource_count: 3
+    logstashes:
+      pod_count: 0
+      resource_count: 0
     maps:
       pod_count: 1
       resource_count: 1
@@ -8,6 +8,7 @@ import (
 	"context"
 	"fmt"
 	"net"
+	"strings"
 
 	corev1 "k8s.io/api/core/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
@@ -107,23 +108,52 @@ func GetServiceDNSName(svc corev1.Service) []string {
 		}
 	}
 
+	names = append(names, GetServiceExternalDNSHostnames(svc)...)
+
 	return names
 }
 
+// ExternalDNSHostnameAnnotation is the annotation used by external-dns to create DNS records for a Service.
+const ExternalDNSHostnameAnnotation = "external-dns.alpha.kubernetes.io/hostname"
+
+// GetServiceExternalDNSHostnames returns the hostnames external-dns is asked to create DNS records for, through the
+// comma separated list of the external-dns hostname annotation of the given service.
+func GetServiceExternalDNSHostnames(svc corev1.Service) []string {
+	var hostnames []string
+	for _, hostname := range strings.Split(svc.Annotations[ExternalDNSHostnameAnnotation], ",") {
+		// external-dns accepts fully qualified names with a trailing dot
+		hostname = strings.TrimSuffix(strings.TrimSpace(hostname), ".")
+		if hostname != "" {
+			hostnames = append(hostnames, hostname)
+		}
+	}
+	return hostnames
+}
+
+// GetServiceIPAddresses returns the IP addresses the given service can be reached at: its cluster IPs, external IPs and
+// load balancer IPs.
 func GetServiceIPAddresses(svc corev1.Service) []net.IP {
 	var ipAddrs []net.IP
 
-	if len(svc.Spec.ExternalIPs) > 0 {
-		ipAddrs = make([]net.IP, len(svc.Spec.ExternalIPs))
-		for i, externalIP := range svc.Spec.ExternalIPs {
-			ipAddrs[i] = netutil.IPToRFCForm(net.ParseIP(externalIP))
+	clusterIPs := svc.Spec.ClusterIPs
+	if len(clusterIPs) == 0 && svc.Spec.ClusterIP != "" {
+		clusterIPs = []string{svc.Spec.ClusterIP}
+	}
+	for _, clusterIP := range clusterIPs {
+		// headless services do not have a cluster IP
+		if clusterIP != corev1.ClusterIPNone {
+			ipAddrs = append(ipAddrs, netutil.IPToRFCForm(net.ParseIP(clusterIP)))
 		}
 	}
 
+	for _, externalIP := range svc.Spec.ExternalIPs {
+		ipAddrs = append(ipAddrs, netutil.IPToRFCForm(net.ParseIP(externalIP)))
+	}
+
 	if svc.Spec.Type == corev1.ServiceTypeLoadBalancer {
 		for _, ingress := range svc.Status.LoadBalancer.Ingress {
 			if ingress.IP != "" {
-				ipAddrs = append(ipAddrs, netutil.IPToRFCForm(net.ParseIP(ingress.IP))) //nolint:makezero
+				ipAddrs = append(ipAddrs, netutil.IPToRFCForm(net.ParseIP(ingress.IP)))
 			}
 		}
 	}
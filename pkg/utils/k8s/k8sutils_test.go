@@ -99,6 +99,19 @@ func TestGetServiceDNSName(t *testing.T) {
 			},
 			want: []string{"test-name.test-ns.svc", "test-name.test-ns"},
 		},
+		{
+			name: "load balancer service with external-dns hostnames",
+			args: args{
+				svc: corev1.Service{
+					ObjectMeta: metav1.ObjectMeta{Namespace: "test-ns", Name: "test-name", Annotations: map[string]string{
+						ExternalDNSHostnameAnnotation: "es.example.com, search.example.com.,",
+					}},
+					Spec:   corev1.ServiceSpec{Type: corev1.ServiceTypeLoadBalancer},
+					Status: corev1.ServiceStatus{LoadBalancer: corev1.LoadBalancerStatus{Ingress: []corev1.LoadBalancerIngress{{Hostname: "mysvc.lb"}}}},
+				},
+			},
+			want: []string{"test-name.test-ns.svc", "test-name.test-ns", "mysvc.lb", "es.example.com", "search.example.com"},
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -120,6 +133,21 @@ func TestGetServiceIPAddresses(t *testing.T) {
 			svc:  corev1.Service{Spec: corev1.ServiceSpec{Type: corev1.ServiceTypeClusterIP}},
 			want: nil,
 		},
+		{
+			name: "ClusterIP service with a cluster IP address",
+			svc:  corev1.Service{Spec: corev1.ServiceSpec{Type: corev1.ServiceTypeClusterIP, ClusterIP: "10.0.0.1"}},
+			want: []net.IP{netutil.IPToRFCForm(net.ParseIP("10.0.0.1"))},
+		},
+		{
+			name: "dual-stack ClusterIP service",
+			svc:  corev1.Service{Spec: corev1.ServiceSpec{Type: corev1.ServiceTypeClusterIP, ClusterIP: "10.0.0.1", ClusterIPs: []string{"10.0.0.1", "fd00::1"}}},
+			want: []net.IP{netutil.IPToRFCForm(net.ParseIP("10.0.0.1")), netutil.IPToRFCForm(net.ParseIP("fd00::1"))},
+		},
+		{
+			name: "headless service",
+			svc:  corev1.Service{Spec: corev1.ServiceSpec{Type: corev1.ServiceTypeClusterIP, ClusterIP: corev1.ClusterIPNone, ClusterIPs: []string{corev1.ClusterIPNone}}},
+			want: nil,
+		},
 		{
 			name: "NodePort service with external IP addresses",
 			svc:  corev1.Service{Spec: corev1.ServiceSpec{Type: corev1.ServiceTypeNodePort, ExternalIPs: []string{"1.2.3.4", "2001:db8:a0b:12f0::1"}}},
@@ -128,10 +156,10 @@ func TestGetServiceIPAddresses(t *testing.T) {
 		{
 			name: "LoadBalancer service",
 			svc: corev1.Service{
-				Spec:   corev1.ServiceSpec{Type: corev1.ServiceTypeLoadBalancer},
+				Spec:   corev1.ServiceSpec{Type: corev1.ServiceTypeLoadBalancer, ClusterIP: "10.0.0.1"},
 				Status: corev1.ServiceStatus{LoadBalancer: corev1.LoadBalancerStatus{Ingress: []corev1.LoadBalancerIngress{{IP: "1.2.3.4"}}}},
 			},
-			want: []net.IP{netutil.IPToRFCForm(net.ParseIP("1.2.3.4"))},
+			want: []net.IP{netutil.IPToRFCForm(net.ParseIP("10.0.0.1")), netutil.IPToRFCForm(net.ParseIP("1.2.3.4"))},
 		},
 		{
 			name: "LoadBalancer service (no status)",
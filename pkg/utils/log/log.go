@@ -34,6 +34,10 @@ const (
 
 var Log = crlog.Log
 
+// level is the level of the global logger. It is shared by all the loggers created by setLogger, so that the verbosity
+// can be changed at runtime with SetVerbosity.
+var level = zap.NewAtomicLevel()
+
 func init() {
 	// Introduced mainly as a workaround for a controller-runtime bug.
 	// https://github.com/kubernetes-sigs/controller-runtime/issues/1359#issuecomment-767413330
@@ -68,15 +72,26 @@ func ChangeVerbosity(v int) {
 	setLogger(&v)
 }
 
-func setLogger(v *int) {
-	zapLevel := determineLogLevel(v)
+// SetVerbosity changes the verbosity level of the global logger at runtime, without replacing it.
+func SetVerbosity(v int) {
+	setLevel(&v)
+}
+
+func setLevel(v *int) {
+	level.SetLevel(determineLogLevel(v).Level())
 
 	// if the Zap custom level is less than debug (verbosity level 2 and above) set the klog level to the same level
-	if zapLevel.Level() < zap.DebugLevel {
-		flagset := flag.NewFlagSet("", flag.ContinueOnError)
-		klog.InitFlags(flagset)
-		_ = flagset.Set("v", strconv.Itoa(int(zapLevel.Level())*-1))
+	klogVerbosity := 0
+	if level.Level() < zap.DebugLevel {
+		klogVerbosity = int(level.Level()) * -1
 	}
+	flagset := flag.NewFlagSet("", flag.ContinueOnError)
+	klog.InitFlags(flagset)
+	_ = flagset.Set("v", strconv.Itoa(klogVerbosity))
+}
+
+func setLogger(v *int) {
+	setLevel(v)
 
 	// send error logs to APM
 	tracing := zap.WrapCore((&apmzap.Core{}).WrapCore)
@@ -110,7 +125,7 @@ func setLogger(v *int) {
 	crlog.SetLogger(crzap.New(func(o *crzap.Options) {
 		o.DestWriter = os.Stderr
 		o.Development = dev.Enabled
-		o.Level = &zapLevel
+		o.Level = &level
 		o.StacktraceLevel = &stackTraceLevel
 		o.Encoder = encoder
 		o.ZapOpts = opts
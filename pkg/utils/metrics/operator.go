@@ -13,13 +13,21 @@ import (
 )
 
 const (
-	namespace          = "elastic"
-	LeaderKey          = "leader"
-	licensingSubsystem = "licensing"
+	namespace             = "elastic"
+	LeaderKey             = "leader"
+	licensingSubsystem    = "licensing"
+	certificatesSubsystem = "certificates"
+	esClientSubsystem     = "elasticsearch_client"
 
+	CertificateTypeLabel   = "type"
+	CodeLabel              = "code"
 	LicenseLevelLabel      = "license_level"
+	MethodLabel            = "method"
+	NameLabel              = "name"
+	NamespaceLabel         = "namespace"
 	OperatorNamespaceLabel = "operator_namespace"
 	UUIDLabel              = "uuid"
+	VersionLabel           = "version"
 )
 
 var (
@@ -52,6 +60,63 @@ var (
 		Name:      "memory_gibibytes_total",
 		Help:      "Total memory used in GiB",
 	}, []string{LicenseLevelLabel}))
+
+	// LicensingExpiryGauge reports the expiry date of the operator license.
+	LicensingExpiryGauge = registerGauge(prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Subsystem: licensingSubsystem,
+		Name:      "expiry_timestamp_seconds",
+		Help:      "Expiry date of the operator license in seconds since the Unix epoch",
+	}, []string{LicenseLevelLabel}))
+
+	// ElasticsearchLicenseExpiryGauge reports the expiry date of the license of each Elasticsearch cluster.
+	ElasticsearchLicenseExpiryGauge = registerGauge(prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Subsystem: licensingSubsystem,
+		Name:      "elasticsearch_expiry_timestamp_seconds",
+		Help:      "Expiry date of the license of the Elasticsearch cluster in seconds since the Unix epoch",
+	}, []string{NamespaceLabel, NameLabel, LicenseLevelLabel}))
+
+	// ElasticsearchMemoryGauge reports the total memory of all Elasticsearch clusters for licensing purposes.
+	ElasticsearchMemoryGauge = registerGauge(prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Subsystem: licensingSubsystem,
+		Name:      "elasticsearch_memory_gibibytes_total",
+		Help:      "Total memory used by Elasticsearch clusters in GiB",
+	}, []string{LicenseLevelLabel}))
+
+	// ElasticsearchNodesGauge reports the number of Elasticsearch nodes per version.
+	ElasticsearchNodesGauge = registerGauge(prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Subsystem: licensingSubsystem,
+		Name:      "elasticsearch_nodes_total",
+		Help:      "Total number of Elasticsearch nodes per version",
+	}, []string{VersionLabel}))
+
+	// ElasticsearchClustersGauge reports the number of Elasticsearch clusters per license level.
+	ElasticsearchClustersGauge = registerGauge(prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Subsystem: licensingSubsystem,
+		Name:      "elasticsearch_clusters_total",
+		Help:      "Total number of Elasticsearch clusters per license level",
+	}, []string{LicenseLevelLabel}))
+
+	// CertificatesIssuedCounter reports the number of certificates issued by the operator per type of certificate.
+	CertificatesIssuedCounter = registerCounter(prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: certificatesSubsystem,
+		Name:      "issued_total",
+		Help:      "Total number of certificates issued by the operator",
+	}, []string{CertificateTypeLabel}))
+
+	// ElasticsearchRequestDuration reports the latency of the requests made to the Elasticsearch API.
+	ElasticsearchRequestDuration = registerHistogram(prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Subsystem: esClientSubsystem,
+		Name:      "request_duration_seconds",
+		Help:      "Latency of the requests made by the operator to the Elasticsearch API in seconds",
+		Buckets:   []float64{0.005, 0.025, 0.1, 0.25, 0.5, 1.0, 2.0, 5.0, 10.0, 30.0, 60.0, 180.0},
+	}, []string{MethodLabel, CodeLabel}))
 )
 
 func registerGauge(gauge *prometheus.GaugeVec) *prometheus.GaugeVec {
@@ -67,3 +132,31 @@ func registerGauge(gauge *prometheus.GaugeVec) *prometheus.GaugeVec {
 
 	return gauge
 }
+
+func registerCounter(counter *prometheus.CounterVec) *prometheus.CounterVec {
+	err := crmetrics.Registry.Register(counter)
+	if err != nil {
+		existsErr := new(prometheus.AlreadyRegisteredError)
+		if errors.As(err, &existsErr) {
+			return existsErr.ExistingCollector.(*prometheus.CounterVec) //nolint:forcetypeassert
+		}
+
+		panic(fmt.Errorf("failed to register counter: %w", err))
+	}
+
+	return counter
+}
+
+func registerHistogram(histogram *prometheus.HistogramVec) *prometheus.HistogramVec {
+	err := crmetrics.Registry.Register(histogram)
+	if err != nil {
+		existsErr := new(prometheus.AlreadyRegisteredError)
+		if errors.As(err, &existsErr) {
+			return existsErr.ExistingCollector.(*prometheus.HistogramVec) //nolint:forcetypeassert
+		}
+
+		panic(fmt.Errorf("failed to register histogram: %w", err))
+	}
+
+	return histogram
+}
@@ -43,6 +43,7 @@ type runFlags struct {
 	commandTimeout         time.Duration
 	logVerbosity           int
 	testTimeout            time.Duration
+	soakDuration           time.Duration
 	autoPortForwarding     bool
 	skipCleanup            bool
 	local                  bool
@@ -98,6 +99,7 @@ func Command() *cobra.Command {
 	cmd.Flags().StringVar(&flags.testRegex, "test-regex", "", "Regex to pass to the test runner")
 	cmd.Flags().StringVar(&flags.testRunName, "test-run-name", randomTestRunName(), "Name of this test run")
 	cmd.Flags().DurationVar(&flags.testTimeout, "test-timeout", 30*time.Minute, "Timeout before failing a test")
+	cmd.Flags().DurationVar(&flags.soakDuration, "soak-duration", 0, "Duration of the soak tests, which keep clusters alive while periodically mutating them. Soak tests are skipped if zero")
 	cmd.Flags().StringVar(&flags.pipeline, "pipeline", "", "E2E test pipeline name")
 	cmd.Flags().StringVar(&flags.buildNumber, "build-number", "", "E2E test build number")
 	cmd.Flags().StringVar(&flags.provider, "provider", "", "E2E test infrastructure provider")
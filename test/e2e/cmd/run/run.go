@@ -179,6 +179,7 @@ func (h *helper) initTestContext() error {
 		TestRegex:             h.testRegex,
 		TestRun:               h.testRunName,
 		TestTimeout:           h.testTimeout,
+		SoakDuration:          h.soakDuration,
 		Pipeline:              h.pipeline,
 		BuildNumber:           h.buildNumber,
 		Provider:              h.provider,
@@ -478,8 +479,10 @@ func (h *helper) deployTestSecrets() error {
 }
 
 func (h *helper) runTestsLocally() error {
-	log.Info("Running local test script", "timeout", h.testTimeout.String())
-	ctx, cancelFunc := context.WithTimeout(context.Background(), h.testTimeout)
+	// soak tests run for the soak duration on top of the usual test steps
+	timeout := h.testTimeout + h.soakDuration
+	log.Info("Running local test script", "timeout", timeout.String())
+	ctx, cancelFunc := context.WithTimeout(context.Background(), timeout)
 
 	cmd := exec.Command("test/e2e/run.sh", "-run", os.Getenv("TESTS_MATCH"), "-args", "-testContextPath", h.testContextOutPath) //nolint:gosec
 	cmd.Stderr = os.Stderr
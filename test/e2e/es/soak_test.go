@@ -0,0 +1,45 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+//go:build es || e2e
+
+package es
+
+import (
+	"testing"
+	"time"
+
+	"github.com/elastic/cloud-on-k8s/v2/test/e2e/test"
+	"github.com/elastic/cloud-on-k8s/v2/test/e2e/test/elasticsearch"
+)
+
+// soakIdleInterval is the time a cluster runs without any change between two mutations of a soak test.
+const soakIdleInterval = 5 * time.Minute
+
+// TestSoak keeps a cluster alive for the soak duration of the test context while periodically scaling it, changing its
+// configuration and rotating its certificates, to catch leaks and slow drifts shorter tests do not expose.
+// It only runs if a soak duration is set, for example with `make e2e-local E2E_SOAK_DURATION=3h TESTS_MATCH=TestSoak`.
+// Note that the whole test run is still bound by the timeout of the go test command.
+func TestSoak(t *testing.T) {
+	duration := test.Ctx().SoakDuration
+	if duration <= 0 {
+		t.Skip("Soak tests are disabled, set a soak duration to run them")
+	}
+
+	b := elasticsearch.NewBuilder("test-soak").
+		WithESMasterDataNodes(3, elasticsearch.DefaultResources)
+
+	k := test.NewK8sClientOrFatal()
+	test.StepList{}.
+		WithSteps(b.InitTestSteps(k)).
+		WithSteps(b.CreationTestSteps(k)).
+		WithSteps(test.CheckTestSteps(b, k)).
+		WithSteps(elasticsearch.SoakTestSteps(b, k, duration, soakIdleInterval,
+			elasticsearch.ScaleSoakMutation(b),
+			elasticsearch.ConfigSoakMutation(),
+			elasticsearch.CertRotationSoakMutation(),
+		)).
+		WithSteps(b.DeletionTestSteps(k)).
+		RunSequential(t)
+}
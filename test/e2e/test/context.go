@@ -110,6 +110,7 @@ type Context struct {
 	TestRun               string             `json:"test_run"`
 	MonitoringSecrets     string             `json:"monitoring_secrets"`
 	TestTimeout           time.Duration      `json:"test_timeout"`
+	SoakDuration          time.Duration      `json:"soak_duration"`
 	AutoPortForwarding    bool               `json:"auto_port_forwarding"`
 	DeployChaosJob        bool               `json:"deploy_chaos_job"`
 	Local                 bool               `json:"local"`
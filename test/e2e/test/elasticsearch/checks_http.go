@@ -52,6 +52,7 @@ func CheckHTTPConnectivityWithCA(es esv1.Elasticsearch, k *test.K8sClient, caCer
 			caCert,
 			client.Timeout(context.Background(), es),
 			true,
+			nil,
 		)
 		_, err := esClient.GetClusterInfo(context.Background())
 		if err != nil {
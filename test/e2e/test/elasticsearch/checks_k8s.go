@@ -322,27 +322,22 @@ func CheckESVersion(b Builder, k *test.K8sClient) test.Step {
 
 // CheckClusterHealth checks that the given ES status reports a green ES health
 func CheckClusterHealth(b Builder, k *test.K8sClient) test.Step {
-	return test.Step{
-		Name: "ES cluster health should eventually be green",
-		Test: test.Eventually(func() error {
-			return clusterHealthIs(b, k, esv1.ElasticsearchGreenHealth)
-		}),
-	}
+	return CheckSpecificClusterHealth(b, k, esv1.ElasticsearchGreenHealth)
 }
 
 // CheckSpecificClusterHealth checks that the given ES status reports a given ES health
 func CheckSpecificClusterHealth(b Builder, k *test.K8sClient, health esv1.ElasticsearchHealth) test.Step {
 	return test.Step{
 		Name: fmt.Sprintf("ES cluster health should eventually be %s", string(health)),
-		Test: test.Eventually(func() error {
-			return clusterHealthIs(b, k, health)
-		}),
+		Test: test.EventuallyWithContext(context.Background(), func(ctx context.Context) error {
+			return clusterHealthIs(ctx, b, k, health)
+		}, clusterSnapshots(b, k)...),
 	}
 }
 
-func clusterHealthIs(b Builder, k *test.K8sClient, health esv1.ElasticsearchHealth) error {
+func clusterHealthIs(ctx context.Context, b Builder, k *test.K8sClient, health esv1.ElasticsearchHealth) error {
 	var es esv1.Elasticsearch
-	err := k.Client.Get(context.Background(), k8s.ExtractNamespacedName(&b.Elasticsearch), &es)
+	err := k.Client.Get(ctx, k8s.ExtractNamespacedName(&b.Elasticsearch), &es)
 	if err != nil {
 		return err
 	}
@@ -352,6 +347,15 @@ func clusterHealthIs(b Builder, k *test.K8sClient, health esv1.ElasticsearchHeal
 	return nil
 }
 
+// clusterSnapshots captures the Elasticsearch resource, its Pods and its events when a check fails.
+func clusterSnapshots(b Builder, k *test.K8sClient) []test.Snapshot {
+	return []test.Snapshot{
+		test.ObjectSnapshot(k, &b.Elasticsearch),
+		test.PodsSnapshot(k, test.ESPodListOptions(b.Elasticsearch.Namespace, b.Elasticsearch.Name)...),
+		test.EventsSnapshot(k, b.Elasticsearch.Namespace, b.Elasticsearch.Name),
+	}
+}
+
 // CheckServices checks that all ES services are created and external IP is provisioned for all LB services
 func CheckServices(b Builder, k *test.K8sClient) test.Step {
 	return test.Step{
@@ -5,6 +5,7 @@
 package elasticsearch
 
 import (
+	"context"
 	"fmt"
 	"reflect"
 	"strings"
@@ -30,7 +31,7 @@ func CheckESKeystoreEntries(k *test.K8sClient, b Builder, expectedKeys []string)
 			if err := checkExpectedPodsReady(b, k); err != nil {
 				return err
 			}
-			if err := clusterHealthIs(b, k, esv1.ElasticsearchGreenHealth); err != nil {
+			if err := clusterHealthIs(context.Background(), b, k, esv1.ElasticsearchGreenHealth); err != nil {
 				return err
 			}
 			// check keystore entries on all Pods
@@ -90,6 +90,7 @@ func NewElasticsearchClientWithUser(es esv1.Elasticsearch, k *test.K8sClient, us
 		caCert,
 		client.Timeout(context.Background(), es),
 		true,
+		nil,
 	)
 	return esClient, nil
 }
@@ -0,0 +1,235 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package elasticsearch
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	esv1 "github.com/elastic/cloud-on-k8s/v2/pkg/apis/elasticsearch/v1"
+	"github.com/elastic/cloud-on-k8s/v2/pkg/controller/common/certificates"
+	"github.com/elastic/cloud-on-k8s/v2/test/e2e/test"
+)
+
+// SoakDataIndex is the index holding the data checked during the whole duration of a soak test.
+const SoakDataIndex = "soak-data-integrity-check"
+
+// SoakMutation is a change applied periodically to a cluster during a soak test.
+type SoakMutation struct {
+	// Name describes the mutation.
+	Name string
+	// Steps returns the steps applying the mutation to the cluster described by the given builder, along with the
+	// builder describing the mutated cluster.
+	Steps func(b Builder, k *test.K8sClient) (test.StepList, Builder)
+	// Restarts is true if the mutation is expected to replace the Pods.
+	Restarts bool
+}
+
+// ScaleSoakMutation alternately adds and removes a node to the first NodeSet of the cluster described by the given
+// builder.
+func ScaleSoakMutation(b Builder) SoakMutation {
+	initialCount := b.Elasticsearch.Spec.NodeSets[0].Count
+	return SoakMutation{
+		Name:     "Scale",
+		Restarts: true,
+		Steps: func(b Builder, k *test.K8sClient) (test.StepList, Builder) {
+			nodeSet := *b.Elasticsearch.Spec.NodeSets[0].DeepCopy()
+			if nodeSet.Count > initialCount {
+				nodeSet.Count--
+			} else {
+				nodeSet.Count++
+			}
+			mutated := b.DeepCopy().WithNodeSet(nodeSet).WithMutatedFrom(&b)
+			return mutated.MutationTestSteps(k), mutated
+		},
+	}
+}
+
+// ConfigSoakMutation alternates the value of a node attribute in the configuration of all the NodeSets, which
+// triggers a rolling restart of the cluster.
+func ConfigSoakMutation() SoakMutation {
+	return SoakMutation{
+		Name:     "Config change",
+		Restarts: true,
+		Steps: func(b Builder, k *test.K8sClient) (test.StepList, Builder) {
+			value := "a"
+			if len(b.Elasticsearch.Spec.NodeSets) > 0 && b.Elasticsearch.Spec.NodeSets[0].Config != nil &&
+				b.Elasticsearch.Spec.NodeSets[0].Config.Data["node.attr.soak"] == "a" {
+				value = "b"
+			}
+			config := map[string]map[string]interface{}{}
+			for _, nodeSet := range b.Elasticsearch.Spec.NodeSets {
+				config[nodeSet.Name] = map[string]interface{}{"node.attr.soak": value}
+			}
+			mutated := b.DeepCopy().WithAdditionalConfig(config).WithMutatedFrom(&b)
+			return mutated.MutationTestSteps(k), mutated
+		},
+	}
+}
+
+// CertRotationSoakMutation deletes the internal HTTP CA of the cluster, which makes the operator issue a new CA and new
+// HTTP certificates. Elasticsearch reloads them without restarting.
+func CertRotationSoakMutation() SoakMutation {
+	return SoakMutation{
+		Name:     "HTTP certificates rotation",
+		Restarts: false,
+		Steps: func(b Builder, k *test.K8sClient) (test.StepList, Builder) {
+			caSecretKey := types.NamespacedName{
+				Namespace: b.Elasticsearch.Namespace,
+				Name:      certificates.CAInternalSecretName(esv1.ESNamer, b.Elasticsearch.Name, certificates.HTTPCAType),
+			}
+			var previousCA []byte
+			//nolint:thelper
+			steps := test.StepList{
+				{
+					Name: "Delete the internal HTTP CA",
+					Test: func(t *testing.T) {
+						var secret corev1.Secret
+						require.NoError(t, k.Client.Get(context.Background(), caSecretKey, &secret))
+						previousCA = secret.Data[certificates.CertFileName]
+						require.NoError(t, k.Client.Delete(context.Background(), &secret))
+					},
+				},
+				{
+					Name: "A new internal HTTP CA should be issued",
+					Test: test.Eventually(func() error {
+						var secret corev1.Secret
+						if err := k.Client.Get(context.Background(), caSecretKey, &secret); err != nil {
+							return err
+						}
+						if string(secret.Data[certificates.CertFileName]) == string(previousCA) {
+							return fmt.Errorf("internal HTTP CA %s not rotated yet", caSecretKey)
+						}
+						return nil
+					}),
+				},
+			}
+			return steps.WithSteps(b.CheckStackTestSteps(k)), b
+		},
+	}
+}
+
+// SoakTestSteps applies the given mutations in turn to the running cluster of the given builder until the given
+// duration elapses, leaving the cluster alone for the given interval after each mutation. It checks that:
+//   - the data indexed at the beginning of the soak test is never lost,
+//   - the Elasticsearch containers never restart,
+//   - the Pods are only replaced by the mutations expected to do so.
+func SoakTestSteps(b Builder, k *test.K8sClient, duration, interval time.Duration, mutations ...SoakMutation) test.StepList {
+	var dataIntegrityCheck *DataIntegrityCheck
+	//nolint:thelper
+	return test.StepList{
+		{
+			Name: "Add some data to the cluster before starting the soak test",
+			Test: func(t *testing.T) {
+				dataIntegrityCheck = NewDataIntegrityCheck(k, b).ForIndex(SoakDataIndex)
+				require.NoError(t, dataIntegrityCheck.Init())
+			},
+		},
+		{
+			Name: fmt.Sprintf("Mutate the cluster periodically for %s", duration),
+			Test: func(t *testing.T) {
+				require.NotEmpty(t, mutations)
+				deadline := time.Now().Add(duration)
+				current := b
+				for i := 0; time.Now().Before(deadline); i++ {
+					mutation := mutations[i%len(mutations)]
+					before, err := getSoakPodsState(k, current)
+					require.NoError(t, err)
+
+					steps, mutated := mutation.Steps(current, k)
+					if !t.Run(fmt.Sprintf("%d: %s", i, mutation.Name), steps.RunSequential) {
+						t.FailNow()
+					}
+					current = mutated
+
+					after, err := getSoakPodsState(k, current)
+					require.NoError(t, err)
+					if !mutation.Restarts {
+						require.NoError(t, before.unchangedIn(after), "Pods replaced by mutation %q", mutation.Name)
+					}
+					require.NoError(t, before.noRestartsIn(after))
+
+					// let the cluster run without any change, nothing should happen to the Pods
+					time.Sleep(interval)
+					idle, err := getSoakPodsState(k, current)
+					require.NoError(t, err)
+					require.NoError(t, after.unchangedIn(idle), "Pods replaced without any mutation")
+					require.NoError(t, after.noRestartsIn(idle))
+					require.NoError(t, dataIntegrityCheck.Verify())
+				}
+			},
+		},
+		{
+			Name: "Data added at the beginning of the soak test should still be present",
+			Test: test.Eventually(func() error {
+				return dataIntegrityCheck.Verify()
+			}),
+		},
+	}
+}
+
+// soakPodState is the state of a Pod relevant to detect unexpected restarts.
+type soakPodState struct {
+	uid      types.UID
+	restarts int32
+}
+
+// soakPodsState is the state of the Pods of a cluster, indexed by Pod name.
+type soakPodsState map[string]soakPodState
+
+func getSoakPodsState(k *test.K8sClient, b Builder) (soakPodsState, error) {
+	pods, err := k.GetPods(test.ESPodListOptions(b.Elasticsearch.Namespace, b.Elasticsearch.Name)...)
+	if err != nil {
+		return nil, err
+	}
+	state := make(soakPodsState, len(pods))
+	for _, pod := range pods {
+		var restarts int32
+		for _, status := range pod.Status.ContainerStatuses {
+			restarts += status.RestartCount
+		}
+		state[pod.Name] = soakPodState{uid: pod.UID, restarts: restarts}
+	}
+	return state, nil
+}
+
+// unchangedIn returns an error if the Pods of s are not the same in other.
+func (s soakPodsState) unchangedIn(other soakPodsState) error {
+	for name, pod := range s {
+		otherPod, exists := other[name]
+		if !exists {
+			return fmt.Errorf("pod %s was deleted", name)
+		}
+		if otherPod.uid != pod.uid {
+			return fmt.Errorf("pod %s was recreated", name)
+		}
+	}
+	for name := range other {
+		if _, exists := s[name]; !exists {
+			return fmt.Errorf("pod %s was created", name)
+		}
+	}
+	return nil
+}
+
+// noRestartsIn returns an error if the containers of the Pods of s restarted in other.
+func (s soakPodsState) noRestartsIn(other soakPodsState) error {
+	for name, pod := range other {
+		previous, exists := s[name]
+		if exists && previous.uid == pod.uid && pod.restarts > previous.restarts {
+			return fmt.Errorf("containers of pod %s restarted %d times", name, pod.restarts-previous.restarts)
+		}
+		if (!exists || previous.uid != pod.uid) && pod.restarts > 0 {
+			return fmt.Errorf("containers of new pod %s restarted %d times", name, pod.restarts)
+		}
+	}
+	return nil
+}
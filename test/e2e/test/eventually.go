@@ -0,0 +1,143 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package test
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ghodss/yaml"
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/elastic/cloud-on-k8s/v2/pkg/utils/k8s"
+)
+
+// Snapshot captures the state of resources relevant to a check, such as objects, statuses or events.
+// Snapshots are attached to the test failure output when a check does not succeed before its deadline.
+type Snapshot struct {
+	// Name describes what the snapshot captures.
+	Name string
+	// Capture returns a human readable representation of the captured state.
+	Capture func(ctx context.Context) (string, error)
+}
+
+// EventuallyWithContext runs f until it succeeds or the given context is done. The default test timeout applies if the
+// context has no deadline. If f does not succeed in time, the test fails with the last error returned by f and the
+// given snapshots, captured right after the deadline.
+func EventuallyWithContext(c context.Context, f func(context.Context) error, snapshots ...Snapshot) func(*testing.T) {
+	return func(t *testing.T) {
+		t.Helper()
+		runCtx := c
+		if _, hasDeadline := c.Deadline(); !hasDeadline {
+			var cancel context.CancelFunc
+			runCtx, cancel = context.WithTimeout(c, ctx.TestTimeout)
+			defer cancel()
+		}
+		err := untilSuccessOrDone(runCtx, f, DefaultRetryDelay)
+		if err == nil {
+			return
+		}
+		// the run context is done, capture the snapshots with a fresh one
+		snapshotCtx, cancel := context.WithTimeout(context.Background(), time.Minute)
+		defer cancel()
+		t.Fatal(failureReport(snapshotCtx, err, snapshots))
+	}
+}
+
+// untilSuccessOrDone calls f every retryInterval until it succeeds or ctx is done, in which case it returns the last
+// error returned by f.
+func untilSuccessOrDone(ctx context.Context, f func(context.Context) error, retryInterval time.Duration) error {
+	fmt.Print("Retries: ")
+	defer fmt.Println()
+	for {
+		fmt.Print(".")
+		err := f(ctx)
+		if err == nil {
+			return nil
+		}
+		retry := time.NewTimer(retryInterval)
+		select {
+		case <-ctx.Done():
+			retry.Stop()
+			return fmt.Errorf("%w: %v", ctx.Err(), err)
+		case <-retry.C:
+		}
+	}
+}
+
+// failureReport formats the given error along with the captured snapshots.
+func failureReport(ctx context.Context, err error, snapshots []Snapshot) string {
+	report := strings.Builder{}
+	report.WriteString(err.Error())
+	for _, snapshot := range snapshots {
+		report.WriteString(fmt.Sprintf("\n\n--- %s ---\n", snapshot.Name))
+		captured, captureErr := snapshot.Capture(ctx)
+		if captureErr != nil {
+			report.WriteString(fmt.Sprintf("failed to capture snapshot: %v", captureErr))
+			continue
+		}
+		report.WriteString(captured)
+	}
+	return report.String()
+}
+
+// ObjectSnapshot captures the given object, including its status, as YAML.
+func ObjectSnapshot(k *K8sClient, obj client.Object) Snapshot {
+	return Snapshot{
+		Name: fmt.Sprintf("%T %s", obj, k8s.ExtractNamespacedName(obj)),
+		Capture: func(ctx context.Context) (string, error) {
+			current := obj.DeepCopyObject().(client.Object) //nolint:forcetypeassert
+			if err := k.Client.Get(ctx, k8s.ExtractNamespacedName(obj), current); err != nil {
+				return "", err
+			}
+			current.SetManagedFields(nil)
+			bytes, err := yaml.Marshal(current)
+			return string(bytes), err
+		},
+	}
+}
+
+// PodsSnapshot captures the phase and the conditions of the Pods matching the given options.
+func PodsSnapshot(k *K8sClient, opts ...client.ListOption) Snapshot {
+	return Snapshot{
+		Name: "Pods",
+		Capture: func(ctx context.Context) (string, error) {
+			var pods corev1.PodList
+			if err := k.Client.List(ctx, &pods, opts...); err != nil {
+				return "", err
+			}
+			out := strings.Builder{}
+			for _, pod := range pods.Items {
+				out.WriteString(fmt.Sprintf("%s: %s\n", pod.Name, pod.Status.Phase))
+				for _, condition := range pod.Status.Conditions {
+					out.WriteString(fmt.Sprintf("  %s=%s %s %s\n", condition.Type, condition.Status, condition.Reason, condition.Message))
+				}
+			}
+			return out.String(), nil
+		},
+	}
+}
+
+// EventsSnapshot captures the events involving the resource with the given namespace and name.
+func EventsSnapshot(k *K8sClient, namespace, name string) Snapshot {
+	return Snapshot{
+		Name: fmt.Sprintf("Events %s/%s", namespace, name),
+		Capture: func(ctx context.Context) (string, error) {
+			var events corev1.EventList
+			if err := k.Client.List(ctx, &events, EventListOptions(namespace, name)...); err != nil {
+				return "", err
+			}
+			out := strings.Builder{}
+			for _, event := range events.Items {
+				out.WriteString(fmt.Sprintf("%s %s %s: %s\n", event.LastTimestamp.Format(time.RFC3339), event.Type, event.Reason, event.Message))
+			}
+			return out.String(), nil
+		},
+	}
+}
@@ -0,0 +1,52 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func Test_untilSuccessOrDone(t *testing.T) {
+	t.Run("succeeds", func(t *testing.T) {
+		calls := 0
+		err := untilSuccessOrDone(context.Background(), func(context.Context) error {
+			calls++
+			if calls < 3 {
+				return errors.New("not yet")
+			}
+			return nil
+		}, time.Millisecond)
+		require.NoError(t, err)
+		require.Equal(t, 3, calls)
+	})
+	t.Run("returns the last error when the context is done", func(t *testing.T) {
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+		defer cancel()
+		err := untilSuccessOrDone(ctx, func(context.Context) error {
+			return errors.New("health is yellow")
+		}, time.Millisecond)
+		require.ErrorIs(t, err, context.DeadlineExceeded)
+		require.ErrorContains(t, err, "health is yellow")
+	})
+}
+
+func Test_failureReport(t *testing.T) {
+	report := failureReport(context.Background(), errors.New("health is yellow"), []Snapshot{
+		{Name: "Elasticsearch", Capture: func(context.Context) (string, error) { return "status: yellow", nil }},
+		{Name: "Events", Capture: func(context.Context) (string, error) { return "", errors.New("forbidden") }},
+	})
+	require.Equal(t, `health is yellow
+
+--- Elasticsearch ---
+status: yellow
+
+--- Events ---
+failed to capture snapshot: forbidden`, report)
+}